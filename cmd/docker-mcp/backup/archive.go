@@ -0,0 +1,406 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+// ArchiveVersion is the version of the format of the backup archives.
+const ArchiveVersion = 1
+
+const (
+	manifestEntry = "manifest.json"
+	databaseEntry = "mcp-toolkit.db"
+	configPrefix  = "config/"
+)
+
+// configFiles are the files of ~/.docker/mcp that are backed up, along with the catalogs/*.yaml files.
+// None of them hold secrets.
+var configFiles = []string{"config.yaml", "registry.yaml", "tools.yaml", "catalog.json"}
+
+// Manifest describes the content of a backup archive.
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Encrypted is true when the database encryption was on. The key stays in the keyring of the machine, so
+	// the encrypted config of the servers is left out of the archive. The encryption is turned on again when it's restored.
+	Encrypted bool `json:"encrypted,omitempty"`
+	// StrippedConfig are the servers, as profile/server, whose encrypted config was left out of the archive.
+	StrippedConfig []string `json:"strippedConfig,omitempty"`
+	// Files are the configuration files in the archive, relative to ~/.docker/mcp.
+	Files []string `json:"files"`
+	// Secrets are the secrets that the servers need. Only their names are backed up, never their values.
+	Secrets []RequiredSecret `json:"secrets"`
+}
+
+// RequiredSecret is a secret that servers of the profiles, or enabled servers, need.
+type RequiredSecret struct {
+	Name    string   `json:"name"`
+	Servers []string `json:"servers"`
+}
+
+// Create packages the local MCP state in a gzipped tarball: the database (profiles, catalogs,
+// migration status and settings), the configuration files and the names of the secrets the servers need.
+func Create(ctx context.Context, docker docker.Client, archivePath string) (*Manifest, error) {
+	dao, err := db.New()
+	if err != nil {
+		return nil, err
+	}
+	defer dao.Close()
+
+	tmpDir, err := os.MkdirTemp("", "docker-mcp-backup")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	databaseFile := filepath.Join(tmpDir, databaseEntry)
+	if err := dao.Snapshot(ctx, databaseFile); err != nil {
+		return nil, fmt.Errorf("failed to copy the database: %w", err)
+	}
+
+	manifest := &Manifest{
+		Version:   ArchiveVersion,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	manifest.Encrypted, err = dao.EncryptionEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the encryption setting: %w", err)
+	}
+
+	files, err := readConfigFiles(ctx, docker)
+	if err != nil {
+		return nil, err
+	}
+	for name := range files {
+		manifest.Files = append(manifest.Files, name)
+	}
+	sort.Strings(manifest.Files)
+
+	if err := prepareSnapshot(ctx, databaseFile, manifest, files["registry.yaml"]); err != nil {
+		return nil, err
+	}
+
+	database, err := os.ReadFile(databaseFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeArchive(archivePath, manifest, database, files); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", archivePath, err)
+	}
+
+	return manifest, nil
+}
+
+// RestoreArchive restores the local MCP state from a backup archive. The existing database is only
+// replaced with force. Gateways should be stopped first.
+func RestoreArchive(ctx context.Context, archivePath string, force bool) (*Manifest, error) {
+	manifest, database, files, err := readArchive(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", archivePath, err)
+	}
+
+	databaseFile, err := db.DefaultDatabaseFilename()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(databaseFile); err == nil && !force {
+		return nil, fmt.Errorf("%s already exists, use --force to replace it", databaseFile)
+	}
+	if err := os.MkdirAll(filepath.Dir(databaseFile), 0o755); err != nil {
+		return nil, err
+	}
+
+	// Write next to the database, then rename, so that the database is never half written
+	restoredFile := databaseFile + ".restore"
+	if err := os.WriteFile(restoredFile, database, 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(restoredFile, databaseFile); err != nil {
+		_ = os.Remove(restoredFile)
+		return nil, err
+	}
+	_ = os.Remove(databaseFile + "-journal")
+
+	if manifest.Encrypted {
+		if err := setEncryption(ctx, databaseFile, true); err != nil {
+			return nil, fmt.Errorf("failed to encrypt the restored database: %w", err)
+		}
+	}
+
+	for _, name := range manifest.Files {
+		filePath, err := config.FilePath(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filePath, files[name], 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+func setEncryption(ctx context.Context, databaseFile string, enabled bool) error {
+	dao, err := db.New(db.WithDatabaseFile(databaseFile))
+	if err != nil {
+		return err
+	}
+	defer dao.Close()
+
+	return dao.SetEncryption(ctx, enabled)
+}
+
+// prepareSnapshot lists the secrets the servers of the copy of the database need and, when the encryption is on,
+// removes the encrypted config of the servers from the copy: the key stays in the keyring of this machine
+// and the config must not leave it in clear.
+func prepareSnapshot(ctx context.Context, databaseFile string, manifest *Manifest, registryContent []byte) error {
+	dao, err := db.New(db.WithDatabaseFile(databaseFile))
+	if err != nil {
+		return err
+	}
+	defer dao.Close()
+
+	if manifest.Encrypted {
+		manifest.StrippedConfig, err = dao.StripEncryptedConfig(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to remove the encrypted config from the copy of the database: %w", err)
+		}
+	}
+
+	manifest.Secrets, err = requiredSecrets(ctx, dao, registryContent)
+	return err
+}
+
+func readConfigFiles(ctx context.Context, docker docker.Client) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	for _, name := range configFiles {
+		var content []byte
+		var err error
+		switch name {
+		case "catalog.json":
+			content, err = config.ReadCatalog()
+		default:
+			content, err = config.ReadConfigFile(ctx, docker, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if len(content) > 0 {
+			files[name] = content
+		}
+	}
+
+	catalogsDir, err := config.FilePath("catalogs")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(catalogsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(catalogsDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files["catalogs/"+entry.Name()] = content
+	}
+
+	return files, nil
+}
+
+// requiredSecrets lists the secrets of the servers of the profiles and of the servers enabled in registry.yaml.
+func requiredSecrets(ctx context.Context, dao db.DAO, registryContent []byte) ([]RequiredSecret, error) {
+	servers := map[string][]string{}
+	add := func(serverName string, secrets []catalog.Secret) {
+		for _, secret := range secrets {
+			if !slices.Contains(servers[secret.Name], serverName) {
+				servers[secret.Name] = append(servers[secret.Name], serverName)
+			}
+		}
+	}
+
+	workingSets, err := dao.ListWorkingSets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+	for _, workingSet := range workingSets {
+		for _, server := range workingSet.Servers {
+			if server.Snapshot != nil {
+				add(server.Snapshot.Server.Name, server.Snapshot.Server.Secrets)
+			}
+		}
+	}
+
+	if len(registryContent) > 0 {
+		registry, err := config.ParseRegistryConfig(registryContent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse registry.yaml: %w", err)
+		}
+		// The catalog might not have been downloaded yet, in which case the enabled servers have no known secrets
+		if mcpCatalog, err := catalog.Get(ctx); err == nil {
+			for _, serverName := range registry.ServerNames() {
+				add(serverName, mcpCatalog.Servers[serverName].Secrets)
+			}
+		}
+	}
+
+	var secrets []RequiredSecret
+	for name, serverNames := range servers {
+		sort.Strings(serverNames)
+		secrets = append(secrets, RequiredSecret{Name: name, Servers: serverNames})
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+
+	return secrets, nil
+}
+
+func writeArchive(archivePath string, manifest *Manifest, database []byte, files map[string][]byte) (err error) {
+	out, err := os.OpenFile(archivePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeEntry(tw, manifestEntry, manifestContent, manifest.CreatedAt); err != nil {
+		return err
+	}
+	if err := writeEntry(tw, databaseEntry, database, manifest.CreatedAt); err != nil {
+		return err
+	}
+	for _, name := range manifest.Files {
+		if err := writeEntry(tw, configPrefix+name, files[name], manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeEntry(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0o600,
+		Size:     int64(len(content)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+func readArchive(archivePath string) (*Manifest, []byte, map[string][]byte, error) {
+	in, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	entries := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		entries[header.Name] = content
+	}
+
+	manifestContent, found := entries[manifestEntry]
+	if !found {
+		return nil, nil, nil, fmt.Errorf("not a backup archive, %s is missing", manifestEntry)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid %s: %w", manifestEntry, err)
+	}
+	if manifest.Version > ArchiveVersion {
+		return nil, nil, nil, fmt.Errorf("the backup archive was created by a newer version (format %d), please upgrade", manifest.Version)
+	}
+
+	database, found := entries[databaseEntry]
+	if !found {
+		return nil, nil, nil, fmt.Errorf("%s is missing", databaseEntry)
+	}
+
+	files := map[string][]byte{}
+	for _, name := range manifest.Files {
+		if !isConfigFile(name) {
+			return nil, nil, nil, fmt.Errorf("unexpected file %s", name)
+		}
+		content, found := entries[configPrefix+name]
+		if !found {
+			return nil, nil, nil, fmt.Errorf("%s is missing", configPrefix+name)
+		}
+		files[name] = content
+	}
+
+	return &manifest, database, files, nil
+}
+
+// isConfigFile prevents a tampered archive from writing anywhere else than where the configuration files are.
+func isConfigFile(name string) bool {
+	if slices.Contains(configFiles, name) {
+		return true
+	}
+	dir, file := path.Split(name)
+	return dir == "catalogs/" && strings.HasSuffix(file, ".yaml") && !strings.ContainsAny(file, `/\`) && file != ".yaml" && !strings.HasPrefix(file, "..")
+}
@@ -0,0 +1,187 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func TestCreateAndRestoreArchive(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	mcpDir := filepath.Join(home, ".docker", "mcp")
+	require.NoError(t, os.MkdirAll(filepath.Join(mcpDir, "catalogs"), 0o755))
+	writeFile(t, filepath.Join(mcpDir, "config.yaml"), "github:\n  org: docker\n")
+	writeFile(t, filepath.Join(mcpDir, "registry.yaml"), "registry:\n  github: {}\n")
+	writeFile(t, filepath.Join(mcpDir, "tools.yaml"), "")
+	writeFile(t, filepath.Join(mcpDir, "catalogs", "docker-mcp.yaml"), `registry:
+  github:
+    image: mcp/github
+    secrets:
+      - name: github.personal_access_token
+        env: GITHUB_PERSONAL_ACCESS_TOKEN
+`)
+
+	dao, err := db.New()
+	require.NoError(t, err)
+	require.NoError(t, dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "dev",
+		Name: "Dev",
+		Servers: db.ServerList{{
+			Type:     "image",
+			Image:    "mcp/notion",
+			Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: "notion", Secrets: []catalog.Secret{{Name: "notion.token"}}}},
+		}},
+		Secrets: db.SecretMap{},
+	}))
+	require.NoError(t, dao.Close())
+
+	archivePath := filepath.Join(t.TempDir(), "mcp-backup.tar.gz")
+	manifest, err := Create(t.Context(), nil, archivePath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"catalogs/docker-mcp.yaml", "config.yaml", "registry.yaml"}, manifest.Files)
+	assert.Equal(t, []RequiredSecret{
+		{Name: "github.personal_access_token", Servers: []string{"github"}},
+		{Name: "notion.token", Servers: []string{"notion"}},
+	}, manifest.Secrets)
+
+	// Restore on a fresh machine
+	t.Setenv("HOME", t.TempDir())
+	restored, err := RestoreArchive(t.Context(), archivePath, false)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Files, restored.Files)
+
+	dao, err = db.New()
+	require.NoError(t, err)
+	defer dao.Close()
+	workingSet, err := dao.GetWorkingSet(t.Context(), "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "Dev", workingSet.Name)
+
+	config, err := os.ReadFile(filepath.Join(os.Getenv("HOME"), ".docker", "mcp", "config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "github:\n  org: docker\n", string(config))
+
+	// The database is only replaced with force
+	_, err = RestoreArchive(t.Context(), archivePath, false)
+	require.ErrorContains(t, err, "use --force")
+	_, err = RestoreArchive(t.Context(), archivePath, true)
+	require.NoError(t, err)
+}
+
+// memoryKeyStore keeps the database encryption key in memory, instead of the OS keyring.
+type memoryKeyStore struct {
+	key []byte
+}
+
+func (m *memoryKeyStore) Key() ([]byte, error) {
+	if m.key == nil {
+		return nil, db.ErrKeyNotFound
+	}
+	return m.key, nil
+}
+
+func (m *memoryKeyStore) SetKey(key []byte) error {
+	m.key = key
+	return nil
+}
+
+func TestCreateArchiveWithEncryption(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	mcpDir := filepath.Join(home, ".docker", "mcp")
+	require.NoError(t, os.MkdirAll(filepath.Join(mcpDir, "catalogs"), 0o755))
+	for _, name := range []string{"config.yaml", "registry.yaml", "tools.yaml"} {
+		writeFile(t, filepath.Join(mcpDir, name), "")
+	}
+
+	databaseFile, err := db.DefaultDatabaseFilename()
+	require.NoError(t, err)
+	dao, err := db.New(db.WithDatabaseFile(databaseFile), db.WithKeyStore(&memoryKeyStore{}))
+	require.NoError(t, err)
+	require.NoError(t, dao.SetEncryption(t.Context(), true))
+	require.NoError(t, dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "dev",
+		Name: "Dev",
+		Servers: db.ServerList{{
+			Type:     "image",
+			Image:    "mcp/postgres",
+			Config:   map[string]any{"url": "postgres://admin@db.internal:5432/production"},
+			Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: "postgres"}},
+		}},
+		Secrets: db.SecretMap{},
+	}))
+	require.NoError(t, dao.Close())
+
+	archivePath := filepath.Join(t.TempDir(), "mcp-backup.tar.gz")
+	manifest, err := Create(t.Context(), nil, archivePath)
+	require.NoError(t, err)
+	assert.True(t, manifest.Encrypted)
+	assert.Equal(t, []string{"dev/postgres"}, manifest.StrippedConfig)
+
+	// Neither the config nor its encrypted form are in the archive
+	archive, err := os.Open(archivePath)
+	require.NoError(t, err)
+	defer archive.Close()
+	gz, err := gzip.NewReader(archive)
+	require.NoError(t, err)
+	content, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "db.internal")
+	assert.NotContains(t, string(content), "encrypted_config")
+
+	// The database of this machine keeps its config
+	dao, err = db.New(db.WithDatabaseFile(databaseFile), db.WithKeyStore(&memoryKeyStore{}))
+	require.NoError(t, err)
+	defer dao.Close()
+	enabled, err := dao.EncryptionEnabled(t.Context())
+	require.NoError(t, err)
+	assert.True(t, enabled)
+}
+
+func TestRestoreArchiveRejectsUnexpectedFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	archivePath := filepath.Join(t.TempDir(), "tampered.tar.gz")
+	out, err := os.Create(archivePath)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{
+		"manifest.json":        `{"version":1,"files":["../../.bashrc"]}`,
+		"mcp-toolkit.db":       "",
+		"config/../../.bashrc": "echo",
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, out.Close())
+
+	_, err = RestoreArchive(t.Context(), archivePath, false)
+	require.ErrorContains(t, err, "unexpected file ../../.bashrc")
+}
+
+func TestIsConfigFile(t *testing.T) {
+	assert.True(t, isConfigFile("registry.yaml"))
+	assert.True(t, isConfigFile("catalogs/docker-mcp.yaml"))
+	assert.False(t, isConfigFile("catalogs/../config.yaml"))
+	assert.False(t, isConfigFile("catalogs/nested/catalog.yaml"))
+	assert.False(t, isConfigFile("mcp-toolkit.db"))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
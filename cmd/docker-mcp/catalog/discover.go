@@ -0,0 +1,119 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/lan"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+type DiscoverOptions struct {
+	MDNS    bool
+	Hosts   []string
+	Timeout time.Duration
+	Format  Format
+	// Add is the name of a discovered server to add to Catalog.
+	Add     string
+	Catalog string
+	Force   bool
+}
+
+// Discover lists the MCP servers advertised on the local network, or adds one of them to a catalog.
+func Discover(ctx context.Context, opts DiscoverOptions) error {
+	// Initialize telemetry
+	telemetry.Init()
+
+	if opts.Add != "" {
+		if opts.Catalog == "" {
+			return fmt.Errorf("--catalog is required to add a discovered server")
+		}
+		if opts.Catalog == DockerCatalogName {
+			return fmt.Errorf("cannot add servers to catalog '%s' as it is managed by Docker", opts.Catalog)
+		}
+		cfg, err := ReadConfig()
+		if err != nil {
+			return err
+		}
+		if _, ok := cfg.Catalogs[opts.Catalog]; !ok {
+			return fmt.Errorf("catalog %q not found", opts.Catalog)
+		}
+	}
+
+	servers, errs := lan.Discover(ctx, lan.Options{
+		MDNS:    opts.MDNS,
+		Hosts:   opts.Hosts,
+		Timeout: opts.Timeout,
+	})
+	for _, err := range errs {
+		fmt.Println("Warning:", err)
+	}
+
+	if opts.Add != "" {
+		for _, server := range servers {
+			if server.Name == opts.Add {
+				return addDiscoveredServer(ctx, opts.Catalog, server, opts.Force)
+			}
+		}
+		return fmt.Errorf("server %q not found on the local network", opts.Add)
+	}
+
+	if opts.Format == JSON {
+		if servers == nil {
+			servers = []lan.Server{}
+		}
+		data, err := json.Marshal(servers)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(servers) == 0 {
+		fmt.Println("No MCP servers found on the local network.")
+		return nil
+	}
+	for _, server := range servers {
+		fmt.Printf("%-20s %-50s %s\n", server.Name, server.URL, server.Source)
+		if server.Description != "" {
+			fmt.Printf("  %s\n", server.Description)
+		}
+	}
+	fmt.Println("These servers are not verified, only add the ones you trust with --add <name> --catalog <catalog>.")
+	return nil
+}
+
+func addDiscoveredServer(ctx context.Context, catalogName string, server lan.Server, force bool) error {
+	start := time.Now()
+	success := false
+	defer func() {
+		telemetry.RecordCatalogOperation(ctx, "discover", catalogName, float64(time.Since(start).Milliseconds()), success)
+	}()
+
+	serverJSON, err := json.Marshal(server.CatalogServer())
+	if err != nil {
+		return err
+	}
+	contentBefore, err := ReadCatalogFile(catalogName)
+	if err != nil {
+		return err
+	}
+	existingJSON, err := extractServerJSON(contentBefore, server.Name)
+	if err == nil && len(existingJSON) > 4 && !force {
+		return fmt.Errorf("server %q already exists in catalog %q (use --force to overwrite)", server.Name, catalogName)
+	}
+	contentAfter, err := injectServerJSON(contentBefore, server.Name, serverJSON)
+	if err != nil {
+		return err
+	}
+	if err := WriteCatalogFile(catalogName, contentAfter); err != nil {
+		return err
+	}
+
+	success = true
+	fmt.Printf("added server \"%s\" at %s to catalog \"%s\"\n", server.Name, server.URL, catalogName)
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/hints"
+	catalogTypes "github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/terminal"
 	"github.com/docker/mcp-gateway/pkg/yq"
 )
@@ -27,6 +28,13 @@ const (
 
 var supportedFormats = []Format{JSON, YAML}
 
+const (
+	// SortName sorts the servers of a catalog by name.
+	SortName = "name"
+	// SortPopularity sorts the servers of a catalog by pulls and stars, most popular first.
+	SortPopularity = "popularity"
+)
+
 func (e *Format) String() string {
 	return string(*e)
 }
@@ -55,7 +63,11 @@ func SupportedFormats() string {
 	return strings.Join(quoted, ", ")
 }
 
-func Show(ctx context.Context, dockerCli command.Cli, name string, format Format, mcpOAuthDcrEnabled bool) error {
+func Show(ctx context.Context, dockerCli command.Cli, name string, format Format, sortBy string, mcpOAuthDcrEnabled bool) error {
+	if sortBy != "" && sortBy != SortName && sortBy != SortPopularity {
+		return fmt.Errorf("unsupported sort %q, expected %s or %s", sortBy, SortName, SortPopularity)
+	}
+
 	cfg, err := ReadConfigWithDefaultCatalog(ctx)
 	if err != nil {
 		return err
@@ -119,6 +131,14 @@ func Show(ctx context.Context, dockerCli command.Cli, name string, format Format
 	}
 	keys := getSortedKeys(registry.Registry)
 
+	var popularity map[string]string
+	if sortBy == SortPopularity {
+		keys, popularity, err = sortByPopularity(ctx, data)
+		if err != nil {
+			return err
+		}
+	}
+
 	termWidth := terminal.GetWidth()
 	wrapWidth := termWidth - 10
 	if wrapWidth < 40 {
@@ -143,6 +163,9 @@ func Show(ctx context.Context, dockerCli command.Cli, name string, format Format
 			continue
 		}
 		fmt.Printf("  \033[1m%s\033[0m\n", k)
+		if line := popularity[k]; line != "" {
+			fmt.Printf("    %s\n", line)
+		}
 		wrappedDesc := wrapText(strings.TrimSpace(val.Description), wrapWidth, "    ")
 		fmt.Println(wrappedDesc)
 
@@ -165,7 +188,67 @@ func Show(ctx context.Context, dockerCli command.Cli, name string, format Format
 	return nil
 }
 
-func getSortedKeys(m map[string]Tile) []string {
+// sortByPopularity returns the names of the servers of a catalog, most popular first, and a summary of their popularity.
+// The popularity is refreshed from Docker Hub and GitHub when it's stale.
+func sortByPopularity(ctx context.Context, data []byte) ([]string, map[string]string, error) {
+	var registry struct {
+		Registry map[string]catalogTypes.Server `yaml:"registry"`
+	}
+	if err := yaml.Unmarshal(data, &registry); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal catalog data: %w", err)
+	}
+	servers := registry.Registry
+
+	cache, err := catalogTypes.ReadPopularity()
+	if err != nil {
+		return nil, nil, err
+	}
+	if cache.Stale() {
+		fmt.Fprintln(os.Stderr, "Refreshing the popularity of the servers...")
+		cache = catalogTypes.RefreshPopularity(ctx, cache, servers)
+		if err := catalogTypes.WritePopularity(cache); err != nil {
+			return nil, nil, fmt.Errorf("caching the popularity of the servers: %w", err)
+		}
+	}
+	cache.Apply(servers)
+
+	keys := getSortedKeys(servers)
+	sort.SliceStable(keys, func(i, j int) bool {
+		serverI, serverJ := servers[keys[i]], servers[keys[j]]
+		return serverI.PopularityScore() > serverJ.PopularityScore()
+	})
+
+	summaries := map[string]string{}
+	for name, server := range servers {
+		if server.Metadata == nil {
+			continue
+		}
+		var parts []string
+		if server.Metadata.Pulls > 0 {
+			parts = append(parts, fmt.Sprintf("%s pulls", humanCount(server.Metadata.Pulls)))
+		}
+		if server.Metadata.GithubStars > 0 {
+			parts = append(parts, fmt.Sprintf("%s GitHub stars", humanCount(server.Metadata.GithubStars)))
+		}
+		summaries[name] = strings.Join(parts, " · ")
+	}
+
+	return keys, summaries, nil
+}
+
+// humanCount formats a count, eg. 12.3k or 4.5M.
+func humanCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+func getSortedKeys[T any](m map[string]T) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
 		keys = append(keys, k)
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/backup"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+func backupCommand(docker docker.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up and restore the local MCP state",
+		Long: `Back up and restore the local MCP state, to move it to another machine or recover from a lost one.
+
+A backup is a gzipped tarball holding the database (profiles, catalogs, migration status and settings),
+the configuration files of ~/.docker/mcp (config.yaml, registry.yaml, tools.yaml and the catalogs),
+and a manifest listing the secrets the servers need. The values of the secrets are never backed up.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "create <file>",
+		Short:   "Back up the local MCP state to a file",
+		Example: "  docker mcp backup create mcp-backup.tar.gz",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, err := backup.Create(cmd.Context(), docker, args[0])
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Backed up the database and %d configuration file(s) to %s\n", len(manifest.Files), args[0])
+			if len(manifest.Secrets) > 0 {
+				fmt.Fprintf(out, "The servers need %d secret(s), whose values are not in the backup: %s\n", len(manifest.Secrets), secretNames(manifest.Secrets))
+			}
+			if len(manifest.StrippedConfig) > 0 {
+				fmt.Fprintf(out, "The database encryption is on, the config of %d server(s) is not in the backup: %s\n", len(manifest.StrippedConfig), strings.Join(manifest.StrippedConfig, ", "))
+			}
+			return nil
+		},
+	})
+
+	var force bool
+	restoreCmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Restore the local MCP state from a backup",
+		Long: `Restore the local MCP state from a backup created with docker mcp backup create.
+
+The database and the configuration files are replaced. Stop the running gateways first.`,
+		Example: "  docker mcp backup restore mcp-backup.tar.gz --force",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, err := backup.RestoreArchive(cmd.Context(), args[0], force)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Restored the database and %d configuration file(s) from the backup of %s\n", len(manifest.Files), manifest.CreatedAt.Local().Format("2006-01-02 15:04"))
+			if len(manifest.Secrets) > 0 {
+				fmt.Fprintln(out, "Set the secrets the servers need with docker mcp secret set <name>=<value>:")
+				for _, secret := range manifest.Secrets {
+					fmt.Fprintf(out, "  %s (%s)\n", secret.Name, strings.Join(secret.Servers, ", "))
+				}
+			}
+			if len(manifest.StrippedConfig) > 0 {
+				fmt.Fprintf(out, "The config of these servers was encrypted and is not in the backup, set it again: %s\n", strings.Join(manifest.StrippedConfig, ", "))
+			}
+			return nil
+		},
+	}
+	restoreCmd.Flags().BoolVar(&force, "force", false, "Replace the existing database")
+	cmd.AddCommand(restoreCmd)
+
+	return cmd
+}
+
+func secretNames(secrets []backup.RequiredSecret) string {
+	var names []string
+	for _, secret := range secrets {
+		names = append(names, secret.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -4,16 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/catalog"
 	catalogTypes "github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/lan"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/registryadapter"
+	"github.com/docker/mcp-gateway/pkg/servertest"
 	"github.com/docker/mcp-gateway/pkg/yq"
 )
 
-func catalogCommand(dockerCli command.Cli) *cobra.Command {
+func catalogCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "catalog",
 		Aliases: []string{"catalogs"},
@@ -32,11 +41,93 @@ func catalogCommand(dockerCli command.Cli) *cobra.Command {
 	cmd.AddCommand(initCatalogCommand())
 	cmd.AddCommand(addCatalogCommand())
 	cmd.AddCommand(resetCatalogCommand())
+	cmd.AddCommand(discoverCatalogCommand())
+	cmd.AddCommand(fixtureCatalogCommand(docker))
+	return cmd
+}
+
+func fixtureCatalogCommand(docker docker.Client) *cobra.Command {
+	var output string
+	var catalogPaths []string
+	var callAll bool
+	var verbose bool
+	cmd := &cobra.Command{
+		Use:   "fixture <server>",
+		Short: "Record a test fixture of a server of the catalog",
+		Long: `Run a server of the catalog, record the tools it lists and a sample call to each of them,
+and write them to a fixture file that can be replayed instead of running the server.
+
+The arguments of the calls are placeholders generated from the input schemas of the tools, and
+the values of the server's secrets are redacted. Only the tools annotated as read-only are called,
+unless --call-all is set.`,
+		Example: `  docker mcp catalog fixture duckduckgo
+  docker mcp catalog fixture github --output testdata/github.fixture.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serverName := args[0]
+			if output == "" {
+				output = serverName + ".fixture.json"
+			}
+			if !verbose {
+				log.SetLogWriter(io.Discard)
+				defer log.SetLogWriter(os.Stderr)
+			}
+
+			defaultPaths := convertCatalogNamesToPaths(catalogPaths)
+			var configuredPaths []string
+			if len(catalogPaths) == 0 {
+				defaultPaths = []string{catalog.DockerCatalogFilename}
+				configuredPaths = getConfiguredCatalogPaths()
+			}
+
+			ctx := cmd.Context()
+			serverConfig, err := gateway.ReadServerConfig(ctx, docker, gateway.Config{
+				CatalogPath: buildUniqueCatalogPaths(defaultPaths, configuredPaths, nil),
+				ConfigPath:  []string{"config.yaml"},
+				SecretsPath: "docker-desktop",
+			}, serverName)
+			if err != nil {
+				return fmt.Errorf("reading configuration of %s: %w", serverName, err)
+			}
+
+			client, stop, err := gateway.StartServer(ctx, gateway.Options{Cpus: 1, Memory: "2Gb"}, docker, serverConfig)
+			if err != nil {
+				return fmt.Errorf("starting server %s: %w", serverName, err)
+			}
+			defer stop()
+
+			fixture, err := servertest.Record(ctx, client.Session(), serverConfig, callAll)
+			if err != nil {
+				return fmt.Errorf("recording fixture of %s: %w", serverName, err)
+			}
+
+			if err := servertest.WriteFixture(output, fixture); err != nil {
+				return fmt.Errorf("writing fixture: %w", err)
+			}
+
+			called := 0
+			for _, call := range fixture.Calls {
+				if call.Skipped == "" {
+					called++
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Recorded %d tools and %d calls of %s to %s\n", len(fixture.Tools), called, serverName, output)
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&output, "output", "o", "", "Path of the fixture file (default <server>.fixture.json)")
+	flags.StringSliceVar(&catalogPaths, "catalog", nil, "Catalogs to find the server in (default: the Docker catalog and the configured catalogs)")
+	flags.BoolVar(&callAll, "call-all", false, "Also call the tools that aren't annotated as read-only")
+	flags.BoolVar(&verbose, "verbose", false, "Show the logs of the server")
+
 	return cmd
 }
 
 func importCatalogCommand() *cobra.Command {
 	var mcpRegistry string
+	var adapter string
 	var dryRun bool
 	cmd := &cobra.Command{
 		Use:   "import <alias|url|file>",
@@ -45,7 +136,14 @@ func importCatalogCommand() *cobra.Command {
 and stored locally for use with the MCP gateway.
 
 When --mcp-registry flag is used, the argument must be an existing catalog name, and the
-command will import servers from the MCP registry URL into that catalog.`,
+command will import servers from the MCP registry URL into that catalog. Instead of a URL,
+--mcp-registry accepts the name of a server in the default MCP registry, optionally with a
+version (e.g. com.example/weather@1.2.0). The latest version is imported by default.
+
+--adapter reads other registry formats, and normalizes their servers into catalog entries:
+  mcp-registry  A server of the MCP registry (default)
+  mcp-servers   A URL or file with an mcpServers configuration, like the ones published by mcp.so
+  smithery      A server hosted by Smithery, by qualified name (uses SMITHERY_API_KEY)`,
 		Args: cobra.ExactArgs(1),
 		Example: `  # Import from URL
   docker mcp catalog import https://example.com/my-catalog.yaml
@@ -54,20 +152,33 @@ command will import servers from the MCP registry URL into that catalog.`,
   docker mcp catalog import ./shared-catalog.yaml
   
   # Import from MCP registry URL into existing catalog
-  docker mcp catalog import my-catalog --mcp-registry https://registry.example.com/server`,
+  docker mcp catalog import my-catalog --mcp-registry https://registry.example.com/server
+
+  # Import a server by name from the default MCP registry into existing catalog
+  docker mcp catalog import my-catalog --mcp-registry com.example/weather@1.2.0
+
+  # Import the servers of an mcpServers configuration into existing catalog
+  docker mcp catalog import my-catalog --adapter mcp-servers --mcp-registry ./claude_desktop_config.json
+
+  # Import a server hosted by Smithery into existing catalog
+  docker mcp catalog import my-catalog --adapter smithery --mcp-registry @owner/server`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// If mcp-registry flag is provided, import to existing catalog
 			if mcpRegistry != "" {
-				if dryRun {
+				if dryRun && adapter == registryadapter.Default {
 					return runMcpregistryImport(cmd.Context(), mcpRegistry, nil)
 				}
-				return importMCPRegistryToCatalog(cmd.Context(), args[0], mcpRegistry)
+				return importMCPRegistryToCatalog(cmd.Context(), args[0], mcpRegistry, adapter, dryRun)
+			}
+			if adapter != registryadapter.Default {
+				return fmt.Errorf("--adapter can only be used with --mcp-registry")
 			}
 			// Default behavior: import entire catalog
 			return catalog.Import(cmd.Context(), args[0])
 		},
 	}
-	cmd.Flags().StringVar(&mcpRegistry, "mcp-registry", "", "Import server from MCP registry URL into existing catalog")
+	cmd.Flags().StringVar(&mcpRegistry, "mcp-registry", "", "Import server from MCP registry URL, or by name from the default MCP registry, into existing catalog")
+	cmd.Flags().StringVar(&adapter, "adapter", registryadapter.Default, fmt.Sprintf("Format of the registry given with --mcp-registry (%s)", strings.Join(registryadapter.Names(), ", ")))
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show Imported Data but do not update the Catalog")
 	return cmd
 }
@@ -146,6 +257,7 @@ If no name is provided, updates all catalogs that have remote sources.`,
 func showCatalogCommand(dockerCli command.Cli) *cobra.Command {
 	var opts struct {
 		Format catalog.Format
+		Sort   string
 	}
 	cmd := &cobra.Command{
 		Use:   "show [name]",
@@ -157,7 +269,10 @@ If no name is provided, shows the Docker official catalog.`,
   docker mcp catalog show
 
   # Show a specific catalog in JSON format
-  docker mcp catalog show my-catalog --format=json`,
+  docker mcp catalog show my-catalog --format=json
+
+  # Show the most popular servers first
+  docker mcp catalog show --sort popularity`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := catalog.DockerCatalogName
 			if len(args) > 0 {
@@ -165,11 +280,12 @@ If no name is provided, shows the Docker official catalog.`,
 			}
 
 			mcpOAuthDcrEnabled := isMcpOAuthDcrFeatureEnabled(dockerCli)
-			return catalog.Show(cmd.Context(), dockerCli, name, opts.Format, mcpOAuthDcrEnabled)
+			return catalog.Show(cmd.Context(), dockerCli, name, opts.Format, opts.Sort, mcpOAuthDcrEnabled)
 		},
 	}
 	flags := cmd.Flags()
 	flags.Var(&opts.Format, "format", fmt.Sprintf("Supported: %s.", catalog.SupportedFormats()))
+	flags.StringVar(&opts.Sort, "sort", catalog.SortName, fmt.Sprintf("Sort the servers by %s or %s (pulls and stars, refreshed daily from Docker Hub and GitHub)", catalog.SortName, catalog.SortPopularity))
 	return cmd
 }
 
@@ -249,6 +365,40 @@ The server definition includes all configuration, tools, and metadata.`,
 	return cmd
 }
 
+func discoverCatalogCommand() *cobra.Command {
+	opts := catalog.DiscoverOptions{MDNS: true}
+	var noMDNS bool
+	cmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Discover MCP servers on the local network",
+		Long: `Discover the MCP servers advertised with mDNS (as _mcp._tcp services) on the local network,
+and the MCP gateways of the given hosts that describe themselves at /.well-known/mcp-gateway.
+Anything on the network can advertise a server: only add the ones you trust to a catalog, as remote servers.`,
+		Args: cobra.NoArgs,
+		Example: `  # List the MCP servers advertised on the local network
+  docker mcp catalog discover
+
+  # Also probe a gateway running on another machine
+  docker mcp catalog discover --host 192.168.1.20:8811
+
+  # Add a discovered server to a catalog
+  docker mcp catalog discover --add home-assistant --catalog my-catalog`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.MDNS = !noMDNS
+			return catalog.Discover(cmd.Context(), opts)
+		},
+	}
+	flags := cmd.Flags()
+	flags.BoolVar(&noMDNS, "no-mdns", false, "Only probe the hosts given with --host")
+	flags.StringSliceVar(&opts.Hosts, "host", nil, "Host (host:port) probed for a /.well-known/mcp-gateway document")
+	flags.DurationVar(&opts.Timeout, "timeout", lan.DefaultTimeout, "How long to wait for the servers to answer")
+	flags.Var(&opts.Format, "format", fmt.Sprintf("Output format. Supported: %s.", catalog.SupportedFormats()))
+	flags.StringVar(&opts.Add, "add", "", "Name of a discovered server to add to the catalog given with --catalog")
+	flags.StringVar(&opts.Catalog, "catalog", "", "Catalog to which the server is added")
+	flags.BoolVar(&opts.Force, "force", false, "Overwrite existing server in the catalog")
+	return cmd
+}
+
 func resetCatalogCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:     "reset",
@@ -264,43 +414,53 @@ func resetCatalogCommand() *cobra.Command {
 	}
 }
 
-// importMCPRegistryToCatalog imports a server from an MCP registry URL into an existing catalog
-func importMCPRegistryToCatalog(ctx context.Context, catalogName, mcpRegistryURL string) error {
-	// Check if the catalog exists
-	cfg, err := catalog.ReadConfig()
+// importMCPRegistryToCatalog imports the servers a registry reference points to into an existing catalog
+func importMCPRegistryToCatalog(ctx context.Context, catalogName, mcpRegistryURL, adapterName string, dryRun bool) error {
+	adapter, err := registryadapter.Get(adapterName)
 	if err != nil {
-		return fmt.Errorf("failed to read catalog config: %w", err)
-	}
-
-	_, exists := cfg.Catalogs[catalogName]
-	if !exists {
-		return fmt.Errorf("catalog '%s' does not exist", catalogName)
+		return err
 	}
 
-	// Prevent users from modifying the Docker catalog
-	if catalogName == catalog.DockerCatalogName {
-		return fmt.Errorf("cannot import servers into catalog '%s' as it is managed by Docker", catalogName)
+	if !dryRun {
+		// Check if the catalog exists
+		cfg, err := catalog.ReadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to read catalog config: %w", err)
+		}
+
+		_, exists := cfg.Catalogs[catalogName]
+		if !exists {
+			return fmt.Errorf("catalog '%s' does not exist", catalogName)
+		}
+
+		// Prevent users from modifying the Docker catalog
+		if catalogName == catalog.DockerCatalogName {
+			return fmt.Errorf("cannot import servers into catalog '%s' as it is managed by Docker", catalogName)
+		}
 	}
 
-	// Fetch server from MCP registry
+	// Fetch the servers from the registry
 	var servers []catalogTypes.Server
-	if err := runMcpregistryImport(ctx, mcpRegistryURL, &servers); err != nil {
-		return fmt.Errorf("failed to fetch server from MCP registry: %w", err)
+	if adapterName == registryadapter.Default {
+		err = runMcpregistryImport(ctx, mcpRegistryURL, &servers)
+	} else {
+		servers, err = adapter.Fetch(ctx, mcpRegistryURL)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch server from %s: %w", adapterName, err)
 	}
 
 	if len(servers) == 0 {
-		return fmt.Errorf("no servers found at MCP registry URL")
+		return fmt.Errorf("no servers found at %s", mcpRegistryURL)
 	}
 
-	// For now, we'll import the first server (MCP registry URLs typically contain one server)
-	server := servers[0]
-
-	serverName := server.Name
-
-	// Convert the server to JSON for injection into the catalog
-	serverJSON, err := json.Marshal(server)
-	if err != nil {
-		return fmt.Errorf("failed to marshal server: %w", err)
+	if dryRun {
+		buf, err := json.MarshalIndent(servers, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal servers: %w", err)
+		}
+		fmt.Println(string(buf))
+		return nil
 	}
 
 	// Read the current catalog content
@@ -309,18 +469,30 @@ func importMCPRegistryToCatalog(ctx context.Context, catalogName, mcpRegistryURL
 		return fmt.Errorf("failed to read catalog file: %w", err)
 	}
 
-	// Inject the server into the catalog using the same pattern as the add function
-	updatedContent, err := injectServerIntoCatalog(catalogContent, serverName, serverJSON)
-	if err != nil {
-		return fmt.Errorf("failed to inject server into catalog: %w", err)
+	for _, server := range servers {
+		serverName := server.Name
+
+		// Convert the server to JSON for injection into the catalog
+		serverJSON, err := json.Marshal(server)
+		if err != nil {
+			return fmt.Errorf("failed to marshal server: %w", err)
+		}
+
+		// Inject the server into the catalog using the same pattern as the add function
+		catalogContent, err = injectServerIntoCatalog(catalogContent, serverName, serverJSON)
+		if err != nil {
+			return fmt.Errorf("failed to inject server into catalog: %w", err)
+		}
 	}
 
 	// Write the updated catalog back
-	if err := catalog.WriteCatalogFile(catalogName, updatedContent); err != nil {
+	if err := catalog.WriteCatalogFile(catalogName, catalogContent); err != nil {
 		return fmt.Errorf("failed to write updated catalog: %w", err)
 	}
 
-	fmt.Printf("Successfully imported server '%s' from MCP registry into catalog '%s'\n", serverName, catalogName)
+	for _, server := range servers {
+		fmt.Printf("Successfully imported server '%s' from %s into catalog '%s'\n", server.Name, adapterName, catalogName)
+	}
 	return nil
 }
 
@@ -25,6 +25,7 @@ func catalogNextCommand() *cobra.Command {
 	cmd.AddCommand(removeCatalogNextCommand())
 	cmd.AddCommand(pushCatalogNextCommand())
 	cmd.AddCommand(pullCatalogNextCommand())
+	cmd.AddCommand(syncCatalogNextCommand())
 	cmd.AddCommand(tagCatalogNextCommand())
 	cmd.AddCommand(catalogNextServerCommand())
 
@@ -155,8 +156,10 @@ func removeCatalogNextCommand() *cobra.Command {
 }
 
 func pushCatalogNextCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "push <oci-reference>",
+	var signKey string
+
+	cmd := &cobra.Command{
+		Use:   "push <oci-reference> [--sign-key <cosign.key>]",
 		Short: "Push a catalog to an OCI registry",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -164,14 +167,21 @@ func pushCatalogNextCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return catalognext.Push(cmd.Context(), dao, args[0])
+			return catalognext.Push(cmd.Context(), dao, args[0], signKey)
 		},
 	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&signKey, "sign-key", "", "Sign the catalog with a cosign private key, decrypted with the COSIGN_PASSWORD environment variable")
+
+	return cmd
 }
 
 func pullCatalogNextCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "pull <oci-reference>",
+	var opts catalognext.VerifyOptions
+
+	cmd := &cobra.Command{
+		Use:   "pull <oci-reference> [--key <cosign.pub>] [--require-signature]",
 		Short: "Pull a catalog from an OCI registry",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -180,9 +190,40 @@ func pullCatalogNextCommand() *cobra.Command {
 				return err
 			}
 			ociService := oci.NewService()
-			return catalognext.Pull(cmd.Context(), dao, ociService, args[0])
+			return catalognext.Pull(cmd.Context(), dao, ociService, args[0], opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Key, "key", "", fmt.Sprintf("Verify the signature of the catalog with a cosign public key. Defaults to the %s setting", catalognext.SettingVerifyKey))
+	flags.BoolVar(&opts.RequireSignature, "require-signature", false, fmt.Sprintf("Refuse catalogs without a valid signature, as does the %s=on setting", catalognext.SettingRequireSignature))
+
+	return cmd
+}
+
+func syncCatalogNextCommand() *cobra.Command {
+	var opts struct {
+		FromLegacyCatalog string
+	}
+
+	cmd := &cobra.Command{
+		Use:   "sync <oci-reference> [--from-legacy-catalog <url>]",
+		Short: "Re-sync a catalog from the profile, legacy catalog or OCI reference it was created from",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			ociService := oci.NewService()
+			return catalognext.Sync(cmd.Context(), dao, ociService, args[0], opts.FromLegacyCatalog)
 		},
 	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.FromLegacyCatalog, "from-legacy-catalog", "", "Location of the legacy catalog, for catalogs created from a legacy catalog that isn't configured locally")
+
+	return cmd
 }
 
 func catalogNextServerCommand() *cobra.Command {
@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/backup"
+	catalognext "github.com/docker/mcp-gateway/pkg/catalog_next"
 	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/registryapi"
 )
 
 func configCommand(docker docker.Client) *cobra.Command {
@@ -50,6 +55,33 @@ func configCommand(docker docker.Client) *cobra.Command {
 		},
 	})
 
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <key>=<value>",
+		Short: "Change a setting",
+		Long: `Change a setting.
+
+Settings:
+  db.encryption=on|off  Encrypt the sensitive fields of the local database (e.g. the config of the servers in profiles),
+                        with a key stored in the OS keyring. Existing data is encrypted or decrypted accordingly.
+  registry.url=<url>    MCP registry that server names (e.g. com.example/weather@1.2.0) are imported from.
+                        Defaults to https://registry.modelcontextprotocol.io. Empty to reset.
+  catalog.verify-key=<path>
+                        Cosign public key that the signatures of pulled catalogs are verified with. Empty to reset.
+  catalog.require-signature=on|off
+                        Refuse to pull catalogs without a valid signature.`,
+		Example: `  docker mcp config set db.encryption=on
+  docker mcp config set registry.url=https://registry.example.com
+  docker mcp config set catalog.verify-key=$HOME/.docker/mcp/cosign.pub`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value, found := strings.Cut(args[0], "=")
+			if !found {
+				return fmt.Errorf("invalid setting %q, expected <key>=<value>", args[0])
+			}
+			return setSetting(cmd, key, value)
+		},
+	})
+
 	cmd.AddCommand(&cobra.Command{
 		Use:    "dump",
 		Short:  "Dump the whole configuration",
@@ -94,3 +126,90 @@ func configCommand(docker docker.Client) *cobra.Command {
 
 	return cmd
 }
+
+func setSetting(cmd *cobra.Command, key, value string) error {
+	switch key {
+	case db.SettingEncryption:
+		var enabled bool
+		switch value {
+		case "on":
+			enabled = true
+		case "off":
+			enabled = false
+		default:
+			return fmt.Errorf("invalid value %q for %s, expected on or off", value, key)
+		}
+
+		dao, err := db.New()
+		if err != nil {
+			return err
+		}
+		defer dao.Close()
+
+		if err := dao.SetEncryption(cmd.Context(), enabled); err != nil {
+			return fmt.Errorf("failed to turn database encryption %s: %w", value, err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Database encryption is %s\n", value)
+		return nil
+	case registryapi.SettingBaseURL:
+		if value != "" && !registryapi.IsServerURL(value) {
+			return fmt.Errorf("invalid value %q for %s, expected an http or https URL", value, key)
+		}
+
+		if err := storeSetting(cmd, key, value); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Server names are imported from %s\n", registryapi.BaseURL(cmd.Context(), db.LazySettings{}))
+		return nil
+	case catalognext.SettingVerifyKey:
+		if value != "" {
+			absPath, err := filepath.Abs(value)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(absPath); err != nil {
+				return fmt.Errorf("invalid value %q for %s: %w", value, key, err)
+			}
+			value = absPath
+		}
+
+		if err := storeSetting(cmd, key, value); err != nil {
+			return err
+		}
+
+		if value == "" {
+			fmt.Fprintln(cmd.OutOrStdout(), "Pulled catalogs are not verified")
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Pulled catalogs are verified with %s\n", value)
+		}
+		return nil
+	case catalognext.SettingRequireSignature:
+		if value != "on" && value != "off" {
+			return fmt.Errorf("invalid value %q for %s, expected on or off", value, key)
+		}
+
+		if err := storeSetting(cmd, key, value); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Catalog signatures are required: %s\n", value)
+		return nil
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+}
+
+func storeSetting(cmd *cobra.Command, key, value string) error {
+	dao, err := db.New()
+	if err != nil {
+		return err
+	}
+	defer dao.Close()
+
+	if err := dao.SetSetting(cmd.Context(), key, value); err != nil {
+		return fmt.Errorf("failed to set %s: %w", key, err)
+	}
+	return nil
+}
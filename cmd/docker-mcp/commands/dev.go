@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/gateway"
+)
+
+func devCommand(docker docker.Client) *cobra.Command {
+	var dev gateway.DevOptions
+	config := gateway.Config{
+		SecretsPath: "docker-desktop",
+		ConfigPath:  []string{"config.yaml"},
+		Options: gateway.Options{
+			Cpus:         1,
+			Memory:       "2Gb",
+			Transport:    "stdio",
+			LogCalls:     true,
+			BlockSecrets: true,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "dev <path|image>",
+		Short: "Run a gateway with a server under development, reloaded on each rebuild",
+		Long: `Run a gateway with a single server under development, for a tight inner loop.
+
+Given the path of a project, the project is built with docker build and rebuilt when its files change.
+Given an image, the gateway waits for new builds of the image, for example by another tool.
+Each time, the server is restarted and its tools, prompts and resources are swapped in the running gateway,
+without restarting the clients' sessions: they're notified that the lists changed.
+
+The definition of the server (secrets, config, tools...) is read from the image's io.docker.server.metadata label, if it has one.
+Its config is read from config.yaml and its secrets from Docker Desktop, like any other server.`,
+		Example: `  # Build the project in the current directory and rebuild it on change
+  docker mcp dev .
+
+  # Reload the server when my-server:dev is rebuilt
+  docker mcp dev my-server:dev
+
+  # Serve the server under development to clients connecting with the streaming transport
+  docker mcp dev ./my-server --transport streaming --port 8811`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains([]string{"stdio", "sse", "streaming"}, config.Transport) {
+				return fmt.Errorf("invalid --transport %q, expected stdio, sse or streaming", config.Transport)
+			}
+
+			if info, err := os.Stat(args[0]); err == nil && info.IsDir() {
+				dev.Path = args[0]
+			} else {
+				if dev.Image != "" {
+					return fmt.Errorf("--image can only be used with the path of a project")
+				}
+				dev.Image = args[0]
+			}
+
+			return gateway.Dev(cmd.Context(), docker, config, dev)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&dev.Name, "name", "", "Name of the server (defaults to the name in the image's metadata label, or to the name of the project's directory)")
+	flags.StringVar(&dev.Image, "image", "", "Tag of the image built from the project (default mcp-dev/<name>:latest)")
+	flags.DurationVar(&dev.Interval, "interval", gateway.DefaultDevInterval, "How often to check the project, or the image, for changes")
+	flags.StringVar(&config.Transport, "transport", config.Transport, "stdio, sse or streaming")
+	flags.IntVar(&config.Port, "port", 0, "TCP port to listen on (with the sse or streaming transport)")
+	flags.BoolVar(&config.Verbose, "verbose", false, "Verbose output")
+
+	return cmd
+}
@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
@@ -13,6 +17,9 @@ import (
 	catalogTypes "github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/logs"
+	"github.com/docker/mcp-gateway/pkg/notify"
 )
 
 func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
@@ -29,6 +36,8 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 	var additionalToolsConfig []string
 	var mcpRegistryUrls []string
 	var enableAllServers bool
+	var exposeAll bool
+	var exposeAllMax int
 	if os.Getenv("DOCKER_MCP_IN_CONTAINER") == "1" {
 		// In-container.
 		// Note: The catalog URL will be updated after checking the feature flag in RunE
@@ -68,14 +77,15 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			if isWorkingSetsFeatureEnabled(dockerCli) {
-				if len(options.ServerNames) > 0 || enableAllServers ||
+				// --servers picks servers of the profile when a profile is given
+				if (len(options.ServerNames) > 0 && options.WorkingSet == "") || enableAllServers || exposeAll ||
 					len(options.CatalogPath) > 0 || len(options.RegistryPath) > 0 || len(options.ConfigPath) > 0 || len(options.ToolsPath) > 0 ||
 					len(additionalCatalogs) > 0 || len(additionalRegistries) > 0 || len(additionalConfigs) > 0 || len(additionalToolsConfig) > 0 ||
 					len(mcpRegistryUrls) > 0 || len(options.OciRef) > 0 ||
 					(options.SecretsPath != "docker-desktop" && !strings.HasPrefix(options.SecretsPath, "docker-desktop:")) {
 					// We're in legacy mode, so we can't use the working set feature
 					if options.WorkingSet != "" {
-						return fmt.Errorf("cannot use --profile with --servers, --enable-all-servers, --catalog, --additional-catalog, --registry, --additional-registry, --config, --additional-config, --tools-config, --additional-tools-config, --secrets, --oci-ref, --mcp-registry flags")
+						return fmt.Errorf("cannot use --profile with --enable-all-servers, --expose-all, --catalog, --additional-catalog, --registry, --additional-registry, --config, --additional-config, --tools-config, --additional-tools-config, --secrets, --oci-ref, --mcp-registry flags")
 					}
 					// Make sure to default the options in legacy mode
 					setLegacyDefaults(&options)
@@ -86,6 +96,25 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 				}
 			}
 
+			// Profiles can be bound to a Docker context. In-container, the context of the host isn't known.
+			if options.WorkingSet == "" {
+				if cmd.Flags().Changed("servers-mode") {
+					return errors.New("cannot use --servers-mode without --profile")
+				}
+				if options.PersistProfile {
+					return errors.New("cannot use --persist without --profile")
+				}
+				if options.Dev {
+					return errors.New("cannot use --dev without --profile")
+				}
+			} else if !slices.Contains(gateway.ServersModes, options.ServersMode) {
+				return fmt.Errorf("invalid --servers-mode %q, expected one of: %s", options.ServersMode, strings.Join(gateway.ServersModes, ", "))
+			}
+
+			if options.WorkingSet != "" && os.Getenv("DOCKER_MCP_IN_CONTAINER") != "1" {
+				options.DockerContext = dockerCli.CurrentContext()
+			}
+
 			// Check if OAuth interceptor feature is enabled
 			options.OAuthInterceptorEnabled = isOAuthInterceptorFeatureEnabled(dockerCli)
 
@@ -107,6 +136,62 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 				options.Watch = false
 			}
 
+			if !slices.Contains(gateway.ToolConflictStrategies, options.ToolConflictStrategy) {
+				return fmt.Errorf("invalid --tool-conflict-strategy %q, expected one of: %s", options.ToolConflictStrategy, strings.Join(gateway.ToolConflictStrategies, ", "))
+			}
+
+			if !slices.Contains(gateway.AutoEnableModes, options.AutoEnable) {
+				return fmt.Errorf("invalid --auto-enable %q, expected one of: %s", options.AutoEnable, strings.Join(gateway.AutoEnableModes, ", "))
+			}
+
+			if !slices.Contains(gateway.PullPolicies, options.PullPolicy) {
+				return fmt.Errorf("invalid --pull-policy %q, expected one of: %s", options.PullPolicy, strings.Join(gateway.PullPolicies, ", "))
+			}
+
+			if !slices.Contains(gateway.BudgetActions, options.BudgetAction) {
+				return fmt.Errorf("invalid --budget-action %q, expected one of: %s", options.BudgetAction, strings.Join(gateway.BudgetActions, ", "))
+			}
+
+			if options.PageSize < 0 {
+				return fmt.Errorf("invalid --page-size %d, must be positive", options.PageSize)
+			}
+
+			if options.CoalesceWindow < 0 {
+				return fmt.Errorf("invalid --coalesce-window %s, must be positive", options.CoalesceWindow)
+			}
+
+			if options.ClientLeakThreshold < 0 {
+				return fmt.Errorf("invalid --client-leak-threshold %s, must be positive", options.ClientLeakThreshold)
+			}
+
+			if options.CompressionMinSize < 0 {
+				return fmt.Errorf("invalid --compression-min-size %d, must be positive", options.CompressionMinSize)
+			}
+
+			if options.HTTPMaxConnsPerHost < 0 {
+				return fmt.Errorf("invalid --http-max-conns-per-host %d, must be positive", options.HTTPMaxConnsPerHost)
+			}
+
+			if options.HTTPMaxIdleConnsPerHost < 0 {
+				return fmt.Errorf("invalid --http-max-idle-conns-per-host %d, must be positive", options.HTTPMaxIdleConnsPerHost)
+			}
+
+			if options.HTTPIdleConnTimeout < 0 {
+				return fmt.Errorf("invalid --http-idle-conn-timeout %s, must be positive", options.HTTPIdleConnTimeout)
+			}
+
+			if options.ContainerName != "" {
+				if _, err := gateway.RenderContainerName(options.ContainerName, gateway.ContainerNameData{Server: "server"}); err != nil {
+					return fmt.Errorf("invalid --container-name: %w", err)
+				}
+			}
+
+			if options.ScratchSize != "" {
+				if _, err := gateway.ParseScratchSize(options.ScratchSize); err != nil {
+					return fmt.Errorf("invalid --scratch-size: %w", err)
+				}
+			}
+
 			if options.Transport == "stdio" {
 				if options.Port != 0 {
 					return errors.New("cannot use --port with --transport=stdio")
@@ -161,10 +246,33 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 				options.ServerNames = allServerNames
 			}
 
+			// Handle --expose-all flag
+			if exposeAll {
+				if len(options.ServerNames) > 0 || enableAllServers {
+					return fmt.Errorf("cannot use --expose-all with --servers or --enable-all-servers flags")
+				}
+
+				mcpCatalog, err := catalogTypes.ReadFrom(cmd.Context(), catalogPaths)
+				if err != nil {
+					return fmt.Errorf("failed to read catalogs for --expose-all: %w", err)
+				}
+
+				exposed, skipped := gateway.ExposableServers(mcpCatalog.Servers, exposeAllMax)
+				if len(exposed) == 0 {
+					return fmt.Errorf("no server of the catalog can be enabled without secrets")
+				}
+				fmt.Fprintf(dockerCli.Err(), "- Exposing %d server(s) of the catalog, skipping %d\n", len(exposed), len(skipped))
+				for _, server := range skipped {
+					fmt.Fprintf(dockerCli.Err(), "  > Skipped %s: %s\n", server.Name, server.Reason)
+				}
+				options.ServerNames = exposed
+			}
+
 			// Disable dynamic-tools if the user explicitly configured a set of servers via the --servers flag.
 			// When users specify servers explicitly, they're operating in a more manual mode
 			// and may not want the automatic server management tools (mcp-find, mcp-add, mcp-remove).
-			if len(options.ServerNames) > 0 && !enableAllServers {
+			// With a profile, mcp-add can still add the other servers of the profile.
+			if len(options.ServerNames) > 0 && !enableAllServers && !exposeAll && options.WorkingSet == "" {
 				if options.DynamicTools {
 					options.DynamicTools = false
 					if options.Verbose {
@@ -179,9 +287,15 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 
 	runCmd.Flags().StringSliceVar(&options.ServerNames, "servers", nil, "Names of the servers to enable (if non empty, ignore --registry flag)")
 	if isWorkingSetsFeatureEnabled(dockerCli) {
-		runCmd.Flags().StringVar(&options.WorkingSet, "profile", "", "Profile ID to use (mutually exclusive with --servers and --enable-all-servers)")
+		runCmd.Flags().StringVar(&options.WorkingSet, "profile", "", "Profile ID to use (mutually exclusive with --enable-all-servers and --expose-all, --servers picks servers of the profile)")
+		runCmd.Flags().StringVar(&options.ServersMode, "servers-mode", gateway.ServersModeIntersection, "With --profile, how --servers combines with the enabled servers of the profile: intersection starts only the listed servers, union starts the listed servers too")
+		runCmd.Flags().BoolVar(&options.Dev, "dev", false, "With --profile, apply the dev volumes and environment variables of the servers, set with docker mcp profile server dev")
+		runCmd.Flags().BoolVar(&options.PersistProfile, "persist", false, "With --profile, write the servers added and removed with mcp-add and mcp-remove back to the profile, instead of keeping the changes for the lifetime of the gateway")
+		runCmd.Flags().BoolVar(&options.SkipBroken, "skip-broken", false, "Start the gateway without the servers of the profile that can't be started, instead of failing")
 	}
 	runCmd.Flags().BoolVar(&enableAllServers, "enable-all-servers", false, "Enable all servers in the catalog (instead of using individual --servers options)")
+	runCmd.Flags().BoolVar(&exposeAll, "expose-all", false, "Enable all the servers of the catalog that need no secrets or OAuth authorization, reporting the servers that are skipped (for demos and testing)")
+	runCmd.Flags().IntVar(&exposeAllMax, "expose-all-max", 0, "Maximum number of servers enabled by --expose-all (0 means no limit)")
 	runCmd.Flags().StringSliceVar(&options.CatalogPath, "catalog", options.CatalogPath, "Paths to docker catalogs (absolute or relative to ~/.docker/mcp/catalogs/)")
 	runCmd.Flags().StringSliceVar(&additionalCatalogs, "additional-catalog", nil, "Additional catalog paths to append to the default catalogs")
 	runCmd.Flags().StringSliceVar(&options.RegistryPath, "registry", options.RegistryPath, "Paths to the registry files (absolute or relative to ~/.docker/mcp/)")
@@ -190,16 +304,20 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 	runCmd.Flags().StringSliceVar(&additionalConfigs, "additional-config", nil, "Additional config paths to merge with the default config.yaml")
 	runCmd.Flags().StringSliceVar(&options.ToolsPath, "tools-config", options.ToolsPath, "Paths to the tools files (absolute or relative to ~/.docker/mcp/)")
 	runCmd.Flags().StringSliceVar(&additionalToolsConfig, "additional-tools-config", nil, "Additional tools paths to merge with the default tools.yaml")
+	runCmd.Flags().DurationVar(&options.SecretsCacheTTL, "secrets-cache-ttl", gateway.DefaultSecretsCacheTTL, "How long the secrets read from Docker Desktop are cached, unless they're changed with `docker mcp secret set` or `rm` (0 disables the cache)")
 	runCmd.Flags().StringVar(&options.SecretsPath, "secrets", options.SecretsPath, "Colon separated paths to search for secrets. Can be `docker-desktop` or a path to a .env file (default to using Docker Desktop's secrets API)")
 	runCmd.Flags().StringSliceVar(&options.ToolNames, "tools", options.ToolNames, "List of tools to enable")
 	runCmd.Flags().StringArrayVar(&options.Interceptors, "interceptor", options.Interceptors, "List of interceptors to use (format: when:type:path, e.g. 'before:exec:/bin/path')")
 	runCmd.Flags().StringArrayVar(&options.OciRef, "oci-ref", options.OciRef, "OCI image references to use")
-	runCmd.Flags().StringSliceVar(&mcpRegistryUrls, "mcp-registry", nil, "MCP registry URLs to fetch servers from (can be repeated)")
+	runCmd.Flags().StringSliceVar(&mcpRegistryUrls, "mcp-registry", nil, "MCP registry URLs, or server names in the default MCP registry (e.g. com.example/weather@1.2.0), to fetch servers from (can be repeated)")
 	runCmd.Flags().IntVar(&options.Port, "port", options.Port, "TCP port to listen on (default is to listen on stdio)")
-	runCmd.Flags().StringVar(&options.Transport, "transport", options.Transport, "stdio, sse or streaming. Uses MCP_GATEWAY_AUTH_TOKEN environment variable for localhost authentication to prevent dns rebinding attacks.")
+	runCmd.Flags().StringVar(&options.Transport, "transport", options.Transport, "stdio, sse, streaming or websocket. Uses MCP_GATEWAY_AUTH_TOKEN environment variable for localhost authentication to prevent dns rebinding attacks.")
 	runCmd.Flags().BoolVar(&options.LogCalls, "log-calls", options.LogCalls, "Log calls to the tools")
 	runCmd.Flags().BoolVar(&options.BlockSecrets, "block-secrets", options.BlockSecrets, "Block secrets from being/received sent to/from tools")
+	runCmd.Flags().BoolVar(&options.ConfirmDestructiveTools, "confirm-destructive-tools", options.ConfirmDestructiveTools, "Ask the user to confirm, through elicitation, calls to tools annotated as destructive")
+	runCmd.Flags().StringSliceVar(&options.ConfirmTools, "confirm-tools", options.ConfirmTools, "Tool name patterns (e.g. 'delete_*') for which the user must confirm calls, through elicitation")
 	runCmd.Flags().BoolVar(&options.BlockNetwork, "block-network", options.BlockNetwork, "Block tools from accessing forbidden network resources")
+	runCmd.Flags().StringVar(&options.PullPolicy, "pull-policy", gateway.PullPolicyIfNotPresent, "When to pull the images of the servers: always, if-not-present or never. Servers can override it with pullPolicy in the catalog")
 	runCmd.Flags().BoolVar(&options.VerifySignatures, "verify-signatures", options.VerifySignatures, "Verify signatures of the server images")
 	runCmd.Flags().BoolVar(&options.DryRun, "dry-run", options.DryRun, "Start the gateway but do not listen for connections (useful for testing the configuration)")
 	runCmd.Flags().BoolVar(&options.Verbose, "verbose", options.Verbose, "Verbose output")
@@ -208,18 +326,529 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 	runCmd.Flags().BoolVar(&options.Watch, "watch", options.Watch, "Watch for changes and reconfigure the gateway")
 	runCmd.Flags().IntVar(&options.Cpus, "cpus", options.Cpus, "CPUs allocated to each MCP Server (default is 1)")
 	runCmd.Flags().StringVar(&options.Memory, "memory", options.Memory, "Memory allocated to each MCP Server (default is 2Gb)")
+	runCmd.Flags().StringVar(&options.ContainerName, "container-name", options.ContainerName, "Go template of the names of the containers of the MCP Servers, with {{.Server}}, {{.Gateway}}, {{.Session}}, {{.Profile}} and {{.Random}} (e.g. mcp-{{.Server}}-{{.Random}}, by default Docker generates the names)")
+	runCmd.Flags().StringVar(&options.ScratchSize, "scratch-size", options.ScratchSize, "Give each MCP Server a scratch volume of this size (e.g. 256m), mounted at /scratch and used as its TMPDIR, removed when the server is removed or the gateway stops")
 	runCmd.Flags().BoolVar(&options.Static, "static", options.Static, "Enable static mode (aka pre-started servers)")
 	runCmd.Flags().StringVar(&options.LogFilePath, "log", options.LogFilePath, "Path to log file for stderr output (relative or absolute)")
+	runCmd.Flags().IntVar(&options.LogMaxSize, "log-max-size", 100, "Maximum size, in megabytes, of the --log file before it's rotated (0 disables the rotation)")
+	runCmd.Flags().IntVar(&options.LogMaxBackups, "log-max-backups", 3, "Number of rotated --log files to keep")
+	runCmd.Flags().BoolVar(&options.LogFrames, "log-frames", options.LogFrames, "Log the JSON-RPC frames exchanged with the client and the stdio servers, truncated (verbose)")
+	runCmd.Flags().IntVar(&options.LogFrameSample, "log-frame-sample", 1, "With --log-frames, log only one frame out of this many, per connection")
+	runCmd.Flags().IntVar(&options.LogRateLimit, "log-rate-limit", logs.DefaultRateLimit.Messages, "Maximum number of messages each server can log per session in an interval, errors excepted (0 means no limit)")
+	runCmd.Flags().DurationVar(&options.LogRateInterval, "log-rate-interval", logs.DefaultRateLimit.Interval, "Interval over which the messages logged by servers are rate limited")
+	runCmd.Flags().DurationVar(&options.SessionIdleTimeout, "session-idle-timeout", options.SessionIdleTimeout, "Close the sse and streaming sessions of clients that have been idle for longer than this duration, freeing their cache and long-lived containers (0 means never)")
+	runCmd.Flags().StringVar(&options.Instructions, "instructions", options.Instructions, "Instructions sent to clients on initialize, before the gateway's usage hints and the instructions of the servers")
+	runCmd.Flags().StringVar(&options.Locale, "locale", options.Locale, "Default locale, like fr-FR, in which servers should write their results, for clients that don't set one")
+	runCmd.Flags().BoolVar(&options.CoerceArguments, "coerce-arguments", options.CoerceArguments, "Coerce the arguments of tool calls to the types of the tool's input schema: numbers and booleans sent as strings, single values instead of arrays, and null optional arguments")
+	runCmd.Flags().BoolVar(&options.MinifySchemas, "minify-schemas", options.MinifySchemas, "Minify the input schemas of the tools listed to clients, to use less context: cut long descriptions, drop long enums and examples. Clients can turn it on or off for their session")
+	runCmd.Flags().IntVar(&options.SchemaDescriptionMaxLength, "schema-description-max-length", gateway.DefaultSchemaDescriptionMaxLength, "Number of characters after which the descriptions of minified schemas are cut")
+	runCmd.Flags().IntVar(&options.SchemaEnumMaxValues, "schema-enum-max-values", gateway.DefaultSchemaEnumMaxValues, "Number of values over which the enums of minified schemas are dropped")
+	runCmd.Flags().IntVar(&options.InstructionsMaxSize, "instructions-max-size", gateway.DefaultInstructionsMaxSize, "Maximum size in bytes of the instructions sent to clients on initialize, longer ones are truncated (0 disables the instructions)")
+	runCmd.Flags().StringVar(&options.OAuthIssuer, "oauth-issuer", options.OAuthIssuer, "URL of an OAuth authorization server or OIDC provider whose access tokens clients use to authenticate to the streaming transport, advertised through the protected resource metadata")
+	runCmd.Flags().StringVar(&options.OAuthAudience, "oauth-audience", options.OAuthAudience, "Audience expected in the access tokens (defaults to --oauth-resource)")
+	runCmd.Flags().StringVar(&options.OAuthResource, "oauth-resource", options.OAuthResource, "Public URL of the gateway's MCP endpoint, as known by the authorization server (defaults to http://localhost:<port>/mcp)")
+	runCmd.Flags().StringSliceVar(&options.OAuthScopes, "oauth-scopes", options.OAuthScopes, "Scopes the access tokens must grant")
+	runCmd.Flags().StringVar(&options.OAuthGroupsClaim, "oauth-groups-claim", gateway.DefaultOAuthGroupsClaim, "Claim of the access tokens listing the groups of the client, matched against the allowedGroups of the --policy")
+	runCmd.Flags().IntVar(&options.PageSize, "page-size", options.PageSize, "Maximum number of tools, prompts, resources or resource templates per page of the lists sent to clients, which follow the cursors to get the next pages (default is 1000)")
+	runCmd.Flags().IntVar(&options.CompressionMinSize, "compression-min-size", gateway.DefaultCompressionMinSize, "Size in bytes from which the responses of the streaming transport are compressed with zstd or gzip, when the client accepts it (0 disables the compression)")
+	runCmd.Flags().IntVar(&options.MaxSessions, "max-sessions", options.MaxSessions, "Maximum number of simultaneous sse and streaming sessions, new sessions are rejected with 503 Service Unavailable over it (0 means no limit)")
 	runCmd.Flags().StringVar(&options.SessionName, "session", "", "Session name for loading and persisting configuration from ~/.docker/mcp/{SessionName}/")
+	runCmd.Flags().StringVar(&options.ToolConflictStrategy, "tool-conflict-strategy", gateway.ToolConflictPrefix, "How to handle tools with the same name exposed by different servers: prefix, first-wins or last-wins")
+	runCmd.Flags().Float64Var(&options.SessionBudget, "session-budget", options.SessionBudget, "Maximum total cost of the tool calls made by a client session, using the tool costs defined in the catalog (0 means no budget)")
+	runCmd.Flags().StringVar(&options.BudgetAction, "budget-action", gateway.BudgetActionReject, "What to do when a tool call exceeds the session budget: reject or warn")
+	runCmd.Flags().DurationVar(&options.CoalesceWindow, "coalesce-window", options.CoalesceWindow, "Make the identical tool calls of a client session, made within this duration of each other, share one execution and its result (0 disables it)")
+	runCmd.Flags().IntVar(&options.HTTPMaxConnsPerHost, "http-max-conns-per-host", options.HTTPMaxConnsPerHost, "Maximum number of connections to the host of a remote server (0 means no limit)")
+	runCmd.Flags().IntVar(&options.HTTPMaxIdleConnsPerHost, "http-max-idle-conns-per-host", options.HTTPMaxIdleConnsPerHost, "Number of idle connections kept alive to the host of a remote server (default is 16)")
+	runCmd.Flags().DurationVar(&options.HTTPIdleConnTimeout, "http-idle-conn-timeout", options.HTTPIdleConnTimeout, "How long idle connections to remote servers are kept alive (default is 90s)")
+	runCmd.Flags().StringVar(&options.AutoEnable, "auto-enable", gateway.AutoEnableOff, "Detect the type of the projects in the client's roots (package.json, go.mod, terraform files...) and suggest or enable matching servers from the catalog: off, suggest or enable")
+	runCmd.Flags().StringVar(&options.PolicyPath, "policy", options.PolicyPath, "Path to an access policy file restricting when, and by whom, servers can be called, and mapping authenticated clients to profiles")
+	runCmd.Flags().StringVar(&options.NotificationsPath, "notifications", options.NotificationsPath, "Path to a file configuring the webhooks notified of gateway events (start/stop, server crash loops, policy denials, OAuth failures)")
+	runCmd.Flags().BoolVar(&options.DiscoverLAN, "discover-lan", options.DiscoverLAN, "Look for MCP servers advertised with mDNS on the local network, and list them as untrusted in the results of mcp-find (with dynamic tools)")
+	runCmd.Flags().StringSliceVar(&options.DiscoverHosts, "discover-hosts", options.DiscoverHosts, "Hosts (host:port) of the local network probed for a /.well-known/mcp-gateway document, listed like --discover-lan servers")
+	runCmd.Flags().DurationVar(&options.ClientLeakThreshold, "client-leak-threshold", gateway.DefaultClientLeakThreshold, "Report the server clients acquired for longer than this duration without being released, along with the code that acquired them (0 disables the reports)")
+	runCmd.Flags().BoolVar(&options.ProfileStartup, "profile-startup", options.ProfileStartup, "Record how long each phase of the startup takes, log a breakdown once started and serve it, along with the pprof profiles, on the control API")
+	runCmd.Flags().StringVar(&options.ControlSocket, "control-socket", options.ControlSocket, "Path to a unix socket on which to expose the gateway's control API (used by `docker mcp gateway reload` and `rotate-secrets`)")
 
 	// Very experimental features
 	_ = runCmd.Flags().MarkHidden("log")
+	_ = runCmd.Flags().MarkHidden("log-max-size")
+	_ = runCmd.Flags().MarkHidden("log-max-backups")
 
 	cmd.AddCommand(runCmd)
+	cmd.AddCommand(reloadGatewayCommand())
+	cmd.AddCommand(rotateSecretsGatewayCommand())
+	cmd.AddCommand(overrideGatewayCommand())
+	cmd.AddCommand(eventsGatewayCommand())
+	cmd.AddCommand(debugGatewayCommand())
+	cmd.AddCommand(selfTestGatewayCommand(docker))
+	cmd.AddCommand(cleanupGatewayCommand(docker))
+	cmd.AddCommand(superviseGatewayCommand())
+	cmd.AddCommand(installServiceGatewayCommand())
+	cmd.AddCommand(uninstallServiceGatewayCommand())
+	cmd.AddCommand(serviceStatusGatewayCommand())
 
 	return cmd
 }
 
+func reloadGatewayCommand() *cobra.Command {
+	var serverName string
+	var controlSocket string
+	var gatewayURL string
+
+	cmd := &cobra.Command{
+		Use:   "reload",
+		Short: "Reload a single server of a running gateway",
+		Long: `Re-read the configuration and secrets of a single server and swap its capabilities
+in a running gateway, without touching the other servers.
+
+The gateway must either have been started with --control-socket or use the sse/streaming transport.`,
+		Example: `  docker mcp gateway reload --server github
+  docker mcp gateway reload --server github --url http://localhost:8811`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := newControlClient(controlSocket, gatewayURL)
+			if err != nil {
+				return err
+			}
+
+			response, err := client.ReloadServer(cmd.Context(), serverName)
+			if err != nil {
+				return fmt.Errorf("reloading server %s: %w", serverName, err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Reloaded server %s (%d tools)\n", response.Server, len(response.Tools))
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&serverName, "server", "", "Name of the server to reload")
+	flags.StringVar(&controlSocket, "control-socket", "", "Path to the gateway's control socket (default ~/.docker/mcp/gateway.sock)")
+	flags.StringVar(&gatewayURL, "url", "", "URL of a gateway running with the sse or streaming transport (uses MCP_GATEWAY_AUTH_TOKEN)")
+	_ = cmd.MarkFlagRequired("server")
+
+	return cmd
+}
+
+func rotateSecretsGatewayCommand() *cobra.Command {
+	var controlSocket string
+	var gatewayURL string
+
+	cmd := &cobra.Command{
+		Use:   "rotate-secrets",
+		Short: "Re-read the secrets of a running gateway's servers",
+		Long: `Re-read the secrets of the servers of a running gateway from their providers, and restart
+only the servers whose secret values changed. Sending SIGHUP to the gateway does the same.
+
+The gateway must either have been started with --control-socket or use the sse/streaming transport.`,
+		Example: `  docker mcp gateway rotate-secrets
+  kill -HUP <gateway pid>`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := newControlClient(controlSocket, gatewayURL)
+			if err != nil {
+				return err
+			}
+
+			response, err := client.RotateSecrets(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("rotating secrets: %w", err)
+			}
+
+			if len(response.Servers) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No secret changed")
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Restarted with rotated secrets: %s\n", strings.Join(response.Servers, ", "))
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&controlSocket, "control-socket", "", "Path to the gateway's control socket (default ~/.docker/mcp/gateway.sock)")
+	flags.StringVar(&gatewayURL, "url", "", "URL of a gateway running with the sse or streaming transport (uses MCP_GATEWAY_AUTH_TOKEN)")
+
+	return cmd
+}
+
+func overrideGatewayCommand() *cobra.Command {
+	var serverName string
+	var duration time.Duration
+	var reason string
+	var controlSocket string
+	var gatewayURL string
+
+	cmd := &cobra.Command{
+		Use:   "override",
+		Short: "Grant an on-call override to a server restricted by the gateway's access policy",
+		Long: `Allow calls to a server outside of the access windows defined in the gateway's policy, for a limited time.
+
+The policy must allow overrides for the server. The gateway must either have been started with --control-socket or use the sse/streaming transport.`,
+		Example: `  docker mcp gateway override --server postgres-prod --duration 2h --reason "incident 1234"`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := newControlClient(controlSocket, gatewayURL)
+			if err != nil {
+				return err
+			}
+
+			response, err := client.OverridePolicy(cmd.Context(), serverName, duration, reason)
+			if err != nil {
+				return fmt.Errorf("granting override to server %s: %w", serverName, err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Server %s can be called until %s\n", response.Server, response.Until.Local().Format(time.RFC1123))
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&serverName, "server", "", "Name of the server to unlock")
+	flags.DurationVar(&duration, "duration", time.Hour, "How long the override lasts")
+	flags.StringVar(&reason, "reason", "", "Why the override is needed (logged by the gateway)")
+	flags.StringVar(&controlSocket, "control-socket", "", "Path to the gateway's control socket (default ~/.docker/mcp/gateway.sock)")
+	flags.StringVar(&gatewayURL, "url", "", "URL of a gateway running with the sse or streaming transport (uses MCP_GATEWAY_AUTH_TOKEN)")
+	_ = cmd.MarkFlagRequired("server")
+
+	return cmd
+}
+
+func eventsGatewayCommand() *cobra.Command {
+	var controlSocket string
+	var gatewayURL string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Stream the events of a running gateway",
+		Long: `Stream the events of a running gateway: client sessions, servers added or removed, reloads, policy denials and errors.
+
+The gateway must either have been started with --control-socket or use the sse/streaming transport.`,
+		Example: `  docker mcp gateway events
+  docker mcp gateway events --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q, expected json", format)
+			}
+
+			client, err := newControlClient(controlSocket, gatewayURL)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			return client.Events(cmd.Context(), func(event notify.Event) {
+				if format == "json" {
+					_ = json.NewEncoder(out).Encode(event)
+					return
+				}
+				fmt.Fprintf(out, "%s %-20s %s\n", event.Time.Local().Format(time.TimeOnly), event.Type, event.Message)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&format, "format", "", "Output format (json)")
+	flags.StringVar(&controlSocket, "control-socket", "", "Path to the gateway's control socket (default ~/.docker/mcp/gateway.sock)")
+	flags.StringVar(&gatewayURL, "url", "", "URL of a gateway running with the sse or streaming transport (uses MCP_GATEWAY_AUTH_TOKEN)")
+
+	return cmd
+}
+
+func debugGatewayCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Inspect the memory, goroutines and clients of a running gateway",
+		Long: `Inspect the memory, goroutines and clients of a running gateway, to debug leaks without restarting it.
+
+The debug endpoints are served on the gateway's --control-socket. They are only served on the port of the
+sse/streaming transport when the gateway was started with --profile-startup.`,
+	}
+
+	cmd.AddCommand(memStatsDebugGatewayCommand())
+	cmd.AddCommand(goroutinesDebugGatewayCommand())
+	cmd.AddCommand(clientsDebugGatewayCommand())
+
+	return cmd
+}
+
+func memStatsDebugGatewayCommand() *cobra.Command {
+	var controlSocket string
+	var gatewayURL string
+	var format string
+	var gc bool
+
+	cmd := &cobra.Command{
+		Use:   "memstats",
+		Short: "Show a snapshot of the memory of a running gateway",
+		Example: `  docker mcp gateway debug memstats
+  docker mcp gateway debug memstats --gc --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q, expected json", format)
+			}
+
+			client, err := newControlClient(controlSocket, gatewayURL)
+			if err != nil {
+				return err
+			}
+
+			stats, err := client.MemStats(cmd.Context(), gc)
+			if err != nil {
+				return fmt.Errorf("reading memory stats: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if format == "json" {
+				encoder := json.NewEncoder(out)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(stats)
+			}
+
+			fmt.Fprintf(out, "Goroutines:     %d\n", stats.Goroutines)
+			fmt.Fprintf(out, "Sessions:       %d\n", stats.Sessions)
+			fmt.Fprintf(out, "Kept clients:   %d\n", stats.KeptClients)
+			fmt.Fprintf(out, "Held clients:   %d\n", stats.HeldClients)
+			fmt.Fprintf(out, "Heap in use:    %s (%d objects)\n", formatMiB(stats.HeapInuse), stats.HeapObjects)
+			fmt.Fprintf(out, "Heap allocated: %s\n", formatMiB(stats.HeapAlloc))
+			fmt.Fprintf(out, "Heap released:  %s\n", formatMiB(stats.HeapReleased))
+			fmt.Fprintf(out, "Stacks:         %s\n", formatMiB(stats.StackInuse))
+			fmt.Fprintf(out, "From the OS:    %s\n", formatMiB(stats.Sys))
+			fmt.Fprintf(out, "Next GC at:     %s\n", formatMiB(stats.NextGC))
+			fmt.Fprintf(out, "GC runs:        %d (%s paused)\n", stats.NumGC, stats.PauseTotal)
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&format, "format", "", "Output format (json)")
+	flags.BoolVar(&gc, "gc", false, "Run a garbage collection first, to only count the memory still in use")
+	flags.StringVar(&controlSocket, "control-socket", "", "Path to the gateway's control socket (default ~/.docker/mcp/gateway.sock)")
+	flags.StringVar(&gatewayURL, "url", "", "URL of a gateway running with the sse or streaming transport (uses MCP_GATEWAY_AUTH_TOKEN)")
+
+	return cmd
+}
+
+func goroutinesDebugGatewayCommand() *cobra.Command {
+	var controlSocket string
+	var gatewayURL string
+
+	cmd := &cobra.Command{
+		Use:     "goroutines",
+		Short:   "Dump the stacks of the goroutines of a running gateway",
+		Example: `  docker mcp gateway debug goroutines > goroutines.txt`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			client, err := newControlClient(controlSocket, gatewayURL)
+			if err != nil {
+				return err
+			}
+
+			if err := client.Goroutines(cmd.Context(), cmd.OutOrStdout()); err != nil {
+				return fmt.Errorf("dumping goroutines: %w", err)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&controlSocket, "control-socket", "", "Path to the gateway's control socket (default ~/.docker/mcp/gateway.sock)")
+	flags.StringVar(&gatewayURL, "url", "", "URL of a gateway running with the sse or streaming transport (uses MCP_GATEWAY_AUTH_TOKEN)")
+
+	return cmd
+}
+
+func clientsDebugGatewayCommand() *cobra.Command {
+	var controlSocket string
+	var gatewayURL string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "clients",
+		Short: "List the server clients acquired and not released yet by a running gateway",
+		Example: `  docker mcp gateway debug clients
+  docker mcp gateway debug clients --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q, expected json", format)
+			}
+
+			client, err := newControlClient(controlSocket, gatewayURL)
+			if err != nil {
+				return err
+			}
+
+			clients, err := client.HeldClients(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("listing clients: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if format == "json" {
+				encoder := json.NewEncoder(out)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(clients)
+			}
+
+			if len(clients) == 0 {
+				fmt.Fprintln(out, "No client held")
+				return nil
+			}
+			for _, held := range clients {
+				leaked := ""
+				if held.Leaked {
+					leaked = " (leaked)"
+				}
+				fmt.Fprintf(out, "%s held for %s by %s%s\n", held.Server, time.Since(held.Since).Round(time.Second), held.Caller, leaked)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&format, "format", "", "Output format (json)")
+	flags.StringVar(&controlSocket, "control-socket", "", "Path to the gateway's control socket (default ~/.docker/mcp/gateway.sock)")
+	flags.StringVar(&gatewayURL, "url", "", "URL of a gateway running with the sse or streaming transport (uses MCP_GATEWAY_AUTH_TOKEN)")
+
+	return cmd
+}
+
+func formatMiB(bytes uint64) string {
+	return fmt.Sprintf("%.1f MiB", float64(bytes)/(1024*1024))
+}
+
+func selfTestGatewayCommand(docker docker.Client) *cobra.Command {
+	var format string
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "self-test",
+		Short: "Check the capabilities of the gateway in the current environment",
+		Long: `Boot an ephemeral gateway with a built-in test server and exercise each capability a client relies on:
+listing and calling tools, prompts, resources, subscriptions, progress notifications, cancellation and reload.
+
+The test server runs in-process, so no image is pulled and no configured server is started.`,
+		Example: `  docker mcp gateway self-test
+  docker mcp gateway self-test --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q, expected json", format)
+			}
+			if !verbose {
+				log.SetLogWriter(io.Discard)
+				defer log.SetLogWriter(os.Stderr)
+			}
+
+			results, err := gateway.SelfTest(cmd.Context(), docker, gateway.Options{
+				Cpus:   1,
+				Memory: "2Gb",
+			})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if format == "json" {
+				encoder := json.NewEncoder(out)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(results); err != nil {
+					return err
+				}
+			}
+
+			var failed []string
+			for _, result := range results {
+				if !result.Passed {
+					failed = append(failed, result.Capability)
+				}
+				if format == "json" {
+					continue
+				}
+				if result.Passed {
+					fmt.Fprintf(out, "PASS %-14s %s\n", result.Capability, result.Duration.Round(time.Millisecond))
+				} else {
+					fmt.Fprintf(out, "FAIL %-14s %s\n", result.Capability, result.Error)
+				}
+			}
+
+			if len(failed) > 0 {
+				return fmt.Errorf("%d of %d checks failed: %s", len(failed), len(results), strings.Join(failed, ", "))
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&format, "format", "", "Output format (json)")
+	flags.BoolVar(&verbose, "verbose", false, "Show the logs of the gateway")
+
+	return cmd
+}
+
+func cleanupGatewayCommand(docker docker.Client) *cobra.Command {
+	var options gateway.CleanupOptions
+
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Remove the containers left behind by gateways",
+		Long: `Remove the containers of MCP Servers and proxies left behind by gateways that didn't stop cleanly.
+
+The containers started by a gateway are labelled with its instance id, host and process id. By default, the
+containers of the gateways that ran on this host and whose process is gone are removed.`,
+		Example: `  docker mcp gateway cleanup --dry-run
+  docker mcp gateway cleanup --gateway-id 3f9a1c2b7d4e
+  docker mcp gateway cleanup --all`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if options.All && options.GatewayID != "" {
+				return errors.New("cannot use --all with --gateway-id")
+			}
+
+			cleaned, err := gateway.Cleanup(cmd.Context(), docker, options)
+
+			out := cmd.OutOrStdout()
+			verb := "Removed"
+			if options.DryRun {
+				verb = "Would remove"
+			}
+			for _, ctr := range cleaned {
+				fmt.Fprintf(out, "%s %s (%s)\n", verb, ctr.Name, ctr.Server)
+			}
+			if len(cleaned) == 0 && err == nil {
+				fmt.Fprintln(out, "No containers to remove")
+			}
+
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.GatewayID, "gateway-id", "", "Remove the containers of this gateway instance, even if it's still running")
+	flags.BoolVar(&options.All, "all", false, "Remove the containers of all the gateways, even the running ones")
+	flags.BoolVar(&options.DryRun, "dry-run", false, "Only list the containers that would be removed")
+
+	return cmd
+}
+
+func newControlClient(controlSocket, gatewayURL string) (*gateway.ControlClient, error) {
+	if gatewayURL != "" {
+		return gateway.NewControlClientForURL(gatewayURL, os.Getenv("MCP_GATEWAY_AUTH_TOKEN")), nil
+	}
+
+	if controlSocket == "" {
+		path, err := gateway.DefaultControlSocketPath()
+		if err != nil {
+			return nil, err
+		}
+		controlSocket = path
+	}
+	return gateway.NewControlClient(controlSocket), nil
+}
+
 // getConfiguredCatalogPaths returns the file paths of all configured catalogs
 func getConfiguredCatalogPaths() []string {
 	cfg, err := catalog.ReadConfig()
@@ -4,48 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
-	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/registryadapter"
 )
 
-func runMcpregistryImport(ctx context.Context, serverURL string, servers *[]catalog.Server) error {
-	// Validate URL
-	parsedURL, err := url.Parse(serverURL)
+func runMcpregistryImport(ctx context.Context, serverRef string, servers *[]catalog.Server) error {
+	fmt.Printf("Fetching server definition from: %s\n\n", serverRef)
+	serverDetail, err := registryadapter.FetchServerDetail(ctx, serverRef)
 	if err != nil {
-		return fmt.Errorf("invalid URL: %w", err)
-	}
-
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		return fmt.Errorf("URL must use http or https protocol")
-	}
-
-	// Fetch the server definition
-	fmt.Printf("Fetching server definition from: %s\n\n", serverURL)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverURL, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to fetch server definition: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to fetch server definition: HTTP %d %s", resp.StatusCode, resp.Status)
-	}
-
-	// Parse the JSON response
-	var serverDetail oci.ServerDetail
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(&serverDetail); err != nil {
-		return fmt.Errorf("failed to parse server definition: %w", err)
+		return err
 	}
 
 	// Convert to catalog server
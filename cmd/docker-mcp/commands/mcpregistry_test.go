@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
 )
 
 func TestMcpregistryImportCommand(t *testing.T) {
@@ -123,3 +125,45 @@ func TestMcpregistryImportCommand_InvalidJSON(t *testing.T) {
 		t.Error("Expected error for invalid JSON, got none")
 	}
 }
+
+func TestMcpregistryImportCommand_ServerName(t *testing.T) {
+	var requestedPath string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.EscapedPath()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+  "server": {
+    "name": "com.example/weather",
+    "description": "Weather forecasts",
+    "version": "1.2.0",
+    "packages": [
+      {
+        "registryType": "oci",
+        "identifier": "example/weather",
+        "version": "1.2.0"
+      }
+    ]
+  }
+}`))
+		if err != nil {
+			t.Errorf("Failed to write response: %v", err)
+		}
+	}))
+	defer testServer.Close()
+
+	t.Setenv("DOCKER_MCP_REGISTRY_URL", testServer.URL)
+
+	var servers []catalog.Server
+	err := runMcpregistryImport(context.Background(), "com.example/weather@1.2.0", &servers)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if requestedPath != "/v0/servers/com.example%2Fweather/versions/1.2.0" {
+		t.Errorf("Unexpected request path: %s", requestedPath)
+	}
+	if len(servers) != 1 || servers[0].Image != "example/weather:1.2.0" {
+		t.Errorf("Unexpected servers: %+v", servers)
+	}
+}
@@ -36,18 +36,20 @@ func lsOauthCommand() *cobra.Command {
 
 func authorizeOauthCommand() *cobra.Command {
 	var opts struct {
-		Scopes string
+		Scopes  string
+		Profile string
 	}
 	cmd := &cobra.Command{
 		Use:   "authorize <app>",
 		Short: "Authorize the specified OAuth app.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return oauth.Authorize(cmd.Context(), args[0], opts.Scopes)
+			return oauth.Authorize(cmd.Context(), args[0], opts.Scopes, opts.Profile)
 		},
 	}
 	flags := cmd.Flags()
 	flags.StringVar(&opts.Scopes, "scopes", "", "OAuth scopes to request (space-separated)")
+	flags.StringVar(&opts.Profile, "profile", "", "Profile whose OAuth scopes to request for the server, instead of the catalog's")
 	return cmd
 }
 
@@ -56,6 +58,10 @@ func revokeOauthCommand() *cobra.Command {
 		Use:   "revoke <app>",
 		Args:  cobra.ExactArgs(1),
 		Short: "Revoke the specified OAuth app.",
+		Long: `Revoke the specified OAuth app.
+
+A gateway running with the default control socket stops exposing the app's server. With Docker Desktop,
+the server comes back when it's authorized again. Otherwise, add it again with mcp-add or restart the gateway.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return oauth.Revoke(cmd.Context(), args[0])
 		},
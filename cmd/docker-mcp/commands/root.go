@@ -88,14 +88,17 @@ func Root(ctx context.Context, cwd string, dockerCli command.Cli) *cobra.Command
 		cmd.AddCommand(workingSetCommand())
 		cmd.AddCommand(catalogNextCommand())
 	}
-	cmd.AddCommand(catalogCommand(dockerCli))
+	cmd.AddCommand(backupCommand(dockerClient))
+	cmd.AddCommand(catalogCommand(dockerClient, dockerCli))
 	cmd.AddCommand(clientCommand(dockerCli, cwd))
 	cmd.AddCommand(configCommand(dockerClient))
+	cmd.AddCommand(devCommand(dockerClient))
 	cmd.AddCommand(featureCommand(dockerCli))
 	cmd.AddCommand(gatewayCommand(dockerClient, dockerCli))
 	cmd.AddCommand(oauthCommand())
 	cmd.AddCommand(policyCommand())
 	cmd.AddCommand(registryCommand())
+	cmd.AddCommand(runCommand())
 	cmd.AddCommand(secretCommand(dockerClient))
 	cmd.AddCommand(serverCommand(dockerClient, dockerCli))
 	cmd.AddCommand(toolsCommand(dockerClient, dockerCli))
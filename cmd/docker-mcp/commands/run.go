@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/tools"
+)
+
+const runScriptExample = `  docker mcp run smoke-test.yaml
+  docker mcp run smoke-test.yaml --profile dev-tools --var topic=docker
+
+A script is a sequence of tool calls. Arguments can use variables and the results of the previous steps:
+
+  profile: dev-tools
+  vars:
+    topic: docker
+  steps:
+    - name: search
+      tool: search
+      arguments:
+        query: "{{.vars.topic}}"
+    - tool: fetch_content
+      arguments:
+        url: "{{(index .steps.search.structured.results 0).url}}"`
+
+func runCommand() *cobra.Command {
+	var opts tools.RunOptions
+	cmd := &cobra.Command{
+		Use:     "run <script.yaml>",
+		Short:   "Run a sequence of tool calls",
+		Example: runScriptExample,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return tools.Run(cmd.Context(), args[0], opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Profile, "profile", "", "Profile to run the gateway with (overrides the script's profile)")
+	flags.StringArrayVar(&opts.Vars, "var", nil, "Variable used in the arguments as {{.vars.name}}, as name=value (overrides the script's vars)")
+	flags.StringVar(&opts.Session, "session", "", "URL of a running gateway to call the tools through, instead of starting one (uses MCP_GATEWAY_AUTH_TOKEN)")
+	flags.StringSliceVar(&opts.GatewayArgs, "gateway-arg", nil, "Additional arguments passed to the gateway")
+	flags.BoolVar(&opts.Verbose, "verbose", false, "Verbose output")
+
+	return cmd
+}
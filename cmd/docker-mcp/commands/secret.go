@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/secret-management/secret"
 	"github.com/docker/mcp-gateway/pkg/docker"
@@ -29,27 +30,47 @@ func secretCommand(docker docker.Client) *cobra.Command {
 		Short:   "Manage secrets",
 		Example: strings.Trim(setSecretExample, "\n"),
 	}
-	cmd.AddCommand(rmSecretCommand())
-	cmd.AddCommand(listSecretCommand())
-	cmd.AddCommand(setSecretCommand())
+	cmd.AddCommand(rmSecretCommand(docker))
+	cmd.AddCommand(listSecretCommand(docker))
+	cmd.AddCommand(setSecretCommand(docker))
+	cmd.AddCommand(getSecretCommand(docker))
 	cmd.AddCommand(exportSecretCommand(docker))
 	return cmd
 }
 
-func rmSecretCommand() *cobra.Command {
+// addStoreFlags adds the flags that select the provider of the secrets.
+func addStoreFlags(flags *pflag.FlagSet, opts *secret.StoreOpts) {
+	flags.StringVar(&opts.Provider, "provider", "", "Provider of the secrets, or entry of the profile's secrets. Supported: "+strings.Join(secret.Providers, ", ")+", oauth/<provider>")
+	flags.StringVar(&opts.Profile, "profile", "", "Use the secret provider of a profile")
+}
+
+func openStore(cmd *cobra.Command, docker docker.Client, opts secret.StoreOpts) (secret.Store, error) {
+	if opts.Profile == "" && !secret.IsValidStoreProvider(opts.Provider) {
+		return nil, fmt.Errorf("invalid provider: %s", opts.Provider)
+	}
+	return secret.OpenStore(cmd.Context(), docker, opts)
+}
+
+func rmSecretCommand(docker docker.Client) *cobra.Command {
 	var opts secret.RmOpts
+	var storeOpts secret.StoreOpts
 	cmd := &cobra.Command{
 		Use:   "rm name1 name2 ...",
-		Short: "Remove secrets from Docker Desktop's secret store",
+		Short: "Remove secrets from Docker Desktop's secret store, or from another provider",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := validateRmArgs(args, opts); err != nil {
 				return err
 			}
-			return secret.Remove(cmd.Context(), args, opts)
+			store, err := openStore(cmd, docker, storeOpts)
+			if err != nil {
+				return err
+			}
+			return secret.Remove(cmd.Context(), store, args, opts)
 		},
 	}
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.All, "all", false, "Remove all secrets")
+	addStoreFlags(flags, &storeOpts)
 	return cmd
 }
 
@@ -60,32 +81,59 @@ func validateRmArgs(args []string, opts secret.RmOpts) error {
 	return nil
 }
 
-func listSecretCommand() *cobra.Command {
+func listSecretCommand(docker docker.Client) *cobra.Command {
 	var opts secret.ListOptions
+	var storeOpts secret.StoreOpts
 	cmd := &cobra.Command{
 		Use:   "ls",
-		Short: "List all secret names in Docker Desktop's secret store",
+		Short: "List all secret names in Docker Desktop's secret store, or in another provider",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return secret.List(cmd.Context(), opts)
+			store, err := openStore(cmd, docker, storeOpts)
+			if err != nil {
+				return err
+			}
+			return secret.List(cmd.Context(), store, opts)
 		},
 	}
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.JSON, "json", false, "Print as JSON.")
+	addStoreFlags(flags, &storeOpts)
+	return cmd
+}
+
+func getSecretCommand(docker docker.Client) *cobra.Command {
+	var storeOpts secret.StoreOpts
+	cmd := &cobra.Command{
+		Use:   "get name",
+		Short: "Print the value of a secret from Docker Desktop's secret store, or from another provider",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openStore(cmd, docker, storeOpts)
+			if err != nil {
+				return err
+			}
+			return secret.Get(cmd.Context(), store, args[0])
+		},
+	}
+	addStoreFlags(cmd.Flags(), &storeOpts)
 	return cmd
 }
 
-func setSecretCommand() *cobra.Command {
+func setSecretCommand(docker docker.Client) *cobra.Command {
 	opts := &secret.SetOpts{}
+	var storeOpts secret.StoreOpts
 	cmd := &cobra.Command{
 		Use:     "set key[=value]",
-		Short:   "Set a secret in Docker Desktop's secret store",
+		Short:   "Set a secret in Docker Desktop's secret store, or in another provider",
 		Example: strings.Trim(setSecretExample, "\n"),
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if !secret.IsValidProvider(opts.Provider) {
-				return fmt.Errorf("invalid provider: %s", opts.Provider)
+			store, err := openStore(cmd, docker, storeOpts)
+			if err != nil {
+				return err
 			}
+			opts.Provider = store.Name()
 			var s secret.Secret
 			if isNotImplicitReadFromStdinSyntax(args, *opts) {
 				va, err := secret.ParseArg(args[0], *opts)
@@ -100,16 +148,15 @@ func setSecretCommand() *cobra.Command {
 				}
 				s = *val
 			}
-			return secret.Set(cmd.Context(), s, *opts)
+			return secret.Set(cmd.Context(), store, s)
 		},
 	}
-	flags := cmd.Flags()
-	flags.StringVar(&opts.Provider, "provider", "", "Supported: credstore, oauth/<provider>")
+	addStoreFlags(cmd.Flags(), &storeOpts)
 	return cmd
 }
 
 func isNotImplicitReadFromStdinSyntax(args []string, opts secret.SetOpts) bool {
-	return strings.Contains(args[0], "=") || len(args) > 1 || opts.Provider != ""
+	return strings.Contains(args[0], "=") || len(args) > 1 || (opts.Provider != "" && opts.Provider != secret.DockerDesktop)
 }
 
 func exportSecretCommand(docker docker.Client) *cobra.Command {
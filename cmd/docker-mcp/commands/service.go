@@ -0,0 +1,236 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/service"
+)
+
+func installServiceGatewayCommand() *cobra.Command {
+	var opts struct {
+		Name      string
+		Profile   string
+		Transport string
+		Port      int
+		System    bool
+	}
+
+	cmd := &cobra.Command{
+		Use:   "install-service [--profile <profile-id>] [--transport streaming] [--port 8811] [--system] [-- gateway run flags]",
+		Short: "Run the gateway as a systemd or launchd service",
+		Long: `Write a systemd unit (Linux) or a launchd property list (macOS) that runs the gateway, then enable and start it.
+The service is restarted when it fails.
+
+User services start when the user logs in. With --system, the service starts at boot, as the user installing it:
+run the command with sudo. On Linux, 'loginctl enable-linger' starts user services at boot too.
+
+The flags after -- are passed to 'docker mcp gateway run'. Unless MCP_GATEWAY_AUTH_TOKEN is set, a token is generated
+and stored in the service definition, which only its owner can read.`,
+		Example: `  docker mcp gateway install-service --profile dev-tools
+  docker mcp gateway install-service --name gateway-ci --profile ci --port 8821 -- --verbose
+  sudo docker mcp gateway install-service --system --profile dev-tools`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Transport = strings.ToLower(opts.Transport)
+			if !isNetworkTransport(opts.Transport) {
+				return fmt.Errorf("the service must use the sse or streaming transport, not %s", opts.Transport)
+			}
+			if opts.Port <= 0 {
+				return fmt.Errorf("invalid --port %d", opts.Port)
+			}
+
+			manager, err := service.NewManager(opts.System)
+			if err != nil {
+				return err
+			}
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("finding the gateway executable: %w", err)
+			}
+
+			runArgs := []string{"--transport", opts.Transport, "--port", strconv.Itoa(opts.Port)}
+			if opts.Profile != "" {
+				runArgs = append(runArgs, "--profile", opts.Profile)
+			}
+			spec := service.Spec{
+				Name:       opts.Name,
+				Executable: executable,
+				Args:       gatewayRunArgs(true, append(runArgs, args...)),
+				Env: map[string]string{
+					// Service managers start services with a minimal PATH, where the docker CLI might not be
+					"PATH": os.Getenv("PATH"),
+				},
+			}
+
+			token := os.Getenv("MCP_GATEWAY_AUTH_TOKEN")
+			if token == "" {
+				if token, err = gateway.GenerateAuthToken(); err != nil {
+					return err
+				}
+			}
+			spec.Env["MCP_GATEWAY_AUTH_TOKEN"] = token
+
+			if opts.System {
+				username, home, err := serviceUser()
+				if err != nil {
+					return err
+				}
+				spec.User = username
+				spec.Env["HOME"] = home
+			}
+
+			path, err := manager.Install(cmd.Context(), spec)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Installed service %s: %s\n", opts.Name, path)
+			fmt.Fprintf(out, "Gateway URL: http://localhost:%d%s\n", opts.Port, gatewayEndpoint(opts.Transport))
+			fmt.Fprintf(out, "Use Bearer token: Authorization: Bearer %s\n", token)
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Name, "name", service.DefaultName, "Name of the service, to run several gateways")
+	flags.StringVar(&opts.Profile, "profile", "", "Profile of the gateway")
+	flags.StringVar(&opts.Transport, "transport", "streaming", "sse or streaming")
+	flags.IntVar(&opts.Port, "port", 8811, "TCP port the gateway listens on")
+	flags.BoolVar(&opts.System, "system", false, "Install a system service, started at boot, instead of a user service")
+
+	return cmd
+}
+
+func uninstallServiceGatewayCommand() *cobra.Command {
+	var name string
+	var system bool
+
+	cmd := &cobra.Command{
+		Use:   "uninstall-service [--name <name>] [--system]",
+		Short: "Stop the gateway service and remove it",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			manager, err := service.NewManager(system)
+			if err != nil {
+				return err
+			}
+			if err := manager.Uninstall(cmd.Context(), name); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Uninstalled service %s\n", name)
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&name, "name", service.DefaultName, "Name of the service")
+	flags.BoolVar(&system, "system", false, "Uninstall a system service instead of a user service")
+
+	return cmd
+}
+
+func serviceStatusGatewayCommand() *cobra.Command {
+	var name string
+	var system bool
+
+	cmd := &cobra.Command{
+		Use:   "service-status [--name <name>] [--system]",
+		Short: "Show the state of the gateway service and whether the gateway is healthy",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			manager, err := service.NewManager(system)
+			if err != nil {
+				return err
+			}
+			status, err := manager.Status(cmd.Context(), name)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if !status.Installed {
+				fmt.Fprintf(out, "Service %s is not installed\n", name)
+				return nil
+			}
+			fmt.Fprintf(out, "Service: %s\n", name)
+			fmt.Fprintf(out, "Definition: %s\n", status.Path)
+			fmt.Fprintf(out, "State: %s\n", status.State)
+			if status.Port != 0 {
+				fmt.Fprintf(out, "Health: %s\n", gatewayHealth(cmd.Context(), status.Port))
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&name, "name", service.DefaultName, "Name of the service")
+	flags.BoolVar(&system, "system", false, "Show a system service instead of a user service")
+
+	return cmd
+}
+
+// isNetworkTransport tells whether a transport serves clients over the network, as opposed to stdio.
+func isNetworkTransport(transport string) bool {
+	switch transport {
+	case "sse", "http", "streamable", "streaming", "streamable-http":
+		return true
+	}
+	return false
+}
+
+func gatewayEndpoint(transport string) string {
+	if transport == "sse" {
+		return "/sse"
+	}
+	return "/mcp"
+}
+
+// serviceUser is the user who runs a system service: the one who ran sudo, if any.
+func serviceUser() (string, string, error) {
+	current, err := user.Current()
+	if err != nil {
+		return "", "", err
+	}
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" && current.Uid == "0" {
+		if current, err = user.Lookup(sudoUser); err != nil {
+			return "", "", err
+		}
+	}
+	return current.Username, current.HomeDir, nil
+}
+
+// gatewayHealth describes the health reported by a gateway listening on a port.
+func gatewayHealth(ctx context.Context, port int) string {
+	url := fmt.Sprintf("http://localhost:%d/health", port)
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err.Error()
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Sprintf("unreachable (%s)", url)
+	}
+	defer resp.Body.Close()
+
+	var health struct {
+		Healthy bool `json:"healthy"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil || !health.Healthy {
+		return fmt.Sprintf("unhealthy (%s)", url)
+	}
+	return fmt.Sprintf("healthy (%s)", url)
+}
@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli-plugins/plugin"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/logs"
+	"github.com/docker/mcp-gateway/pkg/supervisor"
+)
+
+func superviseGatewayCommand() *cobra.Command {
+	var opts struct {
+		HealthPort     int
+		LogFilePath    string
+		LogMaxSize     int
+		LogMaxBackups  int
+		MaxRestarts    int
+		InitialBackoff time.Duration
+		MaxBackoff     time.Duration
+	}
+
+	cmd := &cobra.Command{
+		Use:   "supervise [flags] -- [gateway run flags]",
+		Short: "Run the gateway and restart it when it crashes",
+		Long: `Run the gateway as a child process and restart it when it crashes, waiting longer after each consecutive crash.
+Useful on hosts where no systemd unit or container restart policy is prepared.
+
+The flags after -- are passed to 'docker mcp gateway run'. The gateway must use the sse or streaming transport,
+since a restart would break the session of a stdio client. Unless MCP_GATEWAY_AUTH_TOKEN is set, the supervisor
+generates one token, so that clients don't need a new one after each restart.
+
+With --health-port, the supervisor serves a /health endpoint that combines its state with the health of the gateway,
+even while the gateway is restarting.`,
+		Example: `  docker mcp gateway supervise --health-port 8812 -- --transport streaming --port 8811
+  docker mcp gateway supervise --log /var/log/mcp-gateway.log --max-restarts 10 -- --transport sse --port 8811 --profile dev`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			transport, port, err := parseSupervisedArgs(args)
+			if err != nil {
+				return err
+			}
+			if !isNetworkTransport(transport) {
+				return fmt.Errorf("the supervised gateway must use the sse or streaming transport, not %s", transport)
+			}
+			if port == 0 {
+				return errors.New("the supervised gateway needs a --port")
+			}
+			if opts.HealthPort == port {
+				return errors.New("--health-port must differ from the --port of the gateway")
+			}
+
+			var output io.Writer = os.Stderr
+			if opts.LogFilePath != "" {
+				logFile, err := logs.OpenRotatingFile(opts.LogFilePath, int64(opts.LogMaxSize)*1024*1024, opts.LogMaxBackups)
+				if err != nil {
+					return fmt.Errorf("failed to open log file %s: %w", opts.LogFilePath, err)
+				}
+				defer logFile.Close()
+
+				output = io.MultiWriter(os.Stderr, logFile)
+				log.SetLogWriter(output)
+				defer log.SetLogWriter(os.Stderr)
+			}
+
+			var env []string
+			if os.Getenv("MCP_GATEWAY_AUTH_TOKEN") == "" {
+				token, err := gateway.GenerateAuthToken()
+				if err != nil {
+					return err
+				}
+				env = append(env, "MCP_GATEWAY_AUTH_TOKEN="+token)
+				log.Logf("> Use Bearer token: Authorization: Bearer %s", token)
+			}
+
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("finding the gateway executable: %w", err)
+			}
+
+			s := supervisor.New(supervisor.Options{
+				Command:          append([]string{executable}, gatewayRunArgs(plugin.RunningStandalone(), args)...),
+				Env:              env,
+				Output:           output,
+				GatewayHealthURL: fmt.Sprintf("http://localhost:%d/health", port),
+				InitialBackoff:   opts.InitialBackoff,
+				MaxBackoff:       opts.MaxBackoff,
+				MaxRestarts:      opts.MaxRestarts,
+			})
+
+			ctx := cmd.Context()
+			if opts.HealthPort != 0 {
+				stop, err := serveSupervisorHealth(ctx, opts.HealthPort, s.HealthHandler())
+				if err != nil {
+					return err
+				}
+				defer stop()
+			}
+
+			return s.Run(ctx)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&opts.HealthPort, "health-port", 0, "TCP port on which to serve the combined /health endpoint of the supervisor and the gateway (0 disables it)")
+	flags.StringVar(&opts.LogFilePath, "log", "", "Path to a log file for the output of the supervisor and the gateway")
+	flags.IntVar(&opts.LogMaxSize, "log-max-size", 100, "Maximum size, in megabytes, of the --log file before it's rotated (0 disables the rotation)")
+	flags.IntVar(&opts.LogMaxBackups, "log-max-backups", 3, "Number of rotated --log files to keep")
+	flags.IntVar(&opts.MaxRestarts, "max-restarts", 0, "Give up after the gateway crashed this many times in a row (0 means never)")
+	flags.DurationVar(&opts.InitialBackoff, "backoff", supervisor.DefaultInitialBackoff, "Delay before restarting the gateway after a crash, doubled after each consecutive crash")
+	flags.DurationVar(&opts.MaxBackoff, "max-backoff", supervisor.DefaultMaxBackoff, "Maximum delay before restarting the gateway")
+
+	return cmd
+}
+
+// parseSupervisedArgs reads the transport and the port from the flags of the supervised gateway.
+func parseSupervisedArgs(args []string) (string, int, error) {
+	flags := pflag.NewFlagSet("run", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.SetOutput(io.Discard)
+	transport := flags.String("transport", "stdio", "")
+	port := flags.Int("port", 0, "")
+	if err := flags.Parse(args); err != nil {
+		return "", 0, fmt.Errorf("invalid gateway flags: %w", err)
+	}
+	return strings.ToLower(*transport), *port, nil
+}
+
+// gatewayRunArgs are the arguments with which the executable runs the gateway. As a Docker CLI plugin,
+// the executable expects the name of the plugin first.
+func gatewayRunArgs(standalone bool, args []string) []string {
+	runArgs := []string{"gateway", "run"}
+	if !standalone {
+		runArgs = append([]string{"mcp"}, runArgs...)
+	}
+	return append(runArgs, args...)
+}
+
+func serveSupervisorHealth(ctx context.Context, port int, handler http.Handler) (func(), error) {
+	ln, err := (&net.ListenConfig{}).Listen(ctx, "tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return nil, fmt.Errorf("listening on port %d: %w", port, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/health", handler)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Log("  ! Health endpoint stopped:", err)
+		}
+	}()
+	log.Logf("> Supervisor health endpoint: http://localhost:%d/health", port)
+
+	return func() { _ = server.Close() }, nil
+}
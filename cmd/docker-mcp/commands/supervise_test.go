@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSupervisedArgs(t *testing.T) {
+	transport, port, err := parseSupervisedArgs([]string{"--profile", "dev", "--transport=Streaming", "--port", "8811", "--verbose"})
+	require.NoError(t, err)
+	assert.Equal(t, "streaming", transport)
+	assert.Equal(t, 8811, port)
+
+	transport, port, err = parseSupervisedArgs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "stdio", transport)
+	assert.Zero(t, port)
+
+	_, _, err = parseSupervisedArgs([]string{"--port", "http"})
+	require.Error(t, err)
+}
+
+func TestGatewayRunArgs(t *testing.T) {
+	assert.Equal(t, []string{"gateway", "run", "--port", "8811"}, gatewayRunArgs(true, []string{"--port", "8811"}))
+	assert.Equal(t, []string{"mcp", "gateway", "run", "--port", "8811"}, gatewayRunArgs(false, []string{"--port", "8811"}))
+}
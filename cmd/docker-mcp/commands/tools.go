@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"strings"
+
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
@@ -52,13 +54,24 @@ func toolsCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
 			return tools.List(cmd.Context(), dockerCli, version, gatewayArgs, verbose, "inspect", args[0], format)
 		},
 	})
-	cmd.AddCommand(&cobra.Command{
-		Use:   "call",
+	var callOpts tools.CallOptions
+	callCmd := &cobra.Command{
+		Use:   "call <tool> [key=value ...]",
 		Short: "Call a tool",
+		Example: `  docker mcp tools call search query=docker
+  docker mcp tools call search --server duckduckgo --args-file args.json
+  echo '{"query": "{{.topic}}"}' | docker mcp tools call search --args-file - --var topic=docker
+  docker mcp tools call search query=docker --output structured --session http://localhost:8811/mcp`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return tools.Call(cmd.Context(), version, gatewayArgs, verbose, args)
+			return tools.Call(cmd.Context(), version, gatewayArgs, verbose, args, callOpts)
 		},
-	})
+	}
+	callCmd.Flags().StringVar(&callOpts.ArgsFile, "args-file", "", "JSON file with the arguments of the tool, - to read them from stdin (key=value arguments take precedence)")
+	callCmd.Flags().StringArrayVar(&callOpts.Vars, "var", nil, "Variable used in the arguments as {{.name}}, as name=value")
+	callCmd.Flags().StringVar(&callOpts.Output, "output", tools.OutputText, "Output format ("+strings.Join(tools.Outputs, "|")+"). Tool errors exit with code 2")
+	callCmd.Flags().StringVar(&callOpts.Server, "server", "", "Only start the server that provides the tool")
+	callCmd.Flags().StringVar(&callOpts.Session, "session", "", "URL of a running gateway to call the tool through, instead of starting one (uses MCP_GATEWAY_AUTH_TOKEN)")
+	cmd.AddCommand(callCmd)
 
 	var enableServerName string
 	enableCmd := &cobra.Command{
@@ -24,16 +24,22 @@ func workingSetCommand() *cobra.Command {
 
 	cmd.AddCommand(exportWorkingSetCommand())
 	cmd.AddCommand(importWorkingSetCommand())
+	cmd.AddCommand(importComposeWorkingSetCommand())
+	cmd.AddCommand(validateWorkingSetCommand())
+	cmd.AddCommand(schemaWorkingSetCommand())
 	cmd.AddCommand(showWorkingSetCommand())
 	cmd.AddCommand(listWorkingSetsCommand())
 	cmd.AddCommand(pushWorkingSetCommand())
 	cmd.AddCommand(pullWorkingSetCommand())
 	cmd.AddCommand(createWorkingSetCommand(cfg))
+	cmd.AddCommand(cloneWorkingSetCommand())
 	cmd.AddCommand(removeWorkingSetCommand())
 	cmd.AddCommand(workingsetServerCommand())
 	cmd.AddCommand(configWorkingSetCommand())
 	cmd.AddCommand(toolsWorkingSetCommand())
 	cmd.AddCommand(manualInstructionsCommand())
+	cmd.AddCommand(updateWorkingSetCommand())
+	cmd.AddCommand(dockerContextWorkingSetCommand())
 	return cmd
 }
 
@@ -77,9 +83,11 @@ func toolsWorkingSetCommand() *cobra.Command {
 	var disable []string
 	var enableAll []string
 	var disableAll []string
+	var enableToolsets []string
+	var disableToolsets []string
 
 	cmd := &cobra.Command{
-		Use:   "tools <profile-id> [--enable <tool> ...] [--disable <tool> ...] [--enable-all <server> ...] [--disable-all <server> ...]",
+		Use:   "tools <profile-id> [--enable <tool> ...] [--disable <tool> ...] [--enable-all <server> ...] [--disable-all <server> ...] [--enable-toolset <toolset> ...] [--disable-toolset <toolset> ...]",
 		Short: "Manage tool allowlist for servers in a profile",
 		Long: `Manage the tool allowlist for servers in a profile.
 Tools are specified using dot notation: <serverName>.<toolName>
@@ -88,6 +96,7 @@ Use --enable to enable specific tools for a server (can be specified multiple ti
 Use --disable to disable specific tools for a server (can be specified multiple times).
 Use --enable-all to enable all tools for a server (can be specified multiple times).
 Use --disable-all to disable all tools for a server (can be specified multiple times).
+Use --enable-toolset and --disable-toolset to enable or disable the groups of tools declared by a server: <serverName>.<toolset>
 
 To view enabled tools, use: docker mcp profile show <profile-id>`,
 		Example: `  # Enable specific tools for a server
@@ -105,6 +114,9 @@ To view enabled tools, use: docker mcp profile show <profile-id>`,
   # Disable all tools for a server
   docker mcp profile tools my-profile --disable-all github
 
+  # Disable a group of tools of a server
+  docker mcp profile tools my-profile --disable-toolset github.actions
+
   # View all enabled tools in the profile
   docker mcp profile show my-profile`,
 		Args: cobra.ExactArgs(1),
@@ -113,6 +125,14 @@ To view enabled tools, use: docker mcp profile show <profile-id>`,
 			if err != nil {
 				return err
 			}
+			if len(enableToolsets) > 0 || len(disableToolsets) > 0 {
+				if err := workingset.UpdateToolsets(cmd.Context(), dao, args[0], enableToolsets, disableToolsets); err != nil {
+					return err
+				}
+				if len(enable) == 0 && len(disable) == 0 && len(enableAll) == 0 && len(disableAll) == 0 {
+					return nil
+				}
+			}
 			return workingset.UpdateTools(cmd.Context(), dao, args[0], enable, disable, enableAll, disableAll)
 		},
 	}
@@ -122,6 +142,8 @@ To view enabled tools, use: docker mcp profile show <profile-id>`,
 	flags.StringArrayVar(&disable, "disable", []string{}, "Disable specific tools: <serverName>.<toolName> (repeatable)")
 	flags.StringArrayVar(&enableAll, "enable-all", []string{}, "Enable all tools for a server: <serverName> (repeatable)")
 	flags.StringArrayVar(&disableAll, "disable-all", []string{}, "Disable all tools for a server: <serverName> (repeatable)")
+	flags.StringArrayVar(&enableToolsets, "enable-toolset", []string{}, "Enable a group of tools: <serverName>.<toolset> (repeatable)")
+	flags.StringArrayVar(&disableToolsets, "disable-toolset", []string{}, "Disable a group of tools: <serverName>.<toolset> (repeatable)")
 
 	return cmd
 }
@@ -176,6 +198,36 @@ Profiles are decoupled from catalogs. Servers can be:
 	return cmd
 }
 
+func cloneWorkingSetCommand() *cobra.Command {
+	var id string
+
+	cmd := &cobra.Command{
+		Use:   "clone <profile-id> <new-name> [--id <id>]",
+		Short: "Copy a profile into a new profile",
+		Long: `Copy a profile, with its servers, tools, config and secret mappings, into a new profile.
+Use it to branch a personal profile from a team baseline before experimenting.
+The new profile isn't bound to the Docker context of the original one.`,
+		Example: `  # Branch a personal profile from a team baseline
+  docker mcp profile clone team-baseline my-experiment
+
+  # Choose the ID of the new profile
+  docker mcp profile clone team-baseline "My Experiment" --id my-experiment`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return workingset.Clone(cmd.Context(), dao, args[0], args[1], id)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&id, "id", "", "ID of the new profile (defaults to a slugified version of the name)")
+
+	return cmd
+}
+
 func listWorkingSetsCommand() *cobra.Command {
 	format := string(workingset.OutputFormatHumanReadable)
 
@@ -277,8 +329,10 @@ func exportWorkingSetCommand() *cobra.Command {
 }
 
 func importWorkingSetCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "import <input-file>",
+	var keepExisting bool
+
+	cmd := &cobra.Command{
+		Use:   "import <input-file> [--keep-existing]",
 		Short: "Import profile from file",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -287,7 +341,81 @@ func importWorkingSetCommand() *cobra.Command {
 				return err
 			}
 			ociService := oci.NewService()
-			return workingset.Import(cmd.Context(), dao, ociService, args[0])
+			return workingset.Import(cmd.Context(), dao, ociService, args[0], keepExisting)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&keepExisting, "keep-existing", false, "Keep a profile with the same ID and import the file under a new ID instead of updating it")
+
+	return cmd
+}
+
+func importComposeWorkingSetCommand() *cobra.Command {
+	var opts struct {
+		ID   string
+		Name string
+	}
+
+	cmd := &cobra.Command{
+		Use:   "import-compose <compose-file> [--id <id>] [--name <name>]",
+		Short: "Import the MCP servers of a compose file into a profile",
+		Long: `Import the services of a compose file labeled com.docker.mcp.server=true into a profile, as image servers.
+Their image, environment, volumes, command, user and resource limits are kept.
+The profile is created if it doesn't exist. Servers that are already in the profile are updated, keeping their config, tools and secrets.`,
+		Example: `  # Create a profile named after the compose project
+  docker mcp profile import-compose compose.yaml
+
+  # Import the servers into an existing profile
+  docker mcp profile import-compose compose.yaml --id dev-tools`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return workingset.ImportCompose(cmd.Context(), dao, args[0], opts.ID, opts.Name)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.ID, "id", "", "ID of the profile to import the servers into, created if it doesn't exist")
+	flags.StringVar(&opts.Name, "name", "", "Name of the profile, when it's created (defaults to the name of the compose project)")
+
+	return cmd
+}
+
+func validateWorkingSetCommand() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "validate --file <file>",
+		Short: "Validate a profile file offline",
+		Long: `Validate a YAML or JSON profile file, eg. an exported profile that was edited by hand,
+against the profile JSON Schema (see docker mcp profile schema) and the rules enforced on import.
+Nothing is imported and snapshots aren't resolved.`,
+		Example: `  docker mcp profile validate --file my-profile.yaml`,
+		Args:    cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			return workingset.ValidateFile(file)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&file, "file", "", "Profile file to validate (.yaml or .json)")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func schemaWorkingSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of profile files",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			_, err := cmd.OutOrStdout().Write(workingset.Schema())
+			return err
 		},
 	}
 }
@@ -367,16 +495,23 @@ func workingsetServerCommand() *cobra.Command {
 
 	cmd.AddCommand(listServersCommand())
 	cmd.AddCommand(addServerCommand())
+	cmd.AddCommand(addGatewayServerCommand())
 	cmd.AddCommand(removeServerCommand())
+	cmd.AddCommand(updatePolicyServerCommand())
+	cmd.AddCommand(devServerCommand())
+	cmd.AddCommand(enableServerCommand(true))
+	cmd.AddCommand(enableServerCommand(false))
+	cmd.AddCommand(introspectServerCommand())
 
 	return cmd
 }
 
 func addServerCommand() *cobra.Command {
 	var servers []string
+	var fromFile string
 
 	cmd := &cobra.Command{
-		Use:   "add <profile-id> [--server <ref1> --server <ref2> ...]",
+		Use:   "add <profile-id> [--server <ref1> --server <ref2> ...] [--from-file <file>]",
 		Short: "Add MCP servers to a profile",
 		Long:  "Add MCP servers to a profile.",
 		Example: `  # Add servers from a catalog
@@ -389,7 +524,10 @@ func addServerCommand() *cobra.Command {
   docker mcp profile server add my-profile --server http://registry.modelcontextprotocol.io/v0/servers/71de5a2a-6cfb-4250-a196-f93080ecc860
 
   # Mix server references
-  docker mcp profile server add dev-tools --server catalog://mcp/docker-mcp-catalog/github+obsidian --server docker://my-server:latest`,
+  docker mcp profile server add dev-tools --server catalog://mcp/docker-mcp-catalog/github+obsidian --server docker://my-server:latest
+
+  # Add servers listed in a file, one reference per line (or a YAML list)
+  docker mcp profile server add my-profile --from-file servers.txt`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dao, err := db.New()
@@ -398,41 +536,311 @@ func addServerCommand() *cobra.Command {
 			}
 			registryClient := registryapi.NewClient()
 			ociService := oci.NewService()
+
+			if fromFile != "" {
+				refs, err := workingset.ReadServerRefs(fromFile)
+				if err != nil {
+					return err
+				}
+				return workingset.BulkAddServers(cmd.Context(), dao, registryClient, ociService, args[0], append(servers, refs...))
+			}
+
 			return workingset.AddServers(cmd.Context(), dao, registryClient, ociService, args[0], servers)
 		},
 	}
 
 	flags := cmd.Flags()
 	flags.StringArrayVar(&servers, "server", []string{}, "Server to include specified with a URI: https:// (MCP Registry reference) or docker:// (Docker Image reference) or catalog:// (Catalog reference). Can be specified multiple times.")
+	flags.StringVar(&fromFile, "from-file", "", "File listing the servers to include: one reference per line, or a YAML list (.yaml/.yml). References are resolved concurrently and the ones that can't be resolved are reported without blocking the others.")
+
+	return cmd
+}
+
+func addGatewayServerCommand() *cobra.Command {
+	var name string
+	var endpoint string
+	var prefix string
+
+	cmd := &cobra.Command{
+		Use:   "add-gateway <profile-id> --name <name> --endpoint <url> [--prefix <prefix>]",
+		Short: "Mount another MCP gateway as a server of a profile",
+		Long: `Mount another MCP gateway, running with the streaming transport, as a server of a profile.
+The tools of that gateway are aggregated under a prefix, which defaults to the name of the server,
+so that team gateways can federate into an organization gateway.
+
+The gateway authenticates with the Bearer token stored in the <name>.token secret.`,
+		Example: `  docker mcp profile server add-gateway org --name team-a --endpoint https://team-a.example.com:8811/mcp
+  docker mcp secret set team-a.token=<token of the team-a gateway>`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return workingset.AddGatewayServer(cmd.Context(), dao, args[0], name, endpoint, prefix)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&name, "name", "", "Name of the server")
+	flags.StringVar(&endpoint, "endpoint", "", "Streaming endpoint of the gateway, like http://host:8811/mcp")
+	flags.StringVar(&prefix, "prefix", "", "Prefix of the gateway's tools (defaults to the name of the server)")
+	_ = cmd.MarkFlagRequired("name")
+	_ = cmd.MarkFlagRequired("endpoint")
 
 	return cmd
 }
 
 func removeServerCommand() *cobra.Command {
 	var names []string
+	var keepConfig bool
 
 	cmd := &cobra.Command{
-		Use:     "remove <profile-id> --name <name1> --name <name2> ...",
+		Use:     "remove <profile-id> --name <name1> --name <name2> ... [--keep-config]",
 		Aliases: []string{"rm"},
 		Short:   "Remove MCP servers from a profile",
-		Long:    "Remove MCP servers from a profile by server name.",
+		Long: `Remove MCP servers from a profile by server name.
+The config and the tool allowlist of the servers are removed with them, so adding a server back starts from a clean state.
+The secret providers that no remaining server uses are removed too, except for the default one. Use --keep-config to keep them.`,
 		Example: ` # Remove servers by name
   docker mcp profile server remove dev-tools --name github --name slack
 
   # Remove a single server
-  docker mcp profile server remove dev-tools --name github`,
+  docker mcp profile server remove dev-tools --name github
+
+  # Remove a server but keep the secret providers it used
+  docker mcp profile server remove dev-tools --name github --keep-config`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			dao, err := db.New()
 			if err != nil {
 				return err
 			}
-			return workingset.RemoveServers(cmd.Context(), dao, args[0], names)
+			return workingset.RemoveServers(cmd.Context(), dao, args[0], names, keepConfig)
 		},
 	}
 
 	flags := cmd.Flags()
 	flags.StringArrayVar(&names, "name", []string{}, "Server name to remove (can be specified multiple times)")
+	flags.BoolVar(&keepConfig, "keep-config", false, "Keep the secret providers that no remaining server uses")
+
+	return cmd
+}
+
+func updatePolicyServerCommand() *cobra.Command {
+	var names []string
+	var policy string
+
+	cmd := &cobra.Command{
+		Use:   "update-policy <profile-id> --name <name1> --name <name2> ... --policy <policy>",
+		Short: "Set how MCP servers of a profile are updated",
+		Long: `Set how MCP servers of a profile are updated by docker mcp profile update.
+
+Policies:
+  pinned        Never update the server (default)
+  track-tag     Update an image server to the digest its tag points to
+  track-latest  Update an MCP Registry server to its latest version`,
+		Example: `  # Follow the tag of an image server
+  docker mcp profile server update-policy dev-tools --name github --policy track-tag
+
+  # Pin a server again
+  docker mcp profile server update-policy dev-tools --name github --policy pinned`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return workingset.SetUpdatePolicy(cmd.Context(), dao, args[0], names, workingset.UpdatePolicy(policy))
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringArrayVar(&names, "name", []string{}, "Server name (can be specified multiple times)")
+	flags.StringVar(&policy, "policy", "", "Update policy: pinned, track-tag or track-latest")
+	_ = cmd.MarkFlagRequired("policy")
+
+	return cmd
+}
+
+func devServerCommand() *cobra.Command {
+	var name string
+	var volumes []string
+	var env []string
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "dev <profile-id> --name <name> [--volume <host-path>:<container-path>[:ro]] [--env NAME=value] [--clear]",
+		Short: "Set the dev overrides of an MCP server in a profile",
+		Long: `Set volumes and environment variables of an MCP server of a profile that are only applied
+when the gateway runs with --dev, for example to mount a local checkout of the server's sources.
+Other runs of the profile are left untouched.
+
+Relative host paths are made absolute. Volumes and variables are added to the existing overrides, unless --clear is passed.`,
+		Example: `  # Mount the local sources of a server when the gateway runs with --dev
+  docker mcp profile server dev dev-tools --name my-server --volume ./src:/app/src:ro --env LOG_LEVEL=debug
+  docker mcp gateway run --profile dev-tools --dev
+
+  # Remove the dev overrides of a server
+  docker mcp profile server dev dev-tools --name my-server --clear`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return workingset.SetServerDev(cmd.Context(), dao, args[0], name, volumes, env, clear)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&name, "name", "", "Server name")
+	flags.StringArrayVar(&volumes, "volume", []string{}, "Volume mounted with --dev, as <host-path>:<container-path>[:ro] (can be specified multiple times)")
+	flags.StringArrayVar(&env, "env", []string{}, "Environment variable set with --dev, as NAME=value (can be specified multiple times)")
+	flags.BoolVar(&clear, "clear", false, "Remove the existing dev overrides first")
+	_ = cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func enableServerCommand(enable bool) *cobra.Command {
+	var names []string
+
+	cmd := &cobra.Command{
+		Use:   "enable <profile-id> --name <name1> --name <name2> ...",
+		Short: "Enable MCP servers of a profile",
+		Long:  "Enable MCP servers of a profile that were disabled, so that the gateway starts them again.",
+		Example: `  # Start the github server again
+  docker mcp profile server enable dev-tools --name github`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			if err := workingset.SetServersEnabled(cmd.Context(), dao, args[0], names, enable); err != nil {
+				return err
+			}
+
+			if enable {
+				fmt.Printf("Enabled %d server(s) in profile %s\n", len(names), args[0])
+			} else {
+				fmt.Printf("Disabled %d server(s) in profile %s\n", len(names), args[0])
+			}
+			return nil
+		},
+	}
+	if !enable {
+		cmd.Use = "disable <profile-id> --name <name1> --name <name2> ..."
+		cmd.Short = "Disable MCP servers of a profile"
+		cmd.Long = `Disable MCP servers of a profile. A disabled server keeps its config, secrets and tools,
+but the gateway doesn't start it. Clients can still add it to their session with mcp-add.`
+		cmd.Example = `  # Stop starting the github server by default
+  docker mcp profile server disable dev-tools --name github`
+	}
+
+	flags := cmd.Flags()
+	flags.StringArrayVar(&names, "name", []string{}, "Server name (can be specified multiple times)")
+
+	return cmd
+}
+
+func introspectServerCommand() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "introspect <profile-id> <server-name> [--dry-run]",
+		Short: "Draft the config schema of an MCP server of a profile",
+		Long: `Run an MCP server of a profile without its missing settings, detect the environment variables it needs
+from its image and from the errors it reports, and draft secrets and a config schema into the profile's snapshot
+of the server. The draft must be reviewed before it's used.`,
+		Example: `  # Show the settings a server seems to need
+  docker mcp profile server introspect dev-tools weather --dry-run
+
+  # Draft them into the profile
+  docker mcp profile server introspect dev-tools weather`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			ociService := oci.NewService()
+			return workingset.Introspect(cmd.Context(), dao, ociService, workingset.DockerProber{}, args[0], args[1], dryRun)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&dryRun, "dry-run", false, "Only show the drafted settings")
+
+	return cmd
+}
+
+func updateWorkingSetCommand() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "update <profile-id> [--dry-run]",
+		Short: "Update the MCP servers of a profile according to their update policies",
+		Long:  "Update the MCP servers of a profile according to their update policies, showing the tools added, removed or changed by each update.",
+		Example: `  # Show the available updates
+  docker mcp profile update dev-tools --dry-run
+
+  # Apply the available updates
+  docker mcp profile update dev-tools`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			registryClient := registryapi.NewClient()
+			ociService := oci.NewService()
+			return workingset.Update(cmd.Context(), dao, registryClient, ociService, args[0], dryRun)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&dryRun, "dry-run", false, "Only show the available updates")
+
+	return cmd
+}
+
+func dockerContextWorkingSetCommand() *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "context <profile-id> [<docker-context>] [--unset]",
+		Short: "Bind a profile to a Docker context",
+		Long: `Bind a profile to a Docker context so that the gateway only runs it against that context's engine.
+A profile intended for a remote engine then can't accidentally start containers locally.`,
+		Example: `  # Bind a profile to the current Docker context
+  docker mcp profile context remote-tools "$(docker context show)"
+
+  # Unbind a profile
+  docker mcp profile context remote-tools --unset`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var dockerContext string
+			switch {
+			case unset && len(args) == 2:
+				return fmt.Errorf("cannot use --unset with a Docker context")
+			case !unset && len(args) == 1:
+				return fmt.Errorf("a Docker context or --unset is required")
+			case !unset:
+				dockerContext = args[1]
+			}
+
+			dao, err := db.New()
+			if err != nil {
+				return err
+			}
+			return workingset.SetDockerContext(cmd.Context(), dao, args[0], dockerContext)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&unset, "unset", false, "Unbind the profile from its Docker context")
 
 	return cmd
 }
@@ -3,13 +3,25 @@ package oauth
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/desktop"
 	pkgoauth "github.com/docker/mcp-gateway/pkg/oauth"
 )
 
-func Authorize(ctx context.Context, app string, scopes string) error {
+func Authorize(ctx context.Context, app string, scopes string, profile string) error {
+	// Only ask for the scopes the profile or the catalog say the server needs, unless told otherwise
+	if scopes == "" {
+		var err error
+		scopes, err = configuredScopes(ctx, app, profile)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Check if running in CE mode
 	if pkgoauth.IsCEMode() {
 		return authorizeCEMode(ctx, app, scopes)
@@ -19,6 +31,49 @@ func Authorize(ctx context.Context, app string, scopes string) error {
 	return authorizeDesktopMode(ctx, app, scopes)
 }
 
+// configuredScopes returns the OAuth scopes configured for a server, space-separated: the scopes of the server
+// in the profile, if any, otherwise the scopes declared in the catalog.
+func configuredScopes(ctx context.Context, app string, profile string) (string, error) {
+	if profile != "" {
+		dao, err := db.New()
+		if err != nil {
+			return "", fmt.Errorf("failed to create database client: %w", err)
+		}
+		defer dao.Close()
+
+		workingSet, err := dao.GetWorkingSet(ctx, profile)
+		if err != nil {
+			return "", fmt.Errorf("failed to get profile %s: %w", profile, err)
+		}
+		server, override, found := profileServer(*workingSet, app)
+		if !found {
+			return "", fmt.Errorf("server %s not found in profile %s", app, profile)
+		}
+		return strings.Join(server.ConfiguredOAuthScopes(override), " "), nil
+	}
+
+	catalogData, err := catalog.GetWithOptions(ctx, true, nil)
+	if err != nil {
+		return "", nil
+	}
+	server, found := catalogData.Servers[app]
+	if !found {
+		return "", nil
+	}
+
+	return strings.Join(server.ConfiguredOAuthScopes(nil), " "), nil
+}
+
+// profileServer returns a server of a profile, along with the OAuth scopes the profile narrows it down to.
+func profileServer(workingSet db.WorkingSet, app string) (catalog.Server, []string, bool) {
+	for _, server := range workingSet.Servers {
+		if server.Snapshot != nil && server.Snapshot.Server.Name == app {
+			return server.Snapshot.Server, server.OAuthScopes, true
+		}
+	}
+	return catalog.Server{}, nil, false
+}
+
 // authorizeDesktopMode handles OAuth via Docker Desktop (existing behavior)
 func authorizeDesktopMode(ctx context.Context, app string, scopes string) error {
 	client := desktop.NewAuthClient()
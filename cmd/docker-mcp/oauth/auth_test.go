@@ -0,0 +1,38 @@
+package oauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func TestProfileServerScopes(t *testing.T) {
+	workingSet := db.WorkingSet{
+		ID: "dev",
+		Servers: db.ServerList{
+			{
+				Type:        "remote",
+				OAuthScopes: []string{"read:org"},
+				Snapshot:    &db.ServerSnapshot{Server: catalog.Server{Name: "github", OAuth: &catalog.OAuth{Scopes: []string{"repo", "read:org"}}}},
+			},
+			{
+				Type:     "remote",
+				Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: "notion", OAuth: &catalog.OAuth{Scopes: []string{"read"}}}},
+			},
+		},
+	}
+
+	server, override, found := profileServer(workingSet, "github")
+	assert.True(t, found)
+	assert.Equal(t, []string{"read:org"}, server.ConfiguredOAuthScopes(override))
+
+	server, override, found = profileServer(workingSet, "notion")
+	assert.True(t, found)
+	assert.Equal(t, []string{"read"}, server.ConfiguredOAuthScopes(override))
+
+	_, _, found = profileServer(workingSet, "slack")
+	assert.False(t, found)
+}
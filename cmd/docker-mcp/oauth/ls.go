@@ -4,11 +4,34 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/secret-management/formatting"
 	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/log"
+	pkgoauth "github.com/docker/mcp-gateway/pkg/oauth"
+	"github.com/docker/mcp-gateway/pkg/oauth/dcr"
 )
 
+// DCR registration states
+const (
+	dcrRegistered   = "registered"
+	dcrUnregistered = "unregistered"
+)
+
+// AppStatus is an OAuth app with the health of its token and provider.
+type AppStatus struct {
+	desktop.OAuthApp
+
+	TokenExpiresAt  time.Time `json:"tokenExpiresAt,omitzero"`
+	HasRefreshToken bool      `json:"hasRefreshToken"`
+	DCR             string    `json:"dcr,omitempty"`
+	// Gateway is the state of the provider running in the gateway, if any.
+	Gateway *pkgoauth.ProviderState `json:"gateway,omitempty"`
+}
+
 func Ls(ctx context.Context, outputJSON bool) error {
 	client := desktop.NewAuthClient()
 
@@ -19,25 +42,144 @@ func Ls(ctx context.Context, outputJSON bool) error {
 		return err
 	}
 
-	if outputJSON {
-		if len(apps) == 0 {
-			apps = make([]desktop.OAuthApp, 0) // Guarantee empty list (instead of displaying null)
+	// Reading the token status logs details we don't want to print here
+	log.SetLogWriter(io.Discard)
+
+	statuses := make([]AppStatus, 0, len(apps)) // Guarantee empty list (instead of displaying null)
+	gatewayStates := readGatewayProviderStates(ctx)
+	credHelper := pkgoauth.NewOAuthCredentialHelper()
+	for _, app := range apps {
+		status := AppStatus{
+			OAuthApp: app,
+			DCR:      dcrState(ctx, client, app.App),
+		}
+		if app.Authorized {
+			if tokenStatus, err := credHelper.GetTokenStatus(ctx, app.App); err == nil {
+				status.TokenExpiresAt = tokenStatus.ExpiresAt
+				status.HasRefreshToken = tokenStatus.HasRefreshToken
+			}
+		}
+		if state, ok := gatewayStates[app.App]; ok {
+			status.Gateway = &state
 		}
-		jsonData, err := json.MarshalIndent(apps, "", "  ")
+		statuses = append(statuses, status)
+	}
+
+	if outputJSON {
+		jsonData, err := json.MarshalIndent(statuses, "", "  ")
 		if err != nil {
 			return err
 		}
 		fmt.Println(string(jsonData))
 		return nil
 	}
+
 	var rows [][]string
-	for _, app := range apps {
+	for _, status := range statuses {
 		authorized := "not authorized"
-		if app.Authorized {
+		if status.Authorized {
 			authorized = "authorized"
 		}
-		rows = append(rows, []string{app.App, authorized})
+		rows = append(rows, []string{
+			status.App,
+			authorized,
+			formatExpiry(status, time.Now()),
+			formatRefreshToken(status),
+			orDash(status.DCR),
+			formatLastRefresh(status.Gateway, time.Now()),
+		})
 	}
-	formatting.PrettyPrintTable(rows, []int{80, 120})
+	header := []string{"APP", "STATUS", "EXPIRES", "REFRESH TOKEN", "DCR", "LAST REFRESH"}
+	formatting.PrettyPrintTable(rows, []int{40, 16, 16, 14, 14, 60}, header)
 	return nil
 }
+
+// readGatewayProviderStates asks a running gateway for the state of its OAuth providers.
+// Returns an empty map if no gateway is listening on the default control socket.
+func readGatewayProviderStates(ctx context.Context) map[string]pkgoauth.ProviderState {
+	states := map[string]pkgoauth.ProviderState{}
+
+	socketPath, err := gateway.DefaultControlSocketPath()
+	if err != nil {
+		return states
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	response, err := gateway.NewControlClient(socketPath).OAuthStatus(ctx)
+	if err != nil {
+		return states
+	}
+	for _, state := range response.Providers {
+		states[state.Server] = state
+	}
+	return states
+}
+
+// dcrState returns the dynamic client registration state of an app, or "" if it doesn't use DCR.
+func dcrState(ctx context.Context, client *desktop.Tools, app string) string {
+	if pkgoauth.IsCEMode() {
+		dcrClient, err := dcr.NewManager(pkgoauth.NewReadWriteCredentialHelper(), "").GetDCRClient(app)
+		if err != nil {
+			return ""
+		}
+		if dcrClient.ClientID == "" {
+			return dcrUnregistered
+		}
+		return dcrRegistered
+	}
+
+	dcrClient, err := client.GetDCRClient(ctx, app)
+	if err != nil {
+		return ""
+	}
+	if dcrClient.State != "" {
+		return dcrClient.State
+	}
+	if dcrClient.ClientID == "" {
+		return dcrUnregistered
+	}
+	return dcrRegistered
+}
+
+func formatExpiry(status AppStatus, now time.Time) string {
+	if !status.Authorized || status.TokenExpiresAt.IsZero() {
+		return "-"
+	}
+
+	remaining := status.TokenExpiresAt.Sub(now)
+	if remaining <= 0 {
+		return "expired"
+	}
+	return "in " + remaining.Round(time.Second).String()
+}
+
+func formatRefreshToken(status AppStatus) string {
+	if !status.Authorized {
+		return "-"
+	}
+	if status.HasRefreshToken {
+		return "yes"
+	}
+	return "no"
+}
+
+func formatLastRefresh(state *pkgoauth.ProviderState, now time.Time) string {
+	if state == nil || state.LastRefreshAt.IsZero() {
+		return "-"
+	}
+
+	ago := now.Sub(state.LastRefreshAt).Round(time.Second).String() + " ago"
+	if state.LastRefreshError != "" {
+		return "failed " + ago + ": " + state.LastRefreshError
+	}
+	return "succeeded " + ago
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,35 @@
+package oauth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	pkgoauth "github.com/docker/mcp-gateway/pkg/oauth"
+)
+
+func TestFormatAppStatus(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	authorized := AppStatus{
+		OAuthApp:        desktop.OAuthApp{App: "github", Authorized: true},
+		TokenExpiresAt:  now.Add(42 * time.Minute),
+		HasRefreshToken: true,
+	}
+
+	assert.Equal(t, "in 42m0s", formatExpiry(authorized, now))
+	assert.Equal(t, "expired", formatExpiry(authorized, now.Add(time.Hour)))
+	assert.Equal(t, "yes", formatRefreshToken(authorized))
+	assert.Equal(t, "-", formatExpiry(AppStatus{}, now))
+	assert.Equal(t, "-", formatRefreshToken(AppStatus{}))
+}
+
+func TestFormatLastRefresh(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "-", formatLastRefresh(nil, now))
+	assert.Equal(t, "-", formatLastRefresh(&pkgoauth.ProviderState{Server: "github"}, now))
+	assert.Equal(t, "succeeded 5m0s ago", formatLastRefresh(&pkgoauth.ProviderState{LastRefreshAt: now.Add(-5 * time.Minute)}, now))
+	assert.Equal(t, "failed 30s ago: invalid_grant", formatLastRefresh(&pkgoauth.ProviderState{LastRefreshAt: now.Add(-30 * time.Second), LastRefreshError: "invalid_grant"}, now))
+}
@@ -3,22 +3,70 @@ package oauth
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/gateway"
 	pkgoauth "github.com/docker/mcp-gateway/pkg/oauth"
+	"github.com/docker/mcp-gateway/pkg/oauth/dcr"
 )
 
 func Revoke(ctx context.Context, app string) error {
 	fmt.Printf("Revoking OAuth access for %s...\n", app)
 
 	// Check if CE mode
+	var err error
 	if pkgoauth.IsCEMode() {
-		return revokeCEMode(ctx, app)
+		err = revokeCEMode(ctx, app)
+	} else {
+		err = revokeDesktopMode(ctx, app)
+	}
+	if err != nil {
+		return err
+	}
+
+	notifyGateway(ctx, app, !pkgoauth.IsCEMode())
+	return nil
+}
+
+// notifyGateway tells a running gateway to stop using the revoked server.
+// Nothing to do if no gateway is listening on the default control socket.
+// Only Docker Desktop tells the gateway when the server is authorized again.
+func notifyGateway(ctx context.Context, app string, desktopMode bool) {
+	socketPath, err := gateway.DefaultControlSocketPath()
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := gateway.NewControlClient(socketPath).RevokeOAuth(ctx, app); err == nil {
+		fmt.Printf("Removed %s's tools from the running gateway\n", app)
+		if !desktopMode {
+			fmt.Printf("Once %s is authorized again, add it back with mcp-add or restart the gateway\n", app)
+		}
+	}
+}
+
+// revokeAtProvider revokes the stored token at the provider (RFC 7009), when a revocation endpoint is advertised.
+func revokeAtProvider(ctx context.Context, dcrClient *desktop.DCRClient) {
+	client := dcr.Client{
+		ServerName:            dcrClient.ServerName,
+		ProviderName:          dcrClient.ProviderName,
+		ClientID:              dcrClient.ClientID,
+		AuthorizationServer:   dcrClient.AuthorizationServer,
+		AuthorizationEndpoint: dcrClient.AuthorizationEndpoint,
 	}
 
-	// Desktop mode - existing implementation
-	return revokeDesktopMode(ctx, app)
+	token, err := pkgoauth.NewTokenStore(pkgoauth.NewOAuthCredentialHelper().GetHelper()).Retrieve(client)
+	if err != nil {
+		return
+	}
+	if err := pkgoauth.RevokeAtProvider(ctx, client, token); err != nil {
+		fmt.Printf("Note: failed to revoke the token at the provider: %v\n", err)
+	}
 }
 
 // revokeDesktopMode handles revoke via Docker Desktop (existing behavior)
@@ -34,6 +82,13 @@ func revokeDesktopMode(ctx context.Context, app string) error {
 	server, found := catalogData.Servers[app]
 	isRemoteOAuth := found && server.IsRemoteOAuthServer()
 
+	// Revoke at the provider before the token is erased
+	if isRemoteOAuth {
+		if dcrClient, err := client.GetDCRClient(ctx, app); err == nil {
+			revokeAtProvider(ctx, dcrClient)
+		}
+	}
+
 	// Revoke tokens
 	if err := client.DeleteOAuthApp(ctx, app); err != nil {
 		return fmt.Errorf("failed to revoke OAuth access: %w", err)
@@ -56,7 +111,7 @@ func revokeCEMode(ctx context.Context, app string) error {
 	credHelper := pkgoauth.NewReadWriteCredentialHelper()
 	manager := pkgoauth.NewManager(credHelper)
 
-	// Delete OAuth token
+	// Revoke OAuth token at the provider and delete it
 	if err := manager.RevokeToken(ctx, app); err != nil {
 		// Token might not exist, continue to DCR deletion
 		fmt.Printf("Note: %v\n", err)
@@ -0,0 +1,16 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+)
+
+func Get(ctx context.Context, store Store, name string) error {
+	value, err := store.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
+}
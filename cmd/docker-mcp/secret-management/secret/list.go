@@ -13,8 +13,8 @@ type ListOptions struct {
 	JSON bool
 }
 
-func List(ctx context.Context, opts ListOptions) error {
-	l, err := desktop.NewSecretsClient().ListJfsSecrets(ctx)
+func List(ctx context.Context, store Store, opts ListOptions) error {
+	l, err := store.List(ctx)
 	if err != nil {
 		return err
 	}
@@ -5,17 +5,16 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/docker"
 )
 
 type RmOpts struct {
 	All bool
 }
 
-func Remove(ctx context.Context, names []string, opts RmOpts) error {
-	c := desktop.NewSecretsClient()
+func Remove(ctx context.Context, store Store, names []string, opts RmOpts) error {
 	if opts.All && len(names) == 0 {
-		l, err := c.ListJfsSecrets(ctx)
+		l, err := store.List(ctx)
 		if err != nil {
 			return err
 		}
@@ -25,12 +24,15 @@ func Remove(ctx context.Context, names []string, opts RmOpts) error {
 	}
 	var errs []error
 	for _, name := range names {
-		if err := c.DeleteJfsSecret(ctx, name); err != nil {
+		if err := store.Remove(ctx, name); err != nil {
 			errs = append(errs, err)
 			fmt.Printf("failed removing secret %s\n", name)
 			continue
 		}
 		fmt.Printf("removed secret %s\n", name)
 	}
+
+	// Running gateways read the secrets again. Best effort, their cache expires anyway.
+	_ = docker.InvalidateSecretsCache()
 	return errors.Join(errs...)
 }
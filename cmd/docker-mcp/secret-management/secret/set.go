@@ -6,7 +6,7 @@ import (
 	"os"
 	"strings"
 
-	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/tui"
 )
 
@@ -50,21 +50,17 @@ func ParseArg(arg string, opts SetOpts) (*Secret, error) {
 }
 
 func isDirectValueProvider(provider string) bool {
-	return provider == "" || provider == Credstore
+	return provider == "" || provider == DockerDesktop || provider == Credstore
 }
 
-func Set(ctx context.Context, s Secret, opts SetOpts) error {
-	if opts.Provider == Credstore {
-		p := NewCredStoreProvider()
-		if err := p.SetSecret(s.key, s.val); err != nil {
-			return err
-		}
+func Set(ctx context.Context, store Store, s Secret) error {
+	if err := store.Set(ctx, s.key, s.val); err != nil {
+		return err
 	}
-	return desktop.NewSecretsClient().SetJfsSecret(ctx, desktop.Secret{
-		Name:     s.key,
-		Value:    s.val,
-		Provider: opts.Provider,
-	})
+
+	// Running gateways read the secrets again. Best effort, their cache expires anyway.
+	_ = docker.InvalidateSecretsCache()
+	return nil
 }
 
 func IsValidProvider(provider string) bool {
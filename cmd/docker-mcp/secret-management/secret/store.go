@@ -0,0 +1,279 @@
+package secret
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/secretprovider"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+const (
+	// DockerDesktop is Docker Desktop's secret store, the default provider.
+	DockerDesktop = string(workingset.SecretProviderDockerDesktop)
+	// Env reads secrets from the environment variables.
+	Env = "env"
+)
+
+// ErrNotSupported is returned when a provider doesn't support an operation, eg. setting secrets in AWS Secrets Manager.
+var ErrNotSupported = errors.New("not supported")
+
+// Store is a provider the secrets are set in, read from, removed from and listed from.
+type Store interface {
+	Name() string
+	Set(ctx context.Context, name, value string) error
+	Get(ctx context.Context, name string) (string, error)
+	Remove(ctx context.Context, name string) error
+	List(ctx context.Context) ([]desktop.StoredSecret, error)
+}
+
+// StoreOpts selects the provider of a secret command.
+type StoreOpts struct {
+	// Provider overrides the provider used by the profile.
+	// It's either the name of a provider or the name of an entry of the profile's secrets.
+	Provider string
+	// Profile whose secret provider is used.
+	Profile string
+}
+
+// Providers lists the providers that can be selected with --provider.
+var Providers = []string{
+	DockerDesktop,
+	Credstore,
+	Env,
+	string(workingset.SecretProviderAWSSecretsManager),
+	string(workingset.SecretProviderAWSSSMParameterStore),
+	string(workingset.SecretProviderOnePassword),
+}
+
+// IsValidStoreProvider checks the value of --provider, that can also be an oauth/<provider> of Docker Desktop's store.
+func IsValidStoreProvider(provider string) bool {
+	return IsValidProvider(provider) || slices.Contains(Providers, provider)
+}
+
+// OpenStore returns the provider selected with --provider, or the one used by the profile.
+// Docker Desktop's secret store is used by default.
+func OpenStore(ctx context.Context, dockerClient docker.Client, opts StoreOpts) (Store, error) {
+	if opts.Profile == "" {
+		return newStore(dockerClient, opts.Provider, workingset.Secret{})
+	}
+
+	dao, err := db.New()
+	if err != nil {
+		return nil, err
+	}
+	defer dao.Close()
+
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, opts.Profile)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("profile %s not found", opts.Profile)
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	return profileStore(dockerClient, workingset.NewFromDb(dbWorkingSet), opts.Provider)
+}
+
+// profileStore returns the provider an entry of the profile's secrets is configured with.
+func profileStore(dockerClient docker.Client, workingSet workingset.WorkingSet, provider string) (Store, error) {
+	if provider != "" {
+		if secretConfig, ok := workingSet.Secrets[provider]; ok {
+			return newStore(dockerClient, string(secretConfig.Provider), secretConfig)
+		}
+		// The provider of the secrets is overridden, with the configuration of the profile if it has one.
+		for _, secretConfig := range workingSet.Secrets {
+			if string(secretConfig.Provider) == provider {
+				return newStore(dockerClient, provider, secretConfig)
+			}
+		}
+		return newStore(dockerClient, provider, workingset.Secret{})
+	}
+
+	switch len(workingSet.Secrets) {
+	case 0:
+		return newStore(dockerClient, DockerDesktop, workingset.Secret{})
+	case 1:
+		for _, secretConfig := range workingSet.Secrets {
+			return newStore(dockerClient, string(secretConfig.Provider), secretConfig)
+		}
+	}
+
+	var refs []string
+	for ref := range workingSet.Secrets {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return nil, fmt.Errorf("profile %s uses several secret providers, pick one with --provider: %s", workingSet.ID, strings.Join(refs, ", "))
+}
+
+func newStore(dockerClient docker.Client, provider string, secretConfig workingset.Secret) (Store, error) {
+	switch {
+	case provider == "" || provider == DockerDesktop:
+		return &desktopStore{docker: dockerClient}, nil
+	case provider == Credstore || strings.HasPrefix(provider, "oauth/"):
+		return &desktopStore{docker: dockerClient, provider: provider}, nil
+	case provider == Env:
+		return envStore{}, nil
+	case provider == string(workingset.SecretProviderAWSSecretsManager):
+		return &readOnlyStore{
+			name:     provider,
+			provider: &secretprovider.AWSSecretsManager{Region: secretConfig.Region, Prefix: secretConfig.Prefix},
+		}, nil
+	case provider == string(workingset.SecretProviderAWSSSMParameterStore):
+		return &readOnlyStore{
+			name:     provider,
+			provider: &secretprovider.AWSParameterStore{Region: secretConfig.Region, Prefix: secretConfig.Prefix},
+		}, nil
+	case provider == string(workingset.SecretProviderOnePassword):
+		return &readOnlyStore{
+			name:     provider,
+			provider: &secretprovider.OnePassword{References: secretConfig.References, Vault: secretConfig.Vault},
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid provider: %s", provider)
+	}
+}
+
+// desktopStore is Docker Desktop's secret store. Secrets with the credstore provider are also kept in the credential helper.
+type desktopStore struct {
+	docker   docker.Client
+	provider string
+}
+
+func (s *desktopStore) Name() string {
+	if s.provider != "" {
+		return s.provider
+	}
+	return DockerDesktop
+}
+
+func (s *desktopStore) Set(ctx context.Context, name, value string) error {
+	if s.provider == Credstore {
+		if err := NewCredStoreProvider().SetSecret(name, value); err != nil {
+			return err
+		}
+	}
+	return desktop.NewSecretsClient().SetJfsSecret(ctx, desktop.Secret{
+		Name:     name,
+		Value:    value,
+		Provider: s.provider,
+	})
+}
+
+func (s *desktopStore) Get(ctx context.Context, name string) (string, error) {
+	if s.provider == Credstore {
+		return NewCredStoreProvider().GetSecret(name)
+	}
+
+	secrets, err := s.docker.ReadSecrets(ctx, []string{name}, false)
+	if err != nil {
+		return "", err
+	}
+	value, ok := secrets[name]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found", name)
+	}
+	return value, nil
+}
+
+func (s *desktopStore) Remove(ctx context.Context, name string) error {
+	if s.provider == Credstore {
+		if err := NewCredStoreProvider().DeleteSecret(name); err != nil {
+			return err
+		}
+	}
+	return desktop.NewSecretsClient().DeleteJfsSecret(ctx, name)
+}
+
+func (s *desktopStore) List(ctx context.Context) ([]desktop.StoredSecret, error) {
+	secrets, err := desktop.NewSecretsClient().ListJfsSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.provider == "" {
+		return secrets, nil
+	}
+
+	var filtered []desktop.StoredSecret
+	for _, secret := range secrets {
+		if secret.Provider == s.provider {
+			filtered = append(filtered, secret)
+		}
+	}
+	return filtered, nil
+}
+
+// envStore reads secrets from the environment variables.
+type envStore struct{}
+
+func (envStore) Name() string {
+	return Env
+}
+
+func (envStore) Set(context.Context, string, string) error {
+	return notSupported(Env, "setting")
+}
+
+func (envStore) Get(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("secret %s not found in the environment", name)
+	}
+	return value, nil
+}
+
+func (envStore) Remove(context.Context, string) error {
+	return notSupported(Env, "removing")
+}
+
+func (envStore) List(context.Context) ([]desktop.StoredSecret, error) {
+	return nil, notSupported(Env, "listing")
+}
+
+// readOnlyStore reads secrets from an external provider. They're managed with the provider's own tools.
+type readOnlyStore struct {
+	name     string
+	provider secretprovider.Provider
+}
+
+func (s *readOnlyStore) Name() string {
+	return s.name
+}
+
+func (s *readOnlyStore) Set(context.Context, string, string) error {
+	return notSupported(s.name, "setting")
+}
+
+func (s *readOnlyStore) Get(ctx context.Context, name string) (string, error) {
+	secrets, err := s.provider.GetSecrets(ctx, []string{name})
+	if err != nil {
+		return "", err
+	}
+	value, ok := secrets[name]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found in %s", name, s.name)
+	}
+	return value, nil
+}
+
+func (s *readOnlyStore) Remove(context.Context, string) error {
+	return notSupported(s.name, "removing")
+}
+
+func (s *readOnlyStore) List(context.Context) ([]desktop.StoredSecret, error) {
+	return nil, notSupported(s.name, "listing")
+}
+
+func notSupported(provider, operation string) error {
+	return fmt.Errorf("provider %s doesn't support %s secrets: %w", provider, operation, ErrNotSupported)
+}
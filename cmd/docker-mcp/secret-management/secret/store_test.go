@@ -0,0 +1,93 @@
+package secret
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/secretprovider"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+func TestProfileStoreDefaultsToDockerDesktop(t *testing.T) {
+	store, err := profileStore(nil, workingset.WorkingSet{ID: "dev"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, DockerDesktop, store.Name())
+}
+
+func TestProfileStoreUsesTheProfileProvider(t *testing.T) {
+	workingSet := workingset.WorkingSet{
+		ID: "dev",
+		Secrets: map[string]workingset.Secret{
+			"default": {Provider: workingset.SecretProviderAWSSecretsManager, Region: "eu-west-1", Prefix: "mcp/"},
+		},
+	}
+
+	store, err := profileStore(nil, workingSet, "")
+	require.NoError(t, err)
+	assert.Equal(t, "aws-secrets-manager", store.Name())
+	assert.Equal(t, &secretprovider.AWSSecretsManager{Region: "eu-west-1", Prefix: "mcp/"}, store.(*readOnlyStore).provider)
+}
+
+func TestProfileStoreWithSeveralProviders(t *testing.T) {
+	workingSet := workingset.WorkingSet{
+		ID: "dev",
+		Secrets: map[string]workingset.Secret{
+			"aws": {Provider: workingset.SecretProviderAWSSSMParameterStore},
+			"op":  {Provider: workingset.SecretProviderOnePassword, Vault: "mcp"},
+		},
+	}
+
+	_, err := profileStore(nil, workingSet, "")
+	require.ErrorContains(t, err, "pick one with --provider: aws, op")
+
+	// By entry of the profile's secrets
+	store, err := profileStore(nil, workingSet, "op")
+	require.NoError(t, err)
+	assert.Equal(t, &secretprovider.OnePassword{Vault: "mcp"}, store.(*readOnlyStore).provider)
+
+	// By provider, with the configuration of the profile
+	store, err = profileStore(nil, workingSet, "1password")
+	require.NoError(t, err)
+	assert.Equal(t, &secretprovider.OnePassword{Vault: "mcp"}, store.(*readOnlyStore).provider)
+
+	// Overridden
+	store, err = profileStore(nil, workingSet, Credstore)
+	require.NoError(t, err)
+	assert.Equal(t, Credstore, store.Name())
+}
+
+func TestNewStoreInvalidProvider(t *testing.T) {
+	_, err := newStore(nil, "vault", workingset.Secret{})
+	require.ErrorContains(t, err, "invalid provider: vault")
+}
+
+func TestEnvStore(t *testing.T) {
+	t.Setenv("MCP_TEST_SECRET", "value")
+
+	store, err := newStore(nil, Env, workingset.Secret{})
+	require.NoError(t, err)
+
+	value, err := store.Get(t.Context(), "MCP_TEST_SECRET")
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	_, err = store.Get(t.Context(), "MCP_TEST_UNKNOWN_SECRET")
+	require.Error(t, err)
+
+	err = store.Set(t.Context(), "MCP_TEST_SECRET", "other")
+	assert.True(t, errors.Is(err, ErrNotSupported))
+	assert.EqualError(t, err, "provider env doesn't support setting secrets: not supported")
+}
+
+func TestIsValidStoreProvider(t *testing.T) {
+	assert.True(t, IsValidStoreProvider(""))
+	assert.True(t, IsValidStoreProvider(DockerDesktop))
+	assert.True(t, IsValidStoreProvider(Credstore))
+	assert.True(t, IsValidStoreProvider("oauth/github"))
+	assert.True(t, IsValidStoreProvider(Env))
+	assert.True(t, IsValidStoreProvider("1password"))
+	assert.False(t, IsValidStoreProvider("vault"))
+}
@@ -1,24 +1,83 @@
 package tools
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"slices"
 	"strings"
+	"text/template"
 	"time"
 
+	"github.com/docker/cli/cli"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
-func Call(ctx context.Context, version string, gatewayArgs []string, debug bool, args []string) error {
+const (
+	// OutputText prints the text content of the result.
+	OutputText = "text"
+	// OutputJSON prints the whole result as JSON.
+	OutputJSON = "json"
+	// OutputStructured prints the structured content of the result as JSON, or its text content if it has none.
+	OutputStructured = "structured"
+)
+
+// Outputs lists the supported output formats of a tool call.
+var Outputs = []string{OutputText, OutputJSON, OutputStructured}
+
+// toolErrorExitCode is the exit code of a call whose result is an error, to tell it apart from a call that failed.
+const toolErrorExitCode = 2
+
+// CallOptions configures how a tool is called.
+type CallOptions struct {
+	// ArgsFile is a JSON object with the arguments of the tool, or - to read it from stdin.
+	// Arguments passed as key=value take precedence.
+	ArgsFile string
+	// Vars are name=value variables, used in the arguments as {{.name}}.
+	Vars []string
+	// Output is the output format: text, json or structured.
+	Output string
+	// Server limits the gateway that is started to a single server.
+	Server string
+	// Session is the URL of a running gateway to call the tool through, instead of starting one.
+	Session string
+}
+
+func Call(ctx context.Context, version string, gatewayArgs []string, debug bool, args []string, opts CallOptions) error {
 	if len(args) == 0 {
 		return errors.New("no tool name provided")
 	}
 	toolName := args[0]
 
+	output := opts.Output
+	if output == "" {
+		output = OutputText
+	}
+	if !slices.Contains(Outputs, output) {
+		return fmt.Errorf("unsupported output %q, expected one of %s", output, strings.Join(Outputs, ", "))
+	}
+
+	arguments, err := callArguments(opts.ArgsFile, args[1:], os.Stdin)
+	if err != nil {
+		return err
+	}
+	if len(opts.Vars) > 0 {
+		vars, err := parseVars(opts.Vars)
+		if err != nil {
+			return err
+		}
+		if err := applyVars(arguments, vars); err != nil {
+			return err
+		}
+	}
+
 	// Initialize telemetry for CLI tool calls
 	meter := otel.GetMeterProvider().Meter("github.com/docker/mcp-gateway")
 	toolCallCounter, _ := meter.Int64Counter("mcp.cli.tool.calls",
@@ -28,15 +87,26 @@ func Call(ctx context.Context, version string, gatewayArgs []string, debug bool,
 		metric.WithDescription("Tool call duration from CLI"),
 		metric.WithUnit("ms"))
 
-	c, err := start(ctx, version, gatewayArgs, debug)
-	if err != nil {
-		return fmt.Errorf("starting client: %w", err)
+	var c *mcp.ClientSession
+	if opts.Session != "" {
+		c, err = connect(ctx, opts.Session)
+		if err != nil {
+			return fmt.Errorf("connecting to gateway %s: %w", opts.Session, err)
+		}
+	} else {
+		if opts.Server != "" && version == "2" {
+			gatewayArgs = append(slices.Clone(gatewayArgs), "--servers="+opts.Server)
+		}
+		c, err = start(ctx, version, gatewayArgs, debug)
+		if err != nil {
+			return fmt.Errorf("starting client: %w", err)
+		}
 	}
 	defer c.Close()
 
 	params := &mcp.CallToolParams{
 		Name:      toolName,
-		Arguments: parseArgs(args[1:]),
+		Arguments: arguments,
 	}
 
 	start := time.Now()
@@ -59,17 +129,148 @@ func Call(ctx context.Context, version string, gatewayArgs []string, debug bool,
 	toolCallCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
 	toolCallDuration.Record(ctx, float64(duration.Milliseconds()), metric.WithAttributes(attrs...))
 
-	fmt.Println("Tool call took:", duration)
+	if output == OutputText {
+		fmt.Println("Tool call took:", duration)
+
+		if response.IsError {
+			return cli.StatusError{
+				Status:     fmt.Sprintf("error calling tool %s: %s", toolName, toText(response)),
+				StatusCode: toolErrorExitCode,
+			}
+		}
+
+		fmt.Println(toText(response))
+		return nil
+	}
+
+	// Keep stdout parsable
+	fmt.Fprintln(os.Stderr, "Tool call took:", duration)
+
+	formatted, err := formatResult(response, output)
+	if err != nil {
+		return err
+	}
+	fmt.Println(formatted)
 
 	if response.IsError {
-		return fmt.Errorf("error calling tool %s: %s", toolName, toText(response))
+		return cli.StatusError{
+			Status:     fmt.Sprintf("tool %s returned an error", toolName),
+			StatusCode: toolErrorExitCode,
+		}
 	}
 
-	fmt.Println(toText(response))
+	return nil
+}
+
+// formatResult prints a result as json or structured output.
+func formatResult(response *mcp.CallToolResult, output string) (string, error) {
+	var v any = response
+	if output == OutputStructured {
+		if response.StructuredContent == nil {
+			return toText(response), nil
+		}
+		v = response.StructuredContent
+	}
 
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshalling result: %w", err)
+	}
+	return string(buf), nil
+}
+
+// callArguments reads the arguments of a tool from a JSON file, or stdin, and adds the ones passed as key=value.
+func callArguments(argsFile string, args []string, stdin io.Reader) (map[string]any, error) {
+	arguments := map[string]any{}
+
+	if argsFile != "" {
+		var (
+			buf []byte
+			err error
+		)
+		if argsFile == "-" {
+			buf, err = io.ReadAll(stdin)
+		} else {
+			buf, err = os.ReadFile(argsFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading arguments: %w", err)
+		}
+
+		if err := json.Unmarshal(buf, &arguments); err != nil {
+			return nil, fmt.Errorf("arguments must be a JSON object: %w", err)
+		}
+		if arguments == nil {
+			arguments = map[string]any{}
+		}
+	}
+
+	for key, value := range parseArgs(args) {
+		arguments[key] = value
+	}
+
+	return arguments, nil
+}
+
+func parseVars(vars []string) (map[string]string, error) {
+	parsed := map[string]string{}
+	for _, v := range vars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid variable %q, expected name=value", v)
+		}
+		parsed[name] = value
+	}
+	return parsed, nil
+}
+
+// applyVars executes the string values of the arguments, at any depth, as templates of the given data, eg. {{.name}} for a variable.
+func applyVars(arguments map[string]any, data any) error {
+	for key, value := range arguments {
+		expanded, err := expandVars(value, data)
+		if err != nil {
+			return fmt.Errorf("argument %s: %w", key, err)
+		}
+		arguments[key] = expanded
+	}
 	return nil
 }
 
+func expandVars(value any, data any) (any, error) {
+	switch value := value.(type) {
+	case string:
+		if !strings.Contains(value, "{{") {
+			return value, nil
+		}
+
+		tmpl, err := template.New("").Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		return buf.String(), nil
+	case []any:
+		for i, item := range value {
+			expanded, err := expandVars(item, data)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = expanded
+		}
+		return value, nil
+	case map[string]any:
+		if err := applyVars(value, data); err != nil {
+			return nil, err
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
 func toText(response *mcp.CallToolResult) string {
 	var contents []string
 
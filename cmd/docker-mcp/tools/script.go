@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/goccy/go-yaml"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Script is a sequence of tool calls, run by `docker mcp run`.
+type Script struct {
+	// Profile the gateway is started with. Overridden by --profile.
+	Profile string `yaml:"profile,omitempty"`
+	// Servers the gateway is started with, when no profile is used.
+	Servers []string `yaml:"servers,omitempty"`
+	// Vars are the default values of the variables, used in the arguments as {{.vars.name}}. Overridden by --var.
+	Vars  map[string]string `yaml:"vars,omitempty"`
+	Steps []Step            `yaml:"steps"`
+}
+
+// Step is a tool call of a script.
+// Its arguments can use the results of the previous steps: {{.steps.<name>.text}} or {{.steps.<name>.structured.<field>}}.
+type Step struct {
+	// Name of the step, defaults to the name of the tool.
+	Name      string         `yaml:"name,omitempty"`
+	Tool      string         `yaml:"tool"`
+	Arguments map[string]any `yaml:"arguments,omitempty"`
+	// ContinueOnError runs the next steps even if this call fails or returns an error.
+	ContinueOnError bool `yaml:"continue_on_error,omitempty"`
+}
+
+// RunOptions configures how a script is run.
+type RunOptions struct {
+	Profile     string
+	Vars        []string
+	Session     string
+	GatewayArgs []string
+	Verbose     bool
+}
+
+// toolCaller calls tools. It's implemented by *mcp.ClientSession.
+type toolCaller interface {
+	CallTool(ctx context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error)
+}
+
+// ReadScript reads and validates a script.
+func ReadScript(path string) (Script, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return Script{}, err
+	}
+
+	var script Script
+	if err := yaml.Unmarshal(buf, &script); err != nil {
+		return Script{}, fmt.Errorf("parsing script %s: %w", path, err)
+	}
+
+	if len(script.Steps) == 0 {
+		return Script{}, fmt.Errorf("script %s has no steps", path)
+	}
+	seen := map[string]bool{}
+	for i := range script.Steps {
+		step := &script.Steps[i]
+		if step.Tool == "" {
+			return Script{}, fmt.Errorf("step %d of script %s has no tool", i+1, path)
+		}
+		if step.Name == "" {
+			step.Name = step.Tool
+		}
+		if seen[step.Name] {
+			return Script{}, fmt.Errorf("step name %s is used twice in script %s, name the steps that call the same tool", step.Name, path)
+		}
+		seen[step.Name] = true
+	}
+
+	return script, nil
+}
+
+// Run runs the tool calls of a script, against a gateway started for the script or a running one.
+func Run(ctx context.Context, scriptPath string, opts RunOptions) error {
+	script, err := ReadScript(scriptPath)
+	if err != nil {
+		return err
+	}
+
+	vars := maps.Clone(script.Vars)
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	overrides, err := parseVars(opts.Vars)
+	if err != nil {
+		return err
+	}
+	maps.Copy(vars, overrides)
+
+	var c *mcp.ClientSession
+	if opts.Session != "" {
+		c, err = connect(ctx, opts.Session)
+		if err != nil {
+			return fmt.Errorf("connecting to gateway %s: %w", opts.Session, err)
+		}
+	} else {
+		c, err = start(ctx, "2", scriptGatewayArgs(script, opts), opts.Verbose)
+		if err != nil {
+			return fmt.Errorf("starting client: %w", err)
+		}
+	}
+	defer c.Close()
+
+	return runScript(ctx, c, script, vars, os.Stdout)
+}
+
+// scriptGatewayArgs returns the arguments of the gateway started for a script.
+func scriptGatewayArgs(script Script, opts RunOptions) []string {
+	args := slices.Clone(opts.GatewayArgs)
+
+	profile := script.Profile
+	if opts.Profile != "" {
+		profile = opts.Profile
+	}
+	if profile != "" {
+		return append(args, "--profile="+profile)
+	}
+	for _, server := range script.Servers {
+		args = append(args, "--servers="+server)
+	}
+	return args
+}
+
+func runScript(ctx context.Context, c toolCaller, script Script, vars map[string]string, out io.Writer) error {
+	steps := map[string]any{}
+	data := map[string]any{
+		"vars":  vars,
+		"steps": steps,
+	}
+
+	var errs []error
+	var toolErrors bool
+	for i, step := range script.Steps {
+		arguments := maps.Clone(step.Arguments)
+		if arguments == nil {
+			arguments = map[string]any{}
+		}
+
+		err := applyVars(arguments, data)
+		var response *mcp.CallToolResult
+		var duration time.Duration
+		if err == nil {
+			start := time.Now()
+			response, err = c.CallTool(ctx, &mcp.CallToolParams{
+				Name:      step.Tool,
+				Arguments: arguments,
+			})
+			duration = time.Since(start)
+		}
+
+		switch {
+		case err != nil:
+			fmt.Fprintf(out, "- [%d/%d] %s: %s failed: %v\n", i+1, len(script.Steps), step.Name, step.Tool, err)
+			err = fmt.Errorf("step %s: %w", step.Name, err)
+		case response.IsError:
+			toolErrors = true
+			fmt.Fprintf(out, "- [%d/%d] %s: %s returned an error in %s\n%s\n", i+1, len(script.Steps), step.Name, step.Tool, duration, toText(response))
+			err = fmt.Errorf("step %s: tool %s returned an error", step.Name, step.Tool)
+		default:
+			fmt.Fprintf(out, "- [%d/%d] %s: %s took %s\n%s\n", i+1, len(script.Steps), step.Name, step.Tool, duration, toText(response))
+		}
+
+		if response != nil {
+			steps[step.Name] = map[string]any{
+				"text":       toText(response),
+				"structured": response.StructuredContent,
+				"error":      response.IsError,
+			}
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+			if !step.ContinueOnError {
+				break
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	err := errors.Join(errs...)
+	if toolErrors {
+		return cli.StatusError{Cause: err, Status: err.Error(), StatusCode: toolErrorExitCode}
+	}
+	return err
+}
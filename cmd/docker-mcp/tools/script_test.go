@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCaller struct {
+	calls   []*mcp.CallToolParams
+	results map[string]*mcp.CallToolResult
+}
+
+func (f *fakeCaller) CallTool(_ context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	f.calls = append(f.calls, params)
+	result, ok := f.results[params.Name]
+	if !ok {
+		return nil, errors.New("unknown tool " + params.Name)
+	}
+	return result, nil
+}
+
+func TestReadScript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.yaml")
+	writeFile(t, path, []byte(`profile: dev-tools
+steps:
+  - tool: search
+    arguments:
+      query: docker
+  - name: second
+    tool: search
+`))
+
+	script, err := ReadScript(path)
+	require.NoError(t, err)
+	assert.Equal(t, "dev-tools", script.Profile)
+	require.Len(t, script.Steps, 2)
+	assert.Equal(t, "search", script.Steps[0].Name)
+	assert.Equal(t, map[string]any{"query": "docker"}, script.Steps[0].Arguments)
+	assert.Equal(t, "second", script.Steps[1].Name)
+
+	writeFile(t, path, []byte(`steps:
+  - tool: search
+  - tool: search
+`))
+	_, err = ReadScript(path)
+	require.ErrorContains(t, err, "step name search is used twice")
+}
+
+func TestScriptGatewayArgs(t *testing.T) {
+	script := Script{Profile: "dev-tools", Servers: []string{"duckduckgo"}}
+	assert.Equal(t, []string{"--profile=dev-tools"}, scriptGatewayArgs(script, RunOptions{}))
+	assert.Equal(t, []string{"--verbose", "--profile=other"}, scriptGatewayArgs(script, RunOptions{Profile: "other", GatewayArgs: []string{"--verbose"}}))
+	assert.Equal(t, []string{"--servers=duckduckgo"}, scriptGatewayArgs(Script{Servers: []string{"duckduckgo"}}, RunOptions{}))
+}
+
+func TestRunScriptUsesPreviousResults(t *testing.T) {
+	caller := &fakeCaller{results: map[string]*mcp.CallToolResult{
+		"search": {
+			Content:           []mcp.Content{&mcp.TextContent{Text: "found"}},
+			StructuredContent: map[string]any{"url": "https://docker.com"},
+		},
+		"fetch": {
+			Content: []mcp.Content{&mcp.TextContent{Text: "page"}},
+		},
+	}}
+	script := Script{Steps: []Step{
+		{Name: "search", Tool: "search", Arguments: map[string]any{"query": "{{.vars.topic}}"}},
+		{Name: "fetch", Tool: "fetch", Arguments: map[string]any{"url": "{{.steps.search.structured.url}}", "note": "{{.steps.search.text}}"}},
+	}}
+
+	var out bytes.Buffer
+	err := runScript(t.Context(), caller, script, map[string]string{"topic": "docker"}, &out)
+	require.NoError(t, err)
+
+	require.Len(t, caller.calls, 2)
+	assert.Equal(t, map[string]any{"query": "docker"}, caller.calls[0].Arguments)
+	assert.Equal(t, map[string]any{"url": "https://docker.com", "note": "found"}, caller.calls[1].Arguments)
+	assert.Contains(t, out.String(), "[2/2] fetch: fetch took")
+}
+
+func TestRunScriptStopsOnError(t *testing.T) {
+	caller := &fakeCaller{results: map[string]*mcp.CallToolResult{
+		"broken": {IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "boom"}}},
+		"search": {Content: []mcp.Content{&mcp.TextContent{Text: "found"}}},
+	}}
+
+	var out bytes.Buffer
+	err := runScript(t.Context(), caller, Script{Steps: []Step{
+		{Name: "broken", Tool: "broken"},
+		{Name: "search", Tool: "search"},
+	}}, nil, &out)
+	var statusErr cli.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, toolErrorExitCode, statusErr.StatusCode)
+	assert.Len(t, caller.calls, 1)
+
+	// Unless the step says otherwise
+	caller.calls = nil
+	err = runScript(t.Context(), caller, Script{Steps: []Step{
+		{Name: "broken", Tool: "broken", ContinueOnError: true},
+		{Name: "search", Tool: "search"},
+	}}, nil, &out)
+	require.Error(t, err)
+	assert.Len(t, caller.calls, 2)
+}
@@ -2,8 +2,10 @@ package tools
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -37,3 +39,39 @@ func start(ctx context.Context, version string, gatewayArgs []string, verbose bo
 
 	return session, nil
 }
+
+// connect connects to a gateway running with the sse or streaming transport. MCP_GATEWAY_AUTH_TOKEN is used to authenticate.
+func connect(ctx context.Context, gatewayURL string) (*mcp.ClientSession, error) {
+	httpClient := &http.Client{
+		Transport: &bearerRoundTripper{
+			base:  http.DefaultTransport,
+			token: os.Getenv("MCP_GATEWAY_AUTH_TOKEN"),
+		},
+	}
+
+	var transport mcp.Transport
+	if strings.HasSuffix(strings.TrimSuffix(gatewayURL, "/"), "/sse") {
+		transport = &mcp.SSEClientTransport{Endpoint: gatewayURL, HTTPClient: httpClient}
+	} else {
+		transport = &mcp.StreamableClientTransport{Endpoint: gatewayURL, HTTPClient: httpClient}
+	}
+
+	c := mcp.NewClient(&mcp.Implementation{Name: "mcp-gateway-client", Version: "1.0.0"}, nil)
+	return c.Connect(ctx, transport, nil)
+}
+
+// bearerRoundTripper adds the gateway's auth token to the requests.
+type bearerRoundTripper struct {
+	base  http.RoundTripper
+	token string
+}
+
+func (t *bearerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.token == "" {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/docker/docker/api/types/volume"
@@ -323,7 +324,7 @@ func withSampleCatalog() option {
 // Unit tests for call
 
 func TestCallNoToolName(t *testing.T) {
-	err := Call(context.Background(), "2", []string{}, false, []string{})
+	err := Call(context.Background(), "2", []string{}, false, []string{}, CallOptions{})
 	require.Error(t, err)
 	assert.Equal(t, "no tool name provided", err.Error())
 }
@@ -352,6 +353,73 @@ func TestParseArgs(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestCallUnsupportedOutput(t *testing.T) {
+	err := Call(context.Background(), "2", []string{}, false, []string{"search"}, CallOptions{Output: "yaml"})
+	require.EqualError(t, err, `unsupported output "yaml", expected one of text, json, structured`)
+}
+
+func TestCallArguments(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "args.json")
+	writeFile(t, argsFile, []byte(`{"query": "docker", "limit": 10, "tags": ["a"]}`))
+
+	arguments, err := callArguments(argsFile, []string{"query=mcp"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"query": "mcp", "limit": float64(10), "tags": []any{"a"}}, arguments)
+
+	// From stdin
+	arguments, err = callArguments("-", nil, strings.NewReader(`{"query": "docker"}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"query": "docker"}, arguments)
+
+	_, err = callArguments("-", nil, strings.NewReader(`["docker"]`))
+	require.ErrorContains(t, err, "arguments must be a JSON object")
+}
+
+func TestApplyVars(t *testing.T) {
+	vars, err := parseVars([]string{"topic=docker", "owner=me"})
+	require.NoError(t, err)
+
+	arguments := map[string]any{
+		"query":  "about {{.topic}}",
+		"limit":  float64(10),
+		"nested": map[string]any{"repo": "{{.owner}}/{{.topic}}"},
+		"tags":   []any{"{{.topic}}", "mcp"},
+	}
+	require.NoError(t, applyVars(arguments, vars))
+	assert.Equal(t, map[string]any{
+		"query":  "about docker",
+		"limit":  float64(10),
+		"nested": map[string]any{"repo": "me/docker"},
+		"tags":   []any{"docker", "mcp"},
+	}, arguments)
+
+	err = applyVars(map[string]any{"query": "{{.unknown}}"}, vars)
+	require.ErrorContains(t, err, "argument query")
+
+	_, err = parseVars([]string{"topic"})
+	require.Error(t, err)
+}
+
+func TestFormatResult(t *testing.T) {
+	response := &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: `{"temperature":21}`}},
+		StructuredContent: map[string]any{"temperature": 21},
+	}
+
+	structured, err := formatResult(response, OutputStructured)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"temperature":21}`, structured)
+
+	full, err := formatResult(response, OutputJSON)
+	require.NoError(t, err)
+	assert.Contains(t, full, `"structuredContent"`)
+
+	// Falls back to the text content
+	structured, err = formatResult(&mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "sunny"}}}, OutputStructured)
+	require.NoError(t, err)
+	assert.Equal(t, "sunny", structured)
+}
+
 // Unit tests for list
 
 func TestToolDescription(t *testing.T) {
@@ -153,7 +153,12 @@ func GetWithOptions(ctx context.Context, useConfigured bool, additionalCatalogs
 	// Remove duplicates while preserving order
 	catalogPaths = removeDuplicates(catalogPaths)
 
-	return ReadFrom(ctx, catalogPaths)
+	catalog, err := ReadFrom(ctx, catalogPaths)
+	if err != nil {
+		return Catalog{}, err
+	}
+
+	return WithPluginServers(catalog), nil
 }
 
 // removeDuplicates removes duplicate strings while preserving order (first occurrence wins)
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -268,3 +269,45 @@ func setupOverlappingCatalogs(t *testing.T, homeDir string) {
 	err = os.WriteFile(filepath.Join(homeDir, "cli-catalog.yaml"), []byte(cliCatalog), 0o644)
 	require.NoError(t, err)
 }
+
+func TestConfiguredOAuthScopes(t *testing.T) {
+	server := Server{OAuth: &OAuth{Scopes: []string{"read", "write"}}}
+
+	assert.Equal(t, []string{"read", "write"}, server.ConfiguredOAuthScopes(nil))
+	assert.Equal(t, []string{"read"}, server.ConfiguredOAuthScopes([]string{"read"}))
+	assert.Nil(t, (&Server{}).ConfiguredOAuthScopes(nil))
+}
+
+func TestToolset(t *testing.T) {
+	toolsets := Toolsets{
+		"pulls":  {"create_pull_request", "list_commits"},
+		"issues": {"create_issue", "list_issues"},
+		"repos":  {"list_commits"},
+	}
+
+	assert.Equal(t, "issues", toolsets.Toolset("create_issue"))
+	assert.Equal(t, "pulls", toolsets.Toolset("list_commits"))
+	assert.Empty(t, toolsets.Toolset("search_code"))
+	assert.Empty(t, Toolsets(nil).Toolset("create_issue"))
+}
+
+func TestDeprecatedToolExpired(t *testing.T) {
+	now := time.Date(2026, 3, 15, 18, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		until   string
+		expired bool
+	}{
+		{until: "", expired: false},
+		{until: "2026-03-15", expired: false},
+		{until: "2026-03-14", expired: true},
+		{until: "2027-01-01", expired: false},
+	} {
+		expired, err := DeprecatedTool{ReplacedBy: "search", Until: test.until}.Expired(now)
+		require.NoError(t, err)
+		assert.Equal(t, test.expired, expired, test.until)
+	}
+
+	_, err := DeprecatedTool{ReplacedBy: "search", Until: "next month"}.Expired(now)
+	require.Error(t, err)
+}
@@ -0,0 +1,47 @@
+package catalog
+
+import (
+	"maps"
+	"sync"
+)
+
+// TypePlugin is the type of the servers compiled in the gateway, that run in-process instead of in containers.
+// See pkg/plugins.
+const TypePlugin = "plugin"
+
+var (
+	pluginServersMu sync.RWMutex
+	pluginServers   = map[string]Server{}
+)
+
+// RegisterPluginServer adds the definition of a server compiled in the gateway to the configured catalogs.
+func RegisterPluginServer(name string, server Server) {
+	pluginServersMu.Lock()
+	defer pluginServersMu.Unlock()
+
+	server.Type = TypePlugin
+	pluginServers[name] = server
+}
+
+// WithPluginServers returns the catalog along with the servers compiled in the gateway.
+// The servers of the catalog take precedence over plugins of the same name.
+func WithPluginServers(catalog Catalog) Catalog {
+	pluginServersMu.RLock()
+	defer pluginServersMu.RUnlock()
+
+	if len(pluginServers) == 0 {
+		return catalog
+	}
+
+	servers := maps.Clone(catalog.Servers)
+	if servers == nil {
+		servers = map[string]Server{}
+	}
+	for name, server := range pluginServers {
+		if _, exists := servers[name]; !exists {
+			servers[name] = server
+		}
+	}
+	catalog.Servers = servers
+	return catalog
+}
@@ -0,0 +1,262 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/mcp-gateway/pkg/user"
+)
+
+// PopularityMaxAge is how long the pulls and stars refreshed from Docker Hub and GitHub are used before they're refreshed again.
+const PopularityMaxAge = 24 * time.Hour
+
+// The APIs the popularity is read from. Variables so that tests can use fake ones.
+var (
+	dockerHubURL = "https://hub.docker.com"
+	githubAPIURL = "https://api.github.com"
+)
+
+// Popularity of a server: the pulls and stars of its image on Docker Hub, and the stars of its source repository.
+type Popularity struct {
+	Pulls       int `json:"pulls,omitempty"`
+	Stars       int `json:"stars,omitempty"`
+	GithubStars int `json:"githubStars,omitempty"`
+}
+
+// PopularityCache is the popularity of the catalog servers, by server name, cached in ~/.docker/mcp/popularity.json.
+type PopularityCache struct {
+	UpdatedAt time.Time             `json:"updatedAt"`
+	Servers   map[string]Popularity `json:"servers"`
+}
+
+// Stale tells whether the cache should be refreshed.
+func (c PopularityCache) Stale() bool {
+	return time.Since(c.UpdatedAt) > PopularityMaxAge
+}
+
+// Apply overrides the popularity metadata of the servers with the cached values.
+func (c PopularityCache) Apply(servers map[string]Server) {
+	for name, popularity := range c.Servers {
+		server, ok := servers[name]
+		if !ok {
+			continue
+		}
+		server.Metadata = withPopularity(server.Metadata, popularity)
+		servers[name] = server
+	}
+}
+
+func withPopularity(metadata *Metadata, popularity Popularity) *Metadata {
+	updated := Metadata{}
+	if metadata != nil {
+		updated = *metadata
+	}
+	if popularity.Pulls > 0 {
+		updated.Pulls = popularity.Pulls
+	}
+	if popularity.Stars > 0 {
+		updated.Stars = popularity.Stars
+	}
+	if popularity.GithubStars > 0 {
+		updated.GithubStars = popularity.GithubStars
+	}
+	return &updated
+}
+
+// PopularityScore ranks servers by popularity. Pulls and stars are counted on a log scale,
+// so that a few very popular servers don't hide all the others.
+func (s *Server) PopularityScore() float64 {
+	if s.Metadata == nil {
+		return 0
+	}
+	return math.Log10(1+float64(s.Metadata.Pulls)) +
+		math.Log10(1+float64(s.Metadata.Stars)) +
+		math.Log10(1+float64(s.Metadata.GithubStars))
+}
+
+func popularityPath() (string, error) {
+	homeDir, err := user.HomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".docker", "mcp", "popularity.json"), nil
+}
+
+// ReadPopularity reads the cached popularity. It's empty, and stale, if it was never refreshed.
+func ReadPopularity() (PopularityCache, error) {
+	path, err := popularityPath()
+	if err != nil {
+		return PopularityCache{}, err
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return PopularityCache{Servers: map[string]Popularity{}}, nil
+		}
+		return PopularityCache{}, err
+	}
+
+	var cache PopularityCache
+	if err := json.Unmarshal(buf, &cache); err != nil {
+		return PopularityCache{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if cache.Servers == nil {
+		cache.Servers = map[string]Popularity{}
+	}
+	return cache, nil
+}
+
+// WritePopularity caches the popularity.
+func WritePopularity(cache PopularityCache) error {
+	path, err := popularityPath()
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf, 0o644)
+}
+
+// RefreshPopularity reads the popularity of servers from Docker Hub and GitHub.
+// The previous values of a server are kept when they can't be read.
+// GITHUB_TOKEN is used, if set, to avoid the rate limits of the GitHub API.
+func RefreshPopularity(ctx context.Context, previous PopularityCache, servers map[string]Server) PopularityCache {
+	var mu sync.Mutex
+	refreshed := PopularityCache{
+		UpdatedAt: time.Now(),
+		Servers:   make(map[string]Popularity, len(servers)),
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(8)
+	for name, server := range servers {
+		g.Go(func() error {
+			popularity := previous.Servers[name]
+			if repository := dockerHubRepository(server.Image); repository != "" {
+				if pulls, stars, err := readDockerHubPopularity(ctx, repository); err == nil {
+					popularity.Pulls = pulls
+					popularity.Stars = stars
+				}
+			}
+			if repository := githubRepository(server.Source); repository != "" {
+				if stars, err := readGithubStars(ctx, repository); err == nil {
+					popularity.GithubStars = stars
+				}
+			}
+
+			if popularity != (Popularity{}) {
+				mu.Lock()
+				refreshed.Servers[name] = popularity
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return refreshed
+}
+
+// dockerHubRepository returns the namespace/name of an image hosted on Docker Hub, without its tag or digest.
+func dockerHubRepository(image string) string {
+	if image == "" {
+		return ""
+	}
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	parts := strings.Split(image, "/")
+	switch {
+	case len(parts) == 1:
+		return "library/" + image
+	case parts[0] == "docker.io" || parts[0] == "index.docker.io":
+		return dockerHubRepository(strings.Join(parts[1:], "/"))
+	case strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost":
+		// Another registry
+		return ""
+	}
+	return image
+}
+
+// githubRepository returns the owner/name of a GitHub repository URL.
+func githubRepository(source string) string {
+	u, err := url.Parse(source)
+	if err != nil || u.Host != "github.com" {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "/" + strings.TrimSuffix(parts[1], ".git")
+}
+
+func readDockerHubPopularity(ctx context.Context, repository string) (int, int, error) {
+	var response struct {
+		PullCount int `json:"pull_count"`
+		StarCount int `json:"star_count"`
+	}
+	if err := getPopularity(ctx, dockerHubURL+"/v2/repositories/"+repository+"/", nil, &response); err != nil {
+		return 0, 0, err
+	}
+	return response.PullCount, response.StarCount, nil
+}
+
+func readGithubStars(ctx context.Context, repository string) (int, error) {
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+
+	var response struct {
+		StargazersCount int `json:"stargazers_count"`
+	}
+	if err := getPopularity(ctx, githubAPIURL+"/repos/"+repository, headers, &response); err != nil {
+		return 0, err
+	}
+	return response.StargazersCount, nil
+}
+
+func getPopularity(ctx context.Context, url string, headers map[string]string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("reading %s: HTTP %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
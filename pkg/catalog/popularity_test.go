@@ -0,0 +1,106 @@
+package catalog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerHubRepository(t *testing.T) {
+	assert.Equal(t, "mcp/fetch", dockerHubRepository("mcp/fetch"))
+	assert.Equal(t, "mcp/fetch", dockerHubRepository("mcp/fetch:latest"))
+	assert.Equal(t, "mcp/fetch", dockerHubRepository("docker.io/mcp/fetch@sha256:abc"))
+	assert.Equal(t, "library/alpine", dockerHubRepository("alpine:3"))
+	assert.Empty(t, dockerHubRepository("ghcr.io/github/github-mcp-server"))
+	assert.Empty(t, dockerHubRepository("localhost:5000/fetch"))
+	assert.Empty(t, dockerHubRepository(""))
+}
+
+func TestGithubRepository(t *testing.T) {
+	assert.Equal(t, "docker/mcp-gateway", githubRepository("https://github.com/docker/mcp-gateway"))
+	assert.Equal(t, "docker/mcp-gateway", githubRepository("https://github.com/docker/mcp-gateway.git"))
+	assert.Equal(t, "docker/mcp-gateway", githubRepository("https://github.com/docker/mcp-gateway/tree/main/cmd"))
+	assert.Empty(t, githubRepository("https://gitlab.com/docker/mcp-gateway"))
+	assert.Empty(t, githubRepository("https://github.com/docker"))
+}
+
+func TestRefreshPopularity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/repositories/mcp/fetch/":
+			_, _ = w.Write([]byte(`{"pull_count": 12000, "star_count": 30}`))
+		case "/repos/example/fetch":
+			_, _ = w.Write([]byte(`{"stargazers_count": 450}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	setURL(t, &dockerHubURL, server.URL)
+	setURL(t, &githubAPIURL, server.URL)
+
+	previous := PopularityCache{Servers: map[string]Popularity{
+		"unknown": {Pulls: 10},
+	}}
+	servers := map[string]Server{
+		"fetch":   {Image: "mcp/fetch:latest", Source: "https://github.com/example/fetch"},
+		"unknown": {Image: "mcp/unknown"},
+		"remote":  {Type: "remote"},
+	}
+
+	cache := RefreshPopularity(t.Context(), previous, servers)
+
+	assert.False(t, cache.Stale())
+	assert.Equal(t, map[string]Popularity{
+		"fetch":   {Pulls: 12000, Stars: 30, GithubStars: 450},
+		"unknown": {Pulls: 10},
+	}, cache.Servers)
+}
+
+func TestPopularityCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cache, err := ReadPopularity()
+	require.NoError(t, err)
+	assert.True(t, cache.Stale())
+	assert.Empty(t, cache.Servers)
+
+	cache = PopularityCache{
+		UpdatedAt: time.Now(),
+		Servers:   map[string]Popularity{"fetch": {Pulls: 100}},
+	}
+	require.NoError(t, WritePopularity(cache))
+
+	cache, err = ReadPopularity()
+	require.NoError(t, err)
+	assert.False(t, cache.Stale())
+
+	servers := map[string]Server{
+		"fetch": {Metadata: &Metadata{Pulls: 1, GithubStars: 5, Category: "web"}},
+		"time":  {},
+	}
+	cache.Apply(servers)
+	assert.Equal(t, &Metadata{Pulls: 100, GithubStars: 5, Category: "web"}, servers["fetch"].Metadata)
+	assert.Nil(t, servers["time"].Metadata)
+}
+
+func TestPopularityScore(t *testing.T) {
+	popular := Server{Metadata: &Metadata{Pulls: 100000, GithubStars: 1000}}
+	niche := Server{Metadata: &Metadata{Pulls: 100}}
+	unknown := Server{}
+
+	assert.Greater(t, popular.PopularityScore(), niche.PopularityScore())
+	assert.Greater(t, niche.PopularityScore(), unknown.PopularityScore())
+	assert.Zero(t, unknown.PopularityScore())
+}
+
+func setURL(t *testing.T, variable *string, url string) {
+	t.Helper()
+	previous := *variable
+	*variable = url
+	t.Cleanup(func() { *variable = previous })
+}
@@ -0,0 +1,64 @@
+package catalog
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Types of packages that command servers can run.
+const (
+	PackageTypeNPM  = "npm"
+	PackageTypePyPI = "pypi"
+)
+
+// Images in which command servers are run. The package is installed when the container starts.
+const (
+	NodeSandboxImage   = "node:22-alpine"
+	PythonSandboxImage = "ghcr.io/astral-sh/uv:python3.12-alpine"
+)
+
+// Package is a node or python package that a command server runs, instead of an image.
+type Package struct {
+	RegistryType string `yaml:"registryType" json:"registryType"`
+	Identifier   string `yaml:"identifier" json:"identifier"`
+	Version      string `yaml:"version,omitempty" json:"version,omitempty"`
+}
+
+// IsCommandServer returns true if the server runs a package in a sandbox image.
+func (s *Server) IsCommandServer() bool {
+	return s.Type == "command" && s.Package != nil
+}
+
+// Sandboxed turns a command server into a server that runs in the sandbox image of its package type.
+// The package is installed and started by npx or uvx, followed by the server's command as arguments.
+func (s Server) Sandboxed() (Server, error) {
+	if s.Package == nil || s.Package.Identifier == "" {
+		return s, fmt.Errorf("command server %s has no package", s.Name)
+	}
+
+	sandboxed := s
+	switch s.Package.RegistryType {
+	case PackageTypeNPM:
+		pkg := s.Package.Identifier
+		if s.Package.Version != "" {
+			pkg += "@" + s.Package.Version
+		}
+		sandboxed.Image = NodeSandboxImage
+		sandboxed.Command = append([]string{"npx", "--yes", pkg}, s.Command...)
+		sandboxed.Env = append(slices.Clone(s.Env), Env{Name: "NPM_CONFIG_UPDATE_NOTIFIER", Value: "false"})
+		if sandboxed.User == "" {
+			sandboxed.User = "node"
+		}
+	case PackageTypePyPI:
+		pkg := s.Package.Identifier
+		if s.Package.Version != "" {
+			pkg += "==" + s.Package.Version
+		}
+		sandboxed.Image = PythonSandboxImage
+		sandboxed.Command = append([]string{"uvx", pkg}, s.Command...)
+	default:
+		return s, fmt.Errorf("command server %s: unsupported package type %q, expected %s or %s", s.Name, s.Package.RegistryType, PackageTypeNPM, PackageTypePyPI)
+	}
+
+	return sandboxed, nil
+}
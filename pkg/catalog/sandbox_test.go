@@ -0,0 +1,67 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxedNPM(t *testing.T) {
+	server := Server{
+		Name:    "brave",
+		Type:    "command",
+		Package: &Package{RegistryType: PackageTypeNPM, Identifier: "@modelcontextprotocol/server-brave-search", Version: "0.6.2"},
+		Command: []string{"--verbose"},
+		Env:     []Env{{Name: "LOG_LEVEL", Value: "debug"}},
+	}
+
+	sandboxed, err := server.Sandboxed()
+	require.NoError(t, err)
+
+	assert.Equal(t, NodeSandboxImage, sandboxed.Image)
+	assert.Equal(t, []string{"npx", "--yes", "@modelcontextprotocol/server-brave-search@0.6.2", "--verbose"}, sandboxed.Command)
+	assert.Equal(t, "node", sandboxed.User)
+	assert.Contains(t, sandboxed.Env, Env{Name: "LOG_LEVEL", Value: "debug"})
+	assert.Len(t, server.Env, 1, "the original server is left untouched")
+}
+
+func TestSandboxedPyPI(t *testing.T) {
+	server := Server{
+		Name:    "fetch",
+		Type:    "command",
+		Package: &Package{RegistryType: PackageTypePyPI, Identifier: "mcp-server-fetch", Version: "2025.4.7"},
+	}
+
+	sandboxed, err := server.Sandboxed()
+	require.NoError(t, err)
+
+	assert.Equal(t, PythonSandboxImage, sandboxed.Image)
+	assert.Equal(t, []string{"uvx", "mcp-server-fetch==2025.4.7"}, sandboxed.Command)
+	assert.Empty(t, sandboxed.User)
+}
+
+func TestSandboxedUnversioned(t *testing.T) {
+	server := Server{
+		Type:    "command",
+		Package: &Package{RegistryType: PackageTypeNPM, Identifier: "some-server"},
+	}
+
+	sandboxed, err := server.Sandboxed()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"npx", "--yes", "some-server"}, sandboxed.Command)
+}
+
+func TestSandboxedErrors(t *testing.T) {
+	_, err := Server{Name: "nuget", Type: "command", Package: &Package{RegistryType: "nuget", Identifier: "Some.Server"}}.Sandboxed()
+	require.ErrorContains(t, err, `unsupported package type "nuget"`)
+
+	_, err = Server{Name: "empty", Type: "command"}.Sandboxed()
+	require.ErrorContains(t, err, "has no package")
+}
+
+func TestIsCommandServer(t *testing.T) {
+	assert.True(t, (&Server{Type: "command", Package: &Package{}}).IsCommandServer())
+	assert.False(t, (&Server{Type: "command"}).IsCommandServer())
+	assert.False(t, (&Server{Type: "server", Image: "mcp/fetch"}).IsCommandServer())
+}
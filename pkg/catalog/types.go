@@ -1,5 +1,12 @@
 package catalog
 
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
 type Catalog struct {
 	Servers map[string]Server
 }
@@ -14,6 +21,10 @@ type topLevel struct {
 
 // MCP Servers
 
+// TypeWasm is the type of the servers compiled to WebAssembly, that run in an embedded runtime instead of in containers.
+// See pkg/wasm.
+const TypeWasm = "wasm"
+
 type Server struct {
 	Name           string    `yaml:"name,omitempty" json:"name,omitempty"`
 	Type           string    `yaml:"type" json:"type"`
@@ -35,7 +46,79 @@ type Server struct {
 	Tools          []Tool    `yaml:"tools,omitempty" json:"tools,omitempty"`
 	Config         []any     `yaml:"config,omitempty" json:"config,omitempty"`
 	Prefix         string    `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	ToolCosts      ToolCosts `yaml:"toolCosts,omitempty" json:"toolCosts,omitempty"`
 	Metadata       *Metadata `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	Package        *Package  `yaml:"package,omitempty" json:"package,omitempty"`
+	// Framing is either strict (default) or lenient. Lenient skips the lines a stdio server writes to stdout that are not JSON-RPC.
+	Framing string `yaml:"framing,omitempty" json:"framing,omitempty"`
+	// ToolTransforms shrink the results of tools before they reach the model.
+	ToolTransforms ToolTransforms `yaml:"toolTransforms,omitempty" json:"toolTransforms,omitempty"`
+	// Platform of the image to run, like linux/amd64. Defaults to the platform of the Docker engine.
+	Platform string `yaml:"platform,omitempty" json:"platform,omitempty"`
+	// PullPolicy overrides the gateway's pull policy for the image: always, if-not-present or never.
+	PullPolicy string `yaml:"pullPolicy,omitempty" json:"pullPolicy,omitempty"`
+	// Source is the URL of the source repository. The stars of GitHub repositories are refreshed into the metadata.
+	Source string `yaml:"source,omitempty" json:"source,omitempty"`
+	// Toolsets group the tools of the server, like issues, pulls or actions for GitHub.
+	Toolsets Toolsets `yaml:"toolsets,omitempty" json:"toolsets,omitempty"`
+	// Instructions replace the instructions the server returns on initialize, in those the gateway sends to clients.
+	Instructions string `yaml:"instructions,omitempty" json:"instructions,omitempty"`
+	// Resources limit the container of the server, overriding the gateway's --cpus and --memory.
+	Resources *Resources `yaml:"resources,omitempty" json:"resources,omitempty"`
+	// DeprecatedTools keep the old names of renamed tools working, by old tool name.
+	DeprecatedTools DeprecatedTools `yaml:"deprecatedTools,omitempty" json:"deprecatedTools,omitempty"`
+	// Module is the WebAssembly module of a server of type wasm: an absolute path, ./relative to the working directory, or relative to ~/.docker/mcp.
+	Module string `yaml:"module,omitempty" json:"module,omitempty"`
+}
+
+// ToolCosts weighs the cost of calling each tool, by tool name. Calls are charged to session budgets.
+type ToolCosts map[string]float64
+
+// Toolsets are groups of tools, by group name. Profiles can enable or disable whole groups.
+type Toolsets map[string][]string
+
+// Toolset returns the name of the group the tool belongs to, or an empty string if it's in none.
+// A tool listed in several groups belongs to the first one, in alphabetical order.
+func (t Toolsets) Toolset(toolName string) string {
+	for _, name := range slices.Sorted(maps.Keys(t)) {
+		if slices.Contains(t[name], toolName) {
+			return name
+		}
+	}
+	return ""
+}
+
+// ToolTransforms are jq-style expressions applied to the JSON results of tools, by tool name.
+type ToolTransforms map[string]string
+
+// Resources are the limits of the container of a server, in the units of docker run.
+type Resources struct {
+	// Cpus is the number of CPUs, like 0.5.
+	Cpus string `yaml:"cpus,omitempty" json:"cpus,omitempty"`
+	// Memory is the memory limit, like 512m.
+	Memory string `yaml:"memory,omitempty" json:"memory,omitempty"`
+}
+
+// DeprecatedTools maps the old names of renamed tools to their new names.
+type DeprecatedTools map[string]DeprecatedTool
+
+// DeprecatedTool is an old tool name that forwards calls to the new one until the end of its grace period.
+type DeprecatedTool struct {
+	ReplacedBy string `yaml:"replacedBy" json:"replacedBy"`
+	// Until is the last day, formatted as YYYY-MM-DD, on which the old name is still exposed. Empty means forever.
+	Until string `yaml:"until,omitempty" json:"until,omitempty"`
+}
+
+// Expired tells whether the grace period of the old name is over.
+func (d DeprecatedTool) Expired(now time.Time) (bool, error) {
+	if d.Until == "" {
+		return false, nil
+	}
+	until, err := time.ParseInLocation(time.DateOnly, d.Until, now.Location())
+	if err != nil {
+		return false, fmt.Errorf("invalid until date %q: %w", d.Until, err)
+	}
+	return !now.Before(until.AddDate(0, 0, 1)), nil
 }
 
 type Metadata struct {
@@ -56,6 +139,18 @@ func (s *Server) IsRemoteOAuthServer() bool {
 	return s.Type == "remote" && s.IsOAuthServer()
 }
 
+// ConfiguredOAuthScopes returns the OAuth scopes the server needs: the override if set,
+// otherwise the scopes declared by the catalog. Returns nil if none are configured.
+func (s *Server) ConfiguredOAuthScopes(override []string) []string {
+	if len(override) > 0 {
+		return override
+	}
+	if s.OAuth != nil && len(s.OAuth.Scopes) > 0 {
+		return s.OAuth.Scopes
+	}
+	return nil
+}
+
 type Secret struct {
 	Name string `yaml:"name" json:"name"`
 	Env  string `yaml:"env" json:"env"`
@@ -70,11 +165,24 @@ type Remote struct {
 	URL       string            `yaml:"url,omitempty" json:"url,omitempty"`
 	Transport string            `yaml:"transport_type,omitempty" json:"transport_type,omitempty"`
 	Headers   map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	// ForwardHeaders lists the HTTP headers of the client's request that are passed through to the remote server.
+	// Other headers are stripped.
+	ForwardHeaders []string `yaml:"forward_headers,omitempty" json:"forward_headers,omitempty"`
 }
 
 type OAuth struct {
 	Providers []OAuthProvider `yaml:"providers,omitempty" json:"providers,omitempty"`
 	Scopes    []string        `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	// ClientCredentials is set for machine-to-machine remotes that use the client_credentials grant
+	ClientCredentials *OAuthClientCredentials `yaml:"client_credentials,omitempty" json:"client_credentials,omitempty"`
+}
+
+// OAuthClientCredentials configures the client_credentials grant.
+// ClientID and ClientSecret can reference the server's secrets, eg. ${ACME_CLIENT_SECRET}
+type OAuthClientCredentials struct {
+	TokenURL     string `yaml:"token_url" json:"token_url"`
+	ClientID     string `yaml:"client_id" json:"client_id"`
+	ClientSecret string `yaml:"client_secret" json:"client_secret"`
 }
 
 type OAuthProvider struct {
@@ -110,6 +218,7 @@ type Tool struct {
 	Description string     `yaml:"description" json:"description"`
 	Container   Container  `yaml:"container" json:"container"`
 	Parameters  Parameters `yaml:"parameters" json:"parameters"`
+	Cost        float64    `yaml:"cost,omitempty" json:"cost,omitempty"`
 }
 
 type Parameters struct {
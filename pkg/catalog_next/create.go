@@ -76,16 +76,21 @@ func createCatalogFromWorkingSet(ctx context.Context, dao db.DAO, workingSetID s
 
 	workingSet := workingset.NewFromDb(dbWorkingSet)
 
-	servers := make([]Server, len(workingSet.Servers))
-	for i, server := range workingSet.Servers {
-		servers[i] = Server{
+	servers := make([]Server, 0, len(workingSet.Servers))
+	for _, server := range workingSet.Servers {
+		// Gateways are mounted in a profile with their own token, they're not shared in catalogs
+		if server.Type == workingset.ServerTypeGateway {
+			fmt.Printf("Skipping gateway server %s\n", server.Endpoint)
+			continue
+		}
+		servers = append(servers, Server{
 			Type:     server.Type,
 			Tools:    server.Tools,
 			Source:   server.Source,
 			Image:    server.Image,
 			Endpoint: server.Endpoint,
 			Snapshot: server.Snapshot,
-		}
+		})
 	}
 
 	return Catalog{
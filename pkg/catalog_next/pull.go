@@ -11,8 +11,8 @@ import (
 	"github.com/docker/mcp-gateway/pkg/workingset"
 )
 
-func Pull(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string) error {
-	catalog, err := pullCatalog(ctx, dao, ociService, refStr)
+func Pull(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string, verify VerifyOptions) error {
+	catalog, err := pullCatalog(ctx, dao, ociService, refStr, verify)
 	if err != nil {
 		return err
 	}
@@ -22,16 +22,56 @@ func Pull(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string
 	return nil
 }
 
-func pullCatalog(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string) (*db.Catalog, error) {
+func pullCatalog(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string, verify VerifyOptions) (*db.Catalog, error) {
+	catalog, err := readOCICatalog(ctx, dao, ociService, refStr, verify)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := catalog.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid catalog: %w", err)
+	}
+
+	dbCatalog, err := catalog.ToDb()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert catalog to db: %w", err)
+	}
+
+	err = dao.UpsertCatalog(ctx, dbCatalog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create catalog: %w", err)
+	}
+
+	return &dbCatalog, nil
+}
+
+// readOCICatalog reads a catalog from an OCI registry, verifying its signature if the options or the settings
+// ask for it, and resolves the snapshots of its servers.
+func readOCICatalog(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string, verify VerifyOptions) (Catalog, error) {
 	ref, err := name.ParseReference(refStr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse OCI reference %s: %w", refStr, err)
+		return Catalog{}, fmt.Errorf("failed to parse OCI reference %s: %w", refStr, err)
 	}
 	source := oci.FullName(ref)
 
-	catalogArtifact, err := oci.ReadArtifact[CatalogArtifact](refStr, MCPCatalogArtifactType)
+	verify, err = verify.withSettings(ctx, dao)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read OCI catalog: %w", err)
+		return Catalog{}, err
+	}
+
+	// Read the very artifact whose signature was verified, even if the tag moves in between
+	artifactRef := refStr
+	if verify.verifying() {
+		digest, err := verifyCatalogSignature(ctx, ref, verify)
+		if err != nil {
+			return Catalog{}, err
+		}
+		artifactRef = digest.String()
+	}
+
+	catalogArtifact, err := oci.ReadArtifact[CatalogArtifact](artifactRef, MCPCatalogArtifactType)
+	if err != nil {
+		return Catalog{}, fmt.Errorf("failed to read OCI catalog: %w", err)
 	}
 
 	catalog := Catalog{
@@ -49,7 +89,7 @@ func pullCatalog(ctx context.Context, dao db.DAO, ociService oci.Service, refStr
 		case workingset.ServerTypeImage:
 			serverSnapshot, err := workingset.ResolveImageSnapshot(ctx, ociService, catalog.Servers[i].Image)
 			if err != nil {
-				return nil, fmt.Errorf("failed to resolve image snapshot: %w", err)
+				return Catalog{}, fmt.Errorf("failed to resolve image snapshot: %w", err)
 			}
 			catalog.Servers[i].Snapshot = serverSnapshot
 		case workingset.ServerTypeRegistry:
@@ -57,19 +97,5 @@ func pullCatalog(ctx context.Context, dao db.DAO, ociService oci.Service, refStr
 		}
 	}
 
-	if err := catalog.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid catalog: %w", err)
-	}
-
-	dbCatalog, err := catalog.ToDb()
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert catalog to db: %w", err)
-	}
-
-	err = dao.UpsertCatalog(ctx, dbCatalog)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create catalog: %w", err)
-	}
-
-	return &dbCatalog, nil
+	return catalog, nil
 }
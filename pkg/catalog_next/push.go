@@ -12,7 +12,8 @@ import (
 	"github.com/docker/mcp-gateway/pkg/oci"
 )
 
-func Push(ctx context.Context, dao db.DAO, refStr string) error {
+// Push pushes a catalog as an OCI artifact, signed with the cosign private key at signKey if set.
+func Push(ctx context.Context, dao db.DAO, refStr string, signKey string) error {
 	ref, err := name.ParseReference(refStr)
 	if err != nil {
 		return fmt.Errorf("failed to parse reference: %w", err)
@@ -41,5 +42,12 @@ func Push(ctx context.Context, dao db.DAO, refStr string) error {
 
 	fmt.Printf("Pushed catalog to %s@sha256:%s\n", oci.FullName(ref), hash)
 
+	if signKey != "" {
+		if err := signCatalog(ctx, ref.Context().Digest("sha256:"+hash), signKey); err != nil {
+			return err
+		}
+		fmt.Printf("Signed catalog %s@sha256:%s\n", oci.FullName(ref), hash)
+	}
+
 	return nil
 }
@@ -37,7 +37,7 @@ func Show(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string
 
 	if pullOption == PullOptionAlways {
 		fmt.Fprintf(os.Stderr, "Pulling catalog %s...\n", refStr)
-		_, err := pullCatalog(ctx, dao, ociService, refStr)
+		_, err := pullCatalog(ctx, dao, ociService, refStr, VerifyOptions{})
 		if err != nil {
 			return fmt.Errorf("failed to pull catalog %s: %w", refStr, err)
 		}
@@ -46,7 +46,7 @@ func Show(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string
 	dbCatalog, err := dao.GetCatalog(ctx, refStr)
 	if err != nil && errors.Is(err, sql.ErrNoRows) && (pullOption == PullOptionMissing || pullOption == PullOptionDuration) {
 		fmt.Fprintf(os.Stderr, "Pulling catalog %s (missing)...\n", refStr)
-		_, err = pullCatalog(ctx, dao, ociService, refStr)
+		_, err = pullCatalog(ctx, dao, ociService, refStr, VerifyOptions{})
 		if err != nil {
 			return fmt.Errorf("failed to pull missing catalog %s: %w", refStr, err)
 		}
@@ -64,7 +64,7 @@ func Show(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string
 
 	if pullOption == PullOptionDuration && dbCatalog.LastUpdated != nil && time.Since(*dbCatalog.LastUpdated) > pullInterval {
 		fmt.Fprintf(os.Stderr, "Pulling catalog %s... (last update was %s ago)\n", refStr, time.Since(*dbCatalog.LastUpdated).Round(time.Second))
-		_, err := pullCatalog(ctx, dao, ociService, refStr)
+		_, err := pullCatalog(ctx, dao, ociService, refStr, VerifyOptions{})
 		if err != nil {
 			return fmt.Errorf("failed to pull catalog %s: %w", refStr, err)
 		}
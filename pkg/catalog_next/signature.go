@@ -0,0 +1,104 @@
+package catalognext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/signatures"
+)
+
+const (
+	// SettingVerifyKey is the setting with the path to the cosign public key that pulled catalogs are verified with.
+	SettingVerifyKey = "catalog.verify-key"
+	// SettingRequireSignature is the setting that refuses to pull catalogs without a valid signature.
+	SettingRequireSignature = "catalog.require-signature"
+)
+
+// VerifyOptions tells how the signatures of the pulled catalogs are verified, on top of the catalog settings.
+type VerifyOptions struct {
+	// Key is the path to a cosign public key. Defaults to the catalog.verify-key setting.
+	Key string
+	// RequireSignature refuses the catalogs without a valid signature, as does the catalog.require-signature setting.
+	RequireSignature bool
+}
+
+// withSettings completes the options with the catalog settings.
+func (o VerifyOptions) withSettings(ctx context.Context, settings db.SettingsDAO) (VerifyOptions, error) {
+	if o.Key == "" {
+		key, err := settings.GetSetting(ctx, SettingVerifyKey)
+		if err != nil {
+			return o, fmt.Errorf("failed to read %s setting: %w", SettingVerifyKey, err)
+		}
+		o.Key = key
+	}
+
+	if !o.RequireSignature {
+		required, err := settings.GetSetting(ctx, SettingRequireSignature)
+		if err != nil {
+			return o, fmt.Errorf("failed to read %s setting: %w", SettingRequireSignature, err)
+		}
+		o.RequireSignature = required == "on"
+	}
+
+	return o, nil
+}
+
+// verifying tells whether catalogs have to be verified at all.
+func (o VerifyOptions) verifying() bool {
+	return o.Key != "" || o.RequireSignature
+}
+
+// verifyCatalogSignature resolves a catalog reference to the digest whose signature is verified.
+// Unsigned catalogs are only accepted, with a warning, when no signature is required.
+func verifyCatalogSignature(ctx context.Context, ref name.Reference, opts VerifyOptions) (name.Digest, error) {
+	if opts.Key == "" {
+		return name.Digest{}, fmt.Errorf("a public key is required to verify the signature of catalog %s, use --key or the %s setting", ref, SettingVerifyKey)
+	}
+
+	publicKey, err := os.ReadFile(opts.Key)
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	desc, err := remote.Head(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx))
+	if err != nil {
+		return name.Digest{}, fmt.Errorf("failed to resolve catalog %s: %w", ref, err)
+	}
+	digest := ref.Context().Digest(desc.Digest.String())
+
+	err = signatures.VerifyWithKey(ctx, digest, publicKey)
+	switch {
+	case errors.Is(err, signatures.ErrNotSigned) && !opts.RequireSignature:
+		fmt.Fprintf(os.Stderr, "Warning: catalog %s is not signed\n", ref)
+	case errors.Is(err, signatures.ErrNotSigned):
+		return name.Digest{}, fmt.Errorf("catalog %s is not signed and a signature is required", ref)
+	case err != nil:
+		return name.Digest{}, fmt.Errorf("failed to verify the signature of catalog %s: %w", ref, err)
+	default:
+		fmt.Fprintf(os.Stderr, "Verified the signature of catalog %s\n", ref)
+	}
+
+	return digest, nil
+}
+
+// signCatalog signs a pushed catalog with a cosign private key, decrypted with the COSIGN_PASSWORD environment
+// variable, like cosign does.
+func signCatalog(ctx context.Context, digest name.Digest, keyFile string) error {
+	privateKey, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	if err := signatures.SignWithKey(ctx, digest, privateKey, []byte(os.Getenv("COSIGN_PASSWORD"))); err != nil {
+		return fmt.Errorf("failed to sign catalog: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package catalognext
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+// writeKeyPair writes a cosign key pair, and returns the paths to the private and public keys.
+func writeKeyPair(t *testing.T) (string, string) {
+	t.Helper()
+
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte("secret"), nil })
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	privateKey := filepath.Join(dir, "cosign.key")
+	publicKey := filepath.Join(dir, "cosign.pub")
+	require.NoError(t, os.WriteFile(privateKey, keys.PrivateBytes, 0o600))
+	require.NoError(t, os.WriteFile(publicKey, keys.PublicBytes, 0o644))
+	return privateKey, publicKey
+}
+
+func TestPushSignedAndPullVerified(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	privateKey, publicKey := writeKeyPair(t)
+	_, otherPublicKey := writeKeyPair(t)
+	t.Setenv("COSIGN_PASSWORD", "secret")
+
+	for _, ref := range []string{host + "/test/signed:latest", host + "/test/unsigned:latest"} {
+		require.NoError(t, dao.UpsertCatalog(ctx, db.Catalog{Ref: ref, Title: "Test Catalog"}))
+	}
+	require.NoError(t, Push(ctx, dao, host+"/test/signed:latest", privateKey))
+	require.NoError(t, Push(ctx, dao, host+"/test/unsigned:latest", ""))
+
+	require.NoError(t, Pull(ctx, dao, getMockOciService(), host+"/test/signed:latest", VerifyOptions{Key: publicKey, RequireSignature: true}))
+
+	err := Pull(ctx, dao, getMockOciService(), host+"/test/signed:latest", VerifyOptions{Key: otherPublicKey})
+	require.ErrorContains(t, err, "failed to verify the signature")
+
+	// Unsigned catalogs are only refused when a signature is required
+	require.NoError(t, Pull(ctx, dao, getMockOciService(), host+"/test/unsigned:latest", VerifyOptions{Key: publicKey}))
+	err = Pull(ctx, dao, getMockOciService(), host+"/test/unsigned:latest", VerifyOptions{Key: publicKey, RequireSignature: true})
+	require.ErrorContains(t, err, "is not signed and a signature is required")
+
+	// The settings act as a policy
+	require.NoError(t, dao.SetSetting(ctx, SettingVerifyKey, publicKey))
+	require.NoError(t, dao.SetSetting(ctx, SettingRequireSignature, "on"))
+	err = Pull(ctx, dao, getMockOciService(), host+"/test/unsigned:latest", VerifyOptions{})
+	require.ErrorContains(t, err, "is not signed and a signature is required")
+	require.NoError(t, Pull(ctx, dao, getMockOciService(), host+"/test/signed:latest", VerifyOptions{}))
+}
+
+func TestRequireSignatureWithoutKey(t *testing.T) {
+	dao := setupTestDB(t)
+
+	opts, err := VerifyOptions{RequireSignature: true}.withSettings(t.Context(), dao)
+	require.NoError(t, err)
+	assert.True(t, opts.verifying())
+
+	err = Pull(t.Context(), dao, getMockOciService(), "localhost:5000/test/catalog:latest", opts)
+	require.ErrorContains(t, err, "a public key is required")
+
+	opts, err = VerifyOptions{}.withSettings(t.Context(), dao)
+	require.NoError(t, err)
+	assert.False(t, opts.verifying())
+}
@@ -0,0 +1,98 @@
+package catalognext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+// Sync re-reads a catalog from the source it was created from and updates its servers.
+// Catalogs created from a legacy catalog are re-read from the configured catalog of the same name,
+// unless legacyCatalogURL is set.
+func Sync(ctx context.Context, dao db.DAO, ociService oci.Service, refStr string, legacyCatalogURL string) error {
+	ref, err := name.ParseReference(refStr)
+	if err != nil {
+		return fmt.Errorf("failed to parse oci-reference %s: %w", refStr, err)
+	}
+	refStr = oci.FullNameWithoutDigest(ref)
+
+	dbCatalog, err := dao.GetCatalog(ctx, refStr)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("catalog %s not found", refStr)
+		}
+		return fmt.Errorf("failed to get catalog: %w", err)
+	}
+	existing := NewFromDb(dbCatalog)
+
+	catalog, err := readSource(ctx, dao, ociService, existing.Source, legacyCatalogURL)
+	if err != nil {
+		return err
+	}
+
+	catalog.Ref = existing.Ref
+	catalog.Title = existing.Title
+
+	if err := catalog.Validate(); err != nil {
+		return fmt.Errorf("invalid catalog: %w", err)
+	}
+
+	updated, err := catalog.ToDb()
+	if err != nil {
+		return fmt.Errorf("failed to convert catalog to db: %w", err)
+	}
+
+	if updated.Digest == existing.Digest {
+		fmt.Printf("Catalog %s is already up to date with %s\n", catalog.Ref, catalog.Source)
+		return nil
+	}
+
+	if err := dao.UpsertCatalog(ctx, updated); err != nil {
+		return fmt.Errorf("failed to update catalog: %w", err)
+	}
+
+	fmt.Printf("Catalog %s synced from %s (%s -> %s)\n", catalog.Ref, catalog.Source, existing.Digest, updated.Digest)
+
+	return nil
+}
+
+// readSource reads the catalog a source points to.
+func readSource(ctx context.Context, dao db.DAO, ociService oci.Service, source string, legacyCatalogURL string) (Catalog, error) {
+	switch {
+	case strings.HasPrefix(source, SourcePrefixWorkingSet):
+		catalog, err := createCatalogFromWorkingSet(ctx, dao, strings.TrimPrefix(source, SourcePrefixWorkingSet))
+		if err != nil {
+			return Catalog{}, fmt.Errorf("failed to sync catalog from profile: %w", err)
+		}
+		return catalog, nil
+	case strings.HasPrefix(source, SourcePrefixLegacyCatalog):
+		if legacyCatalogURL == "" {
+			legacyCatalogURL = strings.TrimPrefix(source, SourcePrefixLegacyCatalog) + ".yaml"
+		}
+		catalog, err := createCatalogFromLegacyCatalog(ctx, legacyCatalogURL)
+		if err != nil {
+			return Catalog{}, fmt.Errorf("failed to sync catalog from legacy catalog: %w", err)
+		}
+		if catalog.Source != source {
+			return Catalog{}, fmt.Errorf("legacy catalog %s not found at %s, use --from-legacy-catalog to set its location", strings.TrimPrefix(source, SourcePrefixLegacyCatalog), legacyCatalogURL)
+		}
+		return catalog, nil
+	case strings.HasPrefix(source, SourcePrefixOCI):
+		catalog, err := readOCICatalog(ctx, dao, ociService, strings.TrimPrefix(source, SourcePrefixOCI), VerifyOptions{})
+		if err != nil {
+			return Catalog{}, fmt.Errorf("failed to sync catalog from OCI registry: %w", err)
+		}
+		return catalog, nil
+	case source == "":
+		return Catalog{}, fmt.Errorf("catalog has no recorded source to sync from")
+	default:
+		return Catalog{}, fmt.Errorf("unsupported catalog source: %s", source)
+	}
+}
@@ -0,0 +1,113 @@
+package catalognext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+func TestSyncFromWorkingSet(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	ws := db.WorkingSet{
+		ID:   "test-ws",
+		Name: "Test Working Set",
+		Servers: db.ServerList{
+			{Type: string(workingset.ServerTypeImage), Image: "docker/test:latest"},
+		},
+		Secrets: db.SecretMap{},
+	}
+	require.NoError(t, dao.CreateWorkingSet(ctx, ws))
+
+	captureStdout(t, func() {
+		require.NoError(t, Create(ctx, dao, "test/catalog:latest", "test-ws", "", "My Catalog"))
+	})
+	before, err := dao.GetCatalog(ctx, "test/catalog:latest")
+	require.NoError(t, err)
+
+	output := captureStdout(t, func() {
+		require.NoError(t, Sync(ctx, dao, nil, "test/catalog:latest", ""))
+	})
+	assert.Contains(t, output, "Catalog test/catalog:latest is already up to date with profile:test-ws")
+
+	ws.Servers = append(ws.Servers, db.Server{Type: string(workingset.ServerTypeImage), Image: "docker/other:latest"})
+	require.NoError(t, dao.UpdateWorkingSet(ctx, ws))
+
+	output = captureStdout(t, func() {
+		require.NoError(t, Sync(ctx, dao, nil, "test/catalog:latest", ""))
+	})
+	assert.Contains(t, output, "Catalog test/catalog:latest synced from profile:test-ws")
+
+	after, err := dao.GetCatalog(ctx, "test/catalog:latest")
+	require.NoError(t, err)
+	assert.NotEqual(t, before.Digest, after.Digest)
+
+	catalog := NewFromDb(after)
+	assert.Equal(t, "My Catalog", catalog.Title)
+	assert.Equal(t, "profile:test-ws", catalog.Source)
+	require.Len(t, catalog.Servers, 2)
+	assert.Equal(t, "docker/other:latest", catalog.Servers[1].Image)
+}
+
+func TestSyncFromLegacyCatalog(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalogFile := filepath.Join(t.TempDir(), "test-catalog.yaml")
+	writeLegacyCatalog := func(image string) {
+		legacyCatalogYAML := `name: test-catalog
+registry:
+  server1:
+    type: "server"
+    image: "` + image + `"
+`
+		require.NoError(t, os.WriteFile(catalogFile, []byte(legacyCatalogYAML), 0o644))
+	}
+
+	writeLegacyCatalog("docker/test-server:v1")
+	captureStdout(t, func() {
+		require.NoError(t, Create(ctx, dao, "test/imported:latest", "", catalogFile, ""))
+	})
+
+	writeLegacyCatalog("docker/test-server:v2")
+	output := captureStdout(t, func() {
+		require.NoError(t, Sync(ctx, dao, nil, "test/imported:latest", catalogFile))
+	})
+	assert.Contains(t, output, "synced from legacy-catalog:test-catalog")
+
+	dbCatalog, err := dao.GetCatalog(ctx, "test/imported:latest")
+	require.NoError(t, err)
+	catalog := NewFromDb(dbCatalog)
+	require.Len(t, catalog.Servers, 1)
+	assert.Equal(t, "docker/test-server:v2", catalog.Servers[0].Image)
+}
+
+func TestSyncFromLegacyCatalogNotFound(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	catalogFile := filepath.Join(t.TempDir(), "test-catalog.yaml")
+	require.NoError(t, os.WriteFile(catalogFile, []byte("name: test-catalog\nregistry: {}\n"), 0o644))
+	captureStdout(t, func() {
+		require.NoError(t, Create(ctx, dao, "test/imported:latest", "", catalogFile, ""))
+	})
+
+	err := Sync(ctx, dao, nil, "test/imported:latest", filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "legacy catalog test-catalog not found")
+}
+
+func TestSyncCatalogNotFound(t *testing.T) {
+	dao := setupTestDB(t)
+
+	err := Sync(t.Context(), dao, nil, "test/missing:latest", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "catalog test/missing:latest not found")
+}
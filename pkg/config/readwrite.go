@@ -112,19 +112,6 @@ func writeConfigFile(name string, content []byte) error {
 	return os.WriteFile(path, content, 0o644)
 }
 
-// WriteConfigFileToSession writes a config file to a session directory
-func WriteConfigFileToSession(sessionName, name string, content []byte) error {
-	sessionPath, err := SessionFilePath(sessionName, name)
-	if err != nil {
-		return err
-	}
-
-	if err := os.MkdirAll(filepath.Dir(sessionPath), 0o755); err != nil {
-		return err
-	}
-	return os.WriteFile(sessionPath, content, 0o644)
-}
-
 // SessionFilePath returns the file path within a session directory
 func SessionFilePath(sessionName, name string) (string, error) {
 	if sessionName == "" {
@@ -0,0 +1,322 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sessionManifestName = "session.json"
+	sessionVersionsDir  = "versions"
+	// sessionVersionsKept is the number of versions of the session files kept to recover from.
+	sessionVersionsKept = 5
+)
+
+// sessionMu serializes the writes and recoveries of the session files: a write reads the manifest, writes the
+// next version and records it, and concurrent writes would otherwise write the same version.
+var sessionMu sync.Mutex
+
+// sessionManifest lists the versions of the files of a session, oldest first.
+type sessionManifest struct {
+	Versions []sessionVersion `json:"versions"`
+}
+
+type sessionVersion struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	// Files are the sha256 of the files of the version, by name.
+	Files map[string]string `json:"files"`
+}
+
+// SessionRecovery tells how the files of a session were recovered after the gateway stopped in the middle of a write.
+type SessionRecovery struct {
+	// Version is the version of the session files in use.
+	Version int
+	// Restored are the files restored from Version.
+	Restored []string
+	// Corrupted are the files that were corrupted and restored from Version, with their changes lost.
+	Corrupted []string
+	// Discarded are the versions that were not completely written, with their changes lost.
+	Discarded []int
+}
+
+// Recovered tells whether anything had to be recovered.
+func (r SessionRecovery) Recovered() bool {
+	return len(r.Restored) > 0 || len(r.Corrupted) > 0 || len(r.Discarded) > 0
+}
+
+// WriteSessionFiles writes a new version of the files of a session. Each version is written to its own directory and
+// recorded in the session manifest before the files are replaced, atomically, so that a gateway that stops in the middle
+// of a write leaves either the previous or the new version behind. It returns the written version.
+func WriteSessionFiles(sessionName string, files map[string][]byte) (int, error) {
+	dir, err := SessionFilePath(sessionName, "")
+	if err != nil {
+		return 0, err
+	}
+	return writeSessionFiles(dir, files, time.Now())
+}
+
+// RecoverSessionFiles restores the files of a session that were left inconsistent or corrupted by a gateway that stopped
+// in the middle of a write, from the last version that was completely written. Sessions without a manifest, written by
+// older gateways, are left as they are.
+func RecoverSessionFiles(sessionName string) (SessionRecovery, error) {
+	dir, err := SessionFilePath(sessionName, "")
+	if err != nil {
+		return SessionRecovery{}, err
+	}
+	return recoverSessionFiles(dir)
+}
+
+func writeSessionFiles(dir string, files map[string][]byte, now time.Time) (int, error) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	manifest, err := readSessionManifest(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	version := nextSessionVersion(dir, manifest)
+	versionDir := filepath.Join(dir, sessionVersionsDir, strconv.Itoa(version))
+	if err := os.MkdirAll(versionDir, 0o755); err != nil {
+		return 0, err
+	}
+
+	checksums := map[string]string{}
+	for name, content := range files {
+		if err := writeFileAtomic(filepath.Join(versionDir, name), content); err != nil {
+			return 0, fmt.Errorf("writing version %d of %s: %w", version, name, err)
+		}
+		checksums[name] = checksum(content)
+	}
+
+	// The version only exists once it's in the manifest
+	manifest.Versions = append(manifest.Versions, sessionVersion{Version: version, Time: now.UTC(), Files: checksums})
+	var pruned []sessionVersion
+	if len(manifest.Versions) > sessionVersionsKept {
+		pruned = manifest.Versions[:len(manifest.Versions)-sessionVersionsKept]
+		manifest.Versions = manifest.Versions[len(manifest.Versions)-sessionVersionsKept:]
+	}
+	if err := writeSessionManifest(dir, manifest); err != nil {
+		return 0, err
+	}
+
+	for name, content := range files {
+		if err := writeFileAtomic(filepath.Join(dir, name), content); err != nil {
+			return 0, fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+
+	for _, old := range pruned {
+		_ = os.RemoveAll(filepath.Join(dir, sessionVersionsDir, strconv.Itoa(old.Version)))
+	}
+
+	return version, nil
+}
+
+func recoverSessionFiles(dir string) (SessionRecovery, error) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	var recovery SessionRecovery
+
+	manifest, err := readSessionManifest(dir)
+	if err != nil {
+		return recovery, err
+	}
+	if len(manifest.Versions) == 0 {
+		return recovery, nil
+	}
+
+	// Versions that were written but never made it to the manifest
+	known := map[int]bool{}
+	for _, version := range manifest.Versions {
+		known[version.Version] = true
+	}
+	for _, version := range versionDirs(dir) {
+		if version > manifest.Versions[len(manifest.Versions)-1].Version && !known[version] {
+			recovery.Discarded = append(recovery.Discarded, version)
+			_ = os.RemoveAll(filepath.Join(dir, sessionVersionsDir, strconv.Itoa(version)))
+		}
+	}
+
+	// The last good version is the most recent one whose files are all intact
+	var good *sessionVersion
+	for i := len(manifest.Versions) - 1; i >= 0; i-- {
+		if versionIntact(dir, manifest.Versions[i]) {
+			good = &manifest.Versions[i]
+			break
+		}
+		recovery.Discarded = append(recovery.Discarded, manifest.Versions[i].Version)
+	}
+	if good == nil {
+		return recovery, fmt.Errorf("no intact version of the session files in %s", dir)
+	}
+	recovery.Version = good.Version
+
+	names := make([]string, 0, len(good.Files))
+	for name := range good.Files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil && checksum(content) == good.Files[name] {
+			continue
+		}
+
+		switch {
+		case err == nil && knownChecksum(manifest, name, checksum(content)):
+			// Left behind by a write that didn't complete
+			recovery.Restored = append(recovery.Restored, name)
+		case err == nil && yaml.Unmarshal(content, &map[string]any{}) == nil:
+			// Edited by hand, that's not for us to undo
+			continue
+		case err != nil && !os.IsNotExist(err):
+			return recovery, err
+		default:
+			recovery.Corrupted = append(recovery.Corrupted, name)
+		}
+
+		versionContent, err := os.ReadFile(filepath.Join(dir, sessionVersionsDir, strconv.Itoa(good.Version), name))
+		if err != nil {
+			return recovery, err
+		}
+		if err := writeFileAtomic(filepath.Join(dir, name), versionContent); err != nil {
+			return recovery, fmt.Errorf("restoring %s: %w", name, err)
+		}
+	}
+
+	if len(recovery.Discarded) > 0 {
+		// Forget the broken versions so that they are not recovered from later on
+		manifest.Versions = slices.DeleteFunc(manifest.Versions, func(version sessionVersion) bool {
+			return version.Version > recovery.Version
+		})
+		if err := writeSessionManifest(dir, manifest); err != nil {
+			return recovery, err
+		}
+	}
+	slices.Sort(recovery.Discarded)
+
+	return recovery, nil
+}
+
+func readSessionManifest(dir string) (sessionManifest, error) {
+	var manifest sessionManifest
+
+	buf, err := os.ReadFile(filepath.Join(dir, sessionManifestName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, nil
+		}
+		return manifest, err
+	}
+	if err := json.Unmarshal(buf, &manifest); err != nil {
+		return manifest, fmt.Errorf("parsing %s: %w", sessionManifestName, err)
+	}
+
+	return manifest, nil
+}
+
+func writeSessionManifest(dir string, manifest sessionManifest) error {
+	buf, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, sessionManifestName), buf); err != nil {
+		return fmt.Errorf("writing %s: %w", sessionManifestName, err)
+	}
+	return nil
+}
+
+// nextSessionVersion never reuses the number of a version directory, even one that was not completely written.
+func nextSessionVersion(dir string, manifest sessionManifest) int {
+	latest := 0
+	if len(manifest.Versions) > 0 {
+		latest = manifest.Versions[len(manifest.Versions)-1].Version
+	}
+	for _, version := range versionDirs(dir) {
+		latest = max(latest, version)
+	}
+	return latest + 1
+}
+
+func versionDirs(dir string) []int {
+	entries, err := os.ReadDir(filepath.Join(dir, sessionVersionsDir))
+	if err != nil {
+		return nil
+	}
+
+	var versions []int
+	for _, entry := range entries {
+		if version, err := strconv.Atoi(entry.Name()); err == nil && entry.IsDir() {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+func versionIntact(dir string, version sessionVersion) bool {
+	for name, sum := range version.Files {
+		content, err := os.ReadFile(filepath.Join(dir, sessionVersionsDir, strconv.Itoa(version.Version), name))
+		if err != nil || checksum(content) != sum {
+			return false
+		}
+	}
+	return true
+}
+
+func knownChecksum(manifest sessionManifest, name, sum string) bool {
+	for _, version := range manifest.Versions {
+		if version.Files[name] == sum {
+			return true
+		}
+	}
+	return false
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeFileAtomic writes a file through a temporary file renamed over it, so that it's never left half written.
+func writeFileAtomic(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeVersion(t *testing.T, dir string, registry string) int {
+	t.Helper()
+
+	version, err := writeSessionFiles(dir, map[string][]byte{
+		"registry.yaml": []byte(registry),
+		"tools.yaml":    []byte("{}\n"),
+	}, time.Now())
+	require.NoError(t, err)
+	return version
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(buf)
+}
+
+func TestWriteSessionFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.Equal(t, 1, writeVersion(t, dir, "servers:\n  github: {}\n"))
+	assert.Equal(t, 2, writeVersion(t, dir, "servers:\n  fetch: {}\n"))
+	assert.Equal(t, "servers:\n  fetch: {}\n", readFile(t, filepath.Join(dir, "registry.yaml")))
+	assert.Equal(t, "servers:\n  github: {}\n", readFile(t, filepath.Join(dir, "versions", "1", "registry.yaml")))
+
+	for range sessionVersionsKept {
+		writeVersion(t, dir, "servers: {}\n")
+	}
+	manifest, err := readSessionManifest(dir)
+	require.NoError(t, err)
+	assert.Len(t, manifest.Versions, sessionVersionsKept)
+	assert.Equal(t, 3, manifest.Versions[0].Version)
+	assert.NoDirExists(t, filepath.Join(dir, "versions", "2"))
+
+	recovery, err := recoverSessionFiles(dir)
+	require.NoError(t, err)
+	assert.False(t, recovery.Recovered())
+	assert.Equal(t, 7, recovery.Version)
+}
+
+func TestWriteSessionFilesConcurrently(t *testing.T) {
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	versions := make([]int, sessionVersionsKept)
+	for i := range versions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			version, err := writeSessionFiles(dir, map[string][]byte{"registry.yaml": []byte("servers:\n  github: {}\n")}, time.Now())
+			assert.NoError(t, err)
+			versions[i] = version
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(versions)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, versions)
+
+	manifest, err := readSessionManifest(dir)
+	require.NoError(t, err)
+	require.Len(t, manifest.Versions, sessionVersionsKept)
+	for i, version := range manifest.Versions {
+		assert.Equal(t, i+1, version.Version)
+	}
+
+	recovery, err := recoverSessionFiles(dir)
+	require.NoError(t, err)
+	assert.False(t, recovery.Recovered())
+}
+
+func TestRecoverSessionFilesInterruptedWrite(t *testing.T) {
+	dir := t.TempDir()
+	writeVersion(t, dir, "servers:\n  github: {}\n")
+	writeVersion(t, dir, "servers:\n  fetch: {}\n")
+
+	// Stopped while replacing the files of version 2, registry.yaml is still the one of version 1
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "registry.yaml"), []byte("servers:\n  github: {}\n"), 0o644))
+
+	recovery, err := recoverSessionFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, SessionRecovery{Version: 2, Restored: []string{"registry.yaml"}}, recovery)
+	assert.Equal(t, "servers:\n  fetch: {}\n", readFile(t, filepath.Join(dir, "registry.yaml")))
+}
+
+func TestRecoverSessionFilesIncompleteVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeVersion(t, dir, "servers:\n  github: {}\n")
+
+	// Stopped while writing version 2, before it was added to the manifest
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "versions", "2"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "versions", "2", "registry.yaml"), []byte("servers:\n  fe"), 0o644))
+
+	recovery, err := recoverSessionFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, SessionRecovery{Version: 1, Discarded: []int{2}}, recovery)
+	assert.NoDirExists(t, filepath.Join(dir, "versions", "2"))
+
+	assert.Equal(t, 2, writeVersion(t, dir, "servers:\n  fetch: {}\n"))
+}
+
+func TestRecoverSessionFilesCorrupted(t *testing.T) {
+	dir := t.TempDir()
+	writeVersion(t, dir, "servers:\n  github: {}\n")
+	writeVersion(t, dir, "servers:\n  fetch: {}\n")
+
+	// The files of version 2 were damaged
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "versions", "2", "registry.yaml"), []byte("servers: ["), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "registry.yaml"), []byte("servers: ["), 0o644))
+
+	recovery, err := recoverSessionFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, SessionRecovery{Version: 1, Corrupted: []string{"registry.yaml"}, Discarded: []int{2}}, recovery)
+	assert.Equal(t, "servers:\n  github: {}\n", readFile(t, filepath.Join(dir, "registry.yaml")))
+
+	manifest, err := readSessionManifest(dir)
+	require.NoError(t, err)
+	require.Len(t, manifest.Versions, 1)
+	assert.Equal(t, 1, manifest.Versions[0].Version)
+}
+
+func TestRecoverSessionFilesKeepsEdits(t *testing.T) {
+	dir := t.TempDir()
+	writeVersion(t, dir, "servers:\n  github: {}\n")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "registry.yaml"), []byte("servers:\n  github: {}\n  fetch: {}\n"), 0o644))
+
+	recovery, err := recoverSessionFiles(dir)
+	require.NoError(t, err)
+	assert.False(t, recovery.Recovered())
+	assert.Equal(t, "servers:\n  github: {}\n  fetch: {}\n", readFile(t, filepath.Join(dir, "registry.yaml")))
+}
+
+func TestRecoverSessionFilesWithoutManifest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "registry.yaml"), []byte("servers: ["), 0o644))
+
+	recovery, err := recoverSessionFiles(dir)
+	require.NoError(t, err)
+	assert.False(t, recovery.Recovered())
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "config.yaml")
+
+	for i := range 3 {
+		require.NoError(t, writeFileAtomic(path, []byte(strconv.Itoa(i))))
+	}
+	assert.Equal(t, "2", readFile(t, path))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
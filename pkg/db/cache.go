@@ -0,0 +1,217 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// cachingDAO keeps the working sets and catalogs it reads in memory.
+//
+// The cache is flushed whenever this process writes to the database, and whenever SQLite's
+// data_version changes, which happens when another process (e.g. the CLI while the gateway runs)
+// commits a change. data_version is only comparable on a single connection: the DAO only ever
+// opens one, that it keeps forever.
+type cachingDAO struct {
+	*dao
+
+	mu          sync.Mutex
+	dataVersion int64
+	generation  int64
+	workingSets map[string]WorkingSet
+	allSets     []WorkingSet
+	allSetsOK   bool
+	catalogs    map[string]Catalog
+	allCatalogs []Catalog
+	allCatOK    bool
+}
+
+func newCachingDAO(d *dao) *cachingDAO {
+	c := &cachingDAO{dao: d}
+	c.flush()
+	return c
+}
+
+func (c *cachingDAO) GetWorkingSet(ctx context.Context, id string) (*WorkingSet, error) {
+	cachedSet, generation, found := cached(ctx, c, func() (WorkingSet, bool) {
+		workingSet, found := c.workingSets[id]
+		return workingSet, found
+	})
+	if found {
+		return &cachedSet, nil
+	}
+
+	workingSet, err := c.dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	store(c, generation, *workingSet, func(value WorkingSet) { c.workingSets[id] = value })
+
+	return workingSet, nil
+}
+
+func (c *cachingDAO) ListWorkingSets(ctx context.Context) ([]WorkingSet, error) {
+	cachedSets, generation, found := cached(ctx, c, func() ([]WorkingSet, bool) {
+		return c.allSets, c.allSetsOK
+	})
+	if found {
+		return cachedSets, nil
+	}
+
+	workingSets, err := c.dao.ListWorkingSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	store(c, generation, workingSets, func(value []WorkingSet) { c.allSets, c.allSetsOK = value, true })
+
+	return workingSets, nil
+}
+
+func (c *cachingDAO) CreateWorkingSet(ctx context.Context, workingSet WorkingSet) error {
+	defer c.invalidate()
+	return c.dao.CreateWorkingSet(ctx, workingSet)
+}
+
+func (c *cachingDAO) UpdateWorkingSet(ctx context.Context, workingSet WorkingSet) error {
+	defer c.invalidate()
+	return c.dao.UpdateWorkingSet(ctx, workingSet)
+}
+
+func (c *cachingDAO) RemoveWorkingSet(ctx context.Context, id string) error {
+	defer c.invalidate()
+	return c.dao.RemoveWorkingSet(ctx, id)
+}
+
+func (c *cachingDAO) GetCatalog(ctx context.Context, ref string) (*Catalog, error) {
+	cachedCatalog, generation, found := cached(ctx, c, func() (Catalog, bool) {
+		catalog, found := c.catalogs[ref]
+		return catalog, found
+	})
+	if found {
+		return &cachedCatalog, nil
+	}
+
+	catalog, err := c.dao.GetCatalog(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	store(c, generation, *catalog, func(value Catalog) { c.catalogs[ref] = value })
+
+	return catalog, nil
+}
+
+func (c *cachingDAO) ListCatalogs(ctx context.Context) ([]Catalog, error) {
+	cachedCatalogs, generation, found := cached(ctx, c, func() ([]Catalog, bool) {
+		return c.allCatalogs, c.allCatOK
+	})
+	if found {
+		return cachedCatalogs, nil
+	}
+
+	catalogs, err := c.dao.ListCatalogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	store(c, generation, catalogs, func(value []Catalog) { c.allCatalogs, c.allCatOK = value, true })
+
+	return catalogs, nil
+}
+
+func (c *cachingDAO) UpsertCatalog(ctx context.Context, catalog Catalog) error {
+	defer c.invalidate()
+	return c.dao.UpsertCatalog(ctx, catalog)
+}
+
+func (c *cachingDAO) DeleteCatalog(ctx context.Context, ref string) error {
+	defer c.invalidate()
+	return c.dao.DeleteCatalog(ctx, ref)
+}
+
+func (c *cachingDAO) SetEncryption(ctx context.Context, enabled bool) error {
+	defer c.invalidate()
+	return c.dao.SetEncryption(ctx, enabled)
+}
+
+func (c *cachingDAO) StripEncryptedConfig(ctx context.Context) ([]string, error) {
+	defer c.invalidate()
+	return c.dao.StripEncryptedConfig(ctx)
+}
+
+// cached looks a value up in the cache, after flushing the cache if the database was changed by another process.
+// The value is cloned so that callers can't modify the cache. The returned generation is to be passed to store.
+func cached[T any](ctx context.Context, c *cachingDAO, lookup func() (T, bool)) (T, int64, bool) {
+	var zero T
+
+	var dataVersion int64
+	if err := c.db.GetContext(ctx, &dataVersion, `PRAGMA data_version`); err != nil {
+		return zero, -1, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if dataVersion != c.dataVersion {
+		c.flush()
+		c.dataVersion = dataVersion
+		return zero, c.generation, false
+	}
+
+	value, found := lookup()
+	if !found {
+		return zero, c.generation, false
+	}
+
+	copied, err := clone(value)
+	if err != nil {
+		return zero, c.generation, false
+	}
+	return copied, c.generation, true
+}
+
+// store caches a value read from the database, unless the cache was flushed since the lookup that missed:
+// the value might then be outdated.
+func store[T any](c *cachingDAO, generation int64, value T, set func(T)) {
+	copied, err := clone(value)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation == c.generation {
+		set(copied)
+	}
+}
+
+func (c *cachingDAO) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.flush()
+}
+
+// flush empties the cache. c.mu must be held, or c not shared yet.
+func (c *cachingDAO) flush() {
+	c.generation++
+	c.workingSets = make(map[string]WorkingSet)
+	c.allSets, c.allSetsOK = nil, false
+	c.catalogs = make(map[string]Catalog)
+	c.allCatalogs, c.allCatOK = nil, false
+}
+
+// clone deep copies a value through its JSON representation, which is also how it's stored in the database.
+func clone[T any](value T) (T, error) {
+	var copied T
+
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return copied, err
+	}
+	err = json.Unmarshal(buf, &copied)
+	return copied, err
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWorkingSet(name string) WorkingSet {
+	return WorkingSet{
+		ID:   "cached",
+		Name: name,
+		Servers: ServerList{
+			{
+				Type:   "image",
+				Image:  "docker/test:latest",
+				Config: map[string]any{"key": "value"},
+				Tools:  []string{"tool1"},
+			},
+		},
+		Secrets: SecretMap{"default": {Provider: "docker-desktop-store"}},
+	}
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, dao.CreateWorkingSet(ctx, testWorkingSet("original")))
+
+	first, err := dao.GetWorkingSet(ctx, "cached")
+	require.NoError(t, err)
+	first.Name = "modified"
+	first.Servers[0].Config["key"] = "modified"
+
+	second, err := dao.GetWorkingSet(ctx, "cached")
+	require.NoError(t, err)
+	assert.Equal(t, "original", second.Name)
+	assert.Equal(t, "value", second.Servers[0].Config["key"])
+}
+
+func TestCacheIsInvalidatedByWrites(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, dao.CreateWorkingSet(ctx, testWorkingSet("original")))
+	workingSets, err := dao.ListWorkingSets(ctx)
+	require.NoError(t, err)
+	require.Len(t, workingSets, 1)
+	_, err = dao.GetWorkingSet(ctx, "cached")
+	require.NoError(t, err)
+
+	require.NoError(t, dao.UpdateWorkingSet(ctx, testWorkingSet("updated")))
+
+	workingSet, err := dao.GetWorkingSet(ctx, "cached")
+	require.NoError(t, err)
+	assert.Equal(t, "updated", workingSet.Name)
+
+	require.NoError(t, dao.RemoveWorkingSet(ctx, "cached"))
+
+	_, err = dao.GetWorkingSet(ctx, "cached")
+	require.Error(t, err)
+	workingSets, err = dao.ListWorkingSets(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, workingSets)
+}
+
+func TestCacheIsInvalidatedByOtherConnections(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "test.db")
+	ctx := t.Context()
+
+	gateway, err := New(WithDatabaseFile(dbFile))
+	require.NoError(t, err)
+	defer gateway.Close()
+	cli, err := New(WithDatabaseFile(dbFile))
+	require.NoError(t, err)
+	defer cli.Close()
+
+	require.NoError(t, cli.CreateWorkingSet(ctx, testWorkingSet("original")))
+	workingSet, err := gateway.GetWorkingSet(ctx, "cached")
+	require.NoError(t, err)
+	assert.Equal(t, "original", workingSet.Name)
+
+	require.NoError(t, cli.UpdateWorkingSet(ctx, testWorkingSet("updated")))
+	workingSet, err = gateway.GetWorkingSet(ctx, "cached")
+	require.NoError(t, err)
+	assert.Equal(t, "updated", workingSet.Name)
+
+	require.NoError(t, cli.UpsertCatalog(ctx, Catalog{Ref: "docker.io/test/catalog:latest", Title: "original"}))
+	catalog, err := gateway.GetCatalog(ctx, "docker.io/test/catalog:latest")
+	require.NoError(t, err)
+	assert.Equal(t, "original", catalog.Title)
+
+	require.NoError(t, cli.UpsertCatalog(ctx, Catalog{Ref: "docker.io/test/catalog:latest", Title: "updated"}))
+	catalogs, err := gateway.ListCatalogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, catalogs, 1)
+	assert.Equal(t, "updated", catalogs[0].Title)
+}
+
+func BenchmarkGetWorkingSet(b *testing.B) {
+	dbFile := filepath.Join(b.TempDir(), "test.db")
+	ctx := b.Context()
+
+	cachedDAO, err := New(WithDatabaseFile(dbFile))
+	require.NoError(b, err)
+	defer cachedDAO.Close()
+	require.NoError(b, cachedDAO.CreateWorkingSet(ctx, testWorkingSet("bench")))
+
+	b.Run("cached", func(b *testing.B) {
+		for b.Loop() {
+			_, err := cachedDAO.GetWorkingSet(ctx, "cached")
+			require.NoError(b, err)
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		uncached := cachedDAO.(*cachingDAO).dao
+		for b.Loop() {
+			_, err := uncached.GetWorkingSet(ctx, "cached")
+			require.NoError(b, err)
+		}
+	})
+}
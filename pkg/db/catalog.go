@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -14,6 +15,8 @@ type CatalogDAO interface {
 	UpsertCatalog(ctx context.Context, catalog Catalog) error
 	DeleteCatalog(ctx context.Context, ref string) error
 	ListCatalogs(ctx context.Context) ([]Catalog, error)
+	SearchCatalogServers(ctx context.Context, query string) ([]CatalogServer, error)
+	SearchCatalogServersByPrefix(ctx context.Context, prefix string) ([]CatalogServer, error)
 }
 
 type ToolList []string
@@ -161,3 +164,36 @@ func (d *dao) ListCatalogs(ctx context.Context) ([]Catalog, error) {
 
 	return catalogs, nil
 }
+
+// SearchCatalogServers returns the servers of all the catalogs whose image, source, endpoint or name contains the
+// query, ignoring the case.
+func (d *dao) SearchCatalogServers(ctx context.Context, query string) ([]CatalogServer, error) {
+	return d.searchCatalogServers(ctx, query, false)
+}
+
+// SearchCatalogServersByPrefix returns the servers of all the catalogs whose image, source, endpoint or name starts
+// with the prefix, ignoring the case. Unlike SearchCatalogServers, it's answered from indexes.
+func (d *dao) SearchCatalogServersByPrefix(ctx context.Context, prefix string) ([]CatalogServer, error) {
+	return d.searchCatalogServers(ctx, prefix, true)
+}
+
+func (d *dao) searchCatalogServers(ctx context.Context, query string, prefix bool) ([]CatalogServer, error) {
+	sqlQuery := `SELECT id, server_type, tools, source, image, endpoint, catalog_ref, snapshot FROM catalog_server`
+	var args []any
+	if query != "" {
+		var conditions []string
+		for _, column := range catalogServerSearchColumns {
+			conditions = append(conditions, searchCondition(column, prefix, 1))
+		}
+		sqlQuery += ` WHERE ` + strings.Join(conditions, " OR ")
+		args = []any{query, searchPrefixEnd}
+	}
+	sqlQuery += ` ORDER BY catalog_ref, id`
+
+	var servers []CatalogServer
+	err := d.db.SelectContext(ctx, &servers, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
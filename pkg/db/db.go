@@ -1,12 +1,14 @@
 package db
 
 import (
+	"crypto/cipher"
 	"database/sql"
 	"embed"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/golang-migrate/migrate/v4"
 	msqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
@@ -24,6 +26,9 @@ type DAO interface {
 	WorkingSetDAO
 	CatalogDAO
 	MigrationStatusDAO
+	EncryptionDAO
+	SettingsDAO
+	SnapshotDAO
 
 	// Normally unnecessary to call this
 	Close() error
@@ -31,13 +36,18 @@ type DAO interface {
 
 type dao struct {
 	db *sqlx.DB
+
+	keyStore KeyStore
+	cipherMu sync.Mutex
+	aead     cipher.AEAD
 }
 
 //go:embed migrations/*.sql
 var migrations embed.FS
 
 type options struct {
-	dbFile string
+	dbFile   string
+	keyStore KeyStore
 }
 
 type Option func(o *options) error
@@ -57,6 +67,10 @@ func New(opts ...Option) (DAO, error) {
 		}
 	}
 
+	if o.keyStore == nil {
+		o.keyStore = keyringKeyStore{}
+	}
+
 	if o.dbFile == "" {
 		dbFile, err := DefaultDatabaseFilename()
 		if err != nil {
@@ -98,7 +112,7 @@ func New(opts ...Option) (DAO, error) {
 
 	sqlxDb := sqlx.NewDb(db, "sqlite")
 
-	return &dao{db: sqlxDb}, nil
+	return newCachingDAO(&dao{db: sqlxDb, keyStore: o.keyStore}), nil
 }
 
 func (d *dao) Close() error {
@@ -31,3 +31,24 @@ func TestNewCreatesDirectoryWhenNotExists(t *testing.T) {
 	require.NoError(t, err, "Directory should exist after database creation")
 	assert.True(t, stat.IsDir(), "Created path should be a directory")
 }
+
+func TestSnapshot(t *testing.T) {
+	dao, err := New(WithDatabaseFile(filepath.Join(t.TempDir(), "mcp-toolkit.db")))
+	require.NoError(t, err)
+	defer dao.Close()
+	require.NoError(t, dao.SetSetting(t.Context(), "registry.url", "https://registry.example.com"))
+
+	snapshotFile := filepath.Join(t.TempDir(), "snapshot.db")
+	require.NoError(t, dao.Snapshot(t.Context(), snapshotFile))
+
+	snapshot, err := New(WithDatabaseFile(snapshotFile))
+	require.NoError(t, err)
+	defer snapshot.Close()
+
+	value, err := snapshot.GetSetting(t.Context(), "registry.url")
+	require.NoError(t, err)
+	assert.Equal(t, "https://registry.example.com", value)
+
+	// The snapshot is never written over an existing file
+	require.Error(t, dao.Snapshot(t.Context(), snapshotFile))
+}
@@ -0,0 +1,314 @@
+package db
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker-credential-helpers/credentials"
+
+	"github.com/docker/mcp-gateway/pkg/oauth"
+)
+
+// SettingEncryption is the setting that turns the encryption of the sensitive fields of the database on or off.
+const SettingEncryption = "db.encryption"
+
+// encryptedPrefix versions the format of the encrypted fields.
+const encryptedPrefix = "v1:"
+
+// keyringServerURL identifies the encryption key in the OS keyring.
+const keyringServerURL = "https://mcp.docker.com/db-encryption-key"
+
+// ErrKeyNotFound is returned by a KeyStore that has no key yet.
+var ErrKeyNotFound = errors.New("database encryption key not found")
+
+// KeyStore stores the key that encrypts the sensitive fields of the database.
+type KeyStore interface {
+	Key() ([]byte, error)
+	SetKey(key []byte) error
+}
+
+// WithKeyStore sets where the encryption key is stored. Defaults to the OS keyring, through the docker credential helper.
+func WithKeyStore(keyStore KeyStore) Option {
+	return func(o *options) error {
+		o.keyStore = keyStore
+		return nil
+	}
+}
+
+type EncryptionDAO interface {
+	EncryptionEnabled(ctx context.Context) (bool, error)
+	// SetEncryption turns the encryption of the sensitive fields on or off, and encrypts
+	// or decrypts the existing data accordingly.
+	SetEncryption(ctx context.Context, enabled bool) error
+	// StripEncryptedConfig removes the encrypted config of the servers, from a copy of the database that
+	// leaves the machine holding the key. It returns the servers that lost their config, as profile/server.
+	StripEncryptedConfig(ctx context.Context) ([]string, error)
+}
+
+func (d *dao) EncryptionEnabled(ctx context.Context) (bool, error) {
+	value, err := d.GetSetting(ctx, SettingEncryption)
+	if err != nil {
+		return false, err
+	}
+	return value == "on", nil
+}
+
+func (d *dao) SetEncryption(ctx context.Context, enabled bool) (err error) {
+	if enabled {
+		// Create the key before anything is encrypted with it
+		if _, err := d.cipher(true); err != nil {
+			return err
+		}
+	}
+
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txClose(tx, &err)
+
+	value := "off"
+	if enabled {
+		value = "on"
+	}
+	const upsertQuery = `INSERT INTO settings (key, value) VALUES ($1, $2) ON CONFLICT(key) DO UPDATE SET value = excluded.value`
+	if _, err = tx.ExecContext(ctx, upsertQuery, SettingEncryption, value); err != nil {
+		return err
+	}
+
+	var workingSets []WorkingSet
+	if err = tx.SelectContext(ctx, &workingSets, `SELECT id, name, servers, secrets, docker_context FROM working_set`); err != nil {
+		return err
+	}
+	if err = d.decryptWorkingSets(ctx, workingSets); err != nil {
+		return err
+	}
+
+	const updateQuery = `UPDATE working_set SET servers = $2 WHERE id = $1`
+	for _, workingSet := range workingSets {
+		var servers ServerList
+		servers, err = d.sealServers(workingSet.Servers, enabled)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(ctx, updateQuery, workingSet.ID, servers); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (d *dao) StripEncryptedConfig(ctx context.Context) (stripped []string, err error) {
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txClose(tx, &err)
+
+	var workingSets []WorkingSet
+	if err = tx.SelectContext(ctx, &workingSets, `SELECT id, name, servers, secrets, docker_context FROM working_set ORDER BY id`); err != nil {
+		return nil, err
+	}
+
+	const updateQuery = `UPDATE working_set SET servers = $2 WHERE id = $1`
+	for _, workingSet := range workingSets {
+		changed := false
+		for i, server := range workingSet.Servers {
+			if server.EncryptedConfig == "" {
+				continue
+			}
+			workingSet.Servers[i].EncryptedConfig = ""
+			stripped = append(stripped, workingSet.ID+"/"+serverName(server))
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		if _, err = tx.ExecContext(ctx, updateQuery, workingSet.ID, workingSet.Servers); err != nil {
+			return nil, err
+		}
+	}
+
+	return stripped, tx.Commit()
+}
+
+// serverName names a server of a profile in messages.
+func serverName(server Server) string {
+	switch {
+	case server.Snapshot != nil && server.Snapshot.Server.Name != "":
+		return server.Snapshot.Server.Name
+	case server.Image != "":
+		return server.Image
+	case server.Endpoint != "":
+		return server.Endpoint
+	default:
+		return server.Source
+	}
+}
+
+// encryptServers returns a copy of servers, with their config encrypted if the encryption is on.
+func (d *dao) encryptServers(ctx context.Context, servers ServerList) (ServerList, error) {
+	enabled, err := d.EncryptionEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the encryption setting: %w", err)
+	}
+	return d.sealServers(servers, enabled)
+}
+
+func (d *dao) sealServers(servers ServerList, encrypted bool) (ServerList, error) {
+	if !encrypted {
+		return servers, nil
+	}
+
+	aead, err := d.cipher(true)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := make(ServerList, len(servers))
+	for i, server := range servers {
+		sealed[i] = server
+		if len(server.Config) == 0 {
+			continue
+		}
+
+		buf, err := json.Marshal(server.Config)
+		if err != nil {
+			return nil, err
+		}
+		sealed[i].EncryptedConfig, err = encrypt(aead, buf)
+		if err != nil {
+			return nil, err
+		}
+		sealed[i].Config = nil
+	}
+
+	return sealed, nil
+}
+
+func (d *dao) decryptWorkingSets(ctx context.Context, workingSets []WorkingSet) error {
+	for i := range workingSets {
+		if err := d.decryptWorkingSet(ctx, &workingSets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decryptWorkingSet decrypts the encrypted config of the servers of a working set, whether the encryption is on or not.
+func (d *dao) decryptWorkingSet(_ context.Context, workingSet *WorkingSet) error {
+	for i, server := range workingSet.Servers {
+		if server.EncryptedConfig == "" {
+			continue
+		}
+
+		aead, err := d.cipher(false)
+		if err != nil {
+			return err
+		}
+		buf, err := decrypt(aead, server.EncryptedConfig)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt the config of profile %s: %w", workingSet.ID, err)
+		}
+
+		var config map[string]any
+		if err := json.Unmarshal(buf, &config); err != nil {
+			return fmt.Errorf("failed to decrypt the config of profile %s: %w", workingSet.ID, err)
+		}
+		workingSet.Servers[i].Config = config
+		workingSet.Servers[i].EncryptedConfig = ""
+	}
+	return nil
+}
+
+// cipher returns the cipher that encrypts the fields, with the key from the key store.
+// With create, a key is created if there's none yet.
+func (d *dao) cipher(create bool) (cipher.AEAD, error) {
+	d.cipherMu.Lock()
+	defer d.cipherMu.Unlock()
+
+	if d.aead != nil {
+		return d.aead, nil
+	}
+
+	key, err := d.keyStore.Key()
+	if errors.Is(err, ErrKeyNotFound) && create {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to create the database encryption key: %w", err)
+		}
+		err = d.keyStore.SetKey(key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the database encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database encryption key: %w", err)
+	}
+	d.aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.aead, nil
+}
+
+func encrypt(aead cipher.AEAD, plaintext []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decrypt(aead cipher.AEAD, value string) ([]byte, error) {
+	encoded, found := strings.CutPrefix(value, encryptedPrefix)
+	if !found {
+		return nil, errors.New("unsupported encryption format")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("encrypted value is too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+// keyringKeyStore stores the encryption key in the OS keyring, through the docker credential helper.
+type keyringKeyStore struct{}
+
+func (keyringKeyStore) Key() ([]byte, error) {
+	_, secret, err := oauth.NewReadWriteCredentialHelper().Get(keyringServerURL)
+	if credentials.IsErrCredentialsNotFound(err) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(secret)
+}
+
+func (keyringKeyStore) SetKey(key []byte) error {
+	return oauth.NewReadWriteCredentialHelper().Add(&credentials.Credentials{
+		ServerURL: keyringServerURL,
+		Username:  "docker-mcp",
+		Secret:    base64.StdEncoding.EncodeToString(key),
+	})
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryKeyStore struct {
+	key []byte
+}
+
+func (m *memoryKeyStore) Key() ([]byte, error) {
+	if m.key == nil {
+		return nil, ErrKeyNotFound
+	}
+	return m.key, nil
+}
+
+func (m *memoryKeyStore) SetKey(key []byte) error {
+	m.key = key
+	return nil
+}
+
+func TestEncryption(t *testing.T) {
+	ctx := t.Context()
+	dbFile := filepath.Join(t.TempDir(), "test.db")
+	keyStore := &memoryKeyStore{}
+
+	dao, err := New(WithDatabaseFile(dbFile), WithKeyStore(keyStore))
+	require.NoError(t, err)
+
+	workingSet := WorkingSet{
+		ID:   "test-id",
+		Name: "Test Working Set",
+		Servers: ServerList{
+			{
+				Type:   "image",
+				Image:  "mcp/test",
+				Config: map[string]any{"token": "sensitive"},
+				Tools:  []string{},
+			},
+		},
+		Secrets: SecretMap{},
+	}
+	require.NoError(t, dao.CreateWorkingSet(ctx, workingSet))
+
+	enabled, err := dao.EncryptionEnabled(ctx)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+	assert.Equal(t, `{"token":"sensitive"}`, rawConfig(t, dao, "test-id"))
+
+	// Existing data is encrypted when the encryption is turned on
+	require.NoError(t, dao.SetEncryption(ctx, true))
+	require.NotNil(t, keyStore.key)
+
+	enabled, err = dao.EncryptionEnabled(ctx)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+	assert.Empty(t, rawConfig(t, dao, "test-id"))
+
+	retrieved, err := dao.GetWorkingSet(ctx, "test-id")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"token": "sensitive"}, retrieved.Servers[0].Config)
+	assert.Empty(t, retrieved.Servers[0].EncryptedConfig)
+
+	// New data is encrypted too, and still searchable
+	workingSet.Servers[0].Config = map[string]any{"token": "updated"}
+	require.NoError(t, dao.UpdateWorkingSet(ctx, workingSet))
+	assert.Empty(t, rawConfig(t, dao, "test-id"))
+	assert.Equal(t, map[string]any{"token": "updated"}, workingSet.Servers[0].Config, "the caller's working set is left untouched")
+
+	found, err := dao.SearchWorkingSets(ctx, "mcp/test", "")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, map[string]any{"token": "updated"}, found[0].Servers[0].Config)
+
+	// Another DAO with the same key can read the data
+	other, err := New(WithDatabaseFile(dbFile), WithKeyStore(&memoryKeyStore{key: keyStore.key}))
+	require.NoError(t, err)
+	listed, err := other.ListWorkingSets(ctx)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, map[string]any{"token": "updated"}, listed[0].Servers[0].Config)
+
+	// But not without the key
+	noKey, err := New(WithDatabaseFile(dbFile), WithKeyStore(&memoryKeyStore{}))
+	require.NoError(t, err)
+	_, err = noKey.GetWorkingSet(ctx, "test-id")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	// Existing data is decrypted when the encryption is turned off
+	require.NoError(t, dao.SetEncryption(ctx, false))
+	assert.Equal(t, `{"token":"updated"}`, rawConfig(t, dao, "test-id"))
+}
+
+// rawConfig reads the config of the first server of a working set, as stored in the database.
+func rawConfig(t *testing.T, dao DAO, id string) string {
+	t.Helper()
+
+	var config *string
+	err := dao.(*cachingDAO).db.GetContext(t.Context(), &config, `SELECT json_extract(servers, '$[0].config') FROM working_set WHERE id = $1`, id)
+	require.NoError(t, err)
+	if config == nil {
+		return ""
+	}
+	return *config
+}
@@ -0,0 +1 @@
+alter table working_set add column docker_context text not null default '';
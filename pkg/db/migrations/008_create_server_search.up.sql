@@ -0,0 +1,43 @@
+-- Searchable fields of the servers of the working sets, lowercased for case-insensitive searches.
+-- The servers are stored as JSON in working_set, so triggers keep this table up to date.
+create table working_set_server_search (
+  working_set_id text not null,
+  field text not null,
+  value text not null,
+  foreign key (working_set_id) references working_set(id) on delete cascade
+);
+
+create index working_set_server_search_value on working_set_server_search(value, working_set_id);
+create index working_set_server_search_working_set on working_set_server_search(working_set_id);
+
+create view working_set_server_fields as
+select w.id as working_set_id, f.field, lower(json_extract(s.value, f.path)) as value
+from working_set w, json_each(w.servers) s, (
+  select 'image' as field, '$.image' as path
+  union all select 'source', '$.source'
+  union all select 'endpoint', '$.endpoint'
+  union all select 'name', '$.snapshot.server.name'
+) f
+where coalesce(json_extract(s.value, f.path), '') != '';
+
+insert into working_set_server_search (working_set_id, field, value)
+select working_set_id, field, value from working_set_server_fields;
+
+create trigger working_set_server_search_insert after insert on working_set
+begin
+  insert into working_set_server_search (working_set_id, field, value)
+  select working_set_id, field, value from working_set_server_fields where working_set_id = new.id;
+end;
+
+create trigger working_set_server_search_update after update of id, servers on working_set
+begin
+  delete from working_set_server_search where working_set_id = old.id;
+  insert into working_set_server_search (working_set_id, field, value)
+  select working_set_id, field, value from working_set_server_fields where working_set_id = new.id;
+end;
+
+-- Case-insensitive searches of the servers of the catalogs
+create index catalog_server_image_search on catalog_server(lower(image));
+create index catalog_server_source_search on catalog_server(lower(source));
+create index catalog_server_endpoint_search on catalog_server(lower(endpoint));
+create index catalog_server_name_search on catalog_server(lower(json_extract(snapshot, '$.server.name')));
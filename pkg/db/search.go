@@ -0,0 +1,27 @@
+package db
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// searchPrefixEnd is appended to a prefix to bound the range of the values that start with it.
+const searchPrefixEnd = string(utf8.MaxRune)
+
+// catalogServerSearchColumns are the expressions of catalog_server that are searched, as they're indexed.
+var catalogServerSearchColumns = []string{
+	"lower(image)",
+	"lower(source)",
+	"lower(endpoint)",
+	"lower(json_extract(snapshot, '$.server.name'))",
+}
+
+// searchCondition matches a lowercased column against the query bound to $param. Prefixes are matched with a
+// range, bounded by searchPrefixEnd in $param+1, so that an index on the column is used, while substrings have to
+// be looked for in every value.
+func searchCondition(column string, prefix bool, param int) string {
+	if prefix {
+		return fmt.Sprintf("(%[1]s >= lower($%[2]d) AND %[1]s < lower($%[2]d) || $%[3]d)", column, param, param+1)
+	}
+	return fmt.Sprintf("instr(%s, lower($%d)) > 0", column, param)
+}
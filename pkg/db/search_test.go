@@ -0,0 +1,286 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func workingSetIDs(workingSets []WorkingSet) []string {
+	var ids []string
+	for _, workingSet := range workingSets {
+		ids = append(ids, workingSet.ID)
+	}
+	return ids
+}
+
+func TestSearchWorkingSetsMatchesEndpointAndName(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, dao.CreateWorkingSet(ctx, WorkingSet{
+		ID:   "remote",
+		Name: "Remote",
+		Servers: ServerList{
+			{Type: "remote", Endpoint: "https://MCP.Example.com/sse"},
+		},
+		Secrets: SecretMap{},
+	}))
+	require.NoError(t, dao.CreateWorkingSet(ctx, WorkingSet{
+		ID:   "snapshot",
+		Name: "Snapshot",
+		Servers: ServerList{
+			{Type: "image", Image: "mcp/fetch", Snapshot: &ServerSnapshot{Server: catalog.Server{Name: "Fetcher"}}},
+		},
+		Secrets: SecretMap{},
+	}))
+
+	results, err := dao.SearchWorkingSets(ctx, "example.com", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"remote"}, workingSetIDs(results))
+
+	results, err = dao.SearchWorkingSets(ctx, "fetcher", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"snapshot"}, workingSetIDs(results))
+}
+
+func TestSearchWorkingSetsByPrefix(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, dao.CreateWorkingSet(ctx, WorkingSet{
+		ID:   "set-1",
+		Name: "First",
+		Servers: ServerList{
+			{Type: "image", Image: "MyCompany/postgres:15"},
+			{Type: "registry", Source: "https://registry.example.com/my-server"},
+		},
+		Secrets: SecretMap{},
+	}))
+	require.NoError(t, dao.CreateWorkingSet(ctx, WorkingSet{
+		ID:   "set-2",
+		Name: "Second",
+		Servers: ServerList{
+			{Type: "image", Image: "docker/postgres:16"},
+		},
+		Secrets: SecretMap{},
+	}))
+
+	results, err := dao.SearchWorkingSetsByPrefix(ctx, "mycompany/", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"set-1"}, workingSetIDs(results))
+	assert.Len(t, results[0].Servers, 2)
+
+	results, err = dao.SearchWorkingSetsByPrefix(ctx, "HTTPS://registry", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"set-1"}, workingSetIDs(results))
+
+	// Substrings don't match
+	results, err = dao.SearchWorkingSetsByPrefix(ctx, "postgres", "")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = dao.SearchWorkingSetsByPrefix(ctx, "docker/postgres:16", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"set-2"}, workingSetIDs(results))
+
+	results, err = dao.SearchWorkingSetsByPrefix(ctx, "", "set-2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"set-2"}, workingSetIDs(results))
+}
+
+func TestSearchWorkingSetsFollowsUpdatesAndRemovals(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	workingSet := WorkingSet{
+		ID:      "set-1",
+		Name:    "First",
+		Servers: ServerList{{Type: "image", Image: "mcp/postgres"}},
+		Secrets: SecretMap{},
+	}
+	require.NoError(t, dao.CreateWorkingSet(ctx, workingSet))
+
+	workingSet.Servers = ServerList{{Type: "image", Image: "mcp/mysql"}}
+	require.NoError(t, dao.UpdateWorkingSet(ctx, workingSet))
+
+	results, err := dao.SearchWorkingSets(ctx, "postgres", "")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	results, err = dao.SearchWorkingSetsByPrefix(ctx, "mcp/mysql", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"set-1"}, workingSetIDs(results))
+
+	require.NoError(t, dao.RemoveWorkingSet(ctx, "set-1"))
+
+	var count int
+	require.NoError(t, dao.(*cachingDAO).db.GetContext(ctx, &count, `SELECT COUNT(*) FROM working_set_server_search`))
+	assert.Zero(t, count)
+}
+
+func TestSearchCatalogServers(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{
+		Ref: "docker.io/test/catalog:latest",
+		Servers: []CatalogServer{
+			{ServerType: "image", Image: "mcp/GitHub:latest"},
+			{ServerType: "registry", Source: "https://registry.example.com/notion"},
+			{ServerType: "remote", Endpoint: "https://mcp.linear.app/sse"},
+			{ServerType: "image", Image: "mcp/fetch", Snapshot: &ServerSnapshot{Server: catalog.Server{Name: "Fetcher"}}},
+		},
+	}))
+	require.NoError(t, dao.UpsertCatalog(ctx, Catalog{
+		Ref: "docker.io/test/other:latest",
+		Servers: []CatalogServer{
+			{ServerType: "image", Image: "other/github"},
+		},
+	}))
+
+	servers, err := dao.SearchCatalogServers(ctx, "GITHUB")
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+	assert.Equal(t, "mcp/GitHub:latest", servers[0].Image)
+	assert.Equal(t, "docker.io/test/catalog:latest", servers[0].CatalogRef)
+	assert.Equal(t, "other/github", servers[1].Image)
+
+	servers, err = dao.SearchCatalogServersByPrefix(ctx, "mcp/g")
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "mcp/GitHub:latest", servers[0].Image)
+
+	servers, err = dao.SearchCatalogServersByPrefix(ctx, "https://mcp.linear")
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "remote", servers[0].ServerType)
+
+	servers, err = dao.SearchCatalogServersByPrefix(ctx, "fetch")
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "Fetcher", servers[0].Snapshot.Server.Name)
+
+	servers, err = dao.SearchCatalogServers(ctx, "notion")
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+
+	servers, err = dao.SearchCatalogServersByPrefix(ctx, "notion")
+	require.NoError(t, err)
+	assert.Empty(t, servers)
+
+	servers, err = dao.SearchCatalogServers(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, servers, 5)
+}
+
+func TestSearchByPrefixUsesIndexes(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	queryPlan := func(query string, args ...any) string {
+		rows, err := dao.(*cachingDAO).db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var details []string
+		for rows.Next() {
+			var id, parent, notUsed int
+			var detail string
+			require.NoError(t, rows.Scan(&id, &parent, &notUsed, &detail))
+			details = append(details, detail)
+		}
+		require.NoError(t, rows.Err())
+		return strings.Join(details, "\n")
+	}
+
+	plan := queryPlan(`SELECT working_set_id FROM working_set_server_search WHERE `+searchCondition("value", true, 1), "mcp/", searchPrefixEnd)
+	assert.Contains(t, plan, "USING COVERING INDEX working_set_server_search_value")
+
+	var conditions []string
+	for _, column := range catalogServerSearchColumns {
+		conditions = append(conditions, searchCondition(column, true, 1))
+	}
+	plan = queryPlan(`SELECT id FROM catalog_server WHERE `+strings.Join(conditions, " OR "), "mcp/", searchPrefixEnd)
+	for _, index := range []string{"catalog_server_image_search", "catalog_server_source_search", "catalog_server_endpoint_search", "catalog_server_name_search"} {
+		assert.Contains(t, plan, index)
+	}
+}
+
+// seedSearchBenchmark creates 100 working sets and 100 catalogs, of 50 servers each.
+func seedSearchBenchmark(b *testing.B) DAO {
+	b.Helper()
+
+	dao := setupTestDB(b)
+	ctx := b.Context()
+
+	for i := range 100 {
+		var servers ServerList
+		var catalogServers []CatalogServer
+		for j := range 50 {
+			n := i*50 + j
+			servers = append(servers, Server{
+				Type:     "image",
+				Image:    fmt.Sprintf("vendor%d/server%d:latest", n%500, n),
+				Source:   fmt.Sprintf("https://github.com/vendor%d/server%d", n%500, n),
+				Endpoint: fmt.Sprintf("https://server%d.example.com/mcp", n),
+				Snapshot: &ServerSnapshot{Server: catalog.Server{Name: fmt.Sprintf("server%d", n)}},
+			})
+			catalogServers = append(catalogServers, CatalogServer{
+				ServerType: "image",
+				Tools:      ToolList{},
+				Image:      servers[j].Image,
+				Source:     servers[j].Source,
+				Endpoint:   servers[j].Endpoint,
+				Snapshot:   servers[j].Snapshot,
+			})
+		}
+		require.NoError(b, dao.CreateWorkingSet(ctx, WorkingSet{
+			ID:      fmt.Sprintf("set-%d", i),
+			Name:    fmt.Sprintf("Set %d", i),
+			Servers: servers,
+			Secrets: SecretMap{},
+		}))
+		require.NoError(b, dao.UpsertCatalog(ctx, Catalog{Ref: fmt.Sprintf("docker.io/test/catalog-%d:latest", i), Servers: catalogServers}))
+	}
+
+	return dao
+}
+
+func BenchmarkSearchWorkingSets(b *testing.B) {
+	dao := seedSearchBenchmark(b)
+
+	b.Run("substring", func(b *testing.B) {
+		for b.Loop() {
+			_, err := dao.SearchWorkingSets(b.Context(), "server4321", "")
+			require.NoError(b, err)
+		}
+	})
+	b.Run("prefix", func(b *testing.B) {
+		for b.Loop() {
+			_, err := dao.SearchWorkingSetsByPrefix(b.Context(), "vendor321/", "")
+			require.NoError(b, err)
+		}
+	})
+}
+
+func BenchmarkSearchCatalogServers(b *testing.B) {
+	dao := seedSearchBenchmark(b)
+
+	b.Run("substring", func(b *testing.B) {
+		for b.Loop() {
+			_, err := dao.SearchCatalogServers(b.Context(), "server4321")
+			require.NoError(b, err)
+		}
+	})
+	b.Run("prefix", func(b *testing.B) {
+		for b.Loop() {
+			_, err := dao.SearchCatalogServersByPrefix(b.Context(), "vendor321/")
+			require.NoError(b, err)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type SettingsDAO interface {
+	// GetSetting returns the value of a setting, or an empty string if it isn't set.
+	GetSetting(ctx context.Context, key string) (string, error)
+	SetSetting(ctx context.Context, key string, value string) error
+}
+
+func (d *dao) GetSetting(ctx context.Context, key string) (string, error) {
+	const query = `SELECT value FROM settings WHERE key = $1`
+
+	var value string
+	err := d.db.GetContext(ctx, &value, query, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func (d *dao) SetSetting(ctx context.Context, key string, value string) error {
+	const query = `INSERT INTO settings (key, value) VALUES ($1, $2) ON CONFLICT(key) DO UPDATE SET value = excluded.value`
+
+	_, err := d.db.ExecContext(ctx, query, key, value)
+	return err
+}
+
+// LazySettings reads the settings of the default database, that is only opened when a setting is read.
+type LazySettings struct{}
+
+func (LazySettings) GetSetting(ctx context.Context, key string) (string, error) {
+	dao, err := New()
+	if err != nil {
+		return "", err
+	}
+	return dao.GetSetting(ctx, key)
+}
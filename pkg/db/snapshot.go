@@ -0,0 +1,15 @@
+package db
+
+import (
+	"context"
+)
+
+type SnapshotDAO interface {
+	// Snapshot writes a consistent copy of the database to path, that must not exist yet.
+	Snapshot(ctx context.Context, path string) error
+}
+
+func (d *dao) Snapshot(ctx context.Context, path string) error {
+	_, err := d.db.ExecContext(ctx, `VACUUM INTO $1`, path)
+	return err
+}
@@ -17,6 +17,7 @@ type WorkingSetDAO interface {
 	UpdateWorkingSet(ctx context.Context, workingSet WorkingSet) error
 	RemoveWorkingSet(ctx context.Context, id string) error
 	SearchWorkingSets(ctx context.Context, query string, workingSetID string) ([]WorkingSet, error)
+	SearchWorkingSetsByPrefix(ctx context.Context, prefix string, workingSetID string) ([]WorkingSet, error)
 }
 
 type ServerList []Server
@@ -28,6 +29,8 @@ type WorkingSet struct {
 	Name    string     `db:"name"`
 	Servers ServerList `db:"servers"`
 	Secrets SecretMap  `db:"secrets"`
+	// Docker context the profile is bound to, if any
+	DockerContext string `db:"docker_context"`
 }
 
 type Server struct {
@@ -39,12 +42,33 @@ type Server struct {
 	Image    string         `json:"image,omitempty"`
 	Endpoint string         `json:"endpoint,omitempty"`
 
+	OAuthScopes    []string          `json:"oauth_scopes,omitempty"`
+	ToolTransforms map[string]string `json:"tool_transforms,omitempty"`
+	UpdatePolicy   string            `json:"update_policy,omitempty"`
+	// Enabled is nil for servers that are started by default
+	Enabled *bool `json:"enabled,omitempty"`
+	// Dev overrides the container of the server with the gateway's --dev flag
+	Dev *ServerDev `json:"dev,omitempty"`
+
 	// Optional snapshot of the server schema
 	Snapshot *ServerSnapshot `json:"snapshot,omitempty"`
+
+	// Config, encrypted when the database encryption is on
+	EncryptedConfig string `json:"encrypted_config,omitempty"`
 }
 
 type Secret struct {
 	Provider string `json:"provider"`
+	Region   string `json:"region,omitempty"`
+	Prefix   string `json:"prefix,omitempty"`
+	Vault    string `json:"vault,omitempty"`
+	// References maps secret names to provider specific references
+	References map[string]string `json:"references,omitempty"`
+}
+
+type ServerDev struct {
+	Volumes []string          `json:"volumes,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
 }
 
 type ServerSnapshot struct {
@@ -102,13 +126,16 @@ func (secrets *SecretMap) Scan(value any) error {
 }
 
 func (d *dao) GetWorkingSet(ctx context.Context, id string) (*WorkingSet, error) {
-	const query = `SELECT id, name, servers, secrets FROM working_set WHERE id = $1`
+	const query = `SELECT id, name, servers, secrets, docker_context FROM working_set WHERE id = $1`
 
 	var workingSet WorkingSet
 	err := d.db.GetContext(ctx, &workingSet, query, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := d.decryptWorkingSet(ctx, &workingSet); err != nil {
+		return nil, err
+	}
 	return &workingSet, nil
 }
 
@@ -123,9 +150,14 @@ func (d *dao) RemoveWorkingSet(ctx context.Context, id string) error {
 }
 
 func (d *dao) CreateWorkingSet(ctx context.Context, workingSet WorkingSet) error {
-	const query = `INSERT INTO working_set (id, name, servers, secrets) VALUES ($1, $2, $3, $4)`
+	const query = `INSERT INTO working_set (id, name, servers, secrets, docker_context) VALUES ($1, $2, $3, $4, $5)`
 
-	_, err := d.db.ExecContext(ctx, query, workingSet.ID, workingSet.Name, workingSet.Servers, workingSet.Secrets)
+	servers, err := d.encryptServers(ctx, workingSet.Servers)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.db.ExecContext(ctx, query, workingSet.ID, workingSet.Name, servers, workingSet.Secrets, workingSet.DockerContext)
 	if err != nil {
 		return err
 	}
@@ -133,9 +165,14 @@ func (d *dao) CreateWorkingSet(ctx context.Context, workingSet WorkingSet) error
 }
 
 func (d *dao) UpdateWorkingSet(ctx context.Context, workingSet WorkingSet) error {
-	const query = `UPDATE working_set SET name = $2, servers = $3, secrets = $4 WHERE id = $1`
+	const query = `UPDATE working_set SET name = $2, servers = $3, secrets = $4, docker_context = $5 WHERE id = $1`
+
+	servers, err := d.encryptServers(ctx, workingSet.Servers)
+	if err != nil {
+		return err
+	}
 
-	_, err := d.db.ExecContext(ctx, query, workingSet.ID, workingSet.Name, workingSet.Servers, workingSet.Secrets)
+	_, err = d.db.ExecContext(ctx, query, workingSet.ID, workingSet.Name, servers, workingSet.Secrets, workingSet.DockerContext)
 	if err != nil {
 		return err
 	}
@@ -143,46 +180,66 @@ func (d *dao) UpdateWorkingSet(ctx context.Context, workingSet WorkingSet) error
 }
 
 func (d *dao) FindWorkingSetsByIDPrefix(ctx context.Context, prefix string) ([]WorkingSet, error) {
-	const query = `SELECT id, name, servers, secrets FROM working_set WHERE id LIKE $1`
+	const query = `SELECT id, name, servers, secrets, docker_context FROM working_set WHERE id LIKE $1`
 
 	var workingSets []WorkingSet
 	err := d.db.SelectContext(ctx, &workingSets, query, prefix+"%")
 	if err != nil {
 		return nil, err
 	}
+	if err := d.decryptWorkingSets(ctx, workingSets); err != nil {
+		return nil, err
+	}
 	return workingSets, nil
 }
 
 func (d *dao) ListWorkingSets(ctx context.Context) ([]WorkingSet, error) {
-	const query = `SELECT id, name, servers, secrets FROM working_set`
+	const query = `SELECT id, name, servers, secrets, docker_context FROM working_set`
 
 	var workingSets []WorkingSet
 	err := d.db.SelectContext(ctx, &workingSets, query)
 	if err != nil {
 		return nil, err
 	}
+	if err := d.decryptWorkingSets(ctx, workingSets); err != nil {
+		return nil, err
+	}
 	return workingSets, nil
 }
 
+// SearchWorkingSets returns the working sets with a server whose image, source, endpoint or name contains the query,
+// ignoring the case.
 func (d *dao) SearchWorkingSets(ctx context.Context, query string, workingSetID string) ([]WorkingSet, error) {
+	return d.searchWorkingSets(ctx, query, workingSetID, false)
+}
+
+// SearchWorkingSetsByPrefix returns the working sets with a server whose image, source, endpoint or name starts with
+// the prefix, ignoring the case. Unlike SearchWorkingSets, it's answered from an index.
+func (d *dao) SearchWorkingSetsByPrefix(ctx context.Context, prefix string, workingSetID string) ([]WorkingSet, error) {
+	return d.searchWorkingSets(ctx, prefix, workingSetID, true)
+}
+
+func (d *dao) searchWorkingSets(ctx context.Context, query string, workingSetID string, prefix bool) ([]WorkingSet, error) {
 	sqlQuery := `
-		SELECT id, name, servers, secrets
+		SELECT id, name, servers, secrets, docker_context
 		FROM working_set
 		WHERE ($1 = '' OR id = $1)
-		  AND ($2 = '' OR EXISTS (
-			SELECT 1
-			FROM json_each(servers)
-			WHERE LOWER(json_extract(value, '$.image')) LIKE '%' || LOWER($2) || '%'
-			   OR LOWER(json_extract(value, '$.source')) LIKE '%' || LOWER($2) || '%'
+		  AND ($2 = '' OR id IN (
+			SELECT working_set_id
+			FROM working_set_server_search
+			WHERE ` + searchCondition("value", prefix, 2) + `
 		  ))
 		ORDER BY id
 	`
-	args := []any{workingSetID, query}
+	args := []any{workingSetID, query, searchPrefixEnd}
 
 	var workingSets []WorkingSet
 	err := d.db.SelectContext(ctx, &workingSets, sqlQuery, args...)
 	if err != nil {
 		return nil, err
 	}
+	if err := d.decryptWorkingSets(ctx, workingSets); err != nil {
+		return nil, err
+	}
 	return workingSets, nil
 }
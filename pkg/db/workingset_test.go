@@ -10,7 +10,7 @@ import (
 )
 
 // setupTestDB creates a temporary database for testing
-func setupTestDB(t *testing.T) DAO {
+func setupTestDB(t testing.TB) DAO {
 	t.Helper()
 
 	tempDir := t.TempDir()
@@ -24,16 +24,21 @@ type Client interface {
 	StopContainer(ctx context.Context, containerID string, timeout int) error
 	FindContainerByLabel(ctx context.Context, label string) (string, error)
 	FindAllContainersByLabel(ctx context.Context, label string) ([]string, error)
+	ListContainersByLabel(ctx context.Context, label string) ([]container.Summary, error)
 	InspectContainer(ctx context.Context, containerID string) (container.InspectResponse, error)
 	ReadLogs(ctx context.Context, containerID string, options container.LogsOptions) (io.ReadCloser, error)
 	ImageExists(ctx context.Context, name string) (bool, error)
 	InspectImage(ctx context.Context, name string) (image.InspectResponse, error)
 	PullImage(ctx context.Context, name string) error
 	PullImages(ctx context.Context, names ...string) error
+	PullImageWithOptions(ctx context.Context, name string, options PullOptions) error
+	ServerPlatform(ctx context.Context) (string, error)
 	CreateNetwork(ctx context.Context, name string, internal bool, labels map[string]string) error
 	RemoveNetwork(ctx context.Context, name string) error
 	ConnectNetwork(ctx context.Context, networkName, container, hostname string) error
 	InspectVolume(ctx context.Context, name string) (volume.Volume, error)
+	CreateVolume(ctx context.Context, name string, driverOpts, labels map[string]string) error
+	RemoveVolume(ctx context.Context, name string, force bool) error
 	ReadSecrets(ctx context.Context, names []string, lenient bool) (map[string]string, error)
 }
 
@@ -56,6 +61,16 @@ func NewClient(cli command.Cli) Client {
 	}
 }
 
+// NewClientFromAPI returns a Client that talks to the Docker engine through apiClient,
+// for programs that don't run as a docker CLI plugin.
+func NewClientFromAPI(apiClient client.APIClient) Client {
+	return &dockerClient{
+		apiClient: func() client.APIClient {
+			return apiClient
+		},
+	}
+}
+
 func RunningInDockerCE(ctx context.Context, dockerCli command.Cli) (bool, error) {
 	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
 		return false, nil
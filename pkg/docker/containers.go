@@ -81,6 +81,14 @@ func (c *dockerClient) FindAllContainersByLabel(ctx context.Context, label strin
 	return ids, nil
 }
 
+// ListContainersByLabel lists the containers with a label, stopped ones included.
+func (c *dockerClient) ListContainersByLabel(ctx context.Context, label string) ([]container.Summary, error) {
+	return c.apiClient().ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", label)),
+	})
+}
+
 // Logs will fetch both STDOUT and STDERR from the current container. Returns a
 // ReadCloser and leaves it up to the caller to extract what it wants.
 //
@@ -2,6 +2,8 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -16,7 +18,7 @@ import (
 )
 
 func (c *dockerClient) ImageExists(ctx context.Context, name string) (bool, error) {
-	_, err := c.apiClient().ContainerInspect(ctx, name)
+	_, err := c.apiClient().ImageInspect(ctx, name)
 	if cerrdefs.IsNotFound(err) {
 		return false, nil
 	}
@@ -34,7 +36,7 @@ func (c *dockerClient) PullImages(ctx context.Context, names ...string) error {
 
 	for _, name := range names {
 		errs.Go(func() error {
-			return c.pullImage(ctx, name, registryAuthFn)
+			return c.pullImage(ctx, name, PullOptions{}, registryAuthFn)
 		})
 	}
 
@@ -42,21 +44,67 @@ func (c *dockerClient) PullImages(ctx context.Context, names ...string) error {
 }
 
 func (c *dockerClient) PullImage(ctx context.Context, name string) error {
-	return c.pullImage(ctx, name, func() string {
+	return c.PullImageWithOptions(ctx, name, PullOptions{})
+}
+
+// PullOptions tune how an image is pulled.
+type PullOptions struct {
+	// Platform of the image to pull, like linux/amd64.
+	// An empty platform lets the daemon pick the variant that matches its own platform.
+	Platform string
+	// Always pulls the image, even if it's already present locally.
+	Always bool
+	// Progress, if set, is called as the layers of the image are downloaded.
+	Progress func(PullProgress)
+}
+
+// PullProgress is the number of bytes downloaded so far, for all the layers of an image.
+// Total grows as the size of more layers becomes known.
+type PullProgress struct {
+	Current int64
+	Total   int64
+}
+
+func (c *dockerClient) PullImageWithOptions(ctx context.Context, name string, options PullOptions) error {
+	return c.pullImage(ctx, name, options, func() string {
 		return getRegistryAuth(ctx)
 	})
 }
 
+// ServerPlatform returns the platform of the Docker daemon, like linux/arm64.
+func (c *dockerClient) ServerPlatform(ctx context.Context) (string, error) {
+	version, err := c.apiClient().ServerVersion(ctx)
+	if err != nil {
+		return "", fmt.Errorf("getting docker server version: %w", err)
+	}
+
+	return version.Os + "/" + version.Arch, nil
+}
+
+// ImagePlatform returns the platform of an image, like linux/amd64.
+func ImagePlatform(inspect image.InspectResponse) string {
+	platform := inspect.Os + "/" + inspect.Architecture
+	if inspect.Variant != "" {
+		platform += "/" + inspect.Variant
+	}
+	return platform
+}
+
 func (c *dockerClient) InspectImage(ctx context.Context, name string) (image.InspectResponse, error) {
 	return c.apiClient().ImageInspect(ctx, name)
 }
 
-func (c *dockerClient) pullImage(ctx context.Context, imageName string, registryAuthFn func() string) error {
+func (c *dockerClient) pullImage(ctx context.Context, imageName string, options PullOptions, registryAuthFn func() string) error {
 	inspect, err := c.apiClient().ImageInspect(ctx, imageName)
 	if err != nil && !cerrdefs.IsNotFound(err) {
 		return fmt.Errorf("inspecting docker image %s: %w", imageName, err)
 	}
 
+	// The image we have locally is for another platform, or must be pulled again
+	if err == nil && (options.Always || (options.Platform != "" && !strings.HasPrefix(ImagePlatform(inspect)+"/", options.Platform+"/"))) {
+		inspect = image.InspectResponse{}
+	}
+
 	if len(inspect.RepoDigests) > 0 {
 		if inspect.RepoDigests[0] == imageName {
 			return nil
@@ -75,7 +123,9 @@ func (c *dockerClient) pullImage(ctx context.Context, imageName string, registry
 		}
 	}
 
-	var pullOptions image.PullOptions
+	pullOptions := image.PullOptions{
+		Platform: options.Platform,
+	}
 	if strings.HasPrefix(ref.Name(), "docker.io/") {
 		pullOptions.RegistryAuth = registryAuthFn()
 	}
@@ -86,9 +136,70 @@ func (c *dockerClient) pullImage(ctx context.Context, imageName string, registry
 		return nil
 	}
 
-	if _, err := io.Copy(io.Discard, response); err != nil {
+	defer response.Close()
+
+	if options.Progress == nil {
+		if _, err := io.Copy(io.Discard, response); err != nil {
+			return fmt.Errorf("pulling docker image %s: %w", imageName, err)
+		}
+		return nil
+	}
+
+	if err := readPullProgress(response, options.Progress); err != nil {
 		return fmt.Errorf("pulling docker image %s: %w", imageName, err)
 	}
 
 	return nil
 }
+
+// pullMessage is one of the JSON messages streamed by the engine while pulling an image.
+type pullMessage struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+}
+
+// readPullProgress reads the messages streamed while pulling an image, and reports the download progress of its layers.
+func readPullProgress(r io.Reader, progress func(PullProgress)) error {
+	layers := map[string]*PullProgress{}
+
+	decoder := json.NewDecoder(r)
+	for {
+		var msg pullMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if msg.ID == "" {
+			continue
+		}
+
+		layer, found := layers[msg.ID]
+		if !found {
+			layer = &PullProgress{}
+			layers[msg.ID] = layer
+		}
+
+		switch msg.Status {
+		case "Downloading":
+			layer.Current = msg.ProgressDetail.Current
+			layer.Total = msg.ProgressDetail.Total
+		case "Download complete", "Pull complete", "Already exists":
+			layer.Current = layer.Total
+		default:
+			continue
+		}
+
+		var total PullProgress
+		for _, layer := range layers {
+			total.Current += layer.Current
+			total.Total += layer.Total
+		}
+		progress(total)
+	}
+}
@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPullProgress(t *testing.T) {
+	stream := `{"status":"Pulling from mcp/server","id":"latest"}
+{"status":"Pulling fs layer","progressDetail":{},"id":"a"}
+{"status":"Already exists","progressDetail":{},"id":"b"}
+{"status":"Downloading","progressDetail":{"current":100,"total":1000},"id":"a"}
+{"status":"Pulling fs layer","progressDetail":{},"id":"c"}
+{"status":"Downloading","progressDetail":{"current":50,"total":500},"id":"c"}
+{"status":"Downloading","progressDetail":{"current":600,"total":1000},"id":"a"}
+{"status":"Download complete","progressDetail":{},"id":"c"}
+{"status":"Download complete","progressDetail":{},"id":"a"}
+{"status":"Extracting","progressDetail":{"current":1000,"total":1000},"id":"a"}
+{"status":"Pull complete","progressDetail":{},"id":"a"}
+{"status":"Digest: sha256:0123"}
+`
+
+	var progress []PullProgress
+	err := readPullProgress(strings.NewReader(stream), func(p PullProgress) {
+		progress = append(progress, p)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []PullProgress{
+		{Current: 0, Total: 0},
+		{Current: 100, Total: 1000},
+		{Current: 150, Total: 1500},
+		{Current: 650, Total: 1500},
+		{Current: 1100, Total: 1500},
+		{Current: 1500, Total: 1500},
+		{Current: 1500, Total: 1500},
+	}, progress)
+}
+
+func TestReadPullProgressInvalidStream(t *testing.T) {
+	err := readPullProgress(strings.NewReader(`{"status":`), func(PullProgress) {})
+	require.Error(t, err)
+}
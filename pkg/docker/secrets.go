@@ -9,6 +9,9 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
 
 	"github.com/docker/mcp-gateway/pkg/desktop"
 	"github.com/docker/mcp-gateway/pkg/log"
@@ -96,17 +99,29 @@ func (c *dockerClient) readSecrets(ctx context.Context, names []string) (map[str
 // readSecretsOneByOne reads secrets one by one, which is useful for lenient mode.
 // It's slower but can handle cases where some secrets might not exist.
 func (c *dockerClient) readSecretsOneByOneOptional(ctx context.Context, names []string) (map[string]string, error) {
-	secrets := map[string]string{}
+	var (
+		lock    sync.Mutex
+		secrets = map[string]string{}
+	)
+
+	errs, ctx := errgroup.WithContext(ctx)
+	errs.SetLimit(runtime.NumCPU())
 
 	for _, name := range names {
-		values, err := c.readSecrets(ctx, []string{name})
-		if err != nil {
-			log.Logf("couldn't read secret %s: %v", name, err)
-			continue
-		}
+		errs.Go(func() error {
+			values, err := c.readSecrets(ctx, []string{name})
+			if err != nil {
+				log.Logf("couldn't read secret %s: %v", name, err)
+				return nil
+			}
 
-		maps.Copy(secrets, values)
+			lock.Lock()
+			maps.Copy(secrets, values)
+			lock.Unlock()
+			return nil
+		})
 	}
 
+	_ = errs.Wait()
 	return secrets, nil
 }
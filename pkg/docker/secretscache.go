@@ -0,0 +1,150 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/user"
+)
+
+// secretsChangedFile is touched each time secrets are set or removed, so that running gateways drop the secrets they cached.
+const secretsChangedFile = ".secrets-changed"
+
+// InvalidateSecretsCache tells the running gateways that the secrets they cached are stale.
+func InvalidateSecretsCache() error {
+	path, err := secretsChangedPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return os.Chtimes(path, now, now)
+}
+
+func secretsChangedPath() (string, error) {
+	homeDir, err := user.HomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".docker", "mcp", secretsChangedFile), nil
+}
+
+type cachedSecret struct {
+	value  string
+	found  bool
+	readAt time.Time
+}
+
+// secretsCache is a Client that caches the secrets it reads, for a while.
+// Only the secrets that are not cached are read, in a single batch.
+type secretsCache struct {
+	Client
+	ttl         time.Duration
+	changedPath string
+	now         func() time.Time
+
+	mu        sync.Mutex
+	secrets   map[string]cachedSecret
+	changedAt time.Time
+}
+
+// WithSecretsCache caches the secrets read by a client for a given duration, or until they're changed with
+// `docker mcp secret set` or `docker mcp secret rm`. A zero duration disables the cache.
+func WithSecretsCache(client Client, ttl time.Duration) Client {
+	if ttl <= 0 {
+		return client
+	}
+
+	changedPath, _ := secretsChangedPath()
+	return &secretsCache{
+		Client:      client,
+		ttl:         ttl,
+		changedPath: changedPath,
+		now:         time.Now,
+		secrets:     map[string]cachedSecret{},
+	}
+}
+
+func (c *secretsCache) ReadSecrets(ctx context.Context, names []string, lenient bool) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dropIfChanged()
+
+	secrets := map[string]string{}
+	var missing []string
+	for _, name := range names {
+		cached, ok := c.secrets[name]
+		// Secrets that were not found are read again when they're required
+		if !ok || c.now().Sub(cached.readAt) >= c.ttl || (!cached.found && !lenient) {
+			missing = append(missing, name)
+			continue
+		}
+		if cached.found {
+			secrets[name] = cached.value
+		}
+	}
+	if len(missing) == 0 {
+		return secrets, nil
+	}
+
+	values, err := c.Client.ReadSecrets(ctx, missing, lenient)
+	if err != nil {
+		return nil, err
+	}
+
+	readAt := c.now()
+	for _, name := range missing {
+		value, found := values[name]
+		c.secrets[name] = cachedSecret{
+			value:  value,
+			found:  found,
+			readAt: readAt,
+		}
+		if found {
+			secrets[name] = value
+		}
+	}
+
+	return secrets, nil
+}
+
+// dropIfChanged empties the cache if secrets were set or removed since they were cached.
+func (c *secretsCache) dropIfChanged() {
+	if c.changedPath == "" {
+		return
+	}
+
+	stat, err := os.Stat(c.changedPath)
+	if err != nil {
+		return
+	}
+
+	if !stat.ModTime().Equal(c.changedAt) {
+		c.changedAt = stat.ModTime()
+		c.secrets = map[string]cachedSecret{}
+	}
+}
+
+// FlushSecretsCache drops the secrets cached by a client returned by WithSecretsCache, so that they're read again.
+func FlushSecretsCache(client Client) {
+	cache, ok := client.(*secretsCache)
+	if !ok {
+		return
+	}
+
+	cache.mu.Lock()
+	cache.secrets = map[string]cachedSecret{}
+	cache.mu.Unlock()
+}
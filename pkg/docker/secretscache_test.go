@@ -0,0 +1,111 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSecrets struct {
+	Client
+	values map[string]string
+	reads  [][]string
+}
+
+func (f *fakeSecrets) ReadSecrets(_ context.Context, names []string, _ bool) (map[string]string, error) {
+	f.reads = append(f.reads, names)
+
+	secrets := map[string]string{}
+	for _, name := range names {
+		if value, found := f.values[name]; found {
+			secrets[name] = value
+		}
+	}
+	return secrets, nil
+}
+
+func newTestSecretsCache(t *testing.T, fake *fakeSecrets) (*secretsCache, *time.Time) {
+	t.Helper()
+
+	now := time.Now()
+	cache := WithSecretsCache(fake, time.Minute).(*secretsCache)
+	cache.changedPath = filepath.Join(t.TempDir(), secretsChangedFile)
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func TestSecretsCache(t *testing.T) {
+	fake := &fakeSecrets{values: map[string]string{"github.token": "gh", "slack.token": "sl"}}
+	cache, now := newTestSecretsCache(t, fake)
+
+	secrets, err := cache.ReadSecrets(t.Context(), []string{"github.token"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"github.token": "gh"}, secrets)
+
+	// Only the secrets that are not cached are read
+	secrets, err = cache.ReadSecrets(t.Context(), []string{"github.token", "slack.token", "unknown"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"github.token": "gh", "slack.token": "sl"}, secrets)
+	assert.Equal(t, [][]string{{"github.token"}, {"slack.token", "unknown"}}, fake.reads)
+
+	// Everything is cached, including the secrets that don't exist
+	_, err = cache.ReadSecrets(t.Context(), []string{"github.token", "slack.token", "unknown"}, true)
+	require.NoError(t, err)
+	assert.Len(t, fake.reads, 2)
+
+	// Secrets that don't exist are read again when they're required
+	_, err = cache.ReadSecrets(t.Context(), []string{"unknown"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"unknown"}, fake.reads[2])
+
+	// Secrets expire
+	*now = now.Add(time.Minute)
+	_, err = cache.ReadSecrets(t.Context(), []string{"github.token"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github.token"}, fake.reads[3])
+}
+
+func TestSecretsCacheInvalidation(t *testing.T) {
+	fake := &fakeSecrets{values: map[string]string{"github.token": "old"}}
+	cache, _ := newTestSecretsCache(t, fake)
+
+	secrets, err := cache.ReadSecrets(t.Context(), []string{"github.token"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "old", secrets["github.token"])
+
+	fake.values["github.token"] = "new"
+	require.NoError(t, os.WriteFile(cache.changedPath, nil, 0o644))
+
+	secrets, err = cache.ReadSecrets(t.Context(), []string{"github.token"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "new", secrets["github.token"])
+	assert.Len(t, fake.reads, 2)
+}
+
+func TestSecretsCacheDisabled(t *testing.T) {
+	fake := &fakeSecrets{}
+	assert.Same(t, Client(fake), WithSecretsCache(fake, 0))
+}
+
+func TestFlushSecretsCache(t *testing.T) {
+	fake := &fakeSecrets{values: map[string]string{"github.token": "old"}}
+	cache, _ := newTestSecretsCache(t, fake)
+
+	_, err := cache.ReadSecrets(t.Context(), []string{"github.token"}, true)
+	require.NoError(t, err)
+
+	fake.values["github.token"] = "new"
+	FlushSecretsCache(cache)
+
+	secrets, err := cache.ReadSecrets(t.Context(), []string{"github.token"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "new", secrets["github.token"])
+
+	// Clients without a cache are left alone
+	FlushSecretsCache(fake)
+}
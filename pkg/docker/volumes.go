@@ -9,3 +9,17 @@ import (
 func (c *dockerClient) InspectVolume(ctx context.Context, name string) (volume.Volume, error) {
 	return c.apiClient().VolumeInspect(ctx, name)
 }
+
+func (c *dockerClient) CreateVolume(ctx context.Context, name string, driverOpts, labels map[string]string) error {
+	_, err := c.apiClient().VolumeCreate(ctx, volume.CreateOptions{
+		Name:       name,
+		Driver:     "local",
+		DriverOpts: driverOpts,
+		Labels:     labels,
+	})
+	return err
+}
+
+func (c *dockerClient) RemoveVolume(ctx context.Context, name string, force bool) error {
+	return c.apiClient().VolumeRemove(ctx, name, force)
+}
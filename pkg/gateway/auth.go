@@ -31,6 +31,11 @@ func generateAuthToken() (string, error) {
 	return string(token), nil
 }
 
+// GenerateAuthToken generates a random token for the MCP_GATEWAY_AUTH_TOKEN environment variable.
+func GenerateAuthToken() (string, error) {
+	return generateAuthToken()
+}
+
 // getOrGenerateAuthToken retrieves the auth token from environment variable MCP_GATEWAY_AUTH_TOKEN
 // or generates a new one if not set or empty
 func getOrGenerateAuthToken() (string, bool, error) {
@@ -49,11 +54,11 @@ func getOrGenerateAuthToken() (string, bool, error) {
 // authenticationMiddleware creates an HTTP middleware that validates requests using
 // Bearer token in the Authorization header.
 //
-// The /health endpoint is excluded from authentication.
+// The /health and discovery endpoints are excluded from authentication.
 func authenticationMiddleware(authToken string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip authentication for health check endpoint
-		if r.URL.Path == "/health" {
+		// Skip authentication for health check and discovery endpoints
+		if r.URL.Path == "/health" || r.URL.Path == discoveryPath {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -61,16 +66,10 @@ func authenticationMiddleware(authToken string, next http.Handler) http.Handler
 		authenticated := false
 
 		// Check for Bearer token in Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader != "" {
-			// Extract Bearer token from "Bearer <token>" format
-			const bearerPrefix = "Bearer "
-			if len(authHeader) > len(bearerPrefix) && authHeader[:len(bearerPrefix)] == bearerPrefix {
-				bearerToken := authHeader[len(bearerPrefix):]
-				// Use constant-time comparison to prevent timing attacks
-				if subtle.ConstantTimeCompare([]byte(bearerToken), []byte(authToken)) == 1 {
-					authenticated = true
-				}
+		if token, found := bearerToken(r); found {
+			// Use constant-time comparison to prevent timing attacks
+			if subtle.ConstantTimeCompare([]byte(token), []byte(authToken)) == 1 {
+				authenticated = true
 			}
 		}
 
@@ -86,6 +85,16 @@ func authenticationMiddleware(authToken string, next http.Handler) http.Handler
 	})
 }
 
+// bearerToken extracts the token of an "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) (string, bool) {
+	const bearerPrefix = "Bearer "
+	authHeader := r.Header.Get("Authorization")
+	if len(authHeader) <= len(bearerPrefix) || authHeader[:len(bearerPrefix)] != bearerPrefix {
+		return "", false
+	}
+	return authHeader[len(bearerPrefix):], true
+}
+
 // formatGatewayURL formats the gateway URL without authentication info
 func formatGatewayURL(port int, endpoint string) string {
 	return fmt.Sprintf("http://localhost:%d%s", port, endpoint)
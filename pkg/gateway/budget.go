@@ -0,0 +1,172 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+const (
+	// BudgetActionReject rejects the tool calls that would exceed the session budget.
+	BudgetActionReject = "reject"
+	// BudgetActionWarn logs a warning and lets the tool calls that exceed the session budget through.
+	BudgetActionWarn = "warn"
+)
+
+// BudgetActions lists the valid values for Options.BudgetAction.
+var BudgetActions = []string{BudgetActionReject, BudgetActionWarn}
+
+// BudgetStatus is the spend of a client session, reported by the mcp-status tool.
+type BudgetStatus struct {
+	Limit     float64            `json:"limit,omitempty"`
+	Action    string             `json:"action,omitempty"`
+	Spent     float64            `json:"spent"`
+	Remaining *float64           `json:"remaining,omitempty"`
+	Tools     map[string]float64 `json:"tools,omitempty"`
+}
+
+// budgetMiddleware charges the cost of each successful tool call to the session's budget.
+func (g *Gateway) budgetMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			cost, rejected := g.chargeToolCall(callReq.Session, callReq.Params.Name)
+			if rejected != nil {
+				return rejected, nil
+			}
+
+			result, err := next(ctx, method, req)
+			g.settleToolCall(ctx, callReq.Session, callReq.Params.Name, cost, callFailed(result, err))
+
+			return result, err
+		}
+	}
+}
+
+// chargeToolCall adds the cost of a tool to the session's spend, before the call runs, so that concurrent calls
+// can't go over the budget together. It returns the cost to pass to settleToolCall once the call is done.
+// Returns a non-nil result, to send back instead of calling the tool, when the call is rejected.
+func (g *Gateway) chargeToolCall(session *mcp.ServerSession, toolName string) (float64, *mcp.CallToolResult) {
+	if session == nil {
+		return 0, nil
+	}
+
+	g.capabilitiesMu.RLock()
+	toolReg, found := g.toolRegistrations[toolName]
+	g.capabilitiesMu.RUnlock()
+	if !found || toolReg.Cost <= 0 {
+		return 0, nil
+	}
+
+	g.sessionCacheMu.Lock()
+	cache := g.sessionCache[session]
+	if cache == nil {
+		cache = &ServerSessionCache{}
+		g.sessionCache[session] = cache
+	}
+	if cache.Spend == nil {
+		cache.Spend = make(map[string]float64)
+	}
+
+	spent := totalSpend(cache.Spend)
+	exceeded := g.SessionBudget > 0 && spent+toolReg.Cost > g.SessionBudget
+	if exceeded && g.BudgetAction != BudgetActionWarn {
+		g.sessionCacheMu.Unlock()
+
+		log.Logf("  ! Rejected call to %s: cost %g would exceed the session budget (%g/%g spent)", toolName, toolReg.Cost, spent, g.SessionBudget)
+		return 0, &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: fmt.Sprintf("The call to %s was not executed: it costs %g and only %g of the session budget of %g remains.", toolName, toolReg.Cost, max(g.SessionBudget-spent, 0), g.SessionBudget),
+			}},
+			IsError: true,
+		}
+	}
+	cache.Spend[toolName] += toolReg.Cost
+	g.sessionCacheMu.Unlock()
+
+	if exceeded {
+		log.Logf("  ! Call to %s exceeds the session budget: %g/%g spent", toolName, spent+toolReg.Cost, g.SessionBudget)
+	}
+
+	return toolReg.Cost, nil
+}
+
+// settleToolCall records the cost of a successful call. The calls that failed, or that the user declined,
+// give their cost back to the session.
+func (g *Gateway) settleToolCall(ctx context.Context, session *mcp.ServerSession, toolName string, cost float64, failed bool) {
+	if cost <= 0 {
+		return
+	}
+
+	if !failed {
+		telemetry.RecordToolCost(ctx, toolName, g.toolServerName(toolName), cost)
+		return
+	}
+
+	g.sessionCacheMu.Lock()
+	defer g.sessionCacheMu.Unlock()
+
+	cache := g.sessionCache[session]
+	if cache == nil || cache.Spend == nil {
+		return
+	}
+	cache.Spend[toolName] -= cost
+	if cache.Spend[toolName] <= 0 {
+		delete(cache.Spend, toolName)
+	}
+}
+
+// callFailed returns true if a tool call returned an error, or a result flagged as an error.
+func callFailed(result mcp.Result, err error) bool {
+	if err != nil {
+		return true
+	}
+	toolResult, ok := result.(*mcp.CallToolResult)
+	return ok && toolResult != nil && toolResult.IsError
+}
+
+// budgetStatus reports the spend of a session.
+func (g *Gateway) budgetStatus(session *mcp.ServerSession) *BudgetStatus {
+	status := &BudgetStatus{}
+	if g.SessionBudget > 0 {
+		status.Limit = g.SessionBudget
+		status.Action = g.BudgetAction
+	}
+
+	if session != nil {
+		g.sessionCacheMu.RLock()
+		if cache := g.sessionCache[session]; cache != nil && len(cache.Spend) > 0 {
+			status.Tools = maps.Clone(cache.Spend)
+			status.Spent = totalSpend(cache.Spend)
+		}
+		g.sessionCacheMu.RUnlock()
+	}
+
+	if g.SessionBudget > 0 {
+		remaining := max(g.SessionBudget-status.Spent, 0)
+		status.Remaining = &remaining
+	}
+
+	return status
+}
+
+func totalSpend(spend map[string]float64) float64 {
+	var total float64
+	for _, cost := range spend {
+		total += cost
+	}
+	return total
+}
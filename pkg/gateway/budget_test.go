@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBudgetGateway(budget float64, action string) *Gateway {
+	return &Gateway{
+		Options: Options{SessionBudget: budget, BudgetAction: action},
+		toolRegistrations: map[string]ToolRegistration{
+			"search": {ServerName: "web", Cost: 2},
+			"fetch":  {ServerName: "web"},
+		},
+		sessionCache: map[*mcp.ServerSession]*ServerSessionCache{},
+	}
+}
+
+// chargeRejected charges a tool call and returns the result sent back when it's rejected.
+func chargeRejected(g *Gateway, session *mcp.ServerSession, toolName string) *mcp.CallToolResult {
+	_, result := g.chargeToolCall(session, toolName)
+	return result
+}
+
+func TestChargeToolCallReject(t *testing.T) {
+	g := newBudgetGateway(5, BudgetActionReject)
+	session := &mcp.ServerSession{}
+
+	assert.Nil(t, chargeRejected(g, session, "search"))
+	assert.Nil(t, chargeRejected(g, session, "search"))
+	assert.Nil(t, chargeRejected(g, session, "fetch"))
+
+	result := chargeRejected(g, session, "search")
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+
+	status := g.budgetStatus(session)
+	assert.InDelta(t, 4, status.Spent, 0)
+	require.NotNil(t, status.Remaining)
+	assert.InDelta(t, 1, *status.Remaining, 0)
+	assert.Equal(t, map[string]float64{"search": 4}, status.Tools)
+}
+
+func TestChargeToolCallWarn(t *testing.T) {
+	g := newBudgetGateway(3, BudgetActionWarn)
+	session := &mcp.ServerSession{}
+
+	assert.Nil(t, chargeRejected(g, session, "search"))
+	assert.Nil(t, chargeRejected(g, session, "search"))
+
+	status := g.budgetStatus(session)
+	assert.InDelta(t, 4, status.Spent, 0)
+	require.NotNil(t, status.Remaining)
+	assert.InDelta(t, 0, *status.Remaining, 0)
+}
+
+func TestChargeToolCallWithoutBudget(t *testing.T) {
+	g := newBudgetGateway(0, BudgetActionReject)
+	session := &mcp.ServerSession{}
+
+	for range 10 {
+		assert.Nil(t, chargeRejected(g, session, "search"))
+	}
+
+	status := g.budgetStatus(session)
+	assert.InDelta(t, 20, status.Spent, 0)
+	assert.Nil(t, status.Remaining)
+}
+
+func TestBudgetIsPerSession(t *testing.T) {
+	g := newBudgetGateway(2, BudgetActionReject)
+	first := &mcp.ServerSession{}
+	second := &mcp.ServerSession{}
+
+	assert.Nil(t, chargeRejected(g, first, "search"))
+	assert.NotNil(t, chargeRejected(g, first, "search"))
+	assert.Nil(t, chargeRejected(g, second, "search"))
+}
+
+func TestBudgetMiddlewareChargesSuccessfulCalls(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *mcp.CallToolResult
+		err    error
+		spent  float64
+	}{
+		{name: "success", result: &mcp.CallToolResult{}, spent: 2},
+		{name: "error result", result: &mcp.CallToolResult{IsError: true}, spent: 0},
+		{name: "declined", result: confirmationDenied("search", "the user didn't confirm the call"), spent: 0},
+		{name: "error", err: errors.New("connection closed"), spent: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newBudgetGateway(3, BudgetActionReject)
+			session := &mcp.ServerSession{}
+			handler := g.budgetMiddleware()(func(context.Context, string, mcp.Request) (mcp.Result, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return tt.result, nil
+			})
+			_, err := handler(context.Background(), "tools/call", &mcp.CallToolRequest{
+				Session: session,
+				Params:  &mcp.CallToolParamsRaw{Name: "search"},
+			})
+			assert.Equal(t, tt.err, err)
+			assert.InDelta(t, tt.spent, g.budgetStatus(session).Spent, 0)
+		})
+	}
+}
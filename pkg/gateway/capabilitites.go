@@ -6,7 +6,7 @@ import (
 	"runtime"
 	"slices"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -28,6 +28,18 @@ type ToolRegistration struct {
 	ServerName string
 	Tool       *mcp.Tool
 	Handler    mcp.ToolHandler
+	// Cost is charged to the session's budget on each call
+	Cost float64
+	// ToolName is the name of the tool on its server, before it's prefixed or renamed by the gateway
+	ToolName string
+}
+
+// serverToolName returns the name of a tool on its server, which profiles refer to, given its name in the gateway.
+func (t ToolRegistration) serverToolName(name string) string {
+	if t.ToolName != "" {
+		return t.ToolName
+	}
+	return name
 }
 
 type PromptRegistration struct {
@@ -111,14 +123,12 @@ func (caps *Capabilities) getResourceTemplateByURITemplate(resource string) (Res
 }
 
 func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, clientConfig *clientConfig) (*Capabilities, error) {
-	var (
-		lock            sync.Mutex
-		allCapabilities []Capabilities
-	)
+	// Indexed like serverNames, so that the capabilities are merged in a stable order
+	allCapabilities := make([]Capabilities, len(serverNames))
 
 	errs, ctx := errgroup.WithContext(ctx)
 	errs.SetLimit(runtime.NumCPU())
-	for _, serverName := range serverNames {
+	for i, serverName := range serverNames {
 		serverConfig, toolGroup, found := g.configuration.Find(serverName)
 
 		switch {
@@ -128,26 +138,33 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 		// It's an MCP Server
 		case serverConfig != nil:
 			errs.Go(func() error {
+				defer g.startup.span("capabilities", serverConfig.Name)()
+
+				endStartSpan := g.startup.span("capabilities", serverConfig.Name, "start")
 				client, err := g.clientPool.AcquireClient(ctx, serverConfig, clientConfig)
+				endStartSpan()
 				if err != nil {
 					log.Logf("  > Can't start %s: %s", serverConfig.Name, err)
 					return nil
 				}
 				defer g.clientPool.ReleaseClient(client)
+				defer g.startup.span("capabilities", serverConfig.Name, "list")()
+
+				g.setServerInstructions(serverConfig, client.Session().InitializeResult())
 
 				var capabilities Capabilities
 
-				tools, err := client.Session().ListTools(ctx, &mcp.ListToolsParams{})
+				tools, err := listAllTools(ctx, client.Session())
 				if err != nil {
 					log.Logf("  > Can't list tools %s: %s", serverConfig.Name, err)
 				} else {
 					// Record the number of tools discovered from this server
-					telemetry.RecordToolList(ctx, serverConfig.Name, len(tools.Tools))
+					telemetry.RecordToolList(ctx, serverConfig.Name, len(tools))
 
 					// Determine the prefix to use for this server's tools
 					prefix := g.getToolNamePrefix(serverConfig)
 
-					for _, tool := range tools.Tools {
+					for _, tool := range tools {
 						if !isToolEnabled(g.configuration, serverConfig.Name, serverConfig.Spec.Image, tool.Name, g.ToolNames) {
 							continue
 						}
@@ -155,21 +172,26 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 						// Create a copy of the tool and apply prefix to its name
 						prefixedTool := *tool
 						prefixedTool.Name = prefixToolName(prefix, tool.Name)
+						prefixedTool.Meta = withToolset(tool.Meta, serverConfig.Spec.Toolsets.Toolset(tool.Name))
 
 						capabilities.Tools = append(capabilities.Tools, ToolRegistration{
 							ServerName: serverConfig.Name,
 							Tool:       &prefixedTool,
 							Handler:    g.mcpServerToolHandler(serverConfig.Name, g.mcpServer, tool.Annotations, tool.Name),
+							Cost:       serverConfig.Spec.ToolCosts[tool.Name],
+							ToolName:   tool.Name,
 						})
 					}
+
+					capabilities.Tools = append(capabilities.Tools, deprecatedToolRegistrations(serverConfig.Name, prefix, serverConfig.Spec.DeprecatedTools, capabilities.Tools, time.Now())...)
 				}
 
-				prompts, err := client.Session().ListPrompts(ctx, &mcp.ListPromptsParams{})
+				prompts, err := listAllPrompts(ctx, client.Session())
 				if err == nil {
 					// Record the number of prompts discovered from this server
-					telemetry.RecordPromptList(ctx, serverConfig.Name, len(prompts.Prompts))
+					telemetry.RecordPromptList(ctx, serverConfig.Name, len(prompts))
 
-					for _, prompt := range prompts.Prompts {
+					for _, prompt := range prompts {
 						capabilities.Prompts = append(capabilities.Prompts, PromptRegistration{
 							ServerName: serverConfig.Name,
 							Prompt:     prompt,
@@ -178,12 +200,12 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 					}
 				}
 
-				resources, err := client.Session().ListResources(ctx, &mcp.ListResourcesParams{})
+				resources, err := listAllResources(ctx, client.Session())
 				if err == nil {
 					// Record the number of resources discovered from this server
-					telemetry.RecordResourceList(ctx, serverConfig.Name, len(resources.Resources))
+					telemetry.RecordResourceList(ctx, serverConfig.Name, len(resources))
 
-					for _, resource := range resources.Resources {
+					for _, resource := range resources {
 						capabilities.Resources = append(capabilities.Resources, ResourceRegistration{
 							ServerName: serverConfig.Name,
 							Resource:   resource,
@@ -192,12 +214,12 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 					}
 				}
 
-				resourceTemplates, err := client.Session().ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{})
+				resourceTemplates, err := listAllResourceTemplates(ctx, client.Session())
 				if err == nil {
 					// Record the number of resource templates discovered from this server
-					telemetry.RecordResourceTemplateList(ctx, serverConfig.Name, len(resourceTemplates.ResourceTemplates))
+					telemetry.RecordResourceTemplateList(ctx, serverConfig.Name, len(resourceTemplates))
 
-					for _, resourceTemplate := range resourceTemplates.ResourceTemplates {
+					for _, resourceTemplate := range resourceTemplates {
 						capabilities.ResourceTemplates = append(capabilities.ResourceTemplates, ResourceTemplateRegistration{
 							ServerName:       serverConfig.Name,
 							ResourceTemplate: *resourceTemplate,
@@ -223,9 +245,8 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 					log.Logf("  > %s:%s", serverConfig.Name, logMsg)
 				}
 
-				lock.Lock()
-				allCapabilities = append(allCapabilities, capabilities)
-				lock.Unlock()
+				capabilities.Resources = append(capabilities.Resources, g.serverCardResource(serverConfig.Name))
+				allCapabilities[i] = capabilities
 
 				return nil
 			})
@@ -265,14 +286,15 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 				}
 
 				capabilities.Tools = append(capabilities.Tools, ToolRegistration{
-					Tool:    &mcpTool,
-					Handler: g.mcpToolHandler(tool),
+					ServerName: serverName,
+					Tool:       &mcpTool,
+					Handler:    g.mcpToolHandler(tool),
+					Cost:       tool.Cost,
+					ToolName:   tool.Name,
 				})
 			}
 
-			lock.Lock()
-			allCapabilities = append(allCapabilities, capabilities)
-			lock.Unlock()
+			allCapabilities[i] = capabilities
 		}
 	}
 
@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+// CleanupOptions selects the containers removed by Cleanup.
+type CleanupOptions struct {
+	// GatewayID removes the containers of a given gateway instance, running or not.
+	GatewayID string
+	// All removes all the containers started by a gateway.
+	All bool
+	// DryRun only lists the containers that would be removed.
+	DryRun bool
+}
+
+// CleanedContainer is a container removed by Cleanup.
+type CleanedContainer struct {
+	ID        string
+	Name      string
+	Server    string
+	GatewayID string
+}
+
+// Cleanup removes the containers left behind by gateways. By default, only the containers of the gateways
+// that ran on this host and whose process is gone are removed.
+func Cleanup(ctx context.Context, dockerClient docker.Client, options CleanupOptions) ([]CleanedContainer, error) {
+	containers, err := dockerClient.ListContainersByLabel(ctx, LabelGateway+"=true")
+	if err != nil {
+		return nil, fmt.Errorf("listing containers: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	selected := containersToCleanup(containers, options, hostname, processRunning)
+
+	var cleaned []CleanedContainer
+	var errs []error
+	for _, ctr := range selected {
+		if !options.DryRun {
+			if err := dockerClient.RemoveContainer(ctx, ctr.ID, true); err != nil {
+				errs = append(errs, fmt.Errorf("removing container %s: %w", ctr.ID, err))
+				continue
+			}
+		}
+
+		var name string
+		if len(ctr.Names) > 0 {
+			name = strings.TrimPrefix(ctr.Names[0], "/")
+		}
+		cleaned = append(cleaned, CleanedContainer{
+			ID:        ctr.ID,
+			Name:      name,
+			Server:    ctr.Labels[LabelServer],
+			GatewayID: ctr.Labels[LabelGatewayID],
+		})
+	}
+
+	return cleaned, errors.Join(errs...)
+}
+
+func containersToCleanup(containers []container.Summary, options CleanupOptions, hostname string, running func(pid int) bool) []container.Summary {
+	var selected []container.Summary
+	for _, ctr := range containers {
+		switch {
+		case options.All:
+		case options.GatewayID != "":
+			if ctr.Labels[LabelGatewayID] != options.GatewayID {
+				continue
+			}
+		default:
+			// Containers of gateways without an instance id, or on other hosts, can't be told orphaned.
+			if ctr.Labels[LabelGatewayID] == "" || ctr.Labels[LabelGatewayHost] != hostname {
+				continue
+			}
+			pid, err := strconv.Atoi(ctr.Labels[LabelGatewayPID])
+			if err != nil || running(pid) {
+				continue
+			}
+		}
+		selected = append(selected, ctr)
+	}
+	return selected
+}
@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"os"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainersToCleanup(t *testing.T) {
+	containers := []container.Summary{
+		{ID: "orphan", Labels: map[string]string{LabelGatewayID: "a", LabelGatewayHost: "host", LabelGatewayPID: "10"}},
+		{ID: "running", Labels: map[string]string{LabelGatewayID: "b", LabelGatewayHost: "host", LabelGatewayPID: "20"}},
+		{ID: "other-host", Labels: map[string]string{LabelGatewayID: "c", LabelGatewayHost: "other", LabelGatewayPID: "10"}},
+		{ID: "unlabelled", Labels: map[string]string{LabelGateway: "true"}},
+	}
+	running := func(pid int) bool { return pid == 20 }
+
+	ids := func(options CleanupOptions) []string {
+		var ids []string
+		for _, ctr := range containersToCleanup(containers, options, "host", running) {
+			ids = append(ids, ctr.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"orphan"}, ids(CleanupOptions{}))
+	assert.Equal(t, []string{"running"}, ids(CleanupOptions{GatewayID: "b"}))
+	assert.Equal(t, []string{"orphan", "running", "other-host", "unlabelled"}, ids(CleanupOptions{All: true}))
+}
+
+func TestProcessRunning(t *testing.T) {
+	assert.True(t, processRunning(os.Getpid()))
+}
@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// ClientCapabilities are the capabilities of a client that change how the gateway behaves.
+type ClientCapabilities struct {
+	Elicitation bool `json:"elicitation"`
+	Sampling    bool `json:"sampling"`
+	Roots       bool `json:"roots"`
+	// Progress isn't declared on initialize: it tells whether the client sent progress tokens with its requests
+	Progress bool `json:"progress"`
+}
+
+// clientCapabilities reads the capabilities a client declared on initialize.
+func clientCapabilities(params *mcp.InitializeParams) ClientCapabilities {
+	if params == nil || params.Capabilities == nil {
+		return ClientCapabilities{}
+	}
+
+	return ClientCapabilities{
+		Elicitation: params.Capabilities.Elicitation != nil,
+		Sampling:    params.Capabilities.Sampling != nil,
+		// The SDK only keeps whether the roots list can change, not whether roots are supported at all
+		Roots: params.Capabilities.Roots.ListChanged,
+	}
+}
+
+// String lists the capabilities that are present.
+func (c ClientCapabilities) String() string {
+	var present []string
+	if c.Elicitation {
+		present = append(present, "elicitation")
+	}
+	if c.Sampling {
+		present = append(present, "sampling")
+	}
+	if c.Roots {
+		present = append(present, "roots")
+	}
+	if c.Progress {
+		present = append(present, "progress")
+	}
+	if len(present) == 0 {
+		return "none"
+	}
+	return strings.Join(present, ", ")
+}
+
+// logClientCapabilities reports the capabilities of a client that just initialized,
+// and the features of the gateway that degrade without them.
+func (g *Gateway) logClientCapabilities(ss *mcp.ServerSession) {
+	capabilities := clientCapabilities(ss.InitializeParams())
+	log.Logf("  > Client capabilities: %s", capabilities)
+
+	if !capabilities.Elicitation {
+		log.Log("  > Without elicitation, tool confirmations are denied and OAuth authorizations can't be requested interactively")
+	}
+	if !capabilities.Roots && g.detectsProjects() {
+		log.Log("  > Without roots, the projects of the client may not be detected by --auto-enable")
+	}
+}
+
+// sessionCapabilities returns the capabilities of a client session, including those learnt from its requests.
+func (g *Gateway) sessionCapabilities(ss *mcp.ServerSession) ClientCapabilities {
+	capabilities := clientCapabilities(ss.InitializeParams())
+
+	g.sessionCacheMu.RLock()
+	defer g.sessionCacheMu.RUnlock()
+
+	if cache := g.sessionCache[ss]; cache != nil {
+		capabilities.Progress = cache.ProgressTokens
+		capabilities.Roots = capabilities.Roots || cache.Roots != nil
+	}
+	return capabilities
+}
+
+// hasProgressToken tells whether a request asks for progress notifications.
+func hasProgressToken(req mcp.Request) bool {
+	params, ok := req.GetParams().(mcp.RequestParams)
+	return ok && params != nil && params.GetProgressToken() != nil
+}
@@ -0,0 +1,31 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCapabilities(t *testing.T) {
+	assert.Equal(t, ClientCapabilities{}, clientCapabilities(nil))
+	assert.Equal(t, "none", clientCapabilities(&mcp.InitializeParams{}).String())
+
+	capabilities := &mcp.ClientCapabilities{
+		Elicitation: &mcp.ElicitationCapabilities{},
+		Sampling:    &mcp.SamplingCapabilities{},
+	}
+	capabilities.Roots.ListChanged = true
+
+	clientCaps := clientCapabilities(&mcp.InitializeParams{Capabilities: capabilities})
+	assert.Equal(t, ClientCapabilities{Elicitation: true, Sampling: true, Roots: true}, clientCaps)
+	assert.Equal(t, "elicitation, sampling, roots", clientCaps.String())
+}
+
+func TestHasProgressToken(t *testing.T) {
+	params := &mcp.CallToolParamsRaw{Name: "fetch"}
+	assert.False(t, hasProgressToken(&mcp.CallToolRequest{Params: params}))
+
+	params.Meta = mcp.Meta{"progressToken": "token"}
+	assert.True(t, hasProgressToken(&mcp.CallToolRequest{Params: params}))
+}
@@ -0,0 +1,195 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+)
+
+// DefaultClientLeakThreshold is how long a client can be held before it's reported as leaked.
+const DefaultClientLeakThreshold = 10 * time.Minute
+
+// HeldClient is a client acquired from the pool and not released yet, returned by GET /control/debug/clients.
+type HeldClient struct {
+	Server string `json:"server"`
+	// Caller is the function that acquired the client, along with the file:line of the call
+	Caller string    `json:"caller"`
+	Since  time.Time `json:"since"`
+	// References is how many times the client is currently held, kept clients being shared
+	References int  `json:"references"`
+	Leaked     bool `json:"leaked"`
+}
+
+// callSite is where a client was acquired or released.
+type callSite struct {
+	function string
+	location string
+}
+
+// callerOf returns the call site of the caller of the function calling callerOf, skipping skip more frames.
+func callerOf(skip int) callSite {
+	pc, file, line, ok := runtime.Caller(skip + 2)
+	if !ok {
+		return callSite{function: "unknown", location: "unknown"}
+	}
+
+	function := "unknown"
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		function = fn.Name()
+		// Drop the package path, eg. github.com/docker/mcp-gateway/pkg/gateway.(*Gateway).mcpServerToolHandler.func1
+		if i := strings.LastIndex(function, "/"); i != -1 {
+			function = function[i+1:]
+		}
+	}
+
+	return callSite{function: function, location: fmt.Sprintf("%s:%d", filepath.Base(file), line)}
+}
+
+func (c callSite) String() string {
+	return c.function + " (" + c.location + ")"
+}
+
+// clientAcquisition is one acquisition of a client that hasn't been released yet.
+type clientAcquisition struct {
+	server   string
+	caller   callSite
+	since    time.Time
+	reported bool
+}
+
+// clientRefs counts the references to the clients acquired from the pool, and remembers where each was acquired,
+// so that the clients that are never released can be reported before they show up as orphaned containers.
+type clientRefs struct {
+	mu   sync.Mutex
+	held map[mcpclient.Client][]*clientAcquisition
+}
+
+// acquire records an acquisition and returns how many times the client is now held.
+func (r *clientRefs) acquire(client mcpclient.Client, server string, caller callSite, now time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.held == nil {
+		r.held = make(map[mcpclient.Client][]*clientAcquisition)
+	}
+	r.held[client] = append(r.held[client], &clientAcquisition{server: server, caller: caller, since: now})
+	return len(r.held[client])
+}
+
+// release forgets an acquisition of the client, the oldest one made by the releasing function or else the oldest one.
+// It returns the acquisition and how many times the client is still held, or false if the client wasn't held.
+func (r *clientRefs) release(client mcpclient.Client, caller callSite) (*clientAcquisition, int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	acquisitions := r.held[client]
+	if len(acquisitions) == 0 {
+		return nil, 0, false
+	}
+
+	i := slices.IndexFunc(acquisitions, func(a *clientAcquisition) bool { return a.caller.function == caller.function })
+	if i == -1 {
+		i = 0
+	}
+	acquisition := acquisitions[i]
+
+	acquisitions = slices.Delete(acquisitions, i, i+1)
+	if len(acquisitions) == 0 {
+		delete(r.held, client)
+	} else {
+		r.held[client] = acquisitions
+	}
+
+	return acquisition, len(acquisitions), true
+}
+
+// leaked returns the acquisitions held for longer than the threshold that weren't reported yet, and marks them as reported.
+func (r *clientRefs) leaked(now time.Time, threshold time.Duration) []clientAcquisition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var leaked []clientAcquisition
+	for _, acquisitions := range r.held {
+		for _, acquisition := range acquisitions {
+			if !acquisition.reported && now.Sub(acquisition.since) >= threshold {
+				acquisition.reported = true
+				leaked = append(leaked, *acquisition)
+			}
+		}
+	}
+
+	slices.SortFunc(leaked, func(a, b clientAcquisition) int { return a.since.Compare(b.since) })
+	return leaked
+}
+
+// clients lists the acquisitions not released yet, oldest first.
+func (r *clientRefs) clients(now time.Time, threshold time.Duration) []HeldClient {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clients := []HeldClient{}
+	for _, acquisitions := range r.held {
+		for _, acquisition := range acquisitions {
+			clients = append(clients, HeldClient{
+				Server:     acquisition.server,
+				Caller:     acquisition.caller.String(),
+				Since:      acquisition.since,
+				References: len(acquisitions),
+				Leaked:     threshold > 0 && now.Sub(acquisition.since) >= threshold,
+			})
+		}
+	}
+
+	slices.SortFunc(clients, func(a, b HeldClient) int { return a.Since.Compare(b.Since) })
+	return clients
+}
+
+// count returns how many acquisitions are not released yet.
+func (r *clientRefs) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, acquisitions := range r.held {
+		count += len(acquisitions)
+	}
+	return count
+}
+
+// detectLeaks periodically reports the clients held for longer than --client-leak-threshold, along with where they
+// were acquired. Each acquisition is only reported once.
+func (cp *clientPool) detectLeaks(ctx context.Context) {
+	interval := min(max(cp.ClientLeakThreshold/4, time.Second), time.Minute)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, leak := range cp.refs.leaked(now, cp.ClientLeakThreshold) {
+				log.Logf("! Client of %s acquired %s ago by %s was not released", leak.server, now.Sub(leak.since).Round(time.Second), leak.caller)
+			}
+		}
+	}
+}
+
+// handleHeldClients lists the clients acquired from the pool and not released yet.
+func (g *Gateway) handleHeldClients(w http.ResponseWriter, _ *http.Request) {
+	clients := []HeldClient{}
+	if g.clientPool != nil {
+		clients = g.clientPool.refs.clients(time.Now(), g.ClientLeakThreshold)
+	}
+
+	writeControlJSON(w, http.StatusOK, clients)
+}
@@ -0,0 +1,108 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+)
+
+type fakeClient struct {
+	mcpclient.Client
+	name string
+}
+
+func acquireHere() callSite {
+	return callerOf(0)
+}
+
+func TestCallerOf(t *testing.T) {
+	caller := acquireHere()
+
+	assert.Equal(t, "gateway.TestCallerOf", caller.function)
+	assert.True(t, strings.HasPrefix(caller.location, "clientleaks_test.go:"), caller.location)
+	assert.Equal(t, "gateway.TestCallerOf ("+caller.location+")", caller.String())
+}
+
+func TestClientRefs(t *testing.T) {
+	var refs clientRefs
+	now := time.Now()
+	kept := &fakeClient{name: "kept"}
+	handler := callSite{function: "gateway.handler", location: "handlers.go:10"}
+	list := callSite{function: "gateway.list", location: "capabilities.go:20"}
+
+	assert.Equal(t, 1, refs.acquire(kept, "github", list, now))
+	assert.Equal(t, 2, refs.acquire(kept, "github", handler, now.Add(time.Second)))
+	assert.Equal(t, 2, refs.count())
+
+	// The acquisition made by the releasing function is released, even if it's not the oldest
+	acquisition, remaining, ok := refs.release(kept, handler)
+	require.True(t, ok)
+	assert.Equal(t, handler, acquisition.caller)
+	assert.Equal(t, 1, remaining)
+
+	// Otherwise the oldest one is
+	acquisition, remaining, ok = refs.release(kept, callSite{function: "gateway.other"})
+	require.True(t, ok)
+	assert.Equal(t, list, acquisition.caller)
+	assert.Zero(t, remaining)
+
+	_, _, ok = refs.release(kept, handler)
+	assert.False(t, ok)
+	assert.Zero(t, refs.count())
+}
+
+func TestClientRefsLeaked(t *testing.T) {
+	var refs clientRefs
+	now := time.Now()
+	caller := callSite{function: "gateway.handler", location: "handlers.go:10"}
+
+	refs.acquire(&fakeClient{name: "old"}, "github", caller, now.Add(-time.Hour))
+	refs.acquire(&fakeClient{name: "older"}, "fetch", caller, now.Add(-2*time.Hour))
+	refs.acquire(&fakeClient{name: "recent"}, "time", caller, now.Add(-time.Minute))
+
+	leaked := refs.leaked(now, 10*time.Minute)
+	require.Len(t, leaked, 2)
+	assert.Equal(t, "fetch", leaked[0].server)
+	assert.Equal(t, "github", leaked[1].server)
+
+	// Leaks are only reported once
+	assert.Empty(t, refs.leaked(now, 10*time.Minute))
+	assert.Len(t, refs.leaked(now, 0), 1)
+
+	clients := refs.clients(now, 10*time.Minute)
+	require.Len(t, clients, 3)
+	assert.Equal(t, "fetch", clients[0].Server)
+	assert.True(t, clients[0].Leaked)
+	assert.Equal(t, "gateway.handler (handlers.go:10)", clients[0].Caller)
+	assert.Equal(t, "time", clients[2].Server)
+	assert.False(t, clients[2].Leaked)
+}
+
+func TestHeldClientsControlAPI(t *testing.T) {
+	g := &Gateway{
+		Options:    Options{ClientLeakThreshold: time.Minute},
+		clientPool: newClientPool(Options{}, nil, nil),
+	}
+	g.clientPool.refs.acquire(&fakeClient{}, "github", acquireHere(), time.Now().Add(-time.Hour))
+
+	recorder := httptest.NewRecorder()
+	g.controlMux(true).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/clients", http.NoBody))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var clients []HeldClient
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &clients))
+	require.Len(t, clients, 1)
+	assert.Equal(t, "github", clients[0].Server)
+	assert.Equal(t, 1, clients[0].References)
+	assert.True(t, clients[0].Leaked)
+	assert.Contains(t, clients[0].Caller, "gateway.TestHeldClientsControlAPI")
+	assert.Equal(t, 1, g.memStats().HeldClients)
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
 	"github.com/docker/mcp-gateway/pkg/log"
 	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
 )
 
 type clientKey struct {
@@ -37,6 +40,23 @@ type clientPool struct {
 	networks    []string
 	docker      docker.Client
 	gateway     *Gateway
+
+	// invalidated lists the servers whose clients were invalidated, so that starting
+	// their container again is recorded as a restart.
+	invalidated   map[string]bool
+	invalidatedMu sync.Mutex
+
+	// scratchVolumes are the scratch volumes created for the servers, by server name.
+	scratchVolumes map[string]string
+	scratchMu      sync.Mutex
+
+	// instanceID identifies the gateway in the labels of the containers it starts.
+	instanceID string
+	// profile is the profile the gateway runs, if any.
+	profile string
+
+	// refs tracks the clients acquired and not released yet.
+	refs clientRefs
 }
 
 type clientConfig struct {
@@ -117,13 +137,30 @@ func (cp *clientPool) AcquireClient(ctx context.Context, serverConfig *catalog.S
 			delete(cp.keptClients, key)
 		}
 
+		if cp.gateway != nil {
+			cp.gateway.recordServerFailure(serverConfig.Name, err)
+		}
+
 		return nil, err
 	}
 
+	caller := callerOf(0)
+	refs := cp.refs.acquire(client, serverConfig.Name, caller, time.Now())
+	if cp.Verbose {
+		log.Logf("  - Acquired client of %s (%d references) by %s", serverConfig.Name, refs, caller)
+	}
+
 	return client, nil
 }
 
 func (cp *clientPool) ReleaseClient(client mcpclient.Client) {
+	caller := callerOf(0)
+	if acquisition, refs, ok := cp.refs.release(client, caller); !ok {
+		log.Logf("! Client released by %s was not acquired, or was already released", caller)
+	} else if cp.Verbose {
+		log.Logf("  - Released client of %s (%d references) by %s, held for %s", acquisition.server, refs, caller, time.Since(acquisition.since).Round(time.Millisecond))
+	}
+
 	foundKept := false
 	cp.clientLock.RLock()
 	for _, kc := range cp.keptClients {
@@ -141,6 +178,27 @@ func (cp *clientPool) ReleaseClient(client mcpclient.Client) {
 	}
 }
 
+// ReleaseSession closes the clients kept for a client session.
+func (cp *clientPool) ReleaseSession(ss *mcp.ServerSession) int {
+	cp.clientLock.Lock()
+	var released []keptClient
+	for key, kc := range cp.keptClients {
+		if key.session == ss {
+			released = append(released, kc)
+			delete(cp.keptClients, key)
+		}
+	}
+	cp.clientLock.Unlock()
+
+	for _, kc := range released {
+		client, err := kc.Getter.GetClient(context.TODO()) // should be cached
+		if err == nil {
+			client.Session().Close()
+		}
+	}
+	return len(released)
+}
+
 func (cp *clientPool) Close() {
 	cp.clientLock.Lock()
 	existingMap := cp.keptClients
@@ -160,6 +218,60 @@ func (cp *clientPool) SetNetworks(networks []string) {
 	cp.networks = networks
 }
 
+// SwapOAuthTokens switches the connections kept to an OAuth server to its refreshed token, without closing them,
+// so that the requests in flight aren't dropped. It returns false when the connections have to be invalidated
+// instead: there are none, the endpoint or the scopes of the server changed, or a connection can't swap its token.
+func (cp *clientPool) SwapOAuthTokens(ctx context.Context, serverName string) bool {
+	cp.clientLock.RLock()
+	var kept []keptClient
+	for _, kc := range cp.keptClients {
+		if kc.Config.Spec.OAuth != nil && kc.Config.Name == serverName {
+			kept = append(kept, kc)
+		}
+	}
+	cp.clientLock.RUnlock()
+
+	if len(kept) == 0 || cp.gateway == nil {
+		return false
+	}
+
+	current, _, found := cp.gateway.configuration.Find(serverName)
+	if !found || current == nil {
+		return false
+	}
+
+	for _, kc := range kept {
+		if !sameOAuthEndpoint(kc.Config, current) {
+			log.Logf("  - The endpoint or the scopes of %s changed, reconnecting", serverName)
+			return false
+		}
+
+		client, err := kc.Getter.GetClient(ctx) // should be cached
+		if err != nil {
+			return false
+		}
+		if withCleanup, ok := client.(*clientWithCleanup); ok {
+			client = withCleanup.Client
+		}
+		swapper, ok := client.(mcpclient.OAuthTokenSwapper)
+		if !ok || !swapper.SwapOAuthToken(ctx) {
+			return false
+		}
+	}
+
+	log.Logf("  - Swapped the OAuth token of %d connections to %s", len(kept), serverName)
+	return true
+}
+
+// sameOAuthEndpoint tells whether a connection opened with a server's former configuration can keep being used,
+// only with a new token.
+func sameOAuthEndpoint(former, current *catalog.ServerConfig) bool {
+	return former.Spec.SSEEndpoint == current.Spec.SSEEndpoint &&
+		former.Spec.Remote.URL == current.Spec.Remote.URL &&
+		former.Spec.Remote.Transport == current.Spec.Remote.Transport &&
+		slices.Equal(former.Spec.ConfiguredOAuthScopes(nil), current.Spec.ConfiguredOAuthScopes(nil))
+}
+
 // InvalidateOAuthClients closes and removes all OAuth client connections for the specified provider
 // This allows clients to reconnect with updated/refreshed tokens
 func (cp *clientPool) InvalidateOAuthClients(provider string) {
@@ -193,6 +305,7 @@ func (cp *clientPool) InvalidateOAuthClients(provider string) {
 
 	// Remove invalidated clients from the pool (they will be recreated on next request with new tokens)
 	for _, key := range invalidatedKeys {
+		cp.markInvalidated(key.serverName)
 		delete(cp.keptClients, key)
 	}
 
@@ -203,8 +316,63 @@ func (cp *clientPool) InvalidateOAuthClients(provider string) {
 	}
 }
 
+// InvalidateClients closes and removes all kept client connections for the specified server
+// so that the next request starts a fresh client with the current configuration and secrets
+func (cp *clientPool) InvalidateClients(serverName string) {
+	cp.clientLock.Lock()
+	defer cp.clientLock.Unlock()
+
+	var invalidatedKeys []clientKey
+	for key, keptClient := range cp.keptClients {
+		if keptClient.Name != serverName {
+			continue
+		}
+
+		client, err := keptClient.Getter.GetClient(context.TODO())
+		if err == nil {
+			client.Session().Close()
+		}
+
+		invalidatedKeys = append(invalidatedKeys, key)
+	}
+
+	for _, key := range invalidatedKeys {
+		delete(cp.keptClients, key)
+	}
+
+	if len(invalidatedKeys) > 0 {
+		cp.markInvalidated(serverName)
+		log.Log(fmt.Sprintf("ClientPool: Invalidated %d connections for server %s", len(invalidatedKeys), serverName))
+	}
+}
+
+func (cp *clientPool) markInvalidated(serverName string) {
+	cp.invalidatedMu.Lock()
+	defer cp.invalidatedMu.Unlock()
+
+	if cp.invalidated == nil {
+		cp.invalidated = make(map[string]bool)
+	}
+	cp.invalidated[serverName] = true
+}
+
+// restarting tells whether a container is started again for a server whose clients were invalidated.
+func (cp *clientPool) restarting(serverName string) bool {
+	cp.invalidatedMu.Lock()
+	defer cp.invalidatedMu.Unlock()
+
+	restarting := cp.invalidated[serverName]
+	delete(cp.invalidated, serverName)
+	return restarting
+}
+
 func (cp *clientPool) runToolContainer(ctx context.Context, tool catalog.Tool, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
-	args := cp.baseArgs(tool.Name)
+	args := cp.baseArgs(tool.Name, nil)
+	nameArgs, err := cp.sessionArgs(tool.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, nameArgs...)
 
 	// Attach the MCP servers to the same network as the gateway.
 	for _, network := range cp.networks {
@@ -265,14 +433,20 @@ func (cp *clientPool) runToolContainer(ctx context.Context, tool catalog.Tool, p
 	}, nil
 }
 
-func (cp *clientPool) baseArgs(name string) []string {
+func (cp *clientPool) baseArgs(name string, resources *catalog.Resources) []string {
 	args := []string{"run"}
 
 	args = append(args, "--rm", "-i", "--init", "--security-opt", "no-new-privileges")
-	if cp.Cpus > 0 {
+	switch {
+	case resources != nil && resources.Cpus != "":
+		args = append(args, "--cpus", resources.Cpus)
+	case cp.Cpus > 0:
 		args = append(args, "--cpus", fmt.Sprintf("%d", cp.Cpus))
 	}
-	if cp.Memory != "" {
+	switch {
+	case resources != nil && resources.Memory != "":
+		args = append(args, "--memory", resources.Memory)
+	case cp.Memory != "":
 		args = append(args, "--memory", cp.Memory)
 	}
 	args = append(args, "--pull", "never")
@@ -288,15 +462,23 @@ func (cp *clientPool) baseArgs(name string) []string {
 		"-l", "docker-mcp-name="+name,
 		"-l", "docker-mcp-transport=stdio",
 	)
+	args = append(args, cp.gatewayLabelArgs()...)
 
 	return args
 }
 
 func (cp *clientPool) argsAndEnv(serverConfig *catalog.ServerConfig, readOnly *bool, targetConfig proxies.TargetConfig) ([]string, []string) {
-	args := cp.baseArgs(serverConfig.Name)
+	args := cp.baseArgs(serverConfig.Name, serverConfig.Spec.Resources)
 	var env []string
 
 	// Security options
+	if serverConfig.Spec.IsCommandServer() {
+		// Packages installed at start are not vetted like images are
+		args = append(args, "--cap-drop", "ALL")
+	}
+	if serverConfig.Spec.Platform != "" {
+		args = append(args, "--platform", serverConfig.Spec.Platform)
+	}
 	if serverConfig.Spec.DisableNetwork {
 		args = append(args, "--network", "none")
 	} else {
@@ -335,7 +517,8 @@ func (cp *clientPool) argsAndEnv(serverConfig *catalog.ServerConfig, readOnly *b
 	for _, e := range serverConfig.Spec.Env {
 		var value string
 		if strings.Contains(e.Value, "{{") && strings.Contains(e.Value, "}}") {
-			value = fmt.Sprintf("%v", eval.Evaluate(e.Value, serverConfig.Config))
+			// Secrets can be referenced as $ENV next to config values
+			value = fmt.Sprintf("%v", eval.Evaluate(expandEnv(e.Value, env), serverConfig.Config))
 		} else {
 			value = expandEnv(e.Value, env)
 		}
@@ -421,14 +604,26 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 			cleanup := func(context.Context) error { return nil }
 
 			var client mcpclient.Client
+			container := false
+			var containerIDFile string
 
 			// Deprecated: Use Remote instead
 			if cg.serverConfig.Spec.SSEEndpoint != "" {
 				client = mcpclient.NewRemoteMCPClient(cg.serverConfig)
 			} else if cg.serverConfig.Spec.Remote.URL != "" {
 				client = mcpclient.NewRemoteMCPClient(cg.serverConfig)
+			} else if cg.serverConfig.Spec.Type == catalog.TypePlugin {
+				var err error
+				if client, err = newPluginClient(cg.serverConfig); err != nil {
+					return nil, err
+				}
+			} else if cg.serverConfig.Spec.Type == catalog.TypeWasm {
+				var err error
+				if client, err = cg.cp.newWasmClient(cg.serverConfig); err != nil {
+					return nil, err
+				}
 			} else if cg.cp.Static {
-				client = mcpclient.NewStdioCmdClient(cg.serverConfig.Name, "socat", nil, "STDIO", fmt.Sprintf("TCP:mcp-%s:4444", cg.serverConfig.Name))
+				client = mcpclient.NewStdioCmdClientWithFraming(cg.serverConfig.Name, cg.serverConfig.Spec.Framing, "socat", nil, "STDIO", fmt.Sprintf("TCP:mcp-%s:4444", cg.serverConfig.Name))
 			} else {
 				var targetConfig proxies.TargetConfig
 				if cg.cp.BlockNetwork && len(cg.serverConfig.Spec.AllowHosts) > 0 {
@@ -444,6 +639,23 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 					readOnly = cg.clientConfig.readOnly
 				}
 				args, env := cg.cp.argsAndEnv(cg.serverConfig, readOnly, targetConfig)
+				if cg.cp.ScratchSize != "" {
+					scratchArgs, scratchEnv, err := cg.cp.scratchArgsAndEnv(ctx, cg.serverConfig.Name)
+					if err != nil {
+						return nil, err
+					}
+					args = append(args, scratchArgs...)
+					env = append(env, scratchEnv...)
+				}
+				var session *mcp.ServerSession
+				if cg.clientConfig != nil {
+					session = cg.clientConfig.serverSession
+				}
+				sessionArgs, err := cg.cp.sessionArgs(cg.serverConfig.Name, session)
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, sessionArgs...)
 
 				command := expandEnvList(eval.EvaluateList(cg.serverConfig.Spec.Command, cg.serverConfig.Config), env)
 				if len(command) == 0 {
@@ -452,12 +664,15 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 					log.Log("  - Running", imageBaseName(image), "with", args, "and command", command)
 				}
 
+				containerIDFile = newContainerIDFile()
 				var runArgs []string
 				runArgs = append(runArgs, args...)
+				runArgs = append(runArgs, "--cidfile", containerIDFile)
 				runArgs = append(runArgs, image)
 				runArgs = append(runArgs, command...)
 
-				client = mcpclient.NewStdioCmdClient(cg.serverConfig.Name, "docker", env, runArgs...)
+				client = mcpclient.NewStdioCmdClientWithFraming(cg.serverConfig.Name, cg.serverConfig.Spec.Framing, "docker", env, runArgs...)
+				container = true
 			}
 
 			initParams := &mcp.InitializeParams{
@@ -478,10 +693,21 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 			// defer cancel()
 
 			// TODO add initial roots
-			if err := client.Initialize(ctx, initParams, cg.cp.Verbose, ss, server, cg.cp.gateway); err != nil {
+			start := time.Now()
+			err := client.Initialize(ctx, initParams, cg.cp.Verbose, ss, server, cg.cp.gateway)
+			if container {
+				cg.cp.recordContainerStart(ctx, cg.serverConfig.Name, client, time.Since(start), err)
+			}
+			if err != nil {
+				if containerIDFile != "" {
+					_ = os.Remove(containerIDFile)
+				}
 				return nil, err
 			}
 
+			if containerIDFile != "" {
+				return newContainerClient(ctx, cg.cp.docker, newClientWithCleanup(client, cleanup), containerIDFile), nil
+			}
 			return newClientWithCleanup(client, cleanup), nil
 		}
 
@@ -492,3 +718,21 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 
 	return cg.client, cg.err
 }
+
+// recordContainerStart records the start of the container of a server and keeps track of it
+// being active until its session ends.
+func (cp *clientPool) recordContainerStart(ctx context.Context, serverName string, client mcpclient.Client, duration time.Duration, err error) {
+	telemetry.RecordContainerStart(ctx, serverName, float64(duration.Milliseconds()), err == nil)
+	if cp.restarting(serverName) {
+		telemetry.RecordContainerRestart(ctx, serverName)
+	}
+	if err != nil {
+		return
+	}
+
+	telemetry.RecordActiveContainers(ctx, serverName, 1)
+	go func() {
+		_ = client.Session().Wait()
+		telemetry.RecordActiveContainers(context.Background(), serverName, -1)
+	}()
+}
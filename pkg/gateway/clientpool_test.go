@@ -90,6 +90,33 @@ env:
 	assert.Equal(t, []string{"INTERNAL_INTEGRATION_TOKEN=ntn_DUMMY", `OPENAPI_MCP_HEADERS={"Authorization": "Bearer ntn_DUMMY", "Notion-Version": "2022-06-28"}`}, env)
 }
 
+func TestApplyConfigSecretInTemplate(t *testing.T) {
+	catalogYAML := `
+secrets:
+  - name: weather.api_key
+    env: API_KEY
+env:
+  - name: WEATHER_URL
+    value: 'https://{{weather.region}}.example.com/?key=${API_KEY}'
+  `
+	configYAML := `
+weather:
+  region: eu
+`
+	secrets := map[string]string{
+		"weather.api_key": "KEY",
+	}
+
+	args, env := argsAndEnv(t, "weather", catalogYAML, configYAML, secrets, nil)
+
+	assert.Equal(t, []string{
+		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "1", "--memory", "2Gb", "--pull", "never",
+		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=weather", "-l", "docker-mcp-transport=stdio",
+		"-e", "API_KEY", "-e", "WEATHER_URL",
+	}, args)
+	assert.Equal(t, []string{"API_KEY=KEY", "WEATHER_URL=https://eu.example.com/?key=KEY"}, env)
+}
+
 func TestApplyConfigMountAs(t *testing.T) {
 	catalogYAML := `
 volumes:
@@ -160,6 +187,55 @@ user: "1001:2002"
 	assert.Empty(t, env)
 }
 
+func TestApplyConfigPlatform(t *testing.T) {
+	catalogYAML := `
+platform: linux/amd64
+  `
+
+	args, env := argsAndEnv(t, "svc", catalogYAML, "", nil, nil)
+
+	assert.Equal(t, []string{
+		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "1", "--memory", "2Gb", "--pull", "never",
+		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=svc", "-l", "docker-mcp-transport=stdio",
+		"--platform", "linux/amd64",
+	}, args)
+	assert.Empty(t, env)
+}
+
+func TestApplyConfigResources(t *testing.T) {
+	catalogYAML := `
+resources:
+  cpus: "0.5"
+  memory: 512m
+  `
+
+	args, env := argsAndEnv(t, "svc", catalogYAML, "", nil, nil)
+
+	assert.Equal(t, []string{
+		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "0.5", "--memory", "512m", "--pull", "never",
+		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=svc", "-l", "docker-mcp-transport=stdio",
+	}, args)
+	assert.Empty(t, env)
+}
+
+func TestApplyConfigCommandServer(t *testing.T) {
+	catalogYAML := `
+type: command
+package:
+  registryType: npm
+  identifier: some-server
+  `
+
+	args, env := argsAndEnv(t, "cmd", catalogYAML, "", nil, nil)
+
+	assert.Equal(t, []string{
+		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--cpus", "1", "--memory", "2Gb", "--pull", "never",
+		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=cmd", "-l", "docker-mcp-transport=stdio",
+		"--cap-drop", "ALL",
+	}, args)
+	assert.Empty(t, env)
+}
+
 func argsAndEnv(t *testing.T, name, catalogYAML, configYAML string, secrets map[string]string, readOnly *bool) ([]string, []string) {
 	t.Helper()
 
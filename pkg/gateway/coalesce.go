@@ -0,0 +1,116 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+// coalesceKey identifies identical tool calls of a session. Sessions never share results, since their
+// clients might have different identities.
+type coalesceKey struct {
+	session   *mcp.ServerSession
+	toolName  string
+	arguments string
+}
+
+// coalescedCall is the execution of a tool call, shared with the identical calls made within the window.
+type coalescedCall struct {
+	started time.Time
+	done    chan struct{}
+	result  mcp.Result
+	err     error
+}
+
+// coalesceMiddleware makes the identical calls to a tool, by a session, within --coalesce-window of each other,
+// share one execution and its result. Agents often send the same call twice in quick succession.
+func (g *Gateway) coalesceMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" || g.CoalesceWindow <= 0 {
+				return next(ctx, method, req)
+			}
+
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || callReq.Params == nil || callReq.Session == nil {
+				return next(ctx, method, req)
+			}
+
+			key := coalesceKey{
+				session:   callReq.Session,
+				toolName:  callReq.Params.Name,
+				arguments: canonicalArguments(callReq.Params.Arguments),
+			}
+
+			g.coalesceMu.Lock()
+			if call, found := g.coalescedCalls[key]; found && time.Since(call.started) < g.CoalesceWindow {
+				g.coalesceMu.Unlock()
+
+				select {
+				case <-call.done:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+
+				log.Log("  - Coalesced a call to", key.toolName, "with an identical call")
+				telemetry.RecordToolCoalesced(ctx, key.toolName, g.toolServerName(key.toolName))
+				return call.result, call.err
+			}
+
+			call := &coalescedCall{
+				started: time.Now(),
+				done:    make(chan struct{}),
+			}
+			if g.coalescedCalls == nil {
+				g.coalescedCalls = map[coalesceKey]*coalescedCall{}
+			}
+			g.coalescedCalls[key] = call
+			g.coalesceMu.Unlock()
+
+			call.result, call.err = next(ctx, method, req)
+			close(call.done)
+
+			// Forget the call once its window is over, or right away if it failed, for the next call to try again
+			forget := func() {
+				g.coalesceMu.Lock()
+				if g.coalescedCalls[key] == call {
+					delete(g.coalescedCalls, key)
+				}
+				g.coalesceMu.Unlock()
+			}
+			failed := callFailed(call.result, call.err)
+			if remaining := g.CoalesceWindow - time.Since(call.started); !failed && remaining > 0 {
+				time.AfterFunc(remaining, forget)
+			} else {
+				forget()
+			}
+
+			return call.result, call.err
+		}
+	}
+}
+
+// canonicalArguments formats the arguments of a tool call so that the order of their keys doesn't matter.
+func canonicalArguments(arguments json.RawMessage) string {
+	var decoded any
+	if err := json.Unmarshal(arguments, &decoded); err != nil {
+		return string(arguments)
+	}
+	canonical, err := json.Marshal(decoded)
+	if err != nil {
+		return string(arguments)
+	}
+	return string(canonical)
+}
+
+func (g *Gateway) toolServerName(toolName string) string {
+	g.capabilitiesMu.RLock()
+	defer g.capabilitiesMu.RUnlock()
+
+	return g.toolRegistrations[toolName].ServerName
+}
@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalesceMiddleware(t *testing.T) {
+	g := &Gateway{Options: Options{CoalesceWindow: time.Minute}}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddTool(&mcp.Tool{Name: "search", InputSchema: map[string]any{"type": "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		<-release
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "results"}}}, nil
+	})
+	server.AddReceivingMiddleware(g.coalesceMiddleware())
+
+	connect := func() *mcp.ClientSession {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		_, err := server.Connect(t.Context(), serverTransport, nil)
+		require.NoError(t, err)
+		session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(t.Context(), clientTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { session.Close() })
+		return session
+	}
+	search := func(session *mcp.ClientSession, args map[string]any) {
+		result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "search", Arguments: args})
+		assert.NoError(t, err)
+		assert.Equal(t, "results", result.Content[0].(*mcp.TextContent).Text)
+	}
+
+	session := connect()
+
+	// Identical calls made while the first one runs share its execution
+	var wg sync.WaitGroup
+	for _, args := range []map[string]any{{"query": "mcp", "limit": 5}, {"limit": 5, "query": "mcp"}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			search(session, args)
+		}()
+	}
+	require.Eventually(t, func() bool {
+		g.coalesceMu.Lock()
+		defer g.coalesceMu.Unlock()
+		return len(g.coalescedCalls) == 1
+	}, time.Second, 10*time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+
+	// So do the identical calls made within the window
+	search(session, map[string]any{"query": "mcp", "limit": 5})
+	assert.Equal(t, int32(1), calls.Load())
+
+	// But not the calls with other arguments, or of other sessions
+	search(session, map[string]any{"query": "docker"})
+	assert.Equal(t, int32(2), calls.Load())
+	search(connect(), map[string]any{"query": "mcp", "limit": 5})
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestCoalesceMiddlewareDisabled(t *testing.T) {
+	g := &Gateway{}
+
+	var calls atomic.Int32
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddTool(&mcp.Tool{Name: "search", InputSchema: map[string]any{"type": "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return &mcp.CallToolResult{}, nil
+	})
+	server.AddReceivingMiddleware(g.coalesceMiddleware())
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	for range 2 {
+		_, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "search", Arguments: map[string]any{}})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestCanonicalArguments(t *testing.T) {
+	assert.Equal(t, canonicalArguments([]byte(`{"b":1,"a":{"d":2,"c":3}}`)), canonicalArguments([]byte(`{ "a": {"c":3,"d":2}, "b": 1 }`)))
+	assert.Empty(t, canonicalArguments(nil))
+}
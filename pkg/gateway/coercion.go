@@ -0,0 +1,256 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+// argumentCoercion is an argument of a tool call that was changed to match the type of its schema.
+type argumentCoercion struct {
+	// Path of the argument, like query or filters.labels[0].
+	Path string
+	// From and To are the JSON types before and after the coercion. To is empty for a removed null.
+	From string
+	To   string
+}
+
+func (c argumentCoercion) String() string {
+	if c.To == "" {
+		return fmt.Sprintf("%s: removed null", c.Path)
+	}
+	return fmt.Sprintf("%s: %s to %s", c.Path, c.From, c.To)
+}
+
+// Kind names the coercion for the metrics, like string-to-number.
+func (c argumentCoercion) Kind() string {
+	if c.To == "" {
+		return "null-removed"
+	}
+	return c.From + "-to-" + c.To
+}
+
+// coercionMiddleware normalizes the arguments of the tool calls to the types of the tool's input schema,
+// before the calls are checked and forwarded: models often send numbers as strings, a single value
+// instead of an array, or null for the optional arguments they don't use.
+func (g *Gateway) coercionMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if !g.CoerceArguments || method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || callReq.Params == nil || len(callReq.Params.Arguments) == 0 {
+				return next(ctx, method, req)
+			}
+
+			g.capabilitiesMu.RLock()
+			toolReg, found := g.toolRegistrations[callReq.Params.Name]
+			g.capabilitiesMu.RUnlock()
+			if !found || toolReg.Tool == nil {
+				return next(ctx, method, req)
+			}
+
+			arguments, coercions := coerceArguments(toolReg.Tool.InputSchema, callReq.Params.Arguments)
+			for _, coercion := range coercions {
+				log.Logf("  > Coerced argument of %s %s", callReq.Params.Name, coercion)
+				telemetry.RecordArgumentCoercion(ctx, toolReg.ServerName, callReq.Params.Name, coercion.Kind())
+			}
+			if len(coercions) > 0 {
+				params := *callReq.Params
+				params.Arguments = arguments
+				callReq.Params = &params
+			}
+
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// coerceArguments changes the arguments of a tool call to match the types of the tool's input schema.
+// Arguments that can't be coerced are left as is, for the server to reject them.
+func coerceArguments(schema any, arguments json.RawMessage) (json.RawMessage, []argumentCoercion) {
+	schemaNode, ok := schemaAsMap(schema)
+	if !ok {
+		return arguments, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(arguments))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return arguments, nil
+	}
+
+	var coercions []argumentCoercion
+	value = coerceValue(schemaNode, value, "", &coercions)
+	if len(coercions) == 0 {
+		return arguments, nil
+	}
+
+	coerced, err := json.Marshal(value)
+	if err != nil {
+		return arguments, nil
+	}
+	return coerced, coercions
+}
+
+func schemaAsMap(schema any) (map[string]any, bool) {
+	if schema == nil {
+		return nil, false
+	}
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return nil, false
+	}
+	var node map[string]any
+	if err := json.Unmarshal(buf, &node); err != nil || node == nil {
+		return nil, false
+	}
+	return node, true
+}
+
+func coerceValue(schema map[string]any, value any, path string, coercions *[]argumentCoercion) any {
+	types := schemaTypes(schema)
+	valueType := jsonType(value)
+
+	if len(types) > 0 && !allowsType(types, valueType) {
+		for _, target := range types {
+			if coerced, ok := coerceTo(target, value); ok {
+				*coercions = append(*coercions, argumentCoercion{Path: path, From: valueType, To: target})
+				value = coerced
+				break
+			}
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for name, property := range value {
+			propertySchema, _ := properties[name].(map[string]any)
+			propertyPath := joinPath(path, name)
+
+			// An optional argument set to null is removed, when its schema doesn't accept null
+			if property == nil && propertySchema != nil && !slices.Contains(required, any(name)) && rejectsNull(propertySchema) {
+				delete(value, name)
+				*coercions = append(*coercions, argumentCoercion{Path: propertyPath, From: "null"})
+				continue
+			}
+			if propertySchema != nil {
+				value[name] = coerceValue(propertySchema, property, propertyPath, coercions)
+			}
+		}
+		return value
+	case []any:
+		items, _ := schema["items"].(map[string]any)
+		if items != nil {
+			for i, item := range value {
+				value[i] = coerceValue(items, item, fmt.Sprintf("%s[%d]", path, i), coercions)
+			}
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// allowsType tells whether a value of a JSON type matches the types of a schema. Integers are numbers too.
+func allowsType(types []string, valueType string) bool {
+	return slices.Contains(types, valueType) || (valueType == "integer" && slices.Contains(types, "number"))
+}
+
+// rejectsNull tells whether a schema restricts the types of a value, without allowing null.
+func rejectsNull(schema map[string]any) bool {
+	types := schemaTypes(schema)
+	return len(types) > 0 && !allowsType(types, "null")
+}
+
+// coerceTo converts a value to a JSON type, if it can be done without losing information.
+func coerceTo(target string, value any) (any, bool) {
+	switch target {
+	case "number", "integer":
+		text, ok := value.(string)
+		if !ok {
+			break
+		}
+		// Only JSON numbers, not NaN or +1
+		number := json.Number(strings.TrimSpace(text))
+		if _, err := number.Float64(); err != nil || !json.Valid([]byte(number)) {
+			break
+		}
+		if target == "integer" && jsonType(number) != "integer" {
+			break
+		}
+		return number, true
+	case "boolean":
+		if text, ok := value.(string); ok {
+			switch strings.ToLower(strings.TrimSpace(text)) {
+			case "true":
+				return true, true
+			case "false":
+				return false, true
+			}
+		}
+	case "array":
+		if value != nil {
+			return []any{value}, true
+		}
+	}
+	return nil, false
+}
+
+// schemaTypes lists the types allowed by a schema: its type can be a name or a list of names.
+func schemaTypes(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		var types []string
+		for _, name := range t {
+			if name, ok := name.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return ""
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoerceArguments(t *testing.T) {
+	schema := &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"query"},
+		Properties: map[string]*jsonschema.Schema{
+			"query":    {Type: "string"},
+			"limit":    {Type: "integer"},
+			"ratio":    {Type: "number"},
+			"verbose":  {Type: "boolean"},
+			"labels":   {Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+			"ids":      {Type: "array", Items: &jsonschema.Schema{Type: "integer"}},
+			"cursor":   {Type: "string"},
+			"parent":   {Types: []string{"string", "null"}},
+			"anything": {},
+		},
+	}
+
+	arguments, coercions := coerceArguments(schema, json.RawMessage(`{"query":"docker","limit":"10","ratio":"0.5","verbose":"True","labels":"bug","ids":["1","2"],"cursor":null,"parent":null,"anything":null}`))
+
+	assert.JSONEq(t, `{"query":"docker","limit":10,"ratio":0.5,"verbose":true,"labels":["bug"],"ids":[1,2],"parent":null,"anything":null}`, string(arguments))
+	var descriptions []string
+	for _, coercion := range coercions {
+		descriptions = append(descriptions, coercion.String())
+	}
+	assert.ElementsMatch(t, []string{
+		"limit: string to integer",
+		"ratio: string to number",
+		"verbose: string to boolean",
+		"labels: string to array",
+		"ids[0]: string to integer",
+		"ids[1]: string to integer",
+		"cursor: removed null",
+	}, descriptions)
+}
+
+func TestCoerceArgumentsLeavesInvalidValues(t *testing.T) {
+	schema := &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"limit"},
+		Properties: map[string]*jsonschema.Schema{
+			"limit":   {Type: "integer"},
+			"ratio":   {Type: "number"},
+			"verbose": {Type: "boolean"},
+		},
+	}
+
+	for _, arguments := range []string{
+		`{"limit":"1.5"}`,
+		`{"limit":null}`,
+		`{"ratio":"NaN"}`,
+		`{"ratio":"+1"}`,
+		`{"verbose":"yes"}`,
+		`{"limit":3,"ratio":2,"verbose":false}`,
+	} {
+		coerced, coercions := coerceArguments(schema, json.RawMessage(arguments))
+		assert.Equal(t, arguments, string(coerced))
+		assert.Empty(t, coercions)
+	}
+}
+
+func TestCoerceArgumentsKeepsLargeIntegers(t *testing.T) {
+	schema := &jsonschema.Schema{
+		Type:       "object",
+		Properties: map[string]*jsonschema.Schema{"id": {Type: "integer"}, "name": {Type: "string"}},
+	}
+
+	arguments, coercions := coerceArguments(schema, json.RawMessage(`{"id":9007199254740993,"name":null}`))
+
+	assert.JSONEq(t, `{"id":9007199254740993}`, string(arguments))
+	assert.Len(t, coercions, 1)
+	assert.Equal(t, "null-removed", coercions[0].Kind())
+}
@@ -0,0 +1,135 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// maxCompletionValues is the maximum number of values a completion result can hold, as per the MCP spec.
+const maxCompletionValues = 100
+
+// completionServers lists the enabled servers that own the prompt or the resource template a completion request refers to.
+func (g *Gateway) completionServers(ref *mcp.CompleteReference) []string {
+	if ref == nil {
+		return nil
+	}
+
+	g.capabilitiesMu.RLock()
+	defer g.capabilitiesMu.RUnlock()
+
+	var serverNames []string
+	for serverName, caps := range g.serverCapabilities {
+		var owns bool
+		switch ref.Type {
+		case "ref/prompt":
+			owns = slices.Contains(caps.PromptNames, ref.Name)
+		case "ref/resource":
+			owns = slices.Contains(caps.ResourceTemplateURIs, ref.URI)
+		}
+		if owns {
+			serverNames = append(serverNames, serverName)
+		}
+	}
+	sort.Strings(serverNames)
+
+	return serverNames
+}
+
+// completionHandler forwards completion requests to the servers that own the prompt or the resource template
+// and merges their suggestions.
+func (g *Gateway) completionHandler(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	result := &mcp.CompleteResult{
+		Completion: mcp.CompletionResultDetails{
+			Values: []string{},
+		},
+	}
+
+	serverNames := g.completionServers(req.Params.Ref)
+	if len(serverNames) == 0 {
+		return result, nil
+	}
+
+	ctx = withClientHeaders(ctx, req.Extra)
+	var results []*mcp.CompleteResult
+	for _, serverName := range serverNames {
+		serverResult, err := g.completeWithServer(ctx, serverName, req)
+		if err != nil {
+			log.Logf("  ! Can't complete %s argument with %s: %v", req.Params.Argument.Name, serverName, err)
+			continue
+		}
+		if serverResult != nil {
+			results = append(results, serverResult)
+		}
+	}
+
+	result.Completion = mergeCompletions(results)
+	return result, nil
+}
+
+// completeWithServer forwards a completion request to a server. It returns nil if the server doesn't support completions.
+func (g *Gateway) completeWithServer(ctx context.Context, serverName string, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	serverConfig, _, ok := g.configuration.Find(serverName)
+	if !ok {
+		return nil, fmt.Errorf("server %q not found in configuration", serverName)
+	}
+
+	client, err := g.clientPool.AcquireClient(ctx, serverConfig, getClientConfig(nil, req.Session, g.mcpServer))
+	if err != nil {
+		return nil, err
+	}
+	defer g.clientPool.ReleaseClient(client)
+
+	initResult := client.Session().InitializeResult()
+	if initResult == nil || initResult.Capabilities == nil || initResult.Capabilities.Completions == nil {
+		return nil, nil
+	}
+
+	return client.Session().Complete(ctx, req.Params)
+}
+
+// mergeCompletions combines the suggestions of several servers, dropping duplicates and keeping at most maxCompletionValues.
+func mergeCompletions(results []*mcp.CompleteResult) mcp.CompletionResultDetails {
+	merged := mcp.CompletionResultDetails{
+		Values: []string{},
+	}
+
+	var total int
+	seen := map[string]bool{}
+	for _, result := range results {
+		if result.Completion.HasMore {
+			merged.HasMore = true
+		}
+		if result.Completion.Total > len(result.Completion.Values) {
+			total += result.Completion.Total
+		} else {
+			total += len(result.Completion.Values)
+		}
+
+		for _, value := range result.Completion.Values {
+			if seen[value] {
+				total--
+				continue
+			}
+			seen[value] = true
+
+			if len(merged.Values) == maxCompletionValues {
+				merged.HasMore = true
+				continue
+			}
+			merged.Values = append(merged.Values, value)
+		}
+	}
+
+	if total > len(merged.Values) {
+		merged.Total = total
+		merged.HasMore = true
+	}
+
+	return merged
+}
@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletionServers(t *testing.T) {
+	g := &Gateway{
+		serverCapabilities: map[string]*ServerCapabilities{
+			"github": {PromptNames: []string{"review"}, ResourceTemplateURIs: []string{"repo://{owner}/{repo}"}},
+			"gitlab": {PromptNames: []string{"review"}},
+			"files":  {ResourceTemplateURIs: []string{"file:///{path}"}},
+		},
+	}
+
+	assert.Equal(t, []string{"github", "gitlab"}, g.completionServers(&mcp.CompleteReference{Type: "ref/prompt", Name: "review"}))
+	assert.Equal(t, []string{"github"}, g.completionServers(&mcp.CompleteReference{Type: "ref/resource", URI: "repo://{owner}/{repo}"}))
+	assert.Empty(t, g.completionServers(&mcp.CompleteReference{Type: "ref/prompt", Name: "unknown"}))
+	assert.Empty(t, g.completionServers(nil))
+}
+
+func TestMergeCompletions(t *testing.T) {
+	assert.Equal(t, mcp.CompletionResultDetails{Values: []string{}}, mergeCompletions(nil))
+
+	merged := mergeCompletions([]*mcp.CompleteResult{
+		{Completion: mcp.CompletionResultDetails{Values: []string{"main", "develop"}}},
+		{Completion: mcp.CompletionResultDetails{Values: []string{"main", "release"}}},
+	})
+	assert.Equal(t, mcp.CompletionResultDetails{Values: []string{"main", "develop", "release"}}, merged)
+
+	merged = mergeCompletions([]*mcp.CompleteResult{
+		{Completion: mcp.CompletionResultDetails{Values: []string{"a"}, Total: 10, HasMore: true}},
+	})
+	assert.Equal(t, mcp.CompletionResultDetails{Values: []string{"a"}, Total: 10, HasMore: true}, merged)
+
+	var many []string
+	for i := range 80 {
+		many = append(many, fmt.Sprintf("value-%d", i))
+	}
+	var others []string
+	for i := range 80 {
+		others = append(others, fmt.Sprintf("other-%d", i))
+	}
+	merged = mergeCompletions([]*mcp.CompleteResult{
+		{Completion: mcp.CompletionResultDetails{Values: many}},
+		{Completion: mcp.CompletionResultDetails{Values: others}},
+	})
+	assert.Len(t, merged.Values, maxCompletionValues)
+	assert.True(t, merged.HasMore)
+	assert.Equal(t, 160, merged.Total)
+}
@@ -0,0 +1,130 @@
+package gateway
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                         "",
+		"identity":                 "",
+		"gzip":                     encodingGzip,
+		"gzip, deflate, br, zstd":  encodingZstd,
+		"zstd;q=0.5, gzip":         encodingGzip,
+		"zstd;q=0, gzip;q=0":       "",
+		"GZIP;q=0.8, zstd;q=0.8":   encodingZstd,
+		"gzip;q=invalid, deflate":  "",
+		"br, zstd;q=1.0, gzip;q=1": encodingZstd,
+	}
+	for acceptEncoding, expected := range tests {
+		assert.Equal(t, expected, negotiateEncoding(acceptEncoding), acceptEncoding)
+	}
+}
+
+func TestCompressionHandler(t *testing.T) {
+	large := `{"text":"` + strings.Repeat("result ", 1000) + `"}`
+	handler := compressionHandler(1024, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, large)
+		case "/small":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{}`)
+		case "/events":
+			// A small event flushed first decides for the whole stream
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = io.WriteString(w, "data: {}\n\n")
+			w.(http.Flusher).Flush()
+			_, _ = io.WriteString(w, "data: "+large+"\n\n")
+		case "/text":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = io.WriteString(w, large)
+		}
+	}))
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	response := get("/large", "gzip")
+	assert.Equal(t, "gzip", response.Header().Get("Content-Encoding"))
+	assert.Less(t, response.Body.Len(), len(large))
+	reader, err := gzip.NewReader(response.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, large, string(body))
+
+	response = get("/large", "gzip, zstd")
+	assert.Equal(t, "zstd", response.Header().Get("Content-Encoding"))
+	decoder, err := zstd.NewReader(response.Body)
+	require.NoError(t, err)
+	defer decoder.Close()
+	body, err = io.ReadAll(decoder)
+	require.NoError(t, err)
+	assert.Equal(t, large, string(body))
+
+	for _, path := range []string{"/small", "/events", "/text"} {
+		response = get(path, "gzip")
+		assert.Empty(t, response.Header().Get("Content-Encoding"), path)
+	}
+	assert.Equal(t, "{}", get("/small", "gzip").Body.String())
+
+	response = get("/large", "")
+	assert.Empty(t, response.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, response.Body.String())
+}
+
+// uncompressedRecorder records whether the responses were transparently decompressed by the transport.
+type uncompressedRecorder struct {
+	uncompressed atomic.Bool
+}
+
+func (r *uncompressedRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil && resp.Uncompressed {
+		r.uncompressed.Store(true)
+	}
+	return resp, err
+}
+
+func TestCompressionStreamableTransport(t *testing.T) {
+	large := strings.Repeat("result ", 1000)
+	g := &Gateway{mcpServer: mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)}
+	g.mcpServer.AddTool(&mcp.Tool{Name: "search", InputSchema: map[string]any{"type": "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: large}}}, nil
+	})
+	server := httptest.NewServer(compressionHandler(DefaultCompressionMinSize, g.streamableHandler()))
+	defer server.Close()
+
+	recorder := &uncompressedRecorder{}
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil)
+	session, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{
+		Endpoint:   server.URL,
+		HTTPClient: &http.Client{Transport: recorder},
+	}, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "search"})
+	require.NoError(t, err)
+	assert.Equal(t, large, result.Content[0].(*mcp.TextContent).Text)
+	assert.True(t, recorder.uncompressed.Load())
+}
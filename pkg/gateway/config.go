@@ -1,11 +1,17 @@
 package gateway
 
-import "github.com/docker/mcp-gateway/pkg/catalog"
+import (
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
 
 type Config struct {
 	Options
 	WorkingSet         string
+	DockerContext      string // Current Docker context, checked against the context the profile is bound to
 	ServerNames        []string
+	ServersMode        string // How ServerNames combine with the enabled servers of the profile
 	CatalogPath        []string
 	ConfigPath         []string
 	RegistryPath       []string
@@ -15,27 +21,76 @@ type Config struct {
 	MCPRegistryServers []catalog.Server // catalog.Server objects from MCP registries
 }
 
+// DefaultSecretsCacheTTL is how long the secrets read from Docker Desktop are cached.
+const DefaultSecretsCacheTTL = 5 * time.Minute
+
 type Options struct {
-	Port                    int
-	Transport               string
-	ToolNames               []string
-	Interceptors            []string
-	OciRef                  []string
-	Verbose                 bool
-	LongLived               bool
-	DebugDNS                bool
-	LogCalls                bool
-	BlockSecrets            bool
-	BlockNetwork            bool
-	VerifySignatures        bool
-	DryRun                  bool
-	Watch                   bool
-	Cpus                    int
-	Memory                  string
-	Static                  bool
-	OAuthInterceptorEnabled bool
-	McpOAuthDcrEnabled      bool
-	DynamicTools            bool
-	ToolNamePrefix          bool
-	LogFilePath             string
+	Port                       int
+	Transport                  string
+	ToolNames                  []string
+	Interceptors               []string
+	OciRef                     []string
+	Verbose                    bool
+	LongLived                  bool
+	DebugDNS                   bool
+	LogCalls                   bool
+	BlockSecrets               bool
+	BlockNetwork               bool
+	VerifySignatures           bool
+	DryRun                     bool
+	Watch                      bool
+	Cpus                       int
+	Memory                     string
+	Static                     bool
+	OAuthInterceptorEnabled    bool
+	McpOAuthDcrEnabled         bool
+	DynamicTools               bool
+	ToolNamePrefix             bool
+	ToolConflictStrategy       string
+	LogFilePath                string
+	LogMaxSize                 int // Megabytes
+	LogMaxBackups              int
+	LogFrames                  bool
+	LogFrameSample             int
+	ControlSocket              string
+	SkipBroken                 bool
+	ConfirmDestructiveTools    bool
+	ConfirmTools               []string
+	SessionBudget              float64
+	BudgetAction               string
+	PolicyPath                 string
+	NotificationsPath          string
+	LogRateLimit               int
+	LogRateInterval            time.Duration
+	AutoEnable                 string
+	PullPolicy                 string
+	SecretsCacheTTL            time.Duration
+	SessionIdleTimeout         time.Duration
+	MaxSessions                int
+	PersistProfile             bool
+	PageSize                   int
+	Instructions               string
+	Locale                     string
+	CoerceArguments            bool
+	MinifySchemas              bool
+	SchemaDescriptionMaxLength int
+	SchemaEnumMaxValues        int
+	InstructionsMaxSize        int // Bytes
+	OAuthIssuer                string
+	OAuthAudience              string
+	OAuthResource              string
+	OAuthScopes                []string
+	OAuthGroupsClaim           string
+	DiscoverLAN                bool
+	DiscoverHosts              []string
+	Dev                        bool
+	ScratchSize                string
+	CoalesceWindow             time.Duration
+	HTTPMaxConnsPerHost        int
+	HTTPMaxIdleConnsPerHost    int
+	HTTPIdleConnTimeout        time.Duration
+	CompressionMinSize         int
+	ContainerName              string
+	ProfileStartup             bool
+	ClientLeakThreshold        time.Duration
 }
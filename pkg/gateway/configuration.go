@@ -4,8 +4,11 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -22,6 +25,11 @@ import (
 
 type Configurator interface {
 	Read(ctx context.Context) (Configuration, chan Configuration, func() error, error)
+	// ReadServer re-reads the configuration and secrets of a single server.
+	// The returned Configuration only contains that server.
+	ReadServer(ctx context.Context, serverName string) (Configuration, error)
+	// SetSecret stores the value of a secret of a server in the secret store the configuration reads it from.
+	SetSecret(ctx context.Context, serverName, secretName, value string) error
 }
 
 type Configuration struct {
@@ -31,6 +39,9 @@ type Configuration struct {
 	tools       config.ToolsConfig
 	secrets     map[string]string
 	SessionName string
+
+	// report lists the servers that couldn't be loaded
+	report StartupReport
 }
 
 func (c *Configuration) ServerNames() []string {
@@ -63,6 +74,48 @@ func (c *Configuration) DockerImages() []string {
 	return dockerImages
 }
 
+// imageServers maps the docker images of the enabled servers to the names of the servers that use them.
+func (c *Configuration) imageServers() map[string][]string {
+	servers := map[string][]string{}
+
+	for _, serverName := range c.serverNames {
+		serverConfig, tools, found := c.Find(serverName)
+
+		switch {
+		case !found:
+		case serverConfig != nil && serverConfig.Spec.Image != "":
+			servers[serverConfig.Spec.Image] = append(servers[serverConfig.Spec.Image], serverName)
+		case tools != nil:
+			for _, tool := range *tools {
+				if !slices.Contains(servers[tool.Container.Image], serverName) {
+					servers[tool.Container.Image] = append(servers[tool.Container.Image], serverName)
+				}
+			}
+		}
+	}
+
+	return servers
+}
+
+// imagePulls returns how to pull each image, when servers override the platform or the pull policy.
+func (c *Configuration) imagePulls() map[string]imagePull {
+	pulls := map[string]imagePull{}
+
+	for _, serverName := range c.serverNames {
+		serverConfig, _, found := c.Find(serverName)
+		if !found || serverConfig == nil || serverConfig.Spec.Image == "" {
+			continue
+		}
+
+		pull := serverImagePull(serverConfig.Spec)
+		if pull.platform != "" || pull.policy != "" {
+			pulls[serverConfig.Spec.Image] = pull
+		}
+	}
+
+	return pulls
+}
+
 func (c *Configuration) Find(serverName string) (*catalog.ServerConfig, *map[string]catalog.Tool, bool) {
 	serverName = strings.TrimSpace(serverName)
 
@@ -72,8 +125,18 @@ func (c *Configuration) Find(serverName string) (*catalog.ServerConfig, *map[str
 		return nil, nil, false
 	}
 
+	// Is it a package that runs in a sandbox image?
+	if server.IsCommandServer() {
+		sandboxed, err := server.Sandboxed()
+		if err != nil {
+			log.Log("  - Can't run", serverName+":", err)
+			return nil, nil, false
+		}
+		server = sandboxed
+	}
+
 	// Is it an MCP Server?
-	if server.Image != "" || server.SSEEndpoint != "" || server.Remote.URL != "" {
+	if server.Image != "" || server.SSEEndpoint != "" || server.Remote.URL != "" || server.Type == catalog.TypePlugin || server.Type == catalog.TypeWasm {
 		return &catalog.ServerConfig{
 			Name: serverName,
 			Spec: server,
@@ -92,13 +155,61 @@ func (c *Configuration) Find(serverName string) (*catalog.ServerConfig, *map[str
 	return nil, &byName, true
 }
 
+// withServer returns a copy of the configuration where everything that belongs
+// to serverName (spec, config, tools and secrets) is replaced by what's in fresh.
+// Other servers are left untouched.
+func (c *Configuration) withServer(serverName string, fresh Configuration) Configuration {
+	merged := Configuration{
+		serverNames: c.serverNames,
+		servers:     make(map[string]catalog.Server, len(c.servers)),
+		config:      make(map[string]map[string]any, len(c.config)),
+		tools:       config.ToolsConfig{ServerTools: make(map[string][]string, len(c.tools.ServerTools))},
+		secrets:     make(map[string]string, len(c.secrets)),
+		SessionName: c.SessionName,
+	}
+	maps.Copy(merged.servers, c.servers)
+	maps.Copy(merged.config, c.config)
+	maps.Copy(merged.tools.ServerTools, c.tools.ServerTools)
+	maps.Copy(merged.secrets, c.secrets)
+
+	if server, ok := fresh.servers[serverName]; ok {
+		merged.servers[serverName] = server
+	}
+
+	canonicalName := oci.CanonicalizeServerName(serverName)
+	if serverConfig, ok := fresh.config[canonicalName]; ok {
+		merged.config[canonicalName] = serverConfig
+	} else {
+		delete(merged.config, canonicalName)
+	}
+
+	if tools, ok := fresh.tools.ServerTools[serverName]; ok {
+		merged.tools.ServerTools[serverName] = tools
+	} else {
+		delete(merged.tools.ServerTools, serverName)
+	}
+
+	maps.Copy(merged.secrets, fresh.secrets)
+
+	return merged
+}
+
+// withoutServers returns a copy of the configuration where the given servers are no longer enabled.
+func (c *Configuration) withoutServers(serverNames []string) Configuration {
+	trimmed := *c
+	trimmed.serverNames = slices.DeleteFunc(slices.Clone(c.serverNames), func(name string) bool {
+		return slices.Contains(serverNames, name)
+	})
+	return trimmed
+}
+
 // Persist writes the configuration files to the session directory if SessionName is set
 func (c *Configuration) Persist() error {
 	if c.SessionName == "" {
 		return nil // No session name set, nothing to persist
 	}
 
-	// Serialize and write registry.yaml
+	// Serialize registry.yaml
 	registry := config.Registry{
 		Servers: make(map[string]config.Tile),
 	}
@@ -111,29 +222,53 @@ func (c *Configuration) Persist() error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal registry: %w", err)
 	}
-	if err := config.WriteConfigFileToSession(c.SessionName, "registry.yaml", registryBytes); err != nil {
-		return fmt.Errorf("failed to write registry.yaml: %w", err)
-	}
 
-	// Serialize and write config.yaml
+	// Serialize config.yaml
 	configBytes, err := yaml.Marshal(c.config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
-	if err := config.WriteConfigFileToSession(c.SessionName, "config.yaml", configBytes); err != nil {
-		return fmt.Errorf("failed to write config.yaml: %w", err)
-	}
 
-	// Serialize and write tools.yaml
+	// Serialize tools.yaml
 	toolsBytes, err := yaml.Marshal(c.tools)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tools: %w", err)
 	}
-	if err := config.WriteConfigFileToSession(c.SessionName, "tools.yaml", toolsBytes); err != nil {
-		return fmt.Errorf("failed to write tools.yaml: %w", err)
+
+	// Write the three files as a new version, so that they can't be left half written or out of sync
+	version, err := config.WriteSessionFiles(c.SessionName, map[string][]byte{
+		"registry.yaml": registryBytes,
+		"config.yaml":   configBytes,
+		"tools.yaml":    toolsBytes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write session files: %w", err)
 	}
 
-	log.Log(fmt.Sprintf("  - Configuration persisted to session '%s'", c.SessionName))
+	log.Log(fmt.Sprintf("  - Configuration persisted to session '%s' (version %d)", c.SessionName, version))
+	return nil
+}
+
+// recoverSession restores the files of a session left behind by a gateway that stopped while persisting them,
+// and logs the changes that were lost.
+func recoverSession(sessionName string) error {
+	recovery, err := config.RecoverSessionFiles(sessionName)
+	if err != nil {
+		return fmt.Errorf("recovering session '%s': %w", sessionName, err)
+	}
+	if !recovery.Recovered() {
+		return nil
+	}
+
+	if len(recovery.Discarded) > 0 {
+		log.Logf("Warning: session '%s': discarded the incomplete versions %v of its configuration, the changes they held are lost", sessionName, recovery.Discarded)
+	}
+	for _, name := range recovery.Corrupted {
+		log.Logf("Warning: session '%s': %s was corrupted, the changes made to it after version %d are lost", sessionName, name, recovery.Version)
+	}
+	if len(recovery.Restored) > 0 {
+		log.Logf("  - Session '%s': restored %s from version %d after an interrupted write", sessionName, strings.Join(recovery.Restored, ", "), recovery.Version)
+	}
 	return nil
 }
 
@@ -154,6 +289,12 @@ type FileBasedConfiguration struct {
 }
 
 func (c *FileBasedConfiguration) Read(ctx context.Context) (Configuration, chan Configuration, func() error, error) {
+	if c.sessionName != "" {
+		if err := recoverSession(c.sessionName); err != nil {
+			return Configuration{}, nil, nil, err
+		}
+	}
+
 	configuration, err := c.readOnce(ctx)
 	if err != nil {
 		return Configuration{}, nil, nil, err
@@ -259,7 +400,27 @@ func (c *FileBasedConfiguration) Read(ctx context.Context) (Configuration, chan
 	return configuration, updates, watcher.Close, nil
 }
 
+func (c *FileBasedConfiguration) ReadServer(ctx context.Context, serverName string) (Configuration, error) {
+	configuration, err := c.read(ctx, []string{serverName})
+	if err != nil {
+		return Configuration{}, err
+	}
+
+	if _, ok := configuration.servers[serverName]; !ok {
+		return Configuration{}, fmt.Errorf("server %s not found in catalog", serverName)
+	}
+	configuration.serverNames = []string{serverName}
+
+	return configuration, nil
+}
+
 func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, error) {
+	return c.read(ctx, nil)
+}
+
+// read reads the whole configuration. If secretsFor is not empty, secrets are only
+// read for those servers instead of every enabled server.
+func (c *FileBasedConfiguration) read(ctx context.Context, secretsFor []string) (Configuration, error) {
 	start := time.Now()
 	log.Log("- Reading configuration...")
 
@@ -372,9 +533,14 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 		return Configuration{}, fmt.Errorf("reading tools: %w", err)
 	}
 
+	secretServerNames := serverNames
+	if len(secretsFor) > 0 {
+		secretServerNames = secretsFor
+	}
+
 	var secrets map[string]string
 	if c.SecretsPath == "docker-desktop" {
-		secrets, err = c.readDockerDesktopSecrets(ctx, servers, serverNames)
+		secrets, err = c.readDockerDesktopSecrets(ctx, servers, secretServerNames)
 		if err != nil {
 			return Configuration{}, fmt.Errorf("reading MCP Toolkit's secrets: %w", err)
 		}
@@ -385,7 +551,7 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 		var err error
 		for secretPath := range strings.SplitSeq(c.SecretsPath, ":") {
 			if secretPath == "docker-desktop" {
-				secrets, err = c.readDockerDesktopSecrets(ctx, servers, serverNames)
+				secrets, err = c.readDockerDesktopSecrets(ctx, servers, secretServerNames)
 			} else {
 				secrets, err = c.readSecretsFromFile(ctx, secretPath)
 			}
@@ -408,7 +574,13 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 
 func (c *FileBasedConfiguration) readCatalog(ctx context.Context) (catalog.Catalog, error) {
 	log.Log("  - Reading catalog from", c.CatalogPath)
-	return catalog.ReadFrom(ctx, c.CatalogPath)
+	mcpCatalog, err := catalog.ReadFrom(ctx, c.CatalogPath)
+	if err != nil {
+		return catalog.Catalog{}, err
+	}
+
+	// The servers compiled in the gateway can be enabled like the servers of the catalog
+	return catalog.WithPluginServers(mcpCatalog), nil
 }
 
 func (c *FileBasedConfiguration) readRegistry(ctx context.Context) (config.Registry, error) {
@@ -556,6 +728,33 @@ func (c *FileBasedConfiguration) readDockerDesktopSecrets(ctx context.Context, s
 	return secretsByName, nil
 }
 
+// SetSecret stores a secret in the first of the --secrets stores that can be written to,
+// Docker Desktop's secret store or a .env file.
+func (c *FileBasedConfiguration) SetSecret(ctx context.Context, _ string, secretName, value string) error {
+	var errs []error
+	for secretPath := range strings.SplitSeq(c.SecretsPath, ":") {
+		var err error
+		switch secretPath {
+		case "":
+			continue
+		case "docker-desktop":
+			err = setDockerDesktopSecret(ctx, secretName, value)
+		default:
+			err = writeSecretToFile(secretPath, secretName, value)
+		}
+
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return fmt.Errorf("no secret store to write %s to", secretName)
+	}
+	return fmt.Errorf("storing secret %s: %w", secretName, errors.Join(errs...))
+}
+
 func (c *FileBasedConfiguration) readSecretsFromFile(ctx context.Context, path string) (map[string]string, error) {
 	secrets := map[string]string{}
 
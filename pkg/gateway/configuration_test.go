@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/config"
 	"github.com/docker/mcp-gateway/pkg/docker"
 )
 
@@ -133,3 +134,75 @@ func TestReadServersFromOciNoRefs(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, servers, "Should return empty map when no OCI references provided")
 }
+
+func TestConfigurationWithServer(t *testing.T) {
+	current := Configuration{
+		serverNames: []string{"github", "fetch"},
+		servers: map[string]catalog.Server{
+			"github": {Image: "mcp/github:1"},
+			"fetch":  {Image: "mcp/fetch"},
+		},
+		config: map[string]map[string]any{
+			"github": {"owner": "old"},
+			"fetch":  {"timeout": 10},
+		},
+		tools: config.ToolsConfig{ServerTools: map[string][]string{
+			"github": {"list_issues"},
+			"fetch":  {"fetch"},
+		}},
+		secrets: map[string]string{
+			"github.token": "old-token",
+			"fetch.key":    "fetch-key",
+		},
+	}
+
+	fresh := Configuration{
+		serverNames: []string{"github"},
+		servers:     map[string]catalog.Server{"github": {Image: "mcp/github:2"}},
+		config:      map[string]map[string]any{"github": {"owner": "new"}},
+		tools:       config.ToolsConfig{ServerTools: map[string][]string{}},
+		secrets:     map[string]string{"github.token": "new-token"},
+	}
+
+	merged := current.withServer("github", fresh)
+
+	assert.Equal(t, []string{"github", "fetch"}, merged.ServerNames())
+	assert.Equal(t, "mcp/github:2", merged.servers["github"].Image)
+	assert.Equal(t, "mcp/fetch", merged.servers["fetch"].Image)
+	assert.Equal(t, map[string]any{"owner": "new"}, merged.config["github"])
+	assert.Equal(t, map[string]any{"timeout": 10}, merged.config["fetch"])
+	assert.NotContains(t, merged.tools.ServerTools, "github")
+	assert.Equal(t, []string{"fetch"}, merged.tools.ServerTools["fetch"])
+	assert.Equal(t, "new-token", merged.secrets["github.token"])
+	assert.Equal(t, "fetch-key", merged.secrets["fetch.key"])
+
+	// The current configuration is left untouched
+	assert.Equal(t, "mcp/github:1", current.servers["github"].Image)
+	assert.Equal(t, "old-token", current.secrets["github.token"])
+}
+
+func TestFindCommandServer(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"weather", "broken"},
+		servers: map[string]catalog.Server{
+			"weather": {
+				Type:    "command",
+				Package: &catalog.Package{RegistryType: catalog.PackageTypeNPM, Identifier: "@example/weather-mcp", Version: "1.0.2"},
+			},
+			"broken": {
+				Type:    "command",
+				Package: &catalog.Package{RegistryType: "nuget", Identifier: "Example.Weather"},
+			},
+		},
+	}
+
+	serverConfig, _, found := configuration.Find("weather")
+	require.True(t, found)
+	require.NotNil(t, serverConfig)
+	assert.Equal(t, catalog.NodeSandboxImage, serverConfig.Spec.Image)
+	assert.Equal(t, []string{"npx", "--yes", "@example/weather-mcp@1.0.2"}, serverConfig.Spec.Command)
+	assert.Equal(t, []string{catalog.NodeSandboxImage}, configuration.DockerImages())
+
+	_, _, found = configuration.Find("broken")
+	assert.False(t, found)
+}
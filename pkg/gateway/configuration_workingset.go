@@ -5,6 +5,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
@@ -14,25 +18,56 @@ import (
 	"github.com/docker/mcp-gateway/pkg/log"
 	"github.com/docker/mcp-gateway/pkg/migrate"
 	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/secretprovider"
 	"github.com/docker/mcp-gateway/pkg/workingset"
 )
 
+const (
+	// ServersModeIntersection only starts the servers of the profile that are listed.
+	ServersModeIntersection = "intersection"
+	// ServersModeUnion starts the enabled servers of the profile and the listed ones, even if they're disabled.
+	ServersModeUnion = "union"
+)
+
+// ServersModes lists the valid ways of combining --servers with the servers of a profile.
+var ServersModes = []string{ServersModeIntersection, ServersModeUnion}
+
 type WorkingSetConfiguration struct {
-	WorkingSet string
-	ociService oci.Service
-	docker     docker.Client
+	WorkingSet    string
+	DockerContext string
+	ociService    oci.Service
+	docker        docker.Client
+
+	// ServerNames overrides which servers of the profile start, depending on ServersMode
+	ServerNames []string
+	ServersMode string
+	// Dev applies the dev overrides of the servers
+	Dev bool
+
+	// The database client is kept for the lifetime of the gateway so that its cache is reused across reads.
+	daoOnce sync.Once
+	dao     db.DAO
+	daoErr  error
 }
 
-func NewWorkingSetConfiguration(workingSet string, ociService oci.Service, docker docker.Client) *WorkingSetConfiguration {
+func NewWorkingSetConfiguration(workingSet string, dockerContext string, ociService oci.Service, docker docker.Client) *WorkingSetConfiguration {
 	return &WorkingSetConfiguration{
-		WorkingSet: workingSet,
-		ociService: ociService,
-		docker:     docker,
+		WorkingSet:    workingSet,
+		DockerContext: dockerContext,
+		ociService:    ociService,
+		docker:        docker,
 	}
 }
 
+func (c *WorkingSetConfiguration) database() (db.DAO, error) {
+	c.daoOnce.Do(func() {
+		c.dao, c.daoErr = db.New()
+	})
+	return c.dao, c.daoErr
+}
+
 func (c *WorkingSetConfiguration) Read(ctx context.Context) (Configuration, chan Configuration, func() error, error) {
-	dao, err := db.New()
+	dao, err := c.database()
 	if err != nil {
 		return Configuration{}, nil, nil, fmt.Errorf("failed to create database client: %w", err)
 	}
@@ -51,24 +86,143 @@ func (c *WorkingSetConfiguration) Read(ctx context.Context) (Configuration, chan
 	return configuration, updates, func() error { return nil }, nil
 }
 
+func (c *WorkingSetConfiguration) ReadServer(ctx context.Context, serverName string) (Configuration, error) {
+	dao, err := c.database()
+	if err != nil {
+		return Configuration{}, fmt.Errorf("failed to create database client: %w", err)
+	}
+
+	workingSet, err := c.readWorkingSet(ctx, dao)
+	if err != nil {
+		return Configuration{}, err
+	}
+
+	// Servers that can't be resolved are ignored, they're not the one being reloaded
+	c.resolveSnapshots(ctx, &workingSet, &StartupReport{})
+
+	// Only keep the server being reloaded so that we only read its secrets
+	var servers []workingset.Server
+	for _, server := range workingSet.Servers {
+		if server.Snapshot != nil && server.Snapshot.Server.Name == serverName {
+			servers = append(servers, server)
+		}
+	}
+	if len(servers) == 0 {
+		return Configuration{}, fmt.Errorf("server %s not found in profile %s", serverName, c.WorkingSet)
+	}
+	workingSet.Servers = servers
+
+	return c.configurationFrom(ctx, workingSet)
+}
+
+// SetSecret stores a secret of a server in the secret provider the profile configures for it.
+// Only Docker Desktop's secret store can be written to, the other providers are read-only.
+func (c *WorkingSetConfiguration) SetSecret(ctx context.Context, serverName, secretName, value string) error {
+	dao, err := c.database()
+	if err != nil {
+		return fmt.Errorf("failed to create database client: %w", err)
+	}
+
+	workingSet, err := c.readWorkingSet(ctx, dao)
+	if err != nil {
+		return err
+	}
+
+	for _, server := range workingSet.Servers {
+		if server.Snapshot == nil || server.Snapshot.Server.Name != serverName {
+			continue
+		}
+		if server.Secrets == "" {
+			return setDockerDesktopSecret(ctx, secretName, value)
+		}
+
+		// The gateway sees the secrets namespaced to their provider
+		name := strings.TrimPrefix(secretName, server.Secrets+"_")
+		secretConfig, found := workingSet.Secrets[server.Secrets]
+		if !found {
+			return fmt.Errorf("secret provider %s of server %s not found in profile %s", server.Secrets, serverName, c.WorkingSet)
+		}
+		if secretConfig.Provider != workingset.SecretProviderDockerDesktop {
+			return fmt.Errorf("the secrets of %s are read from %s, which can't be written to by the gateway", serverName, secretConfig.Provider)
+		}
+		return setDockerDesktopSecret(ctx, name, value)
+	}
+
+	return fmt.Errorf("server %s not found in profile %s", serverName, c.WorkingSet)
+}
+
 func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Configuration, error) {
-	start := time.Now()
-	log.Log("- Reading profile configuration...")
+	workingSet, err := c.readWorkingSet(ctx, dao)
+	if err != nil {
+		return Configuration{}, err
+	}
+
+	configuration, err := c.configurationFrom(ctx, workingSet)
+	if err != nil {
+		return Configuration{}, err
+	}
 
+	for _, serverName := range c.ServerNames {
+		if _, found := configuration.servers[serverName]; !found {
+			return Configuration{}, fmt.Errorf("server %s not found in profile %s", serverName, c.WorkingSet)
+		}
+	}
+
+	return configuration, nil
+}
+
+// persistServerEnabled records in the profile whether a server is started.
+func (c *WorkingSetConfiguration) persistServerEnabled(ctx context.Context, serverName string, enabled bool) error {
+	dao, err := c.database()
+	if err != nil {
+		return fmt.Errorf("failed to create database client: %w", err)
+	}
+
+	return workingset.SetServersEnabled(ctx, dao, c.WorkingSet, []string{serverName}, enabled)
+}
+
+// starts tells whether a server of the profile is started, given whether it's enabled in the profile.
+func (c *WorkingSetConfiguration) starts(serverName string, enabled bool) bool {
+	if len(c.ServerNames) == 0 {
+		return enabled
+	}
+
+	listed := slices.Contains(c.ServerNames, serverName)
+	if c.ServersMode == ServersModeUnion {
+		return enabled || listed
+	}
+	return listed
+}
+
+func (c *WorkingSetConfiguration) readWorkingSet(ctx context.Context, dao db.DAO) (workingset.WorkingSet, error) {
 	dbWorkingSet, err := dao.GetWorkingSet(ctx, c.WorkingSet)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return Configuration{}, fmt.Errorf("profile %s not found", c.WorkingSet)
+			return workingset.WorkingSet{}, fmt.Errorf("profile %s not found", c.WorkingSet)
 		}
-		return Configuration{}, fmt.Errorf("failed to get profile: %w", err)
+		return workingset.WorkingSet{}, fmt.Errorf("failed to get profile: %w", err)
 	}
 
 	workingSet := workingset.NewFromDb(dbWorkingSet)
 
-	if err := workingSet.EnsureSnapshotsResolved(ctx, c.ociService); err != nil {
-		return Configuration{}, fmt.Errorf("failed to resolve snapshots: %w", err)
+	// A profile bound to a remote engine must never start its containers on another engine
+	if c.DockerContext != "" {
+		if err := workingSet.CheckDockerContext(c.DockerContext); err != nil {
+			return workingset.WorkingSet{}, err
+		}
 	}
 
+	return workingSet, nil
+}
+
+func (c *WorkingSetConfiguration) configurationFrom(ctx context.Context, workingSet workingset.WorkingSet) (Configuration, error) {
+	start := time.Now()
+	log.Log("- Reading profile configuration...")
+
+	// Servers that can't be resolved are not started and reported instead of failing everything
+	var report StartupReport
+	c.resolveSnapshots(ctx, &workingSet, &report)
+
 	cfg := make(map[string]map[string]any)
 	flattenedSecrets := make(map[string]string)
 
@@ -89,8 +243,13 @@ func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Con
 	serverNames := make([]string, 0)
 	servers := make(map[string]catalog.Server)
 	for _, server := range workingSet.Servers {
-		// Skip registry servers for now
-		if server.Type != workingset.ServerTypeImage && server.Type != workingset.ServerTypeRemote {
+		// Registry servers added before they were snapshotted can't be run
+		if server.Type == workingset.ServerTypeRegistry && server.Snapshot == nil {
+			report.skip(server.BasicName(), "registry server has no snapshot, add it to the profile again")
+			continue
+		}
+		if server.Type != workingset.ServerTypeImage && server.Type != workingset.ServerTypeRemote && server.Type != workingset.ServerTypeRegistry && server.Type != workingset.ServerTypeGateway {
+			report.skip(server.BasicName(), fmt.Sprintf("%s servers are not supported by the gateway yet", server.Type))
 			continue
 		}
 
@@ -100,8 +259,36 @@ func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Con
 			return Configuration{}, fmt.Errorf("duplicate server names: %s", serverName)
 		}
 
+		// Profiles can narrow down the OAuth scopes declared by the catalog
+		if len(server.OAuthScopes) > 0 {
+			oauthConfig := catalog.OAuth{}
+			if server.Snapshot.Server.OAuth != nil {
+				oauthConfig = *server.Snapshot.Server.OAuth
+			}
+			oauthConfig.Scopes = server.OAuthScopes
+			server.Snapshot.Server.OAuth = &oauthConfig
+		}
+
+		// Profiles can override the transforms applied to tool results by the catalog
+		if len(server.ToolTransforms) > 0 {
+			toolTransforms := maps.Clone(server.Snapshot.Server.ToolTransforms)
+			if toolTransforms == nil {
+				toolTransforms = map[string]string{}
+			}
+			maps.Copy(toolTransforms, server.ToolTransforms)
+			server.Snapshot.Server.ToolTransforms = toolTransforms
+		}
+
+		if c.Dev && server.Dev != nil {
+			server.Snapshot.Server = server.Dev.Apply(server.Snapshot.Server)
+			log.Log("  - Applying the dev overrides of", serverName)
+		}
+
 		servers[serverName] = server.Snapshot.Server
-		serverNames = append(serverNames, serverName)
+		// Disabled servers stay in the catalog so that they can be added with mcp-add
+		if c.starts(serverName, server.IsEnabled()) {
+			serverNames = append(serverNames, serverName)
+		}
 
 		cfg[serverName] = server.Config
 
@@ -121,9 +308,36 @@ func (c *WorkingSetConfiguration) readOnce(ctx context.Context, dao db.DAO) (Con
 		config:      cfg,
 		tools:       toolsConfig,
 		secrets:     flattenedSecrets,
+		report:      report,
 	}, nil
 }
 
+// resolveSnapshots makes sure every server has a snapshot. Servers whose snapshot can't be resolved
+// are removed from the working set and reported as failed.
+func (c *WorkingSetConfiguration) resolveSnapshots(ctx context.Context, workingSet *workingset.WorkingSet, report *StartupReport) {
+	resolved := make([]workingset.Server, 0, len(workingSet.Servers))
+	for _, server := range workingSet.Servers {
+		if server.Snapshot == nil {
+			log.Log(fmt.Sprintf("Server %s has no snapshot, lazy loading the snapshot...", server.BasicName()))
+			snapshot, err := workingset.ResolveSnapshot(ctx, c.ociService, server)
+			if err != nil {
+				report.fail(server.BasicName(), fmt.Sprintf("failed to resolve snapshot: %s", err))
+				continue
+			}
+			server.Snapshot = snapshot
+		}
+
+		// TODO(cody): Can be nil with registry (for now)
+		if server.Snapshot == nil && server.Type != workingset.ServerTypeRegistry {
+			report.fail(server.BasicName(), "no snapshot available for this server")
+			continue
+		}
+
+		resolved = append(resolved, server)
+	}
+	workingSet.Servers = resolved
+}
+
 func (c *WorkingSetConfiguration) readTools(workingSet workingset.WorkingSet) config.ToolsConfig {
 	toolsConfig := config.ToolsConfig{
 		ServerTools: make(map[string][]string),
@@ -152,6 +366,27 @@ func (c *WorkingSetConfiguration) readSecrets(ctx context.Context, workingSet wo
 				return nil, fmt.Errorf("failed to read docker desktop secrets: %w", err)
 			}
 			providerSecrets[providerRef] = secrets
+		case workingset.SecretProviderAWSSecretsManager:
+			provider := &secretprovider.AWSSecretsManager{Region: secretConfig.Region, Prefix: secretConfig.Prefix}
+			secrets, err := readProviderSecrets(ctx, "AWS Secrets Manager", provider, servers)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read AWS Secrets Manager secrets: %w", err)
+			}
+			providerSecrets[providerRef] = secrets
+		case workingset.SecretProviderAWSSSMParameterStore:
+			provider := &secretprovider.AWSParameterStore{Region: secretConfig.Region, Prefix: secretConfig.Prefix}
+			secrets, err := readProviderSecrets(ctx, "AWS Parameter Store", provider, servers)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read AWS Parameter Store secrets: %w", err)
+			}
+			providerSecrets[providerRef] = secrets
+		case workingset.SecretProviderOnePassword:
+			provider := &secretprovider.OnePassword{References: secretConfig.References, Vault: secretConfig.Vault}
+			secrets, err := readProviderSecrets(ctx, "1Password", provider, servers)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read 1Password secrets: %w", err)
+			}
+			providerSecrets[providerRef] = secrets
 		default:
 			return nil, fmt.Errorf("unknown secret provider: %s", secretConfig.Provider)
 		}
@@ -161,6 +396,32 @@ func (c *WorkingSetConfiguration) readSecrets(ctx context.Context, workingSet wo
 }
 
 func (c *WorkingSetConfiguration) readDockerDesktopSecrets(ctx context.Context, servers []workingset.Server) (map[string]string, error) {
+	secretNames := secretNamesUsedBy(servers)
+	if len(secretNames) == 0 {
+		return map[string]string{}, nil
+	}
+
+	log.Log("  - Reading secrets from Docker Desktop", secretNames)
+	secretsByName, err := c.docker.ReadSecrets(ctx, secretNames, true)
+	if err != nil {
+		return nil, fmt.Errorf("finding secrets %s: %w", secretNames, err)
+	}
+
+	return secretsByName, nil
+}
+
+func readProviderSecrets(ctx context.Context, storeName string, provider secretprovider.Provider, servers []workingset.Server) (map[string]string, error) {
+	secretNames := secretNamesUsedBy(servers)
+	if len(secretNames) == 0 {
+		return map[string]string{}, nil
+	}
+
+	log.Log("  - Reading secrets from "+storeName, secretNames)
+	return provider.GetSecrets(ctx, secretNames)
+}
+
+// secretNamesUsedBy returns the deduplicated names of the secrets used by the servers.
+func secretNamesUsedBy(servers []workingset.Server) []string {
 	// Use a map to deduplicate secret names
 	uniqueSecretNames := make(map[string]struct{})
 
@@ -172,23 +433,12 @@ func (c *WorkingSetConfiguration) readDockerDesktopSecrets(ctx context.Context,
 		}
 	}
 
-	if len(uniqueSecretNames) == 0 {
-		return map[string]string{}, nil
-	}
-
 	// Convert map keys to slice
 	var secretNames []string
 	for name := range uniqueSecretNames {
 		secretNames = append(secretNames, name)
 	}
-
-	log.Log("  - Reading secrets from Docker Desktop", secretNames)
-	secretsByName, err := c.docker.ReadSecrets(ctx, secretNames, true)
-	if err != nil {
-		return nil, fmt.Errorf("finding secrets %s: %w", secretNames, err)
-	}
-
-	return secretsByName, nil
+	return secretNames
 }
 
 func getServersUsingProvider(workingSet workingset.WorkingSet, providerRef string) []workingset.Server {
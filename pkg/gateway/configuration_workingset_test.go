@@ -0,0 +1,144 @@
+package gateway
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+	"github.com/docker/mcp-gateway/test/mocks"
+)
+
+func TestReadWorkingSetDockerContext(t *testing.T) {
+	dao, err := db.New(db.WithDatabaseFile(filepath.Join(t.TempDir(), "test.db")))
+	require.NoError(t, err)
+
+	err = dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:            "remote-tools",
+		Name:          "Remote Tools",
+		Servers:       db.ServerList{},
+		Secrets:       db.SecretMap{},
+		DockerContext: "remote",
+	})
+	require.NoError(t, err)
+
+	t.Run("same context", func(t *testing.T) {
+		c := NewWorkingSetConfiguration("remote-tools", "remote", mocks.NewMockOCIService(), nil)
+
+		workingSet, err := c.readWorkingSet(t.Context(), dao)
+		require.NoError(t, err)
+		assert.Equal(t, "remote", workingSet.DockerContext)
+	})
+
+	t.Run("other context", func(t *testing.T) {
+		c := NewWorkingSetConfiguration("remote-tools", "desktop-linux", mocks.NewMockOCIService(), nil)
+
+		_, err := c.readWorkingSet(t.Context(), dao)
+		require.ErrorContains(t, err, "profile remote-tools is bound to Docker context remote, but the current context is desktop-linux")
+	})
+
+	t.Run("unknown context", func(t *testing.T) {
+		c := NewWorkingSetConfiguration("remote-tools", "", mocks.NewMockOCIService(), nil)
+
+		_, err := c.readWorkingSet(t.Context(), dao)
+		require.NoError(t, err)
+	})
+}
+
+func TestWorkingSetDisabledServers(t *testing.T) {
+	disabled := false
+	c := NewWorkingSetConfiguration("dev-tools", "", mocks.NewMockOCIService(), nil)
+
+	configuration, err := c.configurationFrom(t.Context(), workingset.WorkingSet{
+		ID: "dev-tools",
+		Servers: []workingset.Server{
+			{Type: workingset.ServerTypeImage, Image: "mcp/fetch", Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "fetch", Image: "mcp/fetch"}}},
+			{Type: workingset.ServerTypeImage, Image: "mcp/github", Enabled: &disabled, Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "github", Image: "mcp/github"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"fetch"}, configuration.ServerNames())
+	_, _, found := configuration.Find("github")
+	assert.True(t, found, "disabled servers can still be added with mcp-add")
+}
+
+func TestWorkingSetServersOverride(t *testing.T) {
+	dao, err := db.New(db.WithDatabaseFile(filepath.Join(t.TempDir(), "test.db")))
+	require.NoError(t, err)
+
+	disabled := false
+	err = dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "dev-tools",
+		Name: "Dev Tools",
+		Servers: db.ServerList{
+			{Type: "image", Image: "mcp/fetch", Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: "fetch", Image: "mcp/fetch"}}},
+			{Type: "image", Image: "mcp/time", Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: "time", Image: "mcp/time"}}},
+			{Type: "image", Image: "mcp/github", Enabled: &disabled, Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: "github", Image: "mcp/github"}}},
+		},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	read := func(serverNames []string, mode string) (Configuration, error) {
+		c := NewWorkingSetConfiguration("dev-tools", "", mocks.NewMockOCIService(), nil)
+		c.ServerNames = serverNames
+		c.ServersMode = mode
+		return c.readOnce(t.Context(), dao)
+	}
+
+	configuration, err := read(nil, ServersModeIntersection)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fetch", "time"}, configuration.ServerNames())
+
+	configuration, err = read([]string{"time", "github"}, ServersModeIntersection)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"time", "github"}, configuration.ServerNames())
+
+	configuration, err = read([]string{"github"}, ServersModeUnion)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fetch", "time", "github"}, configuration.ServerNames())
+
+	_, err = read([]string{"slack"}, ServersModeUnion)
+	require.ErrorContains(t, err, "server slack not found in profile dev-tools")
+
+	// mcp-add and mcp-remove write back to the profile with --persist
+	c := NewWorkingSetConfiguration("dev-tools", "", mocks.NewMockOCIService(), nil)
+	c.daoOnce.Do(func() { c.dao = dao })
+	require.NoError(t, c.persistServerEnabled(t.Context(), "github", true))
+	require.NoError(t, c.persistServerEnabled(t.Context(), "fetch", false))
+
+	configuration, err = c.readOnce(t.Context(), dao)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"time", "github"}, configuration.ServerNames())
+}
+
+func TestWorkingSetDevOverrides(t *testing.T) {
+	workingSet := workingset.WorkingSet{
+		ID: "dev-tools",
+		Servers: []workingset.Server{{
+			Type:     workingset.ServerTypeImage,
+			Image:    "my-server:latest",
+			Dev:      &workingset.ServerDev{Volumes: []string{"/src:/app/src"}, Env: map[string]string{"DEBUG": "1"}},
+			Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "my-server", Image: "my-server:latest"}},
+		}},
+	}
+
+	c := NewWorkingSetConfiguration("dev-tools", "", mocks.NewMockOCIService(), nil)
+	configuration, err := c.configurationFrom(t.Context(), workingSet)
+	require.NoError(t, err)
+	server, _, _ := configuration.Find("my-server")
+	assert.Empty(t, server.Spec.Volumes)
+	assert.Empty(t, server.Spec.Env)
+
+	c.Dev = true
+	configuration, err = c.configurationFrom(t.Context(), workingSet)
+	require.NoError(t, err)
+	server, _, _ = configuration.Find("my-server")
+	assert.Equal(t, []string{"/src:/app/src"}, server.Spec.Volumes)
+	assert.Equal(t, []catalog.Env{{Name: "DEBUG", Value: "1"}}, server.Spec.Env)
+}
@@ -0,0 +1,129 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// needsConfirmation returns true if the user must confirm calls to a tool:
+// tools annotated as destructive when ConfirmDestructiveTools is set, and tools matching a ConfirmTools pattern.
+// It also returns the question asked to the user, which tells why the tool needs a confirmation.
+func (g *Gateway) needsConfirmation(toolName string, annotations *mcp.ToolAnnotations) (string, bool) {
+	if g.ConfirmDestructiveTools && annotations != nil && annotations.DestructiveHint != nil && *annotations.DestructiveHint {
+		return fmt.Sprintf("The '%s' tool can make destructive changes. Do you want to run it?", toolName), true
+	}
+
+	for _, pattern := range g.ConfirmTools {
+		if matched, _ := path.Match(pattern, toolName); matched {
+			return fmt.Sprintf("The '%s' tool requires a confirmation before running. Do you want to run it?", toolName), true
+		}
+	}
+
+	return "", false
+}
+
+// confirmToolCall asks the client's user to confirm a tool call through elicitation.
+// Returns a non-nil result, to send back instead of calling the tool, when the call is not confirmed.
+func (g *Gateway) confirmToolCall(ctx context.Context, req *mcp.CallToolRequest, toolName string, annotations *mcp.ToolAnnotations) *mcp.CallToolResult {
+	message, needed := g.needsConfirmation(toolName, annotations)
+	if !needed {
+		return nil
+	}
+
+	session := req.Session
+	client := sessionClientName(session)
+	if session != nil && g.isToolAlwaysAllowed(session, toolName) {
+		log.Logf("  > Confirmation of %s by %s: always allowed for this session", toolName, client)
+		return nil
+	}
+
+	if session == nil || session.InitializeParams() == nil || session.InitializeParams().Capabilities == nil || session.InitializeParams().Capabilities.Elicitation == nil {
+		log.Logf("  > Confirmation of %s by %s: denied, the client doesn't support elicitation", toolName, client)
+		return confirmationDenied(toolName, "the gateway requires a confirmation and the client doesn't support elicitation")
+	}
+
+	elicitResult, err := session.Elicit(ctx, &mcp.ElicitParams{
+		Message: message,
+		RequestedSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"confirm": {
+					Type:        "boolean",
+					Description: "Run the tool",
+				},
+				"alwaysAllow": {
+					Type:        "boolean",
+					Description: "Don't ask again for this tool in this session",
+				},
+			},
+			Required: []string{"confirm"},
+		},
+	})
+	if err != nil {
+		log.Logf("  > Confirmation of %s by %s: denied, elicitation failed: %v", toolName, client, err)
+		return confirmationDenied(toolName, "the confirmation request failed")
+	}
+
+	if elicitResult.Action != "accept" || elicitResult.Content == nil {
+		log.Logf("  > Confirmation of %s by %s: denied, user answered %s", toolName, client, elicitResult.Action)
+		return confirmationDenied(toolName, "the user didn't confirm the call")
+	}
+	if confirm, _ := elicitResult.Content["confirm"].(bool); !confirm {
+		log.Logf("  > Confirmation of %s by %s: denied by user", toolName, client)
+		return confirmationDenied(toolName, "the user didn't confirm the call")
+	}
+
+	if alwaysAllow, _ := elicitResult.Content["alwaysAllow"].(bool); alwaysAllow {
+		g.alwaysAllowTool(session, toolName)
+		log.Logf("  > Confirmation of %s by %s: allowed by user, for the rest of the session", toolName, client)
+	} else {
+		log.Logf("  > Confirmation of %s by %s: allowed by user", toolName, client)
+	}
+
+	return nil
+}
+
+func sessionClientName(session *mcp.ServerSession) string {
+	if session == nil || session.InitializeParams() == nil || session.InitializeParams().ClientInfo == nil {
+		return "unknown client"
+	}
+	return session.InitializeParams().ClientInfo.Name
+}
+
+func confirmationDenied(toolName, reason string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{
+			Text: fmt.Sprintf("The call to %s was not executed: %s.", toolName, reason),
+		}},
+		IsError: true,
+	}
+}
+
+func (g *Gateway) isToolAlwaysAllowed(ss *mcp.ServerSession, toolName string) bool {
+	g.sessionCacheMu.RLock()
+	defer g.sessionCacheMu.RUnlock()
+
+	cache := g.sessionCache[ss]
+	return cache != nil && cache.AlwaysAllowedTools[toolName]
+}
+
+func (g *Gateway) alwaysAllowTool(ss *mcp.ServerSession, toolName string) {
+	g.sessionCacheMu.Lock()
+	defer g.sessionCacheMu.Unlock()
+
+	cache := g.sessionCache[ss]
+	if cache == nil {
+		cache = &ServerSessionCache{}
+		g.sessionCache[ss] = cache
+	}
+	if cache.AlwaysAllowedTools == nil {
+		cache.AlwaysAllowedTools = make(map[string]bool)
+	}
+	cache.AlwaysAllowedTools[toolName] = true
+}
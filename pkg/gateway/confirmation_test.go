@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeedsConfirmation(t *testing.T) {
+	destructive := true
+	notDestructive := false
+
+	tests := []struct {
+		name        string
+		options     Options
+		toolName    string
+		annotations *mcp.ToolAnnotations
+		expected    bool
+		message     string
+	}{
+		{
+			name:        "disabled",
+			toolName:    "delete_repo",
+			annotations: &mcp.ToolAnnotations{DestructiveHint: &destructive},
+		},
+		{
+			name:        "destructive",
+			options:     Options{ConfirmDestructiveTools: true},
+			toolName:    "delete_repo",
+			annotations: &mcp.ToolAnnotations{DestructiveHint: &destructive},
+			expected:    true,
+			message:     "The 'delete_repo' tool can make destructive changes. Do you want to run it?",
+		},
+		{
+			name:        "not destructive",
+			options:     Options{ConfirmDestructiveTools: true},
+			toolName:    "list_repos",
+			annotations: &mcp.ToolAnnotations{DestructiveHint: &notDestructive},
+		},
+		{
+			name:     "no annotations",
+			options:  Options{ConfirmDestructiveTools: true},
+			toolName: "list_repos",
+		},
+		{
+			name:     "pattern",
+			options:  Options{ConfirmTools: []string{"delete_*"}},
+			toolName: "delete_repo",
+			expected: true,
+			message:  "The 'delete_repo' tool requires a confirmation before running. Do you want to run it?",
+		},
+		{
+			name:     "pattern mismatch",
+			options:  Options{ConfirmTools: []string{"delete_*"}},
+			toolName: "create_repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Gateway{Options: tt.options}
+			message, needed := g.needsConfirmation(tt.toolName, tt.annotations)
+			assert.Equal(t, tt.expected, needed)
+			assert.Equal(t, tt.message, message)
+		})
+	}
+}
+
+func TestConfirmToolCallWithoutElicitation(t *testing.T) {
+	g := &Gateway{Options: Options{ConfirmTools: []string{"delete_*"}}}
+
+	result := g.confirmToolCall(context.Background(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "delete_repo"}}, "delete_repo", nil)
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+
+	result = g.confirmToolCall(context.Background(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "list_repos"}}, "list_repos", nil)
+	assert.Nil(t, result)
+}
+
+func TestAlwaysAllowTool(t *testing.T) {
+	g := &Gateway{sessionCache: map[*mcp.ServerSession]*ServerSessionCache{}}
+	session := &mcp.ServerSession{}
+
+	assert.False(t, g.isToolAlwaysAllowed(session, "delete_repo"))
+
+	g.alwaysAllowTool(session, "delete_repo")
+	assert.True(t, g.isToolAlwaysAllowed(session, "delete_repo"))
+	assert.False(t, g.isToolAlwaysAllowed(session, "delete_issue"))
+
+	g.RemoveSessionCache(session)
+	assert.False(t, g.isToolAlwaysAllowed(session, "delete_repo"))
+}
@@ -0,0 +1,173 @@
+package gateway
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// Strategies to resolve tools with the same name exposed by different servers.
+const (
+	// ToolConflictPrefix keeps the first tool and prefixes the other ones with their server name.
+	ToolConflictPrefix = "prefix"
+	// ToolConflictFirstWins keeps the first tool and drops the other ones.
+	ToolConflictFirstWins = "first-wins"
+	// ToolConflictLastWins keeps the last tool registered. That's how the gateway used to behave.
+	ToolConflictLastWins = "last-wins"
+)
+
+// ToolConflictStrategies lists the valid values for Options.ToolConflictStrategy.
+var ToolConflictStrategies = []string{ToolConflictPrefix, ToolConflictFirstWins, ToolConflictLastWins}
+
+// ToolConflict describes a tool name exposed by more than one server and how it was resolved.
+type ToolConflict struct {
+	Tool       string   `json:"tool"`
+	Servers    []string `json:"servers"`
+	Resolution string   `json:"resolution"`
+}
+
+func (c ToolConflict) String() string {
+	return fmt.Sprintf("%s (%s): %s", c.Tool, strings.Join(c.Servers, ", "), c.Resolution)
+}
+
+// resolveToolConflicts detects tools whose name is already taken by another server and applies the
+// conflict strategy. owners maps the tool names that are already registered to their server.
+// Tools are processed in order, so the caller decides who comes first.
+func resolveToolConflicts(strategy string, owners map[string]string, tools []ToolRegistration) ([]ToolRegistration, []ToolConflict) {
+	taken := make(map[string]string, len(owners)+len(tools))
+	for name, serverName := range owners {
+		taken[name] = serverName
+	}
+	indexes := make(map[string]int, len(tools))
+
+	var (
+		resolved  []ToolRegistration
+		conflicts []ToolConflict
+	)
+	for _, tool := range tools {
+		name := tool.Tool.Name
+
+		owner, exists := taken[name]
+		if !exists || owner == tool.ServerName {
+			taken[name] = tool.ServerName
+			indexes[name] = len(resolved)
+			resolved = append(resolved, tool)
+			continue
+		}
+
+		conflict := ToolConflict{
+			Tool:    name,
+			Servers: []string{owner, tool.ServerName},
+		}
+
+		switch strategy {
+		case ToolConflictFirstWins:
+			conflict.Resolution = "kept the tool from " + owner
+
+		case ToolConflictLastWins:
+			conflict.Resolution = "kept the tool from " + tool.ServerName
+			taken[name] = tool.ServerName
+			if i, ok := indexes[name]; ok {
+				resolved[i] = tool
+			} else {
+				indexes[name] = len(resolved)
+				resolved = append(resolved, tool)
+			}
+
+		default:
+			renamed := prefixToolName(tool.ServerName, name)
+			if _, exists := taken[renamed]; exists {
+				conflict.Resolution = "dropped the tool from " + tool.ServerName + ", " + renamed + " is already taken"
+				break
+			}
+
+			prefixedTool := *tool.Tool
+			prefixedTool.Name = renamed
+			tool.Tool = &prefixedTool
+
+			conflict.Resolution = "renamed the tool from " + tool.ServerName + " to " + renamed
+			taken[renamed] = tool.ServerName
+			indexes[renamed] = len(resolved)
+			resolved = append(resolved, tool)
+		}
+
+		conflicts = append(conflicts, conflict)
+	}
+
+	return resolved, conflicts
+}
+
+// sortToolsByServer orders tools following the order of the enabled servers,
+// so that conflict resolution doesn't depend on which server answered first.
+func sortToolsByServer(tools []ToolRegistration, serverNames []string) {
+	slices.SortStableFunc(tools, func(a, b ToolRegistration) int {
+		return slices.Index(serverNames, a.ServerName) - slices.Index(serverNames, b.ServerName)
+	})
+}
+
+// toolOwners returns which server each registered tool belongs to, ignoring one server.
+// This function expects g.capabilitiesMu to be locked by the caller.
+func (g *Gateway) toolOwners(except string) map[string]string {
+	owners := make(map[string]string)
+	for serverName, caps := range g.serverCapabilities {
+		if serverName == except {
+			continue
+		}
+		for _, toolName := range caps.ToolNames {
+			owners[toolName] = serverName
+		}
+	}
+	return owners
+}
+
+// releaseTakenOverTools removes the tools taken over by a server, with last-wins, from the servers that owned them,
+// so that removing or reloading those servers later doesn't remove the tools that replaced them.
+// owners maps the tool names registered before to their server.
+// This function expects g.capabilitiesMu to be locked by the caller.
+func (g *Gateway) releaseTakenOverTools(owners map[string]string, tools []ToolRegistration) {
+	for _, tool := range tools {
+		name := tool.Tool.Name
+		owner, found := owners[name]
+		if !found || owner == tool.ServerName {
+			continue
+		}
+
+		if caps := g.serverCapabilities[owner]; caps != nil {
+			caps.ToolNames = slices.DeleteFunc(slices.Clone(caps.ToolNames), func(toolName string) bool { return toolName == name })
+		}
+		if available := g.serverAvailableCapabilities[owner]; available != nil {
+			available.Tools = slices.DeleteFunc(slices.Clone(available.Tools), func(t ToolRegistration) bool { return t.Tool.Name == name })
+		}
+		// The server taking over the tool registers it again if it's active
+		if g.mcpServer != nil {
+			g.mcpServer.RemoveTools(name)
+		}
+	}
+}
+
+// setToolConflicts replaces the conflicts that involve serverName, or all of them if serverName is empty.
+// This function expects g.capabilitiesMu to be locked by the caller.
+func (g *Gateway) setToolConflicts(serverName string, conflicts []ToolConflict) {
+	if serverName == "" {
+		g.toolConflicts = nil
+	} else {
+		g.toolConflicts = slices.DeleteFunc(g.toolConflicts, func(c ToolConflict) bool {
+			return slices.Contains(c.Servers, serverName)
+		})
+	}
+	g.toolConflicts = append(g.toolConflicts, conflicts...)
+
+	for _, conflict := range conflicts {
+		log.Log("  ! Tool name conflict:", conflict.String())
+	}
+}
+
+// ToolConflicts returns the tool name conflicts detected while registering capabilities.
+func (g *Gateway) ToolConflicts() []ToolConflict {
+	g.capabilitiesMu.RLock()
+	defer g.capabilitiesMu.RUnlock()
+
+	return slices.Clone(g.toolConflicts)
+}
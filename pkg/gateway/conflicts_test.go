@@ -0,0 +1,174 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func toolRegistration(serverName, toolName string) ToolRegistration {
+	return ToolRegistration{
+		ServerName: serverName,
+		Tool:       &mcp.Tool{Name: toolName},
+	}
+}
+
+func registeredTools(tools []ToolRegistration) []string {
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.ServerName+"/"+tool.Tool.Name)
+	}
+	return names
+}
+
+func TestResolveToolConflicts(t *testing.T) {
+	tools := []ToolRegistration{
+		toolRegistration("github", "search"),
+		toolRegistration("github", "list_issues"),
+		toolRegistration("gitlab", "search"),
+		toolRegistration("gitlab", "list_merge_requests"),
+	}
+
+	tests := []struct {
+		name          string
+		strategy      string
+		expectedTools []string
+		resolution    string
+	}{
+		{
+			name:          "prefix",
+			strategy:      ToolConflictPrefix,
+			expectedTools: []string{"github/search", "github/list_issues", "gitlab/gitlab:search", "gitlab/list_merge_requests"},
+			resolution:    "renamed the tool from gitlab to gitlab:search",
+		},
+		{
+			name:          "default to prefix",
+			strategy:      "",
+			expectedTools: []string{"github/search", "github/list_issues", "gitlab/gitlab:search", "gitlab/list_merge_requests"},
+			resolution:    "renamed the tool from gitlab to gitlab:search",
+		},
+		{
+			name:          "first wins",
+			strategy:      ToolConflictFirstWins,
+			expectedTools: []string{"github/search", "github/list_issues", "gitlab/list_merge_requests"},
+			resolution:    "kept the tool from github",
+		},
+		{
+			name:          "last wins",
+			strategy:      ToolConflictLastWins,
+			expectedTools: []string{"gitlab/search", "github/list_issues", "gitlab/list_merge_requests"},
+			resolution:    "kept the tool from gitlab",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, conflicts := resolveToolConflicts(tt.strategy, nil, tools)
+
+			assert.Equal(t, tt.expectedTools, registeredTools(resolved))
+			assert.Equal(t, []ToolConflict{{
+				Tool:       "search",
+				Servers:    []string{"github", "gitlab"},
+				Resolution: tt.resolution,
+			}}, conflicts)
+		})
+	}
+
+	// The original tools are not modified
+	assert.Equal(t, "search", tools[2].Tool.Name)
+}
+
+func TestResolveToolConflictsWithRegisteredTools(t *testing.T) {
+	owners := map[string]string{"search": "github"}
+
+	resolved, conflicts := resolveToolConflicts(ToolConflictPrefix, owners, []ToolRegistration{
+		toolRegistration("gitlab", "search"),
+		toolRegistration("github", "list_issues"),
+	})
+
+	assert.Equal(t, []string{"gitlab/gitlab:search", "github/list_issues"}, registeredTools(resolved))
+	assert.Len(t, conflicts, 1)
+}
+
+func TestResolveToolConflictsNoConflict(t *testing.T) {
+	tools := []ToolRegistration{
+		toolRegistration("github", "search"),
+		toolRegistration("fetch", "fetch"),
+	}
+
+	resolved, conflicts := resolveToolConflicts(ToolConflictPrefix, map[string]string{"search": "github"}, tools)
+
+	assert.Equal(t, tools, resolved)
+	assert.Empty(t, conflicts)
+}
+
+func TestSortToolsByServer(t *testing.T) {
+	tools := []ToolRegistration{
+		toolRegistration("gitlab", "search"),
+		toolRegistration("github", "search"),
+		toolRegistration("gitlab", "list"),
+	}
+
+	sortToolsByServer(tools, []string{"github", "gitlab"})
+
+	assert.Equal(t, []string{"github/search", "gitlab/search", "gitlab/list"}, registeredTools(tools))
+}
+
+func TestRemoveServerAfterToolTakeover(t *testing.T) {
+	g := &Gateway{
+		Options:                     Options{ToolConflictStrategy: ToolConflictLastWins},
+		mcpServer:                   mcp.NewServer(&mcp.Implementation{Name: "gateway"}, &mcp.ServerOptions{HasTools: true}),
+		serverCapabilities:          map[string]*ServerCapabilities{},
+		serverAvailableCapabilities: map[string]*Capabilities{},
+		toolRegistrations:           map[string]ToolRegistration{},
+		configuration: Configuration{
+			serverNames: []string{"github", "gitlab"},
+			servers: map[string]catalog.Server{
+				"github": {Name: "github", Image: "mcp/github"},
+				"gitlab": {Name: "gitlab", Image: "mcp/gitlab"},
+			},
+		},
+	}
+	add := func(serverName string) {
+		tool := ToolRegistration{
+			ServerName: serverName,
+			Tool:       &mcp.Tool{Name: "search", InputSchema: &jsonschema.Schema{Type: "object"}},
+			Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: serverName}}}, nil
+			},
+		}
+		oldCaps := g.storeServerCapabilities(serverName, &Capabilities{Tools: []ToolRegistration{tool}})
+
+		g.capabilitiesMu.Lock()
+		defer g.capabilitiesMu.Unlock()
+		require.NoError(t, g.updateServerCapabilities(serverName, oldCaps, g.allCapabilities(serverName), nil))
+	}
+
+	add("github")
+	// gitlab takes the tool over
+	add("gitlab")
+	assert.Equal(t, "gitlab", g.toolRegistrations["search"].ServerName)
+	assert.Empty(t, g.serverCapabilities["github"].ToolNames)
+	assert.Equal(t, []string{"search"}, g.serverCapabilities["gitlab"].ToolNames)
+
+	require.NoError(t, g.removeServerConfiguration(t.Context(), "github"))
+
+	// Removing github leaves the tool of gitlab
+	assert.Equal(t, "gitlab", g.toolRegistrations["search"].ServerName)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := g.mcpServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "search"})
+	require.NoError(t, err)
+	assert.Equal(t, "gitlab", result.Content[0].(*mcp.TextContent).Text)
+}
@@ -0,0 +1,273 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/notify"
+	"github.com/docker/mcp-gateway/pkg/oauth"
+	"github.com/docker/mcp-gateway/pkg/user"
+)
+
+// controlPathPrefix is where the control API is mounted on the sse and streaming transports.
+const controlPathPrefix = "/control"
+
+// ReloadRequest is the body of a POST /control/reload request.
+type ReloadRequest struct {
+	Server string `json:"server"`
+}
+
+// ReloadResponse is returned by the control API after a server was reloaded.
+type ReloadResponse struct {
+	Server string   `json:"server"`
+	Tools  []string `json:"tools"`
+}
+
+// RotateSecretsResponse is returned by the control API after the secrets were re-read.
+type RotateSecretsResponse struct {
+	Servers []string `json:"servers"`
+}
+
+// RevokeRequest is the body of a POST /control/oauth/revoke request.
+type RevokeRequest struct {
+	Server string `json:"server"`
+}
+
+// OAuthStatusResponse is returned by GET /control/oauth.
+type OAuthStatusResponse struct {
+	Providers []oauth.ProviderState `json:"providers"`
+}
+
+// OverrideRequest is the body of a POST /control/policy/override request.
+type OverrideRequest struct {
+	Server   string `json:"server"`
+	Duration string `json:"duration"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// OverrideResponse is returned by the control API after an on-call override was granted.
+type OverrideResponse struct {
+	Server string    `json:"server"`
+	Until  time.Time `json:"until"`
+}
+
+type controlError struct {
+	Error string `json:"error"`
+}
+
+// DefaultControlSocketPath returns the default path of the gateway's control socket.
+func DefaultControlSocketPath() (string, error) {
+	homeDir, err := user.HomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".docker", "mcp", "gateway.sock"), nil
+}
+
+// mountControlAPI serves the control API under controlPathPrefix, only when the gateway's bearer token protects it.
+// Without the token, eg. in a container, anything that reaches the port could reload servers, revoke tokens or
+// lift the policy, so the control API is then only served on --control-socket.
+func (g *Gateway) mountControlAPI(mux *http.ServeMux) {
+	if g.authToken == "" {
+		log.Log("  > The control API is only served on --control-socket, without a bearer token")
+		return
+	}
+	mux.Handle(controlPathPrefix+"/", originSecurityHandler(http.StripPrefix(controlPathPrefix, g.controlHandler())))
+}
+
+// controlHandler serves the gateway's control API on the port of the sse and streaming transports.
+// The debug endpoints are only served there with --profile-startup.
+func (g *Gateway) controlHandler() http.Handler {
+	return g.controlMux(g.ProfileStartup)
+}
+
+// controlMux serves the gateway's control API, with the debug endpoints if asked to.
+func (g *Gateway) controlMux(debug bool) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /reload", g.handleReload)
+	mux.HandleFunc("POST /secrets/rotate", g.handleRotateSecrets)
+	mux.HandleFunc("GET /oauth", g.handleOAuthStatus)
+	mux.HandleFunc("POST /oauth/revoke", g.handleOAuthRevoke)
+	mux.HandleFunc("POST /policy/override", g.handlePolicyOverride)
+	mux.HandleFunc("GET /events", g.handleEvents)
+	if g.ProfileStartup {
+		g.registerStartupProfileHandlers(mux)
+	}
+	if debug {
+		g.registerDebugHandlers(mux)
+	}
+
+	return mux
+}
+
+func (g *Gateway) handleReload(w http.ResponseWriter, r *http.Request) {
+	var req ReloadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	if req.Server == "" {
+		writeControlError(w, http.StatusBadRequest, errors.New("server is required"))
+		return
+	}
+
+	unlock := g.lockServer(strings.TrimSpace(req.Server))
+	err := g.ReloadServer(r.Context(), req.Server)
+	unlock()
+	if err != nil {
+		log.Logf("! Failed to reload server %s: %s", req.Server, err)
+		g.emit(notify.Event{Type: notify.EventError, Server: req.Server, Message: fmt.Sprintf("Failed to reload server %s: %s", req.Server, err)})
+		writeControlError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := ReloadResponse{
+		Server: req.Server,
+		Tools:  []string{},
+	}
+	g.capabilitiesMu.RLock()
+	if caps := g.serverAvailableCapabilities[req.Server]; caps != nil {
+		response.Tools = append(response.Tools, caps.ToolNames()...)
+	}
+	g.capabilitiesMu.RUnlock()
+
+	writeControlJSON(w, http.StatusOK, response)
+}
+
+func (g *Gateway) handleRotateSecrets(w http.ResponseWriter, r *http.Request) {
+	rotated, err := g.RotateSecrets(r.Context())
+	if err != nil {
+		log.Logf("! Failed to rotate secrets: %s", err)
+		g.emit(notify.Event{Type: notify.EventError, Message: fmt.Sprintf("Failed to rotate secrets: %s", err)})
+		writeControlError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeControlJSON(w, http.StatusOK, RotateSecretsResponse{Servers: append([]string{}, rotated...)})
+}
+
+func (g *Gateway) handleOAuthStatus(w http.ResponseWriter, _ *http.Request) {
+	g.providersMu.RLock()
+	response := OAuthStatusResponse{
+		Providers: make([]oauth.ProviderState, 0, len(g.oauthProviders)),
+	}
+	for _, provider := range g.oauthProviders {
+		response.Providers = append(response.Providers, provider.State())
+	}
+	g.providersMu.RUnlock()
+
+	slices.SortFunc(response.Providers, func(a, b oauth.ProviderState) int {
+		return strings.Compare(a.Server, b.Server)
+	})
+
+	writeControlJSON(w, http.StatusOK, response)
+}
+
+func (g *Gateway) handleOAuthRevoke(w http.ResponseWriter, r *http.Request) {
+	var req RevokeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	if req.Server == "" {
+		writeControlError(w, http.StatusBadRequest, errors.New("server is required"))
+		return
+	}
+
+	if err := g.revokeOAuthServer(r.Context(), req.Server); err != nil {
+		writeControlError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeControlJSON(w, http.StatusOK, req)
+}
+
+func (g *Gateway) handlePolicyOverride(w http.ResponseWriter, r *http.Request) {
+	var req OverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	if req.Server == "" {
+		writeControlError(w, http.StatusBadRequest, errors.New("server is required"))
+		return
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("invalid duration: %w", err))
+		return
+	}
+
+	until, err := g.grantPolicyOverride(req.Server, duration, req.Reason)
+	if err != nil {
+		writeControlError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeControlJSON(w, http.StatusOK, OverrideResponse{Server: req.Server, Until: until})
+}
+
+// startControlServer serves the control API on a unix socket until the context is done.
+func (g *Gateway) startControlServer(ctx context.Context, socketPath string) error {
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0o755); err != nil {
+		return err
+	}
+
+	// Remove a stale socket left by a gateway that didn't shut down cleanly
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing stale control socket: %w", err)
+	}
+
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on control socket %s: %w", socketPath, err)
+	}
+
+	// Only the user running the gateway can connect to the socket, and to its debug endpoints
+	if runtime.GOOS != "windows" {
+		if err := os.Chmod(socketPath, 0o600); err != nil {
+			_ = ln.Close()
+			return fmt.Errorf("restricting access to control socket %s: %w", socketPath, err)
+		}
+	}
+
+	httpServer := &http.Server{
+		Handler: http.StripPrefix(controlPathPrefix, g.controlMux(true)),
+	}
+	go func() {
+		<-ctx.Done()
+		_ = httpServer.Close()
+		_ = os.Remove(socketPath)
+	}()
+	go func() {
+		if err := httpServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Log("! Control server stopped:", err)
+		}
+	}()
+
+	log.Log("- Control API listening on", socketPath)
+	return nil
+}
+
+func writeControlJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeControlError(w http.ResponseWriter, status int, err error) {
+	writeControlJSON(w, status, controlError{Error: err.Error()})
+}
@@ -0,0 +1,230 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/notify"
+)
+
+// ControlClient talks to the control API of a running gateway,
+// either through its control socket or through its HTTP port.
+type ControlClient struct {
+	baseURL    string
+	authToken  string
+	httpClient *http.Client
+}
+
+// NewControlClient returns a client for a gateway listening on the given control socket.
+func NewControlClient(socketPath string) *ControlClient {
+	return &ControlClient{
+		baseURL: "http://gateway",
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// NewControlClientForURL returns a client for a gateway running with the sse or streaming transport.
+func NewControlClientForURL(gatewayURL, authToken string) *ControlClient {
+	return &ControlClient{
+		baseURL:    strings.TrimSuffix(gatewayURL, "/"),
+		authToken:  authToken,
+		httpClient: http.DefaultClient,
+	}
+}
+
+// ReloadServer asks the gateway to reload a single server.
+func (c *ControlClient) ReloadServer(ctx context.Context, serverName string) (ReloadResponse, error) {
+	var response ReloadResponse
+	if err := c.post(ctx, "/reload", ReloadRequest{Server: serverName}, &response); err != nil {
+		return ReloadResponse{}, err
+	}
+
+	return response, nil
+}
+
+// RotateSecrets asks the gateway to re-read the secrets of its servers and restart those whose secrets changed.
+func (c *ControlClient) RotateSecrets(ctx context.Context) (RotateSecretsResponse, error) {
+	var response RotateSecretsResponse
+	if err := c.post(ctx, "/secrets/rotate", struct{}{}, &response); err != nil {
+		return RotateSecretsResponse{}, err
+	}
+
+	return response, nil
+}
+
+// OAuthStatus returns the state of the OAuth providers running in the gateway.
+func (c *ControlClient) OAuthStatus(ctx context.Context) (OAuthStatusResponse, error) {
+	var response OAuthStatusResponse
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+controlPathPrefix+"/oauth", nil)
+	if err != nil {
+		return OAuthStatusResponse{}, err
+	}
+	if err := c.do(req, &response); err != nil {
+		return OAuthStatusResponse{}, err
+	}
+
+	return response, nil
+}
+
+// RevokeOAuth tells the gateway that the OAuth access of a server was revoked,
+// so that it stops using the server until it's authorized again.
+func (c *ControlClient) RevokeOAuth(ctx context.Context, serverName string) error {
+	var response RevokeRequest
+	return c.post(ctx, "/oauth/revoke", RevokeRequest{Server: serverName}, &response)
+}
+
+// OverridePolicy grants an on-call override to a server, outside of its access windows.
+func (c *ControlClient) OverridePolicy(ctx context.Context, serverName string, duration time.Duration, reason string) (OverrideResponse, error) {
+	var response OverrideResponse
+	if err := c.post(ctx, "/policy/override", OverrideRequest{Server: serverName, Duration: duration.String(), Reason: reason}, &response); err != nil {
+		return OverrideResponse{}, err
+	}
+
+	return response, nil
+}
+
+// Events streams the gateway's events until the context is done or the gateway stops.
+func (c *ControlClient) Events(ctx context.Context, onEvent func(notify.Event)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+controlPathPrefix+"/events", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, found := strings.CutPrefix(scanner.Text(), "data: ")
+		if !found {
+			continue
+		}
+
+		var event notify.Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("invalid event: %w", err)
+		}
+		onEvent(event)
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// MemStats returns a snapshot of the gateway's memory, after a garbage collection if asked to.
+func (c *ControlClient) MemStats(ctx context.Context, gc bool) (MemStatsResponse, error) {
+	var response MemStatsResponse
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+controlPathPrefix+"/debug/memstats?gc="+strconv.FormatBool(gc), nil)
+	if err != nil {
+		return MemStatsResponse{}, err
+	}
+	if err := c.do(req, &response); err != nil {
+		return MemStatsResponse{}, err
+	}
+
+	return response, nil
+}
+
+// HeldClients lists the clients acquired from the gateway's client pool and not released yet.
+func (c *ControlClient) HeldClients(ctx context.Context) ([]HeldClient, error) {
+	var response []HeldClient
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+controlPathPrefix+"/debug/clients", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.do(req, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Goroutines writes the stacks of all the gateway's goroutines.
+func (c *ControlClient) Goroutines(ctx context.Context, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+controlPathPrefix+"/debug/goroutines", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func (c *ControlClient) post(ctx context.Context, path string, body, out any) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+controlPathPrefix+path, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, out)
+}
+
+func (c *ControlClient) do(req *http.Request, out any) error {
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// send sends a request and turns the control API's errors into Go errors.
+func (c *ControlClient) send(req *http.Request) (*http.Response, error) {
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the gateway: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+
+		var controlErr controlError
+		if err := json.NewDecoder(resp.Body).Decode(&controlErr); err == nil && controlErr.Error != "" {
+			return nil, errors.New(controlErr.Error)
+		}
+		return nil, fmt.Errorf("gateway returned status %d", resp.StatusCode)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,108 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"strconv"
+	"time"
+)
+
+// MemStatsResponse is a snapshot of the gateway's memory, returned by GET /control/debug/memstats.
+type MemStatsResponse struct {
+	Time       time.Time `json:"time"`
+	Goroutines int       `json:"goroutines"`
+	// Client sessions and server clients kept by the gateway, which leak when they are not closed
+	Sessions    int `json:"sessions"`
+	KeptClients int `json:"keptClients"`
+	// Clients acquired from the pool and not released yet, see GET /control/debug/clients
+	HeldClients int `json:"heldClients"`
+	// Bytes, see runtime.MemStats
+	HeapAlloc    uint64 `json:"heapAlloc"`
+	HeapInuse    uint64 `json:"heapInuse"`
+	HeapIdle     uint64 `json:"heapIdle"`
+	HeapReleased uint64 `json:"heapReleased"`
+	StackInuse   uint64 `json:"stackInuse"`
+	Sys          uint64 `json:"sys"`
+	TotalAlloc   uint64 `json:"totalAlloc"`
+	NextGC       uint64 `json:"nextGC"`
+	// Counts
+	HeapObjects uint64 `json:"heapObjects"`
+	Mallocs     uint64 `json:"mallocs"`
+	Frees       uint64 `json:"frees"`
+	NumGC       uint32 `json:"numGC"`
+	// Garbage collection
+	LastGC        time.Time     `json:"lastGC"`
+	PauseTotal    time.Duration `json:"pauseTotalNs"`
+	GCCPUFraction float64       `json:"gcCPUFraction"`
+}
+
+// registerDebugHandlers serves the pprof profiles, a dump of the goroutines, a snapshot of the memory and the clients
+// held on the control API.
+func (g *Gateway) registerDebugHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("GET /debug/goroutines", handleGoroutines)
+	mux.HandleFunc("GET /debug/memstats", g.handleMemStats)
+	mux.HandleFunc("GET /debug/clients", g.handleHeldClients)
+}
+
+// handleGoroutines dumps the stacks of all the goroutines, like a panic does.
+func handleGoroutines(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_ = runtimepprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// handleMemStats returns a snapshot of the memory. With ?gc=true, a garbage collection is run first,
+// so that only the memory still in use is counted.
+func (g *Gateway) handleMemStats(w http.ResponseWriter, r *http.Request) {
+	if gc, _ := strconv.ParseBool(r.URL.Query().Get("gc")); gc {
+		runtime.GC()
+	}
+
+	writeControlJSON(w, http.StatusOK, g.memStats())
+}
+
+func (g *Gateway) memStats() MemStatsResponse {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	response := MemStatsResponse{
+		Time:          time.Now(),
+		Goroutines:    runtime.NumGoroutine(),
+		HeapAlloc:     stats.HeapAlloc,
+		HeapInuse:     stats.HeapInuse,
+		HeapIdle:      stats.HeapIdle,
+		HeapReleased:  stats.HeapReleased,
+		StackInuse:    stats.StackInuse,
+		Sys:           stats.Sys,
+		TotalAlloc:    stats.TotalAlloc,
+		NextGC:        stats.NextGC,
+		HeapObjects:   stats.HeapObjects,
+		Mallocs:       stats.Mallocs,
+		Frees:         stats.Frees,
+		NumGC:         stats.NumGC,
+		PauseTotal:    time.Duration(stats.PauseTotalNs),
+		GCCPUFraction: stats.GCCPUFraction,
+	}
+	if stats.LastGC != 0 {
+		response.LastGC = time.Unix(0, int64(stats.LastGC))
+	}
+
+	g.sessionCacheMu.RLock()
+	response.Sessions = len(g.sessionCache)
+	g.sessionCacheMu.RUnlock()
+
+	if g.clientPool != nil {
+		g.clientPool.clientLock.RLock()
+		response.KeptClients = len(g.clientPool.keptClients)
+		g.clientPool.clientLock.RUnlock()
+		response.HeldClients = g.clientPool.refs.count()
+	}
+
+	return response
+}
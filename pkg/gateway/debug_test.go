@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugEndpointsOnControlSocket(t *testing.T) {
+	g := &Gateway{
+		clientPool:   newClientPool(Options{}, nil, nil),
+		sessionCache: map[*mcp.ServerSession]*ServerSessionCache{{}: {}},
+	}
+	socketPath := filepath.Join(t.TempDir(), "gateway.sock")
+	require.NoError(t, g.startControlServer(t.Context(), socketPath))
+
+	info, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	client := NewControlClient(socketPath)
+
+	stats, err := client.MemStats(t.Context(), true)
+	require.NoError(t, err)
+	assert.Positive(t, stats.Goroutines)
+	assert.Equal(t, 1, stats.Sessions)
+	assert.Zero(t, stats.KeptClients)
+	assert.Positive(t, stats.HeapAlloc)
+	assert.Positive(t, stats.NumGC)
+	assert.False(t, stats.LastGC.IsZero())
+
+	var goroutines strings.Builder
+	require.NoError(t, client.Goroutines(t.Context(), &goroutines))
+	assert.Contains(t, goroutines.String(), "goroutine ")
+	assert.Contains(t, goroutines.String(), "TestDebugEndpointsOnControlSocket")
+}
+
+func TestDebugEndpointsOnPort(t *testing.T) {
+	get := func(g *Gateway, path string) int {
+		recorder := httptest.NewRecorder()
+		g.controlHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		return recorder.Code
+	}
+
+	// The port only serves them with --profile-startup
+	assert.Equal(t, http.StatusNotFound, get(&Gateway{}, "/debug/memstats"))
+	assert.Equal(t, http.StatusNotFound, get(&Gateway{}, "/debug/goroutines"))
+	assert.Equal(t, http.StatusNotFound, get(&Gateway{}, "/debug/pprof/heap"))
+
+	g := &Gateway{Options: Options{ProfileStartup: true}}
+	assert.Equal(t, http.StatusOK, get(g, "/debug/memstats"))
+	assert.Equal(t, http.StatusOK, get(g, "/debug/goroutines"))
+	assert.Equal(t, http.StatusOK, get(g, "/debug/pprof/heap"))
+}
+
+func TestControlAPIOnPort(t *testing.T) {
+	reload := func(g *Gateway) int {
+		mux := http.NewServeMux()
+		g.mountControlAPI(mux)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/control/reload", strings.NewReader("{}")))
+		return recorder.Code
+	}
+
+	// Without a bearer token, eg. in a container, the control API is only served on the socket
+	assert.Equal(t, http.StatusNotFound, reload(&Gateway{}))
+	assert.Equal(t, http.StatusBadRequest, reload(&Gateway{authToken: "secret"}))
+}
@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// deprecatedToolRegistrations registers the old names of the renamed tools of a server, so that
+// prompts written for an older version of the catalog keep working until the end of the grace period.
+// tools are the registrations of the server's tools, with the prefix already applied to their names.
+func deprecatedToolRegistrations(serverName, prefix string, deprecatedTools catalog.DeprecatedTools, tools []ToolRegistration, now time.Time) []ToolRegistration {
+	byName := map[string]ToolRegistration{}
+	for _, tool := range tools {
+		byName[tool.Tool.Name] = tool
+	}
+
+	var shims []ToolRegistration
+	for _, oldName := range slices.Sorted(maps.Keys(deprecatedTools)) {
+		deprecatedTool := deprecatedTools[oldName]
+
+		expired, err := deprecatedTool.Expired(now)
+		if err != nil {
+			log.Logf("  ! Can't register deprecated tool %s of %s: %v", oldName, serverName, err)
+			continue
+		}
+		if expired {
+			continue
+		}
+
+		name := prefixToolName(prefix, oldName)
+		if _, exists := byName[name]; exists {
+			// The server still exposes the old name
+			continue
+		}
+		replacement, found := byName[prefixToolName(prefix, deprecatedTool.ReplacedBy)]
+		if !found {
+			// The new tool is either unknown or disabled
+			continue
+		}
+
+		warning := deprecationWarning(name, replacement.Tool.Name, deprecatedTool.Until)
+
+		tool := *replacement.Tool
+		tool.Name = name
+		tool.Description = warning + " " + replacement.Tool.Description
+
+		shims = append(shims, ToolRegistration{
+			ServerName: replacement.ServerName,
+			Tool:       &tool,
+			Handler:    deprecatedToolHandler(replacement.Handler, warning),
+			Cost:       replacement.Cost,
+			ToolName:   replacement.ToolName,
+		})
+	}
+
+	return shims
+}
+
+func deprecationWarning(oldName, newName, until string) string {
+	if until == "" {
+		return fmt.Sprintf("Deprecated: %s was renamed %s.", oldName, newName)
+	}
+	return fmt.Sprintf("Deprecated: %s was renamed %s and will be removed after %s.", oldName, newName, until)
+}
+
+// deprecatedToolHandler forwards the calls to the old name of a tool to the handler of the new one,
+// and prepends a deprecation warning to the result.
+func deprecatedToolHandler(handler mcp.ToolHandler, warning string) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		log.Log("  ! " + warning)
+
+		result, err := handler(ctx, req)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		annotated := *result
+		annotated.Content = append([]mcp.Content{&mcp.TextContent{Text: warning}}, result.Content...)
+		return &annotated, nil
+	}
+}
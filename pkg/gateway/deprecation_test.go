@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestDeprecatedToolRegistrations(t *testing.T) {
+	var called string
+	tools := []ToolRegistration{
+		{
+			ServerName: "github",
+			Tool:       &mcp.Tool{Name: "github:search", Description: "Search issues"},
+			Handler: func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				called = req.Params.Name
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "found"}}}, nil
+			},
+			Cost:     2,
+			ToolName: "search",
+		},
+		{ServerName: "github", Tool: &mcp.Tool{Name: "github:list_issues"}},
+	}
+	deprecatedTools := catalog.DeprecatedTools{
+		"search_issues": {ReplacedBy: "search", Until: "2026-06-30"},
+		"find_issues":   {ReplacedBy: "search", Until: "2026-01-31"},
+		"list_issues":   {ReplacedBy: "search"},
+		"get_issue":     {ReplacedBy: "read_issue"},
+	}
+	now := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	shims := deprecatedToolRegistrations("github", "github", deprecatedTools, tools, now)
+
+	// find_issues is expired, list_issues is still exposed by the server and read_issue doesn't exist
+	require.Len(t, shims, 1)
+	shim := shims[0]
+	assert.Equal(t, "github", shim.ServerName)
+	assert.Equal(t, "github:search_issues", shim.Tool.Name)
+	assert.Equal(t, "Deprecated: github:search_issues was renamed github:search and will be removed after 2026-06-30. Search issues", shim.Tool.Description)
+	assert.InDelta(t, 2, shim.Cost, 0)
+	assert.Equal(t, "search", shim.ToolName)
+
+	result, err := shim.Handler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "github:search_issues"}})
+	require.NoError(t, err)
+	assert.Equal(t, "github:search_issues", called)
+	require.Len(t, result.Content, 2)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "Deprecated")
+	assert.Equal(t, "found", result.Content[1].(*mcp.TextContent).Text)
+}
@@ -0,0 +1,271 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// DefaultDevInterval is how often dev mode checks the project, or the image, for changes.
+const DefaultDevInterval = time.Second
+
+// DevOptions describe the server under development.
+type DevOptions struct {
+	// Path of the project, rebuilt with docker build when one of its files changes.
+	Path string
+	// Image built from the project (mcp-dev/<name>:latest by default), or the image watched for new builds without a project.
+	Image string
+	// Name of the server. Defaults to the name in the image's metadata label, or the name of the project's directory.
+	Name     string
+	Interval time.Duration
+}
+
+// devServer is the state of the server under development.
+type devServer struct {
+	DevOptions
+	docker      docker.Client
+	catalogPath string
+	imageID     string
+	definition  []byte
+}
+
+// Dev runs a gateway with a single server under development. When the project is rebuilt,
+// or when the image is retagged, the server is restarted and its capabilities are swapped
+// in the running gateway, which notifies the clients that the lists of tools, prompts and resources changed.
+func Dev(ctx context.Context, dockerClient docker.Client, config Config, dev DevOptions) error {
+	if dev.Interval <= 0 {
+		dev.Interval = DefaultDevInterval
+	}
+	if dev.Path != "" {
+		path, err := filepath.Abs(dev.Path)
+		if err != nil {
+			return err
+		}
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", dev.Path)
+		}
+		dev.Path = path
+		if dev.Image == "" {
+			name := dev.Name
+			if name == "" {
+				name = devServerName(filepath.Base(path))
+			}
+			dev.Image = "mcp-dev/" + name + ":latest"
+		}
+	}
+	if dev.Image == "" {
+		return fmt.Errorf("a project path or an image is required")
+	}
+
+	dir, err := os.MkdirTemp("", "docker-mcp-dev")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	server := &devServer{
+		DevOptions:  dev,
+		docker:      dockerClient,
+		catalogPath: filepath.Join(dir, "catalog.yaml"),
+	}
+	if dev.Path != "" {
+		if err := server.build(ctx); err != nil {
+			return err
+		}
+	}
+	if _, err := server.update(ctx); err != nil {
+		return err
+	}
+
+	// The gateway only knows about the server under development. It's restarted by the dev loop, not by watching the configuration.
+	config.CatalogPath = []string{server.catalogPath}
+	config.ServerNames = []string{server.Name}
+	config.RegistryPath = nil
+	config.Watch = false
+	g := NewGateway(config, dockerClient)
+
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go server.watch(runCtx, g)
+
+	return g.Run(runCtx)
+}
+
+// watch rebuilds the project when its files stop changing, and reloads the server when its image changes.
+func (s *devServer) watch(ctx context.Context, g *Gateway) {
+	if s.Path != "" {
+		log.Log("- Watching", s.Path, "for changes to", s.Name)
+	} else {
+		log.Log("- Watching", s.Image, "for new builds of", s.Name)
+	}
+
+	var built, previous uint64
+	if s.Path != "" {
+		built, _ = projectFingerprint(s.Path)
+		previous = built
+	}
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if s.Path != "" {
+			fingerprint, err := projectFingerprint(s.Path)
+			if err != nil {
+				log.Log("  ! Reading", s.Path+":", err)
+				continue
+			}
+			// Wait for the files to stop changing before rebuilding
+			stable := fingerprint == previous
+			previous = fingerprint
+			if fingerprint == built || !stable {
+				continue
+			}
+			built = fingerprint
+
+			if err := s.build(ctx); err != nil {
+				log.Log("  !", err)
+				continue
+			}
+		}
+
+		changed, err := s.update(ctx)
+		if err != nil {
+			log.Log("  !", err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		unlock := g.lockServer(s.Name)
+		if err := g.ReloadServer(ctx, s.Name); err != nil {
+			log.Logf("  ! Failed to reload server %s: %s", s.Name, err)
+		}
+		unlock()
+	}
+}
+
+func (s *devServer) build(ctx context.Context) error {
+	log.Log("- Building", s.Image, "from", s.Path)
+	start := time.Now()
+
+	out, err := exec.CommandContext(ctx, "docker", "build", "--quiet", "--tag", s.Image, s.Path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("building %s: %w\n%s", s.Path, err, strings.TrimSpace(string(out)))
+	}
+
+	log.Log("> Built", s.Image, "in", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// update writes the catalog of the server when its image changed. The definition of the server comes from
+// the image's io.docker.server.metadata label, if it has one.
+func (s *devServer) update(ctx context.Context) (bool, error) {
+	inspect, err := s.docker.InspectImage(ctx, s.Image)
+	if err != nil {
+		return false, fmt.Errorf("inspecting image %s: %w", s.Image, err)
+	}
+	if inspect.ID == s.imageID {
+		return false, nil
+	}
+	s.imageID = inspect.ID
+
+	var server catalog.Server
+	if inspect.Config != nil && inspect.Config.Labels["io.docker.server.metadata"] != "" {
+		label := inspect.Config.Labels["io.docker.server.metadata"]
+		if err := yaml.Unmarshal([]byte(label), &server); err != nil {
+			return false, fmt.Errorf("parsing the metadata label of %s: %w", s.Image, err)
+		}
+	}
+	if s.Name == "" {
+		switch {
+		case server.Name != "":
+			s.Name = devServerName(server.Name)
+		case s.Path != "":
+			s.Name = devServerName(filepath.Base(s.Path))
+		default:
+			s.Name = devServerName(s.Image)
+		}
+	}
+	server.Name = s.Name
+	server.Type = "server"
+	server.Image = s.Image
+	// The image is local, never pull it
+	server.PullPolicy = PullPolicyNever
+	if server.Description == "" {
+		server.Description = "Server under development, running " + s.Image
+	}
+
+	definition, err := yaml.Marshal(map[string]any{
+		"name":     "dev",
+		"registry": map[string]catalog.Server{s.Name: server},
+	})
+	if err != nil {
+		return false, err
+	}
+	if string(definition) != string(s.definition) {
+		if err := os.WriteFile(s.catalogPath, definition, 0o644); err != nil {
+			return false, err
+		}
+		s.definition = definition
+	}
+
+	return true, nil
+}
+
+var unsafeDevNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// devServerName turns the name of a directory or an image into a server name.
+func devServerName(name string) string {
+	name = strings.ToLower(name)
+	// Drop the registry, the repository and the tag of an image
+	name = name[strings.LastIndex(name, "/")+1:]
+	name, _, _ = strings.Cut(name, ":")
+	name = strings.Trim(unsafeDevNameChars.ReplaceAllString(name, "-"), "-")
+	if name == "" {
+		return "dev"
+	}
+	return name
+}
+
+// projectFingerprint changes when a file of the project is added, removed or modified.
+// Hidden directories, like .git, and node_modules are skipped.
+func projectFingerprint(root string) (uint64, error) {
+	hash := fnv.New64a()
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && (strings.HasPrefix(entry.Name(), ".") || entry.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	return hash.Sum64(), err
+}
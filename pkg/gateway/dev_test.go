@@ -0,0 +1,85 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/image"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestDevServerName(t *testing.T) {
+	assert.Equal(t, "my-server", devServerName("My Server"))
+	assert.Equal(t, "weather", devServerName("docker.io/acme/weather:dev"))
+	assert.Equal(t, "weather", devServerName("localhost:5000/weather"))
+	assert.Equal(t, "dev", devServerName("..."))
+}
+
+func TestProjectFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o755))
+
+	before, err := projectFingerprint(dir)
+	require.NoError(t, err)
+
+	// Hidden directories are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "index"), []byte("index"), 0o644))
+	unchanged, err := projectFingerprint(dir)
+	require.NoError(t, err)
+	assert.Equal(t, before, unchanged)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tools.go"), []byte("package main"), 0o644))
+	after, err := projectFingerprint(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+}
+
+func TestDevServerUpdate(t *testing.T) {
+	fake := &fakePlatforms{images: map[string]image.InspectResponse{
+		"acme/weather:dev": {
+			ID: "sha256:1",
+			Config: &dockerspec.DockerOCIImageConfig{ImageConfig: ocispec.ImageConfig{Labels: map[string]string{
+				"io.docker.server.metadata": "name: weather\ndescription: Weather forecasts\nsecrets:\n  - name: weather.api_key\n    env: API_KEY\n",
+			}}},
+		},
+	}}
+	server := &devServer{
+		DevOptions:  DevOptions{Image: "acme/weather:dev", Interval: time.Second},
+		docker:      fake,
+		catalogPath: filepath.Join(t.TempDir(), "catalog.yaml"),
+	}
+
+	changed, err := server.update(t.Context())
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "weather", server.Name)
+
+	servers, err := catalog.ReadFrom(t.Context(), []string{server.catalogPath})
+	require.NoError(t, err)
+	weather := servers.Servers["weather"]
+	assert.Equal(t, "acme/weather:dev", weather.Image)
+	assert.Equal(t, "Weather forecasts", weather.Description)
+	assert.Equal(t, PullPolicyNever, weather.PullPolicy)
+	assert.Equal(t, []catalog.Secret{{Name: "weather.api_key", Env: "API_KEY"}}, weather.Secrets)
+
+	// Same image, nothing to reload
+	changed, err = server.update(t.Context())
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Rebuilt image
+	rebuilt := fake.images["acme/weather:dev"]
+	rebuilt.ID = "sha256:2"
+	fake.images["acme/weather:dev"] = rebuilt
+	changed, err = server.update(t.Context())
+	require.NoError(t, err)
+	assert.True(t, changed)
+}
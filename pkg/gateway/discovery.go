@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// discoveryPath is where HTTP transports describe how to connect to the gateway.
+const discoveryPath = "/.well-known/mcp-gateway"
+
+// gatewayDiscovery summarizes the gateway for orchestration systems and client installers
+// that need to know how to connect before speaking MCP.
+type gatewayDiscovery struct {
+	Name      string             `json:"name"`
+	Version   string             `json:"version"`
+	Transport string             `json:"transport"`
+	Endpoints discoveryEndpoints `json:"endpoints"`
+	Auth      discoveryAuth      `json:"auth"`
+	Servers   int                `json:"servers"`
+	Tools     int                `json:"tools"`
+}
+
+type discoveryEndpoints struct {
+	MCP    string `json:"mcp"`
+	Health string `json:"health"`
+}
+
+type discoveryAuth struct {
+	// Type is oauth when clients can authenticate with the access tokens of an authorization server,
+	// bearer when they must send the gateway's token in an Authorization header, none otherwise.
+	Type string `json:"type"`
+	// ResourceMetadata is the URL of the OAuth protected resource metadata.
+	ResourceMetadata string `json:"resourceMetadata,omitempty"`
+	// OAuthServers is the number of enabled servers that need to be authorized with OAuth.
+	OAuthServers int `json:"oauthServers"`
+}
+
+// discoveryHandler serves the discovery document. It doesn't require authentication.
+func (g *Gateway) discoveryHandler(transport, endpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(g.discovery(transport, endpoint))
+	}
+}
+
+func (g *Gateway) discovery(transport, endpoint string) gatewayDiscovery {
+	auth := discoveryAuth{Type: "none"}
+	switch {
+	case g.resourceAuth != nil:
+		auth.Type = "oauth"
+		auth.ResourceMetadata = g.resourceAuth.metadataURL
+	case g.authToken != "":
+		auth.Type = "bearer"
+	}
+
+	serverNames := g.configuration.ServerNames()
+	for _, serverName := range serverNames {
+		serverConfig, _, found := g.configuration.Find(serverName)
+		if found && serverConfig != nil && serverConfig.Spec.IsOAuthServer() {
+			auth.OAuthServers++
+		}
+	}
+
+	g.capabilitiesMu.RLock()
+	defer g.capabilitiesMu.RUnlock()
+
+	return gatewayDiscovery{
+		Name:      gatewayName,
+		Version:   gatewayVersion,
+		Transport: transport,
+		Endpoints: discoveryEndpoints{
+			MCP:    endpoint,
+			Health: "/health",
+		},
+		Auth:    auth,
+		Servers: len(serverNames),
+		Tools:   len(g.toolRegistrations),
+	}
+}
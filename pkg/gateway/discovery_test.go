@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestDiscoveryHandler(t *testing.T) {
+	g := &Gateway{
+		authToken: "secret",
+		configuration: Configuration{
+			serverNames: []string{"fetch", "notion"},
+			servers: map[string]catalog.Server{
+				"fetch":  {Image: "mcp/fetch"},
+				"notion": {Image: "mcp/notion", OAuth: &catalog.OAuth{Providers: []catalog.OAuthProvider{{Provider: "notion"}}}},
+			},
+		},
+		toolRegistrations: map[string]ToolRegistration{
+			"fetch":  {ServerName: "fetch"},
+			"search": {ServerName: "notion"},
+		},
+	}
+
+	// The discovery document is readable without the bearer token
+	handler := authenticationMiddleware(g.authToken, g.discoveryHandler("streamable-http", "/mcp"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, discoveryPath, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var discovery gatewayDiscovery
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &discovery))
+	assert.Equal(t, gatewayDiscovery{
+		Name:      gatewayName,
+		Version:   gatewayVersion,
+		Transport: "streamable-http",
+		Endpoints: discoveryEndpoints{MCP: "/mcp", Health: "/health"},
+		Auth:      discoveryAuth{Type: "bearer", OAuthServers: 1},
+		Servers:   2,
+		Tools:     2,
+	}, discovery)
+}
+
+func TestDiscoveryHandlerMethodNotAllowed(t *testing.T) {
+	g := &Gateway{}
+
+	w := httptest.NewRecorder()
+	g.discoveryHandler("sse", "/sse").ServeHTTP(w, httptest.NewRequest(http.MethodPost, discoveryPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
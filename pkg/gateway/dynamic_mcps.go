@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"os"
 	"slices"
@@ -19,8 +20,13 @@ import (
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/codemode"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/httpclient"
+	"github.com/docker/mcp-gateway/pkg/interceptors"
 	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/notify"
 	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/registryapi"
 	"github.com/docker/mcp-gateway/pkg/telemetry"
 )
 
@@ -28,7 +34,7 @@ import (
 func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistration {
 	tool := &mcp.Tool{
 		Name:        "mcp-find",
-		Description: "Find MCP servers in the current catalog by name, title, or description. Returns matching servers with their details.",
+		Description: "Find MCP servers in the current catalog by name, title, or description. Returns matching servers with their details, including whether they are ready to use without further configuration. When there are more matches, pass the next_cursor of the response to get the next page.",
 		InputSchema: &jsonschema.Schema{
 			Type: "object",
 			Properties: map[string]*jsonschema.Schema{
@@ -40,16 +46,20 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 					Type:        "integer",
 					Description: "Maximum number of results to return (default: 10)",
 				},
+				"cursor": {
+					Type:        "string",
+					Description: "The next_cursor of a previous response, to get the next page of its results. The query can then be omitted",
+				},
 			},
-			Required: []string{"query"},
 		},
 	}
 
-	handler := func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Parse parameters
 		var params struct {
-			Query string `json:"query"`
-			Limit int    `json:"limit"`
+			Query  string `json:"query"`
+			Limit  int    `json:"limit"`
+			Cursor string `json:"cursor"`
 		}
 
 		if req.Params.Arguments == nil {
@@ -65,6 +75,20 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 			return nil, fmt.Errorf("failed to parse arguments: %w", err)
 		}
 
+		offset := 0
+		if params.Cursor != "" {
+			cursor, err := decodeFindCursor(params.Cursor)
+			if err != nil {
+				return nil, err
+			}
+			if params.Query == "" {
+				params.Query = cursor.Query
+			} else if params.Query != cursor.Query {
+				return nil, fmt.Errorf("the cursor is for the query %q, not %q", cursor.Query, params.Query)
+			}
+			offset = cursor.Offset
+		}
+
 		if params.Query == "" {
 			return nil, fmt.Errorf("query parameter is required")
 		}
@@ -77,7 +101,7 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 		query := strings.ToLower(strings.TrimSpace(params.Query))
 		var matches []ServerMatch
 
-		for serverName, server := range configuration.servers {
+		for serverName, server := range g.withPopularity(configuration.servers) {
 			match := false
 			score := 0
 
@@ -150,18 +174,27 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 			}
 		}
 
-		// Sort matches by score (higher scores first)
-		for i := range len(matches) - 1 {
-			for j := i + 1; j < len(matches); j++ {
-				if matches[i].Score < matches[j].Score {
-					matches[i], matches[j] = matches[j], matches[i]
-				}
-			}
-		}
+		// Sort matches by score (higher scores first), then by popularity, then by name for pages to be stable
+		sortServerMatches(matches)
+
+		totalMatches := len(matches)
+		matches, nextOffset := paginateMatches(matches, offset, params.Limit)
 
-		// Limit results
-		if len(matches) > params.Limit {
-			matches = matches[:params.Limit]
+		// Secrets are only read for the enabled servers, read the ones of the matches too
+		secrets := configuration.secrets
+		if fbc, ok := g.configurator.(*FileBasedConfiguration); ok && len(matches) > 0 {
+			var matchNames []string
+			for _, match := range matches {
+				matchNames = append(matchNames, match.Name)
+			}
+			matchSecrets, err := fbc.readDockerDesktopSecrets(ctx, configuration.servers, matchNames)
+			if err != nil {
+				log.Log("Warning: Failed to read secrets:", err)
+			} else {
+				secrets = make(map[string]string, len(configuration.secrets)+len(matchSecrets))
+				maps.Copy(secrets, configuration.secrets)
+				maps.Copy(secrets, matchSecrets)
+			}
 		}
 
 		// Format results
@@ -187,16 +220,35 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 				serverInfo["config_schema"] = match.Server.Config
 			}
 
+			if metadata := match.Server.Metadata; metadata != nil {
+				if metadata.Pulls > 0 {
+					serverInfo["pulls"] = metadata.Pulls
+				}
+				if metadata.GithubStars > 0 {
+					serverInfo["github_stars"] = metadata.GithubStars
+				}
+			}
+
 			serverInfo["long_lived"] = match.Server.LongLived
+			serverInfo["readiness"] = g.serverReadiness(ctx, match.Name, match.Server, secrets, configuration.config)
 
 			results = append(results, serverInfo)
 		}
 
 		response := map[string]any{
 			"query":         params.Query,
-			"total_matches": len(results),
+			"total_matches": totalMatches,
 			"servers":       results,
 		}
+		if nextOffset >= 0 {
+			response["next_cursor"] = encodeFindCursor(params.Query, nextOffset)
+		}
+		// Servers found on the local network aren't in the catalog, they can't be added with mcp-add
+		if offset == 0 {
+			if discovered := g.findLANServers(query); len(discovered) > 0 {
+				response["discovered_servers"] = discovered
+			}
+		}
 
 		responseBytes, err := json.Marshal(response)
 		if err != nil {
@@ -221,6 +273,22 @@ type ServerMatch struct {
 	Score  int
 }
 
+// sortServerMatches sorts the matches by score, then by popularity, then by name.
+func sortServerMatches(matches []ServerMatch) {
+	slices.SortFunc(matches, func(a, b ServerMatch) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		if popularityA, popularityB := a.Server.PopularityScore(), b.Server.PopularityScore(); popularityA != popularityB {
+			if popularityA > popularityB {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
 func (g *Gateway) createCodeModeTool(_ *clientConfig) *ToolRegistration {
 	tool := &mcp.Tool{
 		Name: "code-mode",
@@ -284,6 +352,9 @@ mcp-add, we don't have to activate the tools.
 					}},
 				}, nil
 			}
+			if result := g.checkClientServer(req, serverName); result != nil {
+				return result, nil
+			}
 		}
 
 		// Create a tool set adapter for each server
@@ -400,14 +471,14 @@ func (a *serverToolSetAdapter) Tools(ctx context.Context) ([]*codemode.ToolWithH
 	}
 
 	// List tools from the server
-	listResult, err := client.Session().ListTools(ctx, &mcp.ListToolsParams{})
+	tools, err := listAllTools(ctx, client.Session())
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tools from server %s: %w", a.serverName, err)
 	}
 
 	// Convert MCP tools to ToolWithHandler
 	var result []*codemode.ToolWithHandler
-	for _, tool := range listResult.Tools {
+	for _, tool := range tools {
 		// Create a handler that calls the tool on the remote server
 		handler := func(tool *mcp.Tool) mcp.ToolHandler {
 			return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -470,13 +541,22 @@ func (g *Gateway) createMcpRemoveTool() *ToolRegistration {
 
 		serverName := strings.TrimSpace(params.Name)
 
-		// Remove the server from the current serverNames
-		updatedServerNames := slices.DeleteFunc(slices.Clone(g.configuration.serverNames), func(name string) bool {
-			return name == serverName
-		})
+		if result := g.checkClientServer(req, serverName); result != nil {
+			return result, nil
+		}
+
+		// Concurrent calls for the same server wait for each other
+		unlock := g.lockServer(serverName)
+		defer unlock()
 
-		// Update the current configuration state
-		g.configuration.serverNames = updatedServerNames
+		// Remove the server from the current serverNames
+		if !g.disableServerName(serverName) {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Server '%s' is not enabled.", serverName),
+				}},
+			}, nil
+		}
 
 		// Stop OAuth provider if this is an OAuth server
 		if g.McpOAuthDcrEnabled {
@@ -487,10 +567,17 @@ func (g *Gateway) createMcpRemoveTool() *ToolRegistration {
 			return nil, fmt.Errorf("failed to remove server configuration: %w", err)
 		}
 
+		g.emit(notify.Event{
+			Type:    notify.EventServerRemoved,
+			Server:  serverName,
+			Message: fmt.Sprintf("Server %s removed", serverName),
+		})
+
 		// Persist configuration if session name is set
 		if err := g.configuration.Persist(); err != nil {
 			log.Log("Warning: Failed to persist configuration:", err)
 		}
+		g.persistProfile(ctx, serverName, false)
 
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{
@@ -509,13 +596,13 @@ func (g *Gateway) createMcpRemoveTool() *ToolRegistration {
 func (g *Gateway) createMcpRegistryImportTool(configuration Configuration, _ *clientConfig) *ToolRegistration {
 	tool := &mcp.Tool{
 		Name:        "mcp-registry-import",
-		Description: "Import MCP servers from an MCP registry URL. Fetches server definitions via HTTP GET and adds them to the local catalog.",
+		Description: "Import MCP servers from an MCP registry, by URL or by name. Fetches server definitions via HTTP GET and adds them to the local catalog.",
 		InputSchema: &jsonschema.Schema{
 			Type: "object",
 			Properties: map[string]*jsonschema.Schema{
 				"url": {
 					Type:        "string",
-					Description: "URL to fetch the server details JSON (must be a valid HTTP/HTTPS URL)",
+					Description: "URL to fetch the server details JSON (must be a valid HTTP/HTTPS URL), or the name of a server in the default MCP registry, optionally with a version (e.g. com.example/weather or com.example/weather@1.2.0)",
 				},
 			},
 			Required: []string{"url"},
@@ -547,21 +634,17 @@ func (g *Gateway) createMcpRegistryImportTool(configuration Configuration, _ *cl
 
 		registryURL := strings.TrimSpace(params.URL)
 
-		// Validate URL scheme
-		if !strings.HasPrefix(registryURL, "http://") && !strings.HasPrefix(registryURL, "https://") {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{&mcp.TextContent{
-					Text: fmt.Sprintf("Error: URL must start with http:// or https://, got: %s", registryURL),
-				}},
-			}, nil
+		// Fetch servers from the URL, or from the default registry
+		var servers map[string]catalog.Server
+		if registryapi.IsServerURL(registryURL) {
+			servers, err = g.readServersFromURL(ctx, registryURL)
+		} else {
+			servers, err = g.readServersFromRegistry(ctx, registryURL)
 		}
-
-		// Fetch servers from the URL
-		servers, err := g.readServersFromURL(ctx, registryURL)
 		if err != nil {
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{&mcp.TextContent{
-					Text: fmt.Sprintf("Error fetching servers from URL %s: %v", registryURL, err),
+					Text: fmt.Sprintf("Error fetching servers from %s: %v", registryURL, err),
 				}},
 			}, nil
 		}
@@ -574,6 +657,12 @@ func (g *Gateway) createMcpRegistryImportTool(configuration Configuration, _ *cl
 			}, nil
 		}
 
+		for serverName := range servers {
+			if result := g.checkClientServer(req, serverName); result != nil {
+				return result, nil
+			}
+		}
+
 		// Add the imported servers to the current configuration and build detailed summary
 		var importedServerNames []string
 		var serverSummaries []string
@@ -658,7 +747,7 @@ func (g *Gateway) readServersFromURL(ctx context.Context, url string) (map[strin
 	req.Header.Set("User-Agent", "docker-mcp-gateway/1.0.0")
 
 	// Make the HTTP request
-	client := &http.Client{}
+	client := httpclient.Client(0)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch URL: %w", err)
@@ -690,6 +779,31 @@ func (g *Gateway) readServersFromURL(ctx context.Context, url string) (map[strin
 	return nil, fmt.Errorf("unable to parse response as OCI catalog or direct catalog format")
 }
 
+// readServersFromRegistry fetches a server by name, eg. com.example/weather@1.2.0, from the default MCP registry
+//
+//nolint:unused
+func (g *Gateway) readServersFromRegistry(ctx context.Context, name string) (map[string]catalog.Server, error) {
+	serverURL, err := registryapi.ParseServerReference(ctx, name, db.LazySettings{})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Log(fmt.Sprintf("  - Reading server %s from URL: %s", name, serverURL.String()))
+
+	response, err := registryapi.NewClient().GetServer(ctx, serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch server: %w", err)
+	}
+	if response.Server.Name == "" {
+		return nil, fmt.Errorf("server %s not found", name)
+	}
+
+	serverDetail := oci.ServerDetailFromRegistry(response.Server)
+	return map[string]catalog.Server{
+		serverDetail.Name: serverDetail.ToCatalogServer(),
+	}, nil
+}
+
 type configValue struct {
 	Server string `json:"server"`
 	Key    string `json:"key"`
@@ -780,6 +894,10 @@ func (g *Gateway) createMcpConfigSetTool(_ *clientConfig) *ToolRegistration {
 		serverName := strings.TrimSpace(params.Server)
 		configKey := strings.TrimSpace(params.Key)
 
+		if result := g.checkClientServer(req, serverName); result != nil {
+			return result, nil
+		}
+
 		// Decode JSON-encoded values (e.g., arrays passed as strings)
 		finalValue := params.Value
 		if strValue, ok := params.Value.(string); ok {
@@ -840,6 +958,181 @@ func (g *Gateway) createMcpConfigSetTool(_ *clientConfig) *ToolRegistration {
 	}
 }
 
+// secretValue is the argument of the mcp-secret-set tool.
+type secretValue struct {
+	Server string `json:"server"`
+	Secret string `json:"secret"`
+	Value  string `json:"value"`
+}
+
+func textResult(text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}
+}
+
+// createMcpSecretSetTool implements a tool for setting the missing secrets of a server,
+// asking the user for their value with elicitation when the client supports it.
+func (g *Gateway) createMcpSecretSetTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "mcp-secret-set",
+		Description: "Set a secret of an MCP server, like an API key, and reload the server. Leave the value out to let the user type it, when the client supports it, so that the secret doesn't go through the conversation.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"server": {
+					Type:        "string",
+					Description: "Name of the MCP server whose secret to set",
+				},
+				"secret": {
+					Type:        "string",
+					Description: "Name of the secret, as listed by mcp-add when it's missing",
+				},
+				"value": {
+					Type:        "string",
+					Description: "Value of the secret. Optional, the user is asked for it if left out",
+				},
+			},
+			Required: []string{"server", "secret"},
+		},
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var params secretValue
+		if req.Params.Arguments == nil {
+			return nil, fmt.Errorf("missing arguments")
+		}
+		paramsBytes, err := json.Marshal(req.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal arguments: %w", err)
+		}
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		serverName := strings.TrimSpace(params.Server)
+		secretName := strings.TrimSpace(params.Secret)
+		if serverName == "" {
+			return nil, fmt.Errorf("server parameter is required")
+		}
+		if secretName == "" {
+			return nil, fmt.Errorf("secret parameter is required")
+		}
+
+		serverConfig, _, found := g.configuration.Find(serverName)
+		if !found || serverConfig == nil {
+			return textResult(fmt.Sprintf("Error: Server '%s' not found in catalog.", serverName)), nil
+		}
+
+		// Clients restricted to a profile can only set the secrets of its servers
+		if result := g.checkClientServer(req, serverName); result != nil {
+			return result, nil
+		}
+
+		var secretNames []string
+		for _, secret := range serverConfig.Spec.Secrets {
+			secretNames = append(secretNames, secret.Name)
+		}
+		if !slices.Contains(secretNames, secretName) {
+			if len(secretNames) == 0 {
+				return textResult(fmt.Sprintf("Error: Server '%s' has no secrets.", serverName)), nil
+			}
+			return textResult(fmt.Sprintf("Error: Server '%s' has no secret '%s'. Its secrets are: %s.", serverName, secretName, strings.Join(secretNames, ", "))), nil
+		}
+
+		canElicit := req.Session != nil && req.Session.InitializeParams() != nil && req.Session.InitializeParams().Capabilities != nil && req.Session.InitializeParams().Capabilities.Elicitation != nil
+		alreadySet := g.secret(secretName) != ""
+
+		value := params.Value
+		if value != "" && alreadySet {
+			// The tool is meant to set missing secrets, only the user can replace one
+			if !canElicit {
+				return textResult(fmt.Sprintf("Error: The secret '%s' is already set and the client can't ask the user to confirm replacing it. Ask the user to run:\n  docker mcp secret set %s=<value>", secretName, secretName)), nil
+			}
+
+			elicitResult, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+				Message: fmt.Sprintf("The secret '%s' of the '%s' server is already set. Do you want to replace it with the value provided by the assistant?", secretName, serverName),
+				RequestedSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"confirm": {
+							Type:        "boolean",
+							Description: "Replace the secret",
+						},
+					},
+					Required: []string{"confirm"},
+				},
+			})
+			if err != nil {
+				return textResult(fmt.Sprintf("Error: Failed to ask the user to confirm replacing '%s': %v", secretName, err)), nil
+			}
+			if confirm, _ := elicitResult.Content["confirm"].(bool); elicitResult.Action != "accept" || !confirm {
+				return textResult(fmt.Sprintf("The user didn't confirm replacing '%s', it was not changed.", secretName)), nil
+			}
+		}
+		if value == "" {
+			if !canElicit {
+				return textResult(fmt.Sprintf("Error: The client can't ask the user for the value of '%s'. Pass it as the value argument, or ask the user to run:\n  docker mcp secret set %s=<value>", secretName, secretName)), nil
+			}
+
+			message := fmt.Sprintf("The '%s' server needs the secret '%s'. Enter its value:", serverName, secretName)
+			if alreadySet {
+				message = fmt.Sprintf("The secret '%s' of the '%s' server is already set. Enter a new value to replace it:", secretName, serverName)
+			}
+			elicitResult, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+				Message: message,
+				RequestedSchema: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"value": {
+							Type:        "string",
+							Description: fmt.Sprintf("Value of %s", secretName),
+						},
+					},
+					Required: []string{"value"},
+				},
+			})
+			if err != nil {
+				return textResult(fmt.Sprintf("Error: Failed to ask the user for the value of '%s': %v", secretName, err)), nil
+			}
+			if elicitResult.Action != "accept" || elicitResult.Content == nil {
+				return textResult(fmt.Sprintf("The user didn't enter the value of '%s', it was not set.", secretName)), nil
+			}
+			value, _ = elicitResult.Content["value"].(string)
+			if value == "" {
+				return textResult(fmt.Sprintf("The user entered an empty value for '%s', it was not set.", secretName)), nil
+			}
+		}
+
+		// Don't reload the server while it's being added or removed
+		unlock := g.lockServer(serverName)
+		defer unlock()
+
+		if err := g.configurator.SetSecret(ctx, serverName, secretName, value); err != nil {
+			return textResult(fmt.Sprintf("Error: Failed to store the secret '%s': %v", secretName, err)), nil
+		}
+		log.Log(fmt.Sprintf("  - Set secret '%s' of server '%s'", secretName, serverName))
+
+		// Servers that aren't enabled yet get the secret when they're added
+		g.setSecret(secretName, value)
+
+		if !slices.Contains(g.configuration.ServerNames(), serverName) {
+			return textResult(fmt.Sprintf("Successfully set secret '%s' of server '%s'. Add the server with mcp-add to use it.", secretName, serverName)), nil
+		}
+
+		if err := g.ReloadServer(ctx, serverName); err != nil {
+			return textResult(fmt.Sprintf("Set secret '%s', but failed to reload server '%s': %v", secretName, serverName, err)), nil
+		}
+
+		return textResult(fmt.Sprintf("Successfully set secret '%s' of server '%s' and reloaded the server.", secretName, serverName)), nil
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("mcp-secret-set", handler),
+	}
+}
+
 // createMcpSessionNameTool implements a tool for setting the session name
 //
 //nolint:unused
@@ -1014,8 +1307,18 @@ func (g *Gateway) createMcpExecTool() *ToolRegistration {
 			Extra: req.Extra,
 		}
 
+		// The call doesn't go through the middlewares, check the policy and charge the session's budget here
+		if result := g.checkToolPolicy(toolName, requestIdentity(req.Extra)); result != nil {
+			return result, nil
+		}
+		cost, rejected := g.chargeToolCall(req.Session, toolName)
+		if rejected != nil {
+			return rejected, nil
+		}
+
 		// Execute the tool using its registered handler
 		result, err := toolReg.Handler(ctx, toolCallRequest)
+		g.settleToolCall(ctx, req.Session, toolName, cost, err != nil || (result != nil && result.IsError))
 		if err != nil {
 			return nil, fmt.Errorf("tool execution failed: %w", err)
 		}
@@ -1077,6 +1380,10 @@ func withToolTelemetry(toolName string, handler mcp.ToolHandler) mcp.ToolHandler
 			attribute.String("mcp.server.type", serverType),
 		}
 
+		if correlationID := interceptors.CorrelationID(ctx); correlationID != "" {
+			spanAttrs = append(spanAttrs, attribute.String("mcp.correlation_id", correlationID))
+		}
+
 		ctx, span := telemetry.StartToolCallSpan(ctx, toolName, spanAttrs...)
 		defer span.End()
 
@@ -1108,10 +1415,10 @@ func withToolTelemetry(toolName string, handler mcp.ToolHandler) mcp.ToolHandler
 			// Record error in telemetry
 			telemetry.RecordToolError(ctx, span, serverName, serverType, toolName)
 			span.SetStatus(codes.Error, "Tool execution failed")
-			return nil, err
+			return toolErrorResult("", toolName, err), nil
 		}
 
 		span.SetStatus(codes.Ok, "")
-		return result, nil
+		return classifyToolResult("", toolName, result), nil
 	}
 }
@@ -11,6 +11,9 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
 )
 
 func TestMcpExecTool(t *testing.T) {
@@ -416,3 +419,81 @@ func TestShortenURLWithBitly(t *testing.T) {
 		}
 	})
 }
+
+func TestSortServerMatches(t *testing.T) {
+	matches := []ServerMatch{
+		{Name: "b-unknown", Score: 50},
+		{Name: "exact", Score: 100},
+		{Name: "popular", Score: 50, Server: catalog.Server{Metadata: &catalog.Metadata{Pulls: 100000}}},
+		{Name: "a-unknown", Score: 50},
+		{Name: "niche", Score: 50, Server: catalog.Server{Metadata: &catalog.Metadata{Pulls: 10}}},
+	}
+
+	sortServerMatches(matches)
+
+	var names []string
+	for _, match := range matches {
+		names = append(names, match.Name)
+	}
+	assert.Equal(t, []string{"exact", "popular", "niche", "a-unknown", "b-unknown"}, names)
+}
+
+func TestMcpFindPagination(t *testing.T) {
+	telemetry.Init()
+
+	servers := map[string]catalog.Server{}
+	for i := range 5 {
+		servers[fmt.Sprintf("search-%d", i)] = catalog.Server{Description: "Search the web"}
+	}
+	servers["other"] = catalog.Server{Description: "Something else"}
+
+	g := &Gateway{}
+	tool := g.createMcpFindTool(Configuration{servers: servers})
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddTool(tool.Tool, tool.Handler)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "mcp-find", Arguments: args})
+		require.NoError(t, err)
+		return result
+	}
+	find := func(args map[string]any) (map[string]any, []string) {
+		result := call(args)
+		require.False(t, result.IsError)
+		var response map[string]any
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+		var names []string
+		for _, server := range response["servers"].([]any) {
+			names = append(names, server.(map[string]any)["name"].(string))
+		}
+		return response, names
+	}
+
+	first, names := find(map[string]any{"query": "search", "limit": 2})
+	assert.Equal(t, []string{"search-0", "search-1"}, names)
+	assert.InDelta(t, 5, first["total_matches"], 0)
+	require.NotEmpty(t, first["next_cursor"])
+
+	// The query can be omitted with a cursor
+	second, names := find(map[string]any{"limit": 2, "cursor": first["next_cursor"]})
+	assert.Equal(t, []string{"search-2", "search-3"}, names)
+	assert.Equal(t, "search", second["query"])
+
+	last, names := find(map[string]any{"query": "search", "limit": 2, "cursor": second["next_cursor"]})
+	assert.Equal(t, []string{"search-4"}, names)
+	assert.NotContains(t, last, "next_cursor")
+
+	result := call(map[string]any{"query": "other", "cursor": first["next_cursor"]})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "the cursor is for the query")
+
+	result = call(map[string]any{"cursor": "not a cursor"})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "invalid cursor")
+}
@@ -0,0 +1,33 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterTools adds in-process tools to the gateway, listed to the clients along with the tools of the servers.
+// Tools must be registered before the gateway runs. Their ServerName groups them, for name conflicts and for the policy.
+func (g *Gateway) RegisterTools(tools ...ToolRegistration) {
+	g.extraTools = append(g.extraTools, tools...)
+}
+
+// RunEmbedded runs the gateway in another program, without serving any transport: ready is called once
+// the servers are started and their capabilities listed. The program then serves MCPServer, or
+// StreamableHTTPHandler, itself. RunEmbedded returns when ctx is done, after stopping the servers.
+func (g *Gateway) RunEmbedded(ctx context.Context, ready func()) error {
+	g.embeddedReady = ready
+	return g.Run(ctx)
+}
+
+// MCPServer returns the MCP server that aggregates the servers of the gateway, once it's initialized.
+func (g *Gateway) MCPServer() *mcp.Server {
+	return g.mcpServer
+}
+
+// StreamableHTTPHandler serves the gateway with the streamable HTTP transport, without any authentication
+// or check of the origin of the requests: those are left to the program embedding the gateway.
+func (g *Gateway) StreamableHTTPHandler() http.Handler {
+	return g.streamableHandler()
+}
@@ -0,0 +1,105 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/notify"
+)
+
+// eventsKeepAlive is how often a comment is sent on idle event streams, so that proxies don't close them.
+const eventsKeepAlive = 30 * time.Second
+
+// eventBroker fans the gateway's events out to the subscribers of the /events stream.
+type eventBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan notify.Event]struct{}
+}
+
+// subscribe returns a channel of events and a function to call to unsubscribe.
+func (b *eventBroker) subscribe() (<-chan notify.Event, func()) {
+	events := make(chan notify.Event, 64)
+
+	b.mu.Lock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[chan notify.Event]struct{})
+	}
+	b.subscribers[events] = struct{}{}
+	b.mu.Unlock()
+
+	return events, func() {
+		b.mu.Lock()
+		delete(b.subscribers, events)
+		b.mu.Unlock()
+	}
+}
+
+// publish sends an event to every subscriber. Slow subscribers miss events rather than block the gateway.
+func (b *eventBroker) publish(event notify.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for events := range b.subscribers {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// emit publishes an event on the /events stream and posts it to the configured webhooks.
+func (g *Gateway) emit(event notify.Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	g.events.publish(event)
+	g.notifier.Notify(event)
+}
+
+// handleEvents streams the gateway's events as server-sent events.
+func (g *Gateway) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeControlError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	events, unsubscribe := g.events.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Logf("! Can't marshal event %s: %s", event.Type, err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/notify"
+)
+
+func TestEventBroker(t *testing.T) {
+	var broker eventBroker
+
+	first, unsubscribeFirst := broker.subscribe()
+	second, unsubscribeSecond := broker.subscribe()
+	defer unsubscribeSecond()
+
+	broker.publish(notify.Event{Type: notify.EventServerAdded, Server: "github"})
+	assert.Equal(t, "github", (<-first).Server)
+	assert.Equal(t, "github", (<-second).Server)
+
+	unsubscribeFirst()
+	broker.publish(notify.Event{Type: notify.EventServerRemoved, Server: "github"})
+	assert.Equal(t, notify.EventServerRemoved, (<-second).Type)
+	assert.Empty(t, first)
+}
+
+func TestEventBrokerDropsEventsForSlowSubscribers(t *testing.T) {
+	var broker eventBroker
+
+	events, unsubscribe := broker.subscribe()
+	defer unsubscribe()
+
+	for range 100 {
+		broker.publish(notify.Event{Type: notify.EventError})
+	}
+	assert.Len(t, events, cap(events))
+}
+
+func TestEventsStream(t *testing.T) {
+	g := &Gateway{}
+
+	server := httptest.NewServer(http.StripPrefix(controlPathPrefix, g.controlHandler()))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan notify.Event)
+	done := make(chan error)
+	go func() {
+		done <- NewControlClientForURL(server.URL, "").Events(ctx, func(event notify.Event) {
+			received <- event
+		})
+	}()
+
+	// Wait for the client to subscribe
+	require.Eventually(t, func() bool {
+		g.events.mu.Lock()
+		defer g.events.mu.Unlock()
+		return len(g.events.subscribers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	g.emit(notify.Event{Type: notify.EventReloadCompleted, Server: "github", Message: "Server github reloaded"})
+
+	event := <-received
+	assert.Equal(t, notify.EventReloadCompleted, event.Type)
+	assert.Equal(t, "github", event.Server)
+	assert.Equal(t, "Server github reloaded", event.Message)
+	assert.False(t, event.Time.IsZero())
+
+	cancel()
+	require.NoError(t, <-done)
+}
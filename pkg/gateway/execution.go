@@ -0,0 +1,144 @@
+package gateway
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+)
+
+// ExecutionMetaKey is the key, in the _meta of tool results, of the ToolExecution describing where the tool ran.
+const ExecutionMetaKey = "io.docker.mcp/execution"
+
+const (
+	RuntimeContainer = "container"
+	RuntimeRemote    = "remote"
+	// RuntimeInProcess is a plugin compiled in the gateway.
+	RuntimeInProcess = "in-process"
+	// RuntimeWasm is a WebAssembly module run by the gateway.
+	RuntimeWasm = "wasm"
+
+	NetworkDefault = "default"
+	// NetworkRestricted only lets the container reach its allowed hosts, through proxies.
+	NetworkRestricted = "restricted"
+	NetworkNone       = "none"
+)
+
+// ToolExecution describes where a tool call ran, for auditing and for agents to account for the context of a result.
+type ToolExecution struct {
+	Server string `json:"server"`
+	// Runtime is container, remote, in-process or wasm.
+	Runtime string `json:"runtime"`
+	Image   string `json:"image,omitempty"`
+	// ImageID is the digest of the image the container runs, which doesn't change when its tag is moved.
+	ImageID     string `json:"imageId,omitempty"`
+	ContainerID string `json:"containerId,omitempty"`
+	RemoteURL   string `json:"remoteUrl,omitempty"`
+	// Network is default, restricted or none.
+	Network      string   `json:"network,omitempty"`
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+	// ReadOnlyVolumes is true when the volumes were mounted read-only, for a tool annotated as read-only.
+	ReadOnlyVolumes bool  `json:"readOnlyVolumes,omitempty"`
+	DurationMs      int64 `json:"durationMs"`
+}
+
+// containerClient is the client of a server running in a container started by the gateway.
+type containerClient struct {
+	mcpclient.Client
+	containerID string
+	imageID     string
+}
+
+// newContainerIDFile returns a path, that doesn't exist yet, where docker run writes the ID of the container it creates.
+func newContainerIDFile() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return filepath.Join(os.TempDir(), "docker-mcp-"+hex.EncodeToString(buf)+".cid")
+}
+
+// newContainerClient reads, and removes, the file holding the ID of the container of a client, once it's started.
+func newContainerClient(ctx context.Context, dockerClient docker.Client, client mcpclient.Client, idFile string) mcpclient.Client {
+	buf, err := os.ReadFile(idFile)
+	_ = os.Remove(idFile)
+	if err != nil {
+		return client
+	}
+
+	cc := &containerClient{
+		Client:      client,
+		containerID: strings.TrimSpace(string(buf)),
+	}
+	if inspect, err := dockerClient.InspectContainer(ctx, cc.containerID); err == nil {
+		cc.imageID = inspect.Image
+	}
+	return cc
+}
+
+func (g *Gateway) toolExecution(serverConfig *catalog.ServerConfig, client mcpclient.Client, readOnly *bool, duration time.Duration) ToolExecution {
+	execution := ToolExecution{
+		Server:     serverConfig.Name,
+		DurationMs: duration.Milliseconds(),
+	}
+
+	switch {
+	case serverConfig.Spec.SSEEndpoint != "":
+		execution.Runtime = RuntimeRemote
+		execution.RemoteURL = serverConfig.Spec.SSEEndpoint
+	case serverConfig.Spec.Remote.URL != "":
+		execution.Runtime = RuntimeRemote
+		execution.RemoteURL = serverConfig.Spec.Remote.URL
+	case serverConfig.Spec.Type == catalog.TypePlugin:
+		execution.Runtime = RuntimeInProcess
+	case serverConfig.Spec.Type == catalog.TypeWasm:
+		execution.Runtime = RuntimeWasm
+		if len(serverConfig.Spec.AllowHosts) > 0 && !serverConfig.Spec.DisableNetwork {
+			execution.Network = NetworkRestricted
+			execution.AllowedHosts = serverConfig.Spec.AllowHosts
+		} else {
+			execution.Network = NetworkNone
+		}
+	default:
+		execution.Runtime = RuntimeContainer
+		execution.Image = serverConfig.Spec.Image
+		execution.ReadOnlyVolumes = readOnly != nil && *readOnly && len(serverConfig.Spec.Volumes) > 0
+		switch {
+		case serverConfig.Spec.DisableNetwork:
+			execution.Network = NetworkNone
+		case g.BlockNetwork && len(serverConfig.Spec.AllowHosts) > 0:
+			execution.Network = NetworkRestricted
+			execution.AllowedHosts = serverConfig.Spec.AllowHosts
+		default:
+			execution.Network = NetworkDefault
+		}
+		if cc, ok := client.(*containerClient); ok {
+			execution.ContainerID = cc.containerID
+			execution.ImageID = cc.imageID
+		}
+	}
+
+	return execution
+}
+
+// withExecutionMeta adds the description of where a tool ran to the _meta of its result.
+func withExecutionMeta(result *mcp.CallToolResult, execution ToolExecution) *mcp.CallToolResult {
+	if result == nil {
+		return nil
+	}
+
+	withExecution := *result
+	withExecution.Meta = mcp.Meta{}
+	for k, v := range result.Meta {
+		withExecution.Meta[k] = v
+	}
+	withExecution.Meta[ExecutionMetaKey] = execution
+	return &withExecution
+}
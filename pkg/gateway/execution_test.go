@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+type fakeContainers struct {
+	docker.Client
+	images map[string]string
+}
+
+func (f *fakeContainers) InspectContainer(_ context.Context, containerID string) (container.InspectResponse, error) {
+	return container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{ID: containerID, Image: f.images[containerID]}}, nil
+}
+
+func TestNewContainerClient(t *testing.T) {
+	idFile := newContainerIDFile()
+	require.NoError(t, os.WriteFile(idFile, []byte("c0ffee\n"), 0o600))
+
+	client := newContainerClient(t.Context(), &fakeContainers{images: map[string]string{"c0ffee": "sha256:1234"}}, nil, idFile)
+
+	cc, ok := client.(*containerClient)
+	require.True(t, ok)
+	assert.Equal(t, "c0ffee", cc.containerID)
+	assert.Equal(t, "sha256:1234", cc.imageID)
+	assert.NoFileExists(t, idFile)
+
+	// Without the file, the client is returned as is
+	assert.Nil(t, newContainerClient(t.Context(), &fakeContainers{}, nil, newContainerIDFile()))
+}
+
+func TestToolExecution(t *testing.T) {
+	g := &Gateway{Options: Options{BlockNetwork: true}}
+	readOnly := true
+
+	execution := g.toolExecution(&catalog.ServerConfig{
+		Name: "fetch",
+		Spec: catalog.Server{Image: "mcp/fetch", AllowHosts: []string{"example.com:443"}, Volumes: []string{"/data:/data"}},
+	}, &containerClient{containerID: "c0ffee", imageID: "sha256:1234"}, &readOnly, 1500*time.Millisecond)
+	assert.Equal(t, ToolExecution{
+		Server:          "fetch",
+		Runtime:         RuntimeContainer,
+		Image:           "mcp/fetch",
+		ImageID:         "sha256:1234",
+		ContainerID:     "c0ffee",
+		Network:         NetworkRestricted,
+		AllowedHosts:    []string{"example.com:443"},
+		ReadOnlyVolumes: true,
+		DurationMs:      1500,
+	}, execution)
+
+	execution = g.toolExecution(&catalog.ServerConfig{
+		Name: "time",
+		Spec: catalog.Server{Image: "mcp/time", DisableNetwork: true},
+	}, nil, nil, time.Second)
+	assert.Equal(t, NetworkNone, execution.Network)
+	assert.Empty(t, execution.ContainerID)
+
+	execution = g.toolExecution(&catalog.ServerConfig{
+		Name: "linear",
+		Spec: catalog.Server{Type: "remote", Remote: catalog.Remote{URL: "https://mcp.linear.app/mcp"}},
+	}, nil, nil, time.Second)
+	assert.Equal(t, ToolExecution{Server: "linear", Runtime: RuntimeRemote, RemoteURL: "https://mcp.linear.app/mcp", DurationMs: 1000}, execution)
+}
+
+func TestWithExecutionMeta(t *testing.T) {
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: "ok"}},
+		Meta:    mcp.Meta{"trace": "abc"},
+	}
+	execution := ToolExecution{Server: "fetch", Runtime: RuntimeContainer}
+
+	withExecution := withExecutionMeta(result, execution)
+
+	assert.Equal(t, mcp.Meta{"trace": "abc", ExecutionMetaKey: execution}, withExecution.Meta)
+	assert.Equal(t, result.Content, withExecution.Content)
+	assert.Equal(t, mcp.Meta{"trace": "abc"}, result.Meta, "the server's result is not modified")
+	assert.Nil(t, withExecutionMeta(nil, execution))
+}
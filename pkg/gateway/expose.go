@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// SkippedServer is a catalog server that --expose-all didn't enable, and why.
+type SkippedServer struct {
+	Name   string
+	Reason string
+}
+
+// ExposableServers selects the catalog servers that can be enabled without being configured first:
+// the ones that need neither secrets nor an OAuth authorization. At most maxServers servers are
+// selected, by name, if maxServers is positive. The other servers are returned with the reason they were skipped.
+func ExposableServers(servers map[string]catalog.Server, maxServers int) ([]string, []SkippedServer) {
+	var names []string
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		exposed []string
+		skipped []SkippedServer
+	)
+	for _, name := range names {
+		server := servers[name]
+
+		var reason string
+		switch {
+		case server.IsOAuthServer():
+			reason = "requires an OAuth authorization"
+		case len(server.Secrets) > 0:
+			reason = fmt.Sprintf("requires %d secret(s)", len(server.Secrets))
+		case maxServers > 0 && len(exposed) >= maxServers:
+			reason = fmt.Sprintf("over the limit of %d servers", maxServers)
+		}
+
+		if reason != "" {
+			skipped = append(skipped, SkippedServer{Name: name, Reason: reason})
+			continue
+		}
+		exposed = append(exposed, name)
+	}
+
+	return exposed, skipped
+}
@@ -0,0 +1,30 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestExposableServers(t *testing.T) {
+	servers := map[string]catalog.Server{
+		"fetch":      {Image: "mcp/fetch"},
+		"duckduckgo": {Image: "mcp/duckduckgo"},
+		"github":     {Image: "mcp/github", Secrets: []catalog.Secret{{Name: "github.personal_access_token", Env: "GITHUB_TOKEN"}}},
+		"notion":     {Type: "remote", OAuth: &catalog.OAuth{Providers: []catalog.OAuthProvider{{Provider: "notion"}}}},
+		"time":       {Image: "mcp/time"},
+	}
+
+	exposed, skipped := ExposableServers(servers, 0)
+	assert.Equal(t, []string{"duckduckgo", "fetch", "time"}, exposed)
+	assert.Equal(t, []SkippedServer{
+		{Name: "github", Reason: "requires 1 secret(s)"},
+		{Name: "notion", Reason: "requires an OAuth authorization"},
+	}, skipped)
+
+	exposed, skipped = ExposableServers(servers, 2)
+	assert.Equal(t, []string{"duckduckgo", "fetch"}, exposed)
+	assert.Contains(t, skipped, SkippedServer{Name: "time", Reason: "over the limit of 2 servers"})
+}
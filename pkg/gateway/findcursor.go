@@ -0,0 +1,44 @@
+package gateway
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// findCursor is where the next page of the results of mcp-find starts. It's tied to its query,
+// for a cursor not to be reused with another one.
+type findCursor struct {
+	Query  string `json:"q"`
+	Offset int    `json:"o"`
+}
+
+func encodeFindCursor(query string, offset int) string {
+	buf, _ := json.Marshal(findCursor{Query: query, Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func decodeFindCursor(cursor string) (findCursor, error) {
+	var decoded findCursor
+
+	buf, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return decoded, errors.New("invalid cursor")
+	}
+	if err := json.Unmarshal(buf, &decoded); err != nil || decoded.Offset < 0 {
+		return findCursor{}, errors.New("invalid cursor")
+	}
+	return decoded, nil
+}
+
+// paginateMatches returns a page of sorted matches, and the offset of the next page, or -1 on the last page.
+func paginateMatches(matches []ServerMatch, offset, limit int) ([]ServerMatch, int) {
+	if offset >= len(matches) {
+		return nil, -1
+	}
+	end := offset + limit
+	if end >= len(matches) {
+		return matches[offset:], -1
+	}
+	return matches[offset:end], end
+}
@@ -13,6 +13,8 @@ import (
 	"go.opentelemetry.io/otel/metric"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/interceptors"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
 	"github.com/docker/mcp-gateway/pkg/telemetry"
 )
 
@@ -20,6 +22,15 @@ func getClientConfig(readOnlyHint *bool, ss *mcp.ServerSession, server *mcp.Serv
 	return &clientConfig{readOnly: readOnlyHint, serverSession: ss, server: server}
 }
 
+// withClientHeaders passes the HTTP headers of the client's request down to remote servers,
+// which only forward the headers they allowlist.
+func withClientHeaders(ctx context.Context, extra *mcp.RequestExtra) context.Context {
+	if extra == nil {
+		return ctx
+	}
+	return mcpclient.WithForwardedHeaders(ctx, extra.Header)
+}
+
 // inferServerType determines the type of MCP server based on its configuration
 func inferServerType(serverConfig *catalog.ServerConfig) string {
 	if serverConfig.Spec.Remote.Transport == "http" {
@@ -45,7 +56,7 @@ func (g *Gateway) mcpToolHandler(tool catalog.Tool) mcp.ToolHandler {
 		var args any
 		if len(req.Params.Arguments) > 0 {
 			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal arguments: %w", err)
+				return toolErrorResult("", req.Params.Name, fmt.Errorf("failed to unmarshal arguments: %w", err)), nil
 			}
 		}
 		params := &mcp.CallToolParams{
@@ -53,7 +64,11 @@ func (g *Gateway) mcpToolHandler(tool catalog.Tool) mcp.ToolHandler {
 			Name:      req.Params.Name,
 			Arguments: args,
 		}
-		return g.clientPool.runToolContainer(ctx, tool, params)
+		result, err := g.clientPool.runToolContainer(ctx, tool, params)
+		if err != nil {
+			return toolErrorResult("", req.Params.Name, err), nil
+		}
+		return classifyToolResult("", req.Params.Name, result), nil
 	}
 }
 
@@ -70,6 +85,10 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, an
 			fmt.Fprintf(os.Stderr, "[MCP-HANDLER] Tool call received: %s from server: %s\n", req.Params.Name, serverConfig.Name)
 		}
 
+		if result := g.confirmToolCall(ctx, req, req.Params.Name, annotations); result != nil {
+			return result, nil
+		}
+
 		// Start telemetry span for tool call
 		startTime := time.Now()
 		serverType := inferServerType(serverConfig)
@@ -90,6 +109,10 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, an
 			spanAttrs = append(spanAttrs, attribute.String("mcp.server.endpoint", serverConfig.Spec.Remote.URL))
 		}
 
+		if correlationID := interceptors.CorrelationID(ctx); correlationID != "" {
+			spanAttrs = append(spanAttrs, attribute.String("mcp.correlation_id", correlationID))
+		}
+
 		ctx, span := telemetry.StartToolCallSpan(ctx, req.Params.Name, spanAttrs...)
 		defer span.End()
 
@@ -108,12 +131,14 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, an
 			readOnlyHint = &annotations.ReadOnlyHint
 		}
 
+		locale := g.requestLocale(req.Params.Meta, req.Extra)
+		ctx = mcpclient.WithLocale(withClientHeaders(ctx, req.Extra), locale)
 		client, err := g.clientPool.AcquireClient(ctx, serverConfig, getClientConfig(readOnlyHint, req.Session, server))
 		if err != nil {
 			// Record error in telemetry
 			telemetry.RecordToolError(ctx, span, serverConfig.Name, serverType, req.Params.Name)
 			span.SetStatus(codes.Error, "Failed to acquire client")
-			return nil, err
+			return toolErrorResult(serverConfig.Name, req.Params.Name, err), nil
 		}
 		defer g.clientPool.ReleaseClient(client)
 
@@ -123,11 +148,11 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, an
 			if jsonErr := json.Unmarshal(req.Params.Arguments, &args); jsonErr != nil {
 				telemetry.RecordToolError(ctx, span, serverConfig.Name, serverType, req.Params.Name)
 				span.SetStatus(codes.Error, "Failed to unmarshal arguments")
-				return nil, fmt.Errorf("failed to unmarshal arguments: %w", jsonErr)
+				return toolErrorResult(serverConfig.Name, req.Params.Name, fmt.Errorf("failed to unmarshal arguments: %w", jsonErr)), nil
 			}
 		}
 		params := &mcp.CallToolParams{
-			Meta:      req.Params.Meta,
+			Meta:      withLocaleMeta(req.Params.Meta, locale),
 			Name:      originalToolName,
 			Arguments: args,
 		}
@@ -150,11 +175,20 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, an
 			// Record error in telemetry
 			telemetry.RecordToolError(ctx, span, serverConfig.Name, serverType, req.Params.Name)
 			span.SetStatus(codes.Error, "Tool execution failed")
-			return nil, err
+			return toolErrorResult(serverConfig.Name, req.Params.Name, err), nil
+		}
+
+		execution := g.toolExecution(serverConfig, client, readOnlyHint, time.Since(startTime))
+		if result.IsError {
+			return withExecutionMeta(classifyToolResult(serverConfig.Name, req.Params.Name, result), execution), nil
+		}
+
+		if expression := serverConfig.Spec.ToolTransforms[originalToolName]; expression != "" {
+			result = transformToolResult(req.Params.Name, expression, result)
 		}
 
 		span.SetStatus(codes.Ok, "")
-		return result, nil
+		return withExecutionMeta(result, execution), nil
 	}
 }
 
@@ -197,6 +231,8 @@ func (g *Gateway) mcpServerPromptHandler(serverName string, server *mcp.Server)
 		// Record prompt get counter
 		telemetry.RecordPromptGet(ctx, req.Params.Name, serverConfig.Name, req.Session.InitializeParams().ClientInfo.Name)
 
+		locale := g.requestLocale(req.Params.Meta, req.Extra)
+		ctx = mcpclient.WithLocale(withClientHeaders(ctx, req.Extra), locale)
 		client, err := g.clientPool.AcquireClient(ctx, serverConfig, getClientConfig(nil, req.Session, server))
 		if err != nil {
 			span.RecordError(err)
@@ -206,7 +242,9 @@ func (g *Gateway) mcpServerPromptHandler(serverName string, server *mcp.Server)
 		}
 		defer g.clientPool.ReleaseClient(client)
 
-		result, err := client.Session().GetPrompt(ctx, req.Params)
+		params := *req.Params
+		params.Meta = withLocaleMeta(req.Params.Meta, locale)
+		result, err := client.Session().GetPrompt(ctx, &params)
 
 		// Record duration
 		duration := time.Since(startTime).Milliseconds()
@@ -264,6 +302,7 @@ func (g *Gateway) mcpServerResourceHandler(serverName string, server *mcp.Server
 		// Record counter with server attribution
 		telemetry.RecordResourceRead(ctx, req.Params.URI, serverConfig.Name, req.Session.InitializeParams().ClientInfo.Name)
 
+		ctx = withClientHeaders(ctx, req.Extra)
 		client, err := g.clientPool.AcquireClient(ctx, serverConfig, getClientConfig(nil, req.Session, server))
 		if err != nil {
 			span.RecordError(err)
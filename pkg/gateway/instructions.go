@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// DefaultInstructionsMaxSize is the default size budget, in bytes, of the instructions sent on initialize.
+const DefaultInstructionsMaxSize = 8192
+
+const instructionsTruncated = "\n[instructions truncated]"
+
+// serverInstructions are the instructions of an enabled server.
+type serverInstructions struct {
+	Server string
+	Text   string
+}
+
+// instructionsMiddleware replaces the instructions of the initialize result with
+// those composed from the gateway's hints and the servers' instructions.
+func (g *Gateway) instructionsMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if err != nil || method != "initialize" {
+				return result, err
+			}
+
+			if initResult, ok := result.(*mcp.InitializeResult); ok {
+				initResult.Instructions = g.instructions()
+			}
+			return result, nil
+		}
+	}
+}
+
+// instructions composes the instructions sent to clients on initialize.
+func (g *Gateway) instructions() string {
+	g.instructionsMu.RLock()
+	var servers []serverInstructions
+	for _, serverName := range g.configuration.ServerNames() {
+		if text := g.serverInstructions[serverName]; text != "" {
+			servers = append(servers, serverInstructions{Server: serverName, Text: text})
+		}
+	}
+	g.instructionsMu.RUnlock()
+
+	return composeInstructions(g.Instructions, g.usageHints(), servers, g.InstructionsMaxSize)
+}
+
+// setServerInstructions records the instructions of a server. Those from the catalog take precedence over those the server returned on initialize.
+func (g *Gateway) setServerInstructions(serverConfig *catalog.ServerConfig, initResult *mcp.InitializeResult) {
+	text := serverConfig.Spec.Instructions
+	if text == "" && initResult != nil {
+		text = initResult.Instructions
+	}
+
+	g.instructionsMu.Lock()
+	defer g.instructionsMu.Unlock()
+
+	if g.serverInstructions == nil {
+		g.serverInstructions = map[string]string{}
+	}
+	if text == "" {
+		delete(g.serverInstructions, serverConfig.Name)
+	} else {
+		g.serverInstructions[serverConfig.Name] = text
+	}
+}
+
+// usageHints explains to the model how to use the gateway, given the features that are enabled.
+func (g *Gateway) usageHints() []string {
+	var hints []string
+
+	if g.DynamicTools {
+		hints = append(hints, "Use mcp-find to search the catalog for MCP servers, mcp-add to enable one and mcp-remove to disable it. "+
+			"The tools of the servers added this way are listed once the tools list changes. "+
+			"Use mcp-config-set and mcp-secret-set to configure a server before adding it, and mcp-help to learn about the gateway's tools and the limits of the session.")
+	}
+
+	switch {
+	case g.ToolNamePrefix:
+		hints = append(hints, "Tool names are prefixed with the name of the server exposing them, like server:tool.")
+	case g.ToolConflictStrategy == ToolConflictPrefix:
+		hints = append(hints, "When several servers expose a tool with the same name, the tools of the servers but the first one are renamed server:tool.")
+	}
+
+	return hints
+}
+
+// composeInstructions joins the custom instructions, the usage hints and the servers' instructions.
+// The result is truncated to maxSize bytes. A maxSize of 0 or less disables the instructions.
+func composeInstructions(custom string, hints []string, servers []serverInstructions, maxSize int) string {
+	if maxSize <= 0 {
+		return ""
+	}
+
+	var sections []string
+	if custom = strings.TrimSpace(custom); custom != "" {
+		sections = append(sections, custom)
+	}
+	if len(hints) > 0 {
+		sections = append(sections, "Using the Docker MCP Gateway:\n- "+strings.Join(hints, "\n- "))
+	}
+	for _, server := range servers {
+		if text := strings.TrimSpace(server.Text); text != "" {
+			sections = append(sections, "## "+server.Server+"\n"+text)
+		}
+	}
+
+	return truncateInstructions(strings.Join(sections, "\n\n"), maxSize)
+}
+
+// truncateInstructions cuts the instructions to maxSize bytes, on a rune boundary, and marks them as truncated.
+func truncateInstructions(instructions string, maxSize int) string {
+	if len(instructions) <= maxSize {
+		return instructions
+	}
+	if maxSize <= len(instructionsTruncated) {
+		return ""
+	}
+
+	cut := maxSize - len(instructionsTruncated)
+	for cut > 0 && !utf8.RuneStart(instructions[cut]) {
+		cut--
+	}
+
+	return strings.TrimRight(instructions[:cut], " \n") + instructionsTruncated
+}
@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestComposeInstructions(t *testing.T) {
+	instructions := composeInstructions("Be nice.", []string{"Use mcp-find."}, []serverInstructions{
+		{Server: "github", Text: "Use the GitHub tools.\n"},
+		{Server: "empty", Text: "  "},
+	}, DefaultInstructionsMaxSize)
+
+	assert.Equal(t, "Be nice.\n\nUsing the Docker MCP Gateway:\n- Use mcp-find.\n\n## github\nUse the GitHub tools.", instructions)
+}
+
+func TestComposeInstructionsDisabled(t *testing.T) {
+	assert.Empty(t, composeInstructions("Be nice.", nil, nil, 0))
+}
+
+func TestTruncateInstructions(t *testing.T) {
+	assert.Equal(t, "short", truncateInstructions("short", 10))
+
+	truncated := truncateInstructions(strings.Repeat("é", 100), 50)
+	assert.LessOrEqual(t, len(truncated), 50)
+	assert.True(t, strings.HasSuffix(truncated, instructionsTruncated))
+	assert.True(t, strings.HasPrefix(truncated, "éé"))
+	assert.NotContains(t, truncated, "�")
+
+	assert.Empty(t, truncateInstructions("too long for the budget", 5))
+}
+
+func TestUsageHints(t *testing.T) {
+	g := &Gateway{Options: Options{ToolConflictStrategy: ToolConflictFirstWins}}
+	assert.Empty(t, g.usageHints())
+
+	g = &Gateway{Options: Options{DynamicTools: true, ToolNamePrefix: true, ToolConflictStrategy: ToolConflictPrefix}}
+	hints := g.usageHints()
+	require.Len(t, hints, 2)
+	assert.Contains(t, hints[0], "mcp-find")
+	assert.Contains(t, hints[1], "server:tool")
+}
+
+func TestSetServerInstructions(t *testing.T) {
+	g := &Gateway{}
+
+	g.setServerInstructions(&catalog.ServerConfig{Name: "github"}, &mcp.InitializeResult{Instructions: "From the server."})
+	assert.Equal(t, "From the server.", g.serverInstructions["github"])
+
+	g.setServerInstructions(&catalog.ServerConfig{Name: "github", Spec: catalog.Server{Instructions: "From the catalog."}}, &mcp.InitializeResult{Instructions: "From the server."})
+	assert.Equal(t, "From the catalog.", g.serverInstructions["github"])
+
+	g.setServerInstructions(&catalog.ServerConfig{Name: "github"}, nil)
+	assert.NotContains(t, g.serverInstructions, "github")
+}
+
+func TestInstructionsOnInitialize(t *testing.T) {
+	g := &Gateway{
+		Options: Options{
+			DynamicTools:        true,
+			Instructions:        "Prefer read-only tools.",
+			InstructionsMaxSize: DefaultInstructionsMaxSize,
+		},
+		configuration: Configuration{serverNames: []string{"github", "fetch"}},
+		serverInstructions: map[string]string{
+			"github":   "Use the GitHub tools for issues.",
+			"disabled": "Not enabled anymore.",
+		},
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddReceivingMiddleware(g.instructionsMiddleware())
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	defer ss.Close()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer cs.Close()
+
+	instructions := cs.InitializeResult().Instructions
+	assert.True(t, strings.HasPrefix(instructions, "Prefer read-only tools.\n\n"))
+	assert.Contains(t, instructions, "mcp-find")
+	assert.Contains(t, instructions, "## github\nUse the GitHub tools for issues.")
+	assert.NotContains(t, instructions, "disabled")
+}
@@ -0,0 +1,127 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Labels of the containers the gateway starts, to filter them with `docker ps --filter label=<label>=<value>`.
+const (
+	LabelGateway     = "docker-mcp"
+	LabelServer      = "docker-mcp-name"
+	LabelGatewayID   = "docker-mcp-gateway-id"
+	LabelGatewayHost = "docker-mcp-gateway-host"
+	LabelGatewayPID  = "docker-mcp-gateway-pid"
+	LabelSession     = "docker-mcp-session-id"
+	LabelProfile     = "docker-mcp-profile"
+)
+
+var invalidContainerNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// ContainerNameData is what a --container-name template can use.
+type ContainerNameData struct {
+	// Server is the name of the MCP server, or of the tool for the containers of POCI tools
+	Server string
+	// Gateway is the id of the gateway instance
+	Gateway string
+	// Session is the id of the client session, for the servers started per session
+	Session string
+	// Profile is the id of the profile the gateway runs
+	Profile string
+	// Random is different for each container, to keep the names unique
+	Random string
+}
+
+// RenderContainerName renders a container name template, replacing the characters Docker rejects in a name.
+func RenderContainerName(tmpl string, data ContainerNameData) (string, error) {
+	parsed, err := template.New("container-name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid container name template: %w", err)
+	}
+
+	var name strings.Builder
+	if err := parsed.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("invalid container name template: %w", err)
+	}
+
+	sanitized := strings.Trim(invalidContainerNameChars.ReplaceAllString(name.String(), "-"), "-_.")
+	if sanitized == "" {
+		return "", fmt.Errorf("container name template %q renders an empty name", tmpl)
+	}
+	return sanitized, nil
+}
+
+func newGatewayInstanceID() string {
+	return randomHex(6)
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// gatewayLabels are the labels of the gateway instance that starts a container and of the profile it runs.
+func (cp *clientPool) gatewayLabels() map[string]string {
+	labels := map[string]string{}
+	if cp.instanceID != "" {
+		hostname, _ := os.Hostname()
+		labels[LabelGatewayID] = cp.instanceID
+		labels[LabelGatewayHost] = hostname
+		labels[LabelGatewayPID] = strconv.Itoa(os.Getpid())
+	}
+	if cp.profile != "" {
+		labels[LabelProfile] = cp.profile
+	}
+	return labels
+}
+
+func (cp *clientPool) gatewayLabelArgs() []string {
+	labels := cp.gatewayLabels()
+
+	var args []string
+	for _, label := range []string{LabelGatewayID, LabelGatewayHost, LabelGatewayPID, LabelProfile} {
+		if value, ok := labels[label]; ok {
+			args = append(args, "-l", label+"="+value)
+		}
+	}
+	return args
+}
+
+// sessionArgs labels the container of a server started for a client session, and names it after the
+// --container-name template.
+func (cp *clientPool) sessionArgs(serverName string, session *mcp.ServerSession) ([]string, error) {
+	var args []string
+
+	var sessionID string
+	if session != nil {
+		sessionID = session.ID()
+	}
+	if sessionID != "" {
+		args = append(args, "-l", LabelSession+"="+sessionID)
+	}
+
+	if cp.ContainerName != "" {
+		name, err := RenderContainerName(cp.ContainerName, ContainerNameData{
+			Server:  serverName,
+			Gateway: cp.instanceID,
+			Session: sessionID,
+			Profile: cp.profile,
+			Random:  randomHex(4),
+		})
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--name", name)
+	}
+
+	return args, nil
+}
@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderContainerName(t *testing.T) {
+	data := ContainerNameData{Server: "github", Gateway: "3f9a1c2b7d4e", Session: "AB/CD", Profile: "dev", Random: "0a1b"}
+
+	name, err := RenderContainerName("mcp-{{.Server}}-{{.Random}}", data)
+	require.NoError(t, err)
+	assert.Equal(t, "mcp-github-0a1b", name)
+
+	name, err = RenderContainerName("{{.Profile}}_{{.Session}}:{{.Server}}", data)
+	require.NoError(t, err)
+	assert.Equal(t, "dev_AB-CD-github", name)
+
+	name, err = RenderContainerName("{{.Session}}-{{.Server}}", ContainerNameData{Server: "github"})
+	require.NoError(t, err)
+	assert.Equal(t, "github", name)
+
+	_, err = RenderContainerName("{{.Unknown}}", data)
+	require.Error(t, err)
+	_, err = RenderContainerName("{{.Server", data)
+	require.Error(t, err)
+	_, err = RenderContainerName("{{.Session}}", ContainerNameData{Server: "github"})
+	require.Error(t, err)
+}
+
+func TestGatewayLabelArgs(t *testing.T) {
+	assert.Empty(t, (&clientPool{}).gatewayLabelArgs())
+
+	hostname, _ := os.Hostname()
+	cp := &clientPool{instanceID: "3f9a1c2b7d4e", profile: "dev"}
+	assert.Equal(t, []string{
+		"-l", "docker-mcp-gateway-id=3f9a1c2b7d4e",
+		"-l", "docker-mcp-gateway-host=" + hostname,
+		"-l", "docker-mcp-gateway-pid=" + strconv.Itoa(os.Getpid()),
+		"-l", "docker-mcp-profile=dev",
+	}, cp.gatewayLabelArgs())
+}
+
+func TestSessionArgs(t *testing.T) {
+	args, err := (&clientPool{}).sessionArgs("github", nil)
+	require.NoError(t, err)
+	assert.Empty(t, args)
+
+	cp := &clientPool{Options: Options{ContainerName: "mcp-{{.Gateway}}-{{.Server}}"}, instanceID: "3f9a1c2b7d4e"}
+	args, err = cp.sessionArgs("github", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--name", "mcp-3f9a1c2b7d4e-github"}, args)
+}
@@ -0,0 +1,88 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/lan"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// lanDiscoveryInterval is how often the gateway looks for the MCP servers advertised on the local network.
+const lanDiscoveryInterval = time.Minute
+
+// discoverLANServers keeps the list of the MCP servers advertised on the local network up to date,
+// for mcp-find to list them. Those servers are untrusted: they're only listed, never started.
+func (g *Gateway) discoverLANServers(ctx context.Context) {
+	log.Log("- Looking for MCP servers on the local network")
+
+	known := map[string]bool{}
+	ticker := time.NewTicker(lanDiscoveryInterval)
+	defer ticker.Stop()
+
+	for {
+		servers, errs := lan.Discover(ctx, lan.Options{
+			MDNS:  g.DiscoverLAN,
+			Hosts: g.DiscoverHosts,
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		for _, err := range errs {
+			log.Log("  ! Discovering MCP servers:", err)
+		}
+		for _, server := range servers {
+			if !known[server.URL] {
+				known[server.URL] = true
+				log.Logf("  > Discovered %s at %s (%s, untrusted)", server.Name, server.URL, server.Source)
+			}
+		}
+		g.setLANServers(servers)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (g *Gateway) setLANServers(servers []lan.Server) {
+	g.lanServersMu.Lock()
+	defer g.lanServersMu.Unlock()
+
+	g.lanServers = servers
+}
+
+// findLANServers returns the discovered servers whose name or description contains the query,
+// formatted for the results of mcp-find.
+func (g *Gateway) findLANServers(query string) []map[string]any {
+	g.lanServersMu.RLock()
+	defer g.lanServersMu.RUnlock()
+
+	var results []map[string]any
+	for _, server := range g.lanServers {
+		if !strings.Contains(strings.ToLower(server.Name), query) && !strings.Contains(strings.ToLower(server.Description), query) {
+			continue
+		}
+
+		result := map[string]any{
+			"name":       server.Name,
+			"url":        server.URL,
+			"transport":  server.Transport,
+			"source":     server.Source,
+			"discovered": true,
+			"trusted":    false,
+			"add":        "docker mcp catalog discover --add " + server.Name + " --catalog <catalog>",
+		}
+		if server.Description != "" {
+			result["description"] = server.Description
+		}
+		if server.Auth != "" {
+			result["auth"] = server.Auth
+		}
+		results = append(results, result)
+	}
+	return results
+}
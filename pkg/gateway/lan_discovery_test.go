@@ -0,0 +1,34 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/lan"
+)
+
+func TestFindLANServers(t *testing.T) {
+	g := &Gateway{}
+	g.setLANServers([]lan.Server{
+		{Name: "home-assistant", Description: "Home automation", URL: "http://192.168.1.10:8123/mcp", Transport: "streamable-http", Source: lan.SourceMDNS},
+		{Name: "nas", Description: "MCP Gateway 1.0 with 12 tools, at nas:8811", URL: "http://nas:8811/mcp", Transport: "streamable-http", Auth: "bearer", Source: lan.SourceWellKnown},
+	})
+
+	results := g.findLANServers("home")
+	require.Len(t, results, 1)
+	assert.Equal(t, "home-assistant", results[0]["name"])
+	assert.Equal(t, true, results[0]["discovered"])
+	assert.Equal(t, false, results[0]["trusted"])
+	assert.Equal(t, "docker mcp catalog discover --add home-assistant --catalog <catalog>", results[0]["add"])
+	assert.NotContains(t, results[0], "auth")
+
+	results = g.findLANServers("gateway")
+	require.Len(t, results, 1)
+	assert.Equal(t, "nas", results[0]["name"])
+	assert.Equal(t, "bearer", results[0]["auth"])
+
+	assert.Empty(t, g.findLANServers("github"))
+	assert.Len(t, g.findLANServers(""), 2)
+}
@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// localeMetaKey is the _meta key in which clients set, and servers read, the locale of a request.
+const localeMetaKey = "locale"
+
+// requestLocale returns the locale in which the results of a request should be written:
+// the locale set by the client in _meta, then the first language of its Accept-Language header,
+// then the gateway's --locale.
+func (g *Gateway) requestLocale(meta mcp.Meta, extra *mcp.RequestExtra) string {
+	if locale, ok := meta[localeMetaKey].(string); ok && strings.TrimSpace(locale) != "" {
+		return strings.TrimSpace(locale)
+	}
+	if extra != nil && extra.Header != nil {
+		if locale := firstLanguage(extra.Header.Get("Accept-Language")); locale != "" {
+			return locale
+		}
+	}
+	return g.Locale
+}
+
+// firstLanguage returns the first language of an Accept-Language header, eg. fr-FR for "fr-FR,fr;q=0.9,en;q=0.8".
+func firstLanguage(acceptLanguage string) string {
+	language, _, _ := strings.Cut(acceptLanguage, ",")
+	language, _, _ = strings.Cut(language, ";")
+	language = strings.TrimSpace(language)
+	if language == "*" {
+		return ""
+	}
+	return language
+}
+
+// withLocaleMeta returns a copy of the _meta of a request, with the locale set.
+func withLocaleMeta(meta mcp.Meta, locale string) mcp.Meta {
+	if locale == "" {
+		return meta
+	}
+	withLocale := maps.Clone(meta)
+	if withLocale == nil {
+		withLocale = mcp.Meta{}
+	}
+	withLocale[localeMetaKey] = locale
+	return withLocale
+}
@@ -0,0 +1,30 @@
+package gateway
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLocale(t *testing.T) {
+	g := &Gateway{Options: Options{Locale: "en-US"}}
+	header := http.Header{}
+	header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+
+	assert.Equal(t, "fr-FR", g.requestLocale(mcp.Meta{"locale": "fr-FR"}, &mcp.RequestExtra{Header: header}))
+	assert.Equal(t, "de-DE", g.requestLocale(nil, &mcp.RequestExtra{Header: header}))
+	assert.Equal(t, "en-US", g.requestLocale(mcp.Meta{"locale": 42}, nil))
+	assert.Equal(t, "en-US", g.requestLocale(nil, &mcp.RequestExtra{Header: http.Header{"Accept-Language": {"*"}}}))
+	assert.Empty(t, (&Gateway{}).requestLocale(nil, nil))
+}
+
+func TestWithLocaleMeta(t *testing.T) {
+	meta := mcp.Meta{"progressToken": "1"}
+
+	assert.Equal(t, mcp.Meta{"progressToken": "1", "locale": "fr-FR"}, withLocaleMeta(meta, "fr-FR"))
+	assert.Equal(t, mcp.Meta{"progressToken": "1"}, meta)
+	assert.Equal(t, mcp.Meta{"locale": "fr-FR"}, withLocaleMeta(nil, "fr-FR"))
+	assert.Nil(t, withLocaleMeta(nil, ""))
+}
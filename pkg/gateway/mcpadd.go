@@ -7,7 +7,6 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"slices"
 	"strings"
 	"time"
 
@@ -17,8 +16,8 @@ import (
 	"github.com/docker/mcp-gateway/pkg/contextkeys"
 	"github.com/docker/mcp-gateway/pkg/desktop"
 	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/notify"
 	"github.com/docker/mcp-gateway/pkg/oauth"
-	"github.com/docker/mcp-gateway/pkg/oci"
 )
 
 // mcpAddTool implements a tool for adding new servers to the registry
@@ -78,121 +77,70 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 			}, nil
 		}
 
-		// Append the new server to the current serverNames if not already present
-		found = slices.Contains(g.configuration.serverNames, serverName)
-		if !found {
-			g.configuration.serverNames = append(g.configuration.serverNames, serverName)
+		if result := g.checkClientServer(req, serverName); result != nil {
+			return result, nil
+		}
+
+		// Concurrent calls for the same server wait for each other
+		unlock := g.lockServer(serverName)
+		defer unlock()
+
+		if g.serverAlreadyEnabled(serverName, params.Activate) {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Server '%s' is already enabled.", serverName),
+				}},
+			}, nil
 		}
 
 		// Fetch updated secrets for the new server list
 		if g.configurator != nil {
 			if fbc, ok := g.configurator.(*FileBasedConfiguration); ok {
-				updatedSecrets, err := fbc.readDockerDesktopSecrets(ctx, g.configuration.servers, g.configuration.serverNames)
+				updatedSecrets, err := fbc.readDockerDesktopSecrets(ctx, g.configuration.servers, g.enabledServerNames(serverName))
 				if err == nil {
-					g.configuration.secrets = updatedSecrets
+					g.replaceSecrets(updatedSecrets)
 				} else {
 					log.Log("Warning: Failed to update secrets:", err)
 				}
 			}
 		}
 
-		// Check if all required secrets are set
-		var missingSecrets []string
+		// Check if all required secrets and config values are set
+		var missingSecretNames, missingConfigNames []string
 		if serverConfig != nil {
-			for _, secret := range serverConfig.Spec.Secrets {
-				if value, exists := g.configuration.secrets[secret.Name]; !exists || value == "" {
-					missingSecrets = append(missingSecrets, secret.Name)
-				}
-			}
-		}
-
-		// Check if all required config values are set and validate against schema
-		var missingConfig []string
-		if serverConfig != nil && len(serverConfig.Spec.Config) > 0 {
-			canonicalServerName := oci.CanonicalizeServerName(serverName)
-			serverConfigMap := g.configuration.config[canonicalServerName]
-
-			for _, configItem := range serverConfig.Spec.Config {
-				// Config items should be schema objects with a "name" property
-				schemaMap, ok := configItem.(map[string]any)
-				if !ok {
-					continue
-				}
-
-				// Get the name field - this identifies which config to validate
-				configName, ok := schemaMap["name"].(string)
-				if !ok || configName == "" {
-					continue
-				}
-
-				// Get the actual config value to validate
-				if serverConfigMap == nil {
-					missingConfig = append(missingConfig, fmt.Sprintf("%s (missing)", configName))
-					continue
-				}
-
-				configValue := serverConfigMap
-
-				// Convert the schema map to a jsonschema.Schema for validation
-				schemaBytes, err := json.Marshal(schemaMap)
-				if err != nil {
-					missingConfig = append(missingConfig, fmt.Sprintf("%s (invalid schema)", configName))
-					continue
-				}
-
-				var schema jsonschema.Schema
-				if err := json.Unmarshal(schemaBytes, &schema); err != nil {
-					missingConfig = append(missingConfig, fmt.Sprintf("%s (invalid schema)", configName))
-					continue
-				}
-
-				// Resolve the schema
-				resolved, err := schema.Resolve(nil)
-				if err != nil {
-					missingConfig = append(missingConfig, fmt.Sprintf("%s (schema resolution failed)", configName))
-					continue
-				}
-
-				// Validate the config value against the schema
-				if err := resolved.Validate(configValue); err != nil {
-					// Extract a helpful error message
-					errMsg := err.Error()
-					if len(errMsg) > 100 {
-						errMsg = errMsg[:97] + "..."
-					}
-					missingConfig = append(missingConfig, fmt.Sprintf("%s (%s)", configName, errMsg))
-				}
-			}
+			missingSecretNames = missingSecrets(serverConfig.Spec, g.configuration.secrets)
+			missingConfigNames = missingConfig(serverName, serverConfig.Spec, g.configuration.config)
 		}
 
 		// If secrets or config are missing, handle based on client type
-		if len(missingSecrets) > 0 || len(missingConfig) > 0 {
+		if len(missingSecretNames) > 0 || len(missingConfigNames) > 0 {
 			// Check if the client is nanobot
 			clientName := ""
 			if req.Session.InitializeParams().ClientInfo != nil {
 				clientName = req.Session.InitializeParams().ClientInfo.Name
 			}
 
-			if clientName == "nanobot" && len(missingSecrets) > 0 {
+			if clientName == "nanobot" && len(missingSecretNames) > 0 {
 				// For nanobot, return the interactive UI (only for secrets)
-				return secretInput(missingSecrets, serverName), nil
+				return secretInput(missingSecretNames, serverName), nil
 			}
 
 			// For other clients, return an error with command line instructions
 			var instructions []string
 			var missingItems []string
 
-			if len(missingSecrets) > 0 {
-				missingItems = append(missingItems, fmt.Sprintf("secrets (%s)", strings.Join(missingSecrets, ", ")))
+			if len(missingSecretNames) > 0 {
+				missingItems = append(missingItems, fmt.Sprintf("secrets (%s)", strings.Join(missingSecretNames, ", ")))
 				instructions = append(instructions, "\nRequired secrets:")
-				for _, secret := range missingSecrets {
+				for _, secret := range missingSecretNames {
 					instructions = append(instructions, fmt.Sprintf("  docker mcp secret set %s=<value>", secret))
 				}
+				instructions = append(instructions, "Or use the mcp-secret-set tool to set them.")
 			}
 
-			if len(missingConfig) > 0 {
-				missingItems = append(missingItems, fmt.Sprintf("config (%s)", strings.Join(missingConfig, ", ")))
-				instructions = append(instructions, fmt.Sprintf("\nRequired configuration: %s", strings.Join(missingConfig, ", ")))
+			if len(missingConfigNames) > 0 {
+				missingItems = append(missingItems, fmt.Sprintf("config (%s)", strings.Join(missingConfigNames, ", ")))
+				instructions = append(instructions, fmt.Sprintf("\nRequired configuration: %s", strings.Join(missingConfigNames, ", ")))
 				instructions = append(instructions, "Use the mcp-config-set tool to configure these values.")
 			}
 
@@ -205,9 +153,15 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 		}
 
 		// Pull the Docker image before trying to use the server
+		var pullDuration time.Duration
 		if serverConfig.Spec.Image != "" {
 			log.Log(fmt.Sprintf("Pulling image for server '%s': %s", serverName, serverConfig.Spec.Image))
-			if err := g.docker.PullImage(ctx, serverConfig.Spec.Image); err != nil {
+			pull := serverImagePull(serverConfig.Spec)
+			if progressToken := req.Params.GetProgressToken(); progressToken != nil {
+				pull.progress = pullProgressNotifier(ctx, req.Session, progressToken, serverConfig.Spec.Image)
+			}
+			pullStart := time.Now()
+			if err := g.pullImage(ctx, serverConfig.Spec.Image, pull, []string{serverName}); err != nil {
 				return &mcp.CallToolResult{
 					Content: []mcp.Content{&mcp.TextContent{
 						Text: fmt.Sprintf("Error: Failed to pull image '%s' for server '%s'.\n\nDetails: %v\n\nThe server was not added. Please check the image name and your network connection.",
@@ -215,10 +169,16 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 					}},
 				}, nil
 			}
+			pullDuration = time.Since(pullStart)
 		}
 
+		added := g.enableServerName(serverName)
+
 		oldCaps, err := g.reloadServerCapabilities(ctx, serverName, clientConfig)
 		if err != nil {
+			if added {
+				g.disableServerName(serverName)
+			}
 			return nil, fmt.Errorf("failed to reload configuration: %w", err)
 		}
 
@@ -248,6 +208,13 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 		if err := g.configuration.Persist(); err != nil {
 			log.Log("Warning: Failed to persist configuration:", err)
 		}
+		g.persistProfile(ctx, serverName, true)
+
+		g.emit(notify.Event{
+			Type:    notify.EventServerAdded,
+			Server:  serverName,
+			Message: fmt.Sprintf("Server %s added", serverName),
+		})
 
 		// Get the list of tools that were just added from this server
 		var addedTools []*mcp.Tool
@@ -261,6 +228,9 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 
 		// Build the response text
 		responseText := fmt.Sprintf("Successfully added %d tools in server '%s'. Assume that it is fully configured and ready to use.", len(addedTools), serverName)
+		if serverConfig.Spec.Image != "" {
+			responseText += fmt.Sprintf(" Image %s was pulled in %s.", serverConfig.Spec.Image, pullDuration.Round(time.Millisecond))
+		}
 
 		// Include the JSON representation of the newly added tools if client name contains "cagent" or "claude"
 		shouldSendTools := len(addedTools) > 0 && strings.Contains(clientNameLower, "claude")
@@ -414,7 +384,7 @@ func (g *Gateway) getRemoteOAuthServerStatus(ctx context.Context, serverName str
 			if authorize, ok := elicitResult.Content["authorize"].(bool); ok && authorize {
 				// User agreed to authorize, call the OAuth authorize function
 				client := desktop.NewAuthClient()
-				authResponse, err := client.PostOAuthApp(ctx, serverName, "", false)
+				authResponse, err := client.PostOAuthApp(ctx, serverName, g.oauthScopes(serverName), false)
 				if err != nil {
 					log.Logf("Warning: Failed to start OAuth flow for %s: %v", serverName, err)
 					return false, "unable to trigger OAuth Flow"
@@ -449,7 +419,7 @@ func (g *Gateway) getRemoteOAuthServerStatus(ctx context.Context, serverName str
 	// Set context flag to enable disableAutoOpen parameter
 	ctxWithFlag := context.WithValue(ctx, contextkeys.OAuthInterceptorEnabledKey, true)
 	// disable auto-open
-	authResponse, err := client.PostOAuthApp(ctxWithFlag, serverName, "", true)
+	authResponse, err := client.PostOAuthApp(ctxWithFlag, serverName, g.oauthScopes(serverName), true)
 	if err != nil {
 		log.Logf("Warning: Failed to get OAuth URL for %s: %v", serverName, err)
 		return false, "Unable to get OAuth URL"
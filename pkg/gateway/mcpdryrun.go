@@ -0,0 +1,180 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/policy"
+	"github.com/docker/mcp-gateway/pkg/secretsscan"
+)
+
+// DryRunReport describes what calling a tool would do, without calling it.
+type DryRunReport struct {
+	Tool         string   `json:"tool"`
+	Server       string   `json:"server,omitempty"`
+	ServerType   string   `json:"serverType,omitempty"`
+	Image        string   `json:"image,omitempty"`
+	Endpoint     string   `json:"endpoint,omitempty"`
+	Container    string   `json:"container,omitempty"`
+	Valid        bool     `json:"valid"`
+	SchemaErrors []string `json:"schemaErrors,omitempty"`
+	Interceptors []string `json:"interceptors,omitempty"`
+	BlockedBy    string   `json:"blockedBy,omitempty"`
+	WouldExecute bool     `json:"wouldExecute"`
+}
+
+// dryRun validates a tool call and reports what would happen if the client with the given identity executed it.
+func (g *Gateway) dryRun(toolName string, arguments json.RawMessage, identity *policy.Identity) DryRunReport {
+	report := DryRunReport{Tool: toolName}
+
+	g.capabilitiesMu.RLock()
+	toolReg, found := g.toolRegistrations[toolName]
+	g.capabilitiesMu.RUnlock()
+
+	if !found {
+		report.SchemaErrors = []string{fmt.Sprintf("tool %s not found in current session", toolName)}
+		return report
+	}
+	report.Server = toolReg.ServerName
+
+	if serverConfig, _, ok := g.configuration.Find(toolReg.ServerName); ok && serverConfig != nil {
+		describeServer(&report, serverConfig, g.LongLived)
+	}
+
+	if err := validateToolArguments(toolReg.Tool, arguments); err != nil {
+		report.SchemaErrors = append(report.SchemaErrors, err.Error())
+	}
+	report.Valid = len(report.SchemaErrors) == 0
+
+	for _, interceptor := range g.Interceptors {
+		if strings.HasPrefix(strings.ToLower(interceptor), "before:") {
+			report.Interceptors = append(report.Interceptors, interceptor+" would run before the call and may replace its result")
+		} else {
+			report.Interceptors = append(report.Interceptors, interceptor+" would run after the call and may replace its result")
+		}
+	}
+
+	if g.BlockSecrets && secretsscan.ContainsSecrets(string(arguments)) {
+		report.BlockedBy = "block-secrets: a secret is being passed to the tool"
+	} else if err := g.toolPolicyError(toolReg.ServerName, toolReg.serverToolName(toolName), identity); err != nil {
+		report.BlockedBy = "policy: " + err.Error()
+	}
+
+	report.WouldExecute = report.Valid && report.BlockedBy == ""
+	return report
+}
+
+func describeServer(report *DryRunReport, serverConfig *catalog.ServerConfig, longLived bool) {
+	report.ServerType = inferServerType(serverConfig)
+
+	switch {
+	case serverConfig.Spec.Remote.URL != "":
+		report.Endpoint = serverConfig.Spec.Remote.URL
+	case serverConfig.Spec.SSEEndpoint != "":
+		report.Endpoint = serverConfig.Spec.SSEEndpoint
+	case serverConfig.Spec.Image != "":
+		report.Image = serverConfig.Spec.Image
+		if serverConfig.Spec.LongLived || longLived {
+			report.Container = "long-lived container, started on first use and reused"
+		} else {
+			report.Container = "new container for this call"
+		}
+		if serverConfig.Spec.DisableNetwork {
+			report.Container += ", without network access"
+		}
+	}
+}
+
+// validateToolArguments validates arguments against a tool's input schema.
+func validateToolArguments(tool *mcp.Tool, arguments json.RawMessage) error {
+	if tool.InputSchema == nil {
+		return nil
+	}
+
+	// Input schemas of tools listed from servers are not always *jsonschema.Schema
+	schemaJSON, err := json.Marshal(tool.InputSchema)
+	if err != nil {
+		return fmt.Errorf("invalid input schema: %w", err)
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return fmt.Errorf("invalid input schema: %w", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return fmt.Errorf("invalid input schema: %w", err)
+	}
+
+	var instance any = map[string]any{}
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &instance); err != nil {
+			return fmt.Errorf("arguments are not valid JSON: %w", err)
+		}
+	}
+
+	return resolved.Validate(instance)
+}
+
+// createMcpDryRunTool implements a tool that validates a tool call without executing it
+func (g *Gateway) createMcpDryRunTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "mcp-dry-run",
+		Description: "Validate a tool call without executing it. Checks the arguments against the tool's input schema and the gateway's policies, and reports which server and container would handle the call. Use it to plan destructive operations.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"name": {
+					Type:        "string",
+					Description: "Name of the tool to validate",
+				},
+				"arguments": {
+					Types:       []string{"string", "number", "boolean", "object", "array", "null"},
+					Description: "Arguments that would be passed to the tool",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+
+	handler := func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+		if params.Name == "" {
+			return nil, fmt.Errorf("name parameter is required")
+		}
+
+		// Like mcp-exec, arguments can be a JSON-encoded string
+		arguments := params.Arguments
+		var argString string
+		if err := json.Unmarshal(arguments, &argString); err == nil {
+			arguments = json.RawMessage(argString)
+		}
+
+		reportJSON, err := json.MarshalIndent(g.dryRun(strings.TrimSpace(params.Name), arguments, requestIdentity(req.Extra)), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal dry-run report: %w", err)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: string(reportJSON),
+			}},
+		}, nil
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("mcp-dry-run", handler),
+	}
+}
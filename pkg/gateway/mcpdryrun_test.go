@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/policy"
+)
+
+func newDryRunGateway() *Gateway {
+	g := &Gateway{
+		toolRegistrations: map[string]ToolRegistration{
+			"delete_repo": {
+				ServerName: "github",
+				Tool: &mcp.Tool{
+					Name: "delete_repo",
+					InputSchema: &jsonschema.Schema{
+						Type: "object",
+						Properties: map[string]*jsonschema.Schema{
+							"repo": {Type: "string"},
+						},
+						Required: []string{"repo"},
+					},
+				},
+			},
+		},
+		configuration: Configuration{
+			serverNames: []string{"github"},
+			servers: map[string]catalog.Server{
+				"github": {Name: "github", Image: "mcp/github"},
+			},
+		},
+	}
+	g.Interceptors = []string{"before:exec:/bin/audit"}
+	return g
+}
+
+func TestDryRunValidCall(t *testing.T) {
+	report := newDryRunGateway().dryRun("delete_repo", json.RawMessage(`{"repo":"docker/mcp-gateway"}`), nil)
+
+	assert.True(t, report.Valid)
+	assert.True(t, report.WouldExecute)
+	assert.Equal(t, "github", report.Server)
+	assert.Equal(t, "docker", report.ServerType)
+	assert.Equal(t, "mcp/github", report.Image)
+	assert.Equal(t, "new container for this call", report.Container)
+	require.Len(t, report.Interceptors, 1)
+	assert.Contains(t, report.Interceptors[0], "before:exec:/bin/audit would run before the call")
+}
+
+func TestDryRunInvalidArguments(t *testing.T) {
+	report := newDryRunGateway().dryRun("delete_repo", json.RawMessage(`{"repo":42}`), nil)
+
+	assert.False(t, report.Valid)
+	assert.False(t, report.WouldExecute)
+	assert.NotEmpty(t, report.SchemaErrors)
+
+	report = newDryRunGateway().dryRun("delete_repo", nil, nil)
+	assert.False(t, report.Valid)
+}
+
+func TestDryRunUnknownTool(t *testing.T) {
+	report := newDryRunGateway().dryRun("unknown", nil, nil)
+
+	assert.False(t, report.Valid)
+	assert.False(t, report.WouldExecute)
+	assert.Equal(t, []string{"tool unknown not found in current session"}, report.SchemaErrors)
+}
+
+func TestDryRunPolicy(t *testing.T) {
+	g := newProfileTestGateway()
+	g.toolRegistrations["query"] = ToolRegistration{ServerName: "postgres", Tool: &mcp.Tool{Name: "query"}}
+	g.toolRegistrations["drop"] = ToolRegistration{ServerName: "postgres", Tool: &mcp.Tool{Name: "drop"}}
+	dba := &policy.Identity{Subject: "alice", Groups: []string{"dba"}}
+
+	report := g.dryRun("query", nil, dba)
+	assert.True(t, report.WouldExecute)
+	assert.Empty(t, report.BlockedBy)
+
+	// The tool isn't in the client's profile
+	report = g.dryRun("drop", nil, dba)
+	assert.True(t, report.Valid)
+	assert.False(t, report.WouldExecute)
+	assert.Contains(t, report.BlockedBy, "policy: ")
+}
@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// GatewayHelp is the documentation returned by the mcp-help tool.
+type GatewayHelp struct {
+	Tools  []HelpTool `json:"tools"`
+	Naming string     `json:"naming"`
+	Limits HelpLimits `json:"limits"`
+	Hints  []string   `json:"hints"`
+}
+
+// HelpTool is one of the gateway's own tools.
+type HelpTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// HelpLimits are the limits and restrictions that apply to the tool calls of the session.
+type HelpLimits struct {
+	Budget                  *BudgetStatus `json:"budget,omitempty"`
+	ConfirmDestructiveTools bool          `json:"confirmDestructiveTools"`
+	ConfirmTools            []string      `json:"confirmTools,omitempty"`
+	// RestrictedServers are the servers that can only be called at some times or by some users
+	RestrictedServers []string `json:"restrictedServers,omitempty"`
+	BlockSecrets      bool     `json:"blockSecrets"`
+	BlockNetwork      bool     `json:"blockNetwork"`
+	MaxSessions       int      `json:"maxSessions,omitempty"`
+	// LogRateLimit is how many messages each server can log to the session per interval
+	LogRateLimit int `json:"logRateLimit,omitempty"`
+}
+
+// help documents the gateway's own tools, how tools are named and the limits that apply to the session.
+func (g *Gateway) help(session *mcp.ServerSession) GatewayHelp {
+	help := GatewayHelp{
+		Tools:  []HelpTool{},
+		Naming: g.namingHelp(),
+		Limits: HelpLimits{
+			ConfirmDestructiveTools: g.ConfirmDestructiveTools,
+			ConfirmTools:            g.ConfirmTools,
+			BlockSecrets:            g.BlockSecrets,
+			BlockNetwork:            g.BlockNetwork,
+			MaxSessions:             g.MaxSessions,
+			LogRateLimit:            g.LogRateLimit,
+		},
+		Hints: append(g.usageHints(), "Tools annotated as read-only can't write to the volumes of their server."),
+	}
+	if g.SessionBudget > 0 {
+		help.Limits.Budget = g.budgetStatus(session)
+	}
+
+	g.policyMu.RLock()
+	if g.policy != nil {
+		for serverName, serverPolicy := range g.policy.Servers {
+			if len(serverPolicy.AccessWindows) > 0 || len(serverPolicy.AllowedUsers) > 0 || len(serverPolicy.AllowedGroups) > 0 {
+				help.Limits.RestrictedServers = append(help.Limits.RestrictedServers, serverName)
+			}
+		}
+		slices.Sort(help.Limits.RestrictedServers)
+	}
+	g.policyMu.RUnlock()
+
+	// The gateway's own tools are the ones that belong to no server
+	g.capabilitiesMu.RLock()
+	for _, registration := range g.toolRegistrations {
+		if registration.ServerName == "" {
+			help.Tools = append(help.Tools, HelpTool{Name: registration.Tool.Name, Description: registration.Tool.Description})
+		}
+	}
+	g.capabilitiesMu.RUnlock()
+	slices.SortFunc(help.Tools, func(a, b HelpTool) int { return strings.Compare(a.Name, b.Name) })
+
+	return help
+}
+
+// namingHelp explains how the tools of the servers are named.
+func (g *Gateway) namingHelp() string {
+	switch {
+	case g.ToolNamePrefix:
+		return "Tools are named server:tool, after the server exposing them."
+	case g.ToolConflictStrategy == ToolConflictPrefix:
+		return "Tools keep the name given by their server. When several servers expose a tool with the same name, the tools of the servers but the first one are renamed server:tool."
+	case g.ToolConflictStrategy == ToolConflictLastWins:
+		return "Tools keep the name given by their server. When several servers expose a tool with the same name, only the tool of the last server is exposed."
+	default:
+		return "Tools keep the name given by their server. When several servers expose a tool with the same name, only the tool of the first server is exposed."
+	}
+}
+
+// createMcpHelpTool implements a tool that documents the gateway for the agents using it
+func (g *Gateway) createMcpHelpTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "mcp-help",
+		Description: "Explain how to use the MCP gateway: its own tools, how tools are named, and the limits that apply to this session (budget, confirmations, access restrictions, sandboxing).",
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{},
+		},
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}
+
+	handler := func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		helpJSON, err := json.MarshalIndent(g.help(req.Session), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal help: %w", err)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: string(helpJSON),
+			}},
+		}, nil
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("mcp-help", handler),
+	}
+}
@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/policy"
+)
+
+func TestHelp(t *testing.T) {
+	g := &Gateway{
+		Options: Options{
+			DynamicTools:         true,
+			ToolConflictStrategy: ToolConflictPrefix,
+			SessionBudget:        10,
+			BudgetAction:         BudgetActionReject,
+			ConfirmTools:         []string{"delete_*"},
+			BlockSecrets:         true,
+		},
+		toolRegistrations: map[string]ToolRegistration{
+			"mcp-status": {Tool: &mcp.Tool{Name: "mcp-status", Description: "Report the status"}},
+			"mcp-find":   {Tool: &mcp.Tool{Name: "mcp-find", Description: "Find servers"}},
+			"fetch":      {ServerName: "fetch", Tool: &mcp.Tool{Name: "fetch"}},
+		},
+		policy: &policy.Policy{Servers: map[string]policy.ServerPolicy{
+			"github": {AllowedUsers: []string{"alice@example.com"}},
+			"time":   {AllowOverride: true},
+		}},
+	}
+
+	help := g.help(nil)
+	assert.Equal(t, []HelpTool{
+		{Name: "mcp-find", Description: "Find servers"},
+		{Name: "mcp-status", Description: "Report the status"},
+	}, help.Tools)
+	assert.Contains(t, help.Naming, "renamed server:tool")
+	require.NotNil(t, help.Limits.Budget)
+	assert.InDelta(t, 10, help.Limits.Budget.Limit, 0)
+	assert.Equal(t, []string{"delete_*"}, help.Limits.ConfirmTools)
+	assert.Equal(t, []string{"github"}, help.Limits.RestrictedServers)
+	assert.True(t, help.Limits.BlockSecrets)
+	assert.Contains(t, help.Hints[0], "mcp-help")
+
+	g.ToolNamePrefix = true
+	g.SessionBudget = 0
+	help = g.help(nil)
+	assert.Equal(t, "Tools are named server:tool, after the server exposing them.", help.Naming)
+	assert.Nil(t, help.Limits.Budget)
+}
@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// GatewayStatus is the status reported by the mcp-status tool.
+type GatewayStatus struct {
+	Servers       []ServerStatus `json:"servers"`
+	ToolConflicts []ToolConflict `json:"toolConflicts"`
+	Budget        *BudgetStatus  `json:"budget"`
+	Client        *ClientStatus  `json:"client,omitempty"`
+}
+
+// ClientStatus is the client of the session and its capabilities.
+type ClientStatus struct {
+	Name         string             `json:"name"`
+	Version      string             `json:"version,omitempty"`
+	Capabilities ClientCapabilities `json:"capabilities"`
+}
+
+// ServerStatus is the status of an enabled server.
+type ServerStatus struct {
+	Name  string `json:"name"`
+	Tools int    `json:"tools"`
+}
+
+// status builds a snapshot of the enabled servers, of the tool name conflicts, of the session's spend
+// and of the capabilities of its client.
+func (g *Gateway) status(session *mcp.ServerSession) GatewayStatus {
+	budget := g.budgetStatus(session)
+
+	var client *ClientStatus
+	if session != nil && session.InitializeParams() != nil {
+		client = &ClientStatus{
+			Name:         sessionClientName(session),
+			Capabilities: g.sessionCapabilities(session),
+		}
+		if clientInfo := session.InitializeParams().ClientInfo; clientInfo != nil {
+			client.Version = clientInfo.Version
+		}
+	}
+
+	g.capabilitiesMu.RLock()
+	defer g.capabilitiesMu.RUnlock()
+
+	status := GatewayStatus{
+		Servers:       []ServerStatus{},
+		ToolConflicts: []ToolConflict{},
+		Budget:        budget,
+		Client:        client,
+	}
+	for _, serverName := range g.configuration.ServerNames() {
+		server := ServerStatus{Name: serverName}
+		if caps := g.serverCapabilities[serverName]; caps != nil {
+			server.Tools = len(caps.ToolNames)
+		}
+		status.Servers = append(status.Servers, server)
+	}
+	status.ToolConflicts = append(status.ToolConflicts, g.toolConflicts...)
+
+	return status
+}
+
+// createMcpStatusTool implements a tool that reports the status of the gateway
+func (g *Gateway) createMcpStatusTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "mcp-status",
+		Description: "Report the status of the MCP gateway: enabled servers, number of tools per server, tool name conflicts between servers, the cost of the tools called in this session and the capabilities of the client.",
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{},
+		},
+	}
+
+	handler := func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		statusJSON, err := json.MarshalIndent(g.status(req.Session), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal status: %w", err)
+		}
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: string(statusJSON),
+			}},
+		}, nil
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("mcp-status", handler),
+	}
+}
@@ -0,0 +1,54 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/notify"
+)
+
+const (
+	// A server that fails to start crashLoopThreshold times within crashLoopWindow is in a crash loop.
+	crashLoopThreshold = 3
+	crashLoopWindow    = 5 * time.Minute
+)
+
+// recordServerFailure keeps track of the failures to start a server and notifies when it's in a crash loop.
+func (g *Gateway) recordServerFailure(serverName string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	now := time.Now()
+
+	g.serverFailuresMu.Lock()
+	if g.serverFailures == nil {
+		g.serverFailures = make(map[string][]time.Time)
+	}
+	var failures []time.Time
+	for _, failure := range g.serverFailures[serverName] {
+		if now.Sub(failure) < crashLoopWindow {
+			failures = append(failures, failure)
+		}
+	}
+	failures = append(failures, now)
+
+	crashLoop := len(failures) >= crashLoopThreshold
+	if crashLoop {
+		// Start counting again, so that a server that keeps crashing is reported periodically
+		failures = nil
+	}
+	g.serverFailures[serverName] = failures
+	g.serverFailuresMu.Unlock()
+
+	if crashLoop {
+		g.emit(notify.Event{
+			Type:    notify.EventServerCrashLoop,
+			Server:  serverName,
+			Message: fmt.Sprintf("%s failed to start %d times in %s", serverName, crashLoopThreshold, crashLoopWindow),
+			Details: map[string]string{"error": err.Error()},
+		})
+	}
+}
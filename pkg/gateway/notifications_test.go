@@ -0,0 +1,25 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordServerFailure(t *testing.T) {
+	g := &Gateway{}
+
+	g.recordServerFailure("postgres", errors.New("exit status 1"))
+	g.recordServerFailure("postgres", errors.New("exit status 1"))
+	assert.Len(t, g.serverFailures["postgres"], 2)
+
+	// The third failure is a crash loop, the counter starts again
+	g.recordServerFailure("postgres", errors.New("exit status 1"))
+	assert.Empty(t, g.serverFailures["postgres"])
+
+	// Canceled calls aren't failures of the server
+	g.recordServerFailure("postgres", context.Canceled)
+	assert.Empty(t, g.serverFailures["postgres"])
+}
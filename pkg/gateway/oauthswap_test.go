@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+)
+
+// swappingClient is a connection to a remote server that can switch to a refreshed OAuth token.
+type swappingClient struct {
+	mcpclient.Client
+	swapped int
+}
+
+func (c *swappingClient) SwapOAuthToken(context.Context) bool {
+	c.swapped++
+	return true
+}
+
+func TestSwapOAuthTokens(t *testing.T) {
+	server := func(scopes ...string) catalog.Server {
+		return catalog.Server{
+			Name:   "linear",
+			Remote: catalog.Remote{URL: "https://mcp.linear.app/mcp", Transport: "streamable-http"},
+			OAuth:  &catalog.OAuth{Providers: []catalog.OAuthProvider{{Provider: "linear"}}, Scopes: scopes},
+		}
+	}
+	g := &Gateway{configuration: Configuration{
+		serverNames: []string{"linear"},
+		servers:     map[string]catalog.Server{"linear": server("read")},
+	}}
+	cp := newClientPool(Options{}, nil, g)
+
+	// Nothing to swap, the server is reloaded
+	assert.False(t, cp.SwapOAuthTokens(t.Context(), "linear"))
+
+	client := &swappingClient{}
+	getter := &clientGetter{client: newClientWithCleanup(client, func(context.Context) error { return nil })}
+	getter.once.Do(func() {})
+	config := &catalog.ServerConfig{Name: "linear", Spec: server("read")}
+	cp.keptClients[clientKey{serverName: "linear", session: &mcp.ServerSession{}}] = keptClient{Name: "linear", Getter: getter, Config: config}
+
+	assert.True(t, cp.SwapOAuthTokens(t.Context(), "linear"))
+	assert.Equal(t, 1, client.swapped)
+
+	// The scopes changed, the connections are reopened
+	g.configuration.servers["linear"] = server("read", "write")
+	assert.False(t, cp.SwapOAuthTokens(t.Context(), "linear"))
+	assert.Equal(t, 1, client.swapped)
+}
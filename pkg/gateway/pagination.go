@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// listAll follows the cursors of a paginated list until its last page.
+func listAll[T any](ctx context.Context, list func(ctx context.Context, cursor string) ([]T, string, error)) ([]T, error) {
+	var (
+		all    []T
+		cursor string
+	)
+	seen := map[string]bool{}
+	for {
+		items, next, err := list(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+
+		if next == "" {
+			return all, nil
+		}
+		// A server that sends the same cursor twice would be listed forever
+		if seen[next] {
+			return nil, fmt.Errorf("cursor %q returned twice", next)
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
+
+func listAllTools(ctx context.Context, session *mcp.ClientSession) ([]*mcp.Tool, error) {
+	return listAll(ctx, func(ctx context.Context, cursor string) ([]*mcp.Tool, string, error) {
+		result, err := session.ListTools(ctx, &mcp.ListToolsParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return result.Tools, result.NextCursor, nil
+	})
+}
+
+func listAllPrompts(ctx context.Context, session *mcp.ClientSession) ([]*mcp.Prompt, error) {
+	return listAll(ctx, func(ctx context.Context, cursor string) ([]*mcp.Prompt, string, error) {
+		result, err := session.ListPrompts(ctx, &mcp.ListPromptsParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return result.Prompts, result.NextCursor, nil
+	})
+}
+
+func listAllResources(ctx context.Context, session *mcp.ClientSession) ([]*mcp.Resource, error) {
+	return listAll(ctx, func(ctx context.Context, cursor string) ([]*mcp.Resource, string, error) {
+		result, err := session.ListResources(ctx, &mcp.ListResourcesParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return result.Resources, result.NextCursor, nil
+	})
+}
+
+func listAllResourceTemplates(ctx context.Context, session *mcp.ClientSession) ([]*mcp.ResourceTemplate, error) {
+	return listAll(ctx, func(ctx context.Context, cursor string) ([]*mcp.ResourceTemplate, string, error) {
+		result, err := session.ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return result.ResourceTemplates, result.NextCursor, nil
+	})
+}
@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAll(t *testing.T) {
+	pages := map[string][]string{"": {"a", "b"}, "1": {"c", "d"}, "2": {"e"}}
+	next := map[string]string{"": "1", "1": "2"}
+
+	items, err := listAll(t.Context(), func(_ context.Context, cursor string) ([]string, string, error) {
+		return pages[cursor], next[cursor], nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, items)
+
+	_, err = listAll(t.Context(), func(_ context.Context, _ string) ([]string, string, error) {
+		return []string{"a"}, "1", nil
+	})
+	require.ErrorContains(t, err, `cursor "1" returned twice`)
+
+	_, err = listAll(t.Context(), func(_ context.Context, cursor string) ([]string, string, error) {
+		if cursor == "1" {
+			return nil, "", errors.New("boom")
+		}
+		return []string{"a"}, "1", nil
+	})
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestListAllToolsFollowsCursors(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "paginated"}, &mcp.ServerOptions{PageSize: 2})
+	for _, name := range []string{"echo", "add", "search", "fetch", "time"} {
+		mcp.AddTool(server, &mcp.Tool{Name: name}, func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{}, nil, nil
+		})
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gateway"}, nil)
+	session, err := client.Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	firstPage, err := session.ListTools(t.Context(), &mcp.ListToolsParams{})
+	require.NoError(t, err)
+	require.Len(t, firstPage.Tools, 2)
+	require.NotEmpty(t, firstPage.NextCursor)
+
+	tools, err := listAllTools(t.Context(), session)
+	require.NoError(t, err)
+
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	assert.Equal(t, []string{"add", "echo", "fetch", "search", "time"}, names)
+}
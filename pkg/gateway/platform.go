@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+// dockerPlatform returns the platform of the Docker engine, like linux/arm64, or "" if it can't be detected.
+func (g *Gateway) dockerPlatform(ctx context.Context) string {
+	g.platformOnce.Do(func() {
+		platform, err := g.docker.ServerPlatform(ctx)
+		if err != nil {
+			log.Logf("  ! Can't detect the platform of the Docker engine: %v", err)
+			return
+		}
+		g.platform = platform
+	})
+	return g.platform
+}
+
+// checkEmulation warns when an image is not built for the platform of the Docker engine
+// and will run in emulation, which is slow. It reports whether the image runs in emulation.
+func (g *Gateway) checkEmulation(ctx context.Context, image string, serverNames []string) bool {
+	enginePlatform := g.dockerPlatform(ctx)
+	if enginePlatform == "" {
+		return false
+	}
+
+	inspect, err := g.docker.InspectImage(ctx, image)
+	if err != nil || inspect.Architecture == "" {
+		return false
+	}
+
+	imagePlatform := docker.ImagePlatform(inspect)
+	if !isEmulated(enginePlatform, imagePlatform) {
+		return false
+	}
+
+	log.Logf("  ! Image %s is built for %s and runs in emulation on %s, which is slow", imageBaseName(image), imagePlatform, enginePlatform)
+	for _, serverName := range serverNames {
+		telemetry.RecordEmulatedImage(ctx, serverName, image, imagePlatform, enginePlatform)
+	}
+	return true
+}
+
+// isEmulated tells whether an image built for a platform runs in emulation on an engine.
+// Variants, like arm/v7, are not compared.
+func isEmulated(enginePlatform, imagePlatform string) bool {
+	return osArch(enginePlatform) != osArch(imagePlatform)
+}
+
+func osArch(platform string) string {
+	parts := strings.SplitN(platform, "/", 3)
+	if len(parts) < 2 {
+		return platform
+	}
+	return parts[0] + "/" + parts[1]
+}
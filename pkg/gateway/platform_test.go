@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+type fakePlatforms struct {
+	docker.Client
+	enginePlatform string
+	images         map[string]image.InspectResponse
+}
+
+func (f *fakePlatforms) ServerPlatform(context.Context) (string, error) {
+	return f.enginePlatform, nil
+}
+
+func (f *fakePlatforms) InspectImage(_ context.Context, name string) (image.InspectResponse, error) {
+	return f.images[name], nil
+}
+
+func TestIsEmulated(t *testing.T) {
+	assert.False(t, isEmulated("linux/arm64", "linux/arm64"))
+	assert.False(t, isEmulated("linux/arm64", "linux/arm64/v8"))
+	assert.False(t, isEmulated("linux/arm/v7", "linux/arm/v6"))
+	assert.True(t, isEmulated("linux/arm64", "linux/amd64"))
+	assert.True(t, isEmulated("linux/amd64", "linux/arm64/v8"))
+}
+
+func TestDockerPlatformIsDetectedOnce(t *testing.T) {
+	fake := &fakePlatforms{enginePlatform: "linux/arm64"}
+	g := &Gateway{docker: fake}
+
+	assert.Equal(t, "linux/arm64", g.dockerPlatform(t.Context()))
+
+	fake.enginePlatform = "linux/amd64"
+	assert.Equal(t, "linux/arm64", g.dockerPlatform(t.Context()))
+}
+
+func TestCheckEmulation(t *testing.T) {
+	g := &Gateway{docker: &fakePlatforms{
+		enginePlatform: "linux/arm64",
+		images: map[string]image.InspectResponse{
+			"mcp/amd64-only": {Os: "linux", Architecture: "amd64"},
+			"mcp/multi-arch": {Os: "linux", Architecture: "arm64", Variant: "v8"},
+			"mcp/not-pulled": {},
+		},
+	}}
+
+	assert.True(t, g.checkEmulation(t.Context(), "mcp/amd64-only", []string{"amd64-only"}))
+	assert.False(t, g.checkEmulation(t.Context(), "mcp/multi-arch", []string{"multi-arch"}))
+	assert.False(t, g.checkEmulation(t.Context(), "mcp/not-pulled", []string{"not-pulled"}))
+}
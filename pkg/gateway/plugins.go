@@ -0,0 +1,34 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/plugins"
+)
+
+// newPluginClient returns a client of a server compiled in the gateway, that runs in-process.
+func newPluginClient(serverConfig *catalog.ServerConfig) (mcpclient.Client, error) {
+	plugin, found := plugins.Lookup(serverConfig.Name)
+	if !found {
+		return nil, fmt.Errorf("server %s is a plugin that isn't compiled in this gateway", serverConfig.Name)
+	}
+
+	// Only the config and the secrets of the server are passed to the plugin
+	config, _ := serverConfig.Config[oci.CanonicalizeServerName(serverConfig.Name)].(map[string]any)
+	secrets := map[string]string{}
+	for _, secret := range serverConfig.Spec.Secrets {
+		if value, found := serverConfig.Secrets[secret.Name]; found {
+			secrets[secret.Name] = value
+		}
+	}
+
+	return mcpclient.NewInProcessMCPClient(serverConfig.Name, func(ctx context.Context) (*mcp.Server, error) {
+		return plugin.NewServer(ctx, config, secrets)
+	}), nil
+}
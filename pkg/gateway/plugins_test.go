@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/plugins"
+)
+
+type greeterPlugin struct{}
+
+func (greeterPlugin) Definition() catalog.Server {
+	return catalog.Server{
+		Description: "Greets people",
+		Secrets:     []catalog.Secret{{Name: "greeter.token", Env: "GREETER_TOKEN"}},
+	}
+}
+
+func (greeterPlugin) NewServer(_ context.Context, config map[string]any, secrets map[string]string) (*mcp.Server, error) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "greeter"}, nil)
+	server.AddTool(&mcp.Tool{Name: "greet", InputSchema: map[string]any{"type": "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		text := config["greeting"].(string) + " (" + secrets["greeter.token"] + ")"
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil
+	})
+	return server, nil
+}
+
+func TestPluginClient(t *testing.T) {
+	plugins.Register("test-greeter", greeterPlugin{})
+
+	configuration := Configuration{
+		serverNames: []string{"test-greeter"},
+		servers:     catalog.WithPluginServers(catalog.Catalog{Servers: map[string]catalog.Server{}}).Servers,
+		config:      map[string]map[string]any{"test-greeter": {"greeting": "Hello"}},
+		secrets:     map[string]string{"greeter.token": "t0k3n", "other.token": "secret"},
+	}
+	serverConfig, _, found := configuration.Find("test-greeter")
+	require.True(t, found)
+	require.NotNil(t, serverConfig)
+
+	client, err := newPluginClient(serverConfig)
+	require.NoError(t, err)
+	require.NoError(t, client.Initialize(t.Context(), &mcp.InitializeParams{}, false, nil, nil, nil))
+	defer client.Session().Close()
+
+	result, err := client.Session().CallTool(t.Context(), &mcp.CallToolParams{Name: "greet"})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "Hello (t0k3n)", result.Content[0].(*mcp.TextContent).Text)
+
+	g := &Gateway{}
+	assert.Equal(t, RuntimeInProcess, g.toolExecution(serverConfig, client, nil, 0).Runtime)
+}
+
+func TestPluginClientNotCompiledIn(t *testing.T) {
+	_, err := newPluginClient(&catalog.ServerConfig{Name: "test-missing", Spec: catalog.Server{Type: catalog.TypePlugin}})
+	require.ErrorContains(t, err, "isn't compiled in")
+}
@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/notify"
+	"github.com/docker/mcp-gateway/pkg/policy"
+)
+
+// policyOverride grants access to a server outside of its access windows, until it expires.
+type policyOverride struct {
+	Until  time.Time
+	Reason string
+}
+
+// policyMiddleware denies the tool calls that the access policy doesn't allow.
+func (g *Gateway) policyMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			if result := g.checkToolPolicy(callReq.Params.Name, requestIdentity(callReq.Extra)); result != nil {
+				return result, nil
+			}
+
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// checkToolPolicy returns a non-nil result, to send back instead of calling the tool, when the policy denies the call.
+// The identity is the client authenticated by the identity provider, if any.
+func (g *Gateway) checkToolPolicy(toolName string, identity *policy.Identity) *mcp.CallToolResult {
+	g.capabilitiesMu.RLock()
+	toolReg, found := g.toolRegistrations[toolName]
+	g.capabilitiesMu.RUnlock()
+	if !found || toolReg.ServerName == "" {
+		return nil
+	}
+
+	if err := g.toolPolicyError(toolReg.ServerName, toolReg.serverToolName(toolName), identity); err != nil {
+		log.Logf("  ! Denied call to %s: %s", toolName, err)
+		g.emit(notify.Event{
+			Type:    notify.EventPolicyDenied,
+			Server:  toolReg.ServerName,
+			Message: fmt.Sprintf("Call to %s denied: %s", toolName, err),
+			Details: map[string]string{"tool": toolName},
+		})
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: fmt.Sprintf("The call to %s was denied by the gateway's policy: %s.", toolName, err),
+			}},
+			IsError: true,
+		}
+	}
+
+	return nil
+}
+
+// toolPolicyError returns why the policy denies a client to call a tool of a server, or nil if it allows the call.
+// The tool is named as on its server.
+func (g *Gateway) toolPolicyError(serverName, toolName string, identity *policy.Identity) error {
+	if err := g.checkClientProfile(serverName, toolName, identity); err != nil {
+		return err
+	}
+	if err := g.policy.CheckIdentity(serverName, identity); err != nil {
+		return err
+	}
+	return g.checkServerPolicy(serverName, time.Now())
+}
+
+// checkServerPolicy checks whether a server can be called at the given time, taking on-call overrides into account.
+func (g *Gateway) checkServerPolicy(serverName string, now time.Time) error {
+	err := g.policy.Check(serverName, now)
+	if err == nil {
+		return nil
+	}
+
+	if g.policy.AllowsOverride(serverName) {
+		g.policyMu.RLock()
+		override, found := g.policyOverrides[serverName]
+		g.policyMu.RUnlock()
+
+		if found && now.Before(override.Until) {
+			log.Logf("  > Access to %s granted by an on-call override until %s (%s)", serverName, override.Until.Format(time.RFC3339), override.Reason)
+			return nil
+		}
+	}
+
+	return err
+}
+
+// grantPolicyOverride grants access to a server outside of its access windows for some time.
+func (g *Gateway) grantPolicyOverride(serverName string, duration time.Duration, reason string) (time.Time, error) {
+	if !g.policy.AllowsOverride(serverName) {
+		return time.Time{}, fmt.Errorf("the policy doesn't allow overrides for server %s", serverName)
+	}
+	if duration <= 0 {
+		return time.Time{}, fmt.Errorf("invalid override duration %s", duration)
+	}
+
+	until := time.Now().Add(duration)
+
+	g.policyMu.Lock()
+	if g.policyOverrides == nil {
+		g.policyOverrides = make(map[string]policyOverride)
+	}
+	g.policyOverrides[serverName] = policyOverride{Until: until, Reason: reason}
+	g.policyMu.Unlock()
+
+	log.Logf("- On-call override granted for %s until %s: %s", serverName, until.Format(time.RFC3339), reason)
+	return until, nil
+}
@@ -0,0 +1,90 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/policy"
+)
+
+func newPolicyGateway(allowOverride bool) *Gateway {
+	return &Gateway{
+		policy: &policy.Policy{
+			Servers: map[string]policy.ServerPolicy{
+				"postgres-prod": {
+					AccessWindows: []policy.TimeWindow{{Days: []string{"mon", "tue", "wed", "thu", "fri"}, From: "09:00", To: "18:00"}},
+					Timezone:      "UTC",
+					AllowOverride: allowOverride,
+				},
+			},
+		},
+		toolRegistrations: map[string]ToolRegistration{
+			"query":  {ServerName: "postgres-prod"},
+			"search": {ServerName: "github"},
+		},
+	}
+}
+
+func TestCheckToolPolicyUnrestricted(t *testing.T) {
+	g := newPolicyGateway(false)
+
+	assert.Nil(t, g.checkToolPolicy("search", nil))
+	assert.Nil(t, g.checkToolPolicy("unknown", nil))
+}
+
+func TestCheckToolPolicyIdentity(t *testing.T) {
+	g := &Gateway{
+		policy: &policy.Policy{
+			Servers: map[string]policy.ServerPolicy{
+				"postgres-prod": {AllowedGroups: []string{"dba"}},
+			},
+		},
+		toolRegistrations: map[string]ToolRegistration{
+			"query": {ServerName: "postgres-prod"},
+		},
+	}
+
+	assert.Nil(t, g.checkToolPolicy("query", nil))
+	assert.Nil(t, g.checkToolPolicy("query", &policy.Identity{Subject: "alice", Groups: []string{"dba"}}))
+
+	result := g.checkToolPolicy("query", &policy.Identity{Subject: "bob", Email: "bob@example.com"})
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "bob@example.com isn't allowed to call postgres-prod")
+}
+
+func TestPolicyOverride(t *testing.T) {
+	g := newPolicyGateway(true)
+	saturday := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	require.Error(t, g.checkServerPolicy("postgres-prod", saturday))
+
+	g.policyOverrides = map[string]policyOverride{
+		"postgres-prod": {Until: saturday.Add(time.Hour), Reason: "incident 1234"},
+	}
+	require.NoError(t, g.checkServerPolicy("postgres-prod", saturday))
+	require.Error(t, g.checkServerPolicy("postgres-prod", saturday.Add(2*time.Hour)))
+}
+
+func TestGrantPolicyOverride(t *testing.T) {
+	g := newPolicyGateway(true)
+
+	until, err := g.grantPolicyOverride("postgres-prod", time.Hour, "incident 1234")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), until, time.Minute)
+	assert.Equal(t, "incident 1234", g.policyOverrides["postgres-prod"].Reason)
+
+	_, err = g.grantPolicyOverride("postgres-prod", 0, "incident 1234")
+	require.Error(t, err)
+}
+
+func TestGrantPolicyOverrideNotAllowed(t *testing.T) {
+	g := newPolicyGateway(false)
+
+	_, err := g.grantPolicyOverride("postgres-prod", time.Hour, "incident 1234")
+	require.Error(t, err)
+}
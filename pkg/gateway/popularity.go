@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// popularityCheckInterval is how often the gateway checks whether the popularity of the catalog servers is stale.
+const popularityCheckInterval = time.Hour
+
+// refreshPopularity keeps the pulls and stars of the catalog servers up to date, for mcp-find to rank them.
+// The popularity is shared with the other gateways and with `docker mcp catalog show --sort popularity`.
+func (g *Gateway) refreshPopularity(ctx context.Context, servers map[string]catalog.Server) {
+	cache, err := catalog.ReadPopularity()
+	if err != nil {
+		log.Log("  ! Reading the popularity of the servers:", err)
+		cache = catalog.PopularityCache{Servers: map[string]catalog.Popularity{}}
+	}
+	g.setPopularity(cache)
+
+	ticker := time.NewTicker(popularityCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if cache.Stale() {
+			cache = catalog.RefreshPopularity(ctx, cache, servers)
+			if ctx.Err() != nil {
+				return
+			}
+			g.setPopularity(cache)
+			if err := catalog.WritePopularity(cache); err != nil {
+				log.Log("  ! Caching the popularity of the servers:", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (g *Gateway) setPopularity(cache catalog.PopularityCache) {
+	g.popularityMu.Lock()
+	defer g.popularityMu.Unlock()
+
+	g.popularity = cache
+}
+
+// withPopularity returns the servers with their metadata updated from the refreshed popularity.
+func (g *Gateway) withPopularity(servers map[string]catalog.Server) map[string]catalog.Server {
+	g.popularityMu.RLock()
+	defer g.popularityMu.RUnlock()
+
+	if len(g.popularity.Servers) == 0 {
+		return servers
+	}
+	updated := maps.Clone(servers)
+	g.popularity.Apply(updated)
+	return updated
+}
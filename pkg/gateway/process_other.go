@@ -0,0 +1,20 @@
+//go:build !windows
+// +build !windows
+
+package gateway
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// processRunning tells whether a process is running on this host.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
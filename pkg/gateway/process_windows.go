@@ -0,0 +1,13 @@
+package gateway
+
+import "os"
+
+// processRunning tells whether a process is running on this host.
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = process.Release()
+	return true
+}
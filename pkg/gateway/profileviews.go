@@ -0,0 +1,241 @@
+package gateway
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yosida95/uritemplate/v3"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/policy"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+// profileView is what a profile exposes to the clients mapped to it by the policy:
+// the tools enabled for each of its servers, nil meaning all of them.
+type profileView map[string][]string
+
+func newProfileView(workingSet workingset.WorkingSet) profileView {
+	view := profileView{}
+	for _, server := range workingSet.Servers {
+		if server.Snapshot == nil {
+			continue
+		}
+		view[server.Snapshot.Server.Name] = server.Tools
+	}
+	return view
+}
+
+func (v profileView) allowsServer(serverName string) bool {
+	_, found := v[serverName]
+	return found
+}
+
+// allowsTool reports whether the profile exposes a tool of a server, named as on the server.
+func (v profileView) allowsTool(serverName, toolName string) bool {
+	tools, found := v[serverName]
+	if !found {
+		return false
+	}
+	return tools == nil || slices.Contains(tools, toolName)
+}
+
+// loadProfileViews reads the profiles the policy maps the authenticated clients to.
+// The servers of those profiles must also be enabled in the gateway.
+func (g *Gateway) loadProfileViews(ctx context.Context) error {
+	if !g.policy.HasProfiles() {
+		return nil
+	}
+
+	dao, err := db.New()
+	if err != nil {
+		return fmt.Errorf("failed to create database client: %w", err)
+	}
+	defer dao.Close()
+
+	profileNames := g.policy.ProfileNames()
+	views := make(map[string]profileView, len(profileNames))
+	for _, profileName := range profileNames {
+		dbWorkingSet, err := dao.GetWorkingSet(ctx, profileName)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("profile %s of the policy not found", profileName)
+			}
+			return fmt.Errorf("failed to get profile %s: %w", profileName, err)
+		}
+
+		view := newProfileView(workingset.NewFromDb(dbWorkingSet))
+		for serverName := range view {
+			if !slices.Contains(g.configuration.ServerNames(), serverName) {
+				log.Logf("  ! Server %s of profile %s isn't enabled in the gateway", serverName, profileName)
+			}
+		}
+		views[profileName] = view
+	}
+	g.profileViews = views
+
+	log.Log("- Clients mapped to profiles:", strings.Join(profileNames, ", "))
+	return nil
+}
+
+// clientProfile returns the view of the profile of an authenticated client, and false if the client
+// isn't restricted to a profile. Clients that match no profile get an empty view.
+func (g *Gateway) clientProfile(identity *policy.Identity) (profileView, bool) {
+	if identity == nil || !g.policy.HasProfiles() {
+		return nil, false
+	}
+	return g.profileViews[g.policy.ProfileFor(identity)], true
+}
+
+// checkClientProfile returns an error if the tool of a server isn't in the profile of the client.
+// With an empty tool name, only the server is checked.
+func (g *Gateway) checkClientProfile(serverName, toolName string, identity *policy.Identity) error {
+	view, restricted := g.clientProfile(identity)
+	if !restricted {
+		return nil
+	}
+	if toolName == "" {
+		if _, found := view[serverName]; found {
+			return nil
+		}
+		return fmt.Errorf("%s isn't in the profile of %s", serverName, identity)
+	}
+	if view.allowsTool(serverName, toolName) {
+		return nil
+	}
+	return fmt.Errorf("%s isn't in the profile of %s", toolName, identity)
+}
+
+// checkClientServer returns a non-nil result, to send back instead of acting on the server, when a dynamic tool
+// is asked to act on a server outside of the profile of the client. The gateway and its servers are shared by
+// all the clients, so those restricted to a profile can't add, remove or configure the servers of the others.
+func (g *Gateway) checkClientServer(req *mcp.CallToolRequest, serverName string) *mcp.CallToolResult {
+	if err := g.checkClientProfile(serverName, "", requestIdentity(req.Extra)); err != nil {
+		log.Logf("  ! Denied %s on %s: %s", req.Params.Name, serverName, err)
+		return textResult(fmt.Sprintf("Error: Server '%s' isn't in your profile.", serverName))
+	}
+	return nil
+}
+
+// profileMiddleware only lists, to the clients mapped to a profile, the capabilities of its servers
+// and the gateway's own. Their tool calls are checked along with the rest of the policy.
+func (g *Gateway) profileMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			view, restricted := g.clientProfile(requestIdentity(req.GetExtra()))
+			if !restricted {
+				return next(ctx, method, req)
+			}
+
+			switch req := req.(type) {
+			case *mcp.GetPromptRequest:
+				if req.Params != nil && !g.profileAllowsPrompt(view, req.Params.Name) {
+					return nil, fmt.Errorf("prompt %s isn't in your profile", req.Params.Name)
+				}
+			case *mcp.ReadResourceRequest:
+				if req.Params != nil && !g.profileAllowsResource(view, req.Params.URI) {
+					return nil, mcp.ResourceNotFoundError(req.Params.URI)
+				}
+			}
+
+			result, err := next(ctx, method, req)
+			if err != nil {
+				return result, err
+			}
+
+			switch result := result.(type) {
+			case *mcp.ListToolsResult:
+				result.Tools = slices.DeleteFunc(slices.Clone(result.Tools), func(tool *mcp.Tool) bool {
+					return !g.profileAllowsTool(view, tool.Name)
+				})
+			case *mcp.ListPromptsResult:
+				result.Prompts = slices.DeleteFunc(slices.Clone(result.Prompts), func(prompt *mcp.Prompt) bool {
+					return !g.profileAllowsPrompt(view, prompt.Name)
+				})
+			case *mcp.ListResourcesResult:
+				result.Resources = slices.DeleteFunc(slices.Clone(result.Resources), func(resource *mcp.Resource) bool {
+					return !g.profileAllowsResource(view, resource.URI)
+				})
+			case *mcp.ListResourceTemplatesResult:
+				result.ResourceTemplates = slices.DeleteFunc(slices.Clone(result.ResourceTemplates), func(template *mcp.ResourceTemplate) bool {
+					return !g.profileAllowsResourceTemplate(view, template.URITemplate)
+				})
+			}
+			return result, nil
+		}
+	}
+}
+
+func (g *Gateway) profileAllowsTool(view profileView, toolName string) bool {
+	g.capabilitiesMu.RLock()
+	toolReg, found := g.toolRegistrations[toolName]
+	g.capabilitiesMu.RUnlock()
+
+	// The gateway's own tools are always listed
+	if !found || toolReg.ServerName == "" {
+		return true
+	}
+	return view.allowsTool(toolReg.ServerName, toolReg.serverToolName(toolName))
+}
+
+func (g *Gateway) profileAllowsPrompt(view profileView, promptName string) bool {
+	serverName, found := g.capabilityOwner(func(caps *ServerCapabilities) bool {
+		return slices.Contains(caps.PromptNames, promptName)
+	})
+	if !found {
+		return promptName == projectServersPrompt
+	}
+	return view.allowsServer(serverName)
+}
+
+// profileAllowsResource reports whether a resource is in the profile. The resources read through
+// a resource template belong to the server of the template.
+func (g *Gateway) profileAllowsResource(view profileView, uri string) bool {
+	serverName, found := g.capabilityOwner(func(caps *ServerCapabilities) bool {
+		return slices.Contains(caps.ResourceURIs, uri)
+	})
+	if !found {
+		serverName, found = g.capabilityOwner(func(caps *ServerCapabilities) bool {
+			return slices.ContainsFunc(caps.ResourceTemplateURIs, func(uriTemplate string) bool {
+				return matchesURITemplate(uriTemplate, uri)
+			})
+		})
+	}
+	return found && view.allowsServer(serverName)
+}
+
+func (g *Gateway) profileAllowsResourceTemplate(view profileView, uriTemplate string) bool {
+	serverName, found := g.capabilityOwner(func(caps *ServerCapabilities) bool {
+		return slices.Contains(caps.ResourceTemplateURIs, uriTemplate)
+	})
+	return found && view.allowsServer(serverName)
+}
+
+// capabilityOwner returns the server that registered a capability, or false if no server did.
+// The gateway has no resources of its own, and its only prompt is projectServersPrompt.
+func (g *Gateway) capabilityOwner(registered func(*ServerCapabilities) bool) (string, bool) {
+	g.capabilitiesMu.RLock()
+	defer g.capabilitiesMu.RUnlock()
+
+	for serverName, caps := range g.serverCapabilities {
+		if registered(caps) {
+			return serverName, true
+		}
+	}
+	return "", false
+}
+
+// matchesURITemplate reports whether a URI can be read through a resource template, the way the MCP server matches them.
+func matchesURITemplate(uriTemplate, uri string) bool {
+	tmpl, err := uritemplate.New(uriTemplate)
+	if err != nil {
+		return false
+	}
+	return tmpl.Regexp().MatchString(uri)
+}
@@ -0,0 +1,282 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/policy"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+)
+
+func TestNewProfileView(t *testing.T) {
+	view := newProfileView(workingset.WorkingSet{
+		Servers: []workingset.Server{
+			{Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "github"}}},
+			{Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "postgres"}}, Tools: []string{"query"}},
+			{Image: "mcp/missing:latest"},
+		},
+	})
+
+	assert.Equal(t, profileView{"github": nil, "postgres": {"query"}}, view)
+	assert.True(t, view.allowsTool("github", "create_issue"))
+	assert.True(t, view.allowsTool("postgres", "query"))
+	assert.False(t, view.allowsTool("postgres", "db:query"))
+	assert.False(t, view.allowsTool("postgres", "drop"))
+	assert.False(t, view.allowsTool("fetch", "fetch"))
+}
+
+func newProfileTestGateway() *Gateway {
+	return &Gateway{
+		policy: &policy.Policy{
+			Profiles: []policy.ProfileRule{{Groups: []string{"dba"}, Profile: "data"}},
+		},
+		profileViews: map[string]profileView{
+			"data": {"postgres": {"query"}},
+		},
+		toolRegistrations: map[string]ToolRegistration{
+			"query":        {ServerName: "postgres"},
+			"drop":         {ServerName: "postgres"},
+			"create_issue": {ServerName: "github"},
+			"mcp-find":     {},
+		},
+		serverCapabilities: map[string]*ServerCapabilities{
+			"postgres": {ToolNames: []string{"query", "drop"}, PromptNames: []string{"explain"}},
+			"github": {
+				ToolNames:            []string{"create_issue"},
+				PromptNames:          []string{"review"},
+				ResourceURIs:         []string{"github://repos"},
+				ResourceTemplateURIs: []string{"github://repos/{owner}/{repo}"},
+			},
+		},
+	}
+}
+
+func identityExtra(identity *policy.Identity) *mcp.RequestExtra {
+	return &mcp.RequestExtra{TokenInfo: &auth.TokenInfo{Extra: map[string]any{tokenIdentityKey: identity}}}
+}
+
+// callAsProfileClient calls a tool as a client authenticated as a member of the dba group, restricted to the data profile.
+func callAsProfileClient(t *testing.T, tool *ToolRegistration, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	telemetry.Init()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddTool(tool.Tool, tool.Handler)
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if call, ok := req.(*mcp.CallToolRequest); ok {
+				call.Extra = identityExtra(&policy.Identity{Subject: "alice", Groups: []string{"dba"}})
+			}
+			return next(ctx, method, req)
+		}
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: tool.Tool.Name, Arguments: arguments})
+	require.NoError(t, err)
+	return result
+}
+
+func TestDynamicToolsProfile(t *testing.T) {
+	newGateway := func() *Gateway {
+		g := newProfileTestGateway()
+		g.configuration = Configuration{
+			serverNames: []string{"github", "postgres"},
+			servers: map[string]catalog.Server{
+				"github":   {Name: "github", Image: "mcp/github"},
+				"postgres": {Name: "postgres", Image: "mcp/postgres"},
+			},
+			config: map[string]map[string]any{},
+		}
+		return g
+	}
+	isDenied := func(t *testing.T, result *mcp.CallToolResult) {
+		t.Helper()
+		assert.Equal(t, "Error: Server 'github' isn't in your profile.", result.Content[0].(*mcp.TextContent).Text)
+	}
+
+	t.Run("mcp-add", func(t *testing.T) {
+		g := newGateway()
+		g.configuration.serverNames = []string{"postgres"}
+
+		isDenied(t, callAsProfileClient(t, g.createMcpAddTool(nil), map[string]any{"name": "github"}))
+		assert.Equal(t, []string{"postgres"}, g.configuration.serverNames)
+	})
+
+	t.Run("mcp-remove", func(t *testing.T) {
+		g := newGateway()
+
+		isDenied(t, callAsProfileClient(t, g.createMcpRemoveTool(), map[string]any{"name": "github"}))
+		assert.Equal(t, []string{"github", "postgres"}, g.configuration.serverNames)
+	})
+
+	t.Run("mcp-config-set", func(t *testing.T) {
+		g := newGateway()
+
+		isDenied(t, callAsProfileClient(t, g.createMcpConfigSetTool(nil), map[string]any{"server": "github", "key": "org", "value": "docker"}))
+		assert.Empty(t, g.configuration.config)
+	})
+
+	t.Run("code-mode", func(t *testing.T) {
+		g := newGateway()
+
+		isDenied(t, callAsProfileClient(t, g.createCodeModeTool(nil), map[string]any{"servers": []string{"postgres", "github"}, "name": "all"}))
+	})
+
+	t.Run("mcp-registry-import", func(t *testing.T) {
+		g := newGateway()
+		registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"name":"github","image":"attacker/github"}`))
+		}))
+		defer registry.Close()
+
+		isDenied(t, callAsProfileClient(t, g.createMcpRegistryImportTool(g.configuration, nil), map[string]any{"url": registry.URL}))
+		assert.Equal(t, "mcp/github", g.configuration.servers["github"].Image)
+	})
+
+	t.Run("servers of the profile", func(t *testing.T) {
+		g := newGateway()
+		req := &mcp.CallToolRequest{
+			Params: &mcp.CallToolParamsRaw{Name: "mcp-config-set"},
+			Extra:  identityExtra(&policy.Identity{Subject: "alice", Groups: []string{"dba"}}),
+		}
+
+		assert.Nil(t, g.checkClientServer(req, "postgres"))
+		assert.NotNil(t, g.checkClientServer(req, "github"))
+		assert.Nil(t, g.checkClientServer(&mcp.CallToolRequest{Params: req.Params}, "github"))
+	})
+}
+
+func TestProfileMiddlewareFiltersLists(t *testing.T) {
+	g := newProfileTestGateway()
+	handler := g.profileMiddleware()(func(_ context.Context, method string, _ mcp.Request) (mcp.Result, error) {
+		switch method {
+		case "tools/list":
+			return &mcp.ListToolsResult{Tools: []*mcp.Tool{{Name: "query"}, {Name: "drop"}, {Name: "create_issue"}, {Name: "mcp-find"}}}, nil
+		case "prompts/list":
+			return &mcp.ListPromptsResult{Prompts: []*mcp.Prompt{{Name: "explain"}, {Name: "review"}}}, nil
+		case "resources/list":
+			return &mcp.ListResourcesResult{Resources: []*mcp.Resource{{URI: "github://repos"}}}, nil
+		}
+		return &mcp.GetPromptResult{}, nil
+	})
+
+	dba := identityExtra(&policy.Identity{Subject: "alice", Groups: []string{"dba"}})
+
+	result, err := handler(t.Context(), "tools/list", &mcp.ListToolsRequest{Extra: dba})
+	require.NoError(t, err)
+	var tools []string
+	for _, tool := range result.(*mcp.ListToolsResult).Tools {
+		tools = append(tools, tool.Name)
+	}
+	assert.Equal(t, []string{"query", "mcp-find"}, tools)
+
+	result, err = handler(t.Context(), "prompts/list", &mcp.ListPromptsRequest{Extra: dba})
+	require.NoError(t, err)
+	require.Len(t, result.(*mcp.ListPromptsResult).Prompts, 1)
+	assert.Equal(t, "explain", result.(*mcp.ListPromptsResult).Prompts[0].Name)
+
+	result, err = handler(t.Context(), "resources/list", &mcp.ListResourcesRequest{Extra: dba})
+	require.NoError(t, err)
+	assert.Empty(t, result.(*mcp.ListResourcesResult).Resources)
+
+	_, err = handler(t.Context(), "prompts/get", &mcp.GetPromptRequest{Extra: dba, Params: &mcp.GetPromptParams{Name: "review"}})
+	require.ErrorContains(t, err, "prompt review isn't in your profile")
+
+	// Clients that match no rule, without a default profile, only see the gateway's own tools
+	result, err = handler(t.Context(), "tools/list", &mcp.ListToolsRequest{Extra: identityExtra(&policy.Identity{Subject: "bob"})})
+	require.NoError(t, err)
+	require.Len(t, result.(*mcp.ListToolsResult).Tools, 1)
+	assert.Equal(t, "mcp-find", result.(*mcp.ListToolsResult).Tools[0].Name)
+
+	// Clients that aren't authenticated by the identity provider aren't restricted
+	result, err = handler(t.Context(), "tools/list", &mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, result.(*mcp.ListToolsResult).Tools, 4)
+}
+
+func TestProfileAllowsResources(t *testing.T) {
+	g := newProfileTestGateway()
+	g.serverCapabilities["postgres"].ResourceTemplateURIs = []string{"postgres://tables/{table}"}
+	view := g.profileViews["data"]
+
+	assert.False(t, g.profileAllowsResource(view, "github://repos"))
+	assert.True(t, g.profileAllowsResource(view, "postgres://tables/users"))
+	assert.True(t, g.profileAllowsResourceTemplate(view, "postgres://tables/{table}"))
+	assert.False(t, g.profileAllowsResourceTemplate(view, "github://repos/{owner}/{repo}"))
+
+	// Resources read through the template of a server outside of the profile, or that no server owns, are denied
+	assert.False(t, g.profileAllowsResource(view, "github://repos/docker/mcp-gateway"))
+	assert.False(t, g.profileAllowsResource(view, "unknown://resource"))
+
+	// The gateway's own prompt is always allowed, unlike unknown prompts
+	assert.True(t, g.profileAllowsPrompt(view, projectServersPrompt))
+	assert.True(t, g.profileAllowsPrompt(view, "explain"))
+	assert.False(t, g.profileAllowsPrompt(view, "review"))
+	assert.False(t, g.profileAllowsPrompt(view, "unknown"))
+}
+
+func TestProfileMiddlewareReadsResources(t *testing.T) {
+	g := newProfileTestGateway()
+	handler := g.profileMiddleware()(func(context.Context, string, mcp.Request) (mcp.Result, error) {
+		return &mcp.ReadResourceResult{}, nil
+	})
+	dba := identityExtra(&policy.Identity{Subject: "alice", Groups: []string{"dba"}})
+
+	_, err := handler(t.Context(), "resources/read", &mcp.ReadResourceRequest{Extra: dba, Params: &mcp.ReadResourceParams{URI: "github://repos/docker/mcp-gateway"}})
+	require.Error(t, err)
+
+	_, err = handler(t.Context(), "resources/read", &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: "github://repos/docker/mcp-gateway"}})
+	require.NoError(t, err)
+}
+
+func TestProfileAllowsPrefixedTools(t *testing.T) {
+	g := newProfileTestGateway()
+	g.toolRegistrations["db:query"] = ToolRegistration{ServerName: "postgres", ToolName: "query"}
+	// A tool whose name in the gateway ends with the name of a tool of the profile
+	g.toolRegistrations["db:admin:query"] = ToolRegistration{ServerName: "postgres", ToolName: "admin:query"}
+	view := g.profileViews["data"]
+	dba := &policy.Identity{Subject: "alice", Groups: []string{"dba"}}
+
+	assert.True(t, g.profileAllowsTool(view, "db:query"))
+	assert.Nil(t, g.checkToolPolicy("db:query", dba))
+
+	assert.False(t, g.profileAllowsTool(view, "db:admin:query"))
+	assert.NotNil(t, g.checkToolPolicy("db:admin:query", dba))
+}
+
+func TestCheckToolPolicyProfile(t *testing.T) {
+	g := newProfileTestGateway()
+	dba := &policy.Identity{Subject: "alice", Groups: []string{"dba"}}
+
+	assert.Nil(t, g.checkToolPolicy("query", dba))
+	assert.Nil(t, g.checkToolPolicy("mcp-find", dba))
+	assert.Nil(t, g.checkToolPolicy("create_issue", nil))
+
+	result := g.checkToolPolicy("create_issue", dba)
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "create_issue isn't in the profile of alice")
+
+	assert.NotNil(t, g.checkToolPolicy("drop", dba))
+
+	// Without a tool, only the server is checked
+	require.NoError(t, g.checkClientProfile("postgres", "", dba))
+	require.ErrorContains(t, g.checkClientProfile("github", "", dba), "github isn't in the profile of alice")
+	require.NoError(t, g.checkClientProfile("github", "", nil))
+}
@@ -0,0 +1,239 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/notify"
+)
+
+const (
+	// AutoEnableOff doesn't look at the client's roots.
+	AutoEnableOff = "off"
+	// AutoEnableSuggest logs the servers that match the client's projects, and lists them in the project-servers prompt.
+	AutoEnableSuggest = "suggest"
+	// AutoEnableOn enables the servers that match the client's projects when a session starts.
+	AutoEnableOn = "enable"
+)
+
+var AutoEnableModes = []string{AutoEnableOff, AutoEnableSuggest, AutoEnableOn}
+
+func (o Options) detectsProjects() bool {
+	return o.AutoEnable == AutoEnableSuggest || o.AutoEnable == AutoEnableOn
+}
+
+// projectType maps the files found at the root of a project to the catalog servers that help working on it.
+type projectType struct {
+	name    string
+	markers []string
+	servers []string
+}
+
+// projectTypes are the projects that can be detected. Servers that are not in the catalog are ignored.
+var projectTypes = []projectType{
+	{name: "node", markers: []string{"package.json"}, servers: []string{"node-code-sandbox", "npm-sentinel"}},
+	{name: "go", markers: []string{"go.mod"}, servers: []string{"context7"}},
+	{name: "terraform", markers: []string{"*.tf"}, servers: []string{"terraform"}},
+	{name: "docker", markers: []string{"Dockerfile", "compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}, servers: []string{"dockerhub"}},
+}
+
+// detectProjectTypes looks for well known files at the top of the client's roots.
+func detectProjectTypes(roots []*mcp.Root) []string {
+	var detected []string
+	for _, root := range roots {
+		dir, ok := rootPath(root)
+		if !ok {
+			continue
+		}
+
+		for _, project := range projectTypes {
+			if slices.Contains(detected, project.name) {
+				continue
+			}
+			for _, marker := range project.markers {
+				if matches, _ := filepath.Glob(filepath.Join(dir, marker)); len(matches) > 0 {
+					detected = append(detected, project.name)
+					break
+				}
+			}
+		}
+	}
+	return detected
+}
+
+// rootPath returns the local directory of a file:// root.
+func rootPath(root *mcp.Root) (string, bool) {
+	u, err := url.Parse(root.URI)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+	path := u.Path
+	if runtime.GOOS == "windows" {
+		// file:///C:/project
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.FromSlash(path), true
+}
+
+// projectServers lists the catalog servers that match the detected projects and that are not enabled yet.
+func (g *Gateway) projectServers(projects []string) []string {
+	var servers []string
+	for _, project := range projectTypes {
+		if !slices.Contains(projects, project.name) {
+			continue
+		}
+		for _, serverName := range project.servers {
+			if slices.Contains(servers, serverName) || slices.Contains(g.configuration.serverNames, serverName) {
+				continue
+			}
+			if _, _, found := g.configuration.Find(serverName); found {
+				servers = append(servers, serverName)
+			}
+		}
+	}
+	return servers
+}
+
+// sessionRoots returns the roots of a client session, listing them if they're not known yet.
+func (g *Gateway) sessionRoots(ctx context.Context, ss *mcp.ServerSession) []*mcp.Root {
+	if cache := g.GetSessionCache(ss); cache != nil && cache.Roots != nil {
+		return cache.Roots
+	}
+
+	g.ListRoots(ctx, ss)
+	if cache := g.GetSessionCache(ss); cache != nil {
+		return cache.Roots
+	}
+	return nil
+}
+
+// autoEnableServers suggests or enables the servers that match the projects the client works on.
+func (g *Gateway) autoEnableServers(ctx context.Context, ss *mcp.ServerSession) {
+	projects := detectProjectTypes(g.sessionRoots(ctx, ss))
+	if len(projects) == 0 {
+		return
+	}
+
+	servers := g.projectServers(projects)
+	if len(servers) == 0 {
+		return
+	}
+
+	if g.AutoEnable != AutoEnableOn {
+		log.Logf("- Detected %s project, suggested servers: %s", strings.Join(projects, ", "), strings.Join(servers, ", "))
+		return
+	}
+
+	clientConfig := &clientConfig{
+		serverSession: ss,
+		server:        g.mcpServer,
+	}
+	for _, serverName := range servers {
+		if err := g.enableProjectServer(ctx, serverName, clientConfig); err != nil {
+			log.Logf("  ! Can't auto-enable server %s: %v", serverName, err)
+			continue
+		}
+		log.Logf("- Auto-enabled server %s for %s project", serverName, strings.Join(projects, ", "))
+	}
+}
+
+// enableProjectServer adds a catalog server to the session and activates its tools.
+// Servers that need secrets or config are only suggested.
+func (g *Gateway) enableProjectServer(ctx context.Context, serverName string, clientConfig *clientConfig) error {
+	serverConfig, _, found := g.configuration.Find(serverName)
+	if !found || serverConfig == nil {
+		return fmt.Errorf("server not found in catalog")
+	}
+
+	unlock := g.lockServer(serverName)
+	defer unlock()
+
+	// Another session may have enabled it in the meantime
+	if g.isServerEnabled(serverName) {
+		return nil
+	}
+
+	if fbc, ok := g.configurator.(*FileBasedConfiguration); ok {
+		secrets, err := fbc.readDockerDesktopSecrets(ctx, g.configuration.servers, g.enabledServerNames(serverName))
+		if err == nil {
+			g.replaceSecrets(secrets)
+		}
+	}
+	if missing := missingSecrets(serverConfig.Spec, g.configuration.secrets); len(missing) > 0 {
+		return fmt.Errorf("missing secrets: %s", strings.Join(missing, ", "))
+	}
+	if missing := missingConfig(serverName, serverConfig.Spec, g.configuration.config); len(missing) > 0 {
+		return fmt.Errorf("missing config: %s", strings.Join(missing, ", "))
+	}
+
+	if serverConfig.Spec.Image != "" {
+		if err := g.pullImage(ctx, serverConfig.Spec.Image, serverImagePull(serverConfig.Spec), []string{serverName}); err != nil {
+			return err
+		}
+	}
+
+	g.enableServerName(serverName)
+
+	oldCaps, err := g.reloadServerCapabilities(ctx, serverName, clientConfig)
+	if err != nil {
+		return err
+	}
+
+	g.capabilitiesMu.Lock()
+	newCaps := g.allCapabilities(serverName)
+	err = g.updateServerCapabilities(serverName, oldCaps, newCaps, nil)
+	g.capabilitiesMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	g.emit(notify.Event{
+		Type:    notify.EventServerAdded,
+		Server:  serverName,
+		Message: fmt.Sprintf("Server %s auto-enabled", serverName),
+	})
+
+	return nil
+}
+
+// projectServersPrompt is the name of the gateway's own prompt that lists the servers suggested for the client's projects.
+const projectServersPrompt = "project-servers"
+
+// addProjectServersPrompt adds a prompt that lists the servers suggested for the client's projects.
+func (g *Gateway) addProjectServersPrompt() {
+	g.mcpServer.AddPrompt(&mcp.Prompt{
+		Name:        projectServersPrompt,
+		Description: "List the MCP servers suggested for the projects in the client's roots",
+	}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		projects := detectProjectTypes(g.sessionRoots(ctx, req.Session))
+		servers := g.projectServers(projects)
+
+		var text string
+		switch {
+		case len(projects) == 0:
+			text = "No known project type was detected in the client's roots."
+		case len(servers) == 0:
+			text = fmt.Sprintf("Detected %s project. All the suggested MCP servers are already enabled, or none is in the catalog.", strings.Join(projects, ", "))
+		default:
+			text = fmt.Sprintf("Detected %s project. These MCP servers from the catalog could help: %s.\nEnable them with the mcp-add tool or `docker mcp server enable`.", strings.Join(projects, ", "), strings.Join(servers, ", "))
+		}
+
+		return &mcp.GetPromptResult{
+			Description: "MCP servers suggested for the client's projects",
+			Messages: []*mcp.PromptMessage{
+				{
+					Role:    "user",
+					Content: &mcp.TextContent{Text: text},
+				},
+			},
+		}, nil
+	})
+}
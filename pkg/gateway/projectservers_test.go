@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func fileRoot(t *testing.T, files ...string) *mcp.Root {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, file := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte{}, 0o644))
+	}
+	return &mcp.Root{URI: (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String()}
+}
+
+func TestDetectProjectTypes(t *testing.T) {
+	assert.Empty(t, detectProjectTypes(nil))
+	assert.Empty(t, detectProjectTypes([]*mcp.Root{fileRoot(t, "README.md")}))
+	assert.Empty(t, detectProjectTypes([]*mcp.Root{{URI: "https://example.com/project"}}))
+
+	assert.Equal(t, []string{"node"}, detectProjectTypes([]*mcp.Root{fileRoot(t, "package.json")}))
+	assert.Equal(t, []string{"terraform"}, detectProjectTypes([]*mcp.Root{fileRoot(t, "main.tf")}))
+	assert.Equal(t, []string{"go", "docker"}, detectProjectTypes([]*mcp.Root{fileRoot(t, "go.mod", "Dockerfile")}))
+	assert.Equal(t, []string{"node", "go"}, detectProjectTypes([]*mcp.Root{fileRoot(t, "package.json"), fileRoot(t, "go.mod", "package.json")}))
+}
+
+func TestProjectServers(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"npm-sentinel"},
+			servers: map[string]catalog.Server{
+				"node-code-sandbox": {Image: "mcp/node-code-sandbox"},
+				"npm-sentinel":      {Image: "mcp/npm-sentinel"},
+				"terraform":         {Image: "hashicorp/terraform-mcp-server"},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"node-code-sandbox"}, g.projectServers([]string{"node"}), "enabled servers are not suggested")
+	assert.Equal(t, []string{"node-code-sandbox", "terraform"}, g.projectServers([]string{"terraform", "node"}))
+	assert.Empty(t, g.projectServers([]string{"go"}), "servers missing from the catalog are not suggested")
+}
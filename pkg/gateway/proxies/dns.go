@@ -18,7 +18,7 @@ import (
 
 const dnsImage = "docker/mcp-dns-forwarder:v1@sha256:a47b7362fdc78dd2cf8779c52ff782312a3758537e635b91529fddabaadbd4dd"
 
-func runDNSForwarder(ctx context.Context, cli docker.Client, target *TargetConfig, extNwName string, keepCtrs bool) (_ string, _ io.ReadCloser, retErr error) {
+func runDNSForwarder(ctx context.Context, cli docker.Client, target *TargetConfig, extNwName string, labels map[string]string, keepCtrs bool) (_ string, _ io.ReadCloser, retErr error) {
 	log.Logf("Running dns forwarder...")
 
 	if err := cli.PullImage(ctx, dnsImage); err != nil {
@@ -55,8 +55,9 @@ func runDNSForwarder(ctx context.Context, cli docker.Client, target *TargetConfi
 
 	if err := cli.StartContainer(ctx, ctrName,
 		container.Config{
-			Image: dnsImage,
-			Env:   []string{"HOSTS_ENTRIES=" + strings.Join(slices.Collect(maps.Values(hostsEntries)), "\n")},
+			Image:  dnsImage,
+			Env:    []string{"HOSTS_ENTRIES=" + strings.Join(slices.Collect(maps.Values(hostsEntries)), "\n")},
+			Labels: proxyLabels(labels, "dns"),
 		},
 		container.HostConfig{},
 		network.NetworkingConfig{
@@ -21,7 +21,7 @@ const l4Image = "docker/mcp-l4proxy:v1@sha256:121b87decc25cda901dbd4ffbd20b116ff
 // each hostname. It updates the target config with the container links to add
 // to the MCP tool. It returns a list of proxy container names, and an error if
 // any.
-func runL4Proxies(ctx context.Context, cli docker.Client, target *TargetConfig, extNwName string, proxies []Proxy, keepCtrs bool) (proxyNames []string, retErr error) {
+func runL4Proxies(ctx context.Context, cli docker.Client, target *TargetConfig, extNwName string, proxies []Proxy, labels map[string]string, keepCtrs bool) (proxyNames []string, retErr error) {
 	if len(proxies) == 0 {
 		return nil, nil
 	}
@@ -56,7 +56,7 @@ func runL4Proxies(ctx context.Context, cli docker.Client, target *TargetConfig,
 		}
 
 		proxyName := "docker-mcp-l4proxy-" + randString()
-		if err := runL4Proxy(ctx, cli, proxyName, proxy.Hostname, target.NetworkName, extNwName, toProxy, keepCtrs); err != nil {
+		if err := runL4Proxy(ctx, cli, proxyName, proxy.Hostname, target.NetworkName, extNwName, toProxy, labels, keepCtrs); err != nil {
 			return nil, fmt.Errorf("running l4 proxy %s: %w", proxyName, err)
 		}
 
@@ -72,7 +72,7 @@ func runL4Proxies(ctx context.Context, cli docker.Client, target *TargetConfig,
 
 // runL4Proxy starts an L4 proxy container for a given hostname and a list of
 // ports. It returns an error if the container fails to start.
-func runL4Proxy(ctx context.Context, cli docker.Client, proxyName, hostname, intNwName, extNwName string, ports []uint16, keepCtrs bool) error {
+func runL4Proxy(ctx context.Context, cli docker.Client, proxyName, hostname, intNwName, extNwName string, ports []uint16, labels map[string]string, keepCtrs bool) error {
 	portsStr := strings.Join(sliceutil.Map(ports, func(p uint16) string {
 		return strconv.Itoa(int(p))
 	}), ",")
@@ -86,11 +86,7 @@ func runL4Proxy(ctx context.Context, cli docker.Client, proxyName, hostname, int
 				"PROXY_HOSTNAME=" + hostname,
 				"PROXY_PORTS=" + portsStr,
 			},
-			Labels: map[string]string{
-				"docker-mcp":            "true",
-				"docker-mcp-proxy":      "true",
-				"docker-mcp-proxy-type": "l4",
-			},
+			Labels: proxyLabels(labels, "l4"),
 		},
 		container.HostConfig{
 			NetworkMode: container.NetworkMode(intNwName),
@@ -19,7 +19,7 @@ const l7Image = "docker/mcp-l7proxy:v1@sha256:ef8fd775fdf8ad060af897018c0db3c522
 
 // runL7Proxy starts a single L7 proxy for all the allowed hosts. It returns
 // the proxy container name and a list of links to add to the MCP tool.
-func runL7Proxy(ctx context.Context, cli docker.Client, target *TargetConfig, extNwName string, proxies []Proxy, keepCtrs bool) (string, error) {
+func runL7Proxy(ctx context.Context, cli docker.Client, target *TargetConfig, extNwName string, proxies []Proxy, labels map[string]string, keepCtrs bool) (string, error) {
 	if len(proxies) == 0 {
 		return "", nil
 	}
@@ -46,11 +46,7 @@ func runL7Proxy(ctx context.Context, cli docker.Client, target *TargetConfig, ex
 			Env: []string{
 				"ALLOWED_HOSTS=" + allowedHosts,
 			},
-			Labels: map[string]string{
-				"docker-mcp":            "true",
-				"docker-mcp-proxy":      "true",
-				"docker-mcp-proxy-type": "l7",
-			},
+			Labels: proxyLabels(labels, "l7"),
 		},
 		container.HostConfig{
 			NetworkMode: container.NetworkMode(target.NetworkName),
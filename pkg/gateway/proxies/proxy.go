@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math/rand"
 	"strings"
 	"time"
@@ -29,7 +30,8 @@ type TargetConfig struct {
 // RunNetworkProxies starts a set of Proxy and returns a TargetConfig that
 // should be applied to a target container to get all its traffic proxied, a
 // cleanup function to remove the network and proxies, and an error if any.
-func RunNetworkProxies(ctx context.Context, cli docker.Client, proxies []Proxy, keepCtrs, debugDNS bool) (_ TargetConfig, _ func(context.Context) error, retErr error) {
+// The proxy containers are given the labels on top of their own.
+func RunNetworkProxies(ctx context.Context, cli docker.Client, proxies []Proxy, labels map[string]string, keepCtrs, debugDNS bool) (_ TargetConfig, _ func(context.Context) error, retErr error) {
 	if len(proxies) == 0 {
 		return TargetConfig{}, nil, nil
 	}
@@ -76,7 +78,7 @@ func RunNetworkProxies(ctx context.Context, cli docker.Client, proxies []Proxy,
 
 	// Start L4 proxies.
 	l4Proxies := sliceutil.Filter(proxies, func(p Proxy) bool { return p.Protocol == TCP })
-	proxyNames, err = runL4Proxies(ctx, cli, &target, extNwName, l4Proxies, keepCtrs)
+	proxyNames, err = runL4Proxies(ctx, cli, &target, extNwName, l4Proxies, labels, keepCtrs)
 	if err != nil {
 		return TargetConfig{}, nil, fmt.Errorf("running l4 proxies: %w", err)
 	}
@@ -97,7 +99,7 @@ func RunNetworkProxies(ctx context.Context, cli docker.Client, proxies []Proxy,
 
 	// Start L7 proxy.
 	l7Proxies := sliceutil.Filter(proxies, func(p Proxy) bool { return p.Protocol == HTTP })
-	l7ProxyName, err := runL7Proxy(ctx, cli, &target, extNwName, l7Proxies, keepCtrs)
+	l7ProxyName, err := runL7Proxy(ctx, cli, &target, extNwName, l7Proxies, labels, keepCtrs)
 	if err != nil {
 		return TargetConfig{}, nil, fmt.Errorf("running l7 proxy: %w", err)
 	}
@@ -121,7 +123,7 @@ func RunNetworkProxies(ctx context.Context, cli docker.Client, proxies []Proxy,
 	var dnsLogsReader io.ReadCloser
 	if debugDNS {
 		var dnsName string
-		dnsName, dnsLogsReader, err = runDNSForwarder(ctx, cli, &target, extNwName, keepCtrs)
+		dnsName, dnsLogsReader, err = runDNSForwarder(ctx, cli, &target, extNwName, labels, keepCtrs)
 		if err != nil {
 			return TargetConfig{}, nil, fmt.Errorf("running dns forwarder: %w", err)
 		}
@@ -173,6 +175,16 @@ func removeProxies(ctx context.Context, cli docker.Client, nwNames []string, pro
 	return errors.Join(errs...)
 }
 
+// proxyLabels are the labels of a proxy container of the given type.
+func proxyLabels(labels map[string]string, proxyType string) map[string]string {
+	merged := map[string]string{}
+	maps.Copy(merged, labels)
+	merged["docker-mcp"] = "true"
+	merged["docker-mcp-proxy"] = "true"
+	merged["docker-mcp-proxy-type"] = proxyType
+	return merged
+}
+
 func randString() string {
 	const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
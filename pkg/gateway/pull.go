@@ -3,13 +3,63 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/log"
 	"github.com/docker/mcp-gateway/pkg/signatures"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+const (
+	// PullPolicyAlways pulls the images every time the gateway starts or adds a server.
+	PullPolicyAlways = "always"
+	// PullPolicyIfNotPresent only pulls the images that are not present locally.
+	PullPolicyIfNotPresent = "if-not-present"
+	// PullPolicyNever never pulls images. Servers whose image is not present locally fail to start.
+	PullPolicyNever = "never"
 )
 
+var PullPolicies = []string{PullPolicyAlways, PullPolicyIfNotPresent, PullPolicyNever}
+
+// imagePull is how the image of a server is pulled. Empty fields fall back to the gateway's defaults.
+type imagePull struct {
+	platform string
+	policy   string
+	// progress, if set, is called while the image is downloaded
+	progress func(docker.PullProgress)
+}
+
+func serverImagePull(server catalog.Server) imagePull {
+	return imagePull{
+		platform: server.Platform,
+		policy:   server.PullPolicy,
+	}
+}
+
+// pullPolicy returns the pull policy of a server, given the gateway's pull policy.
+func (g *Gateway) pullPolicy(pull imagePull) (string, error) {
+	policy := pull.policy
+	if policy == "" {
+		policy = g.PullPolicy
+	}
+	if policy == "" {
+		policy = PullPolicyIfNotPresent
+	}
+
+	if !slices.Contains(PullPolicies, policy) {
+		return "", fmt.Errorf("invalid pull policy %q, expected one of: %s", policy, strings.Join(PullPolicies, ", "))
+	}
+	return policy, nil
+}
+
 func (g *Gateway) pullAndVerify(ctx context.Context, configuration Configuration) error {
 	dockerImages := configuration.DockerImages()
 	if len(dockerImages) == 0 {
@@ -26,7 +76,7 @@ func (g *Gateway) pullAndVerify(ctx context.Context, configuration Configuration
 		}
 	}
 
-	if err := g.pullImages(ctx, dockerImages); err != nil {
+	if err := g.pullImages(ctx, dockerImages, configuration.imageServers(), configuration.imagePulls()); err != nil {
 		return err
 	}
 
@@ -37,10 +87,20 @@ func (g *Gateway) pullAndVerify(ctx context.Context, configuration Configuration
 	return nil
 }
 
-func (g *Gateway) pullImages(ctx context.Context, images []string) error {
+func (g *Gateway) pullImages(ctx context.Context, images []string, imageServers map[string][]string, imagePulls map[string]imagePull) error {
 	start := time.Now()
 
-	if err := g.docker.PullImages(ctx, images...); err != nil {
+	errs, ctx := errgroup.WithContext(ctx)
+	errs.SetLimit(runtime.NumCPU())
+
+	for _, image := range images {
+		errs.Go(func() error {
+			defer g.startup.span("pull", imageBaseName(image))()
+			return g.pullImage(ctx, image, imagePulls[image], imageServers[image])
+		})
+	}
+
+	if err := errs.Wait(); err != nil {
 		return fmt.Errorf("pulling docker images: %w", err)
 	}
 
@@ -48,6 +108,83 @@ func (g *Gateway) pullImages(ctx context.Context, images []string) error {
 	return nil
 }
 
+// pullImage pulls an image, given its pull policy and platform. If it wasn't already there,
+// or if it's always pulled, the pull is recorded for each server that uses the image.
+func (g *Gateway) pullImage(ctx context.Context, image string, pull imagePull, serverNames []string) error {
+	policy, err := g.pullPolicy(pull)
+	if err != nil {
+		return err
+	}
+
+	exists, _ := g.docker.ImageExists(ctx, image)
+
+	if policy == PullPolicyNever {
+		if !exists {
+			return fmt.Errorf("image %s is not present locally and the pull policy is %s", image, PullPolicyNever)
+		}
+		g.checkEmulation(ctx, image, serverNames)
+		return nil
+	}
+
+	start := time.Now()
+	err = g.docker.PullImageWithOptions(ctx, image, docker.PullOptions{
+		Platform: pull.platform,
+		Always:   policy == PullPolicyAlways,
+		Progress: pull.progress,
+	})
+	if err == nil {
+		g.checkEmulation(ctx, image, serverNames)
+	}
+	if exists && policy != PullPolicyAlways {
+		return err
+	}
+	duration := float64(time.Since(start).Milliseconds())
+
+	var size int64
+	if err == nil {
+		if inspect, inspectErr := g.docker.InspectImage(ctx, image); inspectErr == nil {
+			size = inspect.Size
+		}
+	}
+
+	for _, serverName := range serverNames {
+		telemetry.RecordContainerPull(ctx, serverName, image, duration, size, err == nil)
+	}
+
+	return err
+}
+
+// pullProgressInterval is the minimum interval between two progress notifications sent while pulling an image.
+const pullProgressInterval = 500 * time.Millisecond
+
+// pullProgressNotifier sends the progress of an image pull to a client, as MCP progress notifications.
+func pullProgressNotifier(ctx context.Context, session *mcp.ServerSession, progressToken any, image string) func(docker.PullProgress) {
+	var (
+		lastSent     time.Time
+		lastProgress int64
+	)
+
+	return func(progress docker.PullProgress) {
+		// Progress must increase with each notification
+		if progress.Current <= lastProgress {
+			return
+		}
+		done := progress.Total > 0 && progress.Current >= progress.Total
+		if !done && time.Since(lastSent) < pullProgressInterval {
+			return
+		}
+		lastSent = time.Now()
+		lastProgress = progress.Current
+
+		_ = session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: progressToken,
+			Message:       fmt.Sprintf("Pulling image %s", imageBaseName(image)),
+			Progress:      float64(progress.Current),
+			Total:         float64(progress.Total),
+		})
+	}
+}
+
 func (g *Gateway) verifyImages(ctx context.Context, images []string) error {
 	if !g.VerifySignatures {
 		return nil
@@ -82,3 +219,39 @@ func imageBaseName(name string) string {
 
 	return name
 }
+
+// findServersWithBrokenImages pulls the images of each server one by one and reports the servers
+// whose image can't be pulled. Returns true if at least one server was found to be broken.
+func (g *Gateway) findServersWithBrokenImages(ctx context.Context, configuration Configuration, report *StartupReport) bool {
+	found := false
+
+	for _, serverName := range configuration.ServerNames() {
+		serverConfig, tools, ok := configuration.Find(serverName)
+
+		var (
+			images []string
+			pull   imagePull
+		)
+		switch {
+		case !ok:
+			continue
+		case serverConfig != nil && serverConfig.Spec.Image != "":
+			images = append(images, serverConfig.Spec.Image)
+			pull = serverImagePull(serverConfig.Spec)
+		case tools != nil:
+			for _, tool := range *tools {
+				images = append(images, tool.Container.Image)
+			}
+		}
+
+		for _, image := range images {
+			if err := g.pullImage(ctx, image, pull, []string{serverName}); err != nil {
+				report.fail(serverName, fmt.Sprintf("image %s can't be pulled: %s", image, err))
+				found = true
+				break
+			}
+		}
+	}
+
+	return found
+}
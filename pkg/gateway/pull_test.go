@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+type fakePulls struct {
+	docker.Client
+	images []string
+	pulls  []docker.PullOptions
+}
+
+func (f *fakePulls) ImageExists(_ context.Context, name string) (bool, error) {
+	return slices.Contains(f.images, name), nil
+}
+
+func (f *fakePulls) PullImageWithOptions(_ context.Context, name string, options docker.PullOptions) error {
+	f.pulls = append(f.pulls, options)
+	f.images = append(f.images, name)
+	return nil
+}
+
+func (f *fakePulls) InspectImage(context.Context, string) (image.InspectResponse, error) {
+	return image.InspectResponse{}, nil
+}
+
+func (f *fakePulls) ServerPlatform(context.Context) (string, error) {
+	return "linux/amd64", nil
+}
+
+func TestPullImagePolicy(t *testing.T) {
+	t.Run("if-not-present by default", func(t *testing.T) {
+		fake := &fakePulls{}
+		g := &Gateway{docker: fake}
+
+		require.NoError(t, g.pullImage(t.Context(), "mcp/server", imagePull{}, nil))
+		assert.Equal(t, []docker.PullOptions{{}}, fake.pulls)
+	})
+
+	t.Run("always", func(t *testing.T) {
+		fake := &fakePulls{images: []string{"mcp/server"}}
+		g := &Gateway{docker: fake, Options: Options{PullPolicy: PullPolicyAlways}}
+
+		require.NoError(t, g.pullImage(t.Context(), "mcp/server", imagePull{platform: "linux/arm64"}, nil))
+		assert.Equal(t, []docker.PullOptions{{Platform: "linux/arm64", Always: true}}, fake.pulls)
+	})
+
+	t.Run("never with a local image", func(t *testing.T) {
+		fake := &fakePulls{images: []string{"mcp/server"}}
+		g := &Gateway{docker: fake, Options: Options{PullPolicy: PullPolicyNever}}
+
+		require.NoError(t, g.pullImage(t.Context(), "mcp/server", imagePull{}, nil))
+		assert.Empty(t, fake.pulls)
+	})
+
+	t.Run("never without a local image", func(t *testing.T) {
+		fake := &fakePulls{}
+		g := &Gateway{docker: fake, Options: Options{PullPolicy: PullPolicyNever}}
+
+		err := g.pullImage(t.Context(), "mcp/server", imagePull{}, nil)
+		require.ErrorContains(t, err, "image mcp/server is not present locally and the pull policy is never")
+		assert.Empty(t, fake.pulls)
+	})
+
+	t.Run("server overrides the gateway", func(t *testing.T) {
+		fake := &fakePulls{}
+		g := &Gateway{docker: fake, Options: Options{PullPolicy: PullPolicyNever}}
+
+		require.NoError(t, g.pullImage(t.Context(), "mcp/server", imagePull{policy: PullPolicyIfNotPresent}, nil))
+		assert.Len(t, fake.pulls, 1)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		g := &Gateway{docker: &fakePulls{}}
+
+		err := g.pullImage(t.Context(), "mcp/server", imagePull{policy: "sometimes"}, nil)
+		require.ErrorContains(t, err, `invalid pull policy "sometimes"`)
+	})
+}
@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/oauth"
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+// ServerReadiness tells whether a server can be used right away, without configuring it first.
+type ServerReadiness struct {
+	Ready           bool     `json:"ready"`
+	SecretsSet      bool     `json:"secrets_set"`
+	MissingSecrets  []string `json:"missing_secrets,omitempty"`
+	ConfigSatisfied bool     `json:"config_satisfied"`
+	MissingConfig   []string `json:"missing_config,omitempty"`
+	// ImagePulled is only set for servers that run an image.
+	ImagePulled *bool `json:"image_pulled,omitempty"`
+	// OAuthAuthorized is only set for remote servers that use OAuth.
+	OAuthAuthorized *bool `json:"oauth_authorized,omitempty"`
+}
+
+// serverReadiness computes the readiness of a server, given the secrets and config that are already set.
+func (g *Gateway) serverReadiness(ctx context.Context, serverName string, server catalog.Server, secrets map[string]string, config map[string]map[string]any) ServerReadiness {
+	readiness := ServerReadiness{
+		MissingSecrets: missingSecrets(server, secrets),
+		MissingConfig:  missingConfig(serverName, server, config),
+	}
+	readiness.SecretsSet = len(readiness.MissingSecrets) == 0
+	readiness.ConfigSatisfied = len(readiness.MissingConfig) == 0
+	readiness.Ready = readiness.SecretsSet && readiness.ConfigSatisfied
+
+	if server.Image != "" && g.docker != nil {
+		pulled, err := g.docker.ImageExists(ctx, server.Image)
+		if err != nil {
+			log.Logf("  ! Failed to check if image %s is pulled: %v", server.Image, err)
+		} else {
+			readiness.ImagePulled = &pulled
+			readiness.Ready = readiness.Ready && pulled
+		}
+	}
+
+	if server.IsRemoteOAuthServer() {
+		authorized := false
+		tokenStatus, err := oauth.NewOAuthCredentialHelper().GetTokenStatus(ctx, serverName)
+		if err == nil {
+			authorized = tokenStatus.Valid
+		}
+		readiness.OAuthAuthorized = &authorized
+		readiness.Ready = readiness.Ready && authorized
+	}
+
+	return readiness
+}
+
+// missingSecrets lists the secrets of a server that aren't set.
+func missingSecrets(server catalog.Server, secrets map[string]string) []string {
+	var missing []string
+	for _, secret := range server.Secrets {
+		if value, exists := secrets[secret.Name]; !exists || value == "" {
+			missing = append(missing, secret.Name)
+		}
+	}
+	return missing
+}
+
+// missingConfig validates the config of a server against its config schemas and lists the
+// schemas that aren't satisfied, with the reason why.
+func missingConfig(serverName string, server catalog.Server, config map[string]map[string]any) []string {
+	if len(server.Config) == 0 {
+		return nil
+	}
+
+	var missing []string
+	serverConfigMap := config[oci.CanonicalizeServerName(serverName)]
+
+	for _, configItem := range server.Config {
+		// Config items should be schema objects with a "name" property
+		schemaMap, ok := configItem.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		// Get the name field - this identifies which config to validate
+		configName, ok := schemaMap["name"].(string)
+		if !ok || configName == "" {
+			continue
+		}
+
+		// Get the actual config value to validate
+		if serverConfigMap == nil {
+			missing = append(missing, fmt.Sprintf("%s (missing)", configName))
+			continue
+		}
+
+		// Convert the schema map to a jsonschema.Schema for validation
+		schemaBytes, err := json.Marshal(schemaMap)
+		if err != nil {
+			missing = append(missing, fmt.Sprintf("%s (invalid schema)", configName))
+			continue
+		}
+
+		var schema jsonschema.Schema
+		if err := json.Unmarshal(schemaBytes, &schema); err != nil {
+			missing = append(missing, fmt.Sprintf("%s (invalid schema)", configName))
+			continue
+		}
+
+		// Resolve the schema
+		resolved, err := schema.Resolve(nil)
+		if err != nil {
+			missing = append(missing, fmt.Sprintf("%s (schema resolution failed)", configName))
+			continue
+		}
+
+		// Validate the config value against the schema
+		if err := resolved.Validate(serverConfigMap); err != nil {
+			// Extract a helpful error message
+			errMsg := err.Error()
+			if len(errMsg) > 100 {
+				errMsg = errMsg[:97] + "..."
+			}
+			missing = append(missing, fmt.Sprintf("%s (%s)", configName, errMsg))
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+type fakeImages struct {
+	docker.Client
+	images []string
+}
+
+func (f *fakeImages) ImageExists(_ context.Context, name string) (bool, error) {
+	return slices.Contains(f.images, name), nil
+}
+
+func TestServerReadiness(t *testing.T) {
+	g := &Gateway{docker: &fakeImages{images: []string{"mcp/ready"}}}
+
+	server := catalog.Server{
+		Image: "mcp/ready",
+		Secrets: []catalog.Secret{
+			{Name: "ready.token", Env: "TOKEN"},
+		},
+		Config: []any{
+			map[string]any{
+				"name": "ready",
+				"type": "object",
+				"properties": map[string]any{
+					"url": map[string]any{"type": "string"},
+				},
+				"required": []any{"url"},
+			},
+		},
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		readiness := g.serverReadiness(t.Context(), "ready", server,
+			map[string]string{"ready.token": "secret"},
+			map[string]map[string]any{"ready": {"url": "https://example.com"}})
+
+		assert.True(t, readiness.Ready)
+		assert.True(t, readiness.SecretsSet)
+		assert.True(t, readiness.ConfigSatisfied)
+		assert.Equal(t, boolPtr(true), readiness.ImagePulled)
+		assert.Nil(t, readiness.OAuthAuthorized)
+	})
+
+	t.Run("missing secrets and config", func(t *testing.T) {
+		readiness := g.serverReadiness(t.Context(), "ready", server, nil, nil)
+
+		assert.False(t, readiness.Ready)
+		assert.False(t, readiness.SecretsSet)
+		assert.Equal(t, []string{"ready.token"}, readiness.MissingSecrets)
+		assert.False(t, readiness.ConfigSatisfied)
+		assert.Equal(t, []string{"ready (missing)"}, readiness.MissingConfig)
+	})
+
+	t.Run("image not pulled", func(t *testing.T) {
+		notPulled := server
+		notPulled.Image = "mcp/other"
+
+		readiness := g.serverReadiness(t.Context(), "ready", notPulled,
+			map[string]string{"ready.token": "secret"},
+			map[string]map[string]any{"ready": {"url": "https://example.com"}})
+
+		assert.False(t, readiness.Ready)
+		assert.Equal(t, boolPtr(false), readiness.ImagePulled)
+	})
+}
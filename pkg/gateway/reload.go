@@ -8,7 +8,9 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/docker/mcp-gateway/pkg/httpclient"
 	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/notify"
 	"github.com/docker/mcp-gateway/pkg/prompts"
 	// "github.com/docker/mcp-gateway/pkg/prompts"
 )
@@ -32,6 +34,7 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 		return fmt.Errorf("listing resources: %w", err)
 	}
 	log.Log(">", len(capabilities.Tools), "tools listed in", time.Since(startList))
+	capabilities.Tools = append(capabilities.Tools, g.extraTools...)
 
 	// Update capabilities
 	// Clear existing capabilities per server and register new ones
@@ -60,8 +63,13 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 	g.serverCapabilities = make(map[string]*ServerCapabilities)
 	g.toolRegistrations = make(map[string]ToolRegistration)
 
+	// Resolve tools exposed under the same name by different servers
+	sortToolsByServer(capabilities.Tools, serverNames)
+	tools, conflicts := resolveToolConflicts(g.ToolConflictStrategy, nil, capabilities.Tools)
+	g.setToolConflicts("", conflicts)
+
 	// Add new capabilities and track them per server
-	for _, tool := range capabilities.Tools {
+	for _, tool := range tools {
 		g.mcpServer.AddTool(tool.Tool, tool.Handler)
 
 		// Track by server
@@ -111,12 +119,36 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 		g.mcpServer.AddTool(mcpConfigSetTool.Tool, mcpConfigSetTool.Handler)
 		g.toolRegistrations[mcpConfigSetTool.Tool.Name] = *mcpConfigSetTool
 
+		// Add mcp-secret-set tool
+		mcpSecretSetTool := g.createMcpSecretSetTool()
+		g.mcpServer.AddTool(mcpSecretSetTool.Tool, mcpSecretSetTool.Handler)
+		g.toolRegistrations[mcpSecretSetTool.Tool.Name] = *mcpSecretSetTool
+
+		// Add mcp-status tool
+		mcpStatusTool := g.createMcpStatusTool()
+		g.mcpServer.AddTool(mcpStatusTool.Tool, mcpStatusTool.Handler)
+		g.toolRegistrations[mcpStatusTool.Tool.Name] = *mcpStatusTool
+
+		// Add mcp-help tool
+		mcpHelpTool := g.createMcpHelpTool()
+		g.mcpServer.AddTool(mcpHelpTool.Tool, mcpHelpTool.Handler)
+		g.toolRegistrations[mcpHelpTool.Tool.Name] = *mcpHelpTool
+
+		// Add mcp-dry-run tool
+		mcpDryRunTool := g.createMcpDryRunTool()
+		g.mcpServer.AddTool(mcpDryRunTool.Tool, mcpDryRunTool.Handler)
+		g.toolRegistrations[mcpDryRunTool.Tool.Name] = *mcpDryRunTool
+
 		log.Log("  > mcp-find: tool for finding MCP servers in the catalog")
 		log.Log("  > mcp-add: tool for adding MCP servers to the registry")
 		log.Log("  > mcp-remove: tool for removing MCP servers from the registry")
 		log.Log("  > mcp-config-set: tool for setting configuration values for MCP servers")
+		log.Log("  > mcp-secret-set: tool for setting the secrets of MCP servers")
+		log.Log("  > mcp-status: tool for reporting the status of the gateway")
+		log.Log("  > mcp-help: tool for explaining how to use the gateway and the limits of the session")
 		log.Log("  > code-mode: write code that calls other MCPs directly")
 		log.Log("  > mcp-exec: execute tools that exist in the current session")
+		log.Log("  > mcp-dry-run: validate tool calls without executing them")
 
 		// Add mcp-registry-import tool
 		// mcpRegistryImportTool := g.createMcpRegistryImportTool(configuration, clientConfig)
@@ -135,6 +167,11 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 		log.Log("  > mcp-discover: prompt for learning about dynamic server management")
 	}
 
+	if g.detectsProjects() {
+		g.addProjectServersPrompt()
+		log.Log("  > project-servers: prompt listing the servers suggested for the client's projects")
+	}
+
 	for _, prompt := range capabilities.Prompts {
 		g.mcpServer.AddPrompt(prompt.Prompt, prompt.Handler)
 
@@ -161,6 +198,9 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 		)
 	}
 
+	// The full schemas of the tools, for the clients that list minified schemas
+	capabilities.ResourceTemplates = append(capabilities.ResourceTemplates, g.toolSchemaResourceTemplate())
+
 	// Resource templates are handled as regular resources in the new SDK
 	for _, template := range capabilities.ResourceTemplates {
 		// Convert ResourceTemplate to Resource
@@ -261,6 +301,12 @@ func (g *Gateway) reloadServerCapabilities(ctx context.Context, serverName strin
 		return nil, fmt.Errorf("failed to list capabilities for %s: %w", serverName, err)
 	}
 
+	return g.storeServerCapabilities(serverName, newServerCaps), nil
+}
+
+// storeServerCapabilities stores the capabilities just listed from a server and registers its tools,
+// resolving the conflicts with the tools of the other servers. It returns the server's old capabilities.
+func (g *Gateway) storeServerCapabilities(serverName string, newServerCaps *Capabilities) *ServerCapabilities {
 	// Lock for reading/writing capability tracking
 	g.capabilitiesMu.Lock()
 	defer g.capabilitiesMu.Unlock()
@@ -271,6 +317,13 @@ func (g *Gateway) reloadServerCapabilities(ctx context.Context, serverName strin
 		oldCaps = &ServerCapabilities{}
 	}
 
+	// Resolve tools that collide with tools from other servers
+	var conflicts []ToolConflict
+	owners := g.toolOwners(serverName)
+	newServerCaps.Tools, conflicts = resolveToolConflicts(g.ToolConflictStrategy, owners, newServerCaps.Tools)
+	g.setToolConflicts(serverName, conflicts)
+	g.releaseTakenOverTools(owners, newServerCaps.Tools)
+
 	// Store the full capabilities
 	g.serverAvailableCapabilities[serverName] = newServerCaps
 
@@ -291,7 +344,7 @@ func (g *Gateway) reloadServerCapabilities(ctx context.Context, serverName strin
 	// The caller should use g.allCapabilities(serverName) to get newCaps
 	// The full capabilities (newServerCaps) are now in g.serverAvailableCapabilities[serverName]
 	// g.serverCapabilities will be set by updateServerCapabilities after all updates succeed
-	return oldCaps, nil
+	return oldCaps
 }
 
 // updateServerCapabilities updates g.mcpServer with capabilities from the server.
@@ -390,7 +443,70 @@ func (g *Gateway) updateServerCapabilities(serverName string, oldCaps, newCaps *
 	return nil
 }
 
-func (g *Gateway) removeServerConfiguration(_ context.Context, serverName string) error {
+// ReloadServer re-reads the configuration and secrets of a single enabled server
+// and swaps its capabilities. Other servers, and their connections, are left untouched.
+// The caller must hold the server's lock, so that the server isn't removed or added while it's reloaded.
+func (g *Gateway) ReloadServer(ctx context.Context, serverName string) error {
+	serverName = strings.TrimSpace(serverName)
+	if !g.isServerEnabled(serverName) {
+		return fmt.Errorf("server %s is not enabled", serverName)
+	}
+
+	log.Log("> Reloading server:", serverName)
+	start := time.Now()
+
+	fresh, err := g.configurator.ReadServer(ctx, serverName)
+	if err != nil {
+		return fmt.Errorf("reading configuration for %s: %w", serverName, err)
+	}
+
+	if !g.Static {
+		if err := g.pullAndVerify(ctx, fresh); err != nil {
+			return err
+		}
+	}
+
+	g.mergeServerConfiguration(serverName, fresh)
+
+	// Existing connections were started with the old config/secrets
+	g.clientPool.InvalidateClients(serverName)
+
+	oldCaps, err := g.reloadServerCapabilities(ctx, serverName, nil)
+	if err != nil {
+		return err
+	}
+
+	g.capabilitiesMu.Lock()
+	defer g.capabilitiesMu.Unlock()
+
+	// Only swap the capabilities of a server whose capabilities are currently exposed.
+	// A server that was added but never activated stays that way.
+	if _, active := g.serverCapabilities[serverName]; active {
+		newCaps := g.allCapabilities(serverName)
+
+		// Drop what's gone and (re-)register everything else so that
+		// updated tool definitions replace the old ones.
+		stale := &ServerCapabilities{}
+		stale.ToolNames, _ = diffStringSlices(newCaps.ToolNames, oldCaps.ToolNames)
+		stale.PromptNames, _ = diffStringSlices(newCaps.PromptNames, oldCaps.PromptNames)
+		stale.ResourceURIs, _ = diffStringSlices(newCaps.ResourceURIs, oldCaps.ResourceURIs)
+		stale.ResourceTemplateURIs, _ = diffStringSlices(newCaps.ResourceTemplateURIs, oldCaps.ResourceTemplateURIs)
+
+		if err := g.updateServerCapabilities(serverName, stale, newCaps, nil); err != nil {
+			return err
+		}
+	}
+
+	log.Log("> Server", serverName, "reloaded in", time.Since(start))
+	g.emit(notify.Event{
+		Type:    notify.EventReloadCompleted,
+		Server:  serverName,
+		Message: fmt.Sprintf("Server %s reloaded", serverName),
+	})
+	return nil
+}
+
+func (g *Gateway) removeServerConfiguration(ctx context.Context, serverName string) error {
 	// Find the server configuration in current config
 	serverConfig, _, found := g.configuration.Find(serverName)
 	if !found || serverConfig == nil {
@@ -430,6 +546,16 @@ func (g *Gateway) removeServerConfiguration(_ context.Context, serverName string
 
 	// Update tracking with new capabilities
 	delete(g.serverCapabilities, serverName)
+	g.setToolConflicts(serverName, nil)
+
+	// Close the idle connections to a remote server
+	httpclient.Release(serverName)
+
+	// The scratch volume can only be removed once the containers of the server are stopped.
+	if g.ScratchSize != "" {
+		g.clientPool.InvalidateClients(serverName)
+		go g.clientPool.removeScratchVolume(context.WithoutCancel(ctx), serverName)
+	}
 
 	return nil
 }
@@ -0,0 +1,365 @@
+package gateway
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/policy"
+)
+
+// protectedResourcePath is the well-known path of the OAuth protected resource metadata (RFC 9728).
+const protectedResourcePath = "/.well-known/oauth-protected-resource"
+
+// DefaultOAuthGroupsClaim is the claim of the access tokens that lists the groups of the client.
+const DefaultOAuthGroupsClaim = "groups"
+
+// tokenIdentityKey is the key of the client's identity in the token info of the requests.
+const tokenIdentityKey = "identity"
+
+const (
+	// jwksCacheTTL is how long the signing keys of the authorization server are cached.
+	jwksCacheTTL = time.Hour
+	// jwksMinRefreshInterval limits how often tokens signed with unknown keys can trigger a refresh.
+	jwksMinRefreshInterval = time.Minute
+)
+
+var accessTokenAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+type protectedResourceMetadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported"`
+	ScopesSupported        []string `json:"scopes_supported,omitempty"`
+	ResourceName           string   `json:"resource_name,omitempty"`
+}
+
+type accessTokenClaims struct {
+	jwt.Claims
+	Scope string `json:"scope,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// resourceAuth lets the clients of the streaming transport authenticate with access tokens
+// issued by an external authorization server (eg. an OIDC provider), discovered through
+// the protected resource metadata. The identities of the clients are checked against the policy.
+type resourceAuth struct {
+	resource    string
+	issuer      string
+	audience    string
+	scopes      []string
+	groupsClaim string
+	metadataURL string
+	httpClient  *http.Client
+
+	mu        sync.Mutex
+	jwksURI   string
+	keys      *jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+// newResourceAuth configures the authentication of the resource, the public URL of the MCP endpoint.
+// The audience of the access tokens defaults to the resource.
+func newResourceAuth(resource, issuer, audience string, scopes []string, groupsClaim string) (*resourceAuth, error) {
+	resourceURL, err := url.Parse(resource)
+	if err != nil || resourceURL.Scheme == "" || resourceURL.Host == "" {
+		return nil, fmt.Errorf("invalid OAuth resource %q: must be an absolute URL", resource)
+	}
+	issuerURL, err := url.Parse(issuer)
+	if err != nil || issuerURL.Scheme == "" || issuerURL.Host == "" {
+		return nil, fmt.Errorf("invalid OAuth issuer %q: must be an absolute URL", issuer)
+	}
+	if audience == "" {
+		audience = resource
+	}
+	if groupsClaim == "" {
+		groupsClaim = DefaultOAuthGroupsClaim
+	}
+
+	// The metadata of a resource with a path is published under the well-known path followed by that path
+	metadataURL := resourceURL.Scheme + "://" + resourceURL.Host + protectedResourcePath + strings.TrimSuffix(resourceURL.EscapedPath(), "/")
+
+	return &resourceAuth{
+		resource:    resource,
+		issuer:      issuer,
+		audience:    audience,
+		scopes:      scopes,
+		groupsClaim: groupsClaim,
+		metadataURL: metadataURL,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// metadataPaths are the paths the protected resource metadata is served on.
+func (a *resourceAuth) metadataPaths() []string {
+	metadataPath := protectedResourcePath
+	if u, err := url.Parse(a.metadataURL); err == nil {
+		metadataPath = u.Path
+	}
+	if metadataPath == protectedResourcePath {
+		return []string{protectedResourcePath}
+	}
+	return []string{metadataPath, protectedResourcePath}
+}
+
+func (a *resourceAuth) metadataHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(protectedResourceMetadata{
+			Resource:               a.resource,
+			AuthorizationServers:   []string{a.issuer},
+			BearerMethodsSupported: []string{"header"},
+			ScopesSupported:        a.scopes,
+			ResourceName:           gatewayName,
+		})
+	}
+}
+
+// middleware accepts the requests that carry a valid access token, or the gateway's own bearer token
+// if it has one, and challenges the others. The health, discovery and metadata endpoints are public.
+// The control API only accepts the gateway's own token: the end users of the authorization server
+// must not be able to reload the gateway, rotate its secrets or override its policy.
+func (a *resourceAuth) middleware(authToken string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" || r.URL.Path == discoveryPath || strings.HasPrefix(r.URL.Path, protectedResourcePath) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, found := bearerToken(r)
+		if !found {
+			a.challenge(w, http.StatusUnauthorized, "", "")
+			return
+		}
+		if authToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(authToken)) == 1 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.URL.Path == controlPathPrefix || strings.HasPrefix(r.URL.Path, controlPathPrefix+"/") {
+			http.Error(w, "The control API only accepts the gateway's token", http.StatusForbidden)
+			return
+		}
+
+		claims, identity, err := a.verify(r.Context(), token)
+		if err != nil {
+			log.Logf("  ! Rejected access token: %v", err)
+			a.challenge(w, http.StatusUnauthorized, "invalid_token", "The access token is invalid or expired")
+			return
+		}
+		if !hasScopes(claims.Scope, a.scopes) {
+			a.challenge(w, http.StatusForbidden, "insufficient_scope", "The access token lacks the required scopes")
+			return
+		}
+
+		// The streaming transport passes the token info of the HTTP request on to the MCP requests
+		tokenInfo := &auth.TokenInfo{
+			Scopes:     strings.Fields(claims.Scope),
+			Expiration: claims.Expiry.Time(),
+			Extra:      map[string]any{tokenIdentityKey: identity},
+		}
+		auth.RequireBearerToken(func(context.Context, string, *http.Request) (*auth.TokenInfo, error) {
+			return tokenInfo, nil
+		}, nil)(next).ServeHTTP(w, r)
+	})
+}
+
+// requestIdentity returns the identity of the client that sent a request, if it was authenticated
+// with an access token. Clients that use stdio or the gateway's own bearer token have none.
+func requestIdentity(extra *mcp.RequestExtra) *policy.Identity {
+	if extra == nil || extra.TokenInfo == nil {
+		return nil
+	}
+	identity, _ := extra.TokenInfo.Extra[tokenIdentityKey].(*policy.Identity)
+	return identity
+}
+
+// challenge rejects a request with a WWW-Authenticate header pointing to the protected resource metadata.
+func (a *resourceAuth) challenge(w http.ResponseWriter, status int, errorCode, description string) {
+	params := []string{fmt.Sprintf("resource_metadata=%q", a.metadataURL)}
+	if errorCode != "" {
+		params = append(params, fmt.Sprintf("error=%q", errorCode), fmt.Sprintf("error_description=%q", description))
+	}
+	if len(a.scopes) > 0 {
+		params = append(params, fmt.Sprintf("scope=%q", strings.Join(a.scopes, " ")))
+	}
+
+	w.Header().Set("WWW-Authenticate", "Bearer "+strings.Join(params, ", "))
+	http.Error(w, http.StatusText(status), status)
+}
+
+// verify checks the signature, issuer, audience and validity period of an access token,
+// and returns the identity of the client.
+func (a *resourceAuth) verify(ctx context.Context, token string) (accessTokenClaims, *policy.Identity, error) {
+	var (
+		claims    accessTokenClaims
+		rawClaims map[string]any
+	)
+
+	parsed, err := jwt.ParseSigned(token, accessTokenAlgorithms)
+	if err != nil {
+		return claims, nil, fmt.Errorf("failed to parse access token: %w", err)
+	}
+
+	keys, err := a.keySet(ctx, false)
+	if err != nil {
+		return claims, nil, err
+	}
+	if err := parsed.Claims(keys, &claims, &rawClaims); err != nil {
+		// The authorization server may have rotated its keys
+		keys, refreshErr := a.keySet(ctx, true)
+		if refreshErr != nil {
+			return claims, nil, refreshErr
+		}
+		if err := parsed.Claims(keys, &claims, &rawClaims); err != nil {
+			return claims, nil, fmt.Errorf("failed to verify access token: %w", err)
+		}
+	}
+
+	if claims.Expiry == nil {
+		return claims, nil, errors.New("access token has no expiration")
+	}
+	if err := claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:      a.issuer,
+		AnyAudience: jwt.Audience{a.audience},
+		Time:        time.Now(),
+	}, jwt.DefaultLeeway); err != nil {
+		return claims, nil, fmt.Errorf("failed to validate access token: %w", err)
+	}
+
+	return claims, &policy.Identity{
+		Subject: claims.Subject,
+		Email:   claims.Email,
+		Groups:  stringsClaim(rawClaims[a.groupsClaim]),
+	}, nil
+}
+
+// stringsClaim reads a claim that's either a list of strings or a single string.
+func stringsClaim(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		var values []string
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// keySet returns the signing keys of the authorization server, fetching them when they're not cached.
+func (a *resourceAuth) keySet(ctx context.Context, refresh bool) (*jose.JSONWebKeySet, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	age := time.Since(a.fetchedAt)
+	if a.keys != nil && age < jwksCacheTTL && (!refresh || age < jwksMinRefreshInterval) {
+		return a.keys, nil
+	}
+
+	if a.jwksURI == "" {
+		jwksURI, err := a.discoverJWKSURI(ctx)
+		if err != nil {
+			return nil, err
+		}
+		a.jwksURI = jwksURI
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := a.getJSON(ctx, a.jwksURI, &keys); err != nil {
+		return nil, fmt.Errorf("failed to fetch the signing keys of %s: %w", a.issuer, err)
+	}
+	a.keys = &keys
+	a.fetchedAt = time.Now()
+
+	return a.keys, nil
+}
+
+// discoverJWKSURI reads the metadata of the authorization server (RFC 8414), or its OpenID configuration.
+func (a *resourceAuth) discoverJWKSURI(ctx context.Context) (string, error) {
+	issuerURL, err := url.Parse(a.issuer)
+	if err != nil {
+		return "", fmt.Errorf("invalid OAuth issuer %q: %w", a.issuer, err)
+	}
+	issuerPath := strings.TrimSuffix(issuerURL.EscapedPath(), "/")
+	base := issuerURL.Scheme + "://" + issuerURL.Host
+
+	var errs []error
+	for _, metadataURL := range []string{
+		base + "/.well-known/oauth-authorization-server" + issuerPath,
+		base + issuerPath + "/.well-known/openid-configuration",
+	} {
+		var metadata struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := a.getJSON(ctx, metadataURL, &metadata); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if metadata.JWKSURI == "" {
+			errs = append(errs, fmt.Errorf("%s has no jwks_uri", metadataURL))
+			continue
+		}
+		return metadata.JWKSURI, nil
+	}
+
+	return "", fmt.Errorf("failed to discover the authorization server %s: %w", a.issuer, errors.Join(errs...))
+}
+
+func (a *resourceAuth) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// hasScopes reports whether the space separated scopes of a token include all the required ones.
+func hasScopes(scope string, required []string) bool {
+	granted := strings.Fields(scope)
+	for _, s := range required {
+		if !slices.Contains(granted, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/policy"
+)
+
+const testResource = "https://gateway.example.com/mcp"
+
+// testIssuer is an authorization server that publishes its metadata and signing keys.
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := &testIssuer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "test", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (i *testIssuer) token(t *testing.T, audience string, expiry time.Time, scope string, extraClaims ...map[string]any) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: i.key}, (&jose.SignerOptions{}).WithHeader("kid", "test"))
+	require.NoError(t, err)
+
+	builder := jwt.Signed(signer).Claims(accessTokenClaims{
+		Claims: jwt.Claims{
+			Issuer:   i.server.URL,
+			Subject:  "user",
+			Audience: jwt.Audience{audience},
+			Expiry:   jwt.NewNumericDate(expiry),
+		},
+		Scope: scope,
+	})
+	for _, claims := range extraClaims {
+		builder = builder.Claims(claims)
+	}
+	token, err := builder.Serialize()
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestProtectedResourceMetadata(t *testing.T) {
+	resourceAuth, err := newResourceAuth(testResource, "https://idp.example.com", "", []string{"mcp"}, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://gateway.example.com/.well-known/oauth-protected-resource/mcp", resourceAuth.metadataURL)
+	assert.Equal(t, []string{"/.well-known/oauth-protected-resource/mcp", protectedResourcePath}, resourceAuth.metadataPaths())
+
+	w := httptest.NewRecorder()
+	resourceAuth.metadataHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/oauth-protected-resource/mcp", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var metadata protectedResourceMetadata
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &metadata))
+	assert.Equal(t, protectedResourceMetadata{
+		Resource:               testResource,
+		AuthorizationServers:   []string{"https://idp.example.com"},
+		BearerMethodsSupported: []string{"header"},
+		ScopesSupported:        []string{"mcp"},
+		ResourceName:           gatewayName,
+	}, metadata)
+}
+
+func TestNewResourceAuthInvalidURLs(t *testing.T) {
+	_, err := newResourceAuth("/mcp", "https://idp.example.com", "", nil, "")
+	require.ErrorContains(t, err, "invalid OAuth resource")
+
+	_, err = newResourceAuth(testResource, "idp", "", nil, "")
+	require.ErrorContains(t, err, "invalid OAuth issuer")
+}
+
+func TestResourceAuthMiddleware(t *testing.T) {
+	issuer := newTestIssuer(t)
+	resourceAuth, err := newResourceAuth(testResource, issuer.server.URL, "", []string{"mcp"}, "")
+	require.NoError(t, err)
+
+	handler := resourceAuth.middleware("static-token", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("no token", func(t *testing.T) {
+		w := serve("/mcp", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, `Bearer resource_metadata="https://gateway.example.com/.well-known/oauth-protected-resource/mcp", scope="mcp"`, w.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("public endpoints", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("/health", "").Code)
+		assert.Equal(t, http.StatusOK, serve(discoveryPath, "").Code)
+		assert.Equal(t, http.StatusOK, serve("/.well-known/oauth-protected-resource/mcp", "").Code)
+	})
+
+	t.Run("static token", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("/mcp", "static-token").Code)
+	})
+
+	t.Run("valid access token", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("/mcp", issuer.token(t, testResource, time.Now().Add(time.Hour), "openid mcp")).Code)
+	})
+
+	t.Run("expired access token", func(t *testing.T) {
+		w := serve("/mcp", issuer.token(t, testResource, time.Now().Add(-time.Hour), "mcp"))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Header().Get("WWW-Authenticate"), `error="invalid_token"`)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		w := serve("/mcp", issuer.token(t, "https://other.example.com", time.Now().Add(time.Hour), "mcp"))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("missing scope", func(t *testing.T) {
+		w := serve("/mcp", issuer.token(t, testResource, time.Now().Add(time.Hour), "openid"))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Header().Get("WWW-Authenticate"), `error="insufficient_scope"`)
+	})
+
+	t.Run("control API", func(t *testing.T) {
+		token := issuer.token(t, testResource, time.Now().Add(time.Hour), "openid mcp")
+		assert.Equal(t, http.StatusForbidden, serve("/control/reload", token).Code)
+		assert.Equal(t, http.StatusForbidden, serve("/control/policy/override", token).Code)
+		assert.Equal(t, http.StatusForbidden, serve("/control", token).Code)
+		assert.Equal(t, http.StatusUnauthorized, serve("/control/reload", "").Code)
+		assert.Equal(t, http.StatusOK, serve("/control/reload", "static-token").Code)
+	})
+
+	t.Run("unknown signing key", func(t *testing.T) {
+		other := newTestIssuer(t)
+		token := other.token(t, testResource, time.Now().Add(time.Hour), "mcp")
+		assert.Equal(t, http.StatusUnauthorized, serve("/mcp", token).Code)
+	})
+}
+
+func TestResourceAuthIdentity(t *testing.T) {
+	issuer := newTestIssuer(t)
+	resourceAuth, err := newResourceAuth(testResource, issuer.server.URL, "", nil, "roles")
+	require.NoError(t, err)
+
+	var identity *policy.Identity
+	handler := resourceAuth.middleware("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenInfo := auth.TokenInfoFromContext(r.Context())
+		require.NotNil(t, tokenInfo)
+		identity = requestIdentity(&mcp.RequestExtra{TokenInfo: tokenInfo})
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	token := issuer.token(t, testResource, time.Now().Add(time.Hour), "", map[string]any{
+		"email": "alice@example.com",
+		"roles": []string{"dba", "dev"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, &policy.Identity{Subject: "user", Email: "alice@example.com", Groups: []string{"dba", "dev"}}, identity)
+}
+
+func TestRequestIdentity(t *testing.T) {
+	assert.Nil(t, requestIdentity(nil))
+	assert.Nil(t, requestIdentity(&mcp.RequestExtra{}))
+}
+
+func TestStringsClaim(t *testing.T) {
+	assert.Equal(t, []string{"a"}, stringsClaim("a"))
+	assert.Equal(t, []string{"a", "b"}, stringsClaim([]any{"a", 1, "b"}))
+	assert.Nil(t, stringsClaim(nil))
+}
+
+func TestHasScopes(t *testing.T) {
+	assert.True(t, hasScopes("a b c", []string{"c", "a"}))
+	assert.True(t, hasScopes("", nil))
+	assert.False(t, hasScopes("a b", []string{"c"}))
+}
@@ -0,0 +1,115 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/notify"
+)
+
+// RotateSecrets re-reads the secrets of the enabled servers from their providers and restarts
+// the servers whose secret values changed. The other servers, and their connections, are left untouched.
+// It returns the servers that were restarted.
+func (g *Gateway) RotateSecrets(ctx context.Context) ([]string, error) {
+	log.Log("> Re-reading secrets...")
+	start := time.Now()
+
+	// Secrets read from Docker Desktop are cached
+	docker.FlushSecretsCache(g.docker)
+
+	var (
+		rotated []string
+		errs    []error
+	)
+	for _, serverName := range g.enabledServerNames("") {
+		changed, err := g.rotateServerSecrets(ctx, serverName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		rotated = append(rotated, serverName)
+
+		log.Log("  - Secrets of", serverName, "changed, restarting it")
+		g.emit(notify.Event{
+			Type:    notify.EventReloadCompleted,
+			Server:  serverName,
+			Message: fmt.Sprintf("Server %s restarted with rotated secrets", serverName),
+		})
+	}
+
+	log.Log(">", len(rotated), "servers with rotated secrets in", time.Since(start))
+	return rotated, errors.Join(errs...)
+}
+
+// rotateServerSecrets re-reads the secrets of a server and, if they changed, restarts it with the new ones.
+// It holds the server's lock, so that the server isn't added, removed or reloaded meanwhile.
+func (g *Gateway) rotateServerSecrets(ctx context.Context, serverName string) (bool, error) {
+	unlock := g.lockServer(serverName)
+	defer unlock()
+
+	// The server was removed since
+	if !g.isServerEnabled(serverName) {
+		return false, nil
+	}
+
+	fresh, err := g.configurator.ReadServer(ctx, serverName)
+	if err != nil {
+		return false, fmt.Errorf("reading secrets of %s: %w", serverName, err)
+	}
+	if !secretsChanged(g.currentSecrets(), fresh, serverName) {
+		return false, nil
+	}
+
+	g.mergeServerConfiguration(serverName, fresh)
+
+	// Kept containers were started with the old secrets, the next call starts a new one
+	g.clientPool.InvalidateClients(serverName)
+	return true, nil
+}
+
+// secretsChanged reports whether the current values of the secrets of a server differ from those of a fresh configuration.
+func secretsChanged(current map[string]string, fresh Configuration, serverName string) bool {
+	server, found := fresh.servers[serverName]
+	if !found {
+		return false
+	}
+
+	for _, secret := range server.Secrets {
+		if current[secret.Name] != fresh.secrets[secret.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// rotateSecretsOnSignal rotates the secrets each time the gateway receives SIGHUP, until the context is done.
+func (g *Gateway) rotateSecretsOnSignal(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				if _, err := g.RotateSecrets(ctx); err != nil {
+					log.Logf("! Failed to rotate secrets: %s", err)
+					g.emit(notify.Event{Type: notify.EventError, Message: fmt.Sprintf("Failed to rotate secrets: %s", err)})
+				}
+			}
+		}
+	}()
+}
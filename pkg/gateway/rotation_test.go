@@ -0,0 +1,112 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// fakeConfigurator re-reads single servers from a fixed configuration.
+type fakeConfigurator struct {
+	Configurator
+	configuration Configuration
+}
+
+func (f *fakeConfigurator) ReadServer(_ context.Context, serverName string) (Configuration, error) {
+	return Configuration{
+		serverNames: []string{serverName},
+		servers:     map[string]catalog.Server{serverName: f.configuration.servers[serverName]},
+		secrets:     f.configuration.secrets,
+	}, nil
+}
+
+func TestRotateSecrets(t *testing.T) {
+	servers := map[string]catalog.Server{
+		"github": {Name: "github", Secrets: []catalog.Secret{{Name: "github.token", Env: "GITHUB_TOKEN"}}},
+		"slack":  {Name: "slack", Secrets: []catalog.Secret{{Name: "slack.token", Env: "SLACK_TOKEN"}}},
+		"fetch":  {Name: "fetch"},
+	}
+	configurator := &fakeConfigurator{configuration: Configuration{
+		servers: servers,
+		secrets: map[string]string{"github.token": "new", "slack.token": "same"},
+	}}
+	g := &Gateway{
+		configurator: configurator,
+		configuration: Configuration{
+			serverNames: []string{"fetch", "github", "slack"},
+			servers:     servers,
+			secrets:     map[string]string{"github.token": "old", "slack.token": "same"},
+		},
+		clientPool: newClientPool(Options{}, nil, nil),
+	}
+
+	rotated, err := g.RotateSecrets(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github"}, rotated)
+	assert.Equal(t, "new", g.configuration.secrets["github.token"])
+
+	// Nothing changed since
+	rotated, err = g.RotateSecrets(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, rotated)
+}
+
+func TestSecretsChanged(t *testing.T) {
+	server := catalog.Server{Name: "github", Secrets: []catalog.Secret{{Name: "github.token"}}}
+	current := Configuration{servers: map[string]catalog.Server{"github": server}, secrets: map[string]string{"github.token": "old"}}
+	secrets := current.secrets
+
+	assert.False(t, secretsChanged(secrets, current, "github"))
+	assert.True(t, secretsChanged(secrets, Configuration{servers: current.servers, secrets: map[string]string{"github.token": "new"}}, "github"))
+	assert.True(t, secretsChanged(secrets, Configuration{servers: current.servers}, "github"))
+	assert.False(t, secretsChanged(secrets, Configuration{}, "github"))
+}
+
+func TestRotateSecretsConcurrently(t *testing.T) {
+	servers := map[string]catalog.Server{
+		"github": {Name: "github", Secrets: []catalog.Secret{{Name: "github.token", Env: "GITHUB_TOKEN"}}},
+		"slack":  {Name: "slack", Secrets: []catalog.Secret{{Name: "slack.token", Env: "SLACK_TOKEN"}}},
+	}
+	g := &Gateway{
+		configurator: &fakeConfigurator{configuration: Configuration{
+			servers: servers,
+			secrets: map[string]string{"github.token": "new", "slack.token": "new"},
+		}},
+		configuration: Configuration{
+			serverNames: []string{"github", "slack"},
+			servers:     servers,
+			secrets:     map[string]string{"github.token": "old", "slack.token": "old"},
+		},
+		clientPool: newClientPool(Options{}, nil, nil),
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		_, _ = g.RotateSecrets(t.Context())
+	}()
+	go func() {
+		defer wg.Done()
+		// mcp-remove
+		unlock := g.lockServer("slack")
+		defer unlock()
+		g.disableServerName("slack")
+	}()
+	go func() {
+		defer wg.Done()
+		// mcp-secret-set
+		g.setSecret("fetch.token", "value")
+	}()
+	wg.Wait()
+
+	// The removed server isn't put back, and the secret set meanwhile isn't lost
+	assert.Equal(t, []string{"github"}, g.configuration.serverNames)
+	assert.Equal(t, "new", g.configuration.secrets["github.token"])
+	assert.Equal(t, "value", g.configuration.secrets["fetch.token"])
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"os"
 	"strings"
@@ -13,17 +14,31 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.opentelemetry.io/otel"
 
+	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/health"
+	"github.com/docker/mcp-gateway/pkg/httpclient"
 	"github.com/docker/mcp-gateway/pkg/interceptors"
+	"github.com/docker/mcp-gateway/pkg/lan"
 	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/logs"
+	"github.com/docker/mcp-gateway/pkg/notify"
 	"github.com/docker/mcp-gateway/pkg/oauth"
 	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/policy"
 	"github.com/docker/mcp-gateway/pkg/telemetry"
 )
 
 type ServerSessionCache struct {
 	Roots []*mcp.Root
+	// Tools the user chose to always allow without confirmation for this session
+	AlwaysAllowedTools map[string]bool
+	// Cost of the tool calls made during this session, by tool
+	Spend map[string]float64
+	// Last time the client sent a request or a notification
+	LastActivity time.Time
+	// Whether the client sent progress tokens with its requests
+	ProgressTokens bool
 }
 
 // type SubsAction int
@@ -40,6 +55,11 @@ type ServerSessionCache struct {
 // }
 
 // ServerCapabilities tracks the capabilities registered for a specific server
+const (
+	gatewayName    = "Docker AI MCP Gateway"
+	gatewayVersion = "2.0.1"
+)
+
 type ServerCapabilities struct {
 	ToolNames            []string
 	PromptNames          []string
@@ -62,24 +82,94 @@ type Gateway struct {
 	sessionCacheMu sync.RWMutex
 	sessionCache   map[*mcp.ServerSession]*ServerSessionCache
 
+	// Tool calls whose execution is shared with the identical calls of their session, with --coalesce-window
+	coalesceMu     sync.Mutex
+	coalescedCalls map[coalesceKey]*coalescedCall
+
 	// Track registered capabilities per server for proper reload handling
 	capabilitiesMu              sync.RWMutex
 	serverCapabilities          map[string]*ServerCapabilities
 	serverAvailableCapabilities map[string]*Capabilities
 
+	// Serializes enabling and disabling each server, and guards the list of enabled servers
+	serverLocksMu sync.Mutex
+	serverLocks   map[string]*sync.Mutex
+
+	// Guards replacing the secrets of the configuration, which is shared with the servers being started
+	secretsMu sync.Mutex
+
+	// Sessions opened or being opened, counted against --max-sessions
+	sessionSlotsMu sync.Mutex
+	sessionSlots   int
+
 	// Track all tool registrations for mcp-exec
 	toolRegistrations map[string]ToolRegistration
 
+	// Tool name conflicts found while registering capabilities
+	toolConflicts []ToolConflict
+
+	// Access policy and the on-call overrides granted through the control API
+	policy          *policy.Policy
+	policyMu        sync.RWMutex
+	policyOverrides map[string]policyOverride
+
+	// Servers and tools of the profiles the policy maps the authenticated clients to, by profile
+	profileViews map[string]profileView
+
+	// Webhooks notified of lifecycle and policy events
+	notifier *notify.Notifier
+	// Subscribers of the control API's /events stream
+	events eventBroker
+
+	// Instructions of the enabled servers, by server name
+	instructionsMu     sync.RWMutex
+	serverInstructions map[string]string
+
+	// Recent failures to start each server, to detect crash loops
+	serverFailuresMu sync.Mutex
+	serverFailures   map[string][]time.Time
+
+	// Platform of the Docker engine, detected once
+	platformOnce sync.Once
+	platform     string
+
 	// authToken stores the authentication token for SSE/streaming modes
 	authToken string
 	// authTokenWasGenerated indicates whether the token was auto-generated or from environment
 	authTokenWasGenerated bool
+	// resourceAuth validates the access tokens issued by an external authorization server, in streaming mode
+	resourceAuth *resourceAuth
+
+	// Pulls and stars of the catalog servers, refreshed for mcp-find
+	popularityMu sync.RWMutex
+	popularity   catalog.PopularityCache
+
+	// MCP servers discovered on the local network, listed by mcp-find
+	lanServersMu sync.RWMutex
+	lanServers   []lan.Server
+
+	// stdioTransport replaces stdin/stdout for the stdio transport, eg. in the self-test
+	stdioTransport mcp.Transport
+
+	// In-process tools registered by a program embedding the gateway, listed along with the tools of the servers
+	extraTools []ToolRegistration
+	// embeddedReady is called, instead of serving a transport, when the gateway is embedded in another program
+	embeddedReady func()
+
+	// Phases of the startup, recorded with --profile-startup
+	startup *startupProfiler
 }
 
-func NewGateway(config Config, docker docker.Client) *Gateway {
+func NewGateway(config Config, dockerClient docker.Client) *Gateway {
+	dockerClient = docker.WithSecretsCache(dockerClient, config.SecretsCacheTTL)
+
 	var configurator Configurator
 	if config.WorkingSet != "" {
-		configurator = NewWorkingSetConfiguration(config.WorkingSet, oci.NewService(), docker)
+		workingSetConfiguration := NewWorkingSetConfiguration(config.WorkingSet, config.DockerContext, oci.NewService(), dockerClient)
+		workingSetConfiguration.ServerNames = config.ServerNames
+		workingSetConfiguration.ServersMode = config.ServersMode
+		workingSetConfiguration.Dev = config.Dev
+		configurator = workingSetConfiguration
 	} else {
 		// Prepend session-specific paths if SessionName is set
 		registryPath := config.RegistryPath
@@ -109,13 +199,13 @@ func NewGateway(config Config, docker docker.Client) *Gateway {
 			Watch:              config.Watch,
 			McpOAuthDcrEnabled: config.McpOAuthDcrEnabled,
 			sessionName:        config.SessionName,
-			docker:             docker,
+			docker:             dockerClient,
 		}
 	}
 
 	g := &Gateway{
 		Options:                     config.Options,
-		docker:                      docker,
+		docker:                      dockerClient,
 		oauthProviders:              make(map[string]*oauth.Provider),
 		configurator:                configurator,
 		sessionCache:                make(map[*mcp.ServerSession]*ServerSessionCache),
@@ -123,7 +213,9 @@ func NewGateway(config Config, docker docker.Client) *Gateway {
 		serverAvailableCapabilities: make(map[string]*Capabilities),
 		toolRegistrations:           make(map[string]ToolRegistration),
 	}
-	g.clientPool = newClientPool(config.Options, docker, g)
+	g.clientPool = newClientPool(config.Options, dockerClient, g)
+	g.clientPool.instanceID = newGatewayInstanceID()
+	g.clientPool.profile = config.WorkingSet
 
 	return g
 }
@@ -133,16 +225,52 @@ func (g *Gateway) Run(ctx context.Context) error {
 	telemetry.Init()
 
 	// Set up log file redirection if specified
+	var logWriter io.Writer = os.Stderr
 	if g.LogFilePath != "" {
-		logFile, err := os.OpenFile(g.LogFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		logFile, err := logs.OpenRotatingFile(g.LogFilePath, int64(g.LogMaxSize)*1024*1024, g.LogMaxBackups)
 		if err != nil {
 			return fmt.Errorf("failed to open log file %s: %w", g.LogFilePath, err)
 		}
 		defer logFile.Close()
 
 		// Create a multi-writer that writes to both stderr and the log file
-		multiWriter := io.MultiWriter(os.Stderr, logFile)
-		log.SetLogWriter(multiWriter)
+		logWriter = io.MultiWriter(os.Stderr, logFile)
+		log.SetLogWriter(logWriter)
+	}
+
+	// Log the JSON-RPC frames exchanged with the client and the servers, if asked to
+	if g.LogFrames {
+		logs.SetFrameLogging(logs.FrameLogging{
+			Writer: logWriter,
+			Sample: g.LogFrameSample,
+		})
+		defer logs.SetFrameLogging(logs.FrameLogging{})
+	}
+
+	// Limit how many messages each server can log
+	logs.SetRateLimit(logs.RateLimit{
+		Messages: g.LogRateLimit,
+		Interval: g.LogRateInterval,
+	})
+
+	// Tune the pools of connections to the remote servers
+	httpclient.Configure(httpclient.Settings{
+		MaxConnsPerHost:     g.HTTPMaxConnsPerHost,
+		MaxIdleConnsPerHost: g.HTTPMaxIdleConnsPerHost,
+		IdleConnTimeout:     g.HTTPIdleConnTimeout,
+	})
+
+	// Load the webhooks to notify
+	if g.NotificationsPath != "" {
+		notificationsConfig, err := notify.LoadConfig(g.NotificationsPath)
+		if err != nil {
+			return err
+		}
+		g.notifier, err = notify.New(notificationsConfig)
+		if err != nil {
+			return fmt.Errorf("configuring notifications: %w", err)
+		}
+		log.Log("- Notifications configured from", g.NotificationsPath)
 	}
 
 	// Record gateway start
@@ -161,6 +289,8 @@ func (g *Gateway) Run(ctx context.Context) error {
 		go g.periodicMetricExport(ctx)
 	}
 
+	// Deferred first, so that the scratch volumes are removed after the clients are closed.
+	defer g.clientPool.removeScratchVolumes()
 	defer g.clientPool.Close()
 	defer func() {
 		// Clean up all session cache entries
@@ -170,6 +300,10 @@ func (g *Gateway) Run(ctx context.Context) error {
 	}()
 
 	start := time.Now()
+	if g.ProfileStartup {
+		g.startup = newStartupProfiler()
+		defer g.startup.finish()
+	}
 
 	// Listen as early as possible to not lose client connections.
 	var ln net.Listener
@@ -185,7 +319,9 @@ func (g *Gateway) Run(ctx context.Context) error {
 	}
 
 	// Read the configuration.
+	endConfigSpan := g.startup.span("config")
 	configuration, configurationUpdates, stopConfigWatcher, err := g.configurator.Read(ctx)
+	endConfigSpan()
 	g.configuration = configuration
 	if err != nil {
 		return err
@@ -210,10 +346,30 @@ func (g *Gateway) Run(ctx context.Context) error {
 		log.Log("- Interceptors enabled:", strings.Join(g.Interceptors, ", "))
 	}
 
+	log.Log("- Labelling containers with", LabelGatewayID+"="+g.clientPool.instanceID)
+
+	// Load the access policy
+	if g.PolicyPath != "" {
+		accessPolicy, err := policy.Load(g.PolicyPath)
+		if err != nil {
+			return err
+		}
+		g.policy = accessPolicy
+		log.Log("- Access policy loaded from", g.PolicyPath)
+	}
+
+	// Map the authenticated clients to the servers of their profiles
+	endProfilesSpan := g.startup.span("profiles")
+	if err := g.loadProfileViews(ctx); err != nil {
+		return err
+	}
+	endProfilesSpan()
+
 	g.mcpServer = mcp.NewServer(&mcp.Implementation{
-		Name:    "Docker AI MCP Gateway",
-		Version: "2.0.1",
+		Name:    gatewayName,
+		Version: gatewayVersion,
 	}, &mcp.ServerOptions{
+		PageSize: g.PageSize,
 		SubscribeHandler: func(_ context.Context, req *mcp.SubscribeRequest) error {
 			log.Log("- Client subscribed to URI:", req.Params.URI)
 			// The MCP SDK doesn't provide ServerSession in SubscribeHandler because it already
@@ -233,10 +389,17 @@ func (g *Gateway) Run(ctx context.Context) error {
 			// We can't get the ServerSession from the request anymore, so we'll need to handle this differently
 			_, _ = req.Session.ListRoots(ctx, &mcp.ListRootsParams{})
 		},
-		CompletionHandler: nil,
-		InitializedHandler: func(_ context.Context, req *mcp.InitializedRequest) {
+		CompletionHandler: g.completionHandler,
+		InitializedHandler: func(ctx context.Context, req *mcp.InitializedRequest) {
 			clientInfo := req.Session.InitializeParams().ClientInfo
 			log.Log(fmt.Sprintf("- Client initialized %s@%s %s", clientInfo.Name, clientInfo.Version, clientInfo.Title))
+			g.logClientCapabilities(req.Session)
+			g.trackSession(req.Session)
+
+			if g.detectsProjects() {
+				// Listing the roots is a request to the client, it can't be made from the notification handler
+				go g.autoEnableServers(context.WithoutCancel(ctx), req.Session)
+			}
 		},
 		HasPrompts:   true,
 		HasResources: true,
@@ -244,31 +407,86 @@ func (g *Gateway) Run(ctx context.Context) error {
 	})
 
 	// Add interceptor middleware to the server (includes telemetry)
-	middlewares := interceptors.Callbacks(g.LogCalls, g.BlockSecrets, g.OAuthInterceptorEnabled, parsedInterceptors)
+	// Each tool call is identified first, so that all the other middlewares can log its correlation ID
+	middlewares := []mcp.Middleware{interceptors.CorrelationMiddleware()}
+	middlewares = append(middlewares, interceptors.Callbacks(g.LogCalls, g.BlockSecrets, g.OAuthInterceptorEnabled, parsedInterceptors)...)
+	middlewares = append(middlewares, g.sessionActivityMiddleware(), g.coercionMiddleware(), g.instructionsMiddleware(), g.policyMiddleware(), g.profileMiddleware(), g.schemaMiddleware(), g.coalesceMiddleware(), g.budgetMiddleware())
 	if len(middlewares) > 0 {
 		g.mcpServer.AddReceivingMiddleware(middlewares...)
 	}
 
 	// Which docker images are used?
 	// Pull them and verify them if possible.
+	report := configuration.report
 	if !g.Static {
+		endPullSpan := g.startup.span("pull")
 		if err := g.pullAndVerify(ctx, configuration); err != nil {
-			return err
+			// Find out which servers are responsible
+			if !g.findServersWithBrokenImages(ctx, configuration, &report) {
+				return err
+			}
 		}
+		endPullSpan()
+	}
 
-		// When running in a container, find on which network we are running.
-		if os.Getenv("DOCKER_MCP_IN_CONTAINER") == "1" {
-			networks, err := g.guessNetworks(ctx)
-			if err != nil {
-				return fmt.Errorf("guessing network: %w", err)
+	// Servers that can't be started are either skipped or fail the startup.
+	report.Log()
+	if report.HasFailures() {
+		if !g.SkipBroken {
+			if _, isWorkingSet := g.configurator.(*WorkingSetConfiguration); isWorkingSet {
+				return fmt.Errorf("%w\nUse --skip-broken to start the gateway without them", report.Err())
 			}
-			g.clientPool.SetNetworks(networks)
+			return report.Err()
 		}
+
+		log.Log("- Skipping broken servers:", strings.Join(report.FailedServers(), ", "))
+		configuration = configuration.withoutServers(report.FailedServers())
+		g.configuration = g.configuration.withoutServers(report.FailedServers())
+
+		if !g.Static {
+			endPullSpan := g.startup.span("pull")
+			if err := g.pullAndVerify(ctx, configuration); err != nil {
+				return err
+			}
+			endPullSpan()
+		}
+	}
+
+	// When running in a container, find on which network we are running.
+	if !g.Static && os.Getenv("DOCKER_MCP_IN_CONTAINER") == "1" {
+		networks, err := g.guessNetworks(ctx)
+		if err != nil {
+			return fmt.Errorf("guessing network: %w", err)
+		}
+		g.clientPool.SetNetworks(networks)
 	}
 
+	endCapabilitiesSpan := g.startup.span("capabilities")
 	if err := g.reloadConfiguration(ctx, configuration, nil, nil); err != nil {
 		return fmt.Errorf("loading configuration: %w", err)
 	}
+	endCapabilitiesSpan()
+
+	// Keep the popularity of the catalog servers up to date, to rank the results of mcp-find.
+	if g.DynamicTools && !g.DryRun {
+		go g.refreshPopularity(ctx, maps.Clone(configuration.servers))
+	}
+
+	// Look for the MCP servers advertised on the local network, for mcp-find to list them.
+	if g.DynamicTools && !g.DryRun && (g.DiscoverLAN || len(g.DiscoverHosts) > 0) {
+		go g.discoverLANServers(ctx)
+	}
+
+	// Close the sessions of the clients that went away without disconnecting.
+	// A stdio client can't go away without closing the gateway's stdin.
+	if g.SessionIdleTimeout > 0 && !g.DryRun && !strings.EqualFold(g.Transport, "stdio") {
+		go g.closeIdleSessions(ctx)
+	}
+
+	// Report the clients that are acquired and never released, before they pile up as orphaned containers.
+	if g.ClientLeakThreshold > 0 && !g.DryRun {
+		go g.clientPool.detectLeaks(ctx)
+	}
 
 	// When running in Container mode, disable OAuth notification monitoring and authentication
 	inContainer := os.Getenv("DOCKER_MCP_IN_CONTAINER") == "1"
@@ -313,37 +531,106 @@ func (g *Gateway) Run(ctx context.Context) error {
 
 					if err := g.pullAndVerify(ctx, configuration); err != nil {
 						log.Logf("> Unable to pull and verify images: %s", err)
+						g.emit(notify.Event{Type: notify.EventError, Message: fmt.Sprintf("Unable to pull and verify images: %s", err)})
 						continue
 					}
 
 					if err := g.reloadConfiguration(ctx, configuration, nil, nil); err != nil {
 						log.Logf("> Unable to list capabilities: %s", err)
+						g.emit(notify.Event{Type: notify.EventError, Message: fmt.Sprintf("Unable to list capabilities: %s", err)})
 						g.configuration = configuration
 						continue
 					}
+
+					g.emit(notify.Event{
+						Type:    notify.EventReloadCompleted,
+						Message: fmt.Sprintf("Configuration reloaded with %d servers", len(configuration.ServerNames())),
+					})
 				}
 			}
 		}()
 	}
 
+	// Expose the control API on a unix socket, if asked to.
+	if g.ControlSocket != "" && !g.DryRun {
+		if err := g.startControlServer(ctx, g.ControlSocket); err != nil {
+			return err
+		}
+	}
+
+	// Rotate the secrets of the servers on SIGHUP. An embedded gateway leaves the signals to the program embedding it.
+	if !g.DryRun && g.embeddedReady == nil {
+		g.rotateSecretsOnSignal(ctx)
+	}
+
 	log.Log("> Initialized in", time.Since(start))
+	endTransportSpan := g.startup.span("transport")
+	if g.embeddedReady != nil {
+		endTransportSpan()
+		g.startup.finish()
+		log.Log("> Embedded gateway ready")
+		g.embeddedReady()
+		<-ctx.Done()
+		return nil
+	}
 	if g.DryRun {
+		endTransportSpan()
+		g.startup.finish()
 		log.Log("Dry run mode enabled, not starting the server.")
 		return nil
 	}
 
+	g.emit(notify.Event{
+		Type:    notify.EventGatewayStarted,
+		Message: fmt.Sprintf("Gateway started with %d servers", len(g.configuration.ServerNames())),
+		Details: map[string]string{"transport": g.Transport},
+	})
+	defer func() {
+		g.emit(notify.Event{
+			Type:    notify.EventGatewayStopped,
+			Message: "Gateway stopped",
+		})
+
+		// Give the last notifications a chance to be delivered
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		g.notifier.Close(closeCtx)
+	}()
+
 	// Initialize authentication token for SSE and streaming modes
 	// Skip authentication when running in container (DOCKER_MCP_IN_CONTAINER=1)
 	transport := strings.ToLower(g.Transport)
-	if (transport == "sse" || transport == "http" || transport == "streamable" || transport == "streaming" || transport == "streamable-http") && !inContainer {
+	if (transport == "sse" || transport == "http" || transport == "streamable" || transport == "streaming" || transport == "streamable-http" || transport == "websocket" || transport == "ws") && !inContainer {
 		token, wasGenerated, err := getOrGenerateAuthToken()
 		if err != nil {
 			return fmt.Errorf("failed to initialize auth token: %w", err)
 		}
-		g.authToken = token
-		g.authTokenWasGenerated = wasGenerated
+		// With an identity provider, only a token explicitly set in the environment bypasses it
+		if !wasGenerated || g.OAuthIssuer == "" {
+			g.authToken = token
+			g.authTokenWasGenerated = wasGenerated
+		}
 	}
 
+	if g.OAuthIssuer != "" {
+		// The sse transport doesn't pass the token info on to the MCP requests, the policy couldn't check the identities
+		if transport != "http" && transport != "streamable" && transport != "streaming" && transport != "streamable-http" {
+			return fmt.Errorf("--oauth-issuer requires the streaming transport")
+		}
+		resource := g.OAuthResource
+		if resource == "" {
+			resource = formatGatewayURL(g.Port, "/mcp")
+		}
+		resourceAuth, err := newResourceAuth(resource, g.OAuthIssuer, g.OAuthAudience, g.OAuthScopes, g.OAuthGroupsClaim)
+		if err != nil {
+			return err
+		}
+		g.resourceAuth = resourceAuth
+	}
+
+	endTransportSpan()
+	g.startup.finish()
+
 	// Start the server
 	switch transport {
 	case "stdio":
@@ -370,7 +657,13 @@ func (g *Gateway) Run(ctx context.Context) error {
 		log.Log("> Start streaming server on port", g.Port)
 		endpoint := "/mcp"
 		url := formatGatewayURL(g.Port, endpoint)
-		if inContainer {
+		if g.resourceAuth != nil {
+			log.Logf("> Gateway URL: %s", url)
+			log.Logf("> Use an access token issued by %s (metadata: %s)", g.OAuthIssuer, g.resourceAuth.metadataURL)
+			if g.authToken != "" {
+				log.Logf("> Or the Bearer token from MCP_GATEWAY_AUTH_TOKEN environment variable")
+			}
+		} else if inContainer {
 			log.Logf("> Gateway URL: %s", url)
 			log.Logf("> Authentication disabled (running in container)")
 		} else if g.authTokenWasGenerated {
@@ -382,8 +675,23 @@ func (g *Gateway) Run(ctx context.Context) error {
 		}
 		return g.startStreamingServer(ctx, ln)
 
+	case "websocket", "ws":
+		log.Log("> Start websocket server on port", g.Port)
+		url := fmt.Sprintf("ws://localhost:%d/ws", g.Port)
+		if inContainer {
+			log.Logf("> Gateway URL: %s", url)
+			log.Logf("> Authentication disabled (running in container)")
+		} else if g.authTokenWasGenerated {
+			log.Logf("> Gateway URL: %s", url)
+			log.Logf("> Use Bearer token: %s", formatBearerToken(g.authToken))
+		} else {
+			log.Logf("> Gateway URL: %s", url)
+			log.Logf("> Use Bearer token from MCP_GATEWAY_AUTH_TOKEN environment variable")
+		}
+		return g.startWebSocketServer(ctx, ln)
+
 	default:
-		return fmt.Errorf("unknown transport %q, expected 'stdio', 'sse' or 'streaming", g.Transport)
+		return fmt.Errorf("unknown transport %q, expected 'stdio', 'sse', 'streaming' or 'websocket'", g.Transport)
 	}
 }
 
@@ -426,6 +734,34 @@ func (g *Gateway) GetSessionCache(ss *mcp.ServerSession) *ServerSessionCache {
 	return g.sessionCache[ss]
 }
 
+// trackSession reports a client session on the /events stream and forgets about it once the client disconnects.
+func (g *Gateway) trackSession(ss *mcp.ServerSession) {
+	g.touchSession(ss, false)
+	telemetry.RecordActiveSessions(context.Background(), g.Transport, 1)
+
+	clientName := sessionClientName(ss)
+	g.emit(notify.Event{
+		Type:    notify.EventSessionConnected,
+		Message: fmt.Sprintf("Client %s connected", clientName),
+		Details: map[string]string{"client": clientName},
+	})
+
+	go func() {
+		_ = ss.Wait()
+		telemetry.RecordActiveSessions(context.Background(), g.Transport, -1)
+		if !g.forgetSession(ss) {
+			// Already forgotten, when it was closed for being idle
+			return
+		}
+
+		g.emit(notify.Event{
+			Type:    notify.EventSessionDisconnected,
+			Message: fmt.Sprintf("Client %s disconnected", clientName),
+			Details: map[string]string{"client": clientName},
+		})
+	}()
+}
+
 // RemoveSessionCache removes the cached information for a server session
 func (g *Gateway) RemoveSessionCache(ss *mcp.ServerSession) {
 	g.sessionCacheMu.Lock()
@@ -552,10 +888,25 @@ func (g *Gateway) startProvider(ctx context.Context, serverName string) {
 
 	// Create and start provider
 	provider := oauth.NewProvider(serverName, reloadFn)
+	provider.OnRefreshError = func(err error) {
+		g.emit(notify.Event{
+			Type:    notify.EventOAuthFailure,
+			Server:  serverName,
+			Message: fmt.Sprintf("Can't refresh the OAuth token of %s", serverName),
+			Details: map[string]string{"error": err.Error()},
+		})
+	}
+	// Connections kept to the server switch to a refreshed token, only reconnecting when the server changed
+	provider.OnTokenRefresh = func(ctx context.Context) bool {
+		return g.clientPool.SwapOAuthTokens(ctx, serverName)
+	}
 	g.oauthProviders[serverName] = provider
 
 	// Wrapper goroutine handles cleanup after provider exits
+	configuredScopes := g.configuredOAuthScopes(serverName)
 	go func() {
+		oauth.WarnOnBroaderScopes(ctx, serverName, configuredScopes, g.clientPool.profile)
+
 		provider.Run(ctx) // Blocks until provider stops
 
 		// Provider exited - remove from map
@@ -567,6 +918,20 @@ func (g *Gateway) startProvider(ctx context.Context, serverName string) {
 	}()
 }
 
+// configuredOAuthScopes returns the OAuth scopes configured for a server by the catalog or the profile.
+func (g *Gateway) configuredOAuthScopes(serverName string) []string {
+	serverConfig, _, found := g.configuration.Find(serverName)
+	if !found || serverConfig == nil {
+		return nil
+	}
+	return serverConfig.Spec.ConfiguredOAuthScopes(nil)
+}
+
+// oauthScopes returns the space-separated OAuth scopes to request when authorizing a server.
+func (g *Gateway) oauthScopes(serverName string) string {
+	return strings.Join(g.configuredOAuthScopes(serverName), " ")
+}
+
 // stopProvider stops an OAuth provider goroutine for a server
 func (g *Gateway) stopProvider(serverName string) {
 	g.providersMu.Lock()
@@ -578,6 +943,35 @@ func (g *Gateway) stopProvider(serverName string) {
 	}
 }
 
+// revokeOAuthServer stops the OAuth provider of a server whose token was revoked,
+// closes its connections and removes its capabilities from active sessions.
+// With Docker Desktop, the server comes back when it's authorized again. Otherwise, nothing tells the gateway
+// about the new token: the server has to be added again with mcp-add, or the gateway restarted.
+func (g *Gateway) revokeOAuthServer(ctx context.Context, serverName string) error {
+	g.stopProvider(serverName)
+	g.clientPool.InvalidateOAuthClients(serverName)
+
+	if _, _, found := g.configuration.Find(serverName); !found {
+		return nil
+	}
+	if err := g.removeServerConfiguration(ctx, serverName); err != nil {
+		return err
+	}
+
+	// Tools can't be called through mcp-exec either
+	g.capabilitiesMu.Lock()
+	if availableCaps := g.serverAvailableCapabilities[serverName]; availableCaps != nil {
+		for _, tool := range availableCaps.Tools {
+			delete(g.toolRegistrations, tool.Tool.Name)
+		}
+		delete(g.serverAvailableCapabilities, serverName)
+	}
+	g.capabilitiesMu.Unlock()
+
+	log.Log("> OAuth access revoked for", serverName)
+	return nil
+}
+
 // routeEventToProvider routes SSE events to the appropriate provider
 func (g *Gateway) routeEventToProvider(event oauth.Event) {
 	g.providersMu.RLock()
@@ -0,0 +1,199 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// MinifySchemasMetaKey is the key, in the _meta of the initialize request, with which a client
+	// turns the minification of the tool schemas on or off for its session.
+	MinifySchemasMetaKey = "io.docker.mcp/minifySchemas"
+
+	DefaultSchemaDescriptionMaxLength = 100
+	DefaultSchemaEnumMaxValues        = 10
+
+	toolSchemaURITemplate = "docker://tools/{name}/schema"
+	toolSchemaURIPrefix   = "docker://tools/"
+	toolSchemaURISuffix   = "/schema"
+)
+
+// schemaMinification tells how the input schemas of the tools are shrunk before they're listed to a client.
+type schemaMinification struct {
+	// DescriptionMaxLength is the number of characters after which descriptions are cut.
+	DescriptionMaxLength int
+	// EnumMaxValues is the number of allowed values over which an enum is dropped.
+	EnumMaxValues int
+}
+
+// sessionMinifiesSchemas tells whether the tool schemas listed to a session are minified: the client
+// decides with the _meta of its initialize request, otherwise --minify-schemas does.
+func (g *Gateway) sessionMinifiesSchemas(ss *mcp.ServerSession) bool {
+	if ss != nil {
+		if params := ss.InitializeParams(); params != nil {
+			if minify, ok := params.Meta[MinifySchemasMetaKey].(bool); ok {
+				return minify
+			}
+		}
+	}
+	return g.MinifySchemas
+}
+
+func (g *Gateway) schemaMinification() schemaMinification {
+	return schemaMinification{
+		DescriptionMaxLength: g.SchemaDescriptionMaxLength,
+		EnumMaxValues:        g.SchemaEnumMaxValues,
+	}
+}
+
+// schemaMiddleware minifies the input schemas of the tools listed to the sessions that want it.
+// The full schemas stay available through the tool schema resource.
+func (g *Gateway) schemaMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if err != nil {
+				return result, err
+			}
+
+			list, ok := result.(*mcp.ListToolsResult)
+			if !ok {
+				return result, nil
+			}
+			session, ok := req.GetSession().(*mcp.ServerSession)
+			if !ok || !g.sessionMinifiesSchemas(session) {
+				return result, nil
+			}
+
+			minification := g.schemaMinification()
+			tools := make([]*mcp.Tool, 0, len(list.Tools))
+			for _, tool := range list.Tools {
+				minified := *tool
+				minified.InputSchema = minification.minify(tool.InputSchema)
+				tools = append(tools, &minified)
+			}
+			list.Tools = tools
+			return list, nil
+		}
+	}
+}
+
+// minify returns a copy of a schema without examples, with shorter descriptions and without long enums.
+// A schema that can't be read as JSON is returned as is.
+func (m schemaMinification) minify(schema any) any {
+	if schema == nil {
+		return nil
+	}
+
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return schema
+	}
+	var node map[string]any
+	if err := json.Unmarshal(buf, &node); err != nil {
+		return schema
+	}
+
+	m.minifyNode(node)
+	return node
+}
+
+func (m schemaMinification) minifyNode(node map[string]any) {
+	delete(node, "examples")
+	delete(node, "example")
+
+	description, _ := node["description"].(string)
+	if enum, ok := node["enum"].([]any); ok && m.EnumMaxValues > 0 && len(enum) > m.EnumMaxValues {
+		delete(node, "enum")
+		description = strings.TrimSpace(fmt.Sprintf("%s (one of %d values)", truncateDescription(description, m.DescriptionMaxLength), len(enum)))
+	} else {
+		description = truncateDescription(description, m.DescriptionMaxLength)
+	}
+	if description != "" {
+		node["description"] = description
+	}
+
+	// Only the keywords holding subschemas are walked: a property can be named description or examples
+	for _, keyword := range []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"} {
+		if schemas, ok := node[keyword].(map[string]any); ok {
+			for _, schema := range schemas {
+				m.minifySubschema(schema)
+			}
+		}
+	}
+	for _, keyword := range []string{"items", "additionalProperties", "additionalItems", "contains", "propertyNames", "not", "if", "then", "else", "unevaluatedItems", "unevaluatedProperties"} {
+		m.minifySubschema(node[keyword])
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf", "prefixItems", "items"} {
+		if schemas, ok := node[keyword].([]any); ok {
+			for _, schema := range schemas {
+				m.minifySubschema(schema)
+			}
+		}
+	}
+}
+
+func (m schemaMinification) minifySubschema(schema any) {
+	if node, ok := schema.(map[string]any); ok {
+		m.minifyNode(node)
+	}
+}
+
+// truncateDescription cuts a text after maxLength characters, if maxLength is positive.
+func truncateDescription(text string, maxLength int) string {
+	runes := []rune(text)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxLength])) + "…"
+}
+
+// toolSchemaResourceTemplate serves the full input schema of each tool, for the clients that list minified schemas.
+func (g *Gateway) toolSchemaResourceTemplate() ResourceTemplateRegistration {
+	return ResourceTemplateRegistration{
+		ResourceTemplate: mcp.ResourceTemplate{
+			URITemplate: toolSchemaURITemplate,
+			Name:        "tool-schema",
+			Description: "Full input schema of a tool, with the descriptions, enums and examples left out of the minified schemas",
+			MIMEType:    "application/json",
+		},
+		Handler: func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			toolName, ok := toolNameOfSchemaURI(req.Params.URI)
+			if !ok {
+				return nil, mcp.ResourceNotFoundError(req.Params.URI)
+			}
+
+			g.capabilitiesMu.RLock()
+			registration, found := g.toolRegistrations[toolName]
+			g.capabilitiesMu.RUnlock()
+			if !found {
+				return nil, mcp.ResourceNotFoundError(req.Params.URI)
+			}
+
+			buf, err := json.MarshalIndent(registration.Tool.InputSchema, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("marshalling the schema of %s: %w", toolName, err)
+			}
+			return &mcp.ReadResourceResult{
+				Contents: []*mcp.ResourceContents{{
+					URI:      req.Params.URI,
+					MIMEType: "application/json",
+					Text:     string(buf),
+				}},
+			}, nil
+		},
+	}
+}
+
+func toolNameOfSchemaURI(uri string) (string, bool) {
+	name, found := strings.CutPrefix(uri, toolSchemaURIPrefix)
+	if !found {
+		return "", false
+	}
+	name, found = strings.CutSuffix(name, toolSchemaURISuffix)
+	return name, found && name != ""
+}
@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinifySchema(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{
+				"type":        "string",
+				"description": "The search query, in the syntax of the search engine",
+				"examples":    []any{"docker", "mcp"},
+			},
+			"language": map[string]any{
+				"type":        "string",
+				"description": "Language",
+				"enum":        []any{"go", "java", "python", "rust"},
+			},
+			"format": map[string]any{
+				"type": "string",
+				"enum": []any{"json", "text"},
+			},
+			// A property named like a keyword is kept
+			"examples": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "string", "description": "An example to look for"},
+			},
+		},
+	}
+
+	minified := schemaMinification{DescriptionMaxLength: 10, EnumMaxValues: 3}.minify(schema)
+
+	buf, err := json.Marshal(minified)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"query": {"type": "string", "description": "The search…"},
+			"language": {"type": "string", "description": "Language (one of 4 values)"},
+			"format": {"type": "string", "enum": ["json", "text"]},
+			"examples": {"type": "array", "items": {"type": "string", "description": "An example…"}}
+		}
+	}`, string(buf))
+
+	// The original schema is left untouched
+	assert.Contains(t, schema["properties"].(map[string]any)["query"], "examples")
+}
+
+func TestMinifyTypedSchema(t *testing.T) {
+	schema := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"name": {Type: "string", Description: "Name", Examples: []any{"docker"}},
+		},
+	}
+
+	minified := schemaMinification{DescriptionMaxLength: 100, EnumMaxValues: 10}.minify(schema)
+
+	buf, err := json.Marshal(minified)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"object","properties":{"name":{"type":"string","description":"Name"}}}`, string(buf))
+}
+
+func TestToolNameOfSchemaURI(t *testing.T) {
+	name, ok := toolNameOfSchemaURI("docker://tools/github:search/schema")
+	assert.True(t, ok)
+	assert.Equal(t, "github:search", name)
+
+	_, ok = toolNameOfSchemaURI("docker://tools//schema")
+	assert.False(t, ok)
+	_, ok = toolNameOfSchemaURI("docker://servers/github/card")
+	assert.False(t, ok)
+}
+
+func TestToolSchemaResource(t *testing.T) {
+	schema := &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{
+		"query": {Type: "string", Description: "The search query"},
+	}}
+	g := &Gateway{toolRegistrations: map[string]ToolRegistration{
+		"search": {ServerName: "github", Tool: &mcp.Tool{Name: "search", InputSchema: schema}},
+	}}
+	handler := g.toolSchemaResourceTemplate().Handler
+
+	result, err := handler(t.Context(), &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: "docker://tools/search/schema"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"object","properties":{"query":{"type":"string","description":"The search query"}}}`, result.Contents[0].Text)
+
+	_, err = handler(t.Context(), &mcp.ReadResourceRequest{Params: &mcp.ReadResourceParams{URI: "docker://tools/unknown/schema"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,145 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/go-units"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// ScratchDir is where the scratch volume of a server is mounted in its containers.
+// It's also exposed to servers through the TMPDIR and MCP_SCRATCH_DIR environment variables.
+const ScratchDir = "/scratch"
+
+// scratchVolumeLabel is the label of the scratch volumes, whose value is the name of the server.
+const scratchVolumeLabel = "docker-mcp-scratch"
+
+const (
+	scratchRemoveAttempts = 10
+	scratchRemoveDelay    = 500 * time.Millisecond
+)
+
+var unsafeVolumeNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+func scratchVolumeName(serverName string) string {
+	return "docker-mcp-scratch-" + unsafeVolumeNameChars.ReplaceAllString(serverName, "-")
+}
+
+// ParseScratchSize parses the size of the scratch volumes, like 256m or 1g.
+func ParseScratchSize(size string) (int64, error) {
+	bytes, err := units.RAMInBytes(size)
+	if err != nil {
+		return 0, err
+	}
+	if bytes <= 0 {
+		return 0, fmt.Errorf("size must be positive: %s", size)
+	}
+	return bytes, nil
+}
+
+// scratchArgsAndEnv creates, on first use, the scratch volume of a server and returns
+// the docker run arguments and the environment that mount it.
+// The volume is a tmpfs limited to --scratch-size, so temporary files can't fill the host's disk.
+func (cp *clientPool) scratchArgsAndEnv(ctx context.Context, serverName string) ([]string, []string, error) {
+	volumeName, err := cp.scratchVolume(ctx, serverName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args := []string{"-v", volumeName + ":" + ScratchDir, "-e", "TMPDIR", "-e", "MCP_SCRATCH_DIR"}
+	env := []string{"TMPDIR=" + ScratchDir, "MCP_SCRATCH_DIR=" + ScratchDir}
+	return args, env, nil
+}
+
+func (cp *clientPool) scratchVolume(ctx context.Context, serverName string) (string, error) {
+	cp.scratchMu.Lock()
+	defer cp.scratchMu.Unlock()
+
+	if volumeName, found := cp.scratchVolumes[serverName]; found {
+		return volumeName, nil
+	}
+
+	size, err := ParseScratchSize(cp.ScratchSize)
+	if err != nil {
+		return "", fmt.Errorf("invalid scratch size: %w", err)
+	}
+
+	volumeName := scratchVolumeName(serverName)
+	driverOpts := map[string]string{
+		"type":   "tmpfs",
+		"device": "tmpfs",
+		"o":      "size=" + strconv.FormatInt(size, 10),
+	}
+	labels := map[string]string{
+		"docker-mcp":       "true",
+		scratchVolumeLabel: serverName,
+	}
+	if err := cp.docker.CreateVolume(ctx, volumeName, driverOpts, labels); err != nil {
+		return "", fmt.Errorf("creating the scratch volume of %s: %w", serverName, err)
+	}
+
+	if cp.scratchVolumes == nil {
+		cp.scratchVolumes = map[string]string{}
+	}
+	cp.scratchVolumes[serverName] = volumeName
+	return volumeName, nil
+}
+
+// removeScratchVolume removes the scratch volume of a server, once its containers are gone.
+func (cp *clientPool) removeScratchVolume(ctx context.Context, serverName string) {
+	cp.scratchMu.Lock()
+	volumeName, found := cp.scratchVolumes[serverName]
+	delete(cp.scratchVolumes, serverName)
+	cp.scratchMu.Unlock()
+
+	if !found {
+		return
+	}
+
+	// Containers are removed asynchronously, after their session is closed.
+	var err error
+	for range scratchRemoveAttempts {
+		err = cp.docker.RemoveVolume(ctx, volumeName, false)
+		if err == nil || cerrdefs.IsNotFound(err) {
+			log.Log("  - Removed the scratch volume of", serverName)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(scratchRemoveDelay):
+			continue
+		}
+		break
+	}
+
+	log.Logf("  ! Failed to remove the scratch volume %s of %s: %s", volumeName, serverName, err)
+}
+
+// removeScratchVolumes removes the scratch volumes of all the servers, when the gateway stops.
+func (cp *clientPool) removeScratchVolumes() {
+	cp.scratchMu.Lock()
+	var serverNames []string
+	for serverName := range cp.scratchVolumes {
+		serverNames = append(serverNames, serverName)
+	}
+	cp.scratchMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, serverName := range serverNames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cp.removeScratchVolume(context.Background(), serverName)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+type fakeVolumes struct {
+	docker.Client
+	created    map[string]map[string]string
+	removed    []string
+	createdFor []string
+}
+
+func (f *fakeVolumes) CreateVolume(_ context.Context, name string, driverOpts, labels map[string]string) error {
+	if f.created == nil {
+		f.created = map[string]map[string]string{}
+	}
+	f.created[name] = driverOpts
+	f.createdFor = append(f.createdFor, labels[scratchVolumeLabel])
+	return nil
+}
+
+func (f *fakeVolumes) RemoveVolume(_ context.Context, name string, _ bool) error {
+	f.removed = append(f.removed, name)
+	return nil
+}
+
+func TestScratchArgsAndEnv(t *testing.T) {
+	volumes := &fakeVolumes{}
+	cp := newClientPool(Options{ScratchSize: "1m"}, volumes, nil)
+
+	args, env, err := cp.scratchArgsAndEnv(t.Context(), "io.github.acme/files")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-v", "docker-mcp-scratch-io.github.acme-files:/scratch", "-e", "TMPDIR", "-e", "MCP_SCRATCH_DIR"}, args)
+	assert.Equal(t, []string{"TMPDIR=/scratch", "MCP_SCRATCH_DIR=/scratch"}, env)
+	assert.Equal(t, map[string]string{"type": "tmpfs", "device": "tmpfs", "o": "size=1048576"}, volumes.created["docker-mcp-scratch-io.github.acme-files"])
+
+	// The volume is created once per server
+	_, _, err = cp.scratchArgsAndEnv(t.Context(), "io.github.acme/files")
+	require.NoError(t, err)
+	_, _, err = cp.scratchArgsAndEnv(t.Context(), "fetch")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"io.github.acme/files", "fetch"}, volumes.createdFor)
+
+	cp.removeScratchVolume(t.Context(), "fetch")
+	assert.Equal(t, []string{"docker-mcp-scratch-fetch"}, volumes.removed)
+
+	cp.removeScratchVolumes()
+	assert.ElementsMatch(t, []string{"docker-mcp-scratch-fetch", "docker-mcp-scratch-io.github.acme-files"}, volumes.removed)
+
+	// Nothing left to remove
+	cp.removeScratchVolumes()
+	assert.Len(t, volumes.removed, 2)
+}
+
+func TestParseScratchSize(t *testing.T) {
+	size, err := ParseScratchSize("256m")
+	require.NoError(t, err)
+	assert.Equal(t, int64(256*1024*1024), size)
+
+	_, err = ParseScratchSize("lots")
+	require.Error(t, err)
+	_, err = ParseScratchSize("0")
+	require.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/desktop"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+// setDockerDesktopSecret stores a secret in Docker Desktop's secret store.
+func setDockerDesktopSecret(ctx context.Context, name, value string) error {
+	if err := desktop.NewSecretsClient().SetJfsSecret(ctx, desktop.Secret{Name: name, Value: value}); err != nil {
+		return fmt.Errorf("setting secret %s in Docker Desktop: %w", name, err)
+	}
+
+	// The secrets read from Docker Desktop are cached. Best effort, the cache expires anyway.
+	_ = docker.InvalidateSecretsCache()
+	return nil
+}
+
+// writeSecretToFile sets a secret in a .env file, replacing its previous value. The file is created if needed.
+func writeSecretToFile(path, name, value string) error {
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("the value of %s can't be written to %s, it has several lines", name, path)
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading secrets from %s: %w", path, err)
+	}
+
+	var lines []string
+	replaced := false
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if key, _, ok := strings.Cut(strings.TrimSpace(line), "="); ok && key == name && !strings.HasPrefix(line, "#") {
+			line = name + "=" + value
+			replaced = true
+		}
+		lines = append(lines, line)
+	}
+	if !replaced {
+		lines = append(lines, name+"="+value)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		return fmt.Errorf("writing secrets to %s: %w", path, err)
+	}
+	return nil
+}
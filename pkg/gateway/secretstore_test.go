@@ -0,0 +1,163 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+// secretStoringConfigurator records the secrets it's asked to store.
+type secretStoringConfigurator struct {
+	Configurator
+	stored map[string]string
+}
+
+func (c *secretStoringConfigurator) SetSecret(_ context.Context, serverName, secretName, value string) error {
+	c.stored[serverName+"/"+secretName] = value
+	return nil
+}
+
+func TestMcpSecretSet(t *testing.T) {
+	telemetry.Init()
+
+	configurator := &secretStoringConfigurator{stored: map[string]string{}}
+	g := &Gateway{
+		configurator: configurator,
+		configuration: Configuration{
+			servers: map[string]catalog.Server{
+				"github": {Name: "github", Image: "mcp/github", Secrets: []catalog.Secret{{Name: "github.token", Env: "GITHUB_TOKEN"}}},
+			},
+		},
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	tool := g.createMcpSecretSetTool()
+	server.AddTool(tool.Tool, tool.Handler)
+
+	connect := func(options *mcp.ClientOptions) *mcp.ClientSession {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		_, err := server.Connect(t.Context(), serverTransport, nil)
+		require.NoError(t, err)
+		session, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, options).Connect(t.Context(), clientTransport, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { session.Close() })
+		return session
+	}
+	call := func(session *mcp.ClientSession, args map[string]any) string {
+		result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "mcp-secret-set", Arguments: args})
+		require.NoError(t, err)
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	session := connect(nil)
+
+	text := call(session, map[string]any{"server": "github", "secret": "github.token", "value": "ghp_123"})
+	assert.Contains(t, text, "Successfully set secret 'github.token'")
+	assert.NotContains(t, text, "ghp_123")
+	assert.Equal(t, "ghp_123", configurator.stored["github/github.token"])
+	assert.Equal(t, "ghp_123", g.configuration.secrets["github.token"])
+
+	text = call(session, map[string]any{"server": "github", "secret": "slack.token", "value": "xoxb"})
+	assert.Contains(t, text, "Its secrets are: github.token")
+
+	// Without a value, the client must support elicitation
+	text = call(session, map[string]any{"server": "github", "secret": "github.token"})
+	assert.Contains(t, text, "docker mcp secret set github.token=<value>")
+
+	var elicitations []string
+	eliciting := connect(&mcp.ClientOptions{
+		ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			elicitations = append(elicitations, req.Params.Message)
+			if strings.Contains(req.Params.Message, "Do you want to replace it") {
+				return &mcp.ElicitResult{Action: "accept", Content: map[string]any{"confirm": true}}, nil
+			}
+			return &mcp.ElicitResult{Action: "accept", Content: map[string]any{"value": "ghp_456"}}, nil
+		},
+	})
+	text = call(eliciting, map[string]any{"server": "github", "secret": "github.token"})
+	assert.Contains(t, text, "Successfully set secret 'github.token'")
+	assert.Equal(t, "ghp_456", configurator.stored["github/github.token"])
+	assert.Contains(t, elicitations[0], "is already set. Enter a new value to replace it")
+
+	// A secret that is already set is only replaced with a value of the model if the user confirms it
+	text = call(session, map[string]any{"server": "github", "secret": "github.token", "value": "ghp_789"})
+	assert.Contains(t, text, "is already set")
+	assert.Equal(t, "ghp_456", configurator.stored["github/github.token"])
+
+	text = call(eliciting, map[string]any{"server": "github", "secret": "github.token", "value": "ghp_789"})
+	assert.Contains(t, text, "Successfully set secret 'github.token'")
+	assert.Equal(t, "ghp_789", configurator.stored["github/github.token"])
+	assert.Equal(t, "ghp_789", g.configuration.secrets["github.token"])
+}
+
+func TestMcpSecretSetProfile(t *testing.T) {
+	telemetry.Init()
+
+	configurator := &secretStoringConfigurator{stored: map[string]string{}}
+	g := newProfileTestGateway()
+	g.configurator = configurator
+	g.configuration = Configuration{
+		servers: map[string]catalog.Server{
+			"github": {Name: "github", Image: "mcp/github", Secrets: []catalog.Secret{{Name: "github.token", Env: "GITHUB_TOKEN"}}},
+		},
+	}
+
+	result := callAsProfileClient(t, g.createMcpSecretSetTool(), map[string]any{"server": "github", "secret": "github.token", "value": "ghp_123"})
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "isn't in your profile")
+	assert.Empty(t, configurator.stored)
+}
+
+func TestSetSecretConcurrently(t *testing.T) {
+	g := &Gateway{}
+	shared := map[string]string{"github.token": "ghp_123"}
+	g.replaceSecrets(shared)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			g.setSecret(fmt.Sprintf("secret%d", i), "value")
+		}()
+		go func() {
+			defer wg.Done()
+			_ = g.secret("github.token")
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, g.configuration.secrets, 21)
+	// The map shared with the servers being started is never written to
+	assert.Len(t, shared, 1)
+}
+
+func TestWriteSecretToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+
+	// The file is created if needed
+	require.NoError(t, writeSecretToFile(path, "github.token", "old"))
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "github.token=old\n", string(buf))
+
+	require.NoError(t, os.WriteFile(path, []byte("# github.token=comment\ngithub.token=old\nslack.token=xoxb\n"), 0o600))
+	require.NoError(t, writeSecretToFile(path, "github.token", "new"))
+	require.NoError(t, writeSecretToFile(path, "notion.token", "secret"))
+
+	buf, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# github.token=comment\ngithub.token=new\nslack.token=xoxb\nnotion.token=secret\n", string(buf))
+
+	require.Error(t, writeSecretToFile(path, "github.token", "multi\nline"))
+}
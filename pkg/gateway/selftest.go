@@ -0,0 +1,399 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+const (
+	selfTestServerName = "selftest"
+	selfTestResource   = "selftest://status"
+	// selfTestTimeout is how long each check can take.
+	selfTestTimeout = 10 * time.Second
+)
+
+// SelfTestResult is the outcome of the self-test of a capability.
+type SelfTestResult struct {
+	Capability string        `json:"capability"`
+	Passed     bool          `json:"passed"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// SelfTest boots an ephemeral gateway with a built-in test server and exercises the capabilities
+// a client relies on: listing and calling tools, prompts, resources, subscriptions, progress, cancellation and reload.
+// The test server runs in-process, behind the gateway's remote server support, so no image is needed.
+func SelfTest(ctx context.Context, dockerClient docker.Client, options Options) ([]SelfTestResult, error) {
+	server := newSelfTestServer()
+	url, stopServer, err := server.serve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting test server: %w", err)
+	}
+	defer stopServer()
+
+	options.Transport = "stdio"
+	options.Port = 0
+	options.DryRun = false
+	options.ControlSocket = ""
+	g := NewGateway(Config{Options: options}, dockerClient)
+	g.configurator = &staticConfigurator{configuration: selfTestConfiguration(url)}
+
+	// The gateway is served over in-memory pipes instead of stdin/stdout.
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	g.stdioTransport = serverTransport
+
+	runCtx, stopGateway := context.WithCancel(ctx)
+	defer stopGateway()
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- g.Run(runCtx)
+		stopGateway()
+	}()
+
+	var (
+		progressMu sync.Mutex
+		progress   []float64
+	)
+	client := mcp.NewClient(&mcp.Implementation{Name: "docker-mcp-self-test", Version: "1.0.0"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			progressMu.Lock()
+			progress = append(progress, req.Params.Progress)
+			progressMu.Unlock()
+		},
+	})
+	session, err := client.Connect(runCtx, clientTransport, nil)
+	if err != nil {
+		select {
+		case err := <-runErr:
+			if err != nil {
+				return nil, fmt.Errorf("starting gateway: %w", err)
+			}
+		default:
+		}
+		return nil, fmt.Errorf("connecting to gateway: %w", err)
+	}
+	defer session.Close()
+
+	checks := []struct {
+		capability string
+		check      func(ctx context.Context) error
+	}{
+		{"tools/list", func(ctx context.Context) error {
+			return checkSelfTestTools(ctx, session)
+		}},
+		{"tools/call", func(ctx context.Context) error {
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "selftest_echo", Arguments: map[string]any{"message": "hello"}})
+			if err != nil {
+				return err
+			}
+			if result.IsError || len(result.Content) == 0 {
+				return errors.New("unexpected result")
+			}
+			if text, ok := result.Content[0].(*mcp.TextContent); !ok || text.Text != "hello" {
+				return errors.New("the message was not echoed")
+			}
+			return nil
+		}},
+		{"prompts", func(ctx context.Context) error {
+			result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "selftest_greet", Arguments: map[string]string{"name": "docker"}})
+			if err != nil {
+				return err
+			}
+			if len(result.Messages) == 0 {
+				return errors.New("prompt has no messages")
+			}
+			return nil
+		}},
+		{"resources", func(ctx context.Context) error {
+			result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: selfTestResource})
+			if err != nil {
+				return err
+			}
+			if len(result.Contents) == 0 || result.Contents[0].Text != "ok" {
+				return errors.New("unexpected resource contents")
+			}
+			return nil
+		}},
+		{"subscriptions", func(ctx context.Context) error {
+			if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: selfTestResource}); err != nil {
+				return err
+			}
+			return session.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: selfTestResource})
+		}},
+		{"progress", func(ctx context.Context) error {
+			params := &mcp.CallToolParams{
+				Meta:      mcp.Meta{"progressToken": "selftest"},
+				Name:      "selftest_progress",
+				Arguments: map[string]any{},
+			}
+			if _, err := session.CallTool(ctx, params); err != nil {
+				return err
+			}
+			// Notifications can be delivered right after the result
+			deadline := time.Now().Add(time.Second)
+			for {
+				progressMu.Lock()
+				received := len(progress)
+				progressMu.Unlock()
+				if received >= selfTestProgressSteps {
+					return nil
+				}
+				if time.Now().After(deadline) {
+					return fmt.Errorf("received %d progress notifications out of %d", received, selfTestProgressSteps)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}},
+		{"cancellation", func(ctx context.Context) error {
+			callCtx, cancel := context.WithCancel(ctx)
+			go func() {
+				select {
+				case <-server.slowStarted:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+			_, _ = session.CallTool(callCtx, &mcp.CallToolParams{Name: "selftest_slow", Arguments: map[string]any{}})
+			cancel()
+
+			select {
+			case <-server.slowCancelled:
+				return nil
+			case <-ctx.Done():
+				return errors.New("the server didn't see the cancellation")
+			}
+		}},
+		{"reload", func(ctx context.Context) error {
+			unlock := g.lockServer(selfTestServerName)
+			err := g.ReloadServer(ctx, selfTestServerName)
+			unlock()
+			if err != nil {
+				return err
+			}
+			return checkSelfTestTools(ctx, session)
+		}},
+	}
+
+	var results []SelfTestResult
+	for _, c := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+		start := time.Now()
+		err := c.check(checkCtx)
+		cancel()
+
+		result := SelfTestResult{
+			Capability: c.capability,
+			Passed:     err == nil,
+			Duration:   time.Since(start),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func checkSelfTestTools(ctx context.Context, session *mcp.ClientSession) error {
+	tools, err := session.ListTools(ctx, &mcp.ListToolsParams{})
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	for _, expected := range []string{"selftest_echo", "selftest_progress", "selftest_slow"} {
+		if !slices.Contains(names, expected) {
+			return fmt.Errorf("tool %s is not listed", expected)
+		}
+	}
+	return nil
+}
+
+func selfTestConfiguration(url string) Configuration {
+	return Configuration{
+		serverNames: []string{selfTestServerName},
+		servers: map[string]catalog.Server{
+			selfTestServerName: {
+				Name:        selfTestServerName,
+				Type:        "remote",
+				Description: "Built-in server of the gateway self-test",
+				Remote: catalog.Remote{
+					URL:       url,
+					Transport: "streamable-http",
+				},
+			},
+		},
+		config:  map[string]map[string]any{},
+		tools:   config.ToolsConfig{ServerTools: map[string][]string{}},
+		secrets: map[string]string{},
+	}
+}
+
+// staticConfigurator serves a configuration that never changes.
+type staticConfigurator struct {
+	configuration Configuration
+}
+
+func (c *staticConfigurator) Read(context.Context) (Configuration, chan Configuration, func() error, error) {
+	return c.configuration, nil, func() error { return nil }, nil
+}
+
+func (c *staticConfigurator) ReadServer(_ context.Context, serverName string) (Configuration, error) {
+	server, ok := c.configuration.servers[serverName]
+	if !ok {
+		return Configuration{}, fmt.Errorf("server %s not found", serverName)
+	}
+
+	return Configuration{
+		serverNames: []string{serverName},
+		servers:     map[string]catalog.Server{serverName: server},
+		config:      map[string]map[string]any{},
+		tools:       config.ToolsConfig{ServerTools: map[string][]string{}},
+		secrets:     map[string]string{},
+	}, nil
+}
+
+func (c *staticConfigurator) SetSecret(_ context.Context, _, secretName, _ string) error {
+	return fmt.Errorf("secret %s can't be stored, the configuration is static", secretName)
+}
+
+// selfTestProgressSteps is the number of progress notifications sent by the selftest_progress tool.
+const selfTestProgressSteps = 3
+
+// selfTestServer is the built-in MCP server of the self-test.
+type selfTestServer struct {
+	server        *mcp.Server
+	slowStarted   chan struct{}
+	slowCancelled chan struct{}
+	slowOnce      sync.Once
+}
+
+func newSelfTestServer() *selfTestServer {
+	s := &selfTestServer{
+		slowStarted:   make(chan struct{}, 1),
+		slowCancelled: make(chan struct{}),
+	}
+
+	s.server = mcp.NewServer(&mcp.Implementation{Name: selfTestServerName, Version: "1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+		UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
+	})
+
+	s.server.AddTool(&mcp.Tool{
+		Name:        "selftest_echo",
+		Description: "Echo a message",
+		InputSchema: &jsonschema.Schema{
+			Type:       "object",
+			Properties: map[string]*jsonschema.Schema{"message": {Type: "string"}},
+		},
+	}, func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: args.Message}}}, nil
+	})
+
+	s.server.AddTool(&mcp.Tool{
+		Name:        "selftest_progress",
+		Description: "Report progress",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		token := req.Params.GetProgressToken()
+		for i := 1; i <= selfTestProgressSteps && token != nil; i++ {
+			if err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+				ProgressToken: token,
+				Progress:      float64(i),
+				Total:         selfTestProgressSteps,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil
+	})
+
+	s.server.AddTool(&mcp.Tool{
+		Name:        "selftest_slow",
+		Description: "Wait until cancelled",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		select {
+		case s.slowStarted <- struct{}{}:
+		default:
+		}
+
+		select {
+		case <-ctx.Done():
+			s.slowOnce.Do(func() { close(s.slowCancelled) })
+			return nil, ctx.Err()
+		case <-time.After(selfTestTimeout):
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "not cancelled"}}}, nil
+		}
+	})
+
+	s.server.AddPrompt(&mcp.Prompt{
+		Name:        "selftest_greet",
+		Description: "Greet someone",
+		Arguments:   []*mcp.PromptArgument{{Name: "name", Required: true}},
+	}, func(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{
+			Messages: []*mcp.PromptMessage{{
+				Role:    "user",
+				Content: &mcp.TextContent{Text: "Say hello to " + req.Params.Arguments["name"]},
+			}},
+		}, nil
+	})
+
+	s.server.AddResource(&mcp.Resource{
+		URI:      selfTestResource,
+		Name:     "selftest_status",
+		MIMEType: "text/plain",
+	}, func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{{URI: req.Params.URI, MIMEType: "text/plain", Text: "ok"}},
+		}, nil
+	})
+
+	return s
+}
+
+// serve serves the test server over the streamable HTTP transport, on a local port.
+func (s *selfTestServer) serve(ctx context.Context) (string, func(), error) {
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, err
+	}
+
+	httpServer := &http.Server{
+		Handler: mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+			return s.server
+		}, nil),
+		ReadHeaderTimeout: selfTestTimeout,
+	}
+	go func() {
+		_ = httpServer.Serve(ln)
+	}()
+
+	return fmt.Sprintf("http://%s/mcp", ln.Addr()), func() { _ = httpServer.Close() }, nil
+}
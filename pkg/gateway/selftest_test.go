@@ -0,0 +1,20 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	results, err := SelfTest(t.Context(), nil, Options{Cpus: 1, Memory: "2Gb"})
+	require.NoError(t, err)
+
+	var capabilities []string
+	for _, result := range results {
+		capabilities = append(capabilities, result.Capability)
+		assert.True(t, result.Passed, "%s: %s", result.Capability, result.Error)
+	}
+	assert.Equal(t, []string{"tools/list", "tools/call", "prompts", "resources", "subscriptions", "progress", "cancellation", "reload"}, capabilities)
+}
@@ -0,0 +1,222 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// maxCardExamples is the number of tools for which a server card shows an example call.
+const maxCardExamples = 3
+
+// serverCardURI is the URI of the resource that summarizes an enabled server.
+func serverCardURI(serverName string) string {
+	return "docker://servers/" + serverName + "/card"
+}
+
+// serverCardResource registers the card of a server: a markdown summary of what the server does
+// and how to use it, cheaper for an agent to read than the schemas of all the tools.
+func (g *Gateway) serverCardResource(serverName string) ResourceRegistration {
+	return ResourceRegistration{
+		ServerName: serverName,
+		Resource: &mcp.Resource{
+			URI:         serverCardURI(serverName),
+			Name:        serverName + "-card",
+			Description: fmt.Sprintf("Summary of the %s MCP server: description, tools, configuration, authorization and usage examples", serverName),
+			MIMEType:    "text/markdown",
+		},
+		Handler: func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			serverConfig, _, found := g.configuration.Find(serverName)
+			if !found || serverConfig == nil {
+				return nil, mcp.ResourceNotFoundError(req.Params.URI)
+			}
+
+			readiness := g.serverReadiness(ctx, serverName, serverConfig.Spec, g.configuration.secrets, g.configuration.config)
+
+			return &mcp.ReadResourceResult{
+				Contents: []*mcp.ResourceContents{{
+					URI:      req.Params.URI,
+					MIMEType: "text/markdown",
+					Text:     serverCard(serverName, serverConfig.Spec, g.serverTools(serverName), readiness),
+				}},
+			}, nil
+		},
+	}
+}
+
+// serverTools lists the tools registered for a server, sorted by name.
+func (g *Gateway) serverTools(serverName string) []*mcp.Tool {
+	g.capabilitiesMu.RLock()
+	defer g.capabilitiesMu.RUnlock()
+
+	var tools []*mcp.Tool
+	for _, registration := range g.toolRegistrations {
+		if registration.ServerName == serverName {
+			tools = append(tools, registration.Tool)
+		}
+	}
+	slices.SortFunc(tools, func(a, b *mcp.Tool) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return tools
+}
+
+// serverCard renders the card of a server as markdown.
+func serverCard(serverName string, server catalog.Server, tools []*mcp.Tool, readiness ServerReadiness) string {
+	var card strings.Builder
+
+	title := server.Title
+	if title == "" {
+		title = serverName
+	}
+	fmt.Fprintf(&card, "# %s\n\n", title)
+	if server.Description != "" {
+		fmt.Fprintf(&card, "%s\n\n", server.Description)
+	}
+
+	switch {
+	case server.Remote.URL != "":
+		fmt.Fprintf(&card, "- Remote: %s\n", server.Remote.URL)
+	case server.Image != "":
+		fmt.Fprintf(&card, "- Image: %s\n", server.Image)
+	}
+	if server.Source != "" {
+		fmt.Fprintf(&card, "- Source: %s\n", server.Source)
+	}
+	fmt.Fprintf(&card, "- Ready: %t\n", readiness.Ready)
+
+	// Servers that couldn't be listed still have the tools of their catalog entry
+	if len(tools) == 0 {
+		for _, tool := range server.Tools {
+			tools = append(tools, &mcp.Tool{Name: tool.Name, Description: tool.Description})
+		}
+	}
+
+	fmt.Fprintf(&card, "\n## Tools\n\n")
+	if len(tools) == 0 {
+		card.WriteString("No tool is enabled.\n")
+	}
+	for _, tool := range tools {
+		fmt.Fprintf(&card, "- `%s`", tool.Name)
+		if description := firstLine(tool.Description); description != "" {
+			fmt.Fprintf(&card, ": %s", description)
+		}
+		if required := toolArguments(tool).Required; len(required) > 0 {
+			fmt.Fprintf(&card, " (required: %s)", strings.Join(required, ", "))
+		}
+		if toolset := server.Toolsets.Toolset(tool.Name); toolset != "" {
+			fmt.Fprintf(&card, " [%s]", toolset)
+		}
+		card.WriteString("\n")
+	}
+
+	if len(server.Secrets) > 0 || len(server.Config) > 0 {
+		fmt.Fprintf(&card, "\n## Configuration\n\n")
+		for _, secret := range server.Secrets {
+			status := "set"
+			if slices.Contains(readiness.MissingSecrets, secret.Name) {
+				status = "missing"
+			}
+			fmt.Fprintf(&card, "- Secret `%s`: %s\n", secret.Name, status)
+		}
+		if len(server.Config) > 0 {
+			if readiness.ConfigSatisfied {
+				card.WriteString("- Config: satisfied\n")
+			} else {
+				fmt.Fprintf(&card, "- Config: %s\n", strings.Join(readiness.MissingConfig, ", "))
+			}
+		}
+		if !readiness.SecretsSet || !readiness.ConfigSatisfied {
+			fmt.Fprintf(&card, "\nSet the secrets with `docker mcp secret set` and the config with the mcp-config-set tool or `docker mcp config write`.\n")
+		}
+	}
+
+	if server.IsOAuthServer() {
+		fmt.Fprintf(&card, "\n## Authorization\n\n")
+		if readiness.OAuthAuthorized == nil || *readiness.OAuthAuthorized {
+			card.WriteString("The server uses OAuth.\n")
+		} else {
+			fmt.Fprintf(&card, "The server isn't authorized yet. Run `docker mcp oauth authorize %s`.\n", serverName)
+		}
+	}
+
+	if len(tools) > 0 {
+		fmt.Fprintf(&card, "\n## Usage\n")
+		for _, tool := range tools[:min(len(tools), maxCardExamples)] {
+			var example bytes.Buffer
+			encoder := json.NewEncoder(&example)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(exampleCall{Name: tool.Name, Arguments: exampleArguments(tool)}); err != nil {
+				continue
+			}
+			fmt.Fprintf(&card, "\n```json\n%s```\n", example.String())
+		}
+	}
+
+	return card.String()
+}
+
+// exampleCall is an example of a call to a tool, shown on server cards.
+type exampleCall struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// toolSchema is the part of a tool's input schema that's shown on server cards.
+type toolSchema struct {
+	Properties map[string]struct {
+		Type any `json:"type"`
+	} `json:"properties"`
+	Required []string `json:"required"`
+}
+
+// toolArguments decodes the input schema of a tool, whatever its Go type.
+func toolArguments(tool *mcp.Tool) toolSchema {
+	var schema toolSchema
+	if tool.InputSchema == nil {
+		return schema
+	}
+	buf, err := json.Marshal(tool.InputSchema)
+	if err != nil {
+		return schema
+	}
+	_ = json.Unmarshal(buf, &schema)
+	return schema
+}
+
+// exampleArguments builds placeholder values for the required arguments of a tool.
+func exampleArguments(tool *mcp.Tool) map[string]any {
+	schema := toolArguments(tool)
+
+	arguments := map[string]any{}
+	for _, name := range schema.Required {
+		argType, _ := schema.Properties[name].Type.(string)
+		switch argType {
+		case "integer", "number":
+			arguments[name] = 0
+		case "boolean":
+			arguments[name] = false
+		case "array":
+			arguments[name] = []any{}
+		case "object":
+			arguments[name] = map[string]any{}
+		default:
+			arguments[name] = "<" + name + ">"
+		}
+	}
+	return arguments
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(line)
+}
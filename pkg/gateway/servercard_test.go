@@ -0,0 +1,105 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestServerCard(t *testing.T) {
+	server := catalog.Server{
+		Title:       "GitHub",
+		Description: "Interact with GitHub repositories.",
+		Image:       "mcp/github",
+		Secrets:     []catalog.Secret{{Name: "github.token"}},
+		Toolsets:    catalog.Toolsets{"issues": {"create_issue"}},
+	}
+	tools := []*mcp.Tool{
+		{
+			Name:        "create_issue",
+			Description: "Create an issue.\nMore details.",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"title":  {Type: "string"},
+					"labels": {Type: "array"},
+					"body":   {Type: "string"},
+				},
+				Required: []string{"title", "labels"},
+			},
+		},
+		{
+			Name: "list_repos",
+			// Schemas of the servers' tools are decoded as maps
+			InputSchema: map[string]any{"type": "object"},
+		},
+	}
+	readiness := ServerReadiness{MissingSecrets: []string{"github.token"}, ConfigSatisfied: true}
+
+	card := serverCard("github", server, tools, readiness)
+
+	assert.Contains(t, card, "# GitHub\n\nInteract with GitHub repositories.\n")
+	assert.Contains(t, card, "- Image: mcp/github\n")
+	assert.Contains(t, card, "- Ready: false\n")
+	assert.Contains(t, card, "- `create_issue`: Create an issue. (required: title, labels) [issues]\n")
+	assert.Contains(t, card, "- `list_repos`\n")
+	assert.Contains(t, card, "- Secret `github.token`: missing\n")
+	assert.Contains(t, card, `"title": "<title>"`)
+	assert.Contains(t, card, `"labels": []`)
+	assert.NotContains(t, card, `"body"`)
+	assert.NotContains(t, card, "## Authorization")
+}
+
+func TestServerCardCatalogTools(t *testing.T) {
+	server := catalog.Server{
+		Type:  "remote",
+		Tools: []catalog.Tool{{Name: "search", Description: "Search the docs"}},
+		OAuth: &catalog.OAuth{Providers: []catalog.OAuthProvider{{Provider: "docs"}}},
+	}
+	server.Remote.URL = "https://docs.example.com/mcp"
+	authorized := false
+
+	card := serverCard("docs", server, nil, ServerReadiness{OAuthAuthorized: &authorized})
+
+	assert.Contains(t, card, "# docs\n")
+	assert.Contains(t, card, "- Remote: https://docs.example.com/mcp\n")
+	assert.Contains(t, card, "- `search`: Search the docs\n")
+	assert.Contains(t, card, "Run `docker mcp oauth authorize docs`.")
+}
+
+func TestReadServerCard(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"fetch"},
+			servers:     map[string]catalog.Server{"fetch": {Image: "mcp/fetch", Description: "Fetch URLs"}},
+		},
+		toolRegistrations: map[string]ToolRegistration{
+			"fetch": {ServerName: "fetch", Tool: &mcp.Tool{Name: "fetch"}},
+			"other": {ServerName: "other", Tool: &mcp.Tool{Name: "other"}},
+		},
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	card := g.serverCardResource("fetch")
+	server.AddResource(card.Resource, card.Handler)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	defer ss.Close()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer cs.Close()
+
+	result, err := cs.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: "docker://servers/fetch/card"})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+	assert.Equal(t, "text/markdown", result.Contents[0].MIMEType)
+	assert.Contains(t, result.Contents[0].Text, "Fetch URLs")
+	assert.Contains(t, result.Contents[0].Text, "- `fetch`\n")
+	assert.NotContains(t, result.Contents[0].Text, "other")
+}
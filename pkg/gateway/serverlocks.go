@@ -0,0 +1,162 @@
+package gateway
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// lockServer serializes the operations that enable or disable a server, so that concurrent
+// calls for the same server don't register its capabilities twice. Call the returned func to unlock.
+func (g *Gateway) lockServer(serverName string) func() {
+	g.serverLocksMu.Lock()
+	if g.serverLocks == nil {
+		g.serverLocks = map[string]*sync.Mutex{}
+	}
+	mu, ok := g.serverLocks[serverName]
+	if !ok {
+		mu = &sync.Mutex{}
+		g.serverLocks[serverName] = mu
+	}
+	g.serverLocksMu.Unlock()
+
+	mu.Lock()
+	return mu.Unlock
+}
+
+// secret returns the value of a secret of the configuration, or an empty string if it's not set.
+func (g *Gateway) secret(name string) string {
+	g.secretsMu.Lock()
+	defer g.secretsMu.Unlock()
+
+	return g.configuration.secrets[name]
+}
+
+// setSecret sets the value of a secret of the configuration. The map of secrets is read by the servers
+// being started, so it's copied and replaced rather than written in place.
+func (g *Gateway) setSecret(name, value string) {
+	g.secretsMu.Lock()
+	defer g.secretsMu.Unlock()
+
+	secrets := make(map[string]string, len(g.configuration.secrets)+1)
+	maps.Copy(secrets, g.configuration.secrets)
+	secrets[name] = value
+	g.configuration.secrets = secrets
+}
+
+// currentSecrets returns the secrets of the configuration. The map is replaced, never written in place,
+// so it can be read once returned.
+func (g *Gateway) currentSecrets() map[string]string {
+	g.secretsMu.Lock()
+	defer g.secretsMu.Unlock()
+
+	return g.configuration.secrets
+}
+
+// mergeServerConfiguration replaces the configuration of a server with a fresh one, eg. when it's reloaded.
+// The list of enabled servers and the secrets are changed concurrently by the dynamic tools,
+// so the configuration is swapped under their locks. Hold the server's lock while calling it.
+func (g *Gateway) mergeServerConfiguration(serverName string, fresh Configuration) {
+	g.serverLocksMu.Lock()
+	defer g.serverLocksMu.Unlock()
+	g.secretsMu.Lock()
+	defer g.secretsMu.Unlock()
+
+	g.configuration = g.configuration.withServer(serverName, fresh)
+}
+
+// replaceSecrets replaces the secrets of the configuration, eg. after reading them again for a new list of servers.
+func (g *Gateway) replaceSecrets(secrets map[string]string) {
+	g.secretsMu.Lock()
+	defer g.secretsMu.Unlock()
+
+	g.configuration.secrets = secrets
+}
+
+// isServerEnabled tells whether a server is in the list of enabled servers.
+func (g *Gateway) isServerEnabled(serverName string) bool {
+	g.serverLocksMu.Lock()
+	defer g.serverLocksMu.Unlock()
+
+	return slices.Contains(g.configuration.serverNames, serverName)
+}
+
+// serverAlreadyEnabled tells whether adding a server would be a no-op: it's enabled, its capabilities
+// are loaded and, if they must be activated, they already are.
+func (g *Gateway) serverAlreadyEnabled(serverName string, activate bool) bool {
+	if !g.isServerEnabled(serverName) {
+		return false
+	}
+
+	g.capabilitiesMu.RLock()
+	defer g.capabilitiesMu.RUnlock()
+
+	_, loaded := g.serverAvailableCapabilities[serverName]
+	_, active := g.serverCapabilities[serverName]
+	return loaded && (active || !activate)
+}
+
+// enableServerName adds a server to the list of enabled servers.
+// It returns false if the server was already enabled.
+func (g *Gateway) enableServerName(serverName string) bool {
+	g.serverLocksMu.Lock()
+	defer g.serverLocksMu.Unlock()
+
+	if slices.Contains(g.configuration.serverNames, serverName) {
+		return false
+	}
+	g.configuration.serverNames = append(slices.Clone(g.configuration.serverNames), serverName)
+	return true
+}
+
+// disableServerName removes a server from the list of enabled servers.
+// It returns false if the server wasn't enabled.
+func (g *Gateway) disableServerName(serverName string) bool {
+	g.serverLocksMu.Lock()
+	defer g.serverLocksMu.Unlock()
+
+	if !slices.Contains(g.configuration.serverNames, serverName) {
+		return false
+	}
+	g.configuration.serverNames = slices.DeleteFunc(slices.Clone(g.configuration.serverNames), func(name string) bool {
+		return name == serverName
+	})
+	return true
+}
+
+// enabledServerNames returns a copy of the list of enabled servers, with an extra server if it isn't enabled yet.
+func (g *Gateway) enabledServerNames(extra string) []string {
+	g.serverLocksMu.Lock()
+	defer g.serverLocksMu.Unlock()
+
+	serverNames := slices.Clone(g.configuration.serverNames)
+	if extra != "" && !slices.Contains(serverNames, extra) {
+		serverNames = append(serverNames, extra)
+	}
+	return serverNames
+}
+
+// persistProfile writes back to the profile that a server was added or removed, with --persist.
+// Otherwise, the changes made with mcp-add and mcp-remove only last as long as the gateway.
+func (g *Gateway) persistProfile(ctx context.Context, serverName string, enabled bool) {
+	if !g.PersistProfile {
+		return
+	}
+	workingSetConfiguration, ok := g.configurator.(*WorkingSetConfiguration)
+	if !ok {
+		return
+	}
+
+	if err := workingSetConfiguration.persistServerEnabled(ctx, serverName, enabled); err != nil {
+		log.Log("Warning: Failed to persist the profile:", err)
+		return
+	}
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+	log.Logf("  - Server %s %s in profile %s", serverName, state, workingSetConfiguration.WorkingSet)
+}
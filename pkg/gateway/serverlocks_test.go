@@ -0,0 +1,152 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestEnableServerNameConcurrently(t *testing.T) {
+	g := &Gateway{}
+
+	var added atomic.Int32
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if g.enableServerName("github") {
+				added.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), added.Load())
+	assert.Equal(t, []string{"github"}, g.configuration.serverNames)
+	assert.Equal(t, []string{"github", "fetch"}, g.enabledServerNames("fetch"))
+
+	assert.True(t, g.disableServerName("github"))
+	assert.False(t, g.disableServerName("github"))
+	assert.Empty(t, g.configuration.serverNames)
+}
+
+func TestLockServer(t *testing.T) {
+	g := &Gateway{}
+
+	var running, overlaps atomic.Int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := g.lockServer("github")
+			defer unlock()
+
+			if running.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+	assert.Zero(t, overlaps.Load())
+
+	// Other servers aren't blocked
+	unlock := g.lockServer("github")
+	defer unlock()
+	g.lockServer("fetch")()
+}
+
+func TestServerAlreadyEnabled(t *testing.T) {
+	g := &Gateway{
+		configuration:               Configuration{serverNames: []string{"fetch", "github"}},
+		serverCapabilities:          map[string]*ServerCapabilities{"fetch": {}},
+		serverAvailableCapabilities: map[string]*Capabilities{"fetch": {}, "github": {}},
+	}
+
+	assert.True(t, g.serverAlreadyEnabled("fetch", true))
+	assert.True(t, g.serverAlreadyEnabled("github", false))
+	// Enabled but not activated yet
+	assert.False(t, g.serverAlreadyEnabled("github", true))
+	assert.False(t, g.serverAlreadyEnabled("time", false))
+}
+
+func TestReloadWaitsForServerLock(t *testing.T) {
+	servers := map[string]catalog.Server{"github": {Name: "github"}}
+	g := &Gateway{
+		configurator:  &fakeConfigurator{configuration: Configuration{servers: servers}},
+		configuration: Configuration{serverNames: []string{"github"}, servers: servers},
+	}
+
+	// mcp-remove is removing the server
+	unlock := g.lockServer("github")
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		recorder := httptest.NewRecorder()
+		g.handleReload(recorder, httptest.NewRequest(http.MethodPost, "/reload", strings.NewReader(`{"server":"github"}`)))
+		done <- recorder
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("the reload didn't wait for the server's lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	g.disableServerName("github")
+	unlock()
+
+	// The reload doesn't put the removed server back
+	recorder := <-done
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "server github is not enabled")
+	assert.Empty(t, g.configuration.serverNames)
+}
+
+func TestMergeServerConfigurationConcurrently(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"github"},
+			servers:     map[string]catalog.Server{"github": {Name: "github"}},
+			secrets:     map[string]string{},
+		},
+	}
+	fresh := Configuration{
+		servers: map[string]catalog.Server{"github": {Name: "github"}},
+		secrets: map[string]string{"github.token": "new"},
+	}
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			g.mergeServerConfiguration("github", fresh)
+		}()
+		go func() {
+			defer wg.Done()
+			g.setSecret(fmt.Sprintf("secret%d", i), "value")
+		}()
+		go func() {
+			defer wg.Done()
+			g.enableServerName(fmt.Sprintf("server%d", i))
+		}()
+	}
+	wg.Wait()
+
+	// Neither the secrets nor the servers enabled concurrently are lost
+	assert.Len(t, g.configuration.secrets, 21)
+	assert.Len(t, g.configuration.serverNames, 21)
+}
@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/notify"
+)
+
+// sessionActivityMiddleware records when each client session was last active.
+func (g *Gateway) sessionActivityMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if ss, ok := req.GetSession().(*mcp.ServerSession); ok && ss != nil {
+				g.touchSession(ss, hasProgressToken(req))
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// touchSession records that a client session is active, and whether it sent a progress token.
+func (g *Gateway) touchSession(ss *mcp.ServerSession, progressToken bool) {
+	g.sessionCacheMu.Lock()
+	defer g.sessionCacheMu.Unlock()
+
+	cache := g.sessionCache[ss]
+	if cache == nil {
+		cache = &ServerSessionCache{}
+		g.sessionCache[ss] = cache
+	}
+	cache.LastActivity = time.Now()
+	if progressToken {
+		cache.ProgressTokens = true
+	}
+}
+
+// forgetSession removes the cache of a client session and closes the clients kept for it.
+// Returns false if the session was already forgotten.
+func (g *Gateway) forgetSession(ss *mcp.ServerSession) bool {
+	g.sessionCacheMu.Lock()
+	_, tracked := g.sessionCache[ss]
+	delete(g.sessionCache, ss)
+	g.sessionCacheMu.Unlock()
+
+	if g.clientPool != nil {
+		if released := g.clientPool.ReleaseSession(ss); released > 0 {
+			log.Logf("  - Released %d client(s) kept for the session", released)
+		}
+	}
+
+	return tracked
+}
+
+// idleSessions returns the client sessions that have been inactive for longer than the idle timeout.
+func (g *Gateway) idleSessions(now time.Time) []*mcp.ServerSession {
+	g.sessionCacheMu.RLock()
+	defer g.sessionCacheMu.RUnlock()
+
+	var idle []*mcp.ServerSession
+	for ss, cache := range g.sessionCache {
+		if !cache.LastActivity.IsZero() && now.Sub(cache.LastActivity) > g.SessionIdleTimeout {
+			idle = append(idle, ss)
+		}
+	}
+	return idle
+}
+
+// closeIdleSessions periodically closes the client sessions that have been inactive for longer than
+// the idle timeout, eg. the streaming sessions of clients that crashed, and frees what they hold.
+func (g *Gateway) closeIdleSessions(ctx context.Context) {
+	interval := min(max(g.SessionIdleTimeout/4, time.Second), time.Minute)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, ss := range g.idleSessions(now) {
+				g.closeIdleSession(ss)
+			}
+		}
+	}
+}
+
+func (g *Gateway) closeIdleSession(ss *mcp.ServerSession) {
+	clientName := sessionClientName(ss)
+	log.Logf("- Closing the session of client %s, idle for more than %s", clientName, g.SessionIdleTimeout)
+
+	g.forgetSession(ss)
+	_ = ss.Close()
+
+	g.emit(notify.Event{
+		Type:    notify.EventSessionClosed,
+		Message: fmt.Sprintf("Session of client %s closed after being idle for more than %s", clientName, g.SessionIdleTimeout),
+		Details: map[string]string{"client": clientName, "reason": "idle", "timeout": g.SessionIdleTimeout.String()},
+	})
+}
@@ -0,0 +1,72 @@
+package gateway
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/notify"
+)
+
+func TestCloseIdleSession(t *testing.T) {
+	g := &Gateway{
+		Options:      Options{SessionIdleTimeout: time.Minute},
+		sessionCache: make(map[*mcp.ServerSession]*ServerSessionCache),
+		clientPool:   newClientPool(Options{}, nil, nil),
+	}
+	events, unsubscribe := g.events.subscribe()
+	defer unsubscribe()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)
+	server.AddReceivingMiddleware(g.sessionActivityMiddleware())
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "crashed-client"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer cs.Close()
+
+	// The initialize request was recorded as activity
+	require.NotNil(t, g.GetSessionCache(ss))
+	assert.Empty(t, g.idleSessions(time.Now()))
+
+	later := time.Now().Add(2 * time.Minute)
+	require.Equal(t, []*mcp.ServerSession{ss}, g.idleSessions(later))
+
+	g.closeIdleSession(ss)
+
+	assert.Nil(t, g.GetSessionCache(ss))
+	require.NoError(t, ss.Wait())
+	event := <-events
+	assert.Equal(t, notify.EventSessionClosed, event.Type)
+	assert.Equal(t, "crashed-client", event.Details["client"])
+	assert.Equal(t, "idle", event.Details["reason"])
+
+	// Forgetting the session again is a no-op
+	assert.False(t, g.forgetSession(ss))
+}
+
+func TestReleaseSessionClients(t *testing.T) {
+	cp := newClientPool(Options{}, nil, nil)
+	ss := &mcp.ServerSession{}
+	other := &mcp.ServerSession{}
+
+	// Getters of clients that failed to start
+	failed := func() *clientGetter {
+		getter := &clientGetter{err: errors.New("failed to start")}
+		getter.once.Do(func() {})
+		return getter
+	}
+	cp.keptClients[clientKey{serverName: "a", session: ss}] = keptClient{Name: "a", Getter: failed()}
+	cp.keptClients[clientKey{serverName: "b", session: ss}] = keptClient{Name: "b", Getter: failed()}
+	cp.keptClients[clientKey{serverName: "a", session: other}] = keptClient{Name: "a", Getter: failed()}
+
+	assert.Equal(t, 2, cp.ReleaseSession(ss))
+	assert.Len(t, cp.keptClients, 1)
+	assert.Contains(t, cp.keptClients, clientKey{serverName: "a", session: other})
+	assert.Equal(t, 0, cp.ReleaseSession(ss))
+}
@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+// sessionRetryAfter is how long clients are asked to wait before opening a session again, when there are too many.
+const sessionRetryAfter = 30 * time.Second
+
+// reserveSessionSlot reserves one of the --max-sessions slots, or returns false and the number of slots
+// in use if there are none left.
+func (g *Gateway) reserveSessionSlot() (bool, int) {
+	g.sessionSlotsMu.Lock()
+	defer g.sessionSlotsMu.Unlock()
+
+	if g.sessionSlots >= g.MaxSessions {
+		return false, g.sessionSlots
+	}
+	g.sessionSlots++
+	return true, g.sessionSlots
+}
+
+func (g *Gateway) releaseSessionSlot() {
+	g.sessionSlotsMu.Lock()
+	defer g.sessionSlotsMu.Unlock()
+
+	g.sessionSlots--
+}
+
+// holdSessionSlot keeps the slot reserved by a request until the session it opened ends. The SSE and websocket
+// handlers only return once their session ends, while a streamable session outlives the request that opened it.
+func (g *Gateway) holdSessionSlot(w http.ResponseWriter) {
+	if id := w.Header().Get("Mcp-Session-Id"); id != "" && g.mcpServer != nil {
+		for session := range g.mcpServer.Sessions() {
+			if session.ID() == id {
+				go func() {
+					_ = session.Wait()
+					g.releaseSessionSlot()
+				}()
+				return
+			}
+		}
+	}
+
+	g.releaseSessionSlot()
+}
+
+// sessionLimitHandler rejects the requests that open a new session with 503 Service Unavailable
+// when the gateway already has --max-sessions sessions, so that it can't be exhausted by clients.
+// The slot is reserved before the request is served, so that concurrent requests can't go over the limit.
+func (g *Gateway) sessionLimitHandler(next http.Handler, opensSession func(*http.Request) bool) http.Handler {
+	if g.MaxSessions <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !opensSession(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reserved, active := g.reserveSessionSlot()
+		if !reserved {
+			log.Logf("  ! Rejected a new session: %d/%d sessions active", active, g.MaxSessions)
+			telemetry.RecordRejectedSession(r.Context(), g.Transport)
+
+			w.Header().Set("Retry-After", strconv.Itoa(int(sessionRetryAfter.Seconds())))
+			http.Error(w, fmt.Sprintf("Too many sessions: the gateway accepts at most %d sessions, retry later", g.MaxSessions), http.StatusServiceUnavailable)
+			return
+		}
+		defer g.holdSessionSlot(w)
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,108 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionLimit(t *testing.T) {
+	g := &Gateway{
+		Options:   Options{MaxSessions: 1, Transport: "streaming"},
+		mcpServer: mcp.NewServer(&mcp.Implementation{Name: "gateway"}, &mcp.ServerOptions{HasTools: true}),
+	}
+	streamHandler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return g.mcpServer
+	}, nil)
+	server := httptest.NewServer(g.sessionLimitHandler(streamHandler, func(r *http.Request) bool {
+		return r.Method == http.MethodPost && r.Header.Get("Mcp-Session-Id") == ""
+	}))
+	defer server.Close()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "first"}, nil)
+	first, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{Endpoint: server.URL}, nil)
+	require.NoError(t, err)
+	defer first.Close()
+	assert.Equal(t, 1, g.sessionSlots)
+
+	// A second session is rejected
+	resp := postInitialize(t, server.URL)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+
+	// The existing session keeps working
+	_, err = first.ListTools(t.Context(), nil)
+	require.NoError(t, err)
+
+	// Its slot is released when it ends
+	require.NoError(t, first.Close())
+	assert.Eventually(t, func() bool {
+		g.sessionSlotsMu.Lock()
+		defer g.sessionSlotsMu.Unlock()
+		return g.sessionSlots == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusOK, postInitialize(t, server.URL).StatusCode)
+}
+
+func TestSessionLimitConcurrently(t *testing.T) {
+	g := &Gateway{Options: Options{MaxSessions: 2}}
+	release := make(chan struct{})
+	handler := g.sessionLimitHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-release
+	}), func(*http.Request) bool { return true })
+
+	var accepted, rejected atomic.Int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp", http.NoBody))
+			if recorder.Code == http.StatusServiceUnavailable {
+				rejected.Add(1)
+			} else {
+				accepted.Add(1)
+			}
+		}()
+	}
+
+	// The requests being served hold their slots, so all but two are rejected
+	assert.Eventually(t, func() bool { return rejected.Load() == 8 }, 5*time.Second, 10*time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(2), accepted.Load())
+	assert.Zero(t, g.sessionSlots)
+}
+
+func postInitialize(t *testing.T, url string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, url, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp
+}
+
+func TestSessionLimitDisabled(t *testing.T) {
+	g := &Gateway{}
+	next := http.NotFoundHandler()
+
+	handler := g.sessionLimitHandler(next, func(*http.Request) bool { return true })
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
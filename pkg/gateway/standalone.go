@@ -0,0 +1,62 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+)
+
+// StartServer starts a single server the way the gateway does, without serving MCP to clients.
+// It's meant for tooling and tests. The returned function stops the server.
+func StartServer(ctx context.Context, options Options, dockerClient docker.Client, serverConfig *catalog.ServerConfig) (mcpclient.Client, func(), error) {
+	if serverConfig.Spec.Image != "" && serverConfig.Spec.Remote.URL == "" && serverConfig.Spec.SSEEndpoint == "" {
+		// Containers are run with --pull never
+		exists, _ := dockerClient.ImageExists(ctx, serverConfig.Spec.Image)
+		if !exists {
+			if err := dockerClient.PullImageWithOptions(ctx, serverConfig.Spec.Image, docker.PullOptions{Platform: serverConfig.Spec.Platform}); err != nil {
+				return nil, nil, fmt.Errorf("pulling image %s: %w", serverConfig.Spec.Image, err)
+			}
+		}
+	}
+
+	cp := newClientPool(options, dockerClient, nil)
+	client, err := cp.AcquireClient(ctx, serverConfig, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, func() {
+		cp.ReleaseClient(client)
+		cp.Close()
+	}, nil
+}
+
+// ReadServerConfig reads the configuration and the secrets of a server of the catalogs the way the gateway does,
+// to start it with StartServer.
+func ReadServerConfig(ctx context.Context, dockerClient docker.Client, config Config, serverName string) (*catalog.ServerConfig, error) {
+	configurator := &FileBasedConfiguration{
+		CatalogPath:        config.CatalogPath,
+		ServerNames:        []string{serverName},
+		ConfigPath:         config.ConfigPath,
+		SecretsPath:        config.SecretsPath,
+		McpOAuthDcrEnabled: config.McpOAuthDcrEnabled,
+		docker:             dockerClient,
+	}
+
+	configuration, err := configurator.ReadServer(ctx, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverConfig, _, found := configuration.Find(serverName)
+	if !found {
+		return nil, fmt.Errorf("server %s not found in catalog", serverName)
+	}
+	if serverConfig == nil {
+		return nil, fmt.Errorf("server %s only declares tools, there's no MCP server to start", serverName)
+	}
+	return serverConfig, nil
+}
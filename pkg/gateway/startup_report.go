@@ -0,0 +1,74 @@
+package gateway
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// ServerIssue explains why a server couldn't be started.
+type ServerIssue struct {
+	Server string `json:"server"`
+	Reason string `json:"reason"`
+}
+
+// StartupReport lists the servers that failed validation on startup and the ones that were skipped.
+type StartupReport struct {
+	Failed  []ServerIssue `json:"failed,omitempty"`
+	Skipped []ServerIssue `json:"skipped,omitempty"`
+}
+
+func (r *StartupReport) fail(serverName, reason string) {
+	r.Failed = append(r.Failed, ServerIssue{Server: serverName, Reason: reason})
+}
+
+func (r *StartupReport) skip(serverName, reason string) {
+	r.Skipped = append(r.Skipped, ServerIssue{Server: serverName, Reason: reason})
+}
+
+// HasFailures returns true if at least one server failed validation.
+func (r *StartupReport) HasFailures() bool {
+	return len(r.Failed) > 0
+}
+
+// FailedServers returns the names of the servers that failed validation.
+func (r *StartupReport) FailedServers() []string {
+	var names []string
+	for _, issue := range r.Failed {
+		if !slices.Contains(names, issue.Server) {
+			names = append(names, issue.Server)
+		}
+	}
+	return names
+}
+
+// Log prints the report, if there's anything to report.
+func (r *StartupReport) Log() {
+	if len(r.Failed) == 0 && len(r.Skipped) == 0 {
+		return
+	}
+
+	log.Log("- Startup report:")
+	for _, issue := range r.Failed {
+		log.Logf("  ! %s failed: %s", issue.Server, issue.Reason)
+	}
+	for _, issue := range r.Skipped {
+		log.Logf("  - %s skipped: %s", issue.Server, issue.Reason)
+	}
+}
+
+// Err returns an error describing the failed servers, or nil if no server failed.
+func (r *StartupReport) Err() error {
+	if !r.HasFailures() {
+		return nil
+	}
+
+	var lines []string
+	for _, issue := range r.Failed {
+		lines = append(lines, fmt.Sprintf("  - %s: %s", issue.Server, issue.Reason))
+	}
+
+	return fmt.Errorf("%d server(s) can't be started:\n%s", len(r.FailedServers()), strings.Join(lines, "\n"))
+}
@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/workingset"
+	"github.com/docker/mcp-gateway/test/mocks"
+)
+
+func TestResolveSnapshotsReportsBrokenServers(t *testing.T) {
+	c := NewWorkingSetConfiguration("default", "", mocks.NewMockOCIService(), nil)
+
+	workingSet := workingset.WorkingSet{
+		Servers: []workingset.Server{
+			{
+				Type:     workingset.ServerTypeImage,
+				Image:    "mcp/github:latest",
+				Snapshot: &workingset.ServerSnapshot{Server: catalog.Server{Name: "github", Image: "mcp/github:latest"}},
+			},
+			{
+				Type:  workingset.ServerTypeImage,
+				Image: "mcp/missing:latest",
+			},
+			{
+				Type:     workingset.ServerTypeRemote,
+				Endpoint: "https://example.com/mcp",
+			},
+			{
+				Type:   workingset.ServerTypeRegistry,
+				Source: "https://registry.example.com/v0/servers/foo",
+			},
+		},
+	}
+
+	var report StartupReport
+	c.resolveSnapshots(t.Context(), &workingSet, &report)
+
+	require.Len(t, workingSet.Servers, 2)
+	assert.Equal(t, "mcp/github:latest", workingSet.Servers[0].Image)
+	assert.Equal(t, workingset.ServerTypeRegistry, workingSet.Servers[1].Type)
+
+	assert.True(t, report.HasFailures())
+	assert.Equal(t, []string{"mcp/missing:latest", "https://example.com/mcp"}, report.FailedServers())
+	assert.Contains(t, report.Failed[0].Reason, "failed to resolve snapshot")
+	assert.Equal(t, "no snapshot available for this server", report.Failed[1].Reason)
+}
+
+func TestStartupReportErr(t *testing.T) {
+	var report StartupReport
+	require.NoError(t, report.Err())
+
+	report.skip("registry-server", "registry servers are not supported by the gateway yet")
+	require.NoError(t, report.Err())
+
+	report.fail("github", "image mcp/github can't be pulled: not found")
+	report.fail("github", "another reason")
+	report.fail("fetch", "failed to resolve snapshot: boom")
+
+	assert.Equal(t, []string{"github", "fetch"}, report.FailedServers())
+	assert.EqualError(t, report.Err(), "2 server(s) can't be started:\n"+
+		"  - github: image mcp/github can't be pulled: not found\n"+
+		"  - github: another reason\n"+
+		"  - fetch: failed to resolve snapshot: boom")
+}
+
+func TestConfigurationWithoutServers(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"github", "fetch", "duckduckgo"},
+	}
+
+	trimmed := configuration.withoutServers([]string{"fetch"})
+
+	assert.Equal(t, []string{"github", "duckduckgo"}, trimmed.ServerNames())
+	assert.Equal(t, []string{"github", "fetch", "duckduckgo"}, configuration.ServerNames())
+}
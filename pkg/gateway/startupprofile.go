@@ -0,0 +1,275 @@
+package gateway
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	runtimepprof "runtime/pprof"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// startupProfileBarWidth is the width of the timeline bars of the startup breakdown.
+const startupProfileBarWidth = 40
+
+// StartupSpan is the time spent in a phase of the gateway's startup, or in a step of a phase.
+type StartupSpan struct {
+	// Path is the phase, followed by the steps it's nested in, e.g. [capabilities github list]
+	Path []string `json:"path"`
+	// StartMs is when the span started, in milliseconds since the startup started
+	StartMs float64 `json:"startMs"`
+	// DurationMs is how long the span took, in milliseconds
+	DurationMs float64 `json:"durationMs"`
+}
+
+// StartupProfile is the breakdown of the gateway's startup recorded with --profile-startup.
+type StartupProfile struct {
+	// Finished tells whether the gateway is done starting up
+	Finished bool `json:"finished"`
+	// TotalMs is how long the startup took, or has taken so far, in milliseconds
+	TotalMs float64       `json:"totalMs"`
+	Spans   []StartupSpan `json:"spans"`
+}
+
+// startupProfiler records how long each phase of the startup takes. A nil profiler records nothing,
+// and nothing is recorded once the startup is finished.
+type startupProfiler struct {
+	mu       sync.Mutex
+	start    time.Time
+	end      time.Time
+	spans    []StartupSpan
+	finished bool
+
+	// CPU profile of the startup, in the pprof format
+	cpuProfile bytes.Buffer
+	profiling  bool
+}
+
+func newStartupProfiler() *startupProfiler {
+	p := &startupProfiler{start: time.Now()}
+	if err := runtimepprof.StartCPUProfile(&p.cpuProfile); err != nil {
+		log.Log("! Can't profile the CPU during the startup:", err)
+	} else {
+		p.profiling = true
+	}
+	return p
+}
+
+// span starts recording a phase, or a step of a phase, and returns the function that ends it.
+func (p *startupProfiler) span(path ...string) func() {
+	if p == nil {
+		return func() {}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.finished {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		end := time.Now()
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.finished {
+			return
+		}
+		p.spans = append(p.spans, StartupSpan{
+			Path:       path,
+			StartMs:    milliseconds(start.Sub(p.start)),
+			DurationMs: milliseconds(end.Sub(start)),
+		})
+	}
+}
+
+// finish stops the recording and logs the breakdown of the startup.
+func (p *startupProfiler) finish() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	if p.finished {
+		p.mu.Unlock()
+		return
+	}
+	p.finished = true
+	p.end = time.Now()
+	if p.profiling {
+		runtimepprof.StopCPUProfile()
+	}
+	p.mu.Unlock()
+
+	var breakdown strings.Builder
+	p.profile().WriteBreakdown(&breakdown)
+	log.Log("> Startup profile:")
+	for line := range strings.Lines(breakdown.String()) {
+		log.Log("  " + strings.TrimSuffix(line, "\n"))
+	}
+}
+
+func (p *startupProfiler) profile() StartupProfile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	end := p.end
+	if !p.finished {
+		end = time.Now()
+	}
+
+	spans := slices.Clone(p.spans)
+	slices.SortStableFunc(spans, func(a, b StartupSpan) int {
+		switch {
+		case a.StartMs < b.StartMs:
+			return -1
+		case a.StartMs > b.StartMs:
+			return 1
+		default:
+			return len(a.Path) - len(b.Path)
+		}
+	})
+
+	return StartupProfile{
+		Finished: p.finished,
+		TotalMs:  milliseconds(end.Sub(p.start)),
+		Spans:    spans,
+	}
+}
+
+// startupNode is a span along with the spans nested in it.
+type startupNode struct {
+	name     string
+	span     StartupSpan
+	children []*startupNode
+}
+
+// tree nests each span in the span of its parent path that was running when it started.
+func (p StartupProfile) tree() *startupNode {
+	root := &startupNode{name: "startup", span: StartupSpan{DurationMs: p.TotalMs}}
+
+	var nodes []*startupNode
+	for _, span := range p.Spans {
+		node := &startupNode{name: span.Path[len(span.Path)-1], span: span}
+
+		parent := root
+		for i := len(nodes) - 1; i >= 0; i-- {
+			candidate := nodes[i]
+			if len(candidate.span.Path) == len(span.Path)-1 && slices.Equal(candidate.span.Path, span.Path[:len(span.Path)-1]) &&
+				span.StartMs >= candidate.span.StartMs && span.StartMs <= candidate.span.StartMs+candidate.span.DurationMs {
+				parent = candidate
+				break
+			}
+		}
+		parent.children = append(parent.children, node)
+		nodes = append(nodes, node)
+	}
+
+	return root
+}
+
+// WriteBreakdown writes the spans as an indented tree, with a timeline bar for each.
+// Steps that run in parallel, like the image pulls, overlap on the timeline.
+func (p StartupProfile) WriteBreakdown(w io.Writer) {
+	root := p.tree()
+
+	nameWidth := 0
+	var measure func(node *startupNode, depth int)
+	measure = func(node *startupNode, depth int) {
+		nameWidth = max(nameWidth, 2*depth+len(node.name))
+		for _, child := range node.children {
+			measure(child, depth+1)
+		}
+	}
+	measure(root, 0)
+
+	var write func(node *startupNode, depth int)
+	write = func(node *startupNode, depth int) {
+		name := strings.Repeat("  ", depth) + node.name
+		duration := time.Duration(node.span.DurationMs * float64(time.Millisecond)).Round(time.Millisecond)
+		fmt.Fprintf(w, "%-*s %10s %s\n", nameWidth, name, duration, timelineBar(node.span, p.TotalMs))
+		for _, child := range node.children {
+			write(child, depth+1)
+		}
+	}
+	write(root, 0)
+}
+
+// WriteFolded writes the spans as folded stacks, weighted with their own time in milliseconds,
+// the input format of flame graph tools like flamegraph.pl or speedscope.
+func (p StartupProfile) WriteFolded(w io.Writer) {
+	var write func(node *startupNode, stack string)
+	write = func(node *startupNode, stack string) {
+		stack += node.name
+		self := node.span.DurationMs
+		for _, child := range node.children {
+			self -= child.span.DurationMs
+		}
+		if self > 0 {
+			fmt.Fprintf(w, "%s %d\n", stack, int64(self))
+		}
+		for _, child := range node.children {
+			write(child, stack+";")
+		}
+	}
+	write(p.tree(), "")
+}
+
+func timelineBar(span StartupSpan, totalMs float64) string {
+	if totalMs <= 0 {
+		return ""
+	}
+
+	offset := min(int(span.StartMs/totalMs*startupProfileBarWidth), startupProfileBarWidth-1)
+	length := min(max(int(span.DurationMs/totalMs*startupProfileBarWidth), 1), startupProfileBarWidth-offset)
+	return "|" + strings.Repeat(" ", offset) + strings.Repeat("#", length) + strings.Repeat(" ", startupProfileBarWidth-offset-length) + "|"
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// handleStartupProfile serves the startup profile as JSON, as folded stacks with ?format=folded or as a breakdown with ?format=text.
+func (g *Gateway) handleStartupProfile(w http.ResponseWriter, r *http.Request) {
+	profile := g.startup.profile()
+
+	switch r.URL.Query().Get("format") {
+	case "":
+		writeControlJSON(w, http.StatusOK, profile)
+	case "folded":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		profile.WriteFolded(w)
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		profile.WriteBreakdown(w)
+	default:
+		writeControlError(w, http.StatusBadRequest, fmt.Errorf("unsupported format %q, expected folded or text", r.URL.Query().Get("format")))
+	}
+}
+
+// handleStartupCPUProfile serves the CPU profile of the startup, once it's finished.
+func (g *Gateway) handleStartupCPUProfile(w http.ResponseWriter, _ *http.Request) {
+	g.startup.mu.Lock()
+	defer g.startup.mu.Unlock()
+
+	if !g.startup.finished || !g.startup.profiling {
+		writeControlError(w, http.StatusNotFound, fmt.Errorf("no CPU profile of the startup"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="startup.pprof"`)
+	_, _ = w.Write(g.startup.cpuProfile.Bytes())
+}
+
+// registerStartupProfileHandlers serves the startup profile on the control API.
+func (g *Gateway) registerStartupProfileHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /startup", g.handleStartupProfile)
+	mux.HandleFunc("GET /startup/cpu", g.handleStartupCPUProfile)
+}
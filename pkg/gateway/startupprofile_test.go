@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStartupProfile() StartupProfile {
+	return StartupProfile{
+		Finished: true,
+		TotalMs:  1000,
+		Spans: []StartupSpan{
+			{Path: []string{"config"}, StartMs: 0, DurationMs: 100},
+			{Path: []string{"pull"}, StartMs: 100, DurationMs: 500},
+			{Path: []string{"pull", "github"}, StartMs: 100, DurationMs: 400},
+			{Path: []string{"pull", "fetch"}, StartMs: 110, DurationMs: 50},
+			{Path: []string{"capabilities"}, StartMs: 600, DurationMs: 350},
+			{Path: []string{"capabilities", "github"}, StartMs: 600, DurationMs: 300},
+			{Path: []string{"capabilities", "github", "start"}, StartMs: 600, DurationMs: 250},
+			{Path: []string{"capabilities", "github", "list"}, StartMs: 850, DurationMs: 50},
+		},
+	}
+}
+
+func TestStartupProfileFolded(t *testing.T) {
+	var folded strings.Builder
+	testStartupProfile().WriteFolded(&folded)
+
+	assert.Equal(t, `startup 50
+startup;config 100
+startup;pull 50
+startup;pull;github 400
+startup;pull;fetch 50
+startup;capabilities 50
+startup;capabilities;github;start 250
+startup;capabilities;github;list 50
+`, folded.String())
+}
+
+func TestStartupProfileBreakdown(t *testing.T) {
+	var breakdown strings.Builder
+	testStartupProfile().WriteBreakdown(&breakdown)
+
+	lines := strings.Split(strings.TrimSpace(breakdown.String()), "\n")
+	require.Len(t, lines, 9)
+	assert.Equal(t, "startup                1s |########################################|", lines[0])
+	assert.Equal(t, "  pull              500ms |    ####################                |", lines[2])
+	assert.Equal(t, "      list           50ms |                                  ##    |", lines[8])
+}
+
+func TestStartupProfiler(t *testing.T) {
+	var profiler *startupProfiler
+	profiler.span("config")()
+	profiler.finish()
+
+	profiler = newStartupProfiler()
+	endPull := profiler.span("pull")
+	profiler.span("pull", "github")()
+	endPull()
+	profiler.finish()
+	profiler.span("capabilities")()
+
+	profile := profiler.profile()
+	assert.True(t, profile.Finished)
+	require.Len(t, profile.Spans, 2)
+	assert.Equal(t, []string{"pull"}, profile.Spans[0].Path)
+	assert.Equal(t, []string{"pull", "github"}, profile.Spans[1].Path)
+}
+
+func TestStartupProfileControlAPI(t *testing.T) {
+	g := &Gateway{Options: Options{ProfileStartup: true}, startup: newStartupProfiler()}
+	g.startup.span("config")()
+	g.startup.finish()
+	handler := g.controlHandler()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		return recorder
+	}
+
+	response := get("/startup")
+	require.Equal(t, http.StatusOK, response.Code)
+	var profile StartupProfile
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &profile))
+	require.Len(t, profile.Spans, 1)
+	assert.Equal(t, []string{"config"}, profile.Spans[0].Path)
+
+	assert.Contains(t, get("/startup?format=text").Body.String(), "  config")
+	assert.Equal(t, http.StatusBadRequest, get("/startup?format=xml").Code)
+	assert.Equal(t, http.StatusOK, get("/startup/cpu").Code)
+	assert.Equal(t, http.StatusOK, get("/debug/pprof/goroutine?debug=1").Code)
+
+	// Not served without --profile-startup
+	handler = (&Gateway{}).controlHandler()
+	assert.Equal(t, http.StatusNotFound, get("/debug/pprof/goroutine").Code)
+	assert.Equal(t, http.StatusNotFound, get("/startup").Code)
+}
@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolErrorCode classifies why a tool call failed, so that clients can react without parsing error messages.
+type ToolErrorCode string
+
+const (
+	ToolErrorAuth        ToolErrorCode = "auth"
+	ToolErrorNotFound    ToolErrorCode = "not-found"
+	ToolErrorTimeout     ToolErrorCode = "timeout"
+	ToolErrorRateLimited ToolErrorCode = "rate-limited"
+	ToolErrorServerCrash ToolErrorCode = "server-crash"
+	ToolErrorValidation  ToolErrorCode = "validation"
+	ToolErrorUnknown     ToolErrorCode = "unknown"
+)
+
+// ToolErrorMetaKey is the key, in the _meta of failed tool calls, of their ToolError.
+const ToolErrorMetaKey = "io.docker.mcp/error"
+
+// ToolError is the machine-readable description of a failed tool call.
+type ToolError struct {
+	Code        ToolErrorCode `json:"code"`
+	Message     string        `json:"message"`
+	Tool        string        `json:"tool"`
+	Server      string        `json:"server,omitempty"`
+	Retryable   bool          `json:"retryable"`
+	Remediation string        `json:"remediation,omitempty"`
+}
+
+// toolErrorPatterns classify error messages, in order. The first match wins.
+var toolErrorPatterns = []struct {
+	code    ToolErrorCode
+	pattern *regexp.Regexp
+}{
+	{ToolErrorRateLimited, regexp.MustCompile(`(?i)\b429\b|rate.?limit|too many requests|quota exceeded`)},
+	{ToolErrorAuth, regexp.MustCompile(`(?i)\b(401|403)\b|(token|key|credentials?|secret)s? (is |are )?(required|missing|not set)|unauthori[sz]ed|forbidden|authentication|bad credentials|invalid (api )?(key|token)|access denied|permission denied`)},
+	{ToolErrorTimeout, regexp.MustCompile(`(?i)timed? ?out|deadline exceeded`)},
+	{ToolErrorServerCrash, regexp.MustCompile(`(?i)connection closed|connection reset|broken pipe|\bEOF\b|exited with|server is closing|client is closing|failed to acquire client|failed to start`)},
+	{ToolErrorValidation, regexp.MustCompile(`(?i)invalid param|invalid argument|missing argument|parameter is required|is required|failed to (un)?marshal arguments|failed to parse arguments|validating|schema`)},
+	{ToolErrorNotFound, regexp.MustCompile(`(?i)\b404\b|not found|no such|unknown tool|does not exist`)},
+}
+
+// classifyToolError tells why a tool call failed, from its error and/or the message it returned.
+func classifyToolError(err error, message string) ToolErrorCode {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ToolErrorTimeout
+	case errors.Is(err, mcp.ErrConnectionClosed), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ToolErrorServerCrash
+	}
+
+	if err != nil {
+		message = err.Error() + "\n" + message
+	}
+	for _, p := range toolErrorPatterns {
+		if p.pattern.MatchString(message) {
+			return p.code
+		}
+	}
+	return ToolErrorUnknown
+}
+
+// remediation suggests what to do about a failed tool call.
+func (e ToolError) remediation() string {
+	server := e.Server
+	if server == "" {
+		server = "<server>"
+	}
+
+	switch e.Code {
+	case ToolErrorAuth:
+		return fmt.Sprintf("Check the credentials of %s: set its secrets with `docker mcp secret set` or authorize it with `docker mcp oauth authorize %s`.", server, server)
+	case ToolErrorNotFound:
+		return "Check the names and identifiers passed to the tool, and that the tool is still provided by the server."
+	case ToolErrorTimeout:
+		return "Retry the call later, or with a smaller request."
+	case ToolErrorRateLimited:
+		return "Wait before retrying: the service behind the tool is rate limiting the calls."
+	case ToolErrorServerCrash:
+		return fmt.Sprintf("The server %s stopped or couldn't be started. Retry the call, and check the gateway's logs (with --verbose) if it keeps failing.", server)
+	case ToolErrorValidation:
+		return "Check the arguments against the tool's input schema."
+	}
+	return ""
+}
+
+func (e ToolError) retryable() bool {
+	switch e.Code {
+	case ToolErrorTimeout, ToolErrorRateLimited, ToolErrorServerCrash:
+		return true
+	}
+	return false
+}
+
+func newToolError(serverName, toolName string, err error, message string) ToolError {
+	if message == "" && err != nil {
+		message = err.Error()
+	}
+
+	toolError := ToolError{
+		Code:    classifyToolError(err, message),
+		Message: message,
+		Tool:    toolName,
+		Server:  serverName,
+	}
+	toolError.Retryable = toolError.retryable()
+	toolError.Remediation = toolError.remediation()
+	return toolError
+}
+
+// toolErrorResult turns the error of a tool call into an isError result that describes it.
+func toolErrorResult(serverName, toolName string, err error) *mcp.CallToolResult {
+	toolError := newToolError(serverName, toolName, err, "")
+
+	text := fmt.Sprintf("Error (%s): %s", toolError.Code, toolError.Message)
+	if toolError.Remediation != "" {
+		text += "\n" + toolError.Remediation
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		IsError: true,
+		Meta:    mcp.Meta{ToolErrorMetaKey: toolError},
+	}
+}
+
+// classifyToolResult describes the isError results returned by servers, keeping their content.
+func classifyToolResult(serverName, toolName string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	if result == nil || !result.IsError {
+		return result
+	}
+	if _, classified := result.Meta[ToolErrorMetaKey]; classified {
+		return result
+	}
+
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	toolError := newToolError(serverName, toolName, nil, strings.Join(texts, "\n"))
+
+	classified := *result
+	classified.Meta = mcp.Meta{}
+	for k, v := range result.Meta {
+		classified.Meta[k] = v
+	}
+	classified.Meta[ToolErrorMetaKey] = toolError
+	if toolError.Remediation != "" {
+		classified.Content = append(append([]mcp.Content{}, result.Content...), &mcp.TextContent{Text: toolError.Remediation})
+	}
+	return &classified
+}
@@ -0,0 +1,77 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyToolError(t *testing.T) {
+	tests := []struct {
+		err     error
+		message string
+		code    ToolErrorCode
+	}{
+		{err: fmt.Errorf("calling tool: %w", context.DeadlineExceeded), code: ToolErrorTimeout},
+		{err: fmt.Errorf("%w: calling \"tools/call\": EOF", mcp.ErrConnectionClosed), code: ToolErrorServerCrash},
+		{err: errors.New("failed to acquire client for server github: container exited with code 1"), code: ToolErrorServerCrash},
+		{message: "GET https://api.github.com/user: 401 Bad credentials []", code: ToolErrorAuth},
+		{message: "GITHUB_TOKEN is required", code: ToolErrorAuth},
+		{message: "API rate limit exceeded for 1.2.3.4", code: ToolErrorRateLimited},
+		{message: "HTTP 429 Too Many Requests", code: ToolErrorRateLimited},
+		{message: "repository octo/missing not found", code: ToolErrorNotFound},
+		{err: errors.New("name parameter is required"), code: ToolErrorValidation},
+		{message: "invalid params: unexpected additional properties [\"foo\"]", code: ToolErrorValidation},
+		{message: "the weather is cloudy", code: ToolErrorUnknown},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.code, classifyToolError(test.err, test.message), "err=%v message=%q", test.err, test.message)
+	}
+}
+
+func TestToolErrorResult(t *testing.T) {
+	result := toolErrorResult("github", "get_me", errors.New("GET https://api.github.com/user: 401 Bad credentials"))
+
+	assert.True(t, result.IsError)
+	require.Len(t, result.Content, 1)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "Error (auth): GET https://api.github.com/user: 401 Bad credentials")
+	assert.Contains(t, text, "docker mcp oauth authorize github")
+
+	toolError := result.Meta[ToolErrorMetaKey].(ToolError)
+	assert.Equal(t, ToolErrorAuth, toolError.Code)
+	assert.Equal(t, "github", toolError.Server)
+	assert.Equal(t, "get_me", toolError.Tool)
+	assert.False(t, toolError.Retryable)
+}
+
+func TestClassifyToolResult(t *testing.T) {
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: "upstream timed out"}},
+		IsError: true,
+		Meta:    mcp.Meta{"other": "value"},
+	}
+
+	classified := classifyToolResult("fetch", "fetch", result)
+
+	toolError := classified.Meta[ToolErrorMetaKey].(ToolError)
+	assert.Equal(t, ToolErrorTimeout, toolError.Code)
+	assert.True(t, toolError.Retryable)
+	assert.Equal(t, "value", classified.Meta["other"])
+	require.Len(t, classified.Content, 2)
+	assert.Equal(t, "upstream timed out", classified.Content[0].(*mcp.TextContent).Text)
+	// The result of the server is left untouched
+	assert.Len(t, result.Content, 1)
+	assert.NotContains(t, result.Meta, ToolErrorMetaKey)
+
+	// Successful results and results that are already classified are kept as they are
+	ok := &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}
+	assert.Same(t, ok, classifyToolResult("fetch", "fetch", ok))
+	assert.Same(t, classified, classifyToolResult("fetch", "fetch", classified))
+}
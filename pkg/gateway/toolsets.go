@@ -0,0 +1,25 @@
+package gateway
+
+import (
+	"maps"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolsetMetaKey is the key, in the _meta of tools, of the name of the group the tool belongs to.
+// Clients can use it to render collapsed groups of tools.
+const ToolsetMetaKey = "io.docker.mcp/toolset"
+
+// withToolset returns a copy of the tool's _meta with the group the tool belongs to.
+func withToolset(meta mcp.Meta, toolset string) mcp.Meta {
+	if toolset == "" {
+		return meta
+	}
+
+	withToolset := maps.Clone(meta)
+	if withToolset == nil {
+		withToolset = mcp.Meta{}
+	}
+	withToolset[ToolsetMetaKey] = toolset
+	return withToolset
+}
@@ -0,0 +1,18 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithToolset(t *testing.T) {
+	assert.Nil(t, withToolset(nil, ""))
+	assert.Equal(t, mcp.Meta{ToolsetMetaKey: "issues"}, withToolset(nil, "issues"))
+
+	meta := mcp.Meta{"other": "value"}
+	assert.Equal(t, mcp.Meta{"other": "value", ToolsetMetaKey: "issues"}, withToolset(meta, "issues"))
+	// The server's _meta is left untouched
+	assert.Equal(t, mcp.Meta{"other": "value"}, meta)
+}
@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"encoding/json"
+
+	"github.com/mikefarah/yq/v4/pkg/yqlib"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/yq"
+)
+
+// transformToolResult applies a jq-style expression to the JSON content of a tool result,
+// to shrink noisy responses before they reach the model. Text that is not JSON and error results are left as is.
+// If the expression fails, the original result is returned.
+func transformToolResult(toolName, expression string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	if result == nil || result.IsError {
+		return result
+	}
+
+	transformed := *result
+	transformed.Content = make([]mcp.Content, 0, len(result.Content))
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok || !json.Valid([]byte(text.Text)) {
+			transformed.Content = append(transformed.Content, content)
+			continue
+		}
+
+		output, err := evaluateTransform(expression, []byte(text.Text))
+		if err != nil {
+			log.Logf("  ! Can't transform the result of %s: %v", toolName, err)
+			return result
+		}
+		transformed.Content = append(transformed.Content, &mcp.TextContent{
+			Text:        string(output),
+			Meta:        text.Meta,
+			Annotations: text.Annotations,
+		})
+	}
+
+	if result.StructuredContent != nil {
+		structured, err := transformStructuredContent(expression, result.StructuredContent)
+		if err != nil {
+			log.Logf("  ! Can't transform the structured result of %s: %v", toolName, err)
+			return result
+		}
+		transformed.StructuredContent = structured
+	}
+
+	return &transformed
+}
+
+func transformStructuredContent(expression string, structuredContent any) (any, error) {
+	buf, err := json.Marshal(structuredContent)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := evaluateTransform(expression, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var transformed any
+	if err := json.Unmarshal(output, &transformed); err != nil {
+		// The expression produced a scalar, or several values
+		return string(output), nil //nolint:nilerr
+	}
+	return transformed, nil
+}
+
+func evaluateTransform(expression string, content []byte) ([]byte, error) {
+	return yq.Evaluate(expression, content, yqlib.NewJSONDecoder(), yq.NewJSONEncoder())
+}
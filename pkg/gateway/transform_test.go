@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformToolResult(t *testing.T) {
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: `{"title":"Docker","url":"https://docker.com","screenshot":"iVBORw0KGgo="}`},
+			&mcp.TextContent{Text: "Not JSON"},
+		},
+		StructuredContent: map[string]any{"title": "Docker", "screenshot": "iVBORw0KGgo="},
+	}
+
+	transformed := transformToolResult("fetch", "del(.screenshot)", result)
+
+	require.Len(t, transformed.Content, 2)
+	assert.JSONEq(t, `{"title":"Docker","url":"https://docker.com"}`, transformed.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, "Not JSON", transformed.Content[1].(*mcp.TextContent).Text)
+	assert.Equal(t, map[string]any{"title": "Docker"}, transformed.StructuredContent)
+
+	// The original result is left untouched
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "screenshot")
+}
+
+func TestTransformToolResultSelectFields(t *testing.T) {
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: `{"items":[{"name":"a","size":1},{"name":"b","size":2}]}`},
+		},
+	}
+
+	transformed := transformToolResult("list", "[.items[].name]", result)
+
+	assert.JSONEq(t, `["a","b"]`, transformed.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestTransformToolResultInvalidExpression(t *testing.T) {
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: `{"title":"Docker"}`}},
+	}
+
+	assert.Same(t, result, transformToolResult("fetch", "del(", result))
+}
+
+func TestTransformToolResultError(t *testing.T) {
+	result := &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: `{"error":"boom"}`}},
+	}
+
+	assert.Same(t, result, transformToolResult("fetch", ".title", result))
+}
@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -10,22 +11,30 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
-	"github.com/docker/mcp-gateway/pkg/health"
+	"github.com/docker/mcp-gateway/pkg/logs"
 )
 
 func (g *Gateway) startStdioServer(ctx context.Context, _ io.Reader, _ io.Writer) error {
-	transport := &mcp.StdioTransport{}
-	return g.mcpServer.Run(ctx, transport)
+	var transport mcp.Transport = &mcp.StdioTransport{}
+	if g.stdioTransport != nil {
+		transport = g.stdioTransport
+	}
+	return g.mcpServer.Run(ctx, logs.WrapTransport("client", transport))
 }
 
 func (g *Gateway) startSseServer(ctx context.Context, ln net.Listener) error {
 	mux := http.NewServeMux()
-	mux.Handle("/health", healthHandler(&g.health))
+	mux.Handle("/health", g.healthHandler())
+	mux.Handle(discoveryPath, g.discoveryHandler("sse", "/sse"))
 	mux.Handle("/", redirectHandler("/sse"))
 	sseHandler := mcp.NewSSEHandler(func(_ *http.Request) *mcp.Server {
 		return g.mcpServer
 	}, nil)
-	mux.Handle("/sse", originSecurityHandler(sseHandler))
+	mux.Handle("/sse", originSecurityHandler(g.sessionLimitHandler(sseHandler, func(r *http.Request) bool {
+		// Clients open their session with a GET and post their messages to it
+		return r.Method == http.MethodGet
+	})))
+	g.mountControlAPI(mux)
 
 	// Wrap with authentication middleware
 	var handler http.Handler = mux
@@ -45,16 +54,23 @@ func (g *Gateway) startSseServer(ctx context.Context, ln net.Listener) error {
 
 func (g *Gateway) startStreamingServer(ctx context.Context, ln net.Listener) error {
 	mux := http.NewServeMux()
-	mux.Handle("/health", healthHandler(&g.health))
+	mux.Handle("/health", g.healthHandler())
+	mux.Handle(discoveryPath, g.discoveryHandler("streamable-http", "/mcp"))
+	if g.resourceAuth != nil {
+		for _, metadataPath := range g.resourceAuth.metadataPaths() {
+			mux.Handle(metadataPath, g.resourceAuth.metadataHandler())
+		}
+	}
 	mux.Handle("/", redirectHandler("/mcp"))
-	streamHandler := mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
-		return g.mcpServer
-	}, nil)
-	mux.Handle("/mcp", originSecurityHandler(streamHandler))
+	mux.Handle("/mcp", originSecurityHandler(compressionHandler(g.CompressionMinSize, g.streamableHandler())))
+	g.mountControlAPI(mux)
 
 	// Wrap with authentication middleware
 	var handler http.Handler = mux
-	if g.authToken != "" {
+	switch {
+	case g.resourceAuth != nil:
+		handler = g.resourceAuth.middleware(g.authToken, mux)
+	case g.authToken != "":
 		handler = authenticationMiddleware(g.authToken, mux)
 	}
 
@@ -69,19 +85,46 @@ func (g *Gateway) startStreamingServer(ctx context.Context, ln net.Listener) err
 	return httpServer.Serve(ln)
 }
 
+// streamableHandler serves the MCP server with the streamable HTTP transport, limiting the number of sessions.
+func (g *Gateway) streamableHandler() http.Handler {
+	streamHandler := mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
+		return g.mcpServer
+	}, nil)
+	return g.sessionLimitHandler(streamHandler, func(r *http.Request) bool {
+		// Requests of existing sessions carry their id
+		return r.Method == http.MethodPost && r.Header.Get("Mcp-Session-Id") == ""
+	})
+}
+
 func redirectHandler(target string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, target, http.StatusTemporaryRedirect)
 	}
 }
 
-func healthHandler(state *health.State) http.HandlerFunc {
+// healthHandler reports whether the gateway is healthy, along with the tool name conflicts.
+func (g *Gateway) healthHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
-		if state.IsHealthy() {
+		healthy := g.health.IsHealthy()
+
+		w.Header().Set("Content-Type", "application/json")
+		if healthy {
 			w.WriteHeader(http.StatusOK)
 		} else {
 			w.WriteHeader(http.StatusServiceUnavailable)
 		}
+
+		toolConflicts := g.ToolConflicts()
+		if toolConflicts == nil {
+			toolConflicts = []ToolConflict{}
+		}
+		_ = json.NewEncoder(w).Encode(struct {
+			Healthy       bool           `json:"healthy"`
+			ToolConflicts []ToolConflict `json:"toolConflicts"`
+		}{
+			Healthy:       healthy,
+			ToolConflicts: toolConflicts,
+		})
 	}
 }
 
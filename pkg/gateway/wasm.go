@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/docker/go-units"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/gateway/proxies"
+	mcpclient "github.com/docker/mcp-gateway/pkg/mcp"
+	"github.com/docker/mcp-gateway/pkg/wasm"
+)
+
+// newWasmClient returns a client of a server compiled to WebAssembly, that runs in an embedded runtime.
+// The module is only granted the environment of the server, and the network access to its allowed hosts.
+func (cp *clientPool) newWasmClient(serverConfig *catalog.ServerConfig) (mcpclient.Client, error) {
+	if serverConfig.Spec.Module == "" {
+		return nil, fmt.Errorf("server %s of type wasm has no module", serverConfig.Name)
+	}
+	modulePath, err := config.FilePath(serverConfig.Spec.Module)
+	if err != nil {
+		return nil, err
+	}
+
+	_, env := cp.argsAndEnv(serverConfig, nil, proxies.TargetConfig{})
+	capabilities := wasm.Capabilities{
+		Env: env,
+	}
+	if !serverConfig.Spec.DisableNetwork {
+		capabilities.AllowHosts = serverConfig.Spec.AllowHosts
+	}
+
+	memory := cp.Memory
+	if serverConfig.Spec.Resources != nil && serverConfig.Spec.Resources.Memory != "" {
+		memory = serverConfig.Spec.Resources.Memory
+	}
+	if memory != "" {
+		if capabilities.MemoryLimit, err = units.RAMInBytes(memory); err != nil {
+			return nil, fmt.Errorf("invalid memory limit of %s: %w", serverConfig.Name, err)
+		}
+	}
+
+	return mcpclient.NewInProcessMCPClientWithCleanup(serverConfig.Name, func(ctx context.Context) (*mcp.Server, func(), error) {
+		module, err := os.ReadFile(modulePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading the module of %s: %w", serverConfig.Name, err)
+		}
+
+		wasmServer, err := wasm.NewServer(ctx, serverConfig.Name, module, capabilities)
+		if err != nil {
+			return nil, nil, err
+		}
+		cleanup := func() { _ = wasmServer.Close(context.Background()) }
+
+		server, err := wasmServer.MCPServer(ctx)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		return server, cleanup, nil
+	}), nil
+}
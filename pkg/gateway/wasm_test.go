@@ -0,0 +1,28 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestWasmClientWithoutModule(t *testing.T) {
+	cp := &clientPool{}
+	_, err := cp.newWasmClient(&catalog.ServerConfig{Name: "wasm-tool", Spec: catalog.Server{Type: catalog.TypeWasm}})
+	require.ErrorContains(t, err, "has no module")
+}
+
+func TestWasmToolExecution(t *testing.T) {
+	g := &Gateway{}
+
+	execution := g.toolExecution(&catalog.ServerConfig{Name: "wasm-tool", Spec: catalog.Server{Type: catalog.TypeWasm, Module: "/tools/tool.wasm"}}, nil, nil, 0)
+	assert.Equal(t, RuntimeWasm, execution.Runtime)
+	assert.Equal(t, NetworkNone, execution.Network)
+
+	execution = g.toolExecution(&catalog.ServerConfig{Name: "wasm-tool", Spec: catalog.Server{Type: catalog.TypeWasm, AllowHosts: []string{"api.example.com:443"}}}, nil, nil, 0)
+	assert.Equal(t, NetworkRestricted, execution.Network)
+	assert.Equal(t, []string{"api.example.com:443"}, execution.AllowedHosts)
+}
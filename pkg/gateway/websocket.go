@@ -0,0 +1,154 @@
+package gateway
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/coder/websocket"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+const (
+	// websocketSubprotocol is the subprotocol clients negotiate, one JSON-RPC message per text message.
+	websocketSubprotocol = "mcp"
+	// websocketBearerPrefix prefixes the token that browsers, which can't set the Authorization header
+	// of WebSocket requests, pass as a subprotocol.
+	websocketBearerPrefix = "bearer."
+	// maxWebSocketMessageSize limits the size of the messages clients send.
+	maxWebSocketMessageSize = 16 * 1024 * 1024
+)
+
+func (g *Gateway) startWebSocketServer(ctx context.Context, ln net.Listener) error {
+	mux := http.NewServeMux()
+	mux.Handle("/health", g.healthHandler())
+	mux.Handle(discoveryPath, g.discoveryHandler("websocket", "/ws"))
+	mux.Handle("/", redirectHandler("/ws"))
+	mux.Handle("/ws", originSecurityHandler(g.sessionLimitHandler(g.websocketHandler(), func(*http.Request) bool {
+		// Each connection is a session
+		return true
+	})))
+	g.mountControlAPI(mux)
+
+	// Wrap with authentication middleware
+	var handler http.Handler = mux
+	if g.authToken != "" {
+		handler = websocketBearerHandler(authenticationMiddleware(g.authToken, mux))
+	}
+
+	httpServer := &http.Server{
+		Handler: handler,
+	}
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	return httpServer.Serve(ln)
+}
+
+// websocketHandler serves an MCP session over each WebSocket connection.
+func (g *Gateway) websocketHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+			Subprotocols: []string{websocketSubprotocol},
+			// The origin was already checked by originSecurityHandler
+			InsecureSkipVerify: true,
+		})
+		if err != nil {
+			// Accept already replied with an error
+			return
+		}
+		conn.SetReadLimit(maxWebSocketMessageSize)
+
+		session, err := g.mcpServer.Connect(r.Context(), &websocketTransport{conn: conn}, nil)
+		if err != nil {
+			log.Logf("  ! Failed to start a WebSocket session: %s", err)
+			_ = conn.Close(websocket.StatusInternalError, "failed to start the session")
+			return
+		}
+		_ = session.Wait()
+	}
+}
+
+// websocketBearerHandler turns a "bearer.<token>" subprotocol into an Authorization header,
+// for browser clients to authenticate like the other clients do.
+func websocketBearerHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			for _, protocol := range websocketSubprotocols(r) {
+				if token, found := strings.CutPrefix(protocol, websocketBearerPrefix); found {
+					r = r.Clone(r.Context())
+					r.Header.Set("Authorization", "Bearer "+token)
+					break
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func websocketSubprotocols(r *http.Request) []string {
+	var protocols []string
+	for _, value := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(value, ",") {
+			protocols = append(protocols, strings.TrimSpace(protocol))
+		}
+	}
+	return protocols
+}
+
+// websocketTransport is an MCP transport over an accepted WebSocket connection.
+type websocketTransport struct {
+	conn *websocket.Conn
+}
+
+func (t *websocketTransport) Connect(context.Context) (mcp.Connection, error) {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return &websocketConnection{
+		conn:      t.conn,
+		sessionID: hex.EncodeToString(buf),
+	}, nil
+}
+
+type websocketConnection struct {
+	conn      *websocket.Conn
+	sessionID string
+}
+
+func (c *websocketConnection) Read(ctx context.Context) (jsonrpc.Message, error) {
+	_, data, err := c.conn.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := jsonrpc.DecodeMessage(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+	return msg, nil
+}
+
+func (c *websocketConnection) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	return c.conn.Write(ctx, websocket.MessageText, data)
+}
+
+func (c *websocketConnection) Close() error {
+	// Close can be called several times
+	_ = c.conn.Close(websocket.StatusNormalClosure, "")
+	return nil
+}
+
+func (c *websocketConnection) SessionID() string {
+	return c.sessionID
+}
@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coder/websocket"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketTransport(t *testing.T) {
+	g := &Gateway{
+		Options:   Options{Transport: "websocket"},
+		mcpServer: mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil),
+	}
+	g.mcpServer.AddTool(&mcp.Tool{Name: "ping", InputSchema: map[string]any{"type": "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "pong"}}}, nil
+	})
+	server := httptest.NewServer(websocketBearerHandler(authenticationMiddleware("s3cr3t", g.websocketHandler())))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	// Browsers pass the token as a subprotocol
+	conn, _, err := websocket.Dial(t.Context(), url, &websocket.DialOptions{Subprotocols: []string{"mcp", "bearer.s3cr3t"}}) //nolint:bodyclose // The body of a 101 response is the connection
+	require.NoError(t, err)
+	assert.Equal(t, "mcp", conn.Subprotocol())
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "browser"}, nil)
+	session, err := client.Connect(t.Context(), &websocketTransport{conn: conn}, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "ping"})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "pong", result.Content[0].(*mcp.TextContent).Text)
+
+	// Other clients set the Authorization header
+	conn, _, err = websocket.Dial(t.Context(), url, &websocket.DialOptions{ //nolint:bodyclose // The body of a 101 response is the connection
+		Subprotocols: []string{"mcp"},
+		HTTPHeader:   http.Header{"Authorization": []string{"Bearer s3cr3t"}},
+	})
+	require.NoError(t, err)
+	conn.Close(websocket.StatusNormalClosure, "")
+}
+
+func TestWebSocketTransportUnauthenticated(t *testing.T) {
+	g := &Gateway{mcpServer: mcp.NewServer(&mcp.Implementation{Name: "gateway"}, nil)}
+	server := httptest.NewServer(websocketBearerHandler(authenticationMiddleware("s3cr3t", g.websocketHandler())))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	_, resp, err := websocket.Dial(t.Context(), url, &websocket.DialOptions{Subprotocols: []string{"mcp", "bearer.wrong"}})
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,158 @@
+// Package gatewaylib embeds the MCP Gateway in Go programs.
+//
+// A Gateway starts the servers of a profile, or of the catalog and registry files, like
+// docker mcp gateway run does, along with in-process tools registered by the program.
+// Instead of listening on stdio or on a port, it exposes an mcp.Server, or an http.Handler,
+// that the program serves itself.
+package gatewaylib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/docker/docker/client"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/gateway"
+	"github.com/docker/mcp-gateway/pkg/logs"
+)
+
+type (
+	// Config selects the servers of the gateway: a profile (WorkingSet), or catalog, registry, config and tools files.
+	Config = gateway.Config
+	// Options are the options of docker mcp gateway run. The transport and port options are ignored.
+	Options = gateway.Options
+	// ToolRegistration is an in-process tool. Its handler is called with the tool calls of the clients.
+	ToolRegistration = gateway.ToolRegistration
+)
+
+// DefaultConfig returns the configuration of docker mcp gateway run, without a profile:
+// the servers enabled in registry.yaml, from the Docker catalog.
+func DefaultConfig() Config {
+	return Config{
+		SecretsPath:  "docker-desktop",
+		CatalogPath:  []string{catalog.DockerCatalogFilename},
+		RegistryPath: []string{"registry.yaml"},
+		ConfigPath:   []string{"config.yaml"},
+		ToolsPath:    []string{"tools.yaml"},
+		ServersMode:  gateway.ServersModeIntersection,
+		Options: gateway.Options{
+			Cpus:                       1,
+			Memory:                     "2Gb",
+			LogCalls:                   true,
+			BlockSecrets:               true,
+			PullPolicy:                 gateway.PullPolicyIfNotPresent,
+			ToolConflictStrategy:       gateway.ToolConflictPrefix,
+			BudgetAction:               gateway.BudgetActionReject,
+			AutoEnable:                 gateway.AutoEnableOff,
+			SecretsCacheTTL:            gateway.DefaultSecretsCacheTTL,
+			LogRateLimit:               logs.DefaultRateLimit.Messages,
+			LogRateInterval:            logs.DefaultRateLimit.Interval,
+			SchemaDescriptionMaxLength: gateway.DefaultSchemaDescriptionMaxLength,
+			SchemaEnumMaxValues:        gateway.DefaultSchemaEnumMaxValues,
+			InstructionsMaxSize:        gateway.DefaultInstructionsMaxSize,
+			OAuthGroupsClaim:           gateway.DefaultOAuthGroupsClaim,
+		},
+	}
+}
+
+// Option customizes an embedded Gateway.
+type Option func(*settings)
+
+type settings struct {
+	docker docker.Client
+	tools  []ToolRegistration
+}
+
+// WithDockerClient sets the client of the Docker engine the servers run on.
+// By default, the engine is found from the environment (DOCKER_HOST...), like the docker CLI does.
+func WithDockerClient(dockerClient docker.Client) Option {
+	return func(s *settings) {
+		s.docker = dockerClient
+	}
+}
+
+// WithTools registers in-process tools, listed to the clients along with the tools of the servers.
+func WithTools(tools ...ToolRegistration) Option {
+	return func(s *settings) {
+		s.tools = append(s.tools, tools...)
+	}
+}
+
+// Gateway is a gateway embedded in a Go program.
+type Gateway struct {
+	gateway *gateway.Gateway
+	stop    context.CancelFunc
+	done    chan error
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// New starts a gateway and returns once its servers are started and their capabilities listed.
+// The gateway runs until Close is called, or ctx is done.
+func New(ctx context.Context, config Config, opts ...Option) (*Gateway, error) {
+	var s settings
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	if s.docker == nil {
+		apiClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+		if err != nil {
+			return nil, fmt.Errorf("creating the Docker client: %w", err)
+		}
+		s.docker = docker.NewClientFromAPI(apiClient)
+	}
+
+	g := gateway.NewGateway(config, s.docker)
+	g.RegisterTools(s.tools...)
+
+	runCtx, stop := context.WithCancel(ctx)
+	ready := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- g.RunEmbedded(runCtx, func() { close(ready) })
+	}()
+
+	select {
+	case <-ready:
+		return &Gateway{
+			gateway: g,
+			stop:    stop,
+			done:    done,
+		}, nil
+	case err := <-done:
+		stop()
+		if err == nil {
+			err = errors.New("the gateway stopped before it was ready")
+		}
+		return nil, err
+	}
+}
+
+// Server returns the MCP server aggregating the tools, prompts and resources of the servers of the gateway.
+// It can be run on any transport, e.g. with mcp.NewInMemoryTransports.
+func (g *Gateway) Server() *mcp.Server {
+	return g.gateway.MCPServer()
+}
+
+// Handler serves the gateway with the streamable HTTP transport. It doesn't authenticate the clients,
+// nor check the origin of the requests: that's left to the program.
+func (g *Gateway) Handler() http.Handler {
+	return g.gateway.StreamableHTTPHandler()
+}
+
+// Close stops the gateway and its servers.
+func (g *Gateway) Close() error {
+	g.closeOnce.Do(func() {
+		g.stop()
+		g.closeErr = <-g.done
+	})
+	return g.closeErr
+}
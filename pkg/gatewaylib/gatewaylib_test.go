@@ -0,0 +1,62 @@
+package gatewaylib
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+type fakeDocker struct {
+	docker.Client
+}
+
+func TestEmbeddedGateway(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	for _, name := range []string{"catalog.yaml", "registry.yaml", "config.yaml", "tools.yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+
+	config := DefaultConfig()
+	config.CatalogPath = []string{filepath.Join(dir, "catalog.yaml")}
+	config.RegistryPath = []string{filepath.Join(dir, "registry.yaml")}
+	config.ConfigPath = []string{filepath.Join(dir, "config.yaml")}
+	config.ToolsPath = []string{filepath.Join(dir, "tools.yaml")}
+	config.SecretsPath = ""
+
+	g, err := New(t.Context(), config, WithDockerClient(&fakeDocker{}), WithTools(ToolRegistration{
+		ServerName: "app",
+		Tool:       &mcp.Tool{Name: "hello", InputSchema: map[string]any{"type": "object"}},
+		Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "hello from the app"}}}, nil
+		},
+	}))
+	require.NoError(t, err)
+	defer g.Close()
+	assert.NotNil(t, g.Handler())
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := g.Server().Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "hello"})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "hello from the app", result.Content[0].(*mcp.TextContent).Text)
+
+	require.NoError(t, g.Close())
+	// Closing twice is fine
+	require.NoError(t, g.Close())
+}
@@ -0,0 +1,124 @@
+// Package httpclient builds the HTTP clients the gateway uses to reach remote servers and registries.
+//
+// Each remote server gets its own pool of keep-alive connections, so that a busy server can't use up
+// the connections of the others, and the other requests share a pool.
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Settings tune the connection pools.
+type Settings struct {
+	// MaxConnsPerHost limits the connections of a pool to a host, 0 means no limit.
+	MaxConnsPerHost int
+	// MaxIdleConnsPerHost is the number of idle connections a pool keeps alive for each host.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long idle connections are kept alive.
+	IdleConnTimeout time.Duration
+}
+
+// DefaultSettings are used until Configure is called, and for the settings Configure is called without.
+var DefaultSettings = Settings{
+	MaxConnsPerHost:     0,
+	MaxIdleConnsPerHost: 16,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+const (
+	dialTimeout         = 30 * time.Second
+	tcpKeepAlive        = 30 * time.Second
+	tlsHandshakeTimeout = 10 * time.Second
+)
+
+var (
+	mu         sync.Mutex
+	settings   = DefaultSettings
+	shared     *http.Transport
+	transports = map[string]*http.Transport{}
+)
+
+// Configure changes the settings of the pools. Existing pools are closed once their connections are idle.
+func Configure(s Settings) {
+	if s.MaxConnsPerHost < 0 {
+		s.MaxConnsPerHost = DefaultSettings.MaxConnsPerHost
+	}
+	if s.MaxIdleConnsPerHost <= 0 {
+		s.MaxIdleConnsPerHost = DefaultSettings.MaxIdleConnsPerHost
+	}
+	if s.IdleConnTimeout <= 0 {
+		s.IdleConnTimeout = DefaultSettings.IdleConnTimeout
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	settings = s
+	if shared != nil {
+		shared.CloseIdleConnections()
+		shared = nil
+	}
+	for name, transport := range transports {
+		transport.CloseIdleConnections()
+		delete(transports, name)
+	}
+}
+
+// Transport returns the pool of connections of a remote server. The same pool is returned until Release is called.
+func Transport(serverName string) http.RoundTripper {
+	mu.Lock()
+	defer mu.Unlock()
+
+	transport, found := transports[serverName]
+	if !found {
+		transport = newTransport(settings)
+		transports[serverName] = transport
+	}
+	return transport
+}
+
+// Release closes the idle connections of the pool of a remote server, once it's removed.
+func Release(serverName string) {
+	mu.Lock()
+	transport, found := transports[serverName]
+	delete(transports, serverName)
+	mu.Unlock()
+
+	if found {
+		transport.CloseIdleConnections()
+	}
+}
+
+// Client returns a client using the shared pool of connections.
+func Client(timeout time.Duration) *http.Client {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if shared == nil {
+		shared = newTransport(settings)
+	}
+	return &http.Client{
+		Transport: shared,
+		Timeout:   timeout,
+	}
+}
+
+func newTransport(s Settings) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: tcpKeepAlive,
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxConnsPerHost:       s.MaxConnsPerHost,
+		MaxIdleConnsPerHost:   s.MaxIdleConnsPerHost,
+		IdleConnTimeout:       s.IdleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+}
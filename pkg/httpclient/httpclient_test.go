@@ -0,0 +1,46 @@
+package httpclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportPerServer(t *testing.T) {
+	t.Cleanup(func() { Configure(DefaultSettings) })
+
+	Configure(Settings{MaxConnsPerHost: 4, IdleConnTimeout: time.Minute})
+
+	first := Transport("first")
+	assert.Same(t, first, Transport("first"))
+	assert.NotSame(t, first, Transport("second"))
+
+	transport, ok := first.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 4, transport.MaxConnsPerHost)
+	assert.Equal(t, DefaultSettings.MaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.True(t, transport.ForceAttemptHTTP2)
+
+	// A released server gets a new pool
+	Release("first")
+	assert.NotSame(t, first, Transport("first"))
+}
+
+func TestClientSharesPool(t *testing.T) {
+	t.Cleanup(func() { Configure(DefaultSettings) })
+
+	first := Client(time.Second)
+	second := Client(0)
+	assert.Same(t, first.Transport, second.Transport)
+	assert.Equal(t, time.Second, first.Timeout)
+
+	// New settings give a new pool
+	Configure(Settings{MaxIdleConnsPerHost: 2})
+	third := Client(0)
+	assert.NotSame(t, first.Transport, third.Transport)
+	assert.Equal(t, 2, third.Transport.(*http.Transport).MaxIdleConnsPerHost)
+}
@@ -0,0 +1,56 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// CorrelationIDMetaKey is the key, in the _meta of tool calls, of the correlation ID that identifies a call
+// across the gateway's logs, its spans, the interceptors and the logs of the server that runs the tool.
+const CorrelationIDMetaKey = "io.docker.mcp/correlation-id"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context carrying the correlation ID of a tool call.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID of the tool call being handled, if any.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// CorrelationMiddleware gives each tool call a correlation ID, or keeps the one set by the client.
+// The ID is stored in the context and in the _meta of the call, that is forwarded to the servers.
+func CorrelationMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok || callReq.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			id, _ := callReq.Params.Meta[CorrelationIDMetaKey].(string)
+			if id == "" {
+				id = newCorrelationID()
+				if callReq.Params.Meta == nil {
+					callReq.Params.Meta = mcp.Meta{}
+				}
+				callReq.Params.Meta[CorrelationIDMetaKey] = id
+			}
+
+			return next(WithCorrelationID(ctx, id), method, req)
+		}
+	}
+}
+
+func newCorrelationID() string {
+	var buf [8]byte
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,67 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelationMiddleware(t *testing.T) {
+	var seen string
+	handler := CorrelationMiddleware()(func(ctx context.Context, _ string, _ mcp.Request) (mcp.Result, error) {
+		seen = CorrelationID(ctx)
+		return &mcp.CallToolResult{}, nil
+	})
+
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "search"}}
+	_, err := handler(t.Context(), "tools/call", req)
+	require.NoError(t, err)
+
+	require.Len(t, seen, 16)
+	assert.Equal(t, seen, req.Params.Meta[CorrelationIDMetaKey])
+
+	// The ID is part of the call that interceptors receive
+	buf, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), `"io.docker.mcp/correlation-id":"`+seen+`"`)
+
+	// Each call gets its own ID
+	first := seen
+	_, err = handler(t.Context(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "search"}})
+	require.NoError(t, err)
+	assert.NotEqual(t, first, seen)
+}
+
+func TestCorrelationMiddlewareKeepsClientID(t *testing.T) {
+	var seen string
+	handler := CorrelationMiddleware()(func(ctx context.Context, _ string, _ mcp.Request) (mcp.Result, error) {
+		seen = CorrelationID(ctx)
+		return &mcp.CallToolResult{}, nil
+	})
+
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Name: "search",
+		Meta: mcp.Meta{CorrelationIDMetaKey: "client-id", "progressToken": "1"},
+	}}
+	_, err := handler(t.Context(), "tools/call", req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "client-id", seen)
+	assert.Equal(t, "1", req.Params.Meta["progressToken"])
+}
+
+func TestCorrelationMiddlewareIgnoresOtherMethods(t *testing.T) {
+	var seen string
+	handler := CorrelationMiddleware()(func(ctx context.Context, _ string, _ mcp.Request) (mcp.Result, error) {
+		seen = CorrelationID(ctx)
+		return &mcp.ListToolsResult{}, nil
+	})
+
+	_, err := handler(t.Context(), "tools/list", &mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, seen)
+}
@@ -29,18 +29,24 @@ func LogCallsMiddleware() mcp.Middleware {
 				arguments = callReq.Params.Arguments
 			}
 
+			callID := ""
+			if id := CorrelationID(ctx); id != "" {
+				callID = " [" + id + "]"
+			}
+
 			if toolName != "" {
-				log.Logf("  - Calling tool %s with arguments: %s\n", toolName, argumentsToString(arguments))
+				log.Logf("  - Calling tool %s%s with arguments: %s\n", toolName, callID, argumentsToString(arguments))
 			} else {
-				log.Logf("  - Calling tool (unknown) with method: %s\n", method)
+				log.Logf("  - Calling tool (unknown)%s with method: %s\n", callID, method)
 			}
 
 			result, err := next(ctx, method, req)
 			if err != nil {
+				log.Logf("  ! Calling tool %s%s failed: %s\n", toolName, callID, err)
 				return result, err
 			}
 
-			log.Logf("  > Calling tool %s took: %s\n", toolName, time.Since(start))
+			log.Logf("  > Calling tool %s%s took: %s\n", toolName, callID, time.Since(start))
 
 			return result, nil
 		}
@@ -28,7 +28,7 @@ func TelemetryMiddleware() mcp.Middleware {
 			switch method {
 			case "initialize":
 				params := req.GetParams().(*mcp.InitializeParams)
-				ctx, span = telemetry.StartInitializeSpan(ctx)
+				ctx, span = telemetry.StartInitializeSpan(ctx, telemetry.ClientCapabilityAttributes(params)...)
 				telemetry.RecordInitialize(ctx, params)
 				tracked = true
 			case "tools/list":
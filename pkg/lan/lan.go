@@ -0,0 +1,157 @@
+package lan
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// DefaultTimeout is how long discovery waits for the mDNS responders and the probed hosts.
+const DefaultTimeout = 3 * time.Second
+
+const (
+	SourceMDNS      = "mdns"
+	SourceWellKnown = "well-known"
+)
+
+// Server is an MCP server found on the local network. It's not trusted: anything on the network can advertise one.
+type Server struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+	// Transport is sse or streamable-http.
+	Transport string `json:"transport"`
+	// Auth is bearer or oauth when the server requires authentication, if it advertises it.
+	Auth string `json:"auth,omitempty"`
+	// Source tells how the server was found: mdns or well-known.
+	Source string `json:"source"`
+}
+
+// Options of a discovery.
+type Options struct {
+	// MDNS browses the MCP servers advertised with mDNS.
+	MDNS bool
+	// Hosts are the host:port probed for a /.well-known/mcp-gateway document.
+	Hosts   []string
+	Timeout time.Duration
+}
+
+// Discover finds the MCP servers advertised on the local network, sorted by name.
+// The errors of the hosts that couldn't be probed are returned along with the servers that were found.
+func Discover(ctx context.Context, opts Options) ([]Server, []error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		servers []Server
+		errs    []error
+		wg      sync.WaitGroup
+	)
+	collect := func(found []Server, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		servers = append(servers, found...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if opts.MDNS {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			collect(Browse(ctx))
+		}()
+	}
+
+	client := &http.Client{Timeout: timeout}
+	for _, host := range opts.Hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server, err := Probe(ctx, client, host)
+			if err != nil {
+				collect(nil, err)
+				return
+			}
+			collect([]Server{server}, nil)
+		}()
+	}
+	wg.Wait()
+
+	return dedupe(servers), errs
+}
+
+// dedupe keeps one server per URL and makes the names unique, then sorts the servers by name.
+func dedupe(servers []Server) []Server {
+	byURL := map[string]Server{}
+	for _, server := range servers {
+		existing, found := byURL[server.URL]
+		// The well-known document describes the server better than its mDNS advertisement
+		if !found || (existing.Source == SourceMDNS && server.Source == SourceWellKnown) {
+			byURL[server.URL] = server
+		}
+	}
+
+	unique := make([]Server, 0, len(byURL))
+	for _, server := range byURL {
+		unique = append(unique, server)
+	}
+	slices.SortFunc(unique, func(a, b Server) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.URL, b.URL)
+	})
+
+	taken := map[string]int{}
+	for i := range unique {
+		name := unique[i].Name
+		taken[name]++
+		if count := taken[name]; count > 1 {
+			unique[i].Name = fmt.Sprintf("%s-%d", name, count)
+		}
+	}
+	return unique
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// serverName turns an advertised name into a name usable in catalogs and tool prefixes.
+func serverName(name string) string {
+	cleaned := strings.Trim(unsafeNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if cleaned == "" {
+		return "mcp-server"
+	}
+	return cleaned
+}
+
+// CatalogServer is the catalog entry of a discovered server, as a remote server.
+func (s Server) CatalogServer() catalog.Server {
+	description := s.Description
+	if description == "" {
+		description = "MCP server discovered on the local network at " + s.URL
+	}
+	return catalog.Server{
+		Name:        s.Name,
+		Type:        "remote",
+		Title:       s.Name,
+		Description: description,
+		Remote: catalog.Remote{
+			URL:       s.URL,
+			Transport: s.Transport,
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package lan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestDedupe(t *testing.T) {
+	servers := dedupe([]Server{
+		{Name: "nas", URL: "http://192.168.1.20:8811/mcp", Source: SourceMDNS},
+		{Name: "nas", URL: "http://192.168.1.20:8811/mcp", Description: "gateway", Source: SourceWellKnown},
+		{Name: "nas", URL: "http://192.168.1.21:8811/mcp", Source: SourceMDNS},
+		{Name: "alpha", URL: "http://192.168.1.30:8811/mcp", Source: SourceMDNS},
+	})
+
+	assert.Equal(t, []Server{
+		{Name: "alpha", URL: "http://192.168.1.30:8811/mcp", Source: SourceMDNS},
+		{Name: "nas", URL: "http://192.168.1.20:8811/mcp", Description: "gateway", Source: SourceWellKnown},
+		{Name: "nas-2", URL: "http://192.168.1.21:8811/mcp", Source: SourceMDNS},
+	}, servers)
+}
+
+func TestServerName(t *testing.T) {
+	assert.Equal(t, "notes-server", serverName("Notes Server"))
+	assert.Equal(t, "mcp-server", serverName("***"))
+}
+
+func TestCatalogServer(t *testing.T) {
+	server := Server{Name: "notes", URL: "http://192.168.1.20:8811/mcp", Transport: "streamable-http", Source: SourceMDNS}
+
+	assert.Equal(t, catalog.Server{
+		Name:        "notes",
+		Type:        "remote",
+		Title:       "notes",
+		Description: "MCP server discovered on the local network at http://192.168.1.20:8811/mcp",
+		Remote:      catalog.Remote{URL: "http://192.168.1.20:8811/mcp", Transport: "streamable-http"},
+	}, server.CatalogServer())
+}
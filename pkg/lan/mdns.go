@@ -0,0 +1,201 @@
+package lan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// ServiceType is the DNS-SD service type with which MCP servers are advertised.
+	ServiceType = "_mcp._tcp.local."
+
+	mdnsAddress = "224.0.0.251:5353"
+	// unicastResponse is the top bit of the class of a question, asking for a unicast response.
+	unicastResponse = 1 << 15
+)
+
+// Browse sends an mDNS query for the MCP servers and collects the answers until the context is done.
+//
+// The query is sent from an ephemeral port: responders answer it directly, as a legacy unicast query,
+// so that the gateway doesn't need to join the multicast group on port 5353.
+// The TXT record of a server can set its path (/mcp by default), transport (streamable-http by default, or sse),
+// scheme (http by default), name, description and auth.
+func Browse(ctx context.Context) ([]Server, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		return nil, fmt.Errorf("listening for mDNS responses: %w", err)
+	}
+	defer conn.Close()
+
+	query, err := browseQuery()
+	if err != nil {
+		return nil, err
+	}
+	destination, err := net.ResolveUDPAddr("udp4", mdnsAddress)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteTo(query, destination); err != nil {
+		return nil, fmt.Errorf("sending the mDNS query: %w", err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(DefaultTimeout)
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+	// Stop reading when the context is canceled before the deadline
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	var messages []dnsmessage.Message
+	buf := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) || isTimeout(err) {
+				break
+			}
+			return nil, fmt.Errorf("reading mDNS responses: %w", err)
+		}
+
+		var message dnsmessage.Message
+		if err := message.Unpack(buf[:n]); err != nil || !message.Header.Response {
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	return serversOfResponses(messages), nil
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func browseQuery() ([]byte, error) {
+	name, err := dnsmessage.NewName(ServiceType)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(dnsmessage.Question{
+		Name:  name,
+		Type:  dnsmessage.TypePTR,
+		Class: dnsmessage.ClassINET | unicastResponse,
+	}); err != nil {
+		return nil, err
+	}
+	return builder.Finish()
+}
+
+type srvRecord struct {
+	target string
+	port   uint16
+}
+
+// serversOfResponses reads the servers from the records of the mDNS responses: the PTR records list the instances
+// of the service, their SRV and TXT records tell where and how to connect, and A records resolve their hosts.
+func serversOfResponses(messages []dnsmessage.Message) []Server {
+	var instances []string
+	srvs := map[string]srvRecord{}
+	txts := map[string][]string{}
+	addresses := map[string]string{}
+
+	for _, message := range messages {
+		for _, resource := range append(message.Answers, message.Additionals...) {
+			name := strings.ToLower(resource.Header.Name.String())
+			switch body := resource.Body.(type) {
+			case *dnsmessage.PTRResource:
+				if name == ServiceType {
+					instances = append(instances, strings.ToLower(body.PTR.String()))
+				}
+			case *dnsmessage.SRVResource:
+				srvs[name] = srvRecord{target: strings.ToLower(body.Target.String()), port: body.Port}
+			case *dnsmessage.TXTResource:
+				txts[name] = body.TXT
+			case *dnsmessage.AResource:
+				addresses[name] = net.IP(body.A[:]).String()
+			case *dnsmessage.AAAAResource:
+				if _, found := addresses[name]; !found {
+					addresses[name] = net.IP(body.AAAA[:]).String()
+				}
+			}
+		}
+	}
+
+	var servers []Server
+	seen := map[string]bool{}
+	for _, instance := range instances {
+		srv, found := srvs[instance]
+		if !found || seen[instance] {
+			continue
+		}
+		seen[instance] = true
+
+		host, found := addresses[srv.target]
+		if !found {
+			host = strings.TrimSuffix(srv.target, ".")
+		}
+		servers = append(servers, advertisedServer(instance, host, srv.port, txtValues(txts[instance])))
+	}
+	return servers
+}
+
+func advertisedServer(instance, host string, port uint16, txt map[string]string) Server {
+	transport := "streamable-http"
+	path := "/mcp"
+	if strings.EqualFold(txt["transport"], "sse") {
+		transport = "sse"
+		path = "/sse"
+	}
+	if txt["path"] != "" {
+		path = "/" + strings.TrimPrefix(txt["path"], "/")
+	}
+	scheme := "http"
+	if strings.EqualFold(txt["scheme"], "https") {
+		scheme = "https"
+	}
+
+	name := txt["name"]
+	if name == "" {
+		name = strings.TrimSuffix(instance, "."+ServiceType)
+	}
+	description := txt["description"]
+	if description == "" {
+		description = txt["desc"]
+	}
+
+	return Server{
+		Name:        serverName(name),
+		Description: description,
+		URL:         scheme + "://" + net.JoinHostPort(host, strconv.Itoa(int(port))) + path,
+		Transport:   transport,
+		Auth:        txt["auth"],
+		Source:      SourceMDNS,
+	}
+}
+
+// txtValues reads the key=value strings of a TXT record.
+func txtValues(txt []string) map[string]string {
+	values := map[string]string{}
+	for _, entry := range txt {
+		key, value, _ := strings.Cut(entry, "=")
+		values[strings.ToLower(key)] = value
+	}
+	return values
+}
@@ -0,0 +1,72 @@
+package lan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func mustName(t *testing.T, name string) dnsmessage.Name {
+	t.Helper()
+	n, err := dnsmessage.NewName(name)
+	require.NoError(t, err)
+	return n
+}
+
+func response(t *testing.T, answers, additionals []dnsmessage.Resource) dnsmessage.Message {
+	t.Helper()
+	return dnsmessage.Message{
+		Header:      dnsmessage.Header{Response: true},
+		Answers:     answers,
+		Additionals: additionals,
+	}
+}
+
+func header(t *testing.T, name string) dnsmessage.ResourceHeader {
+	t.Helper()
+	return dnsmessage.ResourceHeader{Name: mustName(t, name), Class: dnsmessage.ClassINET}
+}
+
+func TestServersOfResponses(t *testing.T) {
+	notes := response(t,
+		[]dnsmessage.Resource{{Header: header(t, ServiceType), Body: &dnsmessage.PTRResource{PTR: mustName(t, "Notes Server."+ServiceType)}}},
+		[]dnsmessage.Resource{
+			{Header: header(t, "Notes Server."+ServiceType), Body: &dnsmessage.SRVResource{Target: mustName(t, "nas.local."), Port: 8811}},
+			{Header: header(t, "Notes Server."+ServiceType), Body: &dnsmessage.TXTResource{TXT: []string{"description=Notes of the team", "auth=bearer"}}},
+			{Header: header(t, "nas.local."), Body: &dnsmessage.AResource{A: [4]byte{192, 168, 1, 20}}},
+		},
+	)
+	search := response(t,
+		[]dnsmessage.Resource{{Header: header(t, ServiceType), Body: &dnsmessage.PTRResource{PTR: mustName(t, "search."+ServiceType)}}},
+		[]dnsmessage.Resource{
+			{Header: header(t, "search."+ServiceType), Body: &dnsmessage.SRVResource{Target: mustName(t, "printer.local."), Port: 9000}},
+			{Header: header(t, "search."+ServiceType), Body: &dnsmessage.TXTResource{TXT: []string{"transport=sse", "path=events", "name=Search"}}},
+		},
+	)
+	// Instances without a SRV record are skipped
+	incomplete := response(t,
+		[]dnsmessage.Resource{{Header: header(t, ServiceType), Body: &dnsmessage.PTRResource{PTR: mustName(t, "other."+ServiceType)}}},
+		nil,
+	)
+
+	servers := serversOfResponses([]dnsmessage.Message{notes, search, incomplete, notes})
+
+	assert.Equal(t, []Server{
+		{Name: "notes-server", Description: "Notes of the team", URL: "http://192.168.1.20:8811/mcp", Transport: "streamable-http", Auth: "bearer", Source: SourceMDNS},
+		{Name: "search", URL: "http://printer.local:9000/events", Transport: "sse", Source: SourceMDNS},
+	}, servers)
+}
+
+func TestBrowseQuery(t *testing.T) {
+	query, err := browseQuery()
+	require.NoError(t, err)
+
+	var message dnsmessage.Message
+	require.NoError(t, message.Unpack(query))
+	require.Len(t, message.Questions, 1)
+	assert.Equal(t, ServiceType, message.Questions[0].Name.String())
+	assert.Equal(t, dnsmessage.TypePTR, message.Questions[0].Type)
+	assert.Equal(t, dnsmessage.ClassINET|unicastResponse, message.Questions[0].Class)
+}
@@ -0,0 +1,83 @@
+package lan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WellKnownPath is where MCP gateways describe how to connect to them.
+const WellKnownPath = "/.well-known/mcp-gateway"
+
+// wellKnownDocument is the part of the discovery document of a gateway needed to connect to it.
+type wellKnownDocument struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Transport string `json:"transport"`
+	Endpoints struct {
+		MCP string `json:"mcp"`
+	} `json:"endpoints"`
+	Auth struct {
+		Type string `json:"type"`
+	} `json:"auth"`
+	Tools int `json:"tools"`
+}
+
+// Probe reads the discovery document of a host, given as host:port or as a URL.
+func Probe(ctx context.Context, client *http.Client, host string) (Server, error) {
+	base := host
+	if !strings.Contains(base, "://") {
+		base = "http://" + base
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil || baseURL.Host == "" {
+		return Server{}, fmt.Errorf("invalid host %q", host)
+	}
+	baseURL.Path = ""
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL.JoinPath(WellKnownPath).String(), nil)
+	if err != nil {
+		return Server{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Server{}, fmt.Errorf("probing %s: %w", host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Server{}, fmt.Errorf("probing %s: %s", host, resp.Status)
+	}
+
+	var document wellKnownDocument
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return Server{}, fmt.Errorf("probing %s: invalid discovery document: %w", host, err)
+	}
+	if document.Endpoints.MCP == "" {
+		return Server{}, fmt.Errorf("probing %s: the discovery document has no MCP endpoint", host)
+	}
+
+	endpoint, err := baseURL.Parse(document.Endpoints.MCP)
+	if err != nil {
+		return Server{}, fmt.Errorf("probing %s: invalid MCP endpoint %q", host, document.Endpoints.MCP)
+	}
+	transport := "streamable-http"
+	if strings.EqualFold(document.Transport, "sse") {
+		transport = "sse"
+	}
+	auth := document.Auth.Type
+	if auth == "none" {
+		auth = ""
+	}
+
+	return Server{
+		Name:        serverName(baseURL.Hostname()),
+		Description: fmt.Sprintf("%s %s with %d tools, at %s", document.Name, document.Version, document.Tools, baseURL.Host),
+		URL:         endpoint.String(),
+		Transport:   transport,
+		Auth:        auth,
+		Source:      SourceWellKnown,
+	}, nil
+}
@@ -0,0 +1,43 @@
+package lan
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != WellKnownPath {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"docker-mcp-gateway","version":"v1.2.0","transport":"streamable-http","endpoints":{"mcp":"/mcp","health":"/health"},"auth":{"type":"bearer"},"servers":2,"tools":14}`))
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	discovered, err := Probe(t.Context(), server.Client(), host)
+	require.NoError(t, err)
+
+	assert.Equal(t, Server{
+		Name:        "127-0-0-1",
+		Description: "docker-mcp-gateway v1.2.0 with 14 tools, at " + host,
+		URL:         server.URL + "/mcp",
+		Transport:   "streamable-http",
+		Auth:        "bearer",
+		Source:      SourceWellKnown,
+	}, discovered)
+}
+
+func TestProbeNoDocument(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := Probe(t.Context(), server.Client(), server.URL)
+	require.ErrorContains(t, err, "404")
+}
@@ -0,0 +1,113 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DefaultMaxFrameSize is how much of each JSON-RPC frame is logged, unless configured otherwise.
+const DefaultMaxFrameSize = 4096
+
+// FrameLogging configures the logging of the JSON-RPC frames exchanged with clients and servers.
+// Frames are not logged if Writer is nil.
+type FrameLogging struct {
+	Writer io.Writer
+	// Sample logs one frame out of Sample, per connection. 0 or 1 logs every frame.
+	Sample int
+	// MaxFrameSize truncates the frames that are logged. 0 means DefaultMaxFrameSize.
+	MaxFrameSize int
+}
+
+var (
+	frameLoggingMu sync.RWMutex
+	frameLogging   FrameLogging
+)
+
+// SetFrameLogging configures the logging of the JSON-RPC frames.
+func SetFrameLogging(config FrameLogging) {
+	frameLoggingMu.Lock()
+	defer frameLoggingMu.Unlock()
+	frameLogging = config
+}
+
+// WrapTransport logs the frames of a transport, if frame logging is enabled.
+func WrapTransport(name string, transport mcp.Transport) mcp.Transport {
+	frameLoggingMu.RLock()
+	config := frameLogging
+	frameLoggingMu.RUnlock()
+
+	if config.Writer == nil {
+		return transport
+	}
+	if config.MaxFrameSize <= 0 {
+		config.MaxFrameSize = DefaultMaxFrameSize
+	}
+	return &frameLoggingTransport{name: name, transport: transport, config: config}
+}
+
+// frameLoggingTransport is like mcp.LoggingTransport, but it samples and truncates the frames it logs,
+// so that long sessions don't produce unbounded logs.
+type frameLoggingTransport struct {
+	name      string
+	transport mcp.Transport
+	config    FrameLogging
+}
+
+func (t *frameLoggingTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	conn, err := t.transport.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &frameLoggingConn{Connection: conn, name: t.name, config: t.config}, nil
+}
+
+type frameLoggingConn struct {
+	mcp.Connection
+	name   string
+	config FrameLogging
+	frames atomic.Int64
+	mu     sync.Mutex
+}
+
+func (c *frameLoggingConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	msg, err := c.Connection.Read(ctx)
+	if err == nil {
+		c.log("read", msg)
+	}
+	return msg, err
+}
+
+func (c *frameLoggingConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	err := c.Connection.Write(ctx, msg)
+	if err == nil {
+		c.log("write", msg)
+	}
+	return err
+}
+
+func (c *frameLoggingConn) log(direction string, msg jsonrpc.Message) {
+	n := c.frames.Add(1) - 1
+	if c.config.Sample > 1 && n%int64(c.config.Sample) != 0 {
+		return
+	}
+
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return
+	}
+	suffix := ""
+	if len(data) > c.config.MaxFrameSize {
+		suffix = fmt.Sprintf("... (%d bytes)", len(data))
+		data = data[:c.config.MaxFrameSize]
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, _ = fmt.Fprintf(c.config.Writer, "  - %s %s: %s%s\n", c.name, direction, data, suffix)
+}
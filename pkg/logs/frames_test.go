@@ -0,0 +1,44 @@
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapTransportDisabled(t *testing.T) {
+	SetFrameLogging(FrameLogging{})
+
+	transport, _ := mcp.NewInMemoryTransports()
+	assert.Same(t, transport, WrapTransport("server", transport))
+}
+
+func TestFrameLogging(t *testing.T) {
+	var buf bytes.Buffer
+	SetFrameLogging(FrameLogging{Writer: &buf, Sample: 2, MaxFrameSize: 60})
+	t.Cleanup(func() { SetFrameLogging(FrameLogging{}) })
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "server"}, nil)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil)
+	session, err := client.Connect(t.Context(), WrapTransport("test", clientTransport), nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	require.NoError(t, session.Ping(t.Context(), nil))
+
+	// One frame out of two is logged, and long frames are truncated
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], `  - test write: {"jsonrpc":"2.0","id":1,"method":"initialize"`))
+	assert.Contains(t, lines[0], "... (")
+	assert.True(t, strings.HasPrefix(lines[1], `  - test write: {"jsonrpc":"2.0","method":"notifications/initialized"`))
+	assert.True(t, strings.HasPrefix(lines[2], `  - test write: {"jsonrpc":"2.0","id":2,"method":"ping"}`))
+}
@@ -0,0 +1,182 @@
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit limits how many messages a source (a server, a session...) can log per interval.
+// Zero messages means no limit.
+type RateLimit struct {
+	Messages int
+	Interval time.Duration
+}
+
+// DefaultRateLimit is the rate limit of the gateway, unless configured otherwise.
+var DefaultRateLimit = RateLimit{
+	Messages: 100,
+	Interval: 10 * time.Second,
+}
+
+// RateLimiter counts the messages logged by each source. Past the limit, messages are suppressed
+// and summarized once the interval is over.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limit   RateLimit
+	sources map[string]*window
+}
+
+type window struct {
+	start      time.Time
+	count      int
+	suppressed int
+	pending    bool
+}
+
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		sources: map[string]*window{},
+	}
+}
+
+var rateLimiter = NewRateLimiter(RateLimit{})
+
+// SetRateLimit sets the rate limit applied to the messages logged by the servers.
+func SetRateLimit(limit RateLimit) {
+	rateLimiter.SetLimit(limit)
+}
+
+// Allow tells whether a source can log a message. Exempt messages, usually errors, are always allowed
+// and don't count towards the limit. When messages are suppressed, summarize is called with their count
+// at the end of the interval.
+func (r *RateLimiter) Allow(source string, exempt bool, summarize func(suppressed int)) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if exempt || r.limit.Messages <= 0 || r.limit.Interval <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	w, found := r.sources[source]
+	if !found || (!w.pending && now.Sub(w.start) >= r.limit.Interval) {
+		r.prune(now)
+		w = &window{start: now}
+		r.sources[source] = w
+	}
+
+	if w.count < r.limit.Messages {
+		w.count++
+		return true
+	}
+
+	w.suppressed++
+	if !w.pending {
+		w.pending = true
+		time.AfterFunc(r.limit.Interval-now.Sub(w.start), func() {
+			r.mu.Lock()
+			suppressed := w.suppressed
+			delete(r.sources, source)
+			r.mu.Unlock()
+
+			summarize(suppressed)
+		})
+	}
+	return false
+}
+
+// SetLimit changes the rate limit. Messages already counted are forgotten.
+func (r *RateLimiter) SetLimit(limit RateLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.limit = limit
+	for source, w := range r.sources {
+		if !w.pending {
+			delete(r.sources, source)
+		}
+	}
+}
+
+// prune forgets the sources whose interval is over, must be called with r.mu held.
+func (r *RateLimiter) prune(now time.Time) {
+	for source, w := range r.sources {
+		if !w.pending && now.Sub(w.start) >= r.limit.Interval {
+			delete(r.sources, source)
+		}
+	}
+}
+
+// AllowMessage tells whether a source can log a message, given the gateway's rate limit.
+func AllowMessage(source string, exempt bool, summarize func(suppressed int)) bool {
+	return rateLimiter.Allow(source, exempt, summarize)
+}
+
+// SuppressedMessage is the line that summarizes suppressed messages.
+func SuppressedMessage(suppressed int) string {
+	return fmt.Sprintf("%d messages suppressed", suppressed)
+}
+
+type rateLimitedWriter struct {
+	mu      sync.Mutex
+	writer  io.Writer
+	limiter *RateLimiter
+	source  string
+	buf     bytes.Buffer
+}
+
+// NewRateLimitedWriter writes the lines logged by a source to a writer, given the gateway's rate limit.
+// Lines that look like errors are never suppressed.
+func NewRateLimitedWriter(writer io.Writer, source string) io.Writer {
+	return newRateLimitedWriter(writer, rateLimiter, source)
+}
+
+func newRateLimitedWriter(writer io.Writer, limiter *RateLimiter, source string) io.Writer {
+	return &rateLimitedWriter{
+		writer:  writer,
+		limiter: limiter,
+		source:  source,
+	}
+}
+
+func (rw *rateLimitedWriter) Write(payload []byte) (int, error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	rw.buf.Write(payload)
+	for {
+		line, err := rw.buf.ReadBytes('\n')
+		if err != nil {
+			// Keep the incomplete line for the next write
+			rw.buf.Reset()
+			rw.buf.Write(line)
+			break
+		}
+
+		if !rw.limiter.Allow(rw.source, isErrorLine(line), rw.summarize) {
+			continue
+		}
+		if _, err := rw.writer.Write(line); err != nil {
+			return len(payload), err
+		}
+	}
+
+	return len(payload), nil
+}
+
+func (rw *rateLimitedWriter) summarize(suppressed int) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	_, _ = fmt.Fprintln(rw.writer, SuppressedMessage(suppressed))
+}
+
+func isErrorLine(line []byte) bool {
+	lower := strings.ToLower(string(line))
+	return strings.Contains(lower, "error") || strings.Contains(lower, "fatal") || strings.Contains(lower, "panic")
+}
@@ -0,0 +1,78 @@
+package logs
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{Messages: 2, Interval: 50 * time.Millisecond})
+
+	var mu sync.Mutex
+	var summarized []int
+	summarize := func(suppressed int) {
+		mu.Lock()
+		defer mu.Unlock()
+		summarized = append(summarized, suppressed)
+	}
+
+	assert.True(t, limiter.Allow("a", false, summarize))
+	assert.True(t, limiter.Allow("a", false, summarize))
+	assert.False(t, limiter.Allow("a", false, summarize))
+	assert.False(t, limiter.Allow("a", false, summarize))
+	assert.True(t, limiter.Allow("a", true, summarize), "exempt messages are always allowed")
+	assert.True(t, limiter.Allow("b", false, summarize), "sources are limited separately")
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(summarized) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int{2}, summarized)
+
+	assert.True(t, limiter.Allow("a", false, summarize), "a new interval starts after the summary")
+}
+
+func TestRateLimiterNoLimit(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{})
+
+	for range 1000 {
+		assert.True(t, limiter.Allow("a", false, func(int) { t.Fatal("nothing should be suppressed") }))
+	}
+}
+
+func TestRateLimitedWriter(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{Messages: 1, Interval: 50 * time.Millisecond})
+
+	var out syncBuffer
+	writer := newRateLimitedWriter(NewPrefixer(&out, "- server: "), limiter, "server")
+
+	_, _ = writer.Write([]byte("first\nsec"))
+	_, _ = writer.Write([]byte("ond\nthird\n"))
+	_, _ = writer.Write([]byte("Error: something failed\n"))
+
+	assert.Eventually(t, func() bool {
+		return out.String() == "- server: first\n- server: Error: something failed\n- server: 2 messages suppressed\n"
+	}, time.Second, 10*time.Millisecond, out.String())
+}
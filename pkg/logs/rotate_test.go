@@ -0,0 +1,84 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.log")
+
+	f, err := OpenRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	assertFile(t, path, "fourth\n")
+	assertFile(t, path+".1", "third\n")
+	assertFile(t, path+".2", "second\n")
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestRotatingFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.log")
+	require.NoError(t, os.WriteFile(path, []byte("previous\n"), 0o644))
+
+	f, err := OpenRotatingFile(path, 12, 1)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = f.Write([]byte("next\n"))
+	require.NoError(t, err)
+
+	assertFile(t, path, "next\n")
+	assertFile(t, path+".1", "previous\n")
+}
+
+func TestRotatingFileWithoutBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.log")
+
+	f, err := OpenRotatingFile(path, 8, 0)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = f.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("second\n"))
+	require.NoError(t, err)
+
+	assertFile(t, path, "second\n")
+	assert.NoFileExists(t, path+".1")
+}
+
+func TestRotatingFileDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.log")
+
+	f, err := OpenRotatingFile(path, 0, 2)
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	assertFile(t, path, "first\nsecond\n")
+	_, err = f.Write([]byte("closed\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+}
+
+func assertFile(t *testing.T, path, expected string) {
+	t.Helper()
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(buf))
+}
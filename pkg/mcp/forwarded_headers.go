@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type forwardedHeadersKey struct{}
+
+// Headers that are never forwarded to remote servers, even when allowlisted:
+// hop-by-hop headers and the credentials used to talk to the gateway itself.
+var neverForwardedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Connection":          true,
+	"Content-Length":      true,
+	"Cookie":              true,
+	"Host":                true,
+	"Keep-Alive":          true,
+	"Mcp-Session-Id":      true,
+	"Proxy-Authorization": true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+}
+
+// WithForwardedHeaders attaches the HTTP headers of the client's request to the context.
+// Remote servers only receive the headers they allowlist.
+func WithForwardedHeaders(ctx context.Context, header http.Header) context.Context {
+	if len(header) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, forwardedHeadersKey{}, header)
+}
+
+// forwardedHeaders returns the headers of the client's request that are in the allowlist.
+func forwardedHeaders(ctx context.Context, allowlist []string) http.Header {
+	header, _ := ctx.Value(forwardedHeadersKey{}).(http.Header)
+	if len(header) == 0 || len(allowlist) == 0 {
+		return nil
+	}
+
+	forwarded := http.Header{}
+	for _, name := range allowlist {
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		if neverForwardedHeaders[name] {
+			continue
+		}
+		if values := header.Values(name); len(values) > 0 {
+			forwarded[name] = values
+		}
+	}
+	return forwarded
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderRoundTripperForwardsAllowlistedHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{
+		Transport: &headerRoundTripper{
+			base:           http.DefaultTransport,
+			headers:        map[string]string{"X-Tenant": "configured"},
+			forwardHeaders: []string{"x-user-email", "X-Tenant", "Authorization"},
+		},
+	}
+
+	clientHeader := http.Header{}
+	clientHeader.Set("X-User-Email", "jane@example.com")
+	clientHeader.Set("X-Tenant", "from-client")
+	clientHeader.Set("Authorization", "Bearer gateway-token")
+	clientHeader.Set("X-Other", "stripped")
+
+	req, err := http.NewRequestWithContext(WithForwardedHeaders(t.Context(), clientHeader), http.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "jane@example.com", received.Get("X-User-Email"))
+	assert.Equal(t, "configured", received.Get("X-Tenant"))
+	assert.Empty(t, received.Get("Authorization"))
+	assert.Empty(t, received.Get("X-Other"))
+}
+
+func TestForwardedHeadersWithoutAllowlist(t *testing.T) {
+	clientHeader := http.Header{}
+	clientHeader.Set("X-User-Email", "jane@example.com")
+
+	assert.Nil(t, forwardedHeaders(WithForwardedHeaders(t.Context(), clientHeader), nil))
+	assert.Nil(t, forwardedHeaders(t.Context(), []string{"X-User-Email"}))
+}
+
+func TestHeaderRoundTripperSetsLocale(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	get := func(roundTripper *headerRoundTripper) {
+		req, err := http.NewRequestWithContext(WithLocale(t.Context(), "fr-FR"), http.MethodPost, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: roundTripper}).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	get(&headerRoundTripper{base: http.DefaultTransport})
+	assert.Equal(t, "fr-FR", received.Get("Accept-Language"))
+
+	get(&headerRoundTripper{base: http.DefaultTransport, headers: map[string]string{"Accept-Language": "en"}})
+	assert.Equal(t, "en", received.Get("Accept-Language"))
+}
+
+func TestHeaderRoundTripperSwapsHeaders(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	headers := &headerRoundTripper{
+		base:    http.DefaultTransport,
+		headers: map[string]string{"Authorization": "Bearer old"},
+	}
+	httpClient := &http.Client{Transport: headers}
+
+	for _, token := range []string{"", "Bearer new"} {
+		if token != "" {
+			headers.setHeader("Authorization", token)
+		}
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := httpClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, []string{"Bearer old", "Bearer new"}, received)
+}
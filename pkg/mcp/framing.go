@@ -0,0 +1,255 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/telemetry"
+)
+
+const (
+	// FramingStrict closes the session when a server writes something that is not JSON-RPC to stdout.
+	FramingStrict = "strict"
+	// FramingLenient skips the lines a server writes to stdout that are not JSON-RPC.
+	FramingLenient = "lenient"
+)
+
+// maxHexdumpBytes caps the number of offending bytes that are logged for a framing error.
+const maxHexdumpBytes = 256
+
+// terminateDuration is how long closing a connection waits for the server to exit before signaling it.
+var terminateDuration = 5 * time.Second
+
+// ErrFraming is returned when a server writes a line that is not JSON-RPC to stdout, in strict mode.
+var ErrFraming = errors.New("framing error")
+
+// commandTransport runs a command and talks newline delimited JSON-RPC over its stdin/stdout.
+// Unlike the SDK's CommandTransport, it reports the lines that are not JSON-RPC and can skip them.
+type commandTransport struct {
+	name    string
+	framing string
+	cmd     *exec.Cmd
+}
+
+func (t *commandTransport) Connect(context.Context) (mcp.Connection, error) {
+	stdout, err := t.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdin, err := t.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return newStdioConn(t.name, t.framing, stdout, stdin, func() error {
+		return waitForCommand(t.cmd)
+	}), nil
+}
+
+// waitForCommand waits for a command whose stdin was closed to exit. It's terminated, then killed, if it doesn't.
+func waitForCommand(cmd *exec.Cmd) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(terminateDuration):
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err == nil {
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(terminateDuration):
+		}
+	}
+
+	if err := cmd.Process.Kill(); err != nil {
+		return err
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(terminateDuration):
+		return fmt.Errorf("unresponsive subprocess")
+	}
+}
+
+type messageOrError struct {
+	msg jsonrpc.Message
+	err error
+}
+
+// stdioConn is an mcp.Connection over a server's stdin/stdout.
+type stdioConn struct {
+	name    string
+	framing string
+
+	stdin io.WriteCloser
+	wait  func() error
+
+	writeMu  sync.Mutex
+	incoming chan messageOrError
+	closed   chan struct{}
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newStdioConn(name, framing string, stdout io.Reader, stdin io.WriteCloser, wait func() error) *stdioConn {
+	c := &stdioConn{
+		name:     name,
+		framing:  framing,
+		stdin:    stdin,
+		wait:     wait,
+		incoming: make(chan messageOrError),
+		closed:   make(chan struct{}),
+	}
+	go c.readLoop(stdout)
+	return c
+}
+
+func (c *stdioConn) readLoop(stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			msgs, decodeErr := decodeMessages(line)
+			if decodeErr != nil {
+				c.reportFramingError(line, decodeErr)
+				if c.framing != FramingLenient {
+					c.deliver(messageOrError{err: fmt.Errorf("%w: server %s wrote %d bytes that are not JSON-RPC to stdout: %v", ErrFraming, c.name, len(line), decodeErr)})
+					return
+				}
+			}
+			for _, msg := range msgs {
+				if !c.deliver(messageOrError{msg: msg}) {
+					return
+				}
+			}
+		}
+
+		if err != nil {
+			c.deliver(messageOrError{err: err})
+			return
+		}
+	}
+}
+
+// deliver hands a message over to Read. It returns false if the connection is closed.
+func (c *stdioConn) deliver(v messageOrError) bool {
+	select {
+	case c.incoming <- v:
+		return true
+	case <-c.closed:
+		return false
+	}
+}
+
+// decodeMessages decodes a line of JSON-RPC, that can be a single message or a batch.
+func decodeMessages(line []byte) ([]jsonrpc.Message, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) > 0 && line[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(line, &batch); err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			return nil, errors.New("empty batch")
+		}
+
+		msgs := make([]jsonrpc.Message, 0, len(batch))
+		for _, raw := range batch {
+			msg, err := jsonrpc.DecodeMessage(raw)
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, msg)
+		}
+		return msgs, nil
+	}
+
+	msg, err := jsonrpc.DecodeMessage(line)
+	if err != nil {
+		return nil, err
+	}
+	return []jsonrpc.Message{msg}, nil
+}
+
+func (c *stdioConn) reportFramingError(line []byte, err error) {
+	telemetry.RecordStdioFramingError(context.Background(), c.name, c.framing)
+
+	dump := line
+	if len(dump) > maxHexdumpBytes {
+		dump = dump[:maxHexdumpBytes]
+	}
+	action := "closing the session"
+	if c.framing == FramingLenient {
+		action = "skipped"
+	}
+	log.Logf("  ! Framing error: %s wrote %d bytes that are not JSON-RPC to stdout (%v), %s:\n%s", c.name, len(line), err, action, strings.TrimSuffix(hex.Dump(dump), "\n"))
+}
+
+func (c *stdioConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case v := <-c.incoming:
+		return v.msg, v.err
+	case <-c.closed:
+		return nil, io.EOF
+	}
+}
+
+func (c *stdioConn) Write(_ context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return fmt.Errorf("marshaling message: %w", err)
+	}
+	data = append(data, '\n')
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err = c.stdin.Write(data)
+	return err
+}
+
+func (c *stdioConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		if err := c.stdin.Close(); err != nil {
+			c.closeErr = fmt.Errorf("closing stdin: %w", err)
+			return
+		}
+		if c.wait != nil {
+			c.closeErr = c.wait()
+		}
+	})
+	return c.closeErr
+}
+
+func (c *stdioConn) SessionID() string {
+	return ""
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func readAll(t *testing.T, conn *stdioConn) ([]jsonrpc.Message, error) {
+	t.Helper()
+
+	var msgs []jsonrpc.Message
+	for {
+		msg, err := conn.Read(t.Context())
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+const (
+	notification = `{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"
+	response     = `{"jsonrpc":"2.0","id":1,"result":{}}` + "\n"
+)
+
+func TestStdioConnStrict(t *testing.T) {
+	stdout := strings.NewReader(notification + "Server started on port 8080\n" + response)
+	conn := newStdioConn("test", FramingStrict, stdout, nopWriteCloser{io.Discard}, nil)
+
+	msgs, err := readAll(t, conn)
+	require.ErrorIs(t, err, ErrFraming)
+	assert.Contains(t, err.Error(), "server test wrote 28 bytes that are not JSON-RPC")
+	assert.Len(t, msgs, 1)
+}
+
+func TestStdioConnLenient(t *testing.T) {
+	stdout := strings.NewReader(notification + "Server started on port 8080\n\n42\n" + response)
+	conn := newStdioConn("test", FramingLenient, stdout, nopWriteCloser{io.Discard}, nil)
+
+	msgs, err := readAll(t, conn)
+	require.ErrorIs(t, err, io.EOF)
+	require.Len(t, msgs, 2)
+	assert.IsType(t, &jsonrpc.Request{}, msgs[0])
+	assert.IsType(t, &jsonrpc.Response{}, msgs[1])
+}
+
+func TestStdioConnBatch(t *testing.T) {
+	stdout := strings.NewReader("[" + strings.TrimSpace(notification) + "," + strings.TrimSpace(response) + "]\n")
+	conn := newStdioConn("test", FramingStrict, stdout, nopWriteCloser{io.Discard}, nil)
+
+	msgs, err := readAll(t, conn)
+	require.ErrorIs(t, err, io.EOF)
+	assert.Len(t, msgs, 2)
+}
+
+func TestStdioConnWrite(t *testing.T) {
+	var stdin strings.Builder
+	conn := newStdioConn("test", FramingStrict, strings.NewReader(""), nopWriteCloser{&stdin}, nil)
+
+	id, err := jsonrpc.MakeID(float64(1))
+	require.NoError(t, err)
+	require.NoError(t, conn.Write(t.Context(), &jsonrpc.Request{ID: id, Method: "ping"}))
+	require.NoError(t, conn.Close())
+
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"method":"ping"}`, strings.TrimSpace(stdin.String()))
+	assert.True(t, strings.HasSuffix(stdin.String(), "\n"))
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// inProcessMCPClient talks to an MCP server running in the gateway's process, over in-memory pipes.
+type inProcessMCPClient struct {
+	name        string
+	newServer   func(context.Context) (*mcp.Server, func(), error)
+	client      *mcp.Client
+	session     *mcp.ClientSession
+	roots       []*mcp.Root
+	initialized atomic.Bool
+}
+
+func NewInProcessMCPClient(name string, newServer func(context.Context) (*mcp.Server, error)) Client {
+	return NewInProcessMCPClientWithCleanup(name, func(ctx context.Context) (*mcp.Server, func(), error) {
+		server, err := newServer(ctx)
+		return server, nil, err
+	})
+}
+
+// NewInProcessMCPClientWithCleanup is like NewInProcessMCPClient, for servers holding resources that
+// must be released once their session is closed.
+func NewInProcessMCPClientWithCleanup(name string, newServer func(context.Context) (*mcp.Server, func(), error)) Client {
+	return &inProcessMCPClient{
+		name:      name,
+		newServer: newServer,
+	}
+}
+
+func (c *inProcessMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams, _ bool, ss *mcp.ServerSession, server *mcp.Server, refresher CapabilityRefresher) error {
+	if c.initialized.Load() {
+		return fmt.Errorf("client already initialized")
+	}
+
+	inProcessServer, cleanup, err := c.newServer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create server %s: %w", c.name, err)
+	}
+	if cleanup == nil {
+		cleanup = func() {}
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := inProcessServer.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		cleanup()
+		return fmt.Errorf("failed to connect to server %s: %w", c.name, err)
+	}
+	go func() {
+		_ = serverSession.Wait()
+		cleanup()
+	}()
+
+	c.client = mcp.NewClient(&mcp.Implementation{
+		Name:    "docker-mcp-gateway",
+		Version: "1.0.0",
+	}, notifications(c.name, ss, server, refresher))
+
+	c.client.AddRoots(c.roots...)
+
+	session, err := c.client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+
+	c.session = session
+	c.initialized.Store(true)
+
+	return nil
+}
+
+func (c *inProcessMCPClient) Session() *mcp.ClientSession { return c.session }
+func (c *inProcessMCPClient) GetClient() *mcp.Client      { return c.client }
+
+func (c *inProcessMCPClient) AddRoots(roots []*mcp.Root) {
+	if c.initialized.Load() {
+		c.client.AddRoots(roots...)
+	}
+	c.roots = roots
+}
@@ -0,0 +1,19 @@
+package mcp
+
+import "context"
+
+type localeKey struct{}
+
+// WithLocale attaches the locale in which results should be written to the context.
+// Remote servers receive it as the Accept-Language header, unless the header is already set.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	if locale == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+func localeFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/logs"
 )
 
 // Client interface wraps the official MCP SDK client with our legacy interface
@@ -52,9 +54,24 @@ func notifications(serverName string, serverSession *mcp.ServerSession, server *
 			}
 		},
 		LoggingMessageHandler: func(ctx context.Context, req *mcp.LoggingMessageRequest) {
-			if serverSession != nil {
-				_ = serverSession.Log(ctx, req.Params)
+			if serverSession == nil {
+				return
+			}
+
+			// Rate limit the messages of each server, per session, but always forward errors
+			source := serverName + "/" + serverSession.ID()
+			summarize := func(suppressed int) {
+				_ = serverSession.Log(context.Background(), &mcp.LoggingMessageParams{
+					Level:  "warning",
+					Logger: serverName,
+					Data:   logs.SuppressedMessage(suppressed),
+				})
+			}
+			if !logs.AllowMessage(source, isErrorLevel(req.Params.Level), summarize) {
+				return
 			}
+
+			_ = serverSession.Log(ctx, req.Params)
 		},
 		ElicitationHandler: func(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
 			if serverSession != nil {
@@ -64,3 +81,12 @@ func notifications(serverName string, serverSession *mcp.ServerSession, server *
 		},
 	}
 }
+
+func isErrorLevel(level mcp.LoggingLevel) bool {
+	switch level {
+	case "error", "critical", "alert", "emergency":
+		return true
+	default:
+		return false
+	}
+}
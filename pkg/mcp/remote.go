@@ -3,14 +3,18 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/oauth2"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/httpclient"
 	"github.com/docker/mcp-gateway/pkg/oauth"
 )
 
@@ -20,6 +24,14 @@ type remoteMCPClient struct {
 	session     *mcp.ClientSession
 	roots       []*mcp.Root
 	initialized atomic.Bool
+	// headers adds the headers, including the OAuth token, to the requests made to the server
+	headers *headerRoundTripper
+}
+
+// OAuthTokenSwapper is implemented by the clients that can switch to a refreshed OAuth token
+// without reconnecting, so that the requests in flight aren't dropped.
+type OAuthTokenSwapper interface {
+	SwapOAuthToken(ctx context.Context) bool
 }
 
 func NewRemoteMCPClient(config *catalog.ServerConfig) Client {
@@ -28,7 +40,7 @@ func NewRemoteMCPClient(config *catalog.ServerConfig) Client {
 	}
 }
 
-func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams, _ bool, _ *mcp.ServerSession, _ *mcp.Server, _ CapabilityRefresher) error {
+func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams, _ bool, ss *mcp.ServerSession, server *mcp.Server, refresher CapabilityRefresher) error {
 	if c.initialized.Load() {
 		return fmt.Errorf("client already initialized")
 	}
@@ -70,12 +82,32 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParam
 	var mcpTransport mcp.Transport
 	var err error
 
+	// Machine-to-machine remotes get a token with the client_credentials grant,
+	// renewed by the transport when it expires
+	// Each remote server keeps its own pool of keep-alive connections
+	pool := httpclient.Transport(c.config.Name)
+	base := pool
+	if c.config.Spec.OAuth != nil && c.config.Spec.OAuth.ClientCredentials != nil {
+		clientCredentials := c.config.Spec.OAuth.ClientCredentials
+		base = &oauth2.Transport{
+			Base: pool,
+			Source: oauth.ClientCredentialsTokenSource(oauth.ClientCredentialsConfig{
+				TokenURL:     expandEnv(clientCredentials.TokenURL, env),
+				ClientID:     expandEnv(clientCredentials.ClientID, env),
+				ClientSecret: expandEnv(clientCredentials.ClientSecret, env),
+				Scopes:       c.config.Spec.OAuth.Scopes,
+			}),
+		}
+	}
+
 	// Create HTTP client with custom headers
+	c.headers = &headerRoundTripper{
+		base:           base,
+		headers:        headers,
+		forwardHeaders: c.config.Spec.Remote.ForwardHeaders,
+	}
 	httpClient := &http.Client{
-		Transport: &headerRoundTripper{
-			base:    http.DefaultTransport,
-			headers: headers,
-		},
+		Transport: c.headers,
 	}
 
 	switch strings.ToLower(transport) {
@@ -96,7 +128,7 @@ func (c *remoteMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParam
 	c.client = mcp.NewClient(&mcp.Implementation{
 		Name:    "docker-mcp-gateway",
 		Version: "1.0.0",
-	}, nil)
+	}, notifications(c.config.Name, ss, server, refresher))
 
 	c.client.AddRoots(c.roots...)
 
@@ -121,6 +153,22 @@ func (c *remoteMCPClient) AddRoots(roots []*mcp.Root) {
 	c.roots = roots
 }
 
+// SwapOAuthToken makes the next requests to the server use the OAuth token currently in the credential store.
+// It returns false if the client doesn't use an OAuth token, or if there's none to swap to.
+func (c *remoteMCPClient) SwapOAuthToken(ctx context.Context) bool {
+	if !c.initialized.Load() || c.headers == nil {
+		return false
+	}
+
+	token := c.getOAuthToken(ctx)
+	if token == "" {
+		return false
+	}
+
+	c.headers.setHeader("Authorization", "Bearer "+token)
+	return true
+}
+
 func expandEnv(value string, secrets map[string]string) string {
 	return os.Expand(value, func(name string) string {
 		return secrets[name]
@@ -129,24 +177,50 @@ func expandEnv(value string, secrets map[string]string) string {
 
 // headerRoundTripper is an http.RoundTripper that adds custom headers to all requests
 type headerRoundTripper struct {
-	base    http.RoundTripper
-	headers map[string]string
+	base http.RoundTripper
+	// headersMu guards headers, replaced when the OAuth token is swapped while requests are made
+	headersMu sync.RWMutex
+	headers   map[string]string
+	// forwardHeaders lists the headers of the client's request that are passed through
+	forwardHeaders []string
 }
 
 func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	// Clone the request to avoid modifying the original
 	newReq := req.Clone(req.Context())
 	// Add custom headers
-	for key, value := range h.headers {
+	h.headersMu.RLock()
+	headers := h.headers
+	h.headersMu.RUnlock()
+	for key, value := range headers {
 		// Don't override Accept header if already set by streamable transport
 		if key == "Accept" && newReq.Header.Get("Accept") != "" {
 			continue
 		}
 		newReq.Header.Set(key, value)
 	}
+	// Pass through allowlisted headers from the client, without overriding configured ones
+	for key, values := range forwardedHeaders(req.Context(), h.forwardHeaders) {
+		if _, configured := headers[key]; configured || newReq.Header.Get(key) != "" {
+			continue
+		}
+		newReq.Header[key] = values
+	}
+	if locale := localeFromContext(req.Context()); locale != "" && newReq.Header.Get("Accept-Language") == "" {
+		newReq.Header.Set("Accept-Language", locale)
+	}
 	return h.base.RoundTrip(newReq)
 }
 
+func (h *headerRoundTripper) setHeader(key, value string) {
+	h.headersMu.Lock()
+	defer h.headersMu.Unlock()
+
+	headers := maps.Clone(h.headers)
+	headers[key] = value
+	h.headers = headers
+}
+
 func (c *remoteMCPClient) getOAuthToken(ctx context.Context) string {
 	if c.config.Spec.OAuth == nil || len(c.config.Spec.OAuth.Providers) == 0 {
 		return ""
@@ -14,6 +14,7 @@ import (
 
 type stdioMCPClient struct {
 	name        string
+	framing     string
 	command     string
 	env         []string
 	args        []string
@@ -24,8 +25,17 @@ type stdioMCPClient struct {
 }
 
 func NewStdioCmdClient(name string, command string, env []string, args ...string) Client {
+	return NewStdioCmdClientWithFraming(name, FramingStrict, command, env, args...)
+}
+
+// NewStdioCmdClientWithFraming creates a stdio client that handles the lines that are not JSON-RPC given a framing mode.
+func NewStdioCmdClientWithFraming(name string, framing string, command string, env []string, args ...string) Client {
+	if framing != FramingLenient {
+		framing = FramingStrict
+	}
 	return &stdioMCPClient{
 		name:    name,
+		framing: framing,
 		command: command,
 		env:     env,
 		args:    args,
@@ -41,10 +51,10 @@ func (c *stdioMCPClient) Initialize(ctx context.Context, _ *mcp.InitializeParams
 	cmd.Env = c.env
 
 	if debug {
-		cmd.Stderr = logs.NewPrefixer(os.Stderr, "- "+c.name+": ")
+		cmd.Stderr = logs.NewRateLimitedWriter(logs.NewPrefixer(os.Stderr, "- "+c.name+": "), c.name)
 	}
 
-	transport := &mcp.CommandTransport{Command: cmd}
+	transport := logs.WrapTransport(c.name, &commandTransport{name: c.name, framing: c.framing, cmd: cmd})
 	c.client = mcp.NewClient(&mcp.Implementation{
 		Name:    "docker-mcp-gateway",
 		Version: "1.0.0",
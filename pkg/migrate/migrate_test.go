@@ -619,6 +619,10 @@ func (m *mockDockerClient) FindAllContainersByLabel(_ context.Context, _ string)
 	return nil, nil
 }
 
+func (m *mockDockerClient) ListContainersByLabel(_ context.Context, _ string) ([]container.Summary, error) {
+	return nil, nil
+}
+
 func (m *mockDockerClient) InspectContainer(_ context.Context, _ string) (container.InspectResponse, error) {
 	return container.InspectResponse{}, nil
 }
@@ -643,6 +647,14 @@ func (m *mockDockerClient) PullImages(_ context.Context, _ ...string) error {
 	return nil
 }
 
+func (m *mockDockerClient) PullImageWithOptions(_ context.Context, _ string, _ docker.PullOptions) error {
+	return nil
+}
+
+func (m *mockDockerClient) ServerPlatform(_ context.Context) (string, error) {
+	return "linux/amd64", nil
+}
+
 func (m *mockDockerClient) CreateNetwork(_ context.Context, _ string, _ bool, _ map[string]string) error {
 	return nil
 }
@@ -663,6 +675,14 @@ func (m *mockDockerClient) InspectVolume(ctx context.Context, name string) (volu
 	return volume.Volume{}, sql.ErrNoRows
 }
 
+func (m *mockDockerClient) CreateVolume(_ context.Context, _ string, _, _ map[string]string) error {
+	return nil
+}
+
+func (m *mockDockerClient) RemoveVolume(_ context.Context, _ string, _ bool) error {
+	return nil
+}
+
 func (m *mockDockerClient) ReadSecrets(_ context.Context, _ []string, _ bool) (map[string]string, error) {
 	return nil, nil //nolint:nilnil
 }
@@ -0,0 +1,292 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// Types of events emitted by the gateway.
+const (
+	EventGatewayStarted      = "gateway.started"
+	EventGatewayStopped      = "gateway.stopped"
+	EventServerCrashLoop     = "server.crash_loop"
+	EventPolicyDenied        = "policy.denied"
+	EventOAuthFailure        = "oauth.failure"
+	EventSessionConnected    = "session.connected"
+	EventSessionDisconnected = "session.disconnected"
+	EventSessionClosed       = "session.closed"
+	EventServerAdded         = "server.added"
+	EventServerRemoved       = "server.removed"
+	EventReloadCompleted     = "reload.completed"
+	EventError               = "error"
+)
+
+// DefaultEvents are the events posted to webhooks that don't list the events they want.
+var DefaultEvents = []string{EventGatewayStarted, EventGatewayStopped, EventServerCrashLoop, EventPolicyDenied, EventOAuthFailure}
+
+// Formats of the payloads posted to webhooks.
+const (
+	FormatJSON  = "json"
+	FormatSlack = "slack"
+)
+
+// slackTemplate renders an event as a Slack incoming webhook message.
+const slackTemplate = `{"text": {{json (printf "*%s* %s" .Type .Message)}}}`
+
+// Event is something that happened in the gateway.
+type Event struct {
+	Type    string            `json:"type"`
+	Time    time.Time         `json:"time"`
+	Server  string            `json:"server,omitempty"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// Config lists the webhooks to notify.
+type Config struct {
+	Webhooks []Webhook `yaml:"webhooks"`
+}
+
+// Webhook is a URL to which events are posted.
+// The URL and header values can reference environment variables, e.g. $SLACK_WEBHOOK_URL.
+type Webhook struct {
+	URL      string            `yaml:"url"`
+	Format   string            `yaml:"format,omitempty"`
+	Template string            `yaml:"template,omitempty"`
+	Events   []string          `yaml:"events,omitempty"`
+	Headers  map[string]string `yaml:"headers,omitempty"`
+}
+
+// LoadConfig reads a notifications configuration file.
+func LoadConfig(path string) (*Config, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading notifications config %s: %w", path, err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(buf, &config); err != nil {
+		return nil, fmt.Errorf("parsing notifications config %s: %w", path, err)
+	}
+
+	return &config, nil
+}
+
+type webhook struct {
+	url      string
+	headers  map[string]string
+	events   []string
+	template *template.Template
+}
+
+// Notifier posts events to webhooks, in the background and with retries.
+// A nil *Notifier drops all the events.
+type Notifier struct {
+	webhooks    []webhook
+	httpClient  *http.Client
+	maxAttempts int
+	backoff     time.Duration
+	pending     sync.WaitGroup
+}
+
+// New creates a Notifier from a configuration.
+func New(config *Config) (*Notifier, error) {
+	notifier := &Notifier{
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: 3,
+		backoff:     time.Second,
+	}
+
+	for i, w := range config.Webhooks {
+		webhookURL := os.ExpandEnv(w.URL)
+		if webhookURL == "" {
+			return nil, fmt.Errorf("webhook %d: url is required", i)
+		}
+
+		text := w.Template
+		if text == "" {
+			switch w.Format {
+			case "", FormatJSON:
+			case FormatSlack:
+				text = slackTemplate
+			default:
+				return nil, fmt.Errorf("webhook %d: unknown format %q, expected %s or %s", i, w.Format, FormatJSON, FormatSlack)
+			}
+		}
+
+		var tmpl *template.Template
+		if text != "" {
+			var err error
+			tmpl, err = template.New("webhook").Funcs(template.FuncMap{"json": toJSON}).Parse(text)
+			if err != nil {
+				return nil, fmt.Errorf("webhook %d: invalid template: %w", i, err)
+			}
+		}
+
+		events := w.Events
+		if len(events) == 0 {
+			events = DefaultEvents
+		}
+
+		headers := make(map[string]string, len(w.Headers))
+		for name, value := range w.Headers {
+			headers[name] = os.ExpandEnv(value)
+		}
+
+		notifier.webhooks = append(notifier.webhooks, webhook{
+			url:      webhookURL,
+			headers:  headers,
+			events:   events,
+			template: tmpl,
+		})
+	}
+
+	return notifier, nil
+}
+
+// Notify posts an event to the webhooks that subscribed to it. It doesn't wait for the deliveries.
+func (n *Notifier) Notify(event Event) {
+	if n == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	for _, w := range n.webhooks {
+		if !slices.Contains(w.events, event.Type) {
+			continue
+		}
+
+		payload, err := w.render(event)
+		if err != nil {
+			log.Logf("! Can't render notification %s for %s: %s", event.Type, redactURL(w.url), err)
+			continue
+		}
+
+		n.pending.Add(1)
+		go func() {
+			defer n.pending.Done()
+			if err := n.deliver(w, payload); err != nil {
+				log.Logf("! Can't deliver notification %s to %s: %s", event.Type, redactURL(w.url), err)
+			}
+		}()
+	}
+}
+
+// Close waits for the pending deliveries, at most until the context is done.
+func (n *Notifier) Close(ctx context.Context) {
+	if n == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		n.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func (w webhook) render(event Event) ([]byte, error) {
+	if w.template == nil {
+		return json.Marshal(event)
+	}
+
+	var buf bytes.Buffer
+	if err := w.template.Execute(&buf, event); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (n *Notifier) deliver(w webhook, payload []byte) error {
+	var lastErr error
+	for attempt := range n.maxAttempts {
+		if attempt > 0 {
+			time.Sleep(n.backoff << (attempt - 1))
+		}
+
+		retry, err := n.post(w, payload)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+
+	return lastErr
+}
+
+// post posts a payload once. It returns whether a failed delivery is worth retrying.
+func (n *Notifier) post(w webhook, payload []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return false, redactError(w.url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return true, redactError(w.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return true, nil
+	}
+
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("status %d", resp.StatusCode)
+}
+
+func toJSON(v any) (string, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// redactURL keeps the secrets that webhook URLs often embed in their path out of the logs.
+func redactURL(webhookURL string) string {
+	scheme, rest, found := strings.Cut(webhookURL, "://")
+	if !found {
+		return "webhook"
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return scheme + "://" + host
+}
+
+// redactError replaces the full webhook URL that net/http puts in its errors with the redacted one.
+func redactError(webhookURL string, err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	return fmt.Errorf("%s %s: %w", urlErr.Op, redactURL(webhookURL), urlErr.Err)
+}
@@ -0,0 +1,185 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receiver struct {
+	mu       sync.Mutex
+	payloads []string
+	headers  []http.Header
+}
+
+func (r *receiver) handler(status func() int) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.payloads = append(r.payloads, string(body))
+		r.headers = append(r.headers, req.Header.Clone())
+		r.mu.Unlock()
+		w.WriteHeader(status())
+	}
+}
+
+func ok() int { return http.StatusOK }
+
+func notifyAndWait(t *testing.T, notifier *Notifier, event Event) {
+	t.Helper()
+	notifier.Notify(event)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	notifier.Close(ctx)
+}
+
+func TestNotifyJSON(t *testing.T) {
+	var r receiver
+	server := httptest.NewServer(r.handler(ok))
+	defer server.Close()
+
+	t.Setenv("WEBHOOK_TOKEN", "secret")
+	notifier, err := New(&Config{Webhooks: []Webhook{{
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer $WEBHOOK_TOKEN"},
+	}}})
+	require.NoError(t, err)
+
+	notifyAndWait(t, notifier, Event{Type: EventPolicyDenied, Server: "postgres-prod", Message: "Call to query denied"})
+
+	require.Len(t, r.payloads, 1)
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(r.payloads[0]), &event))
+	assert.Equal(t, EventPolicyDenied, event.Type)
+	assert.Equal(t, "postgres-prod", event.Server)
+	assert.False(t, event.Time.IsZero())
+	assert.Equal(t, "Bearer secret", r.headers[0].Get("Authorization"))
+}
+
+func TestNotifySlack(t *testing.T) {
+	var r receiver
+	server := httptest.NewServer(r.handler(ok))
+	defer server.Close()
+
+	notifier, err := New(&Config{Webhooks: []Webhook{{URL: server.URL, Format: FormatSlack}}})
+	require.NoError(t, err)
+
+	notifyAndWait(t, notifier, Event{Type: EventGatewayStarted, Message: `Gateway "started"`})
+
+	require.Len(t, r.payloads, 1)
+	assert.JSONEq(t, `{"text": "*gateway.started* Gateway \"started\""}`, r.payloads[0])
+}
+
+func TestNotifyTemplate(t *testing.T) {
+	var r receiver
+	server := httptest.NewServer(r.handler(ok))
+	defer server.Close()
+
+	notifier, err := New(&Config{Webhooks: []Webhook{{
+		URL:      server.URL,
+		Template: `{"event": {{json .Type}}, "server": {{json .Server}}, "error": {{json .Details.error}}}`,
+	}}})
+	require.NoError(t, err)
+
+	notifyAndWait(t, notifier, Event{Type: EventOAuthFailure, Server: "github", Details: map[string]string{"error": "expired"}})
+
+	require.Len(t, r.payloads, 1)
+	assert.JSONEq(t, `{"event": "oauth.failure", "server": "github", "error": "expired"}`, r.payloads[0])
+}
+
+func TestNotifyFiltersEvents(t *testing.T) {
+	var r receiver
+	server := httptest.NewServer(r.handler(ok))
+	defer server.Close()
+
+	notifier, err := New(&Config{Webhooks: []Webhook{{URL: server.URL, Events: []string{EventServerCrashLoop}}}})
+	require.NoError(t, err)
+
+	notifyAndWait(t, notifier, Event{Type: EventGatewayStarted})
+	assert.Empty(t, r.payloads)
+
+	notifyAndWait(t, notifier, Event{Type: EventServerCrashLoop})
+	assert.Len(t, r.payloads, 1)
+}
+
+func TestNotifyRetries(t *testing.T) {
+	var r receiver
+	var calls atomic.Int32
+	server := httptest.NewServer(r.handler(func() int {
+		if calls.Add(1) < 3 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}))
+	defer server.Close()
+
+	notifier, err := New(&Config{Webhooks: []Webhook{{URL: server.URL}}})
+	require.NoError(t, err)
+	notifier.backoff = time.Millisecond
+
+	notifyAndWait(t, notifier, Event{Type: EventGatewayStopped})
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestNotifyDoesNotRetryClientErrors(t *testing.T) {
+	var r receiver
+	var calls atomic.Int32
+	server := httptest.NewServer(r.handler(func() int {
+		calls.Add(1)
+		return http.StatusBadRequest
+	}))
+	defer server.Close()
+
+	notifier, err := New(&Config{Webhooks: []Webhook{{URL: server.URL}}})
+	require.NoError(t, err)
+	notifier.backoff = time.Millisecond
+
+	notifyAndWait(t, notifier, Event{Type: EventGatewayStopped})
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestNewInvalid(t *testing.T) {
+	_, err := New(&Config{Webhooks: []Webhook{{URL: ""}}})
+	require.Error(t, err)
+
+	_, err = New(&Config{Webhooks: []Webhook{{URL: "https://example.com", Format: "xml"}}})
+	require.Error(t, err)
+
+	_, err = New(&Config{Webhooks: []Webhook{{URL: "https://example.com", Template: "{{"}}})
+	require.Error(t, err)
+}
+
+func TestNilNotifier(t *testing.T) {
+	var notifier *Notifier
+
+	notifier.Notify(Event{Type: EventGatewayStarted})
+	notifier.Close(context.Background())
+}
+
+func TestRedactURL(t *testing.T) {
+	assert.Equal(t, "https://hooks.slack.com", redactURL("https://hooks.slack.com/services/T000/B000/XXXX"))
+}
+
+func TestDeliverRedactsErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	notifier, err := New(&Config{Webhooks: []Webhook{{URL: server.URL + "/services/T000/B000/SECRET"}}})
+	require.NoError(t, err)
+	notifier.maxAttempts = 1
+
+	err = notifier.deliver(notifier.webhooks[0], []byte("{}"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), server.URL)
+	assert.NotContains(t, err.Error(), "SECRET")
+}
@@ -0,0 +1,111 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// ClientCredentialsConfig configures the client_credentials grant (RFC 6749 section 4.4)
+// used by machine-to-machine remote servers.
+type ClientCredentialsConfig struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// Tokens are cached for the lifetime of the process and shared by all the
+// connections to servers that use the same client.
+var (
+	clientCredentialsMu      sync.Mutex
+	clientCredentialsSources = map[string]oauth2.TokenSource{}
+)
+
+// ClientCredentialsTokenSource returns a token source that fetches a token with the client_credentials grant,
+// caches it and fetches a new one when it expires.
+func ClientCredentialsTokenSource(config ClientCredentialsConfig) oauth2.TokenSource {
+	key := strings.Join([]string{config.TokenURL, config.ClientID, config.ClientSecret, strings.Join(config.Scopes, " ")}, "\x00")
+
+	clientCredentialsMu.Lock()
+	defer clientCredentialsMu.Unlock()
+
+	if source, found := clientCredentialsSources[key]; found {
+		return source
+	}
+
+	source := oauth2.ReuseTokenSource(nil, &clientCredentialsSource{config: config})
+	clientCredentialsSources[key] = source
+	return source
+}
+
+type clientCredentialsSource struct {
+	config ClientCredentialsConfig
+}
+
+func (s *clientCredentialsSource) Token() (*oauth2.Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting token from %s: %w", s.config.TokenURL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading token response from %s: %w", s.config.TokenURL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("requesting token from %s: status %d: %s", s.config.TokenURL, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Scope       string `json:"scope"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("parsing token response from %s: %w", s.config.TokenURL, err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return nil, fmt.Errorf("no access token in the response from %s", s.config.TokenURL)
+	}
+
+	token := &oauth2.Token{
+		AccessToken: tokenResponse.AccessToken,
+		TokenType:   tokenResponse.TokenType,
+	}
+	if tokenResponse.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+
+	log.Logf("- Fetched client credentials token from %s (expires in %ds)", s.config.TokenURL, tokenResponse.ExpiresIn)
+	return token.WithExtra(map[string]any{"scope": tokenResponse.Scope}), nil
+}
@@ -0,0 +1,82 @@
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *int) {
+	t.Helper()
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", clientID)
+		assert.Equal(t, "s3cr3t", clientSecret)
+		assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+		assert.Equal(t, "mcp:read mcp:write", r.FormValue("scope"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, requests, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestClientCredentialsTokenSourceCachesTokens(t *testing.T) {
+	server, requests := newTokenServer(t, 3600)
+	config := ClientCredentialsConfig{
+		TokenURL:     server.URL,
+		ClientID:     "client",
+		ClientSecret: "s3cr3t",
+		Scopes:       []string{"mcp:read", "mcp:write"},
+	}
+
+	token, err := ClientCredentialsTokenSource(config).Token()
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+
+	// Another connection to a server using the same client reuses the token
+	token, err = ClientCredentialsTokenSource(config).Token()
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+	assert.Equal(t, 1, *requests)
+}
+
+func TestClientCredentialsTokenSourceRenewsExpiredTokens(t *testing.T) {
+	// Tokens that expire within the next 10 seconds are renewed
+	server, requests := newTokenServer(t, 5)
+	source := ClientCredentialsTokenSource(ClientCredentialsConfig{
+		TokenURL:     server.URL,
+		ClientID:     "client",
+		ClientSecret: "s3cr3t",
+		Scopes:       []string{"mcp:read", "mcp:write"},
+	})
+
+	_, err := source.Token()
+	require.NoError(t, err)
+	token, err := source.Token()
+	require.NoError(t, err)
+
+	assert.Equal(t, "token-2", token.AccessToken)
+	assert.Equal(t, 2, *requests)
+}
+
+func TestClientCredentialsTokenSourceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := ClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: server.URL, ClientID: "unknown"}).Token()
+	require.ErrorContains(t, err, "status 401")
+}
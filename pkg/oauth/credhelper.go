@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/docker/docker-credential-helpers/client"
@@ -36,9 +37,12 @@ func NewOAuthCredentialHelper() *CredentialHelper {
 
 // TokenStatus represents the validity status of an OAuth token
 type TokenStatus struct {
-	Valid        bool
-	ExpiresAt    time.Time
-	NeedsRefresh bool
+	Valid           bool
+	ExpiresAt       time.Time
+	NeedsRefresh    bool
+	HasRefreshToken bool
+	// Scopes granted with the token, if the token store recorded them
+	Scopes []string
 }
 
 // GetOAuthToken retrieves an OAuth token for the specified server
@@ -154,6 +158,7 @@ func (h *CredentialHelper) GetTokenStatus(ctx context.Context, serverName string
 		TokenType    string `json:"token_type"`
 		RefreshToken string `json:"refresh_token,omitempty"`
 		Expiry       string `json:"expiry,omitempty"`
+		Scope        string `json:"scope,omitempty"`
 	}
 	if err := json.Unmarshal(tokenJSON, &tokenData); err != nil {
 		return TokenStatus{Valid: false}, fmt.Errorf("failed to parse OAuth token JSON for %s: %w", serverName, err)
@@ -174,9 +179,11 @@ func (h *CredentialHelper) GetTokenStatus(ctx context.Context, serverName string
 	} else {
 		// No expiry information - assume token is valid but check immediately
 		return TokenStatus{
-			Valid:        true,
-			ExpiresAt:    time.Time{},
-			NeedsRefresh: true,
+			Valid:           true,
+			ExpiresAt:       time.Time{},
+			NeedsRefresh:    true,
+			HasRefreshToken: tokenData.RefreshToken != "",
+			Scopes:          strings.Fields(tokenData.Scope),
 		}, nil
 	}
 
@@ -190,9 +197,11 @@ func (h *CredentialHelper) GetTokenStatus(ctx context.Context, serverName string
 		serverName, expiresAt.Format(time.RFC3339), timeUntilExpiry.Round(time.Second), needsRefresh)
 
 	return TokenStatus{
-		Valid:        true,
-		ExpiresAt:    expiresAt,
-		NeedsRefresh: needsRefresh,
+		Valid:           true,
+		ExpiresAt:       expiresAt,
+		NeedsRefresh:    needsRefresh,
+		HasRefreshToken: tokenData.RefreshToken != "",
+		Scopes:          strings.Fields(tokenData.Scope),
 	}, nil
 }
 
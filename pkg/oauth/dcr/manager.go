@@ -43,11 +43,12 @@ func (m *Manager) GetDCRClient(serverName string) (Client, error) {
 func (m *Manager) PerformDiscoveryAndRegistration(ctx context.Context, serverName string, scopes string) error {
 	log.Logf("- Performing OAuth discovery and DCR for: %s", serverName)
 
-	// Get server URL from catalog
-	serverURL, err := getServerURL(ctx, serverName)
+	// Get server from catalog
+	server, err := getRemoteServer(ctx, serverName)
 	if err != nil {
 		return fmt.Errorf("getting server URL: %w", err)
 	}
+	serverURL := server.Remote.URL
 
 	// Perform OAuth discovery (RFC 9728, RFC 8414)
 	log.Logf("- Starting OAuth discovery for: %s at: %s", serverName, serverURL)
@@ -58,12 +59,8 @@ func (m *Manager) PerformDiscoveryAndRegistration(ctx context.Context, serverNam
 	}
 	log.Logf("- Discovery successful for: %s", serverName)
 
-	// Merge user-provided scopes with resource-required scopes
-	mergedScopes := mergeScopes(discovery.Scopes, scopes)
-	if len(mergedScopes) > len(discovery.Scopes) {
-		discovery.Scopes = mergedScopes
-		log.Logf("- Merged scopes for DCR registration: %v", mergedScopes)
-	}
+	discovery.Scopes = requestedScopes(server, discovery.Scopes, scopes)
+	log.Logf("- Scopes for DCR registration of %s: %v", serverName, discovery.Scopes)
 
 	// Perform Dynamic Client Registration (RFC 7591) with our redirect URI
 	creds, err := oauth.PerformDCR(ctx, discovery, serverName, m.redirectURI)
@@ -104,50 +101,33 @@ func (m *Manager) ListDCRClients() (map[string]Client, error) {
 	return m.credentials.ListClients()
 }
 
-// getServerURL retrieves the server URL from the catalog
-func getServerURL(ctx context.Context, serverName string) (string, error) {
+// getRemoteServer retrieves a remote server from the catalog
+func getRemoteServer(ctx context.Context, serverName string) (catalog.Server, error) {
 	cat, err := catalog.GetWithOptions(ctx, true, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to get catalog: %w", err)
+		return catalog.Server{}, fmt.Errorf("failed to get catalog: %w", err)
 	}
 
 	server, found := cat.Servers[serverName]
 	if !found {
-		return "", fmt.Errorf("server %s not found in catalog", serverName)
+		return catalog.Server{}, fmt.Errorf("server %s not found in catalog", serverName)
 	}
 
 	if server.Remote.URL == "" {
-		return "", fmt.Errorf("server %s is not a remote server or missing URL", serverName)
+		return catalog.Server{}, fmt.Errorf("server %s is not a remote server or missing URL", serverName)
 	}
 
-	return server.Remote.URL, nil
+	return server, nil
 }
 
-// mergeScopes combines resource-required scopes with user-provided scopes
-func mergeScopes(requiredScopes []string, userScopes string) []string {
-	if userScopes == "" || userScopes == " " {
-		return requiredScopes
-	}
-
-	userScopesList := strings.Fields(userScopes)
-	merged := make([]string, len(requiredScopes))
-	copy(merged, requiredScopes)
-
-	// Add user scopes if not already present
-	for _, userScope := range userScopesList {
-		found := false
-		for _, existingScope := range merged {
-			if existingScope == userScope {
-				found = true
-				break
-			}
-		}
-		if !found {
-			merged = append(merged, userScope)
-		}
+// requestedScopes returns the scopes to register the client with. The scopes passed by the caller are the scopes
+// configured for the server, eg. narrowed down by a profile, and override the catalog's, see catalog.Server.ConfiguredOAuthScopes.
+// Without any, the scopes discovered from the resource are used.
+func requestedScopes(server catalog.Server, discovered []string, scopes string) []string {
+	if configured := server.ConfiguredOAuthScopes(strings.Fields(scopes)); len(configured) > 0 {
+		return configured
 	}
-
-	return merged
+	return discovered
 }
 
 // logger adapter for oauth-helpers library
@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
 )
 
 func TestDCRManager_BasicOperations(t *testing.T) {
@@ -42,63 +44,14 @@ func TestDCRManager_BasicOperations(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestMergeScopes(t *testing.T) {
-	tests := []struct {
-		name           string
-		requiredScopes []string
-		userScopes     string
-		expected       []string
-	}{
-		{
-			name:           "no user scopes",
-			requiredScopes: []string{"read", "write"},
-			userScopes:     "",
-			expected:       []string{"read", "write"},
-		},
-		{
-			name:           "empty user scopes",
-			requiredScopes: []string{"read", "write"},
-			userScopes:     " ",
-			expected:       []string{"read", "write"},
-		},
-		{
-			name:           "user scopes with no overlap",
-			requiredScopes: []string{"read", "write"},
-			userScopes:     "admin delete",
-			expected:       []string{"read", "write", "admin", "delete"},
-		},
-		{
-			name:           "user scopes with partial overlap",
-			requiredScopes: []string{"read", "write"},
-			userScopes:     "write delete",
-			expected:       []string{"read", "write", "delete"},
-		},
-		{
-			name:           "user scopes with full overlap",
-			requiredScopes: []string{"read", "write"},
-			userScopes:     "read write",
-			expected:       []string{"read", "write"},
-		},
-		{
-			name:           "single user scope",
-			requiredScopes: []string{"read"},
-			userScopes:     "admin",
-			expected:       []string{"read", "admin"},
-		},
-		{
-			name:           "empty required scopes",
-			requiredScopes: []string{},
-			userScopes:     "read write",
-			expected:       []string{"read", "write"},
-		},
-	}
+func TestRequestedScopes(t *testing.T) {
+	server := catalog.Server{OAuth: &catalog.OAuth{Scopes: []string{"repo", "read:org"}}}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := mergeScopes(tt.requiredScopes, tt.userScopes)
-			assert.ElementsMatch(t, tt.expected, result)
-		})
-	}
+	// The scopes of the profile replace the catalog's and the discovered ones, they are not merged
+	assert.Equal(t, []string{"read:org"}, requestedScopes(server, []string{"admin"}, "read:org"))
+	assert.Equal(t, []string{"read:org"}, requestedScopes(catalog.Server{}, []string{"admin"}, "read:org"))
+	assert.Equal(t, []string{"repo", "read:org"}, requestedScopes(server, []string{"admin"}, ""))
+	assert.Equal(t, []string{"admin"}, requestedScopes(catalog.Server{}, []string{"admin"}, ""))
 }
 
 func TestDCRManager_RedirectURI(t *testing.T) {
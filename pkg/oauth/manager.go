@@ -170,12 +170,20 @@ func (m *Manager) ExchangeCode(ctx context.Context, code string, state string) e
 }
 
 // RevokeToken revokes an OAuth token for a server
-func (m *Manager) RevokeToken(_ context.Context, serverName string) error {
+// The token is revoked at the provider when it advertises a revocation endpoint (RFC 7009),
+// then erased from the credential store. Failing to revoke at the provider doesn't prevent the erasure.
+func (m *Manager) RevokeToken(ctx context.Context, serverName string) error {
 	dcrClient, err := m.dcrManager.GetDCRClient(serverName)
 	if err != nil {
 		return fmt.Errorf("DCR client not found for %s: %w", serverName, err)
 	}
 
+	if token, err := m.tokenStore.Retrieve(dcrClient); err == nil {
+		if err := RevokeAtProvider(ctx, dcrClient, token); err != nil {
+			log.Logf("! Failed to revoke token for %s at the provider: %v", serverName, err)
+		}
+	}
+
 	return m.tokenStore.Delete(dcrClient)
 }
 
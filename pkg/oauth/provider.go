@@ -62,9 +62,20 @@ func (p *DCRProvider) GeneratePKCE() string {
 	return oauth2.GenerateVerifier()
 }
 
+// ProviderState is a snapshot of what a running provider knows about its token
+type ProviderState struct {
+	Server           string    `json:"server"`
+	ExpiresAt        time.Time `json:"expiresAt,omitzero"`
+	LastRefreshAt    time.Time `json:"lastRefreshAt,omitzero"`
+	LastRefreshError string    `json:"lastRefreshError,omitempty"`
+	RefreshAttempts  int       `json:"refreshAttempts"`
+}
+
 // Provider manages OAuth token lifecycle for a single MCP server
 // This is used for background token refresh loops in the gateway
 type Provider struct {
+	stateMu           sync.Mutex
+	state             ProviderState
 	name              string
 	lastRefreshExpiry time.Time
 	refreshRetryCount int
@@ -73,6 +84,12 @@ type Provider struct {
 	eventChan         chan Event
 	credHelper        *CredentialHelper
 	reloadFn          func(ctx context.Context, serverName string) error
+
+	// OnRefreshError, if set, is called when a token refresh fails
+	OnRefreshError func(err error)
+	// OnTokenRefresh, if set, is called when the token was refreshed, before reloading the server.
+	// The server isn't reloaded if it returns true, when its connections could switch to the new token.
+	OnTokenRefresh func(ctx context.Context) bool
 }
 
 const maxRefreshRetries = 7 // Max attempts to refresh when expiry hasn't changed
@@ -80,6 +97,7 @@ const maxRefreshRetries = 7 // Max attempts to refresh when expiry hasn't change
 // NewProvider creates a new OAuth provider for token refresh
 func NewProvider(name string, reloadFn func(context.Context, string) error) *Provider {
 	return &Provider{
+		state:      ProviderState{Server: name},
 		name:       name,
 		stopChan:   make(chan struct{}),
 		eventChan:  make(chan Event),
@@ -102,6 +120,9 @@ func (p *Provider) Run(ctx context.Context) {
 			log.Logf("! Run 'docker mcp oauth authorize %s' if not yet authorized", p.name)
 			return
 		}
+		p.updateState(func(state *ProviderState) {
+			state.ExpiresAt = status.ExpiresAt
+		})
 
 		// Calculate wait duration and whether to trigger refresh
 		var waitDuration time.Duration
@@ -132,6 +153,9 @@ func (p *Provider) Run(ctx context.Context) {
 
 			p.lastRefreshExpiry = status.ExpiresAt
 			shouldTriggerRefresh = true
+			p.updateState(func(state *ProviderState) {
+				state.RefreshAttempts = p.refreshRetryCount
+			})
 
 		} else {
 			timeUntilExpiry := time.Until(status.ExpiresAt)
@@ -145,9 +169,11 @@ func (p *Provider) Run(ctx context.Context) {
 			if IsCEMode() {
 				// CE mode: Refresh token directly
 				go func() {
-					if err := p.refreshTokenCE(); err != nil {
+					err := p.refreshTokenCE()
+					if err != nil {
 						log.Logf("! Token refresh failed for %s: %v", p.name, err)
 					}
+					p.recordRefresh(err)
 				}()
 			} else {
 				// Desktop mode: Trigger refresh via Desktop API
@@ -156,12 +182,15 @@ func (p *Provider) Run(ctx context.Context) {
 					app, err := authClient.GetOAuthApp(context.Background(), p.name)
 					if err != nil {
 						log.Logf("! GetOAuthApp failed for %s: %v", p.name, err)
+						p.recordRefresh(err)
 						return
 					}
 					if !app.Authorized {
 						log.Logf("! GetOAuthApp returned Authorized=false for %s", p.name)
+						p.recordRefresh(fmt.Errorf("%s is not authorized", p.name))
 						return
 					}
+					p.recordRefresh(nil)
 				}()
 			}
 		}
@@ -175,12 +204,17 @@ func (p *Provider) Run(ctx context.Context) {
 			case event := <-p.eventChan:
 				timer.Stop()
 				log.Logf("- Provider %s received event: %s", p.name, event.Type)
-				if err := p.reloadFn(ctx, p.name); err != nil {
+				if event.Type == EventTokenRefresh && p.OnTokenRefresh != nil && p.OnTokenRefresh(ctx) {
+					log.Logf("- Switched %s to the refreshed token without reconnecting", p.name)
+				} else if err := p.reloadFn(ctx, p.name); err != nil {
 					log.Logf("- Failed to reload %s after %s: %v", p.name, event.Type, err)
 				}
 				if event.Type == EventLoginSuccess || event.Type == EventTokenRefresh {
 					p.refreshRetryCount = 0
 					p.lastRefreshExpiry = time.Time{}
+					p.updateState(func(state *ProviderState) {
+						state.RefreshAttempts = 0
+					})
 				}
 			case <-p.stopChan:
 				timer.Stop()
@@ -200,6 +234,36 @@ func (p *Provider) Stop() {
 	})
 }
 
+// State returns a snapshot of the provider's state
+func (p *Provider) State() ProviderState {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	return p.state
+}
+
+func (p *Provider) updateState(update func(state *ProviderState)) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+
+	update(&p.state)
+}
+
+// recordRefresh records the result of a refresh attempt
+func (p *Provider) recordRefresh(err error) {
+	p.updateState(func(state *ProviderState) {
+		state.LastRefreshAt = time.Now()
+		state.LastRefreshError = ""
+		if err != nil {
+			state.LastRefreshError = err.Error()
+		}
+	})
+
+	if err != nil && p.OnRefreshError != nil {
+		p.OnRefreshError(err)
+	}
+}
+
 // SendEvent sends an SSE event to this provider's event channel
 func (p *Provider) SendEvent(event Event) {
 	p.eventChan <- event
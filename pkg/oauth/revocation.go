@@ -0,0 +1,129 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/oauth/dcr"
+)
+
+// DiscoverRevocationEndpoint reads the RFC 7009 revocation endpoint from the authorization server metadata.
+// Returns an empty string if the authorization server doesn't advertise one.
+func DiscoverRevocationEndpoint(ctx context.Context, authorizationServer string) (string, error) {
+	issuer, err := url.Parse(authorizationServer)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization server %q: %w", authorizationServer, err)
+	}
+
+	// RFC 8414 inserts the well-known path between the host and the issuer's path.
+	// OpenID providers use the openid-configuration document instead.
+	path := strings.TrimSuffix(issuer.Path, "/")
+	candidates := []string{
+		"/.well-known/oauth-authorization-server" + path,
+		"/.well-known/openid-configuration" + path,
+		path + "/.well-known/openid-configuration",
+	}
+
+	for _, candidate := range candidates {
+		metadataURL := *issuer
+		metadataURL.Path = candidate
+
+		var metadata struct {
+			RevocationEndpoint string `json:"revocation_endpoint"`
+		}
+		if err := getJSON(ctx, metadataURL.String(), &metadata); err != nil {
+			continue
+		}
+		return metadata.RevocationEndpoint, nil
+	}
+
+	return "", nil
+}
+
+// RevokeAtProvider asks the authorization server to revoke a token (RFC 7009).
+// The refresh token is revoked first since revoking it usually revokes the access tokens too.
+// Does nothing if the authorization server doesn't advertise a revocation endpoint.
+func RevokeAtProvider(ctx context.Context, dcrClient dcr.Client, token *oauth2.Token) error {
+	if dcrClient.AuthorizationServer == "" {
+		return nil
+	}
+
+	endpoint, err := DiscoverRevocationEndpoint(ctx, dcrClient.AuthorizationServer)
+	if err != nil {
+		return err
+	}
+	if endpoint == "" {
+		log.Logf("- %s doesn't advertise a revocation endpoint", dcrClient.AuthorizationServer)
+		return nil
+	}
+
+	if token.RefreshToken != "" {
+		if err := revokeToken(ctx, endpoint, dcrClient.ClientID, token.RefreshToken, "refresh_token"); err != nil {
+			return err
+		}
+	}
+	if token.AccessToken != "" {
+		if err := revokeToken(ctx, endpoint, dcrClient.ClientID, token.AccessToken, "access_token"); err != nil {
+			return err
+		}
+	}
+
+	log.Logf("- Revoked OAuth token for %s at %s", dcrClient.ServerName, endpoint)
+	return nil
+}
+
+func revokeToken(ctx context.Context, endpoint, clientID, token, tokenTypeHint string) error {
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", tokenTypeHint)
+	// Public client: identify ourselves with the client_id (RFC 7009 section 2.1)
+	form.Set("client_id", clientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("revoking %s: %w", tokenTypeHint, err)
+	}
+	defer resp.Body.Close()
+
+	// The authorization server responds with 200 even if the token was already invalid
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("revoking %s: status %d: %s", tokenTypeHint, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+func getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
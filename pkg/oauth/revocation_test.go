@@ -0,0 +1,74 @@
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/docker/mcp-gateway/pkg/oauth/dcr"
+)
+
+func TestRevokeAtProvider(t *testing.T) {
+	var revoked []string
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("GET /.well-known/oauth-authorization-server/tenant", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":              server.URL + "/tenant",
+			"revocation_endpoint": server.URL + "/revoke",
+		})
+	})
+	mux.HandleFunc("POST /revoke", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		revoked = append(revoked, r.PostForm.Get("token_type_hint")+"="+r.PostForm.Get("token"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	dcrClient := dcr.Client{
+		ServerName:          "notion",
+		ClientID:            "client-id",
+		AuthorizationServer: server.URL + "/tenant",
+	}
+	err := RevokeAtProvider(t.Context(), dcrClient, &oauth2.Token{AccessToken: "access", RefreshToken: "refresh"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"refresh_token=refresh", "access_token=access"}, revoked)
+}
+
+func TestRevokeAtProviderWithoutRevocationEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/.well-known/oauth-authorization-server" {
+			_ = json.NewEncoder(w).Encode(map[string]string{"issuer": "https://example.com"})
+			return
+		}
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	err := RevokeAtProvider(t.Context(), dcr.Client{AuthorizationServer: server.URL}, &oauth2.Token{AccessToken: "access"})
+	require.NoError(t, err)
+}
+
+func TestRevokeAtProviderError(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("GET /.well-known/oauth-authorization-server", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"revocation_endpoint": server.URL + "/revoke"})
+	})
+	mux.HandleFunc("POST /revoke", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":"unsupported_token_type"}`, http.StatusBadRequest)
+	})
+
+	err := RevokeAtProvider(t.Context(), dcr.Client{AuthorizationServer: server.URL}, &oauth2.Token{AccessToken: "access"})
+	require.ErrorContains(t, err, "revoking access_token: status 400")
+}
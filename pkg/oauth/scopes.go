@@ -0,0 +1,43 @@
+package oauth
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// ExtraScopes returns the granted scopes that are not configured.
+func ExtraScopes(granted, configured []string) []string {
+	var extra []string
+	for _, scope := range granted {
+		if !slices.Contains(configured, scope) {
+			extra = append(extra, scope)
+		}
+	}
+	return extra
+}
+
+// WarnOnBroaderScopes logs a warning when the stored token of a server carries
+// broader scopes than configured. Tokens that don't record their scopes are not checked.
+// The profile, if any, is the one the scopes are configured in.
+func WarnOnBroaderScopes(ctx context.Context, serverName string, configured []string, profile string) {
+	if len(configured) == 0 {
+		return
+	}
+
+	status, err := NewOAuthCredentialHelper().GetTokenStatus(ctx, serverName)
+	if err != nil || len(status.Scopes) == 0 {
+		return
+	}
+
+	if extra := ExtraScopes(status.Scopes, configured); len(extra) > 0 {
+		log.Logf("! The OAuth token for %s carries scopes that are not configured: %s", serverName, strings.Join(extra, " "))
+		authorize := "docker mcp oauth authorize " + serverName
+		if profile != "" {
+			authorize += " --profile " + profile
+		}
+		log.Logf("! Run 'docker mcp oauth revoke %s' then '%s' to get a token with only: %s", serverName, authorize, strings.Join(configured, " "))
+	}
+}
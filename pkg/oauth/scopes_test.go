@@ -0,0 +1,41 @@
+package oauth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/docker/mcp-gateway/pkg/oauth/dcr"
+)
+
+func TestExtraScopes(t *testing.T) {
+	assert.Equal(t, []string{"admin"}, ExtraScopes([]string{"read", "admin"}, []string{"read", "write"}))
+	assert.Empty(t, ExtraScopes([]string{"read"}, []string{"read", "write"}))
+}
+
+func TestTokenStoreKeepsGrantedScopes(t *testing.T) {
+	helper := newFakeCredentialHelper()
+	store := NewTokenStore(helper)
+	dcrClient := dcr.Client{ServerName: "notion", ProviderName: "notion", AuthorizationEndpoint: "https://example.com/authorize"}
+
+	token := (&oauth2.Token{AccessToken: "access"}).WithExtra(map[string]any{"scope": "read write"})
+	require.NoError(t, store.Save(dcrClient, token))
+
+	_, secret, err := helper.Get("https://example.com/authorize/notion")
+	require.NoError(t, err)
+	decoded, err := base64.StdEncoding.DecodeString(secret)
+	require.NoError(t, err)
+
+	var stored map[string]any
+	require.NoError(t, json.Unmarshal(decoded, &stored))
+	assert.Equal(t, "access", stored["access_token"])
+	assert.Equal(t, "read write", stored["scope"])
+
+	retrieved, err := store.Retrieve(dcrClient)
+	require.NoError(t, err)
+	assert.Equal(t, "access", retrieved.AccessToken)
+}
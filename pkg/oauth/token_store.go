@@ -17,6 +17,12 @@ type TokenStore struct {
 	credentialHelper credentials.Helper
 }
 
+// storedToken is the JSON stored in the credential helper
+type storedToken struct {
+	*oauth2.Token
+	Scope string `json:"scope,omitempty"`
+}
+
 // NewTokenStore creates a new token store
 func NewTokenStore(credentialHelper credentials.Helper) *TokenStore {
 	return &TokenStore{
@@ -27,8 +33,9 @@ func NewTokenStore(credentialHelper credentials.Helper) *TokenStore {
 // Save stores an OAuth token in the credential helper
 // Key format: {authorizationEndpoint}/{providerName}
 func (t *TokenStore) Save(dcrClient dcr.Client, token *oauth2.Token) error {
-	// Marshal token to JSON
-	tokenJSON, err := json.Marshal(token)
+	// Marshal token to JSON, keeping the granted scopes that oauth2.Token doesn't serialize
+	scope, _ := token.Extra("scope").(string)
+	tokenJSON, err := json.Marshal(storedToken{Token: token, Scope: scope})
 	if err != nil {
 		return fmt.Errorf("marshalling token: %w", err)
 	}
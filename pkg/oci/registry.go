@@ -0,0 +1,95 @@
+package oci
+
+import (
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// ServerDetailFromRegistry converts a server returned by the MCP Registry API to a ServerDetail,
+// so that it can be converted to a catalog.Server like the servers read from OCI artifacts.
+func ServerDetailFromRegistry(server v0.ServerJSON) ServerDetail {
+	detail := ServerDetail{
+		Name:        server.Name,
+		Description: server.Description,
+		Version:     server.Version,
+		Repository: Repository{
+			URL:    server.Repository.URL,
+			Source: server.Repository.Source,
+			ID:     server.Repository.ID,
+		},
+	}
+
+	for _, pkg := range server.Packages {
+		detail.Packages = append(detail.Packages, Package{
+			RegistryType:     pkg.RegistryType,
+			Identifier:       pkg.Identifier,
+			Version:          pkg.Version,
+			RegistryBaseURL:  pkg.RegistryBaseURL,
+			Env:              keyValueInputsFromRegistry(pkg.EnvironmentVariables),
+			RuntimeOptions:   argumentsFromRegistry(pkg.RuntimeArguments),
+			PackageArguments: argumentsFromRegistry(pkg.PackageArguments),
+		})
+	}
+
+	for _, remote := range server.Remotes {
+		detail.Remotes = append(detail.Remotes, Remote{
+			URL:           remote.URL,
+			TransportType: remote.Type,
+			Headers:       keyValueInputsFromRegistry(remote.Headers),
+		})
+	}
+
+	return detail
+}
+
+func keyValueInputsFromRegistry(inputs []model.KeyValueInput) []KeyValueInput {
+	var converted []KeyValueInput
+	for _, input := range inputs {
+		converted = append(converted, KeyValueInput{
+			InputWithVariables: inputWithVariablesFromRegistry(input.InputWithVariables),
+			Name:               input.Name,
+		})
+	}
+	return converted
+}
+
+func argumentsFromRegistry(arguments []model.Argument) []Argument {
+	var converted []Argument
+	for _, argument := range arguments {
+		converted = append(converted, Argument{
+			InputWithVariables: inputWithVariablesFromRegistry(argument.InputWithVariables),
+			Type:               string(argument.Type),
+			ValueHint:          argument.ValueHint,
+			IsRepeated:         argument.IsRepeated,
+			Name:               argument.Name,
+		})
+	}
+	return converted
+}
+
+func inputWithVariablesFromRegistry(input model.InputWithVariables) InputWithVariables {
+	var variables map[string]Input
+	if len(input.Variables) > 0 {
+		variables = make(map[string]Input, len(input.Variables))
+		for name, variable := range input.Variables {
+			variables[name] = inputFromRegistry(variable)
+		}
+	}
+
+	return InputWithVariables{
+		Input:     inputFromRegistry(input.Input),
+		Variables: variables,
+	}
+}
+
+func inputFromRegistry(input model.Input) Input {
+	return Input{
+		Description:  input.Description,
+		Value:        input.Value,
+		Required:     input.IsRequired,
+		Secret:       input.IsSecret,
+		DefaultValue: input.Default,
+		Choices:      input.Choices,
+		Format:       string(input.Format),
+	}
+}
@@ -0,0 +1,81 @@
+package oci
+
+import (
+	"testing"
+
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestServerDetailFromRegistryNPM(t *testing.T) {
+	server := v0.ServerJSON{
+		Name:        "io.github.example/weather",
+		Description: "Weather forecasts",
+		Version:     "1.0.2",
+		Packages: []model.Package{
+			{
+				RegistryType: "npm",
+				Identifier:   "@example/weather-mcp",
+				Version:      "1.0.2",
+				EnvironmentVariables: []model.KeyValueInput{
+					{
+						Name:               "WEATHER_API_KEY",
+						InputWithVariables: model.InputWithVariables{Input: model.Input{IsSecret: true}},
+					},
+					{
+						Name:               "UNITS",
+						InputWithVariables: model.InputWithVariables{Input: model.Input{Default: "metric"}},
+					},
+				},
+				PackageArguments: []model.Argument{
+					{Type: model.ArgumentTypePositional, InputWithVariables: model.InputWithVariables{Input: model.Input{Value: "stdio"}}},
+				},
+			},
+		},
+	}
+
+	detail := ServerDetailFromRegistry(server)
+	catalogServer := detail.ToCatalogServer()
+
+	assert.Equal(t, "io.github.example/weather", catalogServer.Name)
+	assert.Equal(t, "Weather forecasts", catalogServer.Description)
+	assert.Equal(t, "command", catalogServer.Type)
+	assert.Empty(t, catalogServer.Image)
+	assert.Equal(t, &catalog.Package{RegistryType: "npm", Identifier: "@example/weather-mcp", Version: "1.0.2"}, catalogServer.Package)
+	assert.Equal(t, []string{"stdio"}, catalogServer.Command)
+	assert.Equal(t, []catalog.Secret{{Name: "io_github_example/weather.WEATHER_API_KEY", Env: "WEATHER_API_KEY"}}, catalogServer.Secrets)
+	assert.Equal(t, []catalog.Env{{Name: "UNITS", Value: "metric"}}, catalogServer.Env)
+}
+
+func TestServerDetailFromRegistryOCI(t *testing.T) {
+	server := v0.ServerJSON{
+		Name: "io.github.example/fetch",
+		Packages: []model.Package{
+			{RegistryType: "oci", Identifier: "docker.io/example/fetch:1.0"},
+		},
+	}
+
+	detail := ServerDetailFromRegistry(server)
+	catalogServer := detail.ToCatalogServer()
+
+	assert.Equal(t, "docker.io/example/fetch:1.0", catalogServer.Image)
+	assert.Nil(t, catalogServer.Package)
+}
+
+func TestServerDetailFromRegistryRemote(t *testing.T) {
+	server := v0.ServerJSON{
+		Name: "io.github.example/remote",
+		Remotes: []model.Transport{
+			{Type: "streamable-http", URL: "https://example.com/mcp"},
+		},
+	}
+
+	detail := ServerDetailFromRegistry(server)
+	catalogServer := detail.ToCatalogServer()
+
+	assert.Equal(t, "https://example.com/mcp", catalogServer.Remote.URL)
+	assert.Equal(t, "streamable-http", catalogServer.Remote.Transport)
+}
@@ -3,6 +3,7 @@ package oci
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
@@ -104,68 +105,85 @@ func (sd *ServerDetail) ToCatalogServer() catalog.Server {
 	// Extract image from the first package if available
 	if len(sd.Packages) > 0 {
 		pkg := sd.Packages[0]
-		server.Image = fmt.Sprintf("%s:%s", pkg.Identifier, pkg.Version)
+		switch pkg.RegistryType {
+		case catalog.PackageTypeNPM, catalog.PackageTypePyPI:
+			// Run the package in a sandbox image
+			server.Type = "command"
+			server.Package = &catalog.Package{
+				RegistryType: pkg.RegistryType,
+				Identifier:   pkg.Identifier,
+				Version:      pkg.Version,
+			}
+		default:
+			server.Image = pkg.Identifier
+			// The MCP Registry puts the version of OCI packages in the identifier
+			if pkg.Version != "" {
+				server.Image = fmt.Sprintf("%s:%s", pkg.Identifier, pkg.Version)
+			}
+		}
+
+		serverName := CanonicalizeServerName(sd.Name)
 
 		// Convert environment variables to secrets, env vars, and config schemas
 		for _, envVar := range pkg.Env {
-			value, secrets, config := getKeyValueInput(envVar, CanonicalizeServerName(sd.Name), false)
-			if len(secrets) > 0 {
+			value, secrets, config := getKeyValueInput(envVar, serverName, true)
+			server.Secrets = append(server.Secrets, secrets...)
+			server.Config = mergeConfig(server.Config, serverName, config)
+			if envVar.Secret && len(envVar.Variables) == 0 {
 				// don't need explicit env because secret adds it
-				server.Secrets = append(server.Secrets, secrets...)
-			} else {
-				server.Env = append(server.Env, catalog.Env{
-					Name:  envVar.Name,
-					Value: value,
-				})
-				server.Config = mergeConfig(server.Config, CanonicalizeServerName(sd.Name), config)
+				continue
 			}
+			server.Env = append(server.Env, catalog.Env{
+				Name:  envVar.Name,
+				Value: value,
+			})
 		}
 
-		// Process package arguments and append positional ones to command
-		for _, arg := range pkg.PackageArguments {
-			switch arg.Type {
-			case "positional":
-				value, secrets, configSchema := getInput(arg.InputWithVariables, CanonicalizeServerName(sd.Name))
-				server.Command = append(server.Command, value)
-
-				// Add any secrets from the argument
-				if len(secrets) > 0 {
-					server.Secrets = append(server.Secrets, secrets...)
-				}
-
-				// Add any config schema from the argument
-				if configSchema != nil {
-					server.Config = mergeConfig(server.Config, CanonicalizeServerName(sd.Name), configSchema)
-				}
-			case "named":
-				value, secrets, configSchema := getInput(arg.InputWithVariables, CanonicalizeServerName(sd.Name))
-				server.Command = append(server.Command, fmt.Sprintf("--%s", arg.Name), value)
-
-				// Add any secrets from the argument
-				if len(secrets) > 0 {
-					server.Secrets = append(server.Secrets, secrets...)
-				}
-
-				// Add any config schema from the argument
-				if configSchema != nil {
-					server.Config = mergeConfig(server.Config, CanonicalizeServerName(sd.Name), configSchema)
-				}
-			}
+		// Process package arguments and append them to the command
+		for i, arg := range pkg.PackageArguments {
+			args, secrets, config := getArgument(arg, i, serverName)
+			server.Command = append(server.Command, args...)
+			server.Secrets = append(server.Secrets, secrets...)
+			server.Config = mergeConfig(server.Config, serverName, config)
 		}
 
 		// Process runtime arguments
 		for _, arg := range pkg.RuntimeOptions {
-			// volume arguments have special meaning
-			if arg.Type == "named" && (arg.Name == "-v" || arg.Name == "--mount") {
-				config, volume := createVolume(arg, CanonicalizeServerName(sd.Name))
+			if arg.Type != "named" {
+				continue
+			}
+			switch arg.Name {
+			case "-v", "--mount":
+				// volume arguments have special meaning
+				config, volume := createVolume(arg, serverName)
 				if volume != "" {
 					server.Volumes = append(server.Volumes, volume)
 				}
-				if config != nil {
-					server.Config = mergeConfig(server.Config, CanonicalizeServerName(sd.Name), config)
+				server.Config = mergeConfig(server.Config, serverName, config)
+			case "-e", "--env":
+				name, _, _ := strings.Cut(arg.Value, "=")
+				if name == "" {
+					continue
+				}
+				value, secrets, config := getInput(arg.InputWithVariables, name, serverName)
+				if !arg.Secret {
+					_, value, _ = strings.Cut(value, "=")
+				}
+				server.Env = append(server.Env, catalog.Env{
+					Name:  name,
+					Value: value,
+				})
+				server.Secrets = append(server.Secrets, secrets...)
+				server.Config = mergeConfig(server.Config, serverName, config)
+			case "-u", "--user":
+				value, _, config := getInput(arg.InputWithVariables, "user", serverName)
+				server.User = value
+				server.Config = mergeConfig(server.Config, serverName, config)
+			case "--network":
+				if arg.Value == "none" {
+					server.DisableNetwork = true
 				}
 			}
-			// TODO support User args explicitly
 		}
 	}
 
@@ -215,69 +233,103 @@ func getKeyValueInput(kvi KeyValueInput, serverName string, useEnvForm bool) (st
 			// Use default value when no explicit value is provided
 			return fmt.Sprintf("%v", kvi.DefaultValue), []catalog.Secret{}, map[string]any{}
 		}
-		config := map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				kvi.Name: map[string]any{
-					"type": "string",
-				},
-			},
-		}
+		config := inputConfig(kvi.Name, kvi.Input)
 		return fmt.Sprintf("{{%s}}", fmt.Sprintf("%s.%s", serverName, kvi.Name)), []catalog.Secret{}, config
 	}
-	value, secrets, config := getInput(kvi.InputWithVariables, serverName)
+	value, secrets, config := getInput(kvi.InputWithVariables, kvi.Name, serverName)
 	return value, secrets, config
 }
 
-func getInput(arg InputWithVariables, serverName string) (string, []catalog.Secret, map[string]any) {
+// getArgument converts a package argument to command line arguments. An argument without a value
+// is read from a config property, except for a named argument that's only a flag.
+func getArgument(arg Argument, index int, serverName string) ([]string, []catalog.Secret, map[string]any) {
+	var name string
+	switch {
+	case arg.Type == "named":
+		name = strings.TrimLeft(arg.Name, "-")
+	case arg.ValueHint != "":
+		name = arg.ValueHint
+	default:
+		name = fmt.Sprintf("arg%d", index)
+	}
+
+	value, secrets, config := getInput(arg.InputWithVariables, name, serverName)
+	if value == "" && !arg.Secret {
+		if arg.Type == "named" && !arg.Required && arg.ValueHint == "" {
+			return []string{arg.Name}, nil, nil
+		}
+		value = fmt.Sprintf("{{%s.%s}}", serverName, name)
+		config = inputConfig(name, arg.Input)
+	}
+
+	if arg.Type != "named" {
+		return []string{value}, secrets, config
+	}
+
+	flag := arg.Name
+	if !strings.HasPrefix(flag, "-") {
+		flag = "--" + flag
+	}
+	return []string{flag, value}, secrets, config
+}
+
+// inputConfig creates a config schema with a single property for an input.
+func inputConfig(name string, input Input) map[string]any {
+	config := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			name: inputProperty(input),
+		},
+	}
+	if input.Required {
+		config["required"] = []string{name}
+	}
+	return config
+}
+
+// inputProperty creates the config schema property of an input.
+func inputProperty(input Input) map[string]any {
+	prop := map[string]any{
+		"type": "string", // Default to string
+	}
+	if input.Description != "" {
+		prop["description"] = input.Description
+	}
+	if input.DefaultValue != "" {
+		prop["default"] = input.DefaultValue
+	}
+	if input.Format != "" {
+		prop["format"] = input.Format
+	}
+	if len(input.Choices) > 0 {
+		prop["enum"] = input.Choices
+	}
+	return prop
+}
+
+// getInput converts an input to a value. Secrets are referenced as ${ENV}, which is expanded from
+// the secrets when the server starts, and other variables become config properties.
+func getInput(arg InputWithVariables, name string, serverName string) (string, []catalog.Secret, map[string]any) {
 	var secrets []catalog.Secret
 	var configSchema map[string]any
 
 	// Process the main input
 	value := arg.Value
 	if arg.Secret {
-		// TODO - this is bad practice (visible to docker inspect)
 		secret := catalog.Secret{
-			Name: fmt.Sprintf("%s.%s", serverName, arg.Description),
-			Env:  canonicalizeEnvName(arg.Description),
+			Name: fmt.Sprintf("%s.%s", serverName, name),
+			Env:  canonicalizeEnvName(name),
 		}
 		secrets = append(secrets, secret)
 
-		// Replace with template reference
-		value = fmt.Sprintf("{{%s}}", arg.Description)
+		// Replace with a reference to the secret's env var
+		value = fmt.Sprintf("${%s}", secret.Env)
 	} else if len(arg.Variables) > 0 {
 		// This input has variables, create config schema
 		properties := make(map[string]any)
 		required := []string{}
 
 		for varName, variable := range arg.Variables {
-			prop := map[string]any{
-				"type":        "string", // Default to string
-				"description": variable.Description,
-			}
-
-			// Add default value if present
-			if variable.DefaultValue != "" {
-				prop["default"] = variable.DefaultValue
-			}
-
-			// Add format if specified
-			if variable.Format != "" {
-				prop["format"] = variable.Format
-			}
-
-			// Add choices as enum if present
-			if len(variable.Choices) > 0 {
-				prop["enum"] = variable.Choices
-			}
-
-			properties[varName] = prop
-
-			// Add to required if the variable is required
-			if variable.Required {
-				required = append(required, varName)
-			}
-
 			// Handle secret variables
 			if variable.Secret {
 				secret := catalog.Secret{
@@ -287,9 +339,18 @@ func getInput(arg InputWithVariables, serverName string) (string, []catalog.Secr
 				secrets = append(secrets, secret)
 
 				// Replace in value string using secret reference
-				value = replaceVariables(value, serverName)
+				value = strings.ReplaceAll(value, fmt.Sprintf("{%s}", varName), fmt.Sprintf("${%s}", secret.Env))
+				continue
+			}
+
+			properties[varName] = inputProperty(variable)
+
+			// Add to required if the variable is required
+			if variable.Required {
+				required = append(required, varName)
 			}
 		}
+		slices.Sort(required)
 
 		// Create the config schema
 		configSchema = map[string]any{
@@ -365,38 +426,36 @@ func createVolume(arg Argument, serverName string) (map[string]any, string) {
 // mergeConfig merges a new config into the existing config slice, creating a top-level
 // schema object with name, type "object", and properties fields
 func mergeConfig(existingConfig []any, serverName string, newConfig map[string]any) []any {
-	// If there's no existing config, create a new schema object
-	if len(existingConfig) == 0 {
-		schemaObject := map[string]any{
-			"name":       serverName,
-			"type":       "object",
-			"properties": map[string]any{},
-		}
-		// Merge new properties if they exist
-		if newProperties, hasProps := newConfig["properties"].(map[string]any); hasProps {
-			schemaObject["properties"] = newProperties
-		}
-		return []any{schemaObject}
+	newProperties, _ := newConfig["properties"].(map[string]any)
+	if len(newProperties) == 0 {
+		return existingConfig
 	}
+	newRequired, _ := newConfig["required"].([]string)
 
 	// Find existing server config object by name
 	for _, configItem := range existingConfig {
 		if configMap, ok := configItem.(map[string]any); ok {
 			if name, hasName := configMap["name"].(string); hasName && name == serverName {
 				// Merge properties into the existing server config
-				if properties, hasProps := configMap["properties"].(map[string]any); hasProps {
-					if newProperties, newHasProps := newConfig["properties"].(map[string]any); newHasProps {
-						// Merge new properties into existing properties
-						for key, value := range newProperties {
-							properties[key] = value
-						}
-					}
-				} else {
-					// Initialize properties if they don't exist
-					if newProperties, newHasProps := newConfig["properties"].(map[string]any); newHasProps {
-						configMap["properties"] = newProperties
+				properties, hasProps := configMap["properties"].(map[string]any)
+				if !hasProps {
+					properties = map[string]any{}
+					configMap["properties"] = properties
+				}
+				for key, value := range newProperties {
+					properties[key] = value
+				}
+
+				// Merge the required properties
+				required, _ := configMap["required"].([]string)
+				for _, key := range newRequired {
+					if !slices.Contains(required, key) {
+						required = append(required, key)
 					}
 				}
+				if len(required) > 0 {
+					configMap["required"] = required
+				}
 				return existingConfig
 			}
 		}
@@ -406,10 +465,10 @@ func mergeConfig(existingConfig []any, serverName string, newConfig map[string]a
 	schemaObject := map[string]any{
 		"name":       serverName,
 		"type":       "object",
-		"properties": map[string]any{},
+		"properties": newProperties,
 	}
-	if newProperties, hasProps := newConfig["properties"].(map[string]any); hasProps {
-		schemaObject["properties"] = newProperties
+	if len(newRequired) > 0 {
+		schemaObject["required"] = slices.Clone(newRequired)
 	}
 	return append(existingConfig, schemaObject)
 }
@@ -449,8 +508,12 @@ func parseVolumeMount(value string) string {
 // replaceVariables replaces {X} patterns with {{serverName.X}} in the input string
 func replaceVariables(input, serverName string) string {
 	// Use regex to find all {X} patterns and replace with {{serverName.X}}
-	re := regexp.MustCompile(`\{([^}]+)\}`)
+	// ${X} patterns are references to secrets and are kept as-is
+	re := regexp.MustCompile(`\$?\{([^}]+)\}`)
 	return re.ReplaceAllStringFunc(input, func(match string) string {
+		if strings.HasPrefix(match, "$") {
+			return match
+		}
 		// Extract the variable name (remove the braces)
 		varName := match[1 : len(match)-1]
 		return fmt.Sprintf("{{%s.%s}}", serverName, varName)
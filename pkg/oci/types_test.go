@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/docker/mcp-gateway/pkg/catalog"
 )
 
@@ -568,3 +570,75 @@ func TestRemoteServerConversion(t *testing.T) {
 		}
 	}
 }
+
+func TestConversionOfEnvAndArguments(t *testing.T) {
+	serverDetail := ServerDetail{
+		Name: "io.github.example/db",
+		Packages: []Package{
+			{
+				RegistryType: "oci",
+				Identifier:   "example/db",
+				Version:      "1.0",
+				Env: []KeyValueInput{
+					{
+						Name: "DB_HOST",
+						InputWithVariables: InputWithVariables{
+							Input: Input{Description: "Database host", Required: true},
+						},
+					},
+					{
+						Name: "DB_URL",
+						InputWithVariables: InputWithVariables{
+							Input: Input{Value: "postgres://{user}:{password}@{host}/db"},
+							Variables: map[string]Input{
+								"user":     {Description: "User", Required: true},
+								"password": {Secret: true},
+								"host":     {DefaultValue: "localhost"},
+							},
+						},
+					},
+				},
+				RuntimeOptions: []Argument{
+					{Type: "named", Name: "-e", InputWithVariables: InputWithVariables{Input: Input{Value: "MODE={mode}"}, Variables: map[string]Input{"mode": {Choices: []string{"ro", "rw"}}}}},
+					{Type: "named", Name: "--user", InputWithVariables: InputWithVariables{Input: Input{Value: "1000:1000"}}},
+					{Type: "named", Name: "--network", InputWithVariables: InputWithVariables{Input: Input{Value: "none"}}},
+				},
+				PackageArguments: []Argument{
+					{Type: "named", Name: "--verbose"},
+					{Type: "named", Name: "port", InputWithVariables: InputWithVariables{Input: Input{Value: "5432"}}},
+					{Type: "named", Name: "--token", InputWithVariables: InputWithVariables{Input: Input{Secret: true}}},
+					{Type: "positional", ValueHint: "database", InputWithVariables: InputWithVariables{Input: Input{Required: true}}},
+				},
+			},
+		},
+	}
+
+	server := serverDetail.ToCatalogServer()
+
+	assert.Equal(t, []catalog.Env{
+		{Name: "DB_HOST", Value: "{{io_github_example/db.DB_HOST}}"},
+		{Name: "DB_URL", Value: "postgres://{{io_github_example/db.user}}:${password}@{{io_github_example/db.host}}/db"},
+		{Name: "MODE", Value: "{{io_github_example/db.mode}}"},
+	}, server.Env)
+	assert.Equal(t, []catalog.Secret{
+		{Name: "io_github_example/db.password", Env: "password"},
+		{Name: "io_github_example/db.token", Env: "token"},
+	}, server.Secrets)
+	assert.Equal(t, []string{"--verbose", "--port", "5432", "--token", "${token}", "{{io_github_example/db.database}}"}, server.Command)
+	assert.Equal(t, "1000:1000", server.User)
+	assert.True(t, server.DisableNetwork)
+	assert.Equal(t, []any{
+		map[string]any{
+			"name": "io_github_example/db",
+			"type": "object",
+			"properties": map[string]any{
+				"DB_HOST":  map[string]any{"type": "string", "description": "Database host"},
+				"user":     map[string]any{"type": "string", "description": "User"},
+				"host":     map[string]any{"type": "string", "default": "localhost"},
+				"mode":     map[string]any{"type": "string", "enum": []string{"ro", "rw"}},
+				"database": map[string]any{"type": "string"},
+			},
+			"required": []string{"DB_HOST", "user", "database"},
+		},
+	}, server.Config)
+}
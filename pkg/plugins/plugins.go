@@ -0,0 +1,76 @@
+// Package plugins is the extension point for MCP servers compiled in the gateway.
+//
+// A plugin implements Server and registers itself from the init function of its package,
+// like database/sql drivers do:
+//
+//	func init() {
+//		plugins.Register("acme-tickets", ticketsServer{})
+//	}
+//
+// Once the package is imported by a build of the gateway, the server is listed in the catalog
+// with the plugin type, and it's enabled, configured and given secrets like the other servers.
+// It runs in the gateway's process, without a container.
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// Server is an MCP server that runs in the gateway's process.
+type Server interface {
+	// Definition describes the server like an entry of the catalog: its description, config and secrets.
+	Definition() catalog.Server
+	// NewServer returns a new MCP server, with the config of the server and the values of its secrets, by secret name.
+	// It's called each time the gateway would start a container for the server.
+	NewServer(ctx context.Context, config map[string]any, secrets map[string]string) (*mcp.Server, error)
+}
+
+var (
+	serversMu sync.RWMutex
+	servers   = map[string]Server{}
+)
+
+// Register makes a server available under a name. It panics if the name is already registered.
+func Register(name string, server Server) {
+	serversMu.Lock()
+	defer serversMu.Unlock()
+
+	if server == nil {
+		panic("plugins: Register server is nil")
+	}
+	if _, exists := servers[name]; exists {
+		panic(fmt.Sprintf("plugins: Register called twice for server %s", name))
+	}
+	servers[name] = server
+
+	catalog.RegisterPluginServer(name, server.Definition())
+}
+
+// Lookup returns the server registered under a name.
+func Lookup(name string) (Server, bool) {
+	serversMu.RLock()
+	defer serversMu.RUnlock()
+
+	server, found := servers[name]
+	return server, found
+}
+
+// Names returns the sorted names of the registered servers.
+func Names() []string {
+	serversMu.RLock()
+	defer serversMu.RUnlock()
+
+	var names []string
+	for name := range servers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
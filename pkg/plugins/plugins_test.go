@@ -0,0 +1,43 @@
+package plugins
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+type echoServer struct{}
+
+func (echoServer) Definition() catalog.Server {
+	return catalog.Server{Description: "Echoes its arguments"}
+}
+
+func (echoServer) NewServer(context.Context, map[string]any, map[string]string) (*mcp.Server, error) {
+	return mcp.NewServer(&mcp.Implementation{Name: "echo"}, nil), nil
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-echo", echoServer{})
+
+	server, found := Lookup("test-echo")
+	require.True(t, found)
+	assert.Equal(t, echoServer{}, server)
+	assert.Contains(t, Names(), "test-echo")
+
+	// The server is listed in the catalogs, unless they define a server of the same name
+	mcpCatalog := catalog.WithPluginServers(catalog.Catalog{})
+	assert.Equal(t, catalog.Server{Type: catalog.TypePlugin, Description: "Echoes its arguments"}, mcpCatalog.Servers["test-echo"])
+	mcpCatalog = catalog.WithPluginServers(catalog.Catalog{Servers: map[string]catalog.Server{"test-echo": {Image: "acme/echo"}}})
+	assert.Equal(t, "acme/echo", mcpCatalog.Servers["test-echo"].Image)
+
+	assert.Panics(t, func() { Register("test-echo", echoServer{}) })
+	assert.Panics(t, func() { Register("test-nil", nil) })
+
+	_, found = Lookup("test-unknown")
+	assert.False(t, found)
+}
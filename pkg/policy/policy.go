@@ -0,0 +1,336 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy restricts when, and by whom, the tools of servers can be called.
+type Policy struct {
+	Servers map[string]ServerPolicy `yaml:"servers" json:"servers"`
+	// Profiles map the authenticated clients to the profile whose servers they can use. The first matching rule wins.
+	Profiles []ProfileRule `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+	// DefaultProfile is the profile of the authenticated clients that match no rule. Without it, they can use no server.
+	DefaultProfile string `yaml:"defaultProfile,omitempty" json:"defaultProfile,omitempty"`
+}
+
+// ProfileRule maps the authenticated clients that match some users or groups to a profile.
+type ProfileRule struct {
+	Users   []string `yaml:"users,omitempty" json:"users,omitempty"`
+	Groups  []string `yaml:"groups,omitempty" json:"groups,omitempty"`
+	Profile string   `yaml:"profile" json:"profile"`
+}
+
+// ServerPolicy restricts when, and by whom, the tools of a server can be called.
+type ServerPolicy struct {
+	// AccessWindows are the windows during which the server can be called. No windows means always.
+	AccessWindows []TimeWindow `yaml:"accessWindows,omitempty" json:"accessWindows,omitempty"`
+	// Timezone of the access windows (IANA name). Defaults to the gateway's local time.
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+	// AllowOverride lets an on-call override grant access outside of the access windows.
+	AllowOverride bool `yaml:"allowOverride,omitempty" json:"allowOverride,omitempty"`
+	// AllowedUsers are the subjects or emails of the authenticated clients that can call the server.
+	AllowedUsers []string `yaml:"allowedUsers,omitempty" json:"allowedUsers,omitempty"`
+	// AllowedGroups are the groups of the authenticated clients that can call the server.
+	// No allowed users and groups means any client.
+	AllowedGroups []string `yaml:"allowedGroups,omitempty" json:"allowedGroups,omitempty"`
+}
+
+// Identity is a client authenticated by an identity provider.
+type Identity struct {
+	Subject string
+	Email   string
+	Groups  []string
+}
+
+func (i *Identity) String() string {
+	if i.Email != "" {
+		return i.Email
+	}
+	return i.Subject
+}
+
+// matches reports whether the identity is one of the users, by subject or email, or in one of the groups.
+func (i *Identity) matches(users, groups []string) bool {
+	for _, user := range users {
+		if user == i.Subject || (i.Email != "" && strings.EqualFold(user, i.Email)) {
+			return true
+		}
+	}
+	for _, group := range groups {
+		if slices.Contains(i.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeWindow is a daily window of time, on some days of the week.
+// A window that ends before it starts spans midnight.
+type TimeWindow struct {
+	Days []string `yaml:"days,omitempty" json:"days,omitempty"`
+	From string   `yaml:"from" json:"from"`
+	To   string   `yaml:"to" json:"to"`
+}
+
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Load reads and validates a policy file.
+func Load(path string) (*Policy, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy %s: %w", path, err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(buf, &policy); err != nil {
+		return nil, fmt.Errorf("parsing policy %s: %w", path, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+
+	return &policy, nil
+}
+
+// Validate checks the time windows, time zones and profile rules of the policy.
+func (p *Policy) Validate() error {
+	for i, rule := range p.Profiles {
+		if rule.Profile == "" {
+			return fmt.Errorf("profile rule %d: profile is required", i+1)
+		}
+		if len(rule.Users) == 0 && len(rule.Groups) == 0 {
+			return fmt.Errorf("profile rule %d: users or groups are required", i+1)
+		}
+	}
+
+	for serverName, server := range p.Servers {
+		if _, err := server.location(); err != nil {
+			return fmt.Errorf("server %s: %w", serverName, err)
+		}
+		for _, window := range server.AccessWindows {
+			if err := window.validate(); err != nil {
+				return fmt.Errorf("server %s: %w", serverName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeniedError is returned when a server can't be called at a given time.
+type DeniedError struct {
+	Server        string
+	Windows       string
+	AllowOverride bool
+}
+
+func (e *DeniedError) Error() string {
+	msg := fmt.Sprintf("access to %s is restricted to %s", e.Server, e.Windows)
+	if e.AllowOverride {
+		msg += fmt.Sprintf(". An on-call override can be granted with `docker mcp gateway override --server %s`", e.Server)
+	}
+	return msg
+}
+
+// Check returns a *DeniedError if the server can't be called at the given time.
+func (p *Policy) Check(serverName string, now time.Time) error {
+	if p == nil {
+		return nil
+	}
+
+	server, found := p.Servers[serverName]
+	if !found || len(server.AccessWindows) == 0 {
+		return nil
+	}
+
+	location, err := server.location()
+	if err != nil {
+		return err
+	}
+	now = now.In(location)
+
+	for _, window := range server.AccessWindows {
+		if window.contains(now) {
+			return nil
+		}
+	}
+
+	return &DeniedError{
+		Server:        serverName,
+		Windows:       server.describeWindows(),
+		AllowOverride: server.AllowOverride,
+	}
+}
+
+// IdentityDeniedError is returned when an authenticated client isn't allowed to call a server.
+type IdentityDeniedError struct {
+	Server   string
+	Identity string
+}
+
+func (e *IdentityDeniedError) Error() string {
+	return fmt.Sprintf("%s isn't allowed to call %s", e.Identity, e.Server)
+}
+
+// CheckIdentity returns an *IdentityDeniedError if the client can't call the server.
+// Clients that aren't authenticated by an identity provider, eg. through stdio, aren't restricted.
+func (p *Policy) CheckIdentity(serverName string, identity *Identity) error {
+	if p == nil || identity == nil {
+		return nil
+	}
+
+	server, found := p.Servers[serverName]
+	if !found || (len(server.AllowedUsers) == 0 && len(server.AllowedGroups) == 0) {
+		return nil
+	}
+
+	if identity.matches(server.AllowedUsers, server.AllowedGroups) {
+		return nil
+	}
+
+	return &IdentityDeniedError{
+		Server:   serverName,
+		Identity: identity.String(),
+	}
+}
+
+// HasProfiles reports whether the authenticated clients are restricted to the servers of profiles.
+func (p *Policy) HasProfiles() bool {
+	return p != nil && (len(p.Profiles) > 0 || p.DefaultProfile != "")
+}
+
+// ProfileFor returns the profile of an authenticated client, or "" if it matches no rule and there's no default profile.
+func (p *Policy) ProfileFor(identity *Identity) string {
+	if p == nil || identity == nil {
+		return ""
+	}
+
+	for _, rule := range p.Profiles {
+		if identity.matches(rule.Users, rule.Groups) {
+			return rule.Profile
+		}
+	}
+	return p.DefaultProfile
+}
+
+// ProfileNames returns the profiles the clients can be mapped to, sorted.
+func (p *Policy) ProfileNames() []string {
+	if p == nil {
+		return nil
+	}
+
+	var names []string
+	for _, rule := range p.Profiles {
+		if !slices.Contains(names, rule.Profile) {
+			names = append(names, rule.Profile)
+		}
+	}
+	if p.DefaultProfile != "" && !slices.Contains(names, p.DefaultProfile) {
+		names = append(names, p.DefaultProfile)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// AllowsOverride returns true if an on-call override can grant access to the server.
+func (p *Policy) AllowsOverride(serverName string) bool {
+	if p == nil {
+		return false
+	}
+
+	return p.Servers[serverName].AllowOverride
+}
+
+func (s ServerPolicy) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+	}
+	return location, nil
+}
+
+func (s ServerPolicy) describeWindows() string {
+	var descriptions []string
+	for _, window := range s.AccessWindows {
+		descriptions = append(descriptions, window.String())
+	}
+
+	description := strings.Join(descriptions, ", ")
+	if s.Timezone != "" {
+		description += " (" + s.Timezone + ")"
+	}
+	return description
+}
+
+func (w TimeWindow) validate() error {
+	for _, day := range w.Days {
+		if !slices.Contains(weekdays, strings.ToLower(day)) {
+			return fmt.Errorf("invalid day %q, expected one of: %s", day, strings.Join(weekdays, ", "))
+		}
+	}
+	if _, err := parseTimeOfDay(w.From); err != nil {
+		return err
+	}
+	if _, err := parseTimeOfDay(w.To); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w TimeWindow) contains(now time.Time) bool {
+	from, err := parseTimeOfDay(w.From)
+	if err != nil {
+		return false
+	}
+	to, err := parseTimeOfDay(w.To)
+	if err != nil {
+		return false
+	}
+
+	minutes := now.Hour()*60 + now.Minute()
+	if from < to {
+		return w.onDay(now.Weekday()) && minutes >= from && minutes < to
+	}
+
+	// The window spans midnight: it started either today or yesterday
+	return (w.onDay(now.Weekday()) && minutes >= from) || (w.onDay((now.Weekday()+6)%7) && minutes < to)
+}
+
+func (w TimeWindow) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(w.Days, func(d string) bool {
+		return strings.EqualFold(d, weekdays[day])
+	})
+}
+
+func (w TimeWindow) String() string {
+	days := "every day"
+	if len(w.Days) > 0 {
+		days = strings.Join(w.Days, ",")
+	}
+	return fmt.Sprintf("%s %s-%s", days, w.From, w.To)
+}
+
+// parseTimeOfDay parses HH:MM into a number of minutes since midnight.
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
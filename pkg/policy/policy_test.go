@@ -0,0 +1,197 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func businessHours() *Policy {
+	return &Policy{
+		Servers: map[string]ServerPolicy{
+			"postgres-prod": {
+				AccessWindows: []TimeWindow{{Days: []string{"mon", "tue", "wed", "thu", "fri"}, From: "09:00", To: "18:00"}},
+				Timezone:      "UTC",
+				AllowOverride: true,
+			},
+			"backups": {
+				AccessWindows: []TimeWindow{{From: "22:00", To: "06:00"}},
+				Timezone:      "UTC",
+			},
+		},
+	}
+}
+
+func at(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCheck(t *testing.T) {
+	policy := businessHours()
+
+	tests := []struct {
+		name    string
+		server  string
+		now     string
+		allowed bool
+	}{
+		{"business hours", "postgres-prod", "2026-10-14T10:30:00Z", true},
+		{"before opening", "postgres-prod", "2026-10-14T08:59:00Z", false},
+		{"at closing", "postgres-prod", "2026-10-14T18:00:00Z", false},
+		{"week-end", "postgres-prod", "2026-10-17T10:30:00Z", false},
+		{"unrestricted server", "github", "2026-10-17T03:00:00Z", true},
+		{"night window, evening", "backups", "2026-10-14T23:00:00Z", true},
+		{"night window, morning", "backups", "2026-10-15T05:59:00Z", true},
+		{"night window, day", "backups", "2026-10-15T12:00:00Z", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.server, at(tt.now))
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				var denied *DeniedError
+				require.ErrorAs(t, err, &denied)
+				assert.Equal(t, tt.server, denied.Server)
+			}
+		})
+	}
+}
+
+func TestCheckTimezone(t *testing.T) {
+	policy := &Policy{
+		Servers: map[string]ServerPolicy{
+			"postgres-prod": {
+				AccessWindows: []TimeWindow{{From: "09:00", To: "18:00"}},
+				Timezone:      "Asia/Tokyo",
+			},
+		},
+	}
+
+	// 01:00 UTC is 10:00 in Tokyo
+	require.NoError(t, policy.Check("postgres-prod", at("2026-10-14T01:00:00Z")))
+	require.Error(t, policy.Check("postgres-prod", at("2026-10-14T10:00:00Z")))
+}
+
+func TestDeniedErrorMessage(t *testing.T) {
+	err := businessHours().Check("postgres-prod", at("2026-10-17T10:30:00Z"))
+
+	assert.EqualError(t, err, "access to postgres-prod is restricted to mon,tue,wed,thu,fri 09:00-18:00 (UTC). An on-call override can be granted with `docker mcp gateway override --server postgres-prod`")
+}
+
+func TestNilPolicy(t *testing.T) {
+	var policy *Policy
+
+	assert.NoError(t, policy.Check("postgres-prod", time.Now()))
+	assert.NoError(t, policy.CheckIdentity("postgres-prod", &Identity{Subject: "alice"}))
+	assert.False(t, policy.AllowsOverride("postgres-prod"))
+}
+
+func TestCheckIdentity(t *testing.T) {
+	policy := &Policy{
+		Servers: map[string]ServerPolicy{
+			"postgres-prod": {
+				AllowedUsers:  []string{"alice@example.com", "svc-reporting"},
+				AllowedGroups: []string{"dba"},
+			},
+			"fetch": {},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		server   string
+		identity *Identity
+		allowed  bool
+	}{
+		{"email", "postgres-prod", &Identity{Subject: "1234", Email: "Alice@example.com"}, true},
+		{"subject", "postgres-prod", &Identity{Subject: "svc-reporting"}, true},
+		{"group", "postgres-prod", &Identity{Subject: "5678", Groups: []string{"dev", "dba"}}, true},
+		{"other user", "postgres-prod", &Identity{Subject: "5678", Email: "bob@example.com", Groups: []string{"dev"}}, false},
+		{"not authenticated", "postgres-prod", nil, true},
+		{"unrestricted server", "fetch", &Identity{Subject: "5678"}, true},
+		{"unknown server", "github", &Identity{Subject: "5678"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.CheckIdentity(tt.server, tt.identity)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, "bob@example.com isn't allowed to call postgres-prod")
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`servers:
+  postgres-prod:
+    timezone: Europe/Paris
+    allowOverride: true
+    accessWindows:
+      - days: [mon, tue, wed, thu, fri]
+        from: "09:00"
+        to: "18:00"
+`), 0o644))
+
+	policy, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Paris", policy.Servers["postgres-prod"].Timezone)
+	assert.True(t, policy.AllowsOverride("postgres-prod"))
+	assert.Len(t, policy.Servers["postgres-prod"].AccessWindows, 1)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"invalid day":          "servers:\n  db:\n    accessWindows:\n      - days: [monday]\n        from: \"09:00\"\n        to: \"18:00\"\n",
+		"invalid time":         "servers:\n  db:\n    accessWindows:\n      - from: \"9am\"\n        to: \"18:00\"\n",
+		"invalid timezone":     "servers:\n  db:\n    timezone: Mars/Olympus\n",
+		"rule without profile": "profiles:\n  - groups: [dba]\n",
+		"rule without match":   "profiles:\n  - profile: db-tools\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+			_, err := Load(path)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestProfileFor(t *testing.T) {
+	policy := &Policy{
+		Profiles: []ProfileRule{
+			{Groups: []string{"dba"}, Profile: "db-tools"},
+			{Users: []string{"alice@example.com"}, Profile: "alice"},
+		},
+		DefaultProfile: "base",
+	}
+
+	assert.True(t, policy.HasProfiles())
+	assert.Equal(t, "db-tools", policy.ProfileFor(&Identity{Subject: "1", Email: "alice@example.com", Groups: []string{"dba"}}))
+	assert.Equal(t, "alice", policy.ProfileFor(&Identity{Subject: "1", Email: "alice@example.com"}))
+	assert.Equal(t, "base", policy.ProfileFor(&Identity{Subject: "2"}))
+	assert.Empty(t, policy.ProfileFor(nil))
+	assert.Equal(t, []string{"alice", "base", "db-tools"}, policy.ProfileNames())
+
+	policy.DefaultProfile = ""
+	assert.Empty(t, policy.ProfileFor(&Identity{Subject: "2"}))
+
+	assert.False(t, (&Policy{}).HasProfiles())
+	assert.False(t, (*Policy)(nil).HasProfiles())
+}
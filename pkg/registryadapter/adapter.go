@@ -0,0 +1,82 @@
+// Package registryadapter reads the servers of MCP registries and normalizes them into catalog servers,
+// so that `docker mcp catalog import` can consume registries other than the Docker MCP registry.
+//
+// Adapters for other registries implement Adapter and are made available with Register:
+//
+//	func init() {
+//		registryadapter.Register(&myRegistry{})
+//	}
+package registryadapter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// Default is the adapter used when none is selected: the MCP registry format.
+const Default = MCPRegistryName
+
+// Adapter reads servers from a registry and normalizes them into catalog servers.
+type Adapter interface {
+	// Name is the name the adapter is selected with, eg. mcp-registry.
+	Name() string
+	// Fetch returns the servers a reference points to. Depending on the registry,
+	// the reference is a URL, a file or the name of a server.
+	Fetch(ctx context.Context, ref string) ([]catalog.Server, error)
+}
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = map[string]Adapter{}
+)
+
+func init() {
+	Register(&mcpRegistry{})
+	Register(&mcpServers{})
+	Register(&smithery{})
+}
+
+// Register makes an adapter available by its name. It panics if an adapter with the same name is already registered.
+func Register(adapter Adapter) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+
+	name := adapter.Name()
+	if _, exists := adapters[name]; exists {
+		panic(fmt.Sprintf("registryadapter: adapter %s is already registered", name))
+	}
+	adapters[name] = adapter
+}
+
+// Get returns the adapter registered with a name.
+func Get(name string) (Adapter, error) {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+
+	adapter, ok := adapters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown registry adapter %q, expected one of %v", name, namesLocked())
+	}
+	return adapter, nil
+}
+
+// Names returns the names of the registered adapters, sorted.
+func Names() []string {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+
+	return namesLocked()
+}
+
+func namesLocked() []string {
+	var names []string
+	for name := range adapters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,146 @@
+package registryadapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+type fakeAdapter struct{}
+
+func (fakeAdapter) Name() string { return "fake" }
+
+func (fakeAdapter) Fetch(context.Context, string) ([]catalog.Server, error) {
+	return []catalog.Server{{Name: "fake", Image: "example/fake"}}, nil
+}
+
+func TestRegister(t *testing.T) {
+	Register(fakeAdapter{})
+	t.Cleanup(func() {
+		adaptersMu.Lock()
+		delete(adapters, "fake")
+		adaptersMu.Unlock()
+	})
+
+	adapter, err := Get("fake")
+	require.NoError(t, err)
+	assert.Equal(t, "fake", adapter.Name())
+	assert.Equal(t, []string{"fake", "mcp-registry", "mcp-servers", "smithery"}, Names())
+
+	assert.Panics(t, func() { Register(fakeAdapter{}) })
+
+	_, err = Get("unknown")
+	require.ErrorContains(t, err, `unknown registry adapter "unknown"`)
+}
+
+func TestMCPServers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "claude_desktop_config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+  "mcpServers": {
+    "weather": {
+      "command": "npx",
+      "args": ["-y", "@example/weather-mcp@1.0.2", "--stdio"],
+      "env": {"WEATHER_API_KEY": "<your key>", "UNITS": "metric"}
+    },
+    "fetch": {
+      "command": "uvx",
+      "args": ["mcp-server-fetch==0.6.2"]
+    },
+    "github": {
+      "command": "docker",
+      "args": ["run", "-i", "--rm", "-e", "GITHUB_PERSONAL_ACCESS_TOKEN", "-e", "GITHUB_HOST=github.com", "ghcr.io/github/github-mcp-server"],
+      "env": {"GITHUB_PERSONAL_ACCESS_TOKEN": "<token>"}
+    },
+    "linear": {
+      "url": "https://mcp.linear.app/sse"
+    },
+    "local": {
+      "command": "node",
+      "args": ["/path/to/server.js"]
+    }
+  }
+}`), 0o644))
+
+	servers, err := (&mcpServers{}).Fetch(t.Context(), path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []catalog.Server{
+		{
+			Name:    "fetch",
+			Type:    "command",
+			Package: &catalog.Package{RegistryType: "pypi", Identifier: "mcp-server-fetch", Version: "0.6.2"},
+		},
+		{
+			Name:    "github",
+			Type:    "server",
+			Image:   "ghcr.io/github/github-mcp-server",
+			Env:     []catalog.Env{{Name: "GITHUB_HOST", Value: "github.com"}},
+			Secrets: []catalog.Secret{{Name: "github.github_personal_access_token", Env: "GITHUB_PERSONAL_ACCESS_TOKEN"}},
+		},
+		{
+			Name:   "linear",
+			Type:   "remote",
+			Remote: catalog.Remote{URL: "https://mcp.linear.app/sse", Transport: "sse"},
+		},
+		{
+			Name:    "weather",
+			Type:    "command",
+			Package: &catalog.Package{RegistryType: "npm", Identifier: "@example/weather-mcp", Version: "1.0.2"},
+			Command: []string{"--stdio"},
+			Env:     []catalog.Env{{Name: "UNITS", Value: "metric"}},
+			Secrets: []catalog.Secret{{Name: "weather.weather_api_key", Env: "WEATHER_API_KEY"}},
+		},
+	}, servers)
+}
+
+func TestMCPServersNothingToImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"servers": {"local": {"command": "python", "args": ["server.py"]}}}`), 0o644))
+
+	_, err := (&mcpServers{}).Fetch(t.Context(), path)
+	require.ErrorContains(t, err, "python runs on the host")
+}
+
+func TestSmithery(t *testing.T) {
+	var authorization, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		path = r.URL.EscapedPath()
+		_, _ = w.Write([]byte(`{
+  "qualifiedName": "@acme/notes",
+  "displayName": "Notes",
+  "description": "Take notes",
+  "remote": true,
+  "connections": [{"type": "http", "deploymentUrl": "https://server.smithery.ai/@acme/notes/mcp"}]
+}`))
+	}))
+	defer server.Close()
+	t.Setenv("SMITHERY_REGISTRY_URL", server.URL)
+	t.Setenv("SMITHERY_API_KEY", "secret")
+
+	servers, err := (&smithery{}).Fetch(t.Context(), "@acme/notes")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.Equal(t, "/servers/@acme%2Fnotes", path)
+	assert.Equal(t, []catalog.Server{{
+		Name:        "acme-notes",
+		Type:        "remote",
+		Title:       "Notes",
+		Description: "Take notes",
+		Remote: catalog.Remote{
+			URL:       "https://server.smithery.ai/@acme/notes/mcp",
+			Transport: "streamable-http",
+			Headers:   map[string]string{"Authorization": "Bearer ${SMITHERY_API_KEY}"},
+		},
+		Secrets: []catalog.Secret{{Name: "acme-notes.smithery_api_key", Env: "SMITHERY_API_KEY"}},
+	}}, servers)
+}
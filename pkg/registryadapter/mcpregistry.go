@@ -0,0 +1,105 @@
+package registryadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/registryapi"
+)
+
+// MCPRegistryName is the name of the adapter of the MCP registry format.
+const MCPRegistryName = "mcp-registry"
+
+// mcpRegistry reads a server definition of the MCP registry, either from its URL
+// or by name from the default MCP registry, eg. com.example/weather@1.2.0.
+type mcpRegistry struct{}
+
+func (*mcpRegistry) Name() string {
+	return MCPRegistryName
+}
+
+func (*mcpRegistry) Fetch(ctx context.Context, ref string) ([]catalog.Server, error) {
+	serverDetail, err := FetchServerDetail(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return []catalog.Server{serverDetail.ToCatalogServer()}, nil
+}
+
+// FetchServerDetail fetches a server definition from its URL, or by name from the default MCP registry.
+func FetchServerDetail(ctx context.Context, ref string) (oci.ServerDetail, error) {
+	if registryapi.IsServerURL(ref) {
+		return fetchServerURL(ctx, ref)
+	}
+	return fetchRegistryServer(ctx, ref)
+}
+
+// fetchServerURL fetches a server definition from a URL.
+func fetchServerURL(ctx context.Context, serverURL string) (oci.ServerDetail, error) {
+	parsedURL, err := url.Parse(serverURL)
+	if err != nil {
+		return oci.ServerDetail{}, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return oci.ServerDetail{}, fmt.Errorf("URL must use http or https protocol")
+	}
+
+	var serverDetail oci.ServerDetail
+	if err := getJSON(ctx, serverURL, nil, &serverDetail); err != nil {
+		return oci.ServerDetail{}, fmt.Errorf("failed to fetch server definition: %w", err)
+	}
+
+	return serverDetail, nil
+}
+
+// fetchRegistryServer fetches a server by name, eg. com.example/weather@1.2.0, from the default MCP registry.
+func fetchRegistryServer(ctx context.Context, name string) (oci.ServerDetail, error) {
+	serverURL, err := registryapi.ParseServerReference(ctx, name, db.LazySettings{})
+	if err != nil {
+		return oci.ServerDetail{}, err
+	}
+
+	response, err := registryapi.NewClient().GetServer(ctx, serverURL)
+	if err != nil {
+		return oci.ServerDetail{}, fmt.Errorf("failed to fetch server %s: %w", name, err)
+	}
+	if response.Server.Name == "" {
+		return oci.ServerDetail{}, fmt.Errorf("server %s not found", name)
+	}
+
+	return oci.ServerDetailFromRegistry(response.Server), nil
+}
+
+// getJSON decodes the JSON response of a GET request.
+func getJSON(ctx context.Context, url string, headers map[string]string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package registryadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// MCPServersName is the name of the adapter of the mcpServers format.
+const MCPServersName = "mcp-servers"
+
+// mcpServers reads the `mcpServers` configuration that clients like Claude Desktop use,
+// and that community registries like mcp.so publish for each server. VS Code's `servers` key is also accepted.
+//
+// Servers started with npx or uvx become command servers, servers started with docker run use their image,
+// and servers with a url become remote servers. Other commands run on the host and can't be imported.
+type mcpServers struct{}
+
+type mcpServersFile struct {
+	MCPServers map[string]mcpServerEntry `json:"mcpServers"`
+	Servers    map[string]mcpServerEntry `json:"servers"`
+}
+
+type mcpServerEntry struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env"`
+	URL     string            `json:"url"`
+	// Type is the transport of a remote server: sse, http or streamable-http.
+	Type    string            `json:"type"`
+	Headers map[string]string `json:"headers"`
+}
+
+// secretEnv matches the names of the environment variables that are stored as secrets.
+var secretEnv = regexp.MustCompile(`(?i)(TOKEN|KEY|SECRET|PASSWORD|CREDENTIALS?)$`)
+
+func (*mcpServers) Name() string {
+	return MCPServersName
+}
+
+func (*mcpServers) Fetch(ctx context.Context, ref string) ([]catalog.Server, error) {
+	buf, err := readRef(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var file mcpServersFile
+	if err := json.Unmarshal(buf, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ref, err)
+	}
+	entries := file.MCPServers
+	if len(entries) == 0 {
+		entries = file.Servers
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no mcpServers found in %s", ref)
+	}
+
+	var names []string
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		servers []catalog.Server
+		errs    []error
+	)
+	for _, name := range names {
+		server, err := entries[name].toCatalogServer(name)
+		if err != nil {
+			log.Logf("  ! Skipping %s: %v", name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		servers = append(servers, server)
+	}
+	if len(servers) == 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return servers, nil
+}
+
+func (e mcpServerEntry) toCatalogServer(name string) (catalog.Server, error) {
+	server := catalog.Server{
+		Name: name,
+	}
+
+	if e.URL != "" {
+		transport := "streamable-http"
+		if e.Type == "sse" || strings.HasSuffix(e.URL, "/sse") {
+			transport = "sse"
+		}
+		server.Type = "remote"
+		server.Remote = catalog.Remote{
+			URL:       e.URL,
+			Transport: transport,
+			Headers:   e.Headers,
+		}
+		return server, nil
+	}
+
+	switch command := commandName(e.Command); command {
+	case "npx":
+		identifier, version, args, err := packageArgs(e.Args, "@")
+		if err != nil {
+			return catalog.Server{}, err
+		}
+		server.Type = "command"
+		server.Package = &catalog.Package{RegistryType: catalog.PackageTypeNPM, Identifier: identifier, Version: version}
+		server.Command = args
+	case "uvx":
+		identifier, version, args, err := packageArgs(e.Args, "==")
+		if err != nil {
+			return catalog.Server{}, err
+		}
+		server.Type = "command"
+		server.Package = &catalog.Package{RegistryType: catalog.PackageTypePyPI, Identifier: identifier, Version: version}
+		server.Command = args
+	case "docker":
+		image, args, env, err := dockerRunArgs(e.Args)
+		if err != nil {
+			return catalog.Server{}, err
+		}
+		server.Type = "server"
+		server.Image = image
+		server.Command = args
+		// Variables passed with -e NAME take their value from the env of the configuration
+		for envName, value := range e.Env {
+			env[envName] = value
+		}
+		e.Env = env
+	case "":
+		return catalog.Server{}, errors.New("no command or url")
+	default:
+		return catalog.Server{}, fmt.Errorf("%s runs on the host, only npx, uvx, docker and remote servers can be imported", command)
+	}
+
+	var envNames []string
+	for envName := range e.Env {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+	for _, envName := range envNames {
+		if secretEnv.MatchString(envName) {
+			server.Secrets = append(server.Secrets, catalog.Secret{
+				Name: name + "." + strings.ToLower(envName),
+				Env:  envName,
+			})
+			continue
+		}
+		server.Env = append(server.Env, catalog.Env{Name: envName, Value: e.Env[envName]})
+	}
+
+	return server, nil
+}
+
+// commandName returns the name of a command, without its path or extension, eg. npx for C:\...\npx.cmd.
+func commandName(command string) string {
+	command = command[strings.LastIndexAny(command, `/\`)+1:]
+	return strings.TrimSuffix(command, ".cmd")
+}
+
+// packageArgs splits the arguments of npx or uvx into the package, its version and the arguments of the server.
+func packageArgs(args []string, versionSeparator string) (string, string, []string, error) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			// --package and --from name the package explicitly
+			if (arg == "--package" || arg == "-p" || arg == "--from") && i+1 < len(args) {
+				identifier, version := splitVersion(args[i+1], versionSeparator)
+				// The next argument is the binary of the package
+				return identifier, version, nonEmpty(args[min(i+3, len(args)):]), nil
+			}
+			continue
+		}
+
+		identifier, version := splitVersion(arg, versionSeparator)
+		return identifier, version, nonEmpty(args[i+1:]), nil
+	}
+	return "", "", nil, errors.New("no package in the arguments")
+}
+
+// splitVersion splits a package spec, eg. @scope/name@1.0.0 or name==1.0.0. A leading @ is part of the npm scope.
+func splitVersion(spec, separator string) (string, string) {
+	if i := strings.LastIndex(spec, separator); i > 0 {
+		version := spec[i+len(separator):]
+		if version == "latest" {
+			version = ""
+		}
+		return spec[:i], version
+	}
+	return spec, ""
+}
+
+// dockerFlagsWithValue are the flags of docker run whose value is the next argument.
+var dockerFlagsWithValue = map[string]bool{
+	"-e": true, "--env": true, "-v": true, "--volume": true, "--name": true, "--network": true, "-p": true, "--publish": true,
+	"-u": true, "--user": true, "-w": true, "--workdir": true, "--env-file": true, "--mount": true, "--platform": true,
+}
+
+// dockerRunArgs returns the image of a docker run command, the arguments after the image,
+// and the environment variables set with -e.
+func dockerRunArgs(args []string) (string, []string, map[string]string, error) {
+	if len(args) == 0 || args[0] != "run" {
+		return "", nil, nil, errors.New("only docker run is supported")
+	}
+
+	env := map[string]string{}
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			return arg, nonEmpty(args[i+1:]), env, nil
+		}
+		if strings.Contains(arg, "=") || !dockerFlagsWithValue[arg] {
+			continue
+		}
+
+		i++
+		if i < len(args) && (arg == "-e" || arg == "--env") {
+			name, value, _ := strings.Cut(args[i], "=")
+			env[name] = value
+		}
+	}
+	return "", nil, nil, errors.New("no image in the arguments")
+}
+
+func nonEmpty(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+// readRef reads a reference that's either a URL or a file.
+func readRef(ctx context.Context, ref string) ([]byte, error) {
+	parsedURL, err := url.Parse(ref)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return os.ReadFile(ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: HTTP %d %s", ref, resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,96 @@
+package registryadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// SmitheryName is the name of the adapter of the Smithery registry.
+const SmitheryName = "smithery"
+
+const defaultSmitheryURL = "https://registry.smithery.ai"
+
+// smithery reads a server by its qualified name, eg. @owner/server, from the Smithery registry API.
+// The API key is read from SMITHERY_API_KEY. Only the servers Smithery hosts can be imported:
+// they become remote servers that use the same API key.
+type smithery struct{}
+
+type smitheryServer struct {
+	QualifiedName string               `json:"qualifiedName"`
+	DisplayName   string               `json:"displayName"`
+	Description   string               `json:"description"`
+	IconURL       string               `json:"iconUrl"`
+	Remote        bool                 `json:"remote"`
+	DeploymentURL string               `json:"deploymentUrl"`
+	Connections   []smitheryConnection `json:"connections"`
+}
+
+type smitheryConnection struct {
+	Type          string `json:"type"`
+	DeploymentURL string `json:"deploymentUrl"`
+}
+
+func (*smithery) Name() string {
+	return SmitheryName
+}
+
+func (*smithery) Fetch(ctx context.Context, ref string) ([]catalog.Server, error) {
+	baseURL := os.Getenv("SMITHERY_REGISTRY_URL")
+	if baseURL == "" {
+		baseURL = defaultSmitheryURL
+	}
+
+	headers := map[string]string{}
+	if apiKey := os.Getenv("SMITHERY_API_KEY"); apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+
+	var server smitheryServer
+	serverURL := strings.TrimSuffix(baseURL, "/") + "/servers/" + url.PathEscape(ref)
+	if err := getJSON(ctx, serverURL, headers, &server); err != nil {
+		return nil, fmt.Errorf("failed to fetch server %s from Smithery: %w", ref, err)
+	}
+
+	catalogServer, err := server.toCatalogServer()
+	if err != nil {
+		return nil, fmt.Errorf("server %s: %w", ref, err)
+	}
+	return []catalog.Server{catalogServer}, nil
+}
+
+func (s smitheryServer) toCatalogServer() (catalog.Server, error) {
+	deploymentURL := s.DeploymentURL
+	for _, connection := range s.Connections {
+		if deploymentURL == "" && connection.Type == "http" {
+			deploymentURL = connection.DeploymentURL
+		}
+	}
+	if deploymentURL == "" {
+		return catalog.Server{}, errors.New("the server is not hosted by Smithery, it can only run locally with the Smithery CLI")
+	}
+
+	name := strings.ReplaceAll(strings.TrimPrefix(s.QualifiedName, "@"), "/", "-")
+	return catalog.Server{
+		Name:        name,
+		Type:        "remote",
+		Title:       s.DisplayName,
+		Description: s.Description,
+		Icon:        s.IconURL,
+		Remote: catalog.Remote{
+			URL:       deploymentURL,
+			Transport: "streamable-http",
+			Headers: map[string]string{
+				"Authorization": "Bearer ${SMITHERY_API_KEY}",
+			},
+		},
+		Secrets: []catalog.Secret{
+			{Name: name + ".smithery_api_key", Env: "SMITHERY_API_KEY"},
+		},
+	}, nil
+}
@@ -0,0 +1,71 @@
+package registryapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultBaseURL is the registry that bare server names are resolved against, unless configured otherwise.
+const DefaultBaseURL = "https://registry.modelcontextprotocol.io"
+
+// SettingBaseURL is the setting that changes the registry bare server names are resolved against.
+const SettingBaseURL = "registry.url"
+
+// Settings reads the settings of the local database.
+type Settings interface {
+	GetSetting(ctx context.Context, key string) (string, error)
+}
+
+// BaseURL returns the registry that bare server names are resolved against: the DOCKER_MCP_REGISTRY_URL
+// environment variable, or the registry.url setting, or the official MCP Registry.
+func BaseURL(ctx context.Context, settings Settings) string {
+	if baseURL := os.Getenv("DOCKER_MCP_REGISTRY_URL"); baseURL != "" {
+		return strings.TrimSuffix(baseURL, "/")
+	}
+	if settings != nil {
+		if baseURL, err := settings.GetSetting(ctx, SettingBaseURL); err == nil && baseURL != "" {
+			return strings.TrimSuffix(baseURL, "/")
+		}
+	}
+	return DefaultBaseURL
+}
+
+// IsServerURL tells whether a server reference is a URL rather than a bare server name.
+func IsServerURL(ref string) bool {
+	return strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://")
+}
+
+// serverNamePattern is the format of the server names published in the MCP registry.
+var serverNamePattern = regexp.MustCompile(`^[a-zA-Z0-9.-]+/[a-zA-Z0-9._-]+$`)
+
+// ParseServerReference parses a registry URL, or a bare server name such as com.example/weather,
+// optionally followed by @<version>, that is resolved against the registry returned by BaseURL.
+// Defaults to the latest version.
+func ParseServerReference(ctx context.Context, ref string, settings Settings) (*ServerURL, error) {
+	ref = strings.TrimSpace(ref)
+	if IsServerURL(ref) {
+		return ParseServerURL(ref)
+	}
+
+	name, version, _ := strings.Cut(ref, "@")
+	if !serverNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid server name %q, expected <namespace>/<name>[@<version>]", ref)
+	}
+	if version == "" {
+		version = "latest"
+	}
+
+	serverURL := &ServerURL{
+		BaseURL:    BaseURL(ctx, settings),
+		APIVersion: "v0",
+		ServerName: url.PathEscape(name),
+		Version:    url.PathEscape(version),
+	}
+	serverURL.RawURL = serverURL.String()
+
+	return serverURL, nil
+}
@@ -0,0 +1,85 @@
+package registryapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServerReference(t *testing.T) {
+	t.Setenv("DOCKER_MCP_REGISTRY_URL", "")
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "bare name",
+			ref:  "com.example/weather",
+			want: "https://registry.example.com/v0/servers/com.example%2Fweather/versions/latest",
+		},
+		{
+			name: "bare name with version",
+			ref:  "com.example/weather@1.2.0",
+			want: "https://registry.example.com/v0/servers/com.example%2Fweather/versions/1.2.0",
+		},
+		{
+			name: "explicit latest",
+			ref:  "com.example/weather@latest",
+			want: "https://registry.example.com/v0/servers/com.example%2Fweather/versions/latest",
+		},
+		{
+			name: "url",
+			ref:  "https://registry.modelcontextprotocol.io/v0/servers/ai.aliengiraffe%2Fspotdb/versions/0.1.0",
+			want: "https://registry.modelcontextprotocol.io/v0/servers/ai.aliengiraffe%2Fspotdb/versions/0.1.0",
+		},
+		{
+			name:    "name without namespace",
+			ref:     "weather",
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			ref:     "@1.2.0",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported scheme",
+			ref:     "ftp://example.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseServerReference(t.Context(), tt.ref, fakeSettings{SettingBaseURL: "https://registry.example.com/"})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+type fakeSettings map[string]string
+
+func (f fakeSettings) GetSetting(_ context.Context, key string) (string, error) {
+	return f[key], nil
+}
+
+func TestBaseURL(t *testing.T) {
+	t.Setenv("DOCKER_MCP_REGISTRY_URL", "")
+
+	assert.Equal(t, DefaultBaseURL, BaseURL(t.Context(), nil))
+	assert.Equal(t, DefaultBaseURL, BaseURL(t.Context(), fakeSettings{}))
+	assert.Equal(t, "https://registry.example.com", BaseURL(t.Context(), fakeSettings{SettingBaseURL: "https://registry.example.com/"}))
+
+	t.Setenv("DOCKER_MCP_REGISTRY_URL", "https://env.example.com")
+	assert.Equal(t, "https://env.example.com", BaseURL(t.Context(), fakeSettings{SettingBaseURL: "https://registry.example.com"}))
+}
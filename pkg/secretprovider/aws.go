@@ -0,0 +1,86 @@
+package secretprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// AWSSecretsManager reads secrets from AWS Secrets Manager.
+// Credentials and region are resolved by the AWS CLI's default chain
+// (environment, shared config, ECS task role, EC2 instance profile...).
+type AWSSecretsManager struct {
+	// Region overrides the region from the default chain, if set.
+	Region string
+	// Prefix is prepended to secret names to build the secret id.
+	Prefix string
+}
+
+func (p *AWSSecretsManager) GetSecrets(ctx context.Context, names []string) (map[string]string, error) {
+	secrets := make(map[string]string, len(names))
+
+	for _, name := range names {
+		args := []string{"secretsmanager", "get-secret-value", "--secret-id", p.Prefix + name, "--query", "SecretString", "--output", "text"}
+		value, found, err := runAWS(ctx, p.Region, "ResourceNotFoundException", args...)
+		if err != nil {
+			return nil, fmt.Errorf("reading secret %s from AWS Secrets Manager: %w", p.Prefix+name, err)
+		}
+		if found {
+			secrets[name] = value
+		}
+	}
+
+	return secrets, nil
+}
+
+// AWSParameterStore reads secrets from AWS Systems Manager Parameter Store.
+// SecureString parameters are decrypted.
+type AWSParameterStore struct {
+	// Region overrides the region from the default chain, if set.
+	Region string
+	// Prefix is prepended to secret names to build the parameter name, eg. /mcp/
+	Prefix string
+}
+
+func (p *AWSParameterStore) GetSecrets(ctx context.Context, names []string) (map[string]string, error) {
+	secrets := make(map[string]string, len(names))
+
+	for _, name := range names {
+		args := []string{"ssm", "get-parameter", "--name", p.Prefix + name, "--with-decryption", "--query", "Parameter.Value", "--output", "text"}
+		value, found, err := runAWS(ctx, p.Region, "ParameterNotFound", args...)
+		if err != nil {
+			return nil, fmt.Errorf("reading parameter %s from AWS Parameter Store: %w", p.Prefix+name, err)
+		}
+		if found {
+			secrets[name] = value
+		}
+	}
+
+	return secrets, nil
+}
+
+// runAWS runs the aws CLI. Returns found=false if the call failed with notFoundCode.
+func runAWS(ctx context.Context, region, notFoundCode string, args ...string) (string, bool, error) {
+	if region != "" {
+		args = append(args, "--region", region)
+	}
+
+	out, err := runCommand(ctx, "aws", args...)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			stderr := strings.TrimSpace(string(exitErr.Stderr))
+			if strings.Contains(stderr, notFoundCode) {
+				return "", false, nil
+			}
+			if stderr != "" {
+				return "", false, errors.New(stderr)
+			}
+		}
+		return "", false, err
+	}
+
+	return strings.TrimSuffix(string(out), "\n"), true, nil
+}
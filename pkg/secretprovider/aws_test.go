@@ -0,0 +1,74 @@
+package secretprovider
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCommand replaces runCommand for the duration of a test.
+func fakeCommand(t *testing.T, fn func(name string, args []string) ([]byte, error)) *[][]string {
+	t.Helper()
+
+	var calls [][]string
+	previous := runCommand
+	runCommand = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		calls = append(calls, append([]string{name}, args...))
+		return fn(name, args)
+	}
+	t.Cleanup(func() { runCommand = previous })
+
+	return &calls
+}
+
+func exitError(stderr string) error {
+	return &exec.ExitError{Stderr: []byte(stderr)}
+}
+
+func TestAWSSecretsManager(t *testing.T) {
+	calls := fakeCommand(t, func(_ string, args []string) ([]byte, error) {
+		switch args[3] {
+		case "mcp/github.token":
+			return []byte("ghp_secret\n"), nil
+		default:
+			return nil, exitError("An error occurred (ResourceNotFoundException) when calling the GetSecretValue operation")
+		}
+	})
+
+	provider := &AWSSecretsManager{Region: "eu-west-1", Prefix: "mcp/"}
+	secrets, err := provider.GetSecrets(t.Context(), []string{"github.token", "missing"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"github.token": "ghp_secret"}, secrets)
+	require.Len(t, *calls, 2)
+	assert.Equal(t, "aws secretsmanager get-secret-value --secret-id mcp/github.token --query SecretString --output text --region eu-west-1", strings.Join((*calls)[0], " "))
+}
+
+func TestAWSParameterStore(t *testing.T) {
+	calls := fakeCommand(t, func(_ string, args []string) ([]byte, error) {
+		if args[3] == "/mcp/github.token" {
+			return []byte("ghp_secret\n"), nil
+		}
+		return nil, exitError("An error occurred (ParameterNotFound) when calling the GetParameter operation")
+	})
+
+	provider := &AWSParameterStore{Prefix: "/mcp/"}
+	secrets, err := provider.GetSecrets(t.Context(), []string{"github.token", "missing"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"github.token": "ghp_secret"}, secrets)
+	assert.Equal(t, "aws ssm get-parameter --name /mcp/github.token --with-decryption --query Parameter.Value --output text", strings.Join((*calls)[0], " "))
+}
+
+func TestAWSErrors(t *testing.T) {
+	fakeCommand(t, func(string, []string) ([]byte, error) {
+		return nil, exitError("Unable to locate credentials. You can configure credentials by running \"aws configure\".")
+	})
+
+	_, err := (&AWSSecretsManager{}).GetSecrets(t.Context(), []string{"github.token"})
+	require.ErrorContains(t, err, "reading secret github.token from AWS Secrets Manager: Unable to locate credentials")
+}
@@ -0,0 +1,106 @@
+package secretprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// opNotFoundErrors are the errors of op read for items and fields that don't exist.
+var opNotFoundErrors = []string{"isn't an item", "isn't a field"}
+
+// OnePassword reads secrets from 1Password with the op CLI.
+// The CLI talks to a 1Password Connect server when OP_CONNECT_HOST and OP_CONNECT_TOKEN
+// are set, and uses a service account when OP_SERVICE_ACCOUNT_TOKEN is set.
+type OnePassword struct {
+	// References maps secret names to secret references, eg. github.token -> op://dev/github/token
+	References map[string]string
+	// Vault is used for secrets without an explicit reference.
+	// The secret <server>.<key> is then read from op://<vault>/<server>/<key>
+	Vault string
+}
+
+func (p *OnePassword) GetSecrets(ctx context.Context, names []string) (map[string]string, error) {
+	secrets := make(map[string]string, len(names))
+
+	for _, name := range names {
+		reference, explicit, ok := p.reference(name)
+		if !ok {
+			continue
+		}
+
+		value, err := runOp(ctx, "read", "--no-newline", reference)
+		if err != nil {
+			// The vault doesn't have to hold all the secrets, but explicit references must exist
+			if !explicit && isOpNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading secret %s from 1Password (%s): %w", name, reference, err)
+		}
+		secrets[name] = value
+	}
+
+	return secrets, nil
+}
+
+// reference returns the secret reference of a secret, and whether it's one of References rather than derived from Vault.
+func (p *OnePassword) reference(name string) (string, bool, bool) {
+	if reference, ok := p.References[name]; ok {
+		return reference, true, true
+	}
+	if p.Vault == "" {
+		return "", false, false
+	}
+
+	item, field, ok := strings.Cut(name, ".")
+	if !ok {
+		return "", false, false
+	}
+	return "op://" + p.Vault + "/" + item + "/" + field, false, true
+}
+
+func isOpNotFound(err error) bool {
+	for _, notFound := range opNotFoundErrors {
+		if strings.Contains(err.Error(), notFound) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOnePasswordReference returns true if s is a 1Password secret reference:
+// op://<vault>/<item>/<field> or op://<vault>/<item>/<section>/<field>
+func IsOnePasswordReference(s string) bool {
+	path, ok := strings.CutPrefix(s, "op://")
+	if !ok {
+		return false
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 && len(parts) != 4 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func runOp(ctx context.Context, args ...string) (string, error) {
+	out, err := runCommand(ctx, "op", args...)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+				return "", errors.New(stderr)
+			}
+		}
+		return "", err
+	}
+
+	return string(out), nil
+}
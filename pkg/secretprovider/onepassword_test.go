@@ -0,0 +1,77 @@
+package secretprovider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnePassword(t *testing.T) {
+	calls := fakeCommand(t, func(_ string, args []string) ([]byte, error) {
+		return []byte("value of " + args[2]), nil
+	})
+
+	provider := &OnePassword{
+		References: map[string]string{"github.token": "op://dev/GitHub/credential"},
+		Vault:      "mcp",
+	}
+	secrets, err := provider.GetSecrets(t.Context(), []string{"github.token", "slack.bot_token", "invalid"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"github.token":    "value of op://dev/GitHub/credential",
+		"slack.bot_token": "value of op://mcp/slack/bot_token",
+	}, secrets)
+	require.Len(t, *calls, 2)
+	assert.Equal(t, "op read --no-newline op://dev/GitHub/credential", strings.Join((*calls)[0], " "))
+}
+
+func TestOnePasswordWithoutVault(t *testing.T) {
+	calls := fakeCommand(t, func(string, []string) ([]byte, error) {
+		return nil, exitError(`[ERROR] "GitHub" isn't an item in the "dev" vault`)
+	})
+
+	secrets, err := (&OnePassword{}).GetSecrets(t.Context(), []string{"github.token"})
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+	assert.Empty(t, *calls)
+
+	provider := &OnePassword{References: map[string]string{"github.token": "op://dev/GitHub/credential"}}
+	_, err = provider.GetSecrets(t.Context(), []string{"github.token"})
+	require.ErrorContains(t, err, `isn't an item in the "dev" vault`)
+}
+
+func TestOnePasswordVaultNotFound(t *testing.T) {
+	fakeCommand(t, func(_ string, args []string) ([]byte, error) {
+		switch args[2] {
+		case "op://mcp/github/token":
+			return []byte("ghp_secret"), nil
+		case "op://mcp/slack/bot_token":
+			return nil, exitError(`[ERROR] 2025/01/01 12:00:00 could not read secret 'op://mcp/slack/bot_token': "slack" isn't an item in the "mcp" vault`)
+		default:
+			return nil, exitError(`[ERROR] 2025/01/01 12:00:00 could not read secret 'op://mcp/notion/token': "token" isn't a field in the "notion" item`)
+		}
+	})
+
+	// Secrets that aren't in the vault are omitted
+	secrets, err := (&OnePassword{Vault: "mcp"}).GetSecrets(t.Context(), []string{"github.token", "slack.bot_token", "notion.token"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"github.token": "ghp_secret"}, secrets)
+
+	// Other errors still fail
+	fakeCommand(t, func(string, []string) ([]byte, error) {
+		return nil, exitError(`[ERROR] You are not currently signed in`)
+	})
+	_, err = (&OnePassword{Vault: "mcp"}).GetSecrets(t.Context(), []string{"github.token"})
+	require.ErrorContains(t, err, "not currently signed in")
+}
+
+func TestIsOnePasswordReference(t *testing.T) {
+	assert.True(t, IsOnePasswordReference("op://dev/GitHub/credential"))
+	assert.True(t, IsOnePasswordReference("op://dev/GitHub/api/token"))
+	assert.False(t, IsOnePasswordReference("op://dev/GitHub"))
+	assert.False(t, IsOnePasswordReference("op://dev//credential"))
+	assert.False(t, IsOnePasswordReference("dev/GitHub/credential"))
+}
@@ -0,0 +1,18 @@
+package secretprovider
+
+import (
+	"context"
+	"os/exec"
+)
+
+// Provider resolves secret values from an external secret store.
+type Provider interface {
+	// GetSecrets returns the values of the given secrets.
+	// Secrets that don't exist in the store are omitted from the result.
+	GetSecrets(ctx context.Context, names []string) (map[string]string, error)
+}
+
+// runCommand runs a command and returns its stdout. Replaced in tests.
+var runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).Output()
+}
@@ -0,0 +1,179 @@
+package servertest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Result is the outcome of a check of a suite. It passed if it has no errors.
+type Result struct {
+	Name   string
+	Errors []error
+}
+
+// Passed tells whether a check passed.
+func (r Result) Passed() bool {
+	return len(r.Errors) == 0
+}
+
+// session is the part of an MCP client session used by the checks. It's implemented by *mcp.ClientSession.
+type session interface {
+	ListTools(ctx context.Context, params *mcp.ListToolsParams) (*mcp.ListToolsResult, error)
+	CallTool(ctx context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error)
+}
+
+// Check runs the checks of a suite against a server session: the tools it lists, then each call.
+func Check(ctx context.Context, s session, suite Suite) []Result {
+	toolsResult := Result{Name: "tools"}
+	tools := map[string]*mcp.Tool{}
+	list, err := s.ListTools(ctx, &mcp.ListToolsParams{})
+	if err != nil {
+		toolsResult.Errors = append(toolsResult.Errors, fmt.Errorf("listing tools: %w", err))
+	} else {
+		for _, tool := range list.Tools {
+			tools[tool.Name] = tool
+		}
+		for _, name := range suite.Tools {
+			if _, ok := tools[name]; !ok {
+				toolsResult.Errors = append(toolsResult.Errors, fmt.Errorf("tool %s is not listed", name))
+			}
+		}
+	}
+
+	results := []Result{toolsResult}
+	for _, call := range suite.Calls {
+		results = append(results, checkCall(ctx, s, tools[call.Tool], call))
+	}
+
+	return results
+}
+
+func checkCall(ctx context.Context, s session, tool *mcp.Tool, call Call) Result {
+	result := Result{Name: call.Name}
+
+	arguments := call.Arguments
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	response, err := s.CallTool(ctx, &mcp.CallToolParams{
+		Name:      call.Tool,
+		Arguments: arguments,
+	})
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("calling tool %s: %w", call.Tool, err))
+		return result
+	}
+
+	result.Errors = checkResponse(tool, call.Expect, response)
+	return result
+}
+
+// checkResponse checks a tool call result against the expectations of a call.
+func checkResponse(tool *mcp.Tool, expect Expect, response *mcp.CallToolResult) []error {
+	var errs []error
+
+	text := responseText(response)
+	if response.IsError != expect.Error {
+		if response.IsError {
+			errs = append(errs, fmt.Errorf("result is an error: %s", text))
+		} else {
+			errs = append(errs, fmt.Errorf("result is not an error"))
+		}
+	}
+
+	for _, s := range expect.Contains {
+		if !strings.Contains(text, s) {
+			errs = append(errs, fmt.Errorf("text content doesn't contain %q", s))
+		}
+	}
+
+	if expect.Schema != nil {
+		instance, err := responseInstance(response, text)
+		if err != nil {
+			errs = append(errs, err)
+		} else if err := validate(expect.Schema, instance); err != nil {
+			errs = append(errs, fmt.Errorf("result doesn't match the schema: %w", err))
+		}
+	}
+
+	if expect.OutputSchema {
+		switch {
+		case tool == nil:
+			errs = append(errs, fmt.Errorf("tool is not listed, its output schema is unknown"))
+		case tool.OutputSchema == nil:
+			errs = append(errs, fmt.Errorf("tool %s has no output schema", tool.Name))
+		case response.StructuredContent == nil:
+			errs = append(errs, fmt.Errorf("result has no structured content"))
+		default:
+			if err := validate(tool.OutputSchema, response.StructuredContent); err != nil {
+				errs = append(errs, fmt.Errorf("structured content doesn't match the output schema: %w", err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// responseInstance returns what's validated against a schema: the structured content of the result,
+// or its text content, decoded as JSON.
+func responseInstance(response *mcp.CallToolResult, text string) (any, error) {
+	if response.StructuredContent != nil {
+		return roundTrip(response.StructuredContent)
+	}
+
+	var instance any
+	if err := json.Unmarshal([]byte(text), &instance); err != nil {
+		return nil, fmt.Errorf("result has no structured content and its text content is not JSON: %w", err)
+	}
+	return instance, nil
+}
+
+func responseText(response *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range response.Content {
+		if textContent, ok := content.(*mcp.TextContent); ok {
+			texts = append(texts, textContent.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// validate validates an instance against a JSON schema, given as any value that marshals to JSON.
+func validate(schema any, instance any) error {
+	buf, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("marshalling schema: %w", err)
+	}
+	var s jsonschema.Schema
+	if err := json.Unmarshal(buf, &s); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	resolved, err := s.Resolve(nil)
+	if err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+
+	instance, err = roundTrip(instance)
+	if err != nil {
+		return err
+	}
+	return resolved.Validate(instance)
+}
+
+// roundTrip turns a value into the types JSON decodes to, which the validator expects.
+func roundTrip(v any) (any, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded any
+	if err := json.Unmarshal(buf, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
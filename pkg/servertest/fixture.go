@@ -0,0 +1,248 @@
+package servertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+const (
+	// fixtureCallTimeout is how long each sample call can take while a fixture is recorded.
+	fixtureCallTimeout = 30 * time.Second
+	// redactedSecret replaces the values of the secrets in the recorded fixtures.
+	redactedSecret = "<redacted>"
+)
+
+// Fixture is a recording of the tools of a server and of a sample call to each of them,
+// meant to be replayed instead of running the server.
+type Fixture struct {
+	Server     string        `json:"server"`
+	Image      string        `json:"image,omitempty"`
+	RecordedAt time.Time     `json:"recordedAt"`
+	Tools      []*mcp.Tool   `json:"tools"`
+	Calls      []FixtureCall `json:"calls"`
+}
+
+// FixtureCall is a sample call to a tool, and what the server answered.
+type FixtureCall struct {
+	Tool      string              `json:"tool"`
+	Arguments map[string]any      `json:"arguments,omitempty"`
+	Result    *mcp.CallToolResult `json:"result,omitempty"`
+	Error     string              `json:"error,omitempty"`
+	// Skipped tells why the tool wasn't called.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// Record lists the tools of a running server and calls each of them with sample arguments generated
+// from its input schema. Only the tools annotated as read-only are called, unless callAll is set.
+// The values of the server's secrets are redacted from the recording.
+func Record(ctx context.Context, s session, serverConfig *catalog.ServerConfig, callAll bool) (*Fixture, error) {
+	list, err := s.ListTools(ctx, &mcp.ListToolsParams{})
+	if err != nil {
+		return nil, fmt.Errorf("listing tools: %w", err)
+	}
+	if len(list.Tools) == 0 {
+		return nil, fmt.Errorf("server %s listed no tools", serverConfig.Name)
+	}
+
+	fixture := &Fixture{
+		Server:     serverConfig.Name,
+		Image:      serverConfig.Spec.Image,
+		RecordedAt: time.Now().UTC(),
+		Tools:      list.Tools,
+		Calls:      []FixtureCall{},
+	}
+	for _, tool := range list.Tools {
+		call := FixtureCall{Tool: tool.Name}
+		if !callAll && (tool.Annotations == nil || !tool.Annotations.ReadOnlyHint) {
+			call.Skipped = "not annotated as read-only"
+			fixture.Calls = append(fixture.Calls, call)
+			continue
+		}
+
+		call.Arguments = sampleArguments(tool.InputSchema)
+		callCtx, cancel := context.WithTimeout(ctx, fixtureCallTimeout)
+		result, err := s.CallTool(callCtx, &mcp.CallToolParams{Name: tool.Name, Arguments: call.Arguments})
+		cancel()
+		if err != nil {
+			call.Error = err.Error()
+		} else {
+			call.Result = result
+		}
+		fixture.Calls = append(fixture.Calls, call)
+	}
+
+	var secrets []string
+	for _, secret := range serverConfig.Spec.Secrets {
+		if value := serverConfig.Secrets[secret.Name]; value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+	return fixture.redact(secrets)
+}
+
+// Suite turns the recorded calls into a suite that checks a server still lists the same tools,
+// and that the recorded calls still fail or succeed with results matching the output schemas of the tools.
+func (f *Fixture) Suite() Suite {
+	suite := Suite{Server: f.Server}
+	for _, tool := range f.Tools {
+		suite.Tools = append(suite.Tools, tool.Name)
+	}
+
+	for _, call := range f.Calls {
+		if call.Skipped != "" || call.Result == nil {
+			continue
+		}
+
+		outputSchema := slices.ContainsFunc(f.Tools, func(tool *mcp.Tool) bool {
+			return tool.Name == call.Tool && tool.OutputSchema != nil
+		})
+		suite.Calls = append(suite.Calls, Call{
+			Name:      call.Tool,
+			Tool:      call.Tool,
+			Arguments: call.Arguments,
+			Expect: Expect{
+				Error:        call.Result.IsError,
+				OutputSchema: outputSchema && !call.Result.IsError,
+			},
+		})
+	}
+
+	return suite
+}
+
+// redact returns a copy of the fixture where the values of the secrets are replaced.
+func (f *Fixture) redact(secrets []string) (*Fixture, error) {
+	buf, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Longer secrets first, in case a secret contains another one
+	slices.SortFunc(secrets, func(a, b string) int { return len(b) - len(a) })
+	for _, secret := range secrets {
+		quoted, err := json.Marshal(secret)
+		if err != nil {
+			return nil, err
+		}
+		buf = bytes.ReplaceAll(buf, quoted[1:len(quoted)-1], []byte(redactedSecret))
+	}
+
+	var redacted Fixture
+	if err := json.Unmarshal(buf, &redacted); err != nil {
+		return nil, err
+	}
+	return &redacted, nil
+}
+
+// WriteFixture writes a fixture as indented JSON.
+func WriteFixture(path string, fixture *Fixture) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(fixture); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// ReadFixture reads a fixture written by WriteFixture.
+func ReadFixture(path string) (*Fixture, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(buf, &fixture); err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// sampleArguments generates placeholder values for the required properties of an input schema.
+func sampleArguments(inputSchema any) map[string]any {
+	arguments := map[string]any{}
+
+	buf, err := json.Marshal(inputSchema)
+	if err != nil {
+		return arguments
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(buf, &schema); err != nil {
+		return arguments
+	}
+
+	if value, ok := sampleValue(&schema).(map[string]any); ok {
+		return value
+	}
+	return arguments
+}
+
+func sampleValue(schema *jsonschema.Schema) any {
+	switch {
+	case schema.Const != nil:
+		return *schema.Const
+	case len(schema.Default) > 0:
+		var value any
+		if err := json.Unmarshal(schema.Default, &value); err == nil {
+			return value
+		}
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	case len(schema.Examples) > 0:
+		return schema.Examples[0]
+	case len(schema.AnyOf) > 0:
+		return sampleValue(schema.AnyOf[0])
+	case len(schema.OneOf) > 0:
+		return sampleValue(schema.OneOf[0])
+	}
+
+	schemaType := schema.Type
+	if schemaType == "" && len(schema.Types) > 0 {
+		schemaType = schema.Types[0]
+	}
+	if schemaType == "" && len(schema.Properties) > 0 {
+		schemaType = "object"
+	}
+
+	switch schemaType {
+	case "integer", "number":
+		if schema.Minimum != nil {
+			return *schema.Minimum
+		}
+		return 1
+	case "boolean":
+		return false
+	case "null":
+		return nil
+	case "array":
+		if schema.Items != nil {
+			return []any{sampleValue(schema.Items)}
+		}
+		return []any{}
+	case "object":
+		object := map[string]any{}
+		for _, name := range schema.Required {
+			if property := schema.Properties[name]; property != nil {
+				object[name] = sampleValue(property)
+			} else {
+				object[name] = "example"
+			}
+		}
+		return object
+	}
+
+	return "example"
+}
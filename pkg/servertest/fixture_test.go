@@ -0,0 +1,142 @@
+package servertest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestRecord(t *testing.T) {
+	s := &fakeSession{
+		tools: []*mcp.Tool{
+			{
+				Name:        "get_me",
+				Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+				InputSchema: map[string]any{"type": "object"},
+			},
+			{
+				Name:        "search",
+				Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+				InputSchema: map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"query": map[string]any{"type": "string"}},
+					"required":   []any{"query"},
+				},
+				OutputSchema: map[string]any{"type": "object"},
+			},
+			{Name: "create_issue"},
+		},
+		results: map[string]*mcp.CallToolResult{
+			"get_me": {Content: []mcp.Content{&mcp.TextContent{Text: "authenticated with ghp_secret"}}},
+		},
+	}
+	serverConfig := &catalog.ServerConfig{
+		Name:    "github",
+		Spec:    catalog.Server{Image: "mcp/github", Secrets: []catalog.Secret{{Name: "github.token", Env: "GITHUB_TOKEN"}}},
+		Secrets: map[string]string{"github.token": "ghp_secret"},
+	}
+
+	fixture, err := Record(t.Context(), s, serverConfig, false)
+	require.NoError(t, err)
+	assert.Equal(t, "github", fixture.Server)
+	assert.Equal(t, "mcp/github", fixture.Image)
+	require.Len(t, fixture.Tools, 3)
+	require.Len(t, fixture.Calls, 3)
+
+	assert.Equal(t, "authenticated with <redacted>", fixture.Calls[0].Result.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, map[string]any{"query": "example"}, fixture.Calls[1].Arguments)
+	assert.Equal(t, "unknown tool", fixture.Calls[1].Error)
+	assert.Equal(t, "not annotated as read-only", fixture.Calls[2].Skipped)
+
+	fixture, err = Record(t.Context(), s, serverConfig, true)
+	require.NoError(t, err)
+	assert.Empty(t, fixture.Calls[2].Skipped)
+
+	_, err = Record(t.Context(), &fakeSession{}, serverConfig, false)
+	require.ErrorContains(t, err, "server github listed no tools")
+}
+
+func TestFixtureSuite(t *testing.T) {
+	fixture := &Fixture{
+		Server: "weather",
+		Tools: []*mcp.Tool{
+			{Name: "forecast", OutputSchema: map[string]any{"type": "object"}},
+			{Name: "alerts"},
+			{Name: "delete"},
+		},
+		Calls: []FixtureCall{
+			{Tool: "forecast", Arguments: map[string]any{"city": "example"}, Result: &mcp.CallToolResult{}},
+			{Tool: "alerts", Result: &mcp.CallToolResult{IsError: true}},
+			{Tool: "delete", Skipped: "not annotated as read-only"},
+		},
+	}
+
+	suite := fixture.Suite()
+	assert.Equal(t, "weather", suite.Server)
+	assert.Equal(t, []string{"forecast", "alerts", "delete"}, suite.Tools)
+	assert.Equal(t, []Call{
+		{Name: "forecast", Tool: "forecast", Arguments: map[string]any{"city": "example"}, Expect: Expect{OutputSchema: true}},
+		{Name: "alerts", Tool: "alerts", Expect: Expect{Error: true}},
+	}, suite.Calls)
+}
+
+func TestSampleArguments(t *testing.T) {
+	arguments := sampleArguments(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query":  map[string]any{"type": "string"},
+			"limit":  map[string]any{"type": "integer", "minimum": 5},
+			"order":  map[string]any{"type": "string", "enum": []any{"asc", "desc"}},
+			"safe":   map[string]any{"type": "boolean", "default": true},
+			"labels": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"filter": map[string]any{
+				"properties": map[string]any{"since": map[string]any{"type": "string"}},
+				"required":   []any{"since"},
+			},
+			"optional": map[string]any{"type": "string"},
+		},
+		"required": []any{"query", "limit", "order", "safe", "labels", "filter"},
+	})
+
+	assert.Equal(t, map[string]any{
+		"query":  "example",
+		"limit":  float64(5),
+		"order":  "asc",
+		"safe":   true,
+		"labels": []any{"example"},
+		"filter": map[string]any{"since": "example"},
+	}, arguments)
+
+	assert.Empty(t, sampleArguments(nil))
+	assert.Empty(t, sampleArguments(map[string]any{"type": "object"}))
+}
+
+func TestWriteReadFixture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fetch.fixture.json")
+	fixture := &Fixture{
+		Server: "fetch",
+		Tools:  []*mcp.Tool{{Name: "fetch", Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true}}},
+		Calls: []FixtureCall{{
+			Tool:      "fetch",
+			Arguments: map[string]any{"url": "<redacted>"},
+			Result:    &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "page"}}},
+		}},
+	}
+	require.NoError(t, WriteFixture(path, fixture))
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), `"url": "<redacted>"`)
+
+	read, err := ReadFixture(path)
+	require.NoError(t, err)
+	assert.Equal(t, "fetch", read.Server)
+	assert.True(t, read.Tools[0].Annotations.ReadOnlyHint)
+	assert.Equal(t, "page", read.Calls[0].Result.Content[0].(*mcp.TextContent).Text)
+}
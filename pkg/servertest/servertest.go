@@ -0,0 +1,208 @@
+// Package servertest runs conformance tests against an MCP server: it starts the server the way the gateway does,
+// calls its tools and checks the shape of the results.
+//
+// Server authors can run a suite from a Go test in CI:
+//
+//	func TestServer(t *testing.T) {
+//		servertest.RunFile(t, "testdata/suite.yaml")
+//	}
+package servertest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/flags"
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/gateway"
+)
+
+// defaultTimeout is how long a suite can take, server start included, unless it says otherwise.
+const defaultTimeout = 2 * time.Minute
+
+// Suite is a YAML spec of tool calls, and of the expected shape of their results.
+type Suite struct {
+	// Server is the name of the server in the catalog.
+	Server string `yaml:"server"`
+	// Catalogs are the catalog files or URLs the server is looked up in. Defaults to the configured catalogs.
+	Catalogs []string `yaml:"catalogs,omitempty"`
+	// Config of the server, eg. {"github": {"owner": "docker"}}.
+	Config map[string]any `yaml:"config,omitempty"`
+	// Secrets of the server. Values can reference environment variables, eg. $GITHUB_TOKEN.
+	Secrets map[string]string `yaml:"secrets,omitempty"`
+	// Tools that the server must list.
+	Tools []string `yaml:"tools,omitempty"`
+	// Timeout of the whole suite, eg. 5m.
+	Timeout string `yaml:"timeout,omitempty"`
+	Calls   []Call `yaml:"calls"`
+}
+
+// Call is a tool call of a suite.
+type Call struct {
+	// Name of the test, defaults to the name of the tool.
+	Name      string         `yaml:"name,omitempty"`
+	Tool      string         `yaml:"tool"`
+	Arguments map[string]any `yaml:"arguments,omitempty"`
+	Expect    Expect         `yaml:"expect,omitempty"`
+}
+
+// Expect describes the expected shape of a tool call result.
+type Expect struct {
+	// Error is whether the result is expected to be an error.
+	Error bool `yaml:"error,omitempty"`
+	// Schema is a JSON schema the structured content of the result, or its JSON text content, must validate against.
+	Schema map[string]any `yaml:"schema,omitempty"`
+	// Contains lists strings the text content of the result must contain.
+	Contains []string `yaml:"contains,omitempty"`
+	// OutputSchema checks the structured content of the result against the output schema of the tool.
+	OutputSchema bool `yaml:"output_schema,omitempty"`
+}
+
+// ReadSuite reads a suite from a YAML file.
+func ReadSuite(path string) (Suite, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return Suite{}, err
+	}
+
+	var suite Suite
+	if err := yaml.Unmarshal(buf, &suite); err != nil {
+		return Suite{}, fmt.Errorf("parsing suite %s: %w", path, err)
+	}
+	if err := suite.validate(); err != nil {
+		return Suite{}, fmt.Errorf("invalid suite %s: %w", path, err)
+	}
+
+	return suite, nil
+}
+
+func (s *Suite) validate() error {
+	if s.Server == "" {
+		return fmt.Errorf("server is required")
+	}
+	if s.Timeout != "" {
+		if _, err := time.ParseDuration(s.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+	}
+	for i := range s.Calls {
+		call := &s.Calls[i]
+		if call.Tool == "" {
+			return fmt.Errorf("call %d has no tool", i+1)
+		}
+		if call.Name == "" {
+			call.Name = call.Tool
+		}
+	}
+	return nil
+}
+
+func (s *Suite) timeout() time.Duration {
+	if timeout, err := time.ParseDuration(s.Timeout); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultTimeout
+}
+
+// RunFile reads a suite from a YAML file and runs it.
+func RunFile(t *testing.T, path string) {
+	t.Helper()
+
+	suite, err := ReadSuite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Run(t, suite)
+}
+
+// Run starts the server of a suite, with the local Docker engine, and runs each call as a subtest.
+func Run(t *testing.T, suite Suite) {
+	t.Helper()
+
+	if err := suite.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), suite.timeout())
+	defer cancel()
+
+	dockerClient, err := newDockerClient()
+	if err != nil {
+		t.Fatalf("creating docker client: %v", err)
+	}
+
+	serverConfig, err := suite.serverConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, stop, err := gateway.StartServer(ctx, gateway.Options{Cpus: 1, Memory: "2Gb"}, dockerClient, serverConfig)
+	if err != nil {
+		t.Fatalf("starting server %s: %v", suite.Server, err)
+	}
+	defer stop()
+
+	for _, result := range Check(ctx, client.Session(), suite) {
+		t.Run(result.Name, func(t *testing.T) {
+			for _, err := range result.Errors {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// serverConfig looks up the server of a suite in the catalogs and configures it.
+func (s *Suite) serverConfig(ctx context.Context) (*catalog.ServerConfig, error) {
+	var (
+		servers catalog.Catalog
+		err     error
+	)
+	if len(s.Catalogs) > 0 {
+		servers, err = catalog.ReadFrom(ctx, s.Catalogs)
+	} else {
+		servers, err = catalog.Get(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading catalog: %w", err)
+	}
+
+	server, ok := servers.Servers[s.Server]
+	if !ok {
+		return nil, fmt.Errorf("server %s not found in catalog", s.Server)
+	}
+
+	secrets := map[string]string{}
+	for name, value := range s.Secrets {
+		secrets[name] = os.ExpandEnv(value)
+	}
+
+	config := s.Config
+	if config == nil {
+		config = map[string]any{}
+	}
+
+	return &catalog.ServerConfig{
+		Name:    s.Server,
+		Spec:    server,
+		Config:  config,
+		Secrets: secrets,
+	}, nil
+}
+
+func newDockerClient() (docker.Client, error) {
+	dockerCli, err := command.NewDockerCli()
+	if err != nil {
+		return nil, err
+	}
+	if err := dockerCli.Initialize(flags.NewClientOptions()); err != nil {
+		return nil, err
+	}
+	return docker.NewClient(dockerCli), nil
+}
@@ -0,0 +1,120 @@
+package servertest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSession struct {
+	tools   []*mcp.Tool
+	results map[string]*mcp.CallToolResult
+}
+
+func (f *fakeSession) ListTools(context.Context, *mcp.ListToolsParams) (*mcp.ListToolsResult, error) {
+	return &mcp.ListToolsResult{Tools: f.tools}, nil
+}
+
+func (f *fakeSession) CallTool(_ context.Context, params *mcp.CallToolParams) (*mcp.CallToolResult, error) {
+	result, ok := f.results[params.Name]
+	if !ok {
+		return nil, errors.New("unknown tool")
+	}
+	return result, nil
+}
+
+func TestReadSuite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "suite.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`server: weather
+tools: [forecast]
+calls:
+  - tool: forecast
+    arguments:
+      city: Paris
+    expect:
+      contains: [Paris]
+      schema:
+        type: object
+        required: [temperature]
+`), 0o644))
+
+	suite, err := ReadSuite(path)
+	require.NoError(t, err)
+	assert.Equal(t, "weather", suite.Server)
+	require.Len(t, suite.Calls, 1)
+	assert.Equal(t, "forecast", suite.Calls[0].Name)
+	assert.Equal(t, map[string]any{"city": "Paris"}, suite.Calls[0].Arguments)
+	assert.Equal(t, []string{"Paris"}, suite.Calls[0].Expect.Contains)
+
+	require.NoError(t, os.WriteFile(path, []byte(`calls: []`), 0o644))
+	_, err = ReadSuite(path)
+	require.ErrorContains(t, err, "server is required")
+}
+
+func TestCheck(t *testing.T) {
+	s := &fakeSession{
+		tools: []*mcp.Tool{{
+			Name: "forecast",
+			OutputSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"temperature": map[string]any{"type": "number"}},
+				"required":   []any{"temperature"},
+			},
+		}},
+		results: map[string]*mcp.CallToolResult{
+			"forecast": {
+				Content:           []mcp.Content{&mcp.TextContent{Text: `{"temperature":21,"city":"Paris"}`}},
+				StructuredContent: map[string]any{"temperature": 21, "city": "Paris"},
+			},
+			"broken": {
+				IsError: true,
+				Content: []mcp.Content{&mcp.TextContent{Text: "boom"}},
+			},
+		},
+	}
+
+	results := Check(t.Context(), s, Suite{
+		Server: "weather",
+		Tools:  []string{"forecast", "missing"},
+		Calls: []Call{
+			{Name: "ok", Tool: "forecast", Expect: Expect{
+				Contains:     []string{"Paris"},
+				Schema:       map[string]any{"type": "object", "required": []any{"city"}},
+				OutputSchema: true,
+			}},
+			{Name: "wrong shape", Tool: "forecast", Expect: Expect{
+				Schema: map[string]any{"type": "object", "required": []any{"humidity"}},
+			}},
+			{Name: "expected error", Tool: "broken", Expect: Expect{Error: true, Contains: []string{"boom"}}},
+			{Name: "unexpected error", Tool: "broken"},
+		},
+	})
+
+	require.Len(t, results, 5)
+	assert.Equal(t, "tools", results[0].Name)
+	require.Len(t, results[0].Errors, 1)
+	assert.EqualError(t, results[0].Errors[0], "tool missing is not listed")
+
+	assert.True(t, results[1].Passed(), "%v", results[1].Errors)
+	assert.False(t, results[2].Passed())
+	assert.ErrorContains(t, results[2].Errors[0], "doesn't match the schema")
+	assert.True(t, results[3].Passed(), "%v", results[3].Errors)
+	assert.False(t, results[4].Passed())
+	assert.EqualError(t, results[4].Errors[0], "result is an error: boom")
+}
+
+func TestCheckResponseTextSchema(t *testing.T) {
+	response := &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: `[1, 2]`}}}
+	assert.Empty(t, checkResponse(nil, Expect{Schema: map[string]any{"type": "array"}}, response))
+
+	response = &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: `not json`}}}
+	errs := checkResponse(nil, Expect{Schema: map[string]any{"type": "array"}}, response)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "text content is not JSON")
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type launchd struct {
+	dir    string
+	logDir string
+	// domain is system for system services, gui/<uid> for user services.
+	domain string
+	system bool
+	run    runFunc
+}
+
+func (m *launchd) label(name string) string {
+	return "com.docker." + name
+}
+
+func (m *launchd) path(name string) string {
+	return filepath.Join(m.dir, m.label(name)+".plist")
+}
+
+func (m *launchd) launchctl(ctx context.Context, args ...string) ([]byte, error) {
+	output, err := m.run(ctx, "launchctl", args...)
+	if err != nil {
+		return output, fmt.Errorf("launchctl %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return output, nil
+}
+
+func (m *launchd) Install(ctx context.Context, spec Spec) (string, error) {
+	if err := checkName(spec.Name); err != nil {
+		return "", err
+	}
+	path := m.path(spec.Name)
+	logPath := filepath.Join(m.logDir, "docker-"+spec.Name+".log")
+	if err := writeDefinition(m.dir, path, []byte(launchdPlist(spec, m.label(spec.Name), logPath, m.system))); err != nil {
+		return "", fmt.Errorf("writing plist %s: %w", path, err)
+	}
+
+	// Unload an older version of the service, if it's loaded
+	_, _ = m.launchctl(ctx, "bootout", m.domain+"/"+m.label(spec.Name))
+	if _, err := m.launchctl(ctx, "bootstrap", m.domain, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (m *launchd) Uninstall(ctx context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	path := m.path(name)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return notInstalled(name)
+	}
+
+	// The service might not be loaded
+	_, _ = m.launchctl(ctx, "bootout", m.domain+"/"+m.label(name))
+	return os.Remove(path)
+}
+
+func (m *launchd) Status(ctx context.Context, name string) (Status, error) {
+	if err := checkName(name); err != nil {
+		return Status{}, err
+	}
+	path := m.path(name)
+	definition, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Status{Path: path}, nil
+	}
+	if err != nil {
+		return Status{}, err
+	}
+
+	state := "not loaded"
+	if output, err := m.launchctl(ctx, "print", m.domain+"/"+m.label(name)); err == nil {
+		state = "loaded"
+		for _, line := range strings.Split(string(output), "\n") {
+			if value, found := strings.CutPrefix(strings.TrimSpace(line), "state = "); found {
+				state = value
+				break
+			}
+		}
+	}
+
+	return Status{
+		Installed: true,
+		Path:      path,
+		State:     state,
+		Port:      portOf(definition),
+	}, nil
+}
+
+// launchdPlist renders the property list of a service. It's started at load and restarted when it fails.
+func launchdPlist(spec Spec, label, logPath string, system bool) string {
+	var plist bytes.Buffer
+	plist.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	writeKey(&plist, 1, "Label")
+	writeString(&plist, 1, label)
+
+	writeKey(&plist, 1, "ProgramArguments")
+	plist.WriteString("\t<array>\n")
+	for _, arg := range append([]string{spec.Executable}, spec.Args...) {
+		writeString(&plist, 2, arg)
+	}
+	plist.WriteString("\t</array>\n")
+
+	if len(spec.Env) > 0 {
+		writeKey(&plist, 1, "EnvironmentVariables")
+		plist.WriteString("\t<dict>\n")
+		for _, name := range slices.Sorted(maps.Keys(spec.Env)) {
+			writeKey(&plist, 2, name)
+			writeString(&plist, 2, spec.Env[name])
+		}
+		plist.WriteString("\t</dict>\n")
+	}
+
+	if system && spec.User != "" {
+		writeKey(&plist, 1, "UserName")
+		writeString(&plist, 1, spec.User)
+	}
+
+	writeKey(&plist, 1, "RunAtLoad")
+	plist.WriteString("\t<true/>\n")
+	writeKey(&plist, 1, "KeepAlive")
+	plist.WriteString("\t<dict>\n")
+	writeKey(&plist, 2, "SuccessfulExit")
+	plist.WriteString("\t\t<false/>\n")
+	plist.WriteString("\t</dict>\n")
+	writeKey(&plist, 1, "ThrottleInterval")
+	plist.WriteString("\t<integer>5</integer>\n")
+	writeKey(&plist, 1, "StandardOutPath")
+	writeString(&plist, 1, logPath)
+	writeKey(&plist, 1, "StandardErrorPath")
+	writeString(&plist, 1, logPath)
+
+	plist.WriteString("</dict>\n</plist>\n")
+	return plist.String()
+}
+
+func writeKey(plist *bytes.Buffer, indent int, key string) {
+	writeElement(plist, indent, "key", key)
+}
+
+func writeString(plist *bytes.Buffer, indent int, value string) {
+	writeElement(plist, indent, "string", value)
+}
+
+func writeElement(plist *bytes.Buffer, indent int, element, value string) {
+	plist.WriteString(strings.Repeat("\t", indent))
+	fmt.Fprintf(plist, "<%s>", element)
+	_ = xml.EscapeText(plist, []byte(value))
+	fmt.Fprintf(plist, "</%s>\n", element)
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+)
+
+// DefaultName is the name of the service when only one gateway runs as a service.
+const DefaultName = "mcp-gateway"
+
+// Spec describes a gateway that runs as a service.
+type Spec struct {
+	// Name tells the services apart, so that several gateways can run, eg. one per profile.
+	Name string
+	// Executable and Args run the gateway.
+	Executable string
+	Args       []string
+	Env        map[string]string
+	// User runs a system service. User services run as the user who installs them.
+	User string
+}
+
+// Status is the state of an installed service, as reported by the service manager.
+type Status struct {
+	Installed bool
+	Path      string
+	// State is the state reported by systemd or launchd, like active, failed or running.
+	State string
+	// Port is the port the gateway listens on, read from the service definition.
+	Port int
+}
+
+// Manager installs the gateway as a service of the host's service manager.
+type Manager interface {
+	Install(ctx context.Context, spec Spec) (string, error)
+	Uninstall(ctx context.Context, name string) error
+	Status(ctx context.Context, name string) (Status, error)
+}
+
+// runFunc runs a command of the service manager, like systemctl or launchctl, and returns its combined output.
+type runFunc func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+// NewManager returns the service manager of the host: systemd on Linux and launchd on macOS.
+// System services start at boot, user services when the user logs in.
+func NewManager(system bool) (Manager, error) {
+	switch runtime.GOOS {
+	case "linux":
+		dir := "/etc/systemd/system"
+		if !system {
+			configDir, err := os.UserConfigDir()
+			if err != nil {
+				return nil, err
+			}
+			dir = configDir + "/systemd/user"
+		}
+		return &systemd{dir: dir, system: system, run: runCommand}, nil
+	case "darwin":
+		dir := "/Library/LaunchDaemons"
+		logDir := "/Library/Logs"
+		domain := "system"
+		if !system {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			dir = home + "/Library/LaunchAgents"
+			logDir = home + "/Library/Logs"
+			domain = "gui/" + strconv.Itoa(os.Getuid())
+		}
+		return &launchd{dir: dir, logDir: logDir, domain: domain, system: system, run: runCommand}, nil
+	default:
+		return nil, fmt.Errorf("services are only supported on Linux, with systemd, and macOS, with launchd, not on %s", runtime.GOOS)
+	}
+}
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// checkName rejects the names that can't be part of the file name of a unit or of a launchd label.
+func checkName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid service name %q, use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+func notInstalled(name string) error {
+	return fmt.Errorf("service %s is not installed", name)
+}
+
+// portArg matches the --port of the gateway, in a unit or in a plist.
+var portArg = regexp.MustCompile(`--port(?:[= ]|</string>\s*<string>)(\d+)`)
+
+// portOf reads the port of the gateway from the definition of a service.
+func portOf(definition []byte) int {
+	match := portArg.FindSubmatch(definition)
+	if match == nil {
+		return 0
+	}
+	port, _ := strconv.Atoi(string(match[1]))
+	return port
+}
+
+func writeDefinition(dir, path string, content []byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	// The definition can hold the auth token of the gateway
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
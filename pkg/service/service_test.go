@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSpec = Spec{
+	Name:       "mcp-gateway",
+	Executable: "/home/jane/.docker/cli-plugins/docker-mcp",
+	Args:       []string{"gateway", "run", "--transport", "streaming", "--port", "8811", "--profile", "dev tools"},
+	Env:        map[string]string{"PATH": "/usr/local/bin:/usr/bin", "MCP_GATEWAY_AUTH_TOKEN": "s3cr$t"},
+	User:       "jane",
+}
+
+type recorder struct {
+	commands []string
+	outputs  map[string]string
+}
+
+func (r *recorder) run(_ context.Context, name string, args ...string) ([]byte, error) {
+	command := name + " " + strings.Join(args, " ")
+	r.commands = append(r.commands, command)
+	return []byte(r.outputs[command]), nil
+}
+
+func TestSystemdUnit(t *testing.T) {
+	assert.Equal(t, `[Unit]
+Description=Docker MCP Gateway (mcp-gateway)
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=simple
+ExecStart=/home/jane/.docker/cli-plugins/docker-mcp gateway run --transport streaming --port 8811 --profile "dev tools"
+Environment="MCP_GATEWAY_AUTH_TOKEN=s3cr$$t"
+Environment=PATH=/usr/local/bin:/usr/bin
+User=jane
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=multi-user.target
+`, systemdUnit(testSpec, true))
+
+	assert.Contains(t, systemdUnit(testSpec, false), "WantedBy=default.target\n")
+	assert.NotContains(t, systemdUnit(testSpec, false), "User=")
+}
+
+func TestSystemdQuote(t *testing.T) {
+	assert.Equal(t, "--port", systemdQuote("--port"))
+	assert.Equal(t, `"dev tools"`, systemdQuote("dev tools"))
+	assert.Equal(t, `"100%%"`, systemdQuote("100%"))
+	assert.Equal(t, `"say \"hi\""`, systemdQuote(`say "hi"`))
+	assert.Equal(t, `""`, systemdQuote(""))
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	plist := launchdPlist(testSpec, "com.docker.mcp-gateway", "/Users/jane/Library/Logs/docker-mcp-gateway.log", false)
+
+	assert.Contains(t, plist, "\t<key>Label</key>\n\t<string>com.docker.mcp-gateway</string>\n")
+	assert.Contains(t, plist, "\t\t<string>--profile</string>\n\t\t<string>dev tools</string>\n")
+	assert.Contains(t, plist, "\t\t<key>MCP_GATEWAY_AUTH_TOKEN</key>\n\t\t<string>s3cr$t</string>\n")
+	assert.Contains(t, plist, "<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n")
+	assert.NotContains(t, plist, "UserName")
+	assert.Equal(t, 8811, portOf([]byte(plist)))
+
+	assert.Contains(t, launchdPlist(testSpec, "com.docker.mcp-gateway", "/Library/Logs/docker-mcp-gateway.log", true), "<key>UserName</key>\n\t<string>jane</string>\n")
+}
+
+func TestSystemdInstallStatusUninstall(t *testing.T) {
+	dir := t.TempDir()
+	r := &recorder{outputs: map[string]string{"systemctl --user is-active docker-mcp-gateway.service": "active\n"}}
+	manager := &systemd{dir: dir, run: r.run}
+
+	status, err := manager.Status(t.Context(), "mcp-gateway")
+	require.NoError(t, err)
+	assert.False(t, status.Installed)
+
+	path, err := manager.Install(t.Context(), testSpec)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "docker-mcp-gateway.service"), path)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	status, err = manager.Status(t.Context(), "mcp-gateway")
+	require.NoError(t, err)
+	assert.Equal(t, Status{Installed: true, Path: path, State: "active", Port: 8811}, status)
+
+	require.NoError(t, manager.Uninstall(t.Context(), "mcp-gateway"))
+	assert.NoFileExists(t, path)
+	require.ErrorContains(t, manager.Uninstall(t.Context(), "mcp-gateway"), "service mcp-gateway is not installed")
+
+	assert.Equal(t, []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable docker-mcp-gateway.service",
+		"systemctl --user restart docker-mcp-gateway.service",
+		"systemctl --user is-active docker-mcp-gateway.service",
+		"systemctl --user disable --now docker-mcp-gateway.service",
+		"systemctl --user daemon-reload",
+	}, r.commands)
+}
+
+func TestLaunchdInstallStatusUninstall(t *testing.T) {
+	dir := t.TempDir()
+	r := &recorder{outputs: map[string]string{"launchctl print gui/501/com.docker.mcp-gateway": "gui/501/com.docker.mcp-gateway = {\n\tactive count = 1\n\tstate = running\n}\n"}}
+	manager := &launchd{dir: dir, logDir: dir, domain: "gui/501", run: r.run}
+
+	path, err := manager.Install(t.Context(), testSpec)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "com.docker.mcp-gateway.plist"), path)
+
+	status, err := manager.Status(t.Context(), "mcp-gateway")
+	require.NoError(t, err)
+	assert.Equal(t, Status{Installed: true, Path: path, State: "running", Port: 8811}, status)
+
+	require.NoError(t, manager.Uninstall(t.Context(), "mcp-gateway"))
+	assert.NoFileExists(t, path)
+
+	assert.Equal(t, []string{
+		"launchctl bootout gui/501/com.docker.mcp-gateway",
+		"launchctl bootstrap gui/501 " + path,
+		"launchctl print gui/501/com.docker.mcp-gateway",
+		"launchctl bootout gui/501/com.docker.mcp-gateway",
+	}, r.commands)
+}
+
+func TestInvalidName(t *testing.T) {
+	manager := &systemd{dir: t.TempDir(), run: (&recorder{}).run}
+
+	_, err := manager.Install(t.Context(), Spec{Name: "../etc/passwd"})
+	require.ErrorContains(t, err, "invalid service name")
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type systemd struct {
+	dir    string
+	system bool
+	run    runFunc
+}
+
+func (m *systemd) unitName(name string) string {
+	return "docker-" + name + ".service"
+}
+
+func (m *systemd) path(name string) string {
+	return filepath.Join(m.dir, m.unitName(name))
+}
+
+func (m *systemd) systemctl(ctx context.Context, args ...string) ([]byte, error) {
+	if !m.system {
+		args = append([]string{"--user"}, args...)
+	}
+	output, err := m.run(ctx, "systemctl", args...)
+	if err != nil {
+		return output, fmt.Errorf("systemctl %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return output, nil
+}
+
+func (m *systemd) Install(ctx context.Context, spec Spec) (string, error) {
+	if err := checkName(spec.Name); err != nil {
+		return "", err
+	}
+	path := m.path(spec.Name)
+	if err := writeDefinition(m.dir, path, []byte(systemdUnit(spec, m.system))); err != nil {
+		return "", fmt.Errorf("writing unit %s: %w", path, err)
+	}
+
+	unit := m.unitName(spec.Name)
+	if _, err := m.systemctl(ctx, "daemon-reload"); err != nil {
+		return "", err
+	}
+	if _, err := m.systemctl(ctx, "enable", unit); err != nil {
+		return "", err
+	}
+	// Restart, in case an older version of the service was running
+	if _, err := m.systemctl(ctx, "restart", unit); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (m *systemd) Uninstall(ctx context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	path := m.path(name)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return notInstalled(name)
+	}
+
+	if _, err := m.systemctl(ctx, "disable", "--now", m.unitName(name)); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	_, err := m.systemctl(ctx, "daemon-reload")
+	return err
+}
+
+func (m *systemd) Status(ctx context.Context, name string) (Status, error) {
+	if err := checkName(name); err != nil {
+		return Status{}, err
+	}
+	path := m.path(name)
+	definition, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Status{Path: path}, nil
+	}
+	if err != nil {
+		return Status{}, err
+	}
+
+	// is-active exits with an error when the unit isn't active, but still prints its state
+	output, _ := m.systemctl(ctx, "is-active", m.unitName(name))
+	state, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+
+	return Status{
+		Installed: true,
+		Path:      path,
+		State:     state,
+		Port:      portOf(definition),
+	}, nil
+}
+
+// systemdUnit renders the unit of a service. It's restarted when it fails.
+func systemdUnit(spec Spec, system bool) string {
+	var unit strings.Builder
+	unit.WriteString("[Unit]\n")
+	fmt.Fprintf(&unit, "Description=Docker MCP Gateway (%s)\n", spec.Name)
+	unit.WriteString("Wants=network-online.target\n")
+	unit.WriteString("After=network-online.target\n")
+
+	unit.WriteString("\n[Service]\n")
+	unit.WriteString("Type=simple\n")
+	args := make([]string, 0, len(spec.Args)+1)
+	for _, arg := range append([]string{spec.Executable}, spec.Args...) {
+		args = append(args, systemdQuote(arg))
+	}
+	fmt.Fprintf(&unit, "ExecStart=%s\n", strings.Join(args, " "))
+	for _, name := range slices.Sorted(maps.Keys(spec.Env)) {
+		fmt.Fprintf(&unit, "Environment=%s\n", systemdQuote(name+"="+spec.Env[name]))
+	}
+	if system && spec.User != "" {
+		fmt.Fprintf(&unit, "User=%s\n", spec.User)
+	}
+	unit.WriteString("Restart=on-failure\n")
+	unit.WriteString("RestartSec=5\n")
+
+	unit.WriteString("\n[Install]\n")
+	if system {
+		unit.WriteString("WantedBy=multi-user.target\n")
+	} else {
+		unit.WriteString("WantedBy=default.target\n")
+	}
+
+	return unit.String()
+}
+
+// systemdQuote quotes a word of a unit, escaping the characters systemd would expand.
+func systemdQuote(word string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$").Replace(word)
+	if escaped == word && word != "" && !strings.ContainsAny(word, " \t'") {
+		return word
+	}
+	return `"` + escaped + `"`
+}
@@ -0,0 +1,152 @@
+package signatures
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/version"
+)
+
+// ErrNotSigned is returned when an artifact has no signature at all.
+var ErrNotSigned = errors.New("no signatures found")
+
+// SignWithKey signs an artifact with a cosign private key, the way `cosign sign --key --tlog-upload=false` does:
+// the signature is pushed to the sha256-<digest>.sig tag of the artifact's repository, next to the signatures that
+// are already there, so that it can be verified with `cosign verify --key --insecure-ignore-tlog`.
+func SignWithKey(ctx context.Context, digest name.Digest, privateKey []byte, password []byte) error {
+	signer, err := cosign.LoadPrivateKey(privateKey, password)
+	if err != nil {
+		return fmt.Errorf("loading private key: %w", err)
+	}
+
+	sigPayload, err := payload.Cosign{Image: digest}.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("creating signature payload: %w", err)
+	}
+	sig, err := signer.SignMessage(bytes.NewReader(sigPayload))
+	if err != nil {
+		return fmt.Errorf("signing: %w", err)
+	}
+
+	ociSig, err := static.NewSignature(sigPayload, base64.StdEncoding.EncodeToString(sig))
+	if err != nil {
+		return err
+	}
+	annotations, err := ociSig.Annotations()
+	if err != nil {
+		return err
+	}
+	mediaType, err := ociSig.MediaType()
+	if err != nil {
+		return err
+	}
+
+	registryOpts := registryClientOpts(ctx)
+	tag, err := ociremote.SignatureTag(digest, registryOpts...)
+	if err != nil {
+		return err
+	}
+	sigs, err := ociremote.Signatures(tag, registryOpts...)
+	if err != nil {
+		return fmt.Errorf("reading existing signatures: %w", err)
+	}
+
+	img, err := mutate.Append(sigs, mutate.Addendum{
+		Layer:       ociSig,
+		Annotations: annotations,
+		MediaType:   mediaType,
+	})
+	if err != nil {
+		return err
+	}
+	if err := remote.Write(tag, img, remoteOpts(ctx)...); err != nil {
+		return fmt.Errorf("pushing signature: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyWithKey verifies that an artifact was signed with the private key of a cosign public key, ignoring the
+// transparency log. It returns ErrNotSigned if the artifact has no signatures.
+func VerifyWithKey(ctx context.Context, digest name.Digest, publicKey []byte) error {
+	pubKey, err := cryptoutils.UnmarshalPEMToPublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("pem to public key: %w", err)
+	}
+	sigVerifier, err := signature.LoadVerifier(pubKey, crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("loading public key: %w", err)
+	}
+
+	h, err := v1.NewHash(digest.Identifier())
+	if err != nil {
+		return err
+	}
+
+	co := &cosign.CheckOpts{
+		RegistryClientOpts: registryClientOpts(ctx),
+		SigVerifier:        sigVerifier,
+		ClaimVerifier:      cosign.SimpleClaimVerifier,
+		IgnoreTlog:         true,
+	}
+
+	tag, err := ociremote.SignatureTag(digest, co.RegistryClientOpts...)
+	if err != nil {
+		return err
+	}
+	sigs, err := ociremote.Signatures(tag, co.RegistryClientOpts...)
+	if err != nil {
+		return err
+	}
+	sl, err := sigs.Get()
+	if err != nil {
+		return err
+	}
+	if len(sl) == 0 {
+		return ErrNotSigned
+	}
+
+	// One valid signature is enough, others may have been made with other keys
+	var errs []error
+	for _, sig := range sl {
+		sig, err := static.Copy(sig)
+		if err != nil {
+			return err
+		}
+		if _, err := cosign.VerifyImageSignature(ctx, sig, h, co); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no valid signature: %w", errors.Join(errs...))
+}
+
+func registryClientOpts(ctx context.Context) []ociremote.Option {
+	return []ociremote.Option{ociremote.WithRemoteOptions(remoteOpts(ctx)...)}
+}
+
+func remoteOpts(ctx context.Context) []remote.Option {
+	return []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithUserAgent(version.UserAgent()),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+	}
+}
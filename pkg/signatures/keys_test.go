@@ -0,0 +1,64 @@
+package signatures
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pushRandomImage pushes an image to a local registry and returns its digest.
+func pushRandomImage(t *testing.T) name.Digest {
+	t.Helper()
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+
+	ref, err := name.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/test/catalog:latest")
+	require.NoError(t, err)
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+
+	hash, err := img.Digest()
+	require.NoError(t, err)
+	return ref.Context().Digest(hash.String())
+}
+
+func generateKeyPair(t *testing.T, password string) *cosign.KeysBytes {
+	t.Helper()
+
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte(password), nil })
+	require.NoError(t, err)
+	return keys
+}
+
+func TestSignAndVerifyWithKey(t *testing.T) {
+	digest := pushRandomImage(t)
+	keys := generateKeyPair(t, "secret")
+	otherKeys := generateKeyPair(t, "")
+
+	err := VerifyWithKey(t.Context(), digest, keys.PublicBytes)
+	require.ErrorIs(t, err, ErrNotSigned)
+
+	require.Error(t, SignWithKey(t.Context(), digest, keys.PrivateBytes, []byte("wrong")))
+	require.NoError(t, SignWithKey(t.Context(), digest, keys.PrivateBytes, []byte("secret")))
+
+	require.NoError(t, VerifyWithKey(t.Context(), digest, keys.PublicBytes))
+	err = VerifyWithKey(t.Context(), digest, otherKeys.PublicBytes)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotSigned)
+
+	// Signatures from other keys are kept
+	require.NoError(t, SignWithKey(t.Context(), digest, otherKeys.PrivateBytes, nil))
+	require.NoError(t, VerifyWithKey(t.Context(), digest, keys.PublicBytes))
+	require.NoError(t, VerifyWithKey(t.Context(), digest, otherKeys.PublicBytes))
+}
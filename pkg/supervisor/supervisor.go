@@ -0,0 +1,240 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+const (
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = time.Minute
+	// DefaultStableAfter is how long the gateway must run for its backoff to be reset.
+	DefaultStableAfter = 5 * time.Minute
+	// stopTimeout is how long the gateway has to stop gracefully before it's killed.
+	stopTimeout = 10 * time.Second
+)
+
+type Options struct {
+	// Command runs the gateway: the executable, then its arguments.
+	Command []string
+	// Env is added to the environment of the gateway.
+	Env []string
+	// Output receives the stdout and stderr of the gateway.
+	Output io.Writer
+	// GatewayHealthURL is the /health endpoint of the gateway, merged into the health of the supervisor.
+	GatewayHealthURL string
+	InitialBackoff   time.Duration
+	MaxBackoff       time.Duration
+	StableAfter      time.Duration
+	// MaxRestarts is the number of consecutive crashes after which the supervisor gives up. 0 means never.
+	MaxRestarts int
+}
+
+// Supervisor runs the gateway as a child process and restarts it when it crashes, with an exponential backoff.
+type Supervisor struct {
+	opts   Options
+	client *http.Client
+
+	mu     sync.Mutex
+	status Status
+}
+
+// Status is the state of the supervised gateway.
+type Status struct {
+	Running    bool      `json:"running"`
+	PID        int       `json:"pid,omitempty"`
+	StartedAt  time.Time `json:"startedAt,omitzero"`
+	Restarts   int       `json:"restarts"`
+	LastExit   string    `json:"lastExit,omitempty"`
+	LastExitAt time.Time `json:"lastExitAt,omitzero"`
+	// NextStartAt is set while waiting to restart the gateway.
+	NextStartAt time.Time `json:"nextStartAt,omitzero"`
+}
+
+func New(opts Options) *Supervisor {
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = max(DefaultMaxBackoff, opts.InitialBackoff)
+	}
+	if opts.StableAfter <= 0 {
+		opts.StableAfter = DefaultStableAfter
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stderr
+	}
+
+	return &Supervisor{
+		opts:   opts,
+		client: &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+// Run runs the gateway until the context is canceled, the gateway exits successfully,
+// or it crashed more than MaxRestarts times in a row.
+func (s *Supervisor) Run(ctx context.Context) error {
+	if len(s.opts.Command) == 0 {
+		return errors.New("no command to supervise")
+	}
+
+	crashes := 0
+	backoff := s.opts.InitialBackoff
+	for {
+		startedAt := time.Now()
+		err := s.runOnce(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			log.Log("- Gateway exited")
+			return nil
+		}
+
+		if time.Since(startedAt) >= s.opts.StableAfter {
+			crashes = 0
+			backoff = s.opts.InitialBackoff
+		}
+		crashes++
+		if s.opts.MaxRestarts > 0 && crashes > s.opts.MaxRestarts {
+			return fmt.Errorf("gateway crashed %d times in a row, giving up: %w", crashes, err)
+		}
+
+		log.Logf("  ! Gateway crashed: %v, restarting in %s", err, backoff)
+		s.update(func(status *Status) {
+			status.NextStartAt = time.Now().Add(backoff)
+		})
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = nextBackoff(backoff, s.opts.MaxBackoff)
+		s.update(func(status *Status) {
+			status.Restarts++
+			status.NextStartAt = time.Time{}
+		})
+	}
+}
+
+func (s *Supervisor) runOnce(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, s.opts.Command[0], s.opts.Command[1:]...)
+	cmd.Env = append(os.Environ(), s.opts.Env...)
+	cmd.Stdout = s.opts.Output
+	cmd.Stderr = s.opts.Output
+	cmd.Cancel = func() error {
+		// Let the gateway stop its servers
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = stopTimeout
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting gateway: %w", err)
+	}
+	log.Logf("- Gateway started (pid %d)", cmd.Process.Pid)
+	s.update(func(status *Status) {
+		status.Running = true
+		status.PID = cmd.Process.Pid
+		status.StartedAt = time.Now()
+	})
+
+	err := cmd.Wait()
+
+	lastExit := "exited successfully"
+	if err != nil {
+		lastExit = err.Error()
+	}
+	s.update(func(status *Status) {
+		status.Running = false
+		status.PID = 0
+		status.LastExit = lastExit
+		status.LastExitAt = time.Now()
+	})
+	return err
+}
+
+func (s *Supervisor) update(f func(status *Status)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(&s.status)
+}
+
+// Status returns the state of the supervised gateway.
+func (s *Supervisor) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// nextBackoff doubles the backoff, up to a maximum.
+func nextBackoff(backoff, maxBackoff time.Duration) time.Duration {
+	return min(2*backoff, maxBackoff)
+}
+
+// HealthHandler reports the state of the supervisor combined with the health of the gateway.
+// It's healthy when the gateway is running and, if its health endpoint is known, reports itself healthy.
+func (s *Supervisor) HealthHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := s.Status()
+		healthy := status.Running
+
+		var gateway json.RawMessage
+		if status.Running && s.opts.GatewayHealthURL != "" {
+			gatewayHealthy, body, err := s.gatewayHealth(r.Context())
+			if err != nil {
+				healthy = false
+				gateway, _ = json.Marshal(map[string]string{"error": err.Error()})
+			} else {
+				healthy = gatewayHealthy
+				gateway = body
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if healthy {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(struct {
+			Healthy    bool            `json:"healthy"`
+			Supervisor Status          `json:"supervisor"`
+			Gateway    json.RawMessage `json:"gateway,omitempty"`
+		}{
+			Healthy:    healthy,
+			Supervisor: status,
+			Gateway:    gateway,
+		})
+	}
+}
+
+func (s *Supervisor) gatewayHealth(ctx context.Context) (bool, json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.opts.GatewayHealthURL, nil)
+	if err != nil {
+		return false, nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return false, nil, err
+	}
+	if !json.Valid(body) {
+		return false, nil, fmt.Errorf("invalid health response (status %d)", resp.StatusCode)
+	}
+	return resp.StatusCode == http.StatusOK, body, nil
+}
@@ -0,0 +1,99 @@
+package supervisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, nextBackoff(time.Second, time.Minute))
+	assert.Equal(t, time.Minute, nextBackoff(40*time.Second, time.Minute))
+}
+
+func TestRunRestartsCrashedGateway(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+
+	var output bytes.Buffer
+	s := New(Options{
+		Command:        []string{"sh", "-c", "echo $GREETING; exit 3"},
+		Env:            []string{"GREETING=hello"},
+		Output:         &output,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		MaxRestarts:    2,
+	})
+
+	err := s.Run(t.Context())
+	require.ErrorContains(t, err, "gateway crashed 3 times in a row")
+	assert.Equal(t, "hello\nhello\nhello\n", output.String())
+
+	status := s.Status()
+	assert.False(t, status.Running)
+	assert.Equal(t, 2, status.Restarts)
+	assert.Equal(t, "exit status 3", status.LastExit)
+}
+
+func TestRunStopsWhenGatewayExits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+
+	s := New(Options{Command: []string{"sh", "-c", "exit 0"}, Output: &bytes.Buffer{}})
+
+	require.NoError(t, s.Run(t.Context()))
+	assert.Zero(t, s.Status().Restarts)
+}
+
+func TestHealthHandler(t *testing.T) {
+	var gatewayHealthy atomic.Bool
+	gatewayHealthy.Store(true)
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		healthy := gatewayHealthy.Load()
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]bool{"healthy": healthy})
+	}))
+	defer gateway.Close()
+
+	s := New(Options{Command: []string{"gateway"}, GatewayHealthURL: gateway.URL})
+	health := func() (int, map[string]any) {
+		recorder := httptest.NewRecorder()
+		s.HealthHandler()(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		return recorder.Code, body
+	}
+
+	// The gateway isn't running yet
+	code, body := health()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, false, body["healthy"])
+	assert.Nil(t, body["gateway"])
+
+	s.update(func(status *Status) {
+		status.Running = true
+		status.PID = 42
+	})
+	code, body = health()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, body["healthy"])
+	assert.Equal(t, map[string]any{"healthy": true}, body["gateway"])
+	assert.InDelta(t, 42, body["supervisor"].(map[string]any)["pid"], 0)
+
+	gatewayHealthy.Store(false)
+	code, body = health()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, false, body["healthy"])
+}
@@ -39,6 +39,12 @@ var (
 	// ToolErrorCounter tracks tool call errors by type and server
 	ToolErrorCounter metric.Int64Counter
 
+	// ToolCostCounter tracks the cost charged to session budgets by tool calls
+	ToolCostCounter metric.Float64Counter
+
+	// ToolCoalescedCounter tracks the tool calls that shared the execution of an identical call
+	ToolCoalescedCounter metric.Int64Counter
+
 	// GatewayStartCounter tracks gateway starts
 	GatewayStartCounter metric.Int64Counter
 
@@ -76,6 +82,27 @@ var (
 	ResourceTemplateErrorCounter metric.Int64Counter
 	ResourceTemplatesDiscovered  metric.Int64Gauge
 	ListResourceTemplatesCounter metric.Int64Counter
+
+	// Container lifecycle metrics
+	ContainerPullCounter    metric.Int64Counter
+	ContainerPullDuration   metric.Float64Histogram
+	ContainerPullBytes      metric.Int64Counter
+	ContainerStartDuration  metric.Float64Histogram
+	ContainerRestartCounter metric.Int64Counter
+	ActiveContainers        metric.Int64UpDownCounter
+
+	// EmulatedImageCounter tracks the images that run in emulation because they're not built for the engine's platform
+	EmulatedImageCounter metric.Int64Counter
+
+	// StdioFramingErrorCounter tracks the lines written to stdout by servers that are not JSON-RPC
+	StdioFramingErrorCounter metric.Int64Counter
+
+	// ArgumentCoercionCounter tracks the arguments of tool calls changed to match the types of the tool's schema
+	ArgumentCoercionCounter metric.Int64Counter
+
+	// Client session metrics
+	ActiveSessions          metric.Int64UpDownCounter
+	RejectedSessionsCounter metric.Int64Counter
 )
 
 // Init initializes the telemetry package with global providers
@@ -128,6 +155,26 @@ func Init() {
 		}
 	}
 
+	ToolCostCounter, err = meter.Float64Counter("mcp.tool.cost",
+		metric.WithDescription("Cost of tool calls charged to session budgets"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating tool cost counter: %v\n", err)
+		}
+	}
+
+	ToolCoalescedCounter, err = meter.Int64Counter("mcp.tool.coalesced",
+		metric.WithDescription("Tool calls that shared the execution of an identical call"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating tool coalesced counter: %v\n", err)
+		}
+	}
+
 	GatewayStartCounter, err = meter.Int64Counter("mcp.gateway.starts",
 		metric.WithDescription("Number of gateway starts"),
 		metric.WithUnit("1"))
@@ -351,6 +398,116 @@ func Init() {
 		}
 	}
 
+	ContainerPullCounter, err = meter.Int64Counter("mcp.container.pulls",
+		metric.WithDescription("Number of container image pulls"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating container pull counter: %v\n", err)
+		}
+	}
+
+	ContainerPullDuration, err = meter.Float64Histogram("mcp.container.pull.duration",
+		metric.WithDescription("Duration of container image pulls"),
+		metric.WithUnit("ms"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating container pull duration histogram: %v\n", err)
+		}
+	}
+
+	ContainerPullBytes, err = meter.Int64Counter("mcp.container.pull.bytes",
+		metric.WithDescription("Size of the container images pulled"),
+		metric.WithUnit("By"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating container pull bytes counter: %v\n", err)
+		}
+	}
+
+	ContainerStartDuration, err = meter.Float64Histogram("mcp.container.start.duration",
+		metric.WithDescription("Duration between starting a container and the MCP server being initialized"),
+		metric.WithUnit("ms"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating container start duration histogram: %v\n", err)
+		}
+	}
+
+	ContainerRestartCounter, err = meter.Int64Counter("mcp.container.restarts",
+		metric.WithDescription("Number of containers restarted for a server"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating container restart counter: %v\n", err)
+		}
+	}
+
+	ActiveContainers, err = meter.Int64UpDownCounter("mcp.container.active",
+		metric.WithDescription("Number of containers currently running"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating active containers counter: %v\n", err)
+		}
+	}
+
+	EmulatedImageCounter, err = meter.Int64Counter("mcp.container.emulated",
+		metric.WithDescription("Number of images pulled for a server that run in emulation"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating emulated image counter: %v\n", err)
+		}
+	}
+
+	StdioFramingErrorCounter, err = meter.Int64Counter("mcp.stdio.framing_errors",
+		metric.WithDescription("Number of lines written to stdout by servers that are not JSON-RPC"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating stdio framing error counter: %v\n", err)
+		}
+	}
+
+	ArgumentCoercionCounter, err = meter.Int64Counter("mcp.tool.argument_coercions",
+		metric.WithDescription("Number of arguments of tool calls coerced to the types of the tool's input schema"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating argument coercion counter: %v\n", err)
+		}
+	}
+
+	ActiveSessions, err = meter.Int64UpDownCounter("mcp.sessions.active",
+		metric.WithDescription("Number of client sessions currently connected to the gateway"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating active sessions counter: %v\n", err)
+		}
+	}
+
+	RejectedSessionsCounter, err = meter.Int64Counter("mcp.sessions.rejected",
+		metric.WithDescription("Number of client sessions rejected because the gateway had too many sessions"),
+		metric.WithUnit("1"))
+	if err != nil {
+		// Log error but don't fail
+		if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
+			fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Error creating rejected sessions counter: %v\n", err)
+		}
+	}
+
 	if os.Getenv("DOCKER_MCP_TELEMETRY_DEBUG") != "" {
 		fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Metrics created successfully\n")
 	}
@@ -382,6 +539,32 @@ func StartCommandSpan(ctx context.Context, commandPath string, attrs ...attribut
 		trace.WithSpanKind(trace.SpanKindServer))
 }
 
+// RecordToolCost records the cost of a tool call charged to a session budget
+func RecordToolCost(ctx context.Context, toolName, serverName string, cost float64) {
+	if ToolCostCounter == nil {
+		return // Telemetry not initialized
+	}
+
+	ToolCostCounter.Add(ctx, cost,
+		metric.WithAttributes(
+			attribute.String("mcp.tool.name", toolName),
+			attribute.String("mcp.server.name", serverName),
+		))
+}
+
+// RecordToolCoalesced records a tool call that shared the execution of an identical call
+func RecordToolCoalesced(ctx context.Context, toolName, serverName string) {
+	if ToolCoalescedCounter == nil {
+		return // Telemetry not initialized
+	}
+
+	ToolCoalescedCounter.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("mcp.tool.name", toolName),
+			attribute.String("mcp.server.name", serverName),
+		))
+}
+
 // RecordToolError records a tool error with appropriate attributes
 func RecordToolError(ctx context.Context, span trace.Span, serverName, serverType, toolName string) {
 	if ToolErrorCounter == nil {
@@ -499,11 +682,28 @@ func RecordInitialize(ctx context.Context, params *mcp.InitializeParams) {
 		fmt.Fprintf(os.Stderr, "[MCP-TELEMETRY] Initialize called - adding to counter\n")
 	}
 
+	attrs := []attribute.KeyValue{
+		attribute.String("mcp.client.name", params.ClientInfo.Name),
+		attribute.String("mcp.client.version", params.ClientInfo.Version),
+	}
 	InitializeCounter.Add(ctx, 1,
-		metric.WithAttributes(
-			attribute.String("mcp.client.name", params.ClientInfo.Name),
-			attribute.String("mcp.client.version", params.ClientInfo.Version),
-		))
+		metric.WithAttributes(append(attrs, ClientCapabilityAttributes(params)...)...))
+}
+
+// ClientCapabilityAttributes describes which of the capabilities the gateway relies on a client declared.
+func ClientCapabilityAttributes(params *mcp.InitializeParams) []attribute.KeyValue {
+	var elicitation, sampling, roots bool
+	if params != nil && params.Capabilities != nil {
+		elicitation = params.Capabilities.Elicitation != nil
+		sampling = params.Capabilities.Sampling != nil
+		roots = params.Capabilities.Roots.ListChanged
+	}
+
+	return []attribute.KeyValue{
+		attribute.Bool("mcp.client.capabilities.elicitation", elicitation),
+		attribute.Bool("mcp.client.capabilities.sampling", sampling),
+		attribute.Bool("mcp.client.capabilities.roots", roots),
+	}
 }
 
 // RecordListTools records a list tools call
@@ -856,3 +1056,127 @@ func RecordResourceTemplateList(ctx context.Context, serverName string, template
 			attribute.String("mcp.server.origin", serverName),
 		))
 }
+
+// RecordContainerPull records the pull of the image of a server
+func RecordContainerPull(ctx context.Context, serverName, image string, durationMs float64, bytes int64, success bool) {
+	if ContainerPullCounter == nil {
+		return // Telemetry not initialized
+	}
+
+	attrs := metric.WithAttributes(
+		attribute.String("mcp.server.name", serverName),
+		attribute.String("mcp.container.image", image),
+		attribute.Bool("mcp.container.pull.success", success),
+	)
+
+	ContainerPullCounter.Add(ctx, 1, attrs)
+	if ContainerPullDuration != nil {
+		ContainerPullDuration.Record(ctx, durationMs, attrs)
+	}
+	if ContainerPullBytes != nil && bytes > 0 {
+		ContainerPullBytes.Add(ctx, bytes, attrs)
+	}
+}
+
+// RecordContainerStart records how long the container of a server took to start
+func RecordContainerStart(ctx context.Context, serverName string, durationMs float64, success bool) {
+	if ContainerStartDuration == nil {
+		return // Telemetry not initialized
+	}
+
+	ContainerStartDuration.Record(ctx, durationMs,
+		metric.WithAttributes(
+			attribute.String("mcp.server.name", serverName),
+			attribute.Bool("mcp.container.start.success", success),
+		))
+}
+
+// RecordContainerRestart records the restart of the container of a server
+func RecordContainerRestart(ctx context.Context, serverName string) {
+	if ContainerRestartCounter == nil {
+		return // Telemetry not initialized
+	}
+
+	ContainerRestartCounter.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("mcp.server.name", serverName),
+		))
+}
+
+// RecordActiveContainers records a change in the number of running containers of a server
+func RecordActiveContainers(ctx context.Context, serverName string, delta int64) {
+	if ActiveContainers == nil {
+		return // Telemetry not initialized
+	}
+
+	ActiveContainers.Add(ctx, delta,
+		metric.WithAttributes(
+			attribute.String("mcp.server.name", serverName),
+		))
+}
+
+// RecordActiveSessions records a change in the number of connected client sessions
+func RecordActiveSessions(ctx context.Context, transport string, delta int64) {
+	if ActiveSessions == nil {
+		return // Telemetry not initialized
+	}
+
+	ActiveSessions.Add(ctx, delta,
+		metric.WithAttributes(
+			attribute.String("mcp.transport", transport),
+		))
+}
+
+// RecordRejectedSession records a client session rejected because the gateway had too many sessions
+func RecordRejectedSession(ctx context.Context, transport string) {
+	if RejectedSessionsCounter == nil {
+		return // Telemetry not initialized
+	}
+
+	RejectedSessionsCounter.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("mcp.transport", transport),
+		))
+}
+
+// RecordEmulatedImage records an image, used by a server, that runs in emulation on the engine's platform
+func RecordEmulatedImage(ctx context.Context, serverName, image, imagePlatform, enginePlatform string) {
+	if EmulatedImageCounter == nil {
+		return // Telemetry not initialized
+	}
+
+	EmulatedImageCounter.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("mcp.server.name", serverName),
+			attribute.String("mcp.container.image", image),
+			attribute.String("mcp.container.platform", imagePlatform),
+			attribute.String("mcp.docker.platform", enginePlatform),
+		))
+}
+
+// RecordStdioFramingError records a line written to stdout by a server that is not JSON-RPC
+func RecordStdioFramingError(ctx context.Context, serverName, framing string) {
+	if StdioFramingErrorCounter == nil {
+		return // Telemetry not initialized
+	}
+
+	StdioFramingErrorCounter.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("mcp.server.name", serverName),
+			attribute.String("mcp.stdio.framing", framing),
+		))
+}
+
+// RecordArgumentCoercion records an argument of a tool call coerced to the type of the tool's input schema
+func RecordArgumentCoercion(ctx context.Context, serverName, toolName, kind string) {
+	if ArgumentCoercionCounter == nil {
+		return // Telemetry not initialized
+	}
+
+	ArgumentCoercionCounter.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("mcp.server.name", serverName),
+			attribute.String("mcp.tool.name", toolName),
+			attribute.String("mcp.coercion.kind", kind),
+		))
+}
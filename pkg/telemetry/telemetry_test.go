@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
@@ -328,6 +329,118 @@ func TestRecordToolError(t *testing.T) {
 	assert.True(t, found, "tool error should be recorded")
 }
 
+func TestRecordContainerLifecycle(t *testing.T) {
+	_, metricReader := setupTestTelemetry(t)
+	Init()
+
+	ctx := context.Background()
+	serverName := "test_server"
+
+	RecordContainerPull(ctx, serverName, "mcp/test", 1500, 2048, true)
+	RecordContainerStart(ctx, serverName, 300, true)
+	RecordContainerRestart(ctx, serverName)
+	RecordActiveContainers(ctx, serverName, 1)
+	RecordActiveContainers(ctx, serverName, 1)
+	RecordActiveContainers(ctx, serverName, -1)
+
+	// Collect metrics
+	var rm metricdata.ResourceMetrics
+	err := metricReader.Collect(ctx, &rm)
+	require.NoError(t, err)
+
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	pulls := metrics["mcp.container.pulls"].(metricdata.Sum[int64])
+	assert.Equal(t, int64(1), pulls.DataPoints[0].Value)
+	imageAttr, _ := pulls.DataPoints[0].Attributes.Value(attribute.Key("mcp.container.image"))
+	assert.Equal(t, "mcp/test", imageAttr.AsString())
+
+	pullDuration := metrics["mcp.container.pull.duration"].(metricdata.Histogram[float64])
+	assert.InDelta(t, 1500.0, pullDuration.DataPoints[0].Sum, 0.001)
+
+	pullBytes := metrics["mcp.container.pull.bytes"].(metricdata.Sum[int64])
+	assert.Equal(t, int64(2048), pullBytes.DataPoints[0].Value)
+
+	startDuration := metrics["mcp.container.start.duration"].(metricdata.Histogram[float64])
+	assert.InDelta(t, 300.0, startDuration.DataPoints[0].Sum, 0.001)
+
+	restarts := metrics["mcp.container.restarts"].(metricdata.Sum[int64])
+	assert.Equal(t, int64(1), restarts.DataPoints[0].Value)
+
+	active := metrics["mcp.container.active"].(metricdata.Sum[int64])
+	assert.Equal(t, int64(1), active.DataPoints[0].Value)
+	serverNameAttr, _ := active.DataPoints[0].Attributes.Value(attribute.Key("mcp.server.name"))
+	assert.Equal(t, serverName, serverNameAttr.AsString())
+}
+
+func TestRecordSessions(t *testing.T) {
+	_, metricReader := setupTestTelemetry(t)
+	Init()
+
+	ctx := context.Background()
+	RecordActiveSessions(ctx, "streaming", 1)
+	RecordActiveSessions(ctx, "streaming", 1)
+	RecordActiveSessions(ctx, "streaming", -1)
+	RecordRejectedSession(ctx, "streaming")
+
+	var rm metricdata.ResourceMetrics
+	err := metricReader.Collect(ctx, &rm)
+	require.NoError(t, err)
+
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	active := metrics["mcp.sessions.active"].(metricdata.Sum[int64])
+	assert.Equal(t, int64(1), active.DataPoints[0].Value)
+	transportAttr, _ := active.DataPoints[0].Attributes.Value(attribute.Key("mcp.transport"))
+	assert.Equal(t, "streaming", transportAttr.AsString())
+
+	rejected := metrics["mcp.sessions.rejected"].(metricdata.Sum[int64])
+	assert.Equal(t, int64(1), rejected.DataPoints[0].Value)
+}
+
+func TestRecordInitializeCapabilities(t *testing.T) {
+	_, metricReader := setupTestTelemetry(t)
+	Init()
+
+	ctx := context.Background()
+	RecordInitialize(ctx, &mcp.InitializeParams{
+		ClientInfo:   &mcp.Implementation{Name: "vscode", Version: "1.0.0"},
+		Capabilities: &mcp.ClientCapabilities{Elicitation: &mcp.ElicitationCapabilities{}},
+	})
+
+	var rm metricdata.ResourceMetrics
+	err := metricReader.Collect(ctx, &rm)
+	require.NoError(t, err)
+
+	var initialize metricdata.Sum[int64]
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "mcp.initialize" {
+				initialize = m.Data.(metricdata.Sum[int64])
+			}
+		}
+	}
+	require.Len(t, initialize.DataPoints, 1)
+
+	attrs := initialize.DataPoints[0].Attributes
+	elicitation, _ := attrs.Value(attribute.Key("mcp.client.capabilities.elicitation"))
+	assert.True(t, elicitation.AsBool())
+	sampling, _ := attrs.Value(attribute.Key("mcp.client.capabilities.sampling"))
+	assert.False(t, sampling.AsBool())
+	roots, _ := attrs.Value(attribute.Key("mcp.client.capabilities.roots"))
+	assert.False(t, roots.AsBool())
+}
+
 func TestConcurrentMetricRecording(t *testing.T) {
 	_, metricReader := setupTestTelemetry(t)
 	Init()
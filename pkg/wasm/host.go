@@ -0,0 +1,135 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// maxResponseSize limits the size of the bodies of the HTTP responses passed to modules.
+const maxResponseSize = 10 * 1024 * 1024
+
+type httpRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+type httpResponse struct {
+	Status  int               `json:"status,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+func (s *Server) log(_ context.Context, module api.Module, ptr, length uint32) {
+	message, ok := module.Memory().Read(ptr, length)
+	if !ok {
+		return
+	}
+	log.Logf("  > %s: %s", s.name, strings.TrimRight(string(message), "\n"))
+}
+
+func (s *Server) httpRequest(ctx context.Context, module api.Module, ptr, length uint32) uint64 {
+	var response httpResponse
+
+	in, ok := module.Memory().Read(ptr, length)
+	if !ok {
+		response.Error = "out of bounds memory"
+	} else {
+		var request httpRequest
+		if err := json.Unmarshal(in, &request); err != nil {
+			response.Error = fmt.Sprintf("invalid request: %s", err)
+		} else if err := s.sendRequest(ctx, request, &response); err != nil {
+			response.Error = err.Error()
+		}
+	}
+
+	out, _ := json.Marshal(response)
+	return writePacked(ctx, module, out)
+}
+
+func (s *Server) sendRequest(ctx context.Context, request httpRequest, response *httpResponse) error {
+	u, err := url.Parse(request.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if err := s.checkHost(u); err != nil {
+		return err
+	}
+
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), strings.NewReader(request.Body))
+	if err != nil {
+		return err
+	}
+	for name, value := range request.Headers {
+		req.Header.Set(name, value)
+	}
+
+	client := &http.Client{
+		// Redirects must be to granted hosts too
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return s.checkHost(req.URL)
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+
+	response.Status = resp.StatusCode
+	response.Headers = map[string]string{}
+	for name := range resp.Header {
+		response.Headers[name] = resp.Header.Get(name)
+	}
+	response.Body = string(body)
+	return nil
+}
+
+// checkHost only lets modules reach the hosts they're granted, as host:port[/protocol] like the allowHosts of the catalog.
+func (s *Server) checkHost(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	hostPort := net.JoinHostPort(u.Hostname(), port)
+
+	for _, allowed := range s.capabilities.AllowHosts {
+		allowed, _, _ = strings.Cut(allowed, "/")
+		if strings.EqualFold(allowed, hostPort) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not an allowed host", hostPort)
+}
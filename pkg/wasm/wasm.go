@@ -0,0 +1,270 @@
+// Package wasm runs MCP servers compiled to WebAssembly, in an embedded runtime, instead of in containers.
+//
+// A module implements the tools of a server with a minimal ABI. Data is exchanged as JSON,
+// in the memory of the module, and returned as a (pointer << 32 | length) i64.
+//
+// The module exports:
+//
+//	memory
+//	mcp_alloc(size i32) i32                                     allocates size bytes, for the host to write into
+//	mcp_tools() i64                                             returns the JSON array of its tools
+//	mcp_call(name_ptr, name_len, args_ptr, args_len i32) i64    calls a tool and returns the JSON of its result
+//
+// The host grants capabilities through functions of the "mcp" module:
+//
+//	log(ptr, len i32)                     writes a message to the logs of the gateway
+//	getenv(name_ptr, name_len i32) i64    returns the value of a granted environment variable, or 0
+//	http_request(req_ptr, req_len i32) i64
+//	                                      sends an HTTP request, {"method", "url", "headers", "body"},
+//	                                      to a granted host and returns {"status", "headers", "body"} or {"error"}
+//
+// WASI preview 1 is available too, without any file system, environment variable or argument.
+// Each tool call runs in a fresh instance of the module, that's closed when the call is cancelled.
+package wasm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+const (
+	hostModuleName = "mcp"
+	pageSize       = 65536
+	maxPages       = 65536
+)
+
+// Capabilities are what the host grants to a module. Nothing else than computing is allowed by default.
+type Capabilities struct {
+	// Env are the environment variables the module can read, as NAME=value.
+	Env []string
+	// AllowHosts are the hosts the module can send HTTP requests to, as host:port.
+	AllowHosts []string
+	// MemoryLimit is the maximum size of the memory of the module, in bytes. 0 is the 4GiB limit of wasm32.
+	MemoryLimit int64
+}
+
+// Server is an MCP server running the tools of a WebAssembly module.
+type Server struct {
+	name         string
+	runtime      wazero.Runtime
+	compiled     wazero.CompiledModule
+	capabilities Capabilities
+}
+
+// NewServer compiles a module and lists its tools. Close releases the runtime.
+func NewServer(ctx context.Context, name string, wasm []byte, capabilities Capabilities) (*Server, error) {
+	config := wazero.NewRuntimeConfig().WithCloseOnContextDone(true)
+	if capabilities.MemoryLimit > 0 {
+		config = config.WithMemoryLimitPages(uint32(min(max(capabilities.MemoryLimit/pageSize, 1), maxPages)))
+	}
+	runtime := wazero.NewRuntimeWithConfig(ctx, config)
+
+	s := &Server{
+		name:         name,
+		runtime:      runtime,
+		capabilities: capabilities,
+	}
+	if err := s.instantiateHostModules(ctx); err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+
+	compiled, err := runtime.CompileModule(ctx, wasm)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("compiling the module of %s: %w", name, err)
+	}
+	s.compiled = compiled
+
+	return s, nil
+}
+
+// Close releases the runtime and the compiled module.
+func (s *Server) Close(ctx context.Context) error {
+	return s.runtime.Close(ctx)
+}
+
+// MCPServer returns an MCP server with the tools of the module.
+func (s *Server) MCPServer(ctx context.Context) (*mcp.Server, error) {
+	tools, err := s.tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: s.name}, nil)
+	for _, tool := range tools {
+		server.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return s.CallTool(ctx, req.Params.Name, req.Params.Arguments)
+		})
+	}
+	return server, nil
+}
+
+func (s *Server) tools(ctx context.Context) ([]*mcp.Tool, error) {
+	module, err := s.instantiate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer module.Close(ctx)
+
+	out, err := call(ctx, module, "mcp_tools")
+	if err != nil {
+		return nil, err
+	}
+
+	var tools []*mcp.Tool
+	if err := json.Unmarshal(out, &tools); err != nil {
+		return nil, fmt.Errorf("invalid tools of %s: %w", s.name, err)
+	}
+	for _, tool := range tools {
+		if tool.Name == "" {
+			return nil, fmt.Errorf("invalid tools of %s: a tool has no name", s.name)
+		}
+		if tool.InputSchema == nil {
+			tool.InputSchema = map[string]any{"type": "object"}
+		}
+	}
+	return tools, nil
+}
+
+// CallTool calls a tool in a fresh instance of the module.
+func (s *Server) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*mcp.CallToolResult, error) {
+	module, err := s.instantiate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer module.Close(context.WithoutCancel(ctx))
+
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+	namePtr, err := write(ctx, module, []byte(name))
+	if err != nil {
+		return nil, err
+	}
+	argsPtr, err := write(ctx, module, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := call(ctx, module, "mcp_call", uint64(namePtr), uint64(len(name)), uint64(argsPtr), uint64(len(arguments)))
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("invalid result of %s: %w", name, err)
+	}
+	return &result, nil
+}
+
+func (s *Server) instantiate(ctx context.Context) (api.Module, error) {
+	config := wazero.NewModuleConfig().
+		// Anonymous, for instances of the module to run concurrently
+		WithName("").
+		WithStartFunctions("_initialize").
+		WithStderr(os.Stderr).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithRandSource(rand.Reader)
+
+	module, err := s.runtime.InstantiateModule(ctx, s.compiled, config)
+	if err != nil {
+		return nil, fmt.Errorf("instantiating the module of %s: %w", s.name, err)
+	}
+	return module, nil
+}
+
+func (s *Server) instantiateHostModules(ctx context.Context) error {
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, s.runtime); err != nil {
+		return fmt.Errorf("instantiating WASI: %w", err)
+	}
+
+	_, err := s.runtime.NewHostModuleBuilder(hostModuleName).
+		NewFunctionBuilder().WithFunc(s.log).Export("log").
+		NewFunctionBuilder().WithFunc(s.getenv).Export("getenv").
+		NewFunctionBuilder().WithFunc(s.httpRequest).Export("http_request").
+		Instantiate(ctx)
+	if err != nil {
+		return fmt.Errorf("instantiating the host functions: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) getenv(ctx context.Context, module api.Module, namePtr, nameLen uint32) uint64 {
+	name, ok := module.Memory().Read(namePtr, nameLen)
+	if !ok {
+		return 0
+	}
+	for _, env := range s.capabilities.Env {
+		if value, found := strings.CutPrefix(env, string(name)+"="); found {
+			return writePacked(ctx, module, []byte(value))
+		}
+	}
+	return 0
+}
+
+// call calls a function of the module that returns JSON, as a packed pointer and length.
+func call(ctx context.Context, module api.Module, name string, params ...uint64) ([]byte, error) {
+	fn := module.ExportedFunction(name)
+	if fn == nil {
+		return nil, fmt.Errorf("the module doesn't export %s", name)
+	}
+
+	results, err := fn.Call(ctx, params...)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s: %w", name, err)
+	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("%s should return an i64", name)
+	}
+
+	ptr, length := uint32(results[0]>>32), uint32(results[0])
+	out, ok := module.Memory().Read(ptr, length)
+	if !ok {
+		return nil, fmt.Errorf("%s returned out of bounds memory", name)
+	}
+	return append([]byte(nil), out...), nil
+}
+
+// write copies data in memory allocated by the module.
+func write(ctx context.Context, module api.Module, data []byte) (uint32, error) {
+	alloc := module.ExportedFunction("mcp_alloc")
+	if alloc == nil {
+		return 0, errors.New("the module doesn't export mcp_alloc")
+	}
+
+	results, err := alloc.Call(ctx, uint64(len(data)))
+	if err != nil {
+		return 0, fmt.Errorf("calling mcp_alloc: %w", err)
+	}
+	if len(results) != 1 {
+		return 0, errors.New("mcp_alloc should return an i32")
+	}
+
+	ptr := uint32(results[0])
+	if !module.Memory().Write(ptr, data) {
+		return 0, errors.New("mcp_alloc returned out of bounds memory")
+	}
+	return ptr, nil
+}
+
+// writePacked copies data in memory allocated by the module, and returns it packed for a host function to return.
+func writePacked(ctx context.Context, module api.Module, data []byte) uint64 {
+	ptr, err := write(ctx, module, data)
+	if err != nil {
+		panic(err)
+	}
+	return uint64(ptr)<<32 | uint64(len(data))
+}
@@ -0,0 +1,180 @@
+package wasm
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTools     = `[{"name":"greet","description":"Greets people"}]`
+	testEnvOffset = 512
+	testEnvName   = "RESULT"
+	testHeapStart = 1024
+)
+
+// testModule is a module whose greet tool returns the RESULT environment variable, read with getenv.
+func testModule() []byte {
+	const (
+		i32 = 0x7f
+		i64 = 0x7e
+	)
+
+	types := vec(
+		funcType([]byte{i32, i32}, []byte{i64}),           // getenv
+		funcType([]byte{i32}, []byte{i32}),                // mcp_alloc
+		funcType(nil, []byte{i64}),                        // mcp_tools
+		funcType([]byte{i32, i32, i32, i32}, []byte{i64}), // mcp_call
+	)
+
+	imports := vec(concat(name("mcp"), name("getenv"), []byte{0x00, 0x00}))
+	functions := vec([]byte{1}, []byte{2}, []byte{3})
+	memories := vec([]byte{0x00, 0x01})
+	globals := vec(concat([]byte{i32, 0x01, 0x41}, sleb(testHeapStart), []byte{0x0b}))
+	exports := vec(
+		concat(name("memory"), []byte{0x02, 0x00}),
+		concat(name("mcp_alloc"), []byte{0x00, 0x01}),
+		concat(name("mcp_tools"), []byte{0x00, 0x02}),
+		concat(name("mcp_call"), []byte{0x00, 0x03}),
+	)
+	code := vec(
+		// Bump allocator: return the heap pointer and move it by size
+		body([]byte{0x23, 0x00, 0x23, 0x00, 0x20, 0x00, 0x6a, 0x24, 0x00}),
+		body(concat([]byte{0x42}, sleb(int64(len(testTools))))),
+		body(concat([]byte{0x41}, sleb(testEnvOffset), []byte{0x41}, sleb(int64(len(testEnvName))), []byte{0x10, 0x00})),
+	)
+	data := vec(
+		concat([]byte{0x00, 0x41}, sleb(0), []byte{0x0b}, name(testTools)),
+		concat([]byte{0x00, 0x41}, sleb(testEnvOffset), []byte{0x0b}, name(testEnvName)),
+	)
+
+	return concat(
+		[]byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00},
+		section(1, types),
+		section(2, imports),
+		section(3, functions),
+		section(5, memories),
+		section(6, globals),
+		section(7, exports),
+		section(10, code),
+		section(11, data),
+	)
+}
+
+func TestServer(t *testing.T) {
+	server, err := NewServer(t.Context(), "greeter", testModule(), Capabilities{
+		Env:         []string{`RESULT={"content":[{"type":"text","text":"Hello"}]}`},
+		MemoryLimit: 1024 * 1024,
+	})
+	require.NoError(t, err)
+	defer server.Close(t.Context())
+
+	mcpServer, err := server.MCPServer(t.Context())
+	require.NoError(t, err)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err = mcpServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	tools, err := session.ListTools(t.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 1)
+	assert.Equal(t, "greet", tools.Tools[0].Name)
+	assert.Equal(t, "Greets people", tools.Tools[0].Description)
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "greet", Arguments: map[string]any{"name": "Alice"}})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "Hello", result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestServerEnvNotGranted(t *testing.T) {
+	server, err := NewServer(t.Context(), "greeter", testModule(), Capabilities{Env: []string{"OTHER=value"}})
+	require.NoError(t, err)
+	defer server.Close(t.Context())
+
+	_, err = server.CallTool(t.Context(), "greet", json.RawMessage(`{}`))
+	require.ErrorContains(t, err, "invalid result of greet")
+}
+
+func TestNewServerInvalidModule(t *testing.T) {
+	_, err := NewServer(t.Context(), "broken", []byte("not wasm"), Capabilities{})
+	require.ErrorContains(t, err, "compiling the module of broken")
+}
+
+func TestCheckHost(t *testing.T) {
+	server := &Server{capabilities: Capabilities{AllowHosts: []string{"api.example.com:443", "localhost:8080/tcp"}}}
+
+	for rawURL, allowed := range map[string]bool{
+		"https://api.example.com/v1":     true,
+		"https://API.example.com:443/v1": true,
+		"http://api.example.com/v1":      false,
+		"http://localhost:8080/":         true,
+		"https://other.example.com/":     false,
+		"ftp://api.example.com:443/":     false,
+	} {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		assert.Equal(t, allowed, server.checkHost(u) == nil, rawURL)
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
+
+func uleb(v uint64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func sleb(v int64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func name(s string) []byte {
+	return concat(uleb(uint64(len(s))), []byte(s))
+}
+
+func vec(items ...[]byte) []byte {
+	return concat(uleb(uint64(len(items))), concat(items...))
+}
+
+func section(id byte, content []byte) []byte {
+	return concat([]byte{id}, uleb(uint64(len(content))), content)
+}
+
+func funcType(params, results []byte) []byte {
+	return concat([]byte{0x60}, uleb(uint64(len(params))), params, uleb(uint64(len(results))), results)
+}
+
+func body(instructions []byte) []byte {
+	content := concat([]byte{0x00}, instructions, []byte{0x0b})
+	return concat(uleb(uint64(len(content))), content)
+}
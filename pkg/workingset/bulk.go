@@ -0,0 +1,209 @@
+package workingset
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/moby/term"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/registryapi"
+)
+
+// maxConcurrentResolves limits how many server references are resolved at the same time.
+const maxConcurrentResolves = 8
+
+// ReadServerRefs reads a list of server references from a file.
+//
+// YAML files (.yaml or .yml) contain either a list of references or a `servers` key with that list.
+// Other files contain one reference per line. Blank lines and lines starting with # are ignored.
+func ReadServerRefs(path string) ([]string, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		refs, err := parseYAMLServerRefs(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return refs, nil
+	default:
+		return parseTextServerRefs(buf)
+	}
+}
+
+func parseYAMLServerRefs(buf []byte) ([]string, error) {
+	var refs []string
+	if err := yaml.Unmarshal(buf, &refs); err == nil {
+		return refs, nil
+	}
+
+	var list struct {
+		Servers []string `yaml:"servers"`
+	}
+	if err := yaml.Unmarshal(buf, &list); err != nil {
+		return nil, errors.New("expected a list of server references or a servers key with that list")
+	}
+	return list.Servers, nil
+}
+
+func parseTextServerRefs(buf []byte) ([]string, error) {
+	var refs []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		refs = append(refs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// BulkAddServers resolves many server references concurrently and adds the ones that could be
+// resolved to a profile. Unlike AddServers, a reference that can't be resolved doesn't prevent
+// the others from being added: the failures are listed in a summary and reported as an error.
+func BulkAddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Client, ociService oci.Service, id string, servers []string) error {
+	var progress io.Writer = io.Discard
+	if _, isTerminal := term.GetFdInfo(os.Stderr); isTerminal {
+		progress = os.Stderr
+	}
+
+	return bulkAddServers(ctx, dao, registryClient, ociService, id, servers, progress)
+}
+
+type resolveResult struct {
+	servers []Server
+	err     error
+}
+
+func bulkAddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Client, ociService oci.Service, id string, servers []string, progress io.Writer) error {
+	if len(servers) == 0 {
+		return fmt.Errorf("at least one server must be specified")
+	}
+
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	results := make([]resolveResult, len(servers))
+	bar := newProgressBar(progress, len(servers))
+
+	errs, resolveCtx := errgroup.WithContext(ctx)
+	errs.SetLimit(maxConcurrentResolves)
+	for i, server := range servers {
+		errs.Go(func() error {
+			ss, err := resolveServersFromString(resolveCtx, registryClient, ociService, dao, server)
+			results[i] = resolveResult{servers: ss, err: err}
+			bar.increment()
+			return nil
+		})
+	}
+	_ = errs.Wait()
+	bar.done()
+
+	// Keep the servers in the order of the references, whatever order they were resolved in.
+	var (
+		newServers []Server
+		failures   []string
+	)
+	for i, result := range results {
+		if result.err != nil {
+			failures = append(failures, fmt.Sprintf("  %s: %s", servers[i], result.err))
+			continue
+		}
+		newServers = append(newServers, result.servers...)
+	}
+
+	if len(newServers) > 0 {
+		workingSet := NewFromDb(dbWorkingSet)
+
+		defaultSecret := "default"
+		_, defaultFound := workingSet.Secrets[defaultSecret]
+		if workingSet.Secrets == nil || !defaultFound {
+			defaultSecret = ""
+		}
+		for i := range newServers {
+			newServers[i].Secrets = defaultSecret
+		}
+
+		workingSet.Servers = append(workingSet.Servers, newServers...)
+
+		if err := workingSet.Validate(); err != nil {
+			return fmt.Errorf("invalid profile: %w", err)
+		}
+
+		if err := dao.UpdateWorkingSet(ctx, workingSet.ToDb()); err != nil {
+			return fmt.Errorf("failed to update profile: %w", err)
+		}
+	}
+
+	fmt.Printf("Added %d server(s) to profile %s (%d/%d reference(s) resolved)\n", len(newServers), id, len(servers)-len(failures), len(servers))
+	if len(failures) > 0 {
+		fmt.Printf("Failed to resolve %d reference(s):\n%s\n", len(failures), strings.Join(failures, "\n"))
+		return fmt.Errorf("failed to resolve %d of %d server reference(s)", len(failures), len(servers))
+	}
+
+	return nil
+}
+
+// progressBar draws the progress of the resolution of the server references on a single line.
+type progressBar struct {
+	mu    sync.Mutex
+	out   io.Writer
+	total int
+	count int
+}
+
+const progressBarWidth = 30
+
+func newProgressBar(out io.Writer, total int) *progressBar {
+	bar := &progressBar{out: out, total: total}
+	bar.draw()
+	return bar
+}
+
+func (b *progressBar) increment() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.count++
+	b.draw()
+}
+
+func (b *progressBar) done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, _ = fmt.Fprintln(b.out)
+}
+
+// draw must be called with b.mu held, or before b is shared.
+func (b *progressBar) draw() {
+	filled := progressBarWidth * b.count / b.total
+	_, _ = fmt.Fprintf(b.out, "\rResolving servers [%s%s] %d/%d", strings.Repeat("=", filled), strings.Repeat(" ", progressBarWidth-filled), b.count, b.total)
+}
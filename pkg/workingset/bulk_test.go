@@ -0,0 +1,159 @@
+package workingset
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func TestReadServerRefsText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.txt")
+	require.NoError(t, os.WriteFile(path, []byte(`# Team servers
+docker://myimage:latest
+
+  docker://anotherimage:v1.0  
+# https://registry.modelcontextprotocol.io/v0/servers/disabled
+`), 0o644))
+
+	refs, err := ReadServerRefs(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docker://myimage:latest", "docker://anotherimage:v1.0"}, refs)
+}
+
+func TestReadServerRefsYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "list",
+			content: `- docker://myimage:latest
+- docker://anotherimage:v1.0
+`,
+		},
+		{
+			name: "servers key",
+			content: `servers:
+  - docker://myimage:latest
+  - docker://anotherimage:v1.0
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "servers.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+
+			refs, err := ReadServerRefs(path)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"docker://myimage:latest", "docker://anotherimage:v1.0"}, refs)
+		})
+	}
+}
+
+func TestReadServerRefsInvalidYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.yml")
+	require.NoError(t, os.WriteFile(path, []byte("servers: docker://myimage:latest\n"), 0o644))
+
+	_, err := ReadServerRefs(path)
+	require.Error(t, err)
+}
+
+func TestReadServerRefsMissingFile(t *testing.T) {
+	_, err := ReadServerRefs(filepath.Join(t.TempDir(), "missing.txt"))
+	require.Error(t, err)
+}
+
+func TestBulkAddServers(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "test-set",
+		Name:    "Test Working Set",
+		Servers: db.ServerList{},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	var progress bytes.Buffer
+	err = bulkAddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{
+		"docker://myimage:latest",
+		"docker://anotherimage:v1.0",
+	}, &progress)
+	require.NoError(t, err)
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	require.Len(t, dbSet.Servers, 2)
+	assert.Equal(t, "My Image", dbSet.Servers[0].Snapshot.Server.Name)
+	assert.Equal(t, "Another Image", dbSet.Servers[1].Snapshot.Server.Name)
+	assert.Contains(t, progress.String(), "2/2")
+}
+
+func TestBulkAddServersPartialFailure(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "test-set",
+		Name:    "Test Working Set",
+		Servers: db.ServerList{},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	err = bulkAddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{
+		"docker://myimage:latest",
+		"invalid://server",
+		"docker://anotherimage:v1.0",
+	}, &bytes.Buffer{})
+	require.ErrorContains(t, err, "failed to resolve 1 of 3 server reference(s)")
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	require.Len(t, dbSet.Servers, 2)
+	assert.Equal(t, "My Image", dbSet.Servers[0].Snapshot.Server.Name)
+	assert.Equal(t, "Another Image", dbSet.Servers[1].Snapshot.Server.Name)
+}
+
+func TestBulkAddServersAllFail(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "test-set",
+		Name:    "Test Working Set",
+		Servers: db.ServerList{},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	err = bulkAddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"invalid://server"}, &bytes.Buffer{})
+	require.Error(t, err)
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Empty(t, dbSet.Servers)
+}
+
+func TestBulkAddServersProfileNotFound(t *testing.T) {
+	dao := setupTestDB(t)
+
+	err := bulkAddServers(t.Context(), dao, getMockRegistryClient(), getMockOciService(), "missing", []string{"docker://myimage:latest"}, &bytes.Buffer{})
+	require.ErrorContains(t, err, "profile missing not found")
+}
+
+func TestBulkAddServersNoServers(t *testing.T) {
+	dao := setupTestDB(t)
+
+	err := bulkAddServers(t.Context(), dao, getMockRegistryClient(), getMockOciService(), "test-set", nil, &bytes.Buffer{})
+	require.ErrorContains(t, err, oneServerError)
+}
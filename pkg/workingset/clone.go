@@ -0,0 +1,56 @@
+package workingset
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+// Clone copies a profile, with its servers, tools, config and secret mappings, into a new profile.
+// The new profile isn't bound to the Docker context of the original one.
+func Clone(ctx context.Context, dao db.DAO, id string, name string, newID string) error {
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	if newID != "" {
+		_, err := dao.GetWorkingSet(ctx, newID)
+		if err == nil {
+			return fmt.Errorf("profile with id %s already exists", newID)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to look for existing profile: %w", err)
+		}
+	} else {
+		newID, err = createWorkingSetID(ctx, name, dao)
+		if err != nil {
+			return fmt.Errorf("failed to create profile id: %w", err)
+		}
+	}
+
+	// The profile was just read from the database, so nothing is shared with the original one
+	workingSet := NewFromDb(dbWorkingSet)
+	workingSet.ID = newID
+	workingSet.Name = name
+	workingSet.DockerContext = ""
+
+	if err := workingSet.Validate(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+
+	err = dao.CreateWorkingSet(ctx, workingSet.ToDb())
+	if err != nil {
+		return fmt.Errorf("failed to create profile: %w", err)
+	}
+
+	fmt.Printf("Cloned profile %s into %s with %d servers\n", id, newID, len(workingSet.Servers))
+
+	return nil
+}
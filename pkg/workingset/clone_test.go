@@ -0,0 +1,82 @@
+package workingset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func TestClone(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	server := makeServer("github", []catalog.Tool{{Name: "create_issue"}, {Name: "list_issues"}}, []string{"create_issue"})
+	server.Config = map[string]any{"owner": "docker"}
+	server.Secrets = "team"
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "team-baseline",
+		Name:    "Team Baseline",
+		Servers: db.ServerList{server},
+		Secrets: db.SecretMap{
+			"team": {Provider: string(SecretProviderDockerDesktop)},
+		},
+		DockerContext: "desktop-linux",
+	})
+	require.NoError(t, err)
+
+	output := captureStdout(func() {
+		err = Clone(ctx, dao, "team-baseline", "My Experiment", "")
+		require.NoError(t, err)
+	})
+	assert.Equal(t, "Cloned profile team-baseline into my-experiment with 1 servers\n", output)
+
+	clone, err := dao.GetWorkingSet(ctx, "my-experiment")
+	require.NoError(t, err)
+	assert.Equal(t, "My Experiment", clone.Name)
+	assert.Empty(t, clone.DockerContext)
+	require.Len(t, clone.Servers, 1)
+	assert.Equal(t, []string{"create_issue"}, clone.Servers[0].Tools)
+	assert.Equal(t, map[string]any{"owner": "docker"}, clone.Servers[0].Config)
+	assert.Equal(t, "team", clone.Servers[0].Secrets)
+	assert.Equal(t, "github", clone.Servers[0].Snapshot.Server.Name)
+	assert.Equal(t, string(SecretProviderDockerDesktop), clone.Secrets["team"].Provider)
+
+	// Changing the clone leaves the original untouched
+	require.NoError(t, UpdateTools(ctx, dao, "my-experiment", []string{"github.list_issues"}, nil, nil, nil))
+	original, err := dao.GetWorkingSet(ctx, "team-baseline")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"create_issue"}, original.Servers[0].Tools)
+	assert.Equal(t, "desktop-linux", original.DockerContext)
+}
+
+func TestCloneWithID(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	for _, id := range []string{"baseline", "taken"} {
+		err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+			ID:      id,
+			Name:    id,
+			Servers: db.ServerList{},
+			Secrets: db.SecretMap{},
+		})
+		require.NoError(t, err)
+	}
+
+	err := Clone(ctx, dao, "baseline", "Mine", "taken")
+	require.ErrorContains(t, err, "profile with id taken already exists")
+
+	err = Clone(ctx, dao, "missing", "Mine", "")
+	require.ErrorContains(t, err, "profile missing not found")
+
+	captureStdout(func() {
+		err = Clone(ctx, dao, "baseline", "Mine", "mine-v2")
+	})
+	require.NoError(t, err)
+	_, err = dao.GetWorkingSet(ctx, "mine-v2")
+	require.NoError(t, err)
+}
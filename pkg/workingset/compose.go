@@ -0,0 +1,324 @@
+package workingset
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+// ComposeServerLabel marks the services of a compose file that are MCP servers.
+const ComposeServerLabel = "com.docker.mcp.server"
+
+type composeFile struct {
+	Name     string                    `yaml:"name"`
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image       string          `yaml:"image"`
+	Labels      composeMapping  `yaml:"labels"`
+	Environment composeMapping  `yaml:"environment"`
+	Volumes     []composeVolume `yaml:"volumes"`
+	Command     composeCommand  `yaml:"command"`
+	User        string          `yaml:"user"`
+	NetworkMode string          `yaml:"network_mode"`
+	Platform    string          `yaml:"platform"`
+	Cpus        composeScalar   `yaml:"cpus"`
+	MemLimit    composeScalar   `yaml:"mem_limit"`
+	Deploy      struct {
+		Resources struct {
+			Limits struct {
+				Cpus   composeScalar `yaml:"cpus"`
+				Memory composeScalar `yaml:"memory"`
+			} `yaml:"limits"`
+		} `yaml:"resources"`
+	} `yaml:"deploy"`
+}
+
+// composeMapping is either a list of KEY=VALUE or a map, like labels and environment.
+type composeMapping map[string]string
+
+func (m *composeMapping) UnmarshalYAML(node *yaml.Node) error {
+	mapping := composeMapping{}
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var list []string
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+		for _, item := range list {
+			key, value, _ := strings.Cut(item, "=")
+			mapping[key] = value
+		}
+	case yaml.MappingNode:
+		var values map[string]*composeScalar
+		if err := node.Decode(&values); err != nil {
+			return err
+		}
+		for key, value := range values {
+			if value == nil {
+				mapping[key] = ""
+			} else {
+				mapping[key] = string(*value)
+			}
+		}
+	default:
+		return fmt.Errorf("line %d: expected a list or a map", node.Line)
+	}
+	*m = mapping
+	return nil
+}
+
+// composeScalar is a string, a number or a boolean, kept as written.
+type composeScalar string
+
+func (s *composeScalar) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: expected a scalar", node.Line)
+	}
+	*s = composeScalar(node.Value)
+	return nil
+}
+
+// composeCommand is either a string or a list.
+type composeCommand []string
+
+func (c *composeCommand) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*c = strings.Fields(node.Value)
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*c = list
+	return nil
+}
+
+// composeVolume is a volume in either the short syntax, source:target[:mode], or the long syntax.
+type composeVolume struct {
+	Type     string `yaml:"type"`
+	Source   string `yaml:"source"`
+	Target   string `yaml:"target"`
+	ReadOnly bool   `yaml:"read_only"`
+}
+
+func (v *composeVolume) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		parts := strings.Split(node.Value, ":")
+		switch len(parts) {
+		case 1:
+			v.Target = parts[0]
+		case 2:
+			v.Source, v.Target = parts[0], parts[1]
+		default:
+			v.Source, v.Target = parts[0], parts[1]
+			v.ReadOnly = slices.Contains(strings.Split(parts[2], ","), "ro")
+		}
+		return nil
+	}
+	type plain composeVolume
+	return node.Decode((*plain)(v))
+}
+
+// ImportCompose adds the services of a compose file labeled com.docker.mcp.server=true to a profile, as image servers.
+// Their image, environment, volumes, command, user and resource limits are kept. The profile is created if it doesn't exist,
+// and the servers it already has are updated from the services with the same name.
+func ImportCompose(ctx context.Context, dao db.DAO, filename string, id string, name string) error {
+	project, servers, err := readComposeServers(filename)
+	if err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("no service labeled %s=true in %s", ComposeServerLabel, filename)
+	}
+
+	var workingSet WorkingSet
+	created := false
+	if id != "" {
+		dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+		switch {
+		case err == nil:
+			workingSet = NewFromDb(dbWorkingSet)
+		case errors.Is(err, sql.ErrNoRows):
+			created = true
+		default:
+			return fmt.Errorf("failed to get profile: %w", err)
+		}
+	} else {
+		if name == "" {
+			name = project
+		}
+		id, err = createWorkingSetID(ctx, name, dao)
+		if err != nil {
+			return fmt.Errorf("failed to create profile id: %w", err)
+		}
+		created = true
+	}
+	if created {
+		if name == "" {
+			name = id
+		}
+		workingSet = WorkingSet{
+			ID:      id,
+			Name:    name,
+			Version: CurrentWorkingSetVersion,
+			Servers: make([]Server, 0),
+			Secrets: map[string]Secret{
+				"default": {Provider: SecretProviderDockerDesktop},
+			},
+		}
+	}
+
+	defaultSecret := "default"
+	if _, found := workingSet.Secrets[defaultSecret]; !found {
+		defaultSecret = ""
+	}
+
+	for _, server := range servers {
+		server.Secrets = defaultSecret
+		if existing := workingSet.FindServer(server.Snapshot.Server.Name); existing != nil {
+			// Re-importing updates the service, without losing what was configured in the profile
+			server.Secrets = existing.Secrets
+			server.Config = existing.Config
+			server.Tools = existing.Tools
+			server.Enabled = existing.Enabled
+			*existing = server
+		} else {
+			workingSet.Servers = append(workingSet.Servers, server)
+		}
+	}
+
+	if err := workingSet.Validate(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+
+	if created {
+		err = dao.CreateWorkingSet(ctx, workingSet.ToDb())
+	} else {
+		err = dao.UpdateWorkingSet(ctx, workingSet.ToDb())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+
+	fmt.Printf("Imported %d server(s) from %s into profile %s\n", len(servers), filename, id)
+
+	return nil
+}
+
+// readComposeServers returns the name of the compose project, and the servers of its services labeled as MCP servers.
+// Like with compose, the project is named after the directory of the file unless the file names it.
+func readComposeServers(filename string) (string, []Server, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	var compose composeFile
+	if err := yaml.Unmarshal(buf, &compose); err != nil {
+		return "", nil, fmt.Errorf("failed to parse compose file %s: %w", filename, err)
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return "", nil, err
+	}
+	project := compose.Name
+	if project == "" {
+		project = filepath.Base(dir)
+	}
+
+	var servers []Server
+	for _, serviceName := range slices.Sorted(maps.Keys(compose.Services)) {
+		service := compose.Services[serviceName]
+		if enabled, _ := strconv.ParseBool(service.Labels[ComposeServerLabel]); !enabled {
+			continue
+		}
+		if service.Image == "" {
+			return "", nil, fmt.Errorf("service %s has no image, build it and set its image first", serviceName)
+		}
+
+		servers = append(servers, Server{
+			Type:  ServerTypeImage,
+			Image: service.Image,
+			Snapshot: &ServerSnapshot{
+				Server: composeCatalogServer(serviceName, service, dir),
+			},
+		})
+	}
+
+	return project, servers, nil
+}
+
+func composeCatalogServer(serviceName string, service composeService, dir string) catalog.Server {
+	server := catalog.Server{
+		Name:           serviceName,
+		Type:           "server",
+		Image:          service.Image,
+		Command:        service.Command,
+		User:           service.User,
+		Platform:       service.Platform,
+		DisableNetwork: service.NetworkMode == "none",
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(service.Environment)) {
+		server.Env = append(server.Env, catalog.Env{Name: name, Value: service.Environment[name]})
+	}
+
+	for _, volume := range service.Volumes {
+		if volume.Source == "" || volume.Type == "tmpfs" {
+			// Anonymous volumes and tmpfs mounts don't outlive the container anyway
+			continue
+		}
+		source := volume.Source
+		if strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~") {
+			source = resolveComposePath(dir, source)
+		}
+		mount := source + ":" + volume.Target
+		if volume.ReadOnly {
+			mount += ":ro"
+		}
+		server.Volumes = append(server.Volumes, mount)
+	}
+
+	resources := catalog.Resources{
+		Cpus:   string(service.Deploy.Resources.Limits.Cpus),
+		Memory: string(service.Deploy.Resources.Limits.Memory),
+	}
+	if resources.Cpus == "" {
+		resources.Cpus = string(service.Cpus)
+	}
+	if resources.Memory == "" {
+		resources.Memory = string(service.MemLimit)
+	}
+	if resources != (catalog.Resources{}) {
+		server.Resources = &resources
+	}
+
+	return server
+}
+
+// resolveComposePath resolves the relative paths of bind mounts like compose does, from the directory of the compose file.
+func resolveComposePath(dir, path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,142 @@
+package workingset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+const testComposeFile = `
+name: Dev Tools
+services:
+  notes:
+    image: acme/notes-mcp:1.2
+    labels:
+      com.docker.mcp.server: "true"
+    environment:
+      NOTES_DIR: /notes
+      DEBUG:
+    volumes:
+      - ./notes:/notes:ro
+      - type: bind
+        source: /var/cache/notes
+        target: /cache
+      - /tmp
+    command: --transport stdio
+    user: "1000"
+    network_mode: none
+    deploy:
+      resources:
+        limits:
+          cpus: 0.5
+          memory: 256M
+  search:
+    image: acme/search-mcp
+    labels:
+      - com.docker.mcp.server=true
+    environment:
+      - INDEX=docs
+    cpus: 2
+    mem_limit: 1g
+  db:
+    image: postgres:17
+`
+
+func writeComposeFile(t *testing.T, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "compose.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0o644))
+	return filename
+}
+
+func TestImportCompose(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	filename := writeComposeFile(t, testComposeFile)
+
+	output := captureStdout(func() {
+		err := ImportCompose(ctx, dao, filename, "", "")
+		require.NoError(t, err)
+	})
+	assert.Equal(t, "Imported 2 server(s) from "+filename+" into profile dev-tools\n", output)
+
+	dbSet, err := dao.GetWorkingSet(ctx, "dev-tools")
+	require.NoError(t, err)
+	workingSet := NewFromDb(dbSet)
+	assert.Equal(t, "Dev Tools", workingSet.Name)
+	require.Len(t, workingSet.Servers, 2)
+
+	notes := workingSet.FindServer("notes")
+	require.NotNil(t, notes)
+	assert.Equal(t, ServerTypeImage, notes.Type)
+	assert.Equal(t, "acme/notes-mcp:1.2", notes.Image)
+	assert.Equal(t, "default", notes.Secrets)
+	assert.Equal(t, catalog.Server{
+		Name:           "notes",
+		Type:           "server",
+		Image:          "acme/notes-mcp:1.2",
+		Command:        []string{"--transport", "stdio"},
+		User:           "1000",
+		DisableNetwork: true,
+		Env:            []catalog.Env{{Name: "DEBUG", Value: ""}, {Name: "NOTES_DIR", Value: "/notes"}},
+		Volumes:        []string{filepath.Join(filepath.Dir(filename), "notes") + ":/notes:ro", "/var/cache/notes:/cache"},
+		Resources:      &catalog.Resources{Cpus: "0.5", Memory: "256M"},
+	}, notes.Snapshot.Server)
+
+	search := workingSet.FindServer("search")
+	require.NotNil(t, search)
+	assert.Equal(t, []catalog.Env{{Name: "INDEX", Value: "docs"}}, search.Snapshot.Server.Env)
+	assert.Equal(t, &catalog.Resources{Cpus: "2", Memory: "1g"}, search.Snapshot.Server.Resources)
+
+	assert.Nil(t, workingSet.FindServer("db"))
+}
+
+func TestImportComposeIntoExistingProfile(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	notes := makeServer("notes", nil, []string{"read_note"})
+	notes.Image = "acme/notes-mcp:1.0"
+	notes.Config = map[string]any{"notes": map[string]any{"dir": "/notes"}}
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "dev",
+		Name:    "Dev",
+		Servers: db.ServerList{notes, makeServer("github", nil)},
+	})
+	require.NoError(t, err)
+
+	captureStdout(func() {
+		err = ImportCompose(ctx, dao, writeComposeFile(t, testComposeFile), "dev", "")
+		require.NoError(t, err)
+	})
+
+	dbSet, err := dao.GetWorkingSet(ctx, "dev")
+	require.NoError(t, err)
+	workingSet := NewFromDb(dbSet)
+	assert.Equal(t, "Dev", workingSet.Name)
+	require.Len(t, workingSet.Servers, 3)
+
+	updated := workingSet.FindServer("notes")
+	require.NotNil(t, updated)
+	assert.Equal(t, "acme/notes-mcp:1.2", updated.Image)
+	assert.Equal(t, []string{"read_note"}, updated.Tools)
+	assert.Equal(t, map[string]any{"notes": map[string]any{"dir": "/notes"}}, updated.Config)
+	assert.Empty(t, updated.Secrets)
+	assert.NotNil(t, workingSet.FindServer("github"))
+}
+
+func TestImportComposeWithoutServers(t *testing.T) {
+	dao := setupTestDB(t)
+
+	err := ImportCompose(t.Context(), dao, writeComposeFile(t, "services:\n  db:\n    image: postgres:17\n"), "", "")
+	require.ErrorContains(t, err, "no service labeled com.docker.mcp.server=true")
+
+	err = ImportCompose(t.Context(), dao, writeComposeFile(t, "services:\n  app:\n    build: .\n    labels:\n      com.docker.mcp.server: \"true\"\n"), "", "")
+	require.ErrorContains(t, err, "service app has no image")
+}
@@ -33,32 +33,38 @@ func getMockOciService() oci.Service {
 }
 
 func getMockRegistryClient() registryapi.Client {
-	server := v0.ServerResponse{
-		Server: v0.ServerJSON{
-			Version: "0.1.0",
-			Packages: []model.Package{
-				{
-					RegistryType: "oci",
+	newServer := func(name string) v0.ServerResponse {
+		return v0.ServerResponse{
+			Server: v0.ServerJSON{
+				Name:    name,
+				Version: "0.1.0",
+				Packages: []model.Package{
+					{
+						RegistryType: "oci",
+						Identifier:   "example/" + name,
+					},
 				},
 			},
-		},
-		Meta: v0.ResponseMeta{
-			Official: &v0.RegistryExtensions{
-				IsLatest: true,
+			Meta: v0.ResponseMeta{
+				Official: &v0.RegistryExtensions{
+					IsLatest: true,
+				},
 			},
-		},
+		}
 	}
+	server1 := newServer("server1")
+	server2 := newServer("server2")
 
 	return mocks.NewMockRegistryAPIClient(mocks.WithServerListResponses(map[string]v0.ServerListResponse{
 		"https://example.com/v0/servers/server1/versions": {
-			Servers: []v0.ServerResponse{server},
+			Servers: []v0.ServerResponse{server1},
 		},
 		"https://example.com/v0/servers/server2/versions": {
-			Servers: []v0.ServerResponse{server},
+			Servers: []v0.ServerResponse{server2},
 		},
 	}), mocks.WithServerResponses(map[string]v0.ServerResponse{
-		"https://example.com/v0/servers/server1/versions/0.1.0": server,
-		"https://example.com/v0/servers/server2/versions/0.1.0": server,
+		"https://example.com/v0/servers/server1/versions/0.1.0": server1,
+		"https://example.com/v0/servers/server2/versions/0.1.0": server2,
 	}))
 }
 
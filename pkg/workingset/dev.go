@@ -0,0 +1,148 @@
+package workingset
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+// ServerDev overrides the container of a server when the gateway runs with --dev,
+// typically to mount a local checkout of the server's sources.
+type ServerDev struct {
+	// Volumes are mounted in the container, as /absolute/host/path:/container/path[:ro]
+	Volumes []string `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+	// Env sets environment variables in the container, overriding those of the catalog
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+}
+
+func (workingSet *WorkingSet) validateDevOverrides() error {
+	for _, server := range workingSet.Servers {
+		if server.Dev == nil {
+			continue
+		}
+		if server.Type == ServerTypeRemote || server.Type == ServerTypeGateway {
+			return fmt.Errorf("dev overrides are only supported by servers running in containers, not %s", server.BasicName())
+		}
+		for _, volume := range server.Dev.Volumes {
+			if err := validateDevVolume(volume); err != nil {
+				return fmt.Errorf("invalid dev volume of %s: %w", server.BasicName(), err)
+			}
+		}
+		for name := range server.Dev.Env {
+			if name == "" || strings.ContainsAny(name, "= ") {
+				return fmt.Errorf("invalid dev environment variable of %s: %q", server.BasicName(), name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateDevVolume(volume string) error {
+	parts := strings.Split(volume, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("%q, expected /host/path:/container/path[:ro]", volume)
+	}
+	if !filepath.IsAbs(parts[0]) {
+		return fmt.Errorf("%q, the host path must be absolute", volume)
+	}
+	if !strings.HasPrefix(parts[1], "/") {
+		return fmt.Errorf("%q, the container path must be absolute", volume)
+	}
+	if len(parts) == 3 && parts[2] != "ro" && parts[2] != "rw" {
+		return fmt.Errorf("%q, the mode must be ro or rw", volume)
+	}
+	return nil
+}
+
+// Apply returns the catalog entry of a server with the dev overrides.
+func (dev *ServerDev) Apply(server catalog.Server) catalog.Server {
+	if dev == nil {
+		return server
+	}
+
+	server.Volumes = append(slices.Clone(server.Volumes), dev.Volumes...)
+
+	env := slices.Clone(server.Env)
+	for _, name := range slices.Sorted(maps.Keys(dev.Env)) {
+		i := slices.IndexFunc(env, func(e catalog.Env) bool { return e.Name == name })
+		if i >= 0 {
+			env[i].Value = dev.Env[name]
+		} else {
+			env = append(env, catalog.Env{Name: name, Value: dev.Env[name]})
+		}
+	}
+	server.Env = env
+
+	return server
+}
+
+// SetServerDev adds dev volumes and environment variables to a server of a profile.
+// Relative host paths are made absolute. With clear, the existing dev overrides are removed first.
+func SetServerDev(ctx context.Context, dao db.DAO, id, serverName string, volumes, env []string, clear bool) error {
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	workingSet := NewFromDb(dbWorkingSet)
+
+	server := workingSet.FindServer(serverName)
+	if server == nil {
+		return fmt.Errorf("server %s not found in profile", serverName)
+	}
+	if clear || server.Dev == nil {
+		server.Dev = &ServerDev{}
+	}
+
+	for _, volume := range volumes {
+		source, target, found := strings.Cut(volume, ":")
+		if !found {
+			return fmt.Errorf("invalid volume %q, expected /host/path:/container/path[:ro]", volume)
+		}
+		source, err := filepath.Abs(source)
+		if err != nil {
+			return err
+		}
+		server.Dev.Volumes = append(server.Dev.Volumes, source+":"+target)
+	}
+	for _, variable := range env {
+		name, value, found := strings.Cut(variable, "=")
+		if !found {
+			return fmt.Errorf("invalid environment variable %q, expected NAME=value", variable)
+		}
+		if server.Dev.Env == nil {
+			server.Dev.Env = map[string]string{}
+		}
+		server.Dev.Env[name] = value
+	}
+	if len(server.Dev.Volumes) == 0 && len(server.Dev.Env) == 0 {
+		server.Dev = nil
+	}
+
+	if err := workingSet.Validate(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+
+	if err := dao.UpdateWorkingSet(ctx, workingSet.ToDb()); err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	if server.Dev == nil {
+		fmt.Printf("Cleared the dev overrides of %s in profile %s\n", serverName, id)
+	} else {
+		fmt.Printf("Set %d dev volume(s) and %d dev environment variable(s) on %s in profile %s, applied with docker mcp gateway run --dev\n", len(server.Dev.Volumes), len(server.Dev.Env), serverName, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package workingset
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func TestServerDevApply(t *testing.T) {
+	server := catalog.Server{
+		Name:    "my-server",
+		Image:   "my-server:latest",
+		Volumes: []string{"{{my-server.data}}:/data"},
+		Env:     []catalog.Env{{Name: "LOG_LEVEL", Value: "info"}, {Name: "MODE", Value: "prod"}},
+	}
+	dev := &ServerDev{
+		Volumes: []string{"/home/me/my-server/src:/app/src:ro"},
+		Env:     map[string]string{"LOG_LEVEL": "debug", "RELOAD": "1"},
+	}
+
+	applied := dev.Apply(server)
+
+	assert.Equal(t, []string{"{{my-server.data}}:/data", "/home/me/my-server/src:/app/src:ro"}, applied.Volumes)
+	assert.Equal(t, []catalog.Env{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "MODE", Value: "prod"}, {Name: "RELOAD", Value: "1"}}, applied.Env)
+	// The catalog entry is left untouched
+	assert.Equal(t, []string{"{{my-server.data}}:/data"}, server.Volumes)
+	assert.Equal(t, "info", server.Env[0].Value)
+
+	var none *ServerDev
+	assert.Equal(t, server, none.Apply(server))
+}
+
+func TestValidateDevOverrides(t *testing.T) {
+	workingSet := func(server Server) WorkingSet {
+		server.Snapshot = &ServerSnapshot{Server: catalog.Server{Name: "my-server"}}
+		return WorkingSet{Version: CurrentWorkingSetVersion, ID: "dev", Name: "Dev", Servers: []Server{server}}
+	}
+
+	valid := workingSet(Server{Type: ServerTypeImage, Image: "my-server:latest", Dev: &ServerDev{
+		Volumes: []string{"/src:/app/src", "/cache:/cache:rw"},
+		Env:     map[string]string{"DEBUG": "1"},
+	}})
+	require.NoError(t, valid.Validate())
+
+	for _, volume := range []string{"src:/app/src", "/src", "/src:app", "/src:/app:rx"} {
+		ws := workingSet(Server{Type: ServerTypeImage, Image: "my-server:latest", Dev: &ServerDev{Volumes: []string{volume}}})
+		require.ErrorContains(t, ws.Validate(), "invalid dev volume", volume)
+	}
+
+	ws := workingSet(Server{Type: ServerTypeImage, Image: "my-server:latest", Dev: &ServerDev{Env: map[string]string{"A=B": "1"}}})
+	require.ErrorContains(t, ws.Validate(), "invalid dev environment variable")
+
+	ws = workingSet(Server{Type: ServerTypeRemote, Endpoint: "https://example.com/mcp", Dev: &ServerDev{Env: map[string]string{"DEBUG": "1"}}})
+	require.ErrorContains(t, ws.Validate(), "only supported by servers running in containers")
+}
+
+func TestSetServerDev(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:   "dev",
+		Name: "Dev",
+		Servers: db.ServerList{{
+			Type:     "image",
+			Image:    "my-server:latest",
+			Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: "my-server", Image: "my-server:latest"}},
+		}},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	captureStdout(func() {
+		err = SetServerDev(ctx, dao, "dev", "my-server", []string{"./src:/app/src:ro"}, []string{"DEBUG=1"}, false)
+	})
+	require.NoError(t, err)
+
+	dbSet, err := dao.GetWorkingSet(ctx, "dev")
+	require.NoError(t, err)
+	src, err := filepath.Abs("./src")
+	require.NoError(t, err)
+	assert.Equal(t, &db.ServerDev{Volumes: []string{src + ":/app/src:ro"}, Env: map[string]string{"DEBUG": "1"}}, dbSet.Servers[0].Dev)
+
+	captureStdout(func() {
+		err = SetServerDev(ctx, dao, "dev", "my-server", nil, []string{"DEBUG=2"}, false)
+	})
+	require.NoError(t, err)
+	dbSet, err = dao.GetWorkingSet(ctx, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DEBUG": "2"}, dbSet.Servers[0].Dev.Env)
+	assert.Len(t, dbSet.Servers[0].Dev.Volumes, 1)
+
+	captureStdout(func() {
+		err = SetServerDev(ctx, dao, "dev", "my-server", nil, nil, true)
+	})
+	require.NoError(t, err)
+	dbSet, err = dao.GetWorkingSet(ctx, "dev")
+	require.NoError(t, err)
+	assert.Nil(t, dbSet.Servers[0].Dev)
+
+	err = SetServerDev(ctx, dao, "dev", "unknown", nil, []string{"DEBUG=1"}, false)
+	require.ErrorContains(t, err, "server unknown not found in profile")
+}
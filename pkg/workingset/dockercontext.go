@@ -0,0 +1,45 @@
+package workingset
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+// SetDockerContext binds a profile to a Docker context, or unbinds it when dockerContext is empty.
+func SetDockerContext(ctx context.Context, dao db.DAO, id string, dockerContext string) error {
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	workingSet := NewFromDb(dbWorkingSet)
+	workingSet.DockerContext = dockerContext
+
+	err = dao.UpdateWorkingSet(ctx, workingSet.ToDb())
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	if dockerContext == "" {
+		fmt.Printf("Profile %s is no longer bound to a Docker context\n", id)
+	} else {
+		fmt.Printf("Profile %s is bound to Docker context %s\n", id, dockerContext)
+	}
+
+	return nil
+}
+
+// CheckDockerContext fails if a profile is bound to a Docker context other than the current one.
+func (workingSet WorkingSet) CheckDockerContext(currentContext string) error {
+	if workingSet.DockerContext == "" || workingSet.DockerContext == currentContext {
+		return nil
+	}
+	return fmt.Errorf("profile %s is bound to Docker context %s, but the current context is %s. Switch with `docker context use %s` or unbind the profile with `docker mcp profile context %s --unset`", workingSet.ID, workingSet.DockerContext, currentContext, workingSet.DockerContext, workingSet.ID)
+}
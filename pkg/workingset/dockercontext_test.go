@@ -0,0 +1,56 @@
+package workingset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func TestSetDockerContext(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "test-set",
+		Name:    "Test Working Set",
+		Servers: db.ServerList{},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	output := captureStdout(func() {
+		require.NoError(t, SetDockerContext(ctx, dao, "test-set", "remote"))
+	})
+	assert.Contains(t, output, "Profile test-set is bound to Docker context remote")
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Equal(t, "remote", dbSet.DockerContext)
+	assert.Equal(t, "remote", NewFromDb(dbSet).DockerContext)
+
+	output = captureStdout(func() {
+		require.NoError(t, SetDockerContext(ctx, dao, "test-set", ""))
+	})
+	assert.Contains(t, output, "no longer bound")
+
+	dbSet, err = dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Empty(t, dbSet.DockerContext)
+
+	err = SetDockerContext(ctx, dao, "missing", "remote")
+	require.ErrorContains(t, err, "profile missing not found")
+}
+
+func TestCheckDockerContext(t *testing.T) {
+	unbound := WorkingSet{ID: "unbound"}
+	require.NoError(t, unbound.CheckDockerContext("default"))
+
+	bound := WorkingSet{ID: "bound", DockerContext: "remote"}
+	require.NoError(t, bound.CheckDockerContext("remote"))
+
+	err := bound.CheckDockerContext("default")
+	require.ErrorContains(t, err, "profile bound is bound to Docker context remote, but the current context is default")
+}
@@ -0,0 +1,96 @@
+package workingset
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+// gatewayTokenEnv is the variable, in the snapshot of a gateway server, holding the token of the gateway.
+const gatewayTokenEnv = "MCP_GATEWAY_AUTH_TOKEN"
+
+// GatewayTokenSecret is the name of the secret holding the Bearer token of a gateway server.
+func GatewayTokenSecret(name string) string {
+	return name + ".token"
+}
+
+// NewGatewayServer mounts another MCP gateway, listening with the streaming transport, as a server.
+// The tools of the gateway are aggregated under the prefix, which defaults to the name of the server.
+func NewGatewayServer(name, endpoint, prefix string) (Server, error) {
+	if name == "" || strings.ContainsAny(name, ": /") {
+		return Server{}, fmt.Errorf("invalid server name %q", name)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Server{}, fmt.Errorf("invalid gateway endpoint %q, expected http(s)://<host>:<port>/mcp", endpoint)
+	}
+	if prefix == "" {
+		prefix = name
+	}
+
+	return Server{
+		Type:     ServerTypeGateway,
+		Endpoint: endpoint,
+		Snapshot: &ServerSnapshot{
+			Server: catalog.Server{
+				Name:        name,
+				Type:        "remote",
+				Title:       name,
+				Description: fmt.Sprintf("Tools of the MCP gateway at %s", endpoint),
+				Remote: catalog.Remote{
+					URL:       endpoint,
+					Transport: "streamable-http",
+					Headers: map[string]string{
+						"Authorization": "Bearer ${" + gatewayTokenEnv + "}",
+					},
+				},
+				Secrets: []catalog.Secret{{
+					Name: GatewayTokenSecret(name),
+					Env:  gatewayTokenEnv,
+				}},
+				Prefix: prefix,
+			},
+		},
+	}, nil
+}
+
+// AddGatewayServer adds another MCP gateway to a profile, as a server.
+func AddGatewayServer(ctx context.Context, dao db.DAO, id, name, endpoint, prefix string) error {
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	workingSet := NewFromDb(dbWorkingSet)
+
+	server, err := NewGatewayServer(name, endpoint, prefix)
+	if err != nil {
+		return err
+	}
+	if _, found := workingSet.Secrets["default"]; found {
+		server.Secrets = "default"
+	}
+	workingSet.Servers = append(workingSet.Servers, server)
+
+	if err := workingSet.Validate(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+
+	if err := dao.UpdateWorkingSet(ctx, workingSet.ToDb()); err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	fmt.Printf("Added gateway %s to profile %s, its tools are prefixed with %s:\n", name, id, server.Snapshot.Server.Prefix)
+	fmt.Printf("Set its token with: docker mcp secret set %s=<token>\n", GatewayTokenSecret(name))
+
+	return nil
+}
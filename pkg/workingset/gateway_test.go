@@ -0,0 +1,91 @@
+package workingset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func TestNewGatewayServer(t *testing.T) {
+	server, err := NewGatewayServer("team-a", "https://team-a.example.com:8811/mcp", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, ServerTypeGateway, server.Type)
+	assert.Equal(t, "https://team-a.example.com:8811/mcp", server.Endpoint)
+	assert.Equal(t, catalog.Server{
+		Name:        "team-a",
+		Type:        "remote",
+		Title:       "team-a",
+		Description: "Tools of the MCP gateway at https://team-a.example.com:8811/mcp",
+		Remote: catalog.Remote{
+			URL:       "https://team-a.example.com:8811/mcp",
+			Transport: "streamable-http",
+			Headers:   map[string]string{"Authorization": "Bearer ${MCP_GATEWAY_AUTH_TOKEN}"},
+		},
+		Secrets: []catalog.Secret{{Name: "team-a.token", Env: "MCP_GATEWAY_AUTH_TOKEN"}},
+		Prefix:  "team-a",
+	}, server.Snapshot.Server)
+
+	server, err = NewGatewayServer("team-a", "http://localhost:8811/mcp", "a")
+	require.NoError(t, err)
+	assert.Equal(t, "a", server.Snapshot.Server.Prefix)
+}
+
+func TestNewGatewayServerInvalid(t *testing.T) {
+	_, err := NewGatewayServer("team:a", "http://localhost:8811/mcp", "")
+	require.ErrorContains(t, err, "invalid server name")
+
+	for _, endpoint := range []string{"", "localhost:8811", "ftp://localhost/mcp", "http:///mcp"} {
+		_, err := NewGatewayServer("team-a", endpoint, "")
+		require.ErrorContains(t, err, "invalid gateway endpoint", endpoint)
+	}
+}
+
+func TestAddGatewayServer(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "org",
+		Name:    "Org",
+		Servers: db.ServerList{},
+		Secrets: db.SecretMap{"default": {Provider: string(SecretProviderDockerDesktop)}},
+	})
+	require.NoError(t, err)
+
+	output := captureStdout(func() {
+		err := AddGatewayServer(ctx, dao, "org", "team-a", "http://team-a:8811/mcp", "")
+		require.NoError(t, err)
+	})
+	assert.Contains(t, output, "docker mcp secret set team-a.token=<token>")
+
+	dbSet, err := dao.GetWorkingSet(ctx, "org")
+	require.NoError(t, err)
+	workingSet := NewFromDb(dbSet)
+	require.Len(t, workingSet.Servers, 1)
+	assert.Equal(t, ServerTypeGateway, workingSet.Servers[0].Type)
+	assert.Equal(t, "http://team-a:8811/mcp", workingSet.Servers[0].Endpoint)
+	assert.Equal(t, "default", workingSet.Servers[0].Secrets)
+	assert.Equal(t, "team-a", workingSet.Servers[0].Snapshot.Server.Prefix)
+
+	// Server names must be unique
+	err = AddGatewayServer(ctx, dao, "org", "team-a", "http://other:8811/mcp", "")
+	require.Error(t, err)
+}
+
+func TestValidateGatewayServerNeedsEndpoint(t *testing.T) {
+	workingSet := WorkingSet{
+		Version: CurrentWorkingSetVersion,
+		ID:      "org",
+		Name:    "Org",
+		Servers: []Server{{Type: ServerTypeGateway}},
+	}
+	require.Error(t, workingSet.Validate())
+
+	workingSet.Servers[0].Endpoint = "http://team-a:8811/mcp"
+	require.NoError(t, workingSet.Validate())
+}
@@ -3,35 +3,19 @@ package workingset
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"strings"
-
-	"gopkg.in/yaml.v3"
 
 	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/pkg/oci"
 )
 
-func Import(ctx context.Context, dao db.DAO, ociService oci.Service, filename string) error {
-	workingSetBuf, err := os.ReadFile(filename)
+// Import creates or updates a profile from an exported file. With keepExisting, a profile with the same ID isn't
+// updated: the file is imported as a new profile, with a free ID derived from the one of the file.
+func Import(ctx context.Context, dao db.DAO, ociService oci.Service, filename string, keepExisting bool) error {
+	workingSet, err := readWorkingSetFile(filename)
 	if err != nil {
-		return fmt.Errorf("failed to read profile file: %w", err)
-	}
-
-	var workingSet WorkingSet
-	if strings.HasSuffix(strings.ToLower(filename), ".yaml") {
-		if err := yaml.Unmarshal(workingSetBuf, &workingSet); err != nil {
-			return fmt.Errorf("failed to unmarshal profile: %w", err)
-		}
-	} else if strings.HasSuffix(strings.ToLower(filename), ".json") {
-		if err := json.Unmarshal(workingSetBuf, &workingSet); err != nil {
-			return fmt.Errorf("failed to unmarshal profile: %w", err)
-		}
-	} else {
-		return fmt.Errorf("unsupported file extension: %s, must be .yaml or .json", filename)
+		return err
 	}
 
 	// Resolve snapshots for each server before saving
@@ -49,14 +33,25 @@ func Import(ctx context.Context, dao db.DAO, ociService oci.Service, filename st
 		return fmt.Errorf("invalid profile: %w", err)
 	}
 
-	dbSet := workingSet.ToDb()
-
 	_, err = dao.GetWorkingSet(ctx, workingSet.ID)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return fmt.Errorf("failed to get profile: %w", err)
 	}
+	exists := err == nil
+
+	if exists && keepExisting {
+		existingID := workingSet.ID
+		workingSet.ID, err = createWorkingSetID(ctx, existingID, dao)
+		if err != nil {
+			return fmt.Errorf("failed to create profile id: %w", err)
+		}
+		fmt.Printf("Profile %s already exists, importing as %s\n", existingID, workingSet.ID)
+		exists = false
+	}
+
+	dbSet := workingSet.ToDb()
 
-	if err != nil { // Not found
+	if !exists {
 		err = dao.CreateWorkingSet(ctx, dbSet)
 		if err != nil {
 			return fmt.Errorf("failed to create profile: %w", err)
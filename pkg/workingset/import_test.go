@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
+	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/db"
 	"github.com/docker/mcp-gateway/test/mocks"
 )
@@ -46,7 +47,7 @@ func TestImportYAML(t *testing.T) {
 	require.NoError(t, err)
 
 	// Import the file
-	err = Import(ctx, dao, getMockOciService(), yamlFile)
+	err = Import(ctx, dao, getMockOciService(), yamlFile, false)
 	require.NoError(t, err)
 
 	// Verify it was imported
@@ -99,7 +100,7 @@ func TestImportJSON(t *testing.T) {
 				DigestString: "sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
 			},
 		}))
-	err = Import(ctx, dao, mockOci, jsonFile)
+	err = Import(ctx, dao, mockOci, jsonFile, false)
 	require.NoError(t, err)
 
 	// Verify it was imported
@@ -139,7 +140,7 @@ func TestImportCreatesNewWorkingSet(t *testing.T) {
 	require.ErrorIs(t, err, sql.ErrNoRows)
 
 	// Import the file
-	err = Import(ctx, dao, getMockOciService(), yamlFile)
+	err = Import(ctx, dao, getMockOciService(), yamlFile, false)
 	require.NoError(t, err)
 
 	// Verify set was created
@@ -195,7 +196,7 @@ func TestImportUpdatesExistingWorkingSet(t *testing.T) {
 			DigestString: "sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
 		},
 	}))
-	err = Import(ctx, dao, mockOci, yamlFile)
+	err = Import(ctx, dao, mockOci, yamlFile, false)
 	require.NoError(t, err)
 
 	// Verify set was updated
@@ -209,12 +210,102 @@ func TestImportUpdatesExistingWorkingSet(t *testing.T) {
 	assert.Equal(t, "myimage:latest", dbSet.Servers[0].Image)
 }
 
+func TestImportKeepsExistingWorkingSet(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:   "existing-set",
+		Name: "Original Name",
+		Servers: db.ServerList{
+			{Type: "image", Image: "old:latest"},
+		},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	yamlFile := filepath.Join(t.TempDir(), "import.yaml")
+	data, err := yaml.Marshal(WorkingSet{
+		Version: CurrentWorkingSetVersion,
+		ID:      "existing-set",
+		Name:    "Imported Name",
+		Servers: []Server{
+			{Type: ServerTypeImage, Image: "myimage:latest"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(yamlFile, data, 0o644))
+
+	err = Import(ctx, dao, getMockOciService(), yamlFile, true)
+	require.NoError(t, err)
+
+	// The existing profile is untouched
+	dbSet, err := dao.GetWorkingSet(ctx, "existing-set")
+	require.NoError(t, err)
+	assert.Equal(t, "Original Name", dbSet.Name)
+	assert.Equal(t, "old:latest", dbSet.Servers[0].Image)
+
+	dbSet, err = dao.GetWorkingSet(ctx, "existing-set-2")
+	require.NoError(t, err)
+	assert.Equal(t, "Imported Name", dbSet.Name)
+	assert.Equal(t, "myimage:latest", dbSet.Servers[0].Image)
+
+	// Without a collision, the ID of the file is kept
+	require.NoError(t, dao.RemoveWorkingSet(ctx, "existing-set"))
+	err = Import(ctx, dao, getMockOciService(), yamlFile, true)
+	require.NoError(t, err)
+	dbSet, err = dao.GetWorkingSet(ctx, "existing-set")
+	require.NoError(t, err)
+	assert.Equal(t, "Imported Name", dbSet.Name)
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	source := setupTestDB(t)
+	destination := setupTestDB(t)
+	ctx := t.Context()
+
+	original := db.WorkingSet{
+		ID:   "team",
+		Name: "Team",
+		Servers: db.ServerList{
+			{
+				Type:     "image",
+				Image:    "myimage:latest",
+				Config:   map[string]any{"my-image": map[string]any{"path": "/data"}},
+				Secrets:  "aws",
+				Tools:    []string{"read", "write"},
+				Snapshot: &db.ServerSnapshot{Server: catalog.Server{Name: "my-image", Image: "myimage:latest"}},
+			},
+		},
+		Secrets: db.SecretMap{
+			"aws": {Provider: "aws-secrets-manager", Region: "eu-west-1", Prefix: "team/"},
+		},
+	}
+	require.NoError(t, source.CreateWorkingSet(ctx, original))
+
+	for _, filename := range []string{"team.yaml", "team.json"} {
+		file := filepath.Join(t.TempDir(), filename)
+		require.NoError(t, Export(ctx, source, "team", file))
+		require.NoError(t, Import(ctx, destination, getMockOciService(), file, false))
+
+		imported, err := destination.GetWorkingSet(ctx, "team")
+		require.NoError(t, err)
+		assert.Equal(t, original.Name, imported.Name)
+		assert.Equal(t, original.Secrets, imported.Secrets)
+		require.Len(t, imported.Servers, 1)
+		assert.Equal(t, original.Servers[0].Config, imported.Servers[0].Config)
+		assert.Equal(t, original.Servers[0].Secrets, imported.Servers[0].Secrets)
+		assert.Equal(t, original.Servers[0].Tools, imported.Servers[0].Tools)
+		assert.Equal(t, "my-image", imported.Servers[0].Snapshot.Server.Name)
+	}
+}
+
 func TestImportInvalidFile(t *testing.T) {
 	dao := setupTestDB(t)
 	ctx := t.Context()
 
 	// Try to import non-existent file
-	err := Import(ctx, dao, getMockOciService(), "/nonexistent/file.yaml")
+	err := Import(ctx, dao, getMockOciService(), "/nonexistent/file.yaml", false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to read")
 }
@@ -231,7 +322,7 @@ func TestImportInvalidYAML(t *testing.T) {
 	require.NoError(t, err)
 
 	// Try to import
-	err = Import(ctx, dao, getMockOciService(), yamlFile)
+	err = Import(ctx, dao, getMockOciService(), yamlFile, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to unmarshal")
 }
@@ -248,7 +339,7 @@ func TestImportInvalidJSON(t *testing.T) {
 	require.NoError(t, err)
 
 	// Try to import
-	err = Import(ctx, dao, getMockOciService(), jsonFile)
+	err = Import(ctx, dao, getMockOciService(), jsonFile, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to unmarshal")
 }
@@ -265,7 +356,7 @@ func TestImportUnsupportedExtension(t *testing.T) {
 	require.NoError(t, err)
 
 	// Try to import
-	err = Import(ctx, dao, getMockOciService(), txtFile)
+	err = Import(ctx, dao, getMockOciService(), txtFile, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported file extension")
 }
@@ -291,7 +382,7 @@ func TestImportValidationFailure(t *testing.T) {
 	require.NoError(t, err)
 
 	// Try to import
-	err = Import(ctx, dao, getMockOciService(), yamlFile)
+	err = Import(ctx, dao, getMockOciService(), yamlFile, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid profile")
 }
@@ -308,7 +399,7 @@ func TestImportEmptyFile(t *testing.T) {
 	require.NoError(t, err)
 
 	// Try to import
-	err = Import(ctx, dao, getMockOciService(), yamlFile)
+	err = Import(ctx, dao, getMockOciService(), yamlFile, false)
 	require.Error(t, err)
 	// Empty file will fail validation
 }
@@ -0,0 +1,404 @@
+package workingset
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+// introspectTimeout is how long a server has to start and list its tools when it's introspected.
+const introspectTimeout = 30 * time.Second
+
+// draftDescription marks the config schemas drafted by introspection, until they are reviewed.
+const draftDescription = "Draft generated by docker mcp profile server introspect, review before use"
+
+var (
+	// envName matches the names of environment variables in the output of a server, eg. GITHUB_TOKEN.
+	envName = regexp.MustCompile(`\b[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)+\b`)
+	// missingSetting matches the lines of the output of a server that tell a setting is missing.
+	missingSetting = regexp.MustCompile(`(?i)required|missing|not set|must be set|must be provided|not provided|not defined|undefined|no .* found|is empty|environment variable`)
+	// secretEnv matches the names of the environment variables that hold secrets.
+	secretEnv = regexp.MustCompile(`(?i)(TOKEN|KEY|SECRET|PASSWORD|CREDENTIALS?)$`)
+	// ignoredEnv are variables that servers commonly mention but that are not settings.
+	ignoredEnv = []string{"NODE_ENV", "NODE_OPTIONS", "PYTHONPATH", "LOG_LEVEL", "MCP_TRANSPORT", "NPM_CONFIG_UPDATE_NOTIFIER"}
+)
+
+// Probe is what was observed while running a server.
+type Probe struct {
+	// ImageEnv are the environment variables declared by the image, as NAME=value.
+	ImageEnv []string
+	// Output is what the server wrote to stderr, and the lines of stdout that are not JSON-RPC.
+	Output string
+	// Tools is the number of tools listed by the server, if it started.
+	Tools int
+	// Started tells whether the server answered the initialize request.
+	Started bool
+}
+
+// Prober runs a server to see what it needs.
+type Prober interface {
+	Probe(ctx context.Context, server catalog.Server) (Probe, error)
+}
+
+// DetectedSetting is a setting that a server seems to need.
+type DetectedSetting struct {
+	Env    string
+	Secret bool
+	Reason string
+}
+
+// Introspect runs a server of a profile without its missing settings, detects the environment variables it needs
+// from the image and from its errors, and drafts a config schema into the snapshot of the server, for review.
+// With dryRun, the draft is only shown.
+func Introspect(ctx context.Context, dao db.DAO, ociService oci.Service, prober Prober, id, serverName string, dryRun bool) error {
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	workingSet := NewFromDb(dbWorkingSet)
+	if err := workingSet.EnsureSnapshotsResolved(ctx, ociService); err != nil {
+		return fmt.Errorf("failed to resolve snapshots: %w", err)
+	}
+
+	server := workingSet.FindServer(serverName)
+	if server == nil {
+		return fmt.Errorf("server %s not found in profile", serverName)
+	}
+
+	spec := server.Snapshot.Server
+	if spec.IsCommandServer() {
+		spec, err = spec.Sandboxed()
+		if err != nil {
+			return err
+		}
+	}
+	if spec.Image == "" {
+		return fmt.Errorf("server %s doesn't run in a container, only image and package servers can be introspected", serverName)
+	}
+
+	fmt.Printf("Running %s to detect its settings...\n", serverName)
+	probe, err := prober.Probe(ctx, spec)
+	if err != nil {
+		return fmt.Errorf("failed to run server %s: %w", serverName, err)
+	}
+	if probe.Started {
+		fmt.Printf("Server %s started and listed %d tool(s)\n", serverName, probe.Tools)
+	} else {
+		fmt.Printf("Server %s didn't start\n", serverName)
+	}
+
+	settings := detectSettings(server.Snapshot.Server, probe)
+	if len(settings) == 0 {
+		fmt.Printf("No missing setting detected for %s\n", serverName)
+		return nil
+	}
+
+	drafted := draftSettings(server.Snapshot.Server, settings)
+
+	fmt.Printf("Detected %d setting(s):\n", len(settings))
+	for _, setting := range settings {
+		kind := "config"
+		if setting.Secret {
+			kind = "secret"
+		}
+		fmt.Printf("  - %s (%s): %s\n", setting.Env, kind, setting.Reason)
+	}
+
+	draft, err := yaml.Marshal(map[string]any{
+		"config":  drafted.Config,
+		"env":     drafted.Env,
+		"secrets": drafted.Secrets,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal draft: %w", err)
+	}
+	fmt.Printf("\n%s\n", draft)
+
+	if dryRun {
+		return nil
+	}
+
+	server.Snapshot.Server = drafted
+	if err := workingSet.Validate(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+	if err := dao.UpdateWorkingSet(ctx, workingSet.ToDb()); err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	fmt.Printf("Drafted the settings of %s into profile %s, review them with docker mcp profile show %s\n", serverName, id, id)
+	return nil
+}
+
+// detectSettings returns the environment variables that a server seems to need and that it isn't configured with:
+// the ones declared empty by its image, and the ones named in its errors.
+func detectSettings(server catalog.Server, probe Probe) []DetectedSetting {
+	known := map[string]bool{}
+	for _, env := range server.Env {
+		known[env.Name] = true
+	}
+	for _, secret := range server.Secrets {
+		known[secret.Env] = true
+	}
+
+	detected := map[string]DetectedSetting{}
+	add := func(name, reason string) {
+		if known[name] || slices.Contains(ignoredEnv, name) {
+			return
+		}
+		if _, exists := detected[name]; exists {
+			return
+		}
+		detected[name] = DetectedSetting{Env: name, Secret: secretEnv.MatchString(name), Reason: reason}
+	}
+
+	for _, line := range strings.Split(probe.Output, "\n") {
+		if !missingSetting.MatchString(line) {
+			continue
+		}
+		for _, name := range envName.FindAllString(line, -1) {
+			add(name, fmt.Sprintf("server output: %q", strings.TrimSpace(truncate(line, 120))))
+		}
+	}
+
+	for _, env := range probe.ImageEnv {
+		name, value, _ := strings.Cut(env, "=")
+		if value == "" {
+			add(name, "declared empty by the image")
+		}
+	}
+
+	var settings []DetectedSetting
+	for _, setting := range detected {
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool {
+		return settings[i].Env < settings[j].Env
+	})
+	return settings
+}
+
+// draftSettings adds the detected settings to a server: secrets for the ones that hold secrets,
+// and properties of a draft config schema, passed as environment variables, for the others.
+func draftSettings(server catalog.Server, settings []DetectedSetting) catalog.Server {
+	drafted := server
+	drafted.Env = slices.Clone(server.Env)
+	drafted.Secrets = slices.Clone(server.Secrets)
+	drafted.Config = slices.Clone(server.Config)
+
+	schema, index := configSchema(drafted.Config, server.Name)
+	properties, _ := schema["properties"].(map[string]any)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	required := toStrings(schema["required"])
+
+	for _, setting := range settings {
+		property := strings.ToLower(setting.Env)
+		if setting.Secret {
+			drafted.Secrets = append(drafted.Secrets, catalog.Secret{
+				Name: server.Name + "." + property,
+				Env:  setting.Env,
+			})
+			continue
+		}
+
+		properties[property] = map[string]any{
+			"type":        "string",
+			"description": setting.Reason,
+		}
+		if !slices.Contains(required, property) {
+			required = append(required, property)
+		}
+		drafted.Env = append(drafted.Env, catalog.Env{
+			Name:  setting.Env,
+			Value: fmt.Sprintf("{{%s.%s}}", server.Name, property),
+		})
+	}
+
+	if len(properties) == 0 {
+		return drafted
+	}
+	schema["properties"] = properties
+	schema["required"] = required
+	if index < 0 {
+		drafted.Config = append(drafted.Config, schema)
+	} else {
+		drafted.Config[index] = schema
+	}
+	return drafted
+}
+
+// configSchema returns a copy of the config schema of a server, and its index, or a new draft schema and -1.
+func configSchema(config []any, serverName string) (map[string]any, int) {
+	for i, item := range config {
+		schema, ok := item.(map[string]any)
+		if ok && schema["name"] == serverName {
+			clone := make(map[string]any, len(schema))
+			for k, v := range schema {
+				clone[k] = v
+			}
+			if properties, ok := schema["properties"].(map[string]any); ok {
+				clonedProperties := make(map[string]any, len(properties))
+				for k, v := range properties {
+					clonedProperties[k] = v
+				}
+				clone["properties"] = clonedProperties
+			}
+			return clone, i
+		}
+	}
+
+	return map[string]any{
+		"name":        serverName,
+		"type":        "object",
+		"description": draftDescription,
+	}, -1
+}
+
+func toStrings(value any) []string {
+	var values []string
+	switch v := value.(type) {
+	case []string:
+		values = append(values, v...)
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+// DockerProber runs servers with the docker CLI, like the gateway does.
+type DockerProber struct{}
+
+func (DockerProber) Probe(ctx context.Context, server catalog.Server) (Probe, error) {
+	ctx, cancel := context.WithTimeout(ctx, introspectTimeout)
+	defer cancel()
+
+	var probe Probe
+
+	// Pull the image if needed, and read the environment it declares
+	if _, err := exec.CommandContext(ctx, "docker", "image", "inspect", server.Image).Output(); err != nil {
+		if out, err := exec.CommandContext(ctx, "docker", "pull", server.Image).CombinedOutput(); err != nil {
+			return Probe{}, fmt.Errorf("pulling %s: %w: %s", server.Image, err, out)
+		}
+	}
+	out, err := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", "{{json .Config.Env}}", server.Image).Output()
+	if err != nil {
+		return Probe{}, fmt.Errorf("inspecting %s: %w", server.Image, err)
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &probe.ImageEnv); err != nil {
+		return Probe{}, fmt.Errorf("inspecting %s: %w", server.Image, err)
+	}
+
+	// Run the server with its static environment only, and ask it to list its tools
+	args := []string{"run", "--rm", "-i", "--init", "--pull", "never", "-l", "docker-mcp=true", "-l", "docker-mcp-introspect=true"}
+	if server.User != "" && !strings.Contains(server.User, "{{") {
+		args = append(args, "-u", server.User)
+	}
+	for _, env := range server.Env {
+		if env.Value != "" && !strings.Contains(env.Value, "{{") && !strings.Contains(env.Value, "$") {
+			args = append(args, "-e", env.Name+"="+env.Value)
+		}
+	}
+	args = append(args, server.Image)
+	args = append(args, server.Command...)
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second
+	cmd.Stdin = strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"docker-mcp-introspect","version":"1.0.0"}}}` + "\n" +
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return Probe{}, err
+	}
+	if err := cmd.Start(); err != nil {
+		return Probe{}, err
+	}
+
+	var other []string
+	probe.Started, probe.Tools, other = readProbeResponses(stdout, cancel)
+	_ = cmd.Wait()
+
+	probe.Output = strings.TrimSpace(stderr.String() + "\n" + strings.Join(other, "\n"))
+	return probe, nil
+}
+
+// readProbeResponses reads the responses of a server to the initialize and tools/list requests.
+// It stops the server once it listed its tools.
+func readProbeResponses(stdout io.Reader, stop func()) (bool, int, []string) {
+	var (
+		started bool
+		tools   int
+		other   []string
+	)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var response struct {
+			ID     int             `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			other = append(other, scanner.Text())
+			continue
+		}
+		if response.Error != nil {
+			other = append(other, response.Error.Message)
+		}
+
+		switch response.ID {
+		case 1:
+			started = response.Result != nil
+		case 2:
+			var result struct {
+				Tools []json.RawMessage `json:"tools"`
+			}
+			_ = json.Unmarshal(response.Result, &result)
+			tools = len(result.Tools)
+			stop()
+			return started, tools, other
+		}
+	}
+
+	return started, tools, other
+}
@@ -0,0 +1,171 @@
+package workingset
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/test/mocks"
+)
+
+type fakeProber struct {
+	probe  Probe
+	server catalog.Server
+}
+
+func (p *fakeProber) Probe(_ context.Context, server catalog.Server) (Probe, error) {
+	p.server = server
+	return p.probe, nil
+}
+
+func createIntrospectedWorkingSet(t *testing.T, dao db.DAO, server catalog.Server) {
+	t.Helper()
+
+	err := dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "test-set",
+		Name: "Test Working Set",
+		Servers: db.ServerList{
+			{
+				Type:     "image",
+				Image:    "example/weather:latest",
+				Snapshot: &db.ServerSnapshot{Server: server},
+			},
+		},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+}
+
+func TestIntrospect(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	createIntrospectedWorkingSet(t, dao, catalog.Server{
+		Name:  "weather",
+		Type:  "server",
+		Image: "example/weather:latest",
+		Env:   []catalog.Env{{Name: "UNITS", Value: "metric"}},
+	})
+
+	prober := &fakeProber{probe: Probe{
+		ImageEnv: []string{"PATH=/usr/local/bin:/usr/bin", "WEATHER_REGION=", "NODE_ENV="},
+		Output: strings.Join([]string{
+			"Starting weather server",
+			"Error: WEATHER_API_KEY environment variable is required",
+			"UNITS must be set, using WEATHER_API_KEY and DEFAULT_CITY",
+		}, "\n"),
+	}}
+
+	output := captureStdout(func() {
+		err := Introspect(ctx, dao, mocks.NewMockOCIService(), prober, "test-set", "weather", false)
+		require.NoError(t, err)
+	})
+
+	assert.Equal(t, "example/weather:latest", prober.server.Image)
+	assert.Contains(t, output, "Server weather didn't start")
+	assert.Contains(t, output, "Detected 3 setting(s)")
+	assert.Contains(t, output, "  - WEATHER_API_KEY (secret)")
+	assert.Contains(t, output, "  - WEATHER_REGION (config): declared empty by the image")
+	assert.NotContains(t, output, "NODE_ENV")
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	server := dbSet.Servers[0].Snapshot.Server
+	assert.Equal(t, []catalog.Secret{{Name: "weather.weather_api_key", Env: "WEATHER_API_KEY"}}, server.Secrets)
+	assert.Equal(t, []catalog.Env{
+		{Name: "UNITS", Value: "metric"},
+		{Name: "DEFAULT_CITY", Value: "{{weather.default_city}}"},
+		{Name: "WEATHER_REGION", Value: "{{weather.weather_region}}"},
+	}, server.Env)
+	require.Len(t, server.Config, 1)
+	schema := server.Config[0].(map[string]any)
+	assert.Equal(t, "weather", schema["name"])
+	assert.Equal(t, draftDescription, schema["description"])
+	assert.ElementsMatch(t, []any{"default_city", "weather_region"}, schema["required"])
+	assert.Contains(t, schema["properties"], "default_city")
+	assert.Contains(t, schema["properties"], "weather_region")
+}
+
+func TestIntrospectKeepsExistingSchema(t *testing.T) {
+	existing := map[string]any{
+		"name":     "weather",
+		"type":     "object",
+		"required": []any{"units"},
+		"properties": map[string]any{
+			"units": map[string]any{"type": "string"},
+		},
+	}
+
+	drafted := draftSettings(catalog.Server{
+		Name:   "weather",
+		Config: []any{existing},
+	}, []DetectedSetting{{Env: "DEFAULT_CITY", Reason: "declared empty by the image"}})
+
+	require.Len(t, drafted.Config, 1)
+	schema := drafted.Config[0].(map[string]any)
+	assert.Equal(t, []string{"units", "default_city"}, schema["required"])
+	assert.Len(t, schema["properties"], 2)
+	// The original schema is left untouched
+	assert.Len(t, existing["properties"], 1)
+}
+
+func TestIntrospectDryRun(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	createIntrospectedWorkingSet(t, dao, catalog.Server{Name: "weather", Type: "server", Image: "example/weather:latest"})
+
+	prober := &fakeProber{probe: Probe{Output: "missing API_TOKEN"}}
+	output := captureStdout(func() {
+		err := Introspect(ctx, dao, mocks.NewMockOCIService(), prober, "test-set", "weather", true)
+		require.NoError(t, err)
+	})
+	assert.Contains(t, output, "API_TOKEN (secret)")
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Empty(t, dbSet.Servers[0].Snapshot.Server.Secrets)
+}
+
+func TestIntrospectNothingDetected(t *testing.T) {
+	dao := setupTestDB(t)
+	createIntrospectedWorkingSet(t, dao, catalog.Server{Name: "weather", Type: "server", Image: "example/weather:latest"})
+
+	prober := &fakeProber{probe: Probe{Started: true, Tools: 2}}
+	output := captureStdout(func() {
+		err := Introspect(t.Context(), dao, mocks.NewMockOCIService(), prober, "test-set", "weather", false)
+		require.NoError(t, err)
+	})
+	assert.Contains(t, output, "Server weather started and listed 2 tool(s)")
+	assert.Contains(t, output, "No missing setting detected for weather")
+}
+
+func TestIntrospectErrors(t *testing.T) {
+	dao := setupTestDB(t)
+	createIntrospectedWorkingSet(t, dao, catalog.Server{Name: "remote", Type: "remote", Remote: catalog.Remote{URL: "https://example.com/mcp"}})
+
+	err := Introspect(t.Context(), dao, mocks.NewMockOCIService(), &fakeProber{}, "missing", "remote", false)
+	require.ErrorContains(t, err, "profile missing not found")
+
+	err = Introspect(t.Context(), dao, mocks.NewMockOCIService(), &fakeProber{}, "test-set", "other", false)
+	require.ErrorContains(t, err, "server other not found in profile")
+
+	err = Introspect(t.Context(), dao, mocks.NewMockOCIService(), &fakeProber{}, "test-set", "remote", false)
+	require.ErrorContains(t, err, "only image and package servers can be introspected")
+}
+
+func TestReadProbeResponses(t *testing.T) {
+	stopped := false
+	started, tools, other := readProbeResponses(strings.NewReader(`warning: no config
+{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-06-18"}}
+{"jsonrpc":"2.0","id":2,"result":{"tools":[{"name":"a"},{"name":"b"}]}}
+`), func() { stopped = true })
+
+	assert.True(t, started)
+	assert.Equal(t, 2, tools)
+	assert.Equal(t, []string{"warning: no config"}, other)
+	assert.True(t, stopped)
+}
@@ -0,0 +1,205 @@
+package workingset
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// profileSchema is the JSON Schema of the YAML and JSON representations of profiles.
+//
+//go:embed profile.schema.json
+var profileSchema []byte
+
+// Schema returns the JSON Schema of the YAML and JSON representations of profiles.
+func Schema() []byte {
+	return profileSchema
+}
+
+type resolvedSchemas struct {
+	profile *jsonschema.Resolved
+	server  *jsonschema.Resolved
+	secret  *jsonschema.Resolved
+}
+
+var loadSchemas = sync.OnceValues(func() (*resolvedSchemas, error) {
+	var root jsonschema.Schema
+	if err := json.Unmarshal(profileSchema, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse profile schema: %w", err)
+	}
+
+	// Servers and secrets are validated on their own to report where errors are
+	resolve := func(schema *jsonschema.Schema) (*jsonschema.Resolved, error) {
+		resolved, err := schema.Resolve(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve profile schema: %w", err)
+		}
+		return resolved, nil
+	}
+	var (
+		schemas resolvedSchemas
+		err     error
+	)
+	if schemas.profile, err = resolve(&root); err != nil {
+		return nil, err
+	}
+	if schemas.server, err = resolve(&jsonschema.Schema{Ref: "#/$defs/server", Defs: root.Defs}); err != nil {
+		return nil, err
+	}
+	if schemas.secret, err = resolve(&jsonschema.Schema{Ref: "#/$defs/secret", Defs: root.Defs}); err != nil {
+		return nil, err
+	}
+	return &schemas, nil
+})
+
+// ValidateFile validates a profile file offline: against the profile schema and the rules enforced when
+// profiles are saved. Snapshots are not resolved.
+func ValidateFile(filename string) error {
+	workingSet, err := readWorkingSetFile(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := workingSet.Validate(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+
+	fmt.Printf("Profile file %s is valid\n", filename)
+
+	return nil
+}
+
+// readWorkingSetFile reads a YAML or JSON profile file and enforces the profile schema.
+func readWorkingSetFile(filename string) (WorkingSet, error) {
+	workingSetBuf, err := os.ReadFile(filename)
+	if err != nil {
+		return WorkingSet{}, fmt.Errorf("failed to read profile file: %w", err)
+	}
+
+	var (
+		workingSet WorkingSet
+		document   any
+	)
+	if strings.HasSuffix(strings.ToLower(filename), ".yaml") {
+		if err := yaml.Unmarshal(workingSetBuf, &document); err != nil {
+			return WorkingSet{}, fmt.Errorf("failed to unmarshal profile: %w", err)
+		}
+		// Validate the same values as for a JSON file
+		if document, err = yamlToJSON(document); err != nil {
+			return WorkingSet{}, fmt.Errorf("failed to unmarshal profile: %w", err)
+		}
+		if err := validateSchema(document); err != nil {
+			return WorkingSet{}, fmt.Errorf("invalid profile: %w", err)
+		}
+		if err := yaml.Unmarshal(workingSetBuf, &workingSet); err != nil {
+			return WorkingSet{}, fmt.Errorf("failed to unmarshal profile: %w", err)
+		}
+	} else if strings.HasSuffix(strings.ToLower(filename), ".json") {
+		if err := json.Unmarshal(workingSetBuf, &document); err != nil {
+			return WorkingSet{}, fmt.Errorf("failed to unmarshal profile: %w", err)
+		}
+		if err := validateSchema(document); err != nil {
+			return WorkingSet{}, fmt.Errorf("invalid profile: %w", err)
+		}
+		if err := json.Unmarshal(workingSetBuf, &workingSet); err != nil {
+			return WorkingSet{}, fmt.Errorf("failed to unmarshal profile: %w", err)
+		}
+	} else {
+		return WorkingSet{}, fmt.Errorf("unsupported file extension: %s, must be .yaml or .json", filename)
+	}
+
+	return workingSet, nil
+}
+
+func yamlToJSON(document any) (any, error) {
+	buf, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	var converted any
+	if err := json.Unmarshal(buf, &converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// validateSchema validates a profile against the schema. Errors point to the invalid value, eg. servers[1].type.
+func validateSchema(document any) error {
+	schemas, err := loadSchemas()
+	if err != nil {
+		return err
+	}
+
+	if profile, ok := document.(map[string]any); ok {
+		if servers, ok := profile["servers"].([]any); ok {
+			for i, server := range servers {
+				if err := schemas.server.Validate(server); err != nil {
+					return schemaError(fmt.Sprintf("servers[%d]", i), err)
+				}
+			}
+		}
+		if secrets, ok := profile["secrets"].(map[string]any); ok {
+			for _, name := range slices.Sorted(maps.Keys(secrets)) {
+				if err := schemas.secret.Validate(secrets[name]); err != nil {
+					return schemaError("secrets."+name, err)
+				}
+			}
+		}
+	}
+
+	if err := schemas.profile.Validate(document); err != nil {
+		return schemaError("", err)
+	}
+	return nil
+}
+
+var schemaPathPrefix = regexp.MustCompile(`^validating ([^ ]+): `)
+
+// schemaError rewrites the errors of the schema validator, that are nested by schema location,
+// into errors that point to the invalid value.
+func schemaError(path string, err error) error {
+	message := err.Error()
+	field := ""
+	for {
+		match := schemaPathPrefix.FindStringSubmatch(message)
+		if match == nil {
+			break
+		}
+		message = message[len(match[0]):]
+
+		// The innermost property is the invalid field
+		if property, found := lastProperty(match[1]); found {
+			field = property
+		}
+	}
+
+	switch {
+	case path != "" && field != "":
+		path += "." + field
+	case field != "":
+		path = field
+	}
+	if path == "" {
+		return fmt.Errorf("%s", message)
+	}
+	return fmt.Errorf("%s: %s", path, message)
+}
+
+// lastProperty returns the property a schema location ends with, eg. type for /$defs/server/properties/type.
+func lastProperty(location string) (string, bool) {
+	i := strings.LastIndex(location, "/properties/")
+	if i < 0 {
+		return "", false
+	}
+	property := location[i+len("/properties/"):]
+	return property, !strings.Contains(property, "/")
+}
@@ -0,0 +1,143 @@
+package workingset
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+)
+
+func TestSchemaIsValidJSON(t *testing.T) {
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(Schema(), &schema))
+	assert.Equal(t, "Docker MCP profile", schema["title"])
+
+	_, err := loadSchemas()
+	require.NoError(t, err)
+}
+
+func TestExportedProfilesAreValid(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	server := makeServer("github", []catalog.Tool{{Name: "create_issue"}}, []string{"create_issue"})
+	server.Config = map[string]any{"owner": "docker", "retries": 3}
+	server.Secrets = "default"
+	server.OAuthScopes = []string{"repo"}
+	server.ToolTransforms = map[string]string{"create_issue": ".url"}
+	server.UpdatePolicy = string(UpdatePolicyTrackTag)
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "test-set",
+		Name:    "Test Working Set",
+		Servers: db.ServerList{server, makeServer("fetch", nil)},
+		Secrets: db.SecretMap{
+			"default": {Provider: string(SecretProviderDockerDesktop)},
+			"vault":   {Provider: string(SecretProviderOnePassword), References: map[string]string{"github.token": "op://dev/GitHub/credential"}},
+		},
+		DockerContext: "desktop-linux",
+	})
+	require.NoError(t, err)
+
+	for _, filename := range []string{"profile.yaml", "profile.json"} {
+		t.Run(filename, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), filename)
+			captureStdout(func() {
+				require.NoError(t, Export(ctx, dao, "test-set", path))
+			})
+
+			output := captureStdout(func() {
+				require.NoError(t, ValidateFile(path))
+			})
+			assert.Equal(t, "Profile file "+path+" is valid\n", output)
+		})
+	}
+}
+
+func TestValidateFileErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		filename      string
+		content       string
+		expectedError string
+	}{
+		{
+			name:          "unknown field",
+			filename:      "profile.yaml",
+			content:       "version: 1\nid: test\nname: Test\nservers:\n  - type: image\n    image: mcp/fetch\n    tool: [fetch]\n",
+			expectedError: `invalid profile: servers[0]: unexpected additional properties ["tool"]`,
+		},
+		{
+			name:          "invalid server type",
+			filename:      "profile.yaml",
+			content:       "version: 1\nid: test\nname: Test\nservers:\n  - type: image\n    image: mcp/fetch\n  - type: docker\n",
+			expectedError: "invalid profile: servers[1].type: enum",
+		},
+		{
+			name:          "missing image",
+			filename:      "profile.json",
+			content:       `{"version": 1, "id": "test", "name": "Test", "servers": [{"type": "image"}]}`,
+			expectedError: `invalid profile: servers[0]: required: missing properties: ["image"]`,
+		},
+		{
+			name:          "invalid secret provider",
+			filename:      "profile.yaml",
+			content:       "version: 1\nid: test\nname: Test\nservers: []\nsecrets:\n  default:\n    provider: vault\n",
+			expectedError: "invalid profile: secrets.default.provider: enum",
+		},
+		{
+			name:          "unsupported version",
+			filename:      "profile.yaml",
+			content:       "version: 2\nid: test\nname: Test\nservers: []\n",
+			expectedError: "invalid profile: version: const",
+		},
+		{
+			name:          "empty id",
+			filename:      "profile.json",
+			content:       `{"version": 1, "id": "", "name": "Test", "servers": []}`,
+			expectedError: "invalid profile: id: minLength",
+		},
+		{
+			name:          "duplicate server names",
+			filename:      "profile.yaml",
+			content:       "version: 1\nid: test\nname: Test\nservers:\n  - type: image\n    image: mcp/a\n    snapshot:\n      server:\n        name: same\n  - type: image\n    image: mcp/b\n    snapshot:\n      server:\n        name: same\n",
+			expectedError: "invalid profile",
+		},
+		{
+			name:          "unsupported extension",
+			filename:      "profile.txt",
+			content:       "",
+			expectedError: "unsupported file extension",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.filename)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+
+			err := ValidateFile(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestImportEnforcesSchema(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	path := filepath.Join(t.TempDir(), "profile.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("version: 1\nid: test\nname: Test\nservers:\n  - type: image\n    image: mcp/fetch\n    tool: [fetch]\n"), 0o644))
+
+	err := Import(ctx, dao, getMockOciService(), path, false)
+	require.ErrorContains(t, err, `invalid profile: servers[0]: unexpected additional properties ["tool"]`)
+
+	_, err = dao.GetWorkingSet(ctx, "test")
+	require.Error(t, err)
+}
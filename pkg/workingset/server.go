@@ -68,7 +68,10 @@ func AddServers(ctx context.Context, dao db.DAO, registryClient registryapi.Clie
 	return nil
 }
 
-func RemoveServers(ctx context.Context, dao db.DAO, id string, serverNames []string) error {
+// RemoveServers removes servers from a profile, along with their config and tool allowlists.
+// Unless keepConfig is set, the secret providers that no remaining server uses are removed too,
+// except for the default one.
+func RemoveServers(ctx context.Context, dao db.DAO, id string, serverNames []string, keepConfig bool) error {
 	if len(serverNames) == 0 {
 		return fmt.Errorf("at least one server must be specified")
 	}
@@ -85,6 +88,9 @@ func RemoveServers(ctx context.Context, dao db.DAO, id string, serverNames []str
 
 	namesToRemove := make(map[string]bool)
 	for _, name := range serverNames {
+		if workingSet.FindServer(name) == nil {
+			return fmt.Errorf("server %s not found in profile", name)
+		}
 		namesToRemove[name] = true
 	}
 
@@ -98,12 +104,13 @@ func RemoveServers(ctx context.Context, dao db.DAO, id string, serverNames []str
 	}
 
 	removedCount := originalCount - len(filtered)
-	if removedCount == 0 {
-		return fmt.Errorf("no matching servers found to remove")
-	}
-
 	workingSet.Servers = filtered
 
+	var removedSecrets []string
+	if !keepConfig {
+		removedSecrets = workingSet.removeUnusedSecrets()
+	}
+
 	if err := workingSet.Validate(); err != nil {
 		return fmt.Errorf("invalid profile: %w", err)
 	}
@@ -114,10 +121,71 @@ func RemoveServers(ctx context.Context, dao db.DAO, id string, serverNames []str
 	}
 
 	fmt.Printf("Removed %d server(s) from profile %s\n", removedCount, id)
+	if len(removedSecrets) > 0 {
+		fmt.Printf("Removed unused secret provider(s) from profile %s: %s\n", id, strings.Join(removedSecrets, ", "))
+	}
+
+	return nil
+}
+
+// SetServersEnabled enables or disables servers of a profile. A disabled server keeps its config
+// and tools but isn't started by the gateway.
+func SetServersEnabled(ctx context.Context, dao db.DAO, id string, serverNames []string, enabled bool) error {
+	if len(serverNames) == 0 {
+		return fmt.Errorf("at least one server must be specified")
+	}
+
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	workingSet := NewFromDb(dbWorkingSet)
+
+	for _, serverName := range serverNames {
+		server := workingSet.FindServer(serverName)
+		if server == nil {
+			return fmt.Errorf("server %s not found in profile", serverName)
+		}
+		// Servers are enabled by default, only the disabled ones are recorded
+		if enabled {
+			server.Enabled = nil
+		} else {
+			server.Enabled = &enabled
+		}
+	}
+
+	err = dao.UpdateWorkingSet(ctx, workingSet.ToDb())
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
 
 	return nil
 }
 
+// removeUnusedSecrets removes the secret providers that no server uses, except for the default one
+// that's given to new servers. It returns the names of the providers that were removed.
+func (workingSet *WorkingSet) removeUnusedSecrets() []string {
+	used := map[string]bool{"default": true}
+	for _, server := range workingSet.Servers {
+		used[server.Secrets] = true
+	}
+
+	var removed []string
+	for name := range workingSet.Secrets {
+		if !used[name] {
+			delete(workingSet.Secrets, name)
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	return removed
+}
+
 type SearchResult struct {
 	ID      string   `json:"id" yaml:"id"`
 	Name    string   `json:"name" yaml:"name"`
@@ -2,6 +2,8 @@ package workingset
 
 import (
 	"encoding/json"
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -102,7 +104,7 @@ func TestRemoveOneServerFromWorkingSet(t *testing.T) {
 
 	err = RemoveServers(ctx, dao, setID, []string{
 		"My Image",
-	})
+	}, false)
 	require.NoError(t, err)
 
 	dbSet, err = dao.GetWorkingSet(ctx, setID)
@@ -129,7 +131,7 @@ func TestRemoveMultipleServersFromWorkingSet(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, dbSet.Servers, 2)
 
-	err = RemoveServers(ctx, dao, workingSetID, []string{"My Image", "Another Image"})
+	err = RemoveServers(ctx, dao, workingSetID, []string{"My Image", "Another Image"}, false)
 	require.NoError(t, err)
 
 	dbSet, err = dao.GetWorkingSet(ctx, workingSetID)
@@ -155,7 +157,7 @@ func TestRemoveOneOfManyServerFromWorkingSet(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, dbSet.Servers, 2)
 
-	err = RemoveServers(ctx, dao, workingSetID, []string{"My Image"})
+	err = RemoveServers(ctx, dao, workingSetID, []string{"My Image"}, false)
 	require.NoError(t, err)
 
 	dbSet, err = dao.GetWorkingSet(ctx, workingSetID)
@@ -177,7 +179,7 @@ func TestRemoveNoServersFromWorkingSet(t *testing.T) {
 	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), workingSetID, "My Test Set", servers, []string{})
 	require.NoError(t, err)
 
-	err = RemoveServers(ctx, dao, workingSetID, []string{})
+	err = RemoveServers(ctx, dao, workingSetID, []string{}, false)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), oneServerError)
 }
@@ -803,3 +805,160 @@ func TestListServersUnsupportedFilterKey(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported filter key")
 }
+
+func TestRemoveServersCascade(t *testing.T) {
+	withSecrets := func(server db.Server, secrets string, config map[string]any) db.Server {
+		server.Secrets = secrets
+		server.Config = config
+		return server
+	}
+
+	tests := []struct {
+		name            string
+		servers         []db.Server
+		secrets         db.SecretMap
+		removeArgs      []string
+		keepConfig      bool
+		expectedOutput  string
+		expectedSecrets []string
+	}{
+		{
+			name: "remove a server and its unused secret provider",
+			servers: []db.Server{
+				withSecrets(makeServer("github", []catalog.Tool{{Name: "create_issue"}}, []string{"create_issue"}), "github-vault", map[string]any{"owner": "docker"}),
+				withSecrets(makeServer("slack", []catalog.Tool{{Name: "post"}}), "default", nil),
+			},
+			secrets: db.SecretMap{
+				"default":      {Provider: string(SecretProviderDockerDesktop)},
+				"github-vault": {Provider: string(SecretProviderOnePassword), Vault: "github"},
+			},
+			removeArgs:      []string{"github"},
+			expectedOutput:  "Removed 1 server(s) from profile test-set\nRemoved unused secret provider(s) from profile test-set: github-vault\n",
+			expectedSecrets: []string{"default"},
+		},
+		{
+			name: "secret provider still used by another server",
+			servers: []db.Server{
+				withSecrets(makeServer("github", []catalog.Tool{{Name: "create_issue"}}), "shared", nil),
+				withSecrets(makeServer("gitlab", []catalog.Tool{{Name: "create_issue"}}), "shared", nil),
+			},
+			secrets: db.SecretMap{
+				"shared": {Provider: string(SecretProviderDockerDesktop)},
+			},
+			removeArgs:      []string{"github"},
+			expectedOutput:  "Removed 1 server(s) from profile test-set\n",
+			expectedSecrets: []string{"shared"},
+		},
+		{
+			name: "keep config",
+			servers: []db.Server{
+				withSecrets(makeServer("github", []catalog.Tool{{Name: "create_issue"}}), "github-vault", nil),
+			},
+			secrets: db.SecretMap{
+				"github-vault": {Provider: string(SecretProviderOnePassword), Vault: "github"},
+			},
+			removeArgs:      []string{"github"},
+			keepConfig:      true,
+			expectedOutput:  "Removed 1 server(s) from profile test-set\n",
+			expectedSecrets: []string{"github-vault"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := setupTestDB(t)
+			ctx := t.Context()
+
+			err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+				ID:      "test-set",
+				Name:    "Test Working Set",
+				Servers: tt.servers,
+				Secrets: tt.secrets,
+			})
+			require.NoError(t, err)
+
+			output := captureStdout(func() {
+				err = RemoveServers(ctx, dao, "test-set", tt.removeArgs, tt.keepConfig)
+				require.NoError(t, err)
+			})
+			assert.Equal(t, tt.expectedOutput, output)
+
+			dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+			require.NoError(t, err)
+			for _, server := range dbSet.Servers {
+				assert.NotContains(t, tt.removeArgs, server.Snapshot.Server.Name)
+			}
+			assert.ElementsMatch(t, tt.expectedSecrets, slices.Collect(maps.Keys(dbSet.Secrets)))
+		})
+	}
+}
+
+func TestRemoveServerThenReAddStartsClean(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", "test-set", []string{"docker://myimage:latest"}, []string{})
+	require.NoError(t, err)
+
+	// Configure the server and restrict its tools before removing it
+	require.NoError(t, UpdateConfig(ctx, dao, getMockOciService(), "test-set", []string{"My Image.owner=docker"}, nil, nil, false, OutputFormatHumanReadable))
+	require.NoError(t, UpdateTools(ctx, dao, "test-set", nil, nil, nil, []string{"My Image"}))
+
+	require.NoError(t, RemoveServers(ctx, dao, "test-set", []string{"My Image"}, false))
+	require.NoError(t, AddServers(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", []string{"docker://myimage:latest"}))
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	require.Len(t, dbSet.Servers, 1)
+	assert.Empty(t, dbSet.Servers[0].Config)
+	assert.Nil(t, dbSet.Servers[0].Tools)
+}
+
+func TestRemoveUnknownServer(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", "test-set", []string{"docker://myimage:latest"}, []string{})
+	require.NoError(t, err)
+
+	err = RemoveServers(ctx, dao, "test-set", []string{"My Image", "unknown"}, false)
+	require.ErrorContains(t, err, "server unknown not found in profile")
+
+	// Nothing was removed
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Len(t, dbSet.Servers, 1)
+}
+
+func TestSetServersEnabled(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := Create(ctx, dao, getMockRegistryClient(), getMockOciService(), "test-set", "test-set", []string{
+		"docker://myimage:latest",
+	}, []string{})
+	require.NoError(t, err)
+
+	err = SetServersEnabled(ctx, dao, "test-set", []string{"My Image"}, false)
+	require.NoError(t, err)
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	require.NotNil(t, dbSet.Servers[0].Enabled)
+	assert.False(t, *dbSet.Servers[0].Enabled)
+	assert.False(t, NewFromDb(dbSet).Servers[0].IsEnabled())
+
+	err = SetServersEnabled(ctx, dao, "test-set", []string{"My Image"}, true)
+	require.NoError(t, err)
+
+	dbSet, err = dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Nil(t, dbSet.Servers[0].Enabled)
+	assert.True(t, NewFromDb(dbSet).Servers[0].IsEnabled())
+
+	err = SetServersEnabled(ctx, dao, "test-set", []string{"unknown"}, false)
+	require.ErrorContains(t, err, "server unknown not found in profile")
+
+	err = SetServersEnabled(ctx, dao, "test-set", nil, false)
+	require.ErrorContains(t, err, "at least one server must be specified")
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -75,19 +77,46 @@ func printHumanReadable(workingSet WorkingSet) string {
 			servers += fmt.Sprintf("    Source: %s\n", server.Source)
 		case ServerTypeImage:
 			servers += fmt.Sprintf("    Image: %s\n", server.Image)
-		case ServerTypeRemote:
+		case ServerTypeRemote, ServerTypeGateway:
 			servers += fmt.Sprintf("    Endpoint: %s\n", server.Endpoint)
 		}
 		servers += fmt.Sprintf("    Config: %v\n", server.Config)
 		servers += fmt.Sprintf("    Secrets: %s\n", server.Secrets)
 		servers += fmt.Sprintf("    Tools: %v\n", server.Tools)
+		if !server.IsEnabled() {
+			servers += "    Enabled: false\n"
+		}
+		if server.Dev != nil {
+			for _, volume := range server.Dev.Volumes {
+				servers += fmt.Sprintf("    Dev volume: %s\n", volume)
+			}
+			for _, name := range slices.Sorted(maps.Keys(server.Dev.Env)) {
+				servers += fmt.Sprintf("    Dev env: %s=%s\n", name, server.Dev.Env[name])
+			}
+		}
 	}
 	servers = strings.TrimSuffix(servers, "\n")
 	secrets := ""
 	for name, secret := range workingSet.Secrets {
 		secrets += fmt.Sprintf("  - Name: %s\n", name)
 		secrets += fmt.Sprintf("    Provider: %s\n", secret.Provider)
+		if secret.Region != "" {
+			secrets += fmt.Sprintf("    Region: %s\n", secret.Region)
+		}
+		if secret.Prefix != "" {
+			secrets += fmt.Sprintf("    Prefix: %s\n", secret.Prefix)
+		}
+		if secret.Vault != "" {
+			secrets += fmt.Sprintf("    Vault: %s\n", secret.Vault)
+		}
+		for _, secretName := range slices.Sorted(maps.Keys(secret.References)) {
+			secrets += fmt.Sprintf("    Reference: %s -> %s\n", secretName, secret.References[secretName])
+		}
 	}
 	secrets = strings.TrimSuffix(secrets, "\n")
-	return fmt.Sprintf("ID: %s\nName: %s\nServers:\n%s\nSecrets:\n%s", workingSet.ID, workingSet.Name, servers, secrets)
+	dockerContext := ""
+	if workingSet.DockerContext != "" {
+		dockerContext = fmt.Sprintf("Docker Context: %s\n", workingSet.DockerContext)
+	}
+	return fmt.Sprintf("ID: %s\nName: %s\n%sServers:\n%s\nSecrets:\n%s", workingSet.ID, workingSet.Name, dockerContext, servers, secrets)
 }
@@ -134,3 +134,89 @@ func UpdateTools(ctx context.Context, dao db.DAO, id string, enable, disable, en
 
 	return nil
 }
+
+// UpdateToolsets enables or disables whole groups of tools, given as <serverName>.<toolset>.
+// The groups are those declared by the servers' snapshots.
+func UpdateToolsets(ctx context.Context, dao db.DAO, id string, enable, disable []string) error {
+	if len(enable) == 0 && len(disable) == 0 {
+		return fmt.Errorf("must provide at least one flag: --enable-toolset or --disable-toolset")
+	}
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+	workingSet := NewFromDb(dbWorkingSet)
+
+	enabledCount := 0
+	for _, toolsetArg := range enable {
+		server, tools, err := findToolset(&workingSet, toolsetArg)
+		if err != nil {
+			return err
+		}
+		for _, toolName := range tools {
+			if !slices.Contains(server.Tools, toolName) {
+				server.Tools = append(server.Tools, toolName)
+				enabledCount++
+			}
+		}
+	}
+
+	disabledCount := 0
+	for _, toolsetArg := range disable {
+		server, tools, err := findToolset(&workingSet, toolsetArg)
+		if err != nil {
+			return err
+		}
+
+		// If Tools is nil (all tools enabled), expand it to include all tools from snapshot
+		if server.Tools == nil {
+			server.Tools = make([]string, 0, len(server.Snapshot.Server.Tools))
+			for _, tool := range server.Snapshot.Server.Tools {
+				server.Tools = append(server.Tools, tool.Name)
+			}
+		}
+
+		for _, toolName := range tools {
+			if idx := slices.Index(server.Tools, toolName); idx != -1 {
+				server.Tools = slices.Delete(server.Tools, idx, idx+1)
+				disabledCount++
+			}
+		}
+	}
+
+	err = dao.UpdateWorkingSet(ctx, workingSet.ToDb())
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	if enabledCount == 0 && disabledCount == 0 {
+		fmt.Printf("No changes made to profile %s\n", id)
+	} else {
+		fmt.Printf("Updated profile %s: %d tool(s) enabled, %d tool(s) disabled\n", id, enabledCount, disabledCount)
+	}
+
+	return nil
+}
+
+// findToolset returns the server of a <serverName>.<toolset> argument and the tools of the group.
+func findToolset(workingSet *WorkingSet, toolsetArg string) (*Server, []string, error) {
+	serverName, toolsetName, found := strings.Cut(toolsetArg, ".")
+	if !found {
+		return nil, nil, fmt.Errorf("invalid toolset argument: %s, expected <serverName>.<toolset>", toolsetArg)
+	}
+	server := workingSet.FindServer(serverName)
+	if server == nil {
+		return nil, nil, fmt.Errorf("server %s not found in profile for argument %s", serverName, toolsetArg)
+	}
+	if server.Snapshot == nil {
+		return nil, nil, fmt.Errorf("server %s has no snapshot, can't resolve toolset %s", serverName, toolsetName)
+	}
+	tools, found := server.Snapshot.Server.Toolsets[toolsetName]
+	if !found {
+		return nil, nil, fmt.Errorf("toolset %s not found for server %s", toolsetName, serverName)
+	}
+	return server, tools, nil
+}
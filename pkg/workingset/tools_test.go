@@ -689,3 +689,95 @@ func TestToolsUpdateOperationsErrors(t *testing.T) {
 		})
 	}
 }
+
+func makeToolsetServer(initialTools []string) db.Server {
+	server := makeServer("github", []catalog.Tool{{Name: "create_issue"}, {Name: "list_issues"}, {Name: "list_workflows"}}, initialTools)
+	server.Snapshot.Server.Toolsets = catalog.Toolsets{
+		"issues":  {"create_issue", "list_issues"},
+		"actions": {"list_workflows"},
+	}
+	return server
+}
+
+func TestToolsetsUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		server         db.Server
+		enableArgs     []string
+		disableArgs    []string
+		expectedOutput string
+		expectedTools  []string
+	}{
+		{
+			name:           "all tools enabled, disable a toolset",
+			server:         makeToolsetServer(nil),
+			disableArgs:    []string{"github.actions"},
+			expectedOutput: "Updated profile test-set: 0 tool(s) enabled, 1 tool(s) disabled\n",
+			expectedTools:  []string{"create_issue", "list_issues"},
+		},
+		{
+			name:           "no tools enabled, enable a toolset",
+			server:         makeToolsetServer([]string{}),
+			enableArgs:     []string{"github.issues"},
+			expectedOutput: "Updated profile test-set: 2 tool(s) enabled, 0 tool(s) disabled\n",
+			expectedTools:  []string{"create_issue", "list_issues"},
+		},
+		{
+			name:           "toolset already enabled",
+			server:         makeToolsetServer([]string{"list_workflows"}),
+			enableArgs:     []string{"github.actions"},
+			expectedOutput: "No changes made to profile test-set\n",
+			expectedTools:  []string{"list_workflows"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := setupTestDB(t)
+			ctx := t.Context()
+
+			err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+				ID:      "test-set",
+				Name:    "Test Working Set",
+				Servers: []db.Server{tt.server},
+				Secrets: db.SecretMap{},
+			})
+			require.NoError(t, err)
+
+			output := captureStdout(func() {
+				err = UpdateToolsets(ctx, dao, "test-set", tt.enableArgs, tt.disableArgs)
+				require.NoError(t, err)
+			})
+			assert.Equal(t, tt.expectedOutput, output)
+
+			dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTools, dbSet.Servers[0].Tools)
+		})
+	}
+}
+
+func TestToolsetsUpdateErrors(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:      "test-set",
+		Name:    "Test Working Set",
+		Servers: []db.Server{makeToolsetServer(nil)},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	err = UpdateToolsets(ctx, dao, "test-set", nil, nil)
+	require.ErrorContains(t, err, "must provide at least one flag")
+
+	err = UpdateToolsets(ctx, dao, "test-set", []string{"github"}, nil)
+	require.ErrorContains(t, err, "invalid toolset argument: github, expected <serverName>.<toolset>")
+
+	err = UpdateToolsets(ctx, dao, "test-set", []string{"gitlab.issues"}, nil)
+	require.ErrorContains(t, err, "server gitlab not found in profile")
+
+	err = UpdateToolsets(ctx, dao, "test-set", nil, []string{"github.pulls"})
+	require.ErrorContains(t, err, "toolset pulls not found for server github")
+}
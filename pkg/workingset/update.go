@@ -0,0 +1,333 @@
+package workingset
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/registryapi"
+)
+
+type UpdatePolicy string
+
+const (
+	// UpdatePolicyPinned never updates the server. This is the default.
+	UpdatePolicyPinned UpdatePolicy = "pinned"
+	// UpdatePolicyTrackTag updates an image server to the digest its tag currently points to.
+	UpdatePolicyTrackTag UpdatePolicy = "track-tag"
+	// UpdatePolicyTrackLatest updates a registry server to the latest version published in the MCP Registry.
+	UpdatePolicyTrackLatest UpdatePolicy = "track-latest"
+)
+
+// UpdatePolicies lists the valid update policies.
+var UpdatePolicies = []UpdatePolicy{UpdatePolicyPinned, UpdatePolicyTrackTag, UpdatePolicyTrackLatest}
+
+func (workingSet *WorkingSet) validateUpdatePolicies() error {
+	for _, server := range workingSet.Servers {
+		switch server.UpdatePolicy {
+		case UpdatePolicyTrackTag:
+			if server.Type != ServerTypeImage {
+				return fmt.Errorf("update policy %s is only supported by image servers, not %s", server.UpdatePolicy, server.BasicName())
+			}
+		case UpdatePolicyTrackLatest:
+			if server.Type != ServerTypeRegistry {
+				return fmt.Errorf("update policy %s is only supported by registry servers, not %s", server.UpdatePolicy, server.BasicName())
+			}
+		}
+	}
+	return nil
+}
+
+// SetUpdatePolicy sets the update policy of servers of a profile.
+func SetUpdatePolicy(ctx context.Context, dao db.DAO, id string, serverNames []string, policy UpdatePolicy) error {
+	if len(serverNames) == 0 {
+		return fmt.Errorf("at least one server must be specified")
+	}
+	if !slices.Contains(UpdatePolicies, policy) {
+		return fmt.Errorf("invalid update policy %q, expected one of: %s", policy, joinUpdatePolicies())
+	}
+
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	workingSet := NewFromDb(dbWorkingSet)
+
+	for _, serverName := range serverNames {
+		server := workingSet.FindServer(serverName)
+		if server == nil {
+			return fmt.Errorf("server %s not found in profile", serverName)
+		}
+		server.UpdatePolicy = policy
+	}
+
+	if err := workingSet.Validate(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+
+	err = dao.UpdateWorkingSet(ctx, workingSet.ToDb())
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	fmt.Printf("Set update policy %s on %d server(s) in profile %s\n", policy, len(serverNames), id)
+
+	return nil
+}
+
+// serverUpdate is an update available for a server of a profile.
+type serverUpdate struct {
+	name         string
+	from         string
+	to           string
+	addedTools   []string
+	removedTools []string
+	changedTools []string
+
+	index  int
+	server Server
+}
+
+// Update applies the updates available for the servers of a profile, according to their update policies.
+// With dryRun, the updates are only listed.
+func Update(ctx context.Context, dao db.DAO, registryClient registryapi.Client, ociService oci.Service, id string, dryRun bool) error {
+	dbWorkingSet, err := dao.GetWorkingSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("profile %s not found", id)
+		}
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	workingSet := NewFromDb(dbWorkingSet)
+
+	updates, err := findUpdates(ctx, registryClient, ociService, workingSet)
+	if err != nil {
+		return err
+	}
+
+	if len(updates) == 0 {
+		fmt.Printf("All servers in profile %s are up to date\n", id)
+		return nil
+	}
+
+	for _, update := range updates {
+		fmt.Print(update.String())
+	}
+
+	if dryRun {
+		fmt.Printf("%d update(s) available for profile %s\n", len(updates), id)
+		return nil
+	}
+
+	for _, update := range updates {
+		workingSet.Servers[update.index] = update.server
+	}
+
+	if err := workingSet.Validate(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+
+	err = dao.UpdateWorkingSet(ctx, workingSet.ToDb())
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	fmt.Printf("Updated %d server(s) in profile %s\n", len(updates), id)
+
+	return nil
+}
+
+func findUpdates(ctx context.Context, registryClient registryapi.Client, ociService oci.Service, workingSet WorkingSet) ([]serverUpdate, error) {
+	var updates []serverUpdate
+
+	for i, server := range workingSet.Servers {
+		var (
+			updated Server
+			changed bool
+			err     error
+		)
+		switch server.UpdatePolicy {
+		case UpdatePolicyTrackTag:
+			updated, changed, err = updateTrackedTag(ctx, ociService, server)
+		case UpdatePolicyTrackLatest:
+			updated, changed, err = updateTrackedLatest(ctx, registryClient, server)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for updates of %s: %w", serverName(server), err)
+		}
+		if !changed {
+			continue
+		}
+
+		update := serverUpdate{
+			name:   serverName(server),
+			from:   server.BasicName(),
+			to:     updated.BasicName(),
+			index:  i,
+			server: updated,
+		}
+		update.addedTools, update.removedTools, update.changedTools = diffTools(server.Snapshot, updated.Snapshot)
+		updates = append(updates, update)
+	}
+
+	return updates, nil
+}
+
+// updateTrackedTag resolves the image that the tag of an image server points to now.
+// Images pinned to a digest are looked up in the registry, other images are local images: only their
+// snapshot can change, when they are rebuilt.
+func updateTrackedTag(ctx context.Context, ociService oci.Service, server Server) (Server, bool, error) {
+	image := server.Image
+	if tag, _, pinned := strings.Cut(server.Image, "@"); pinned {
+		ref, err := name.ParseReference(tag)
+		if err != nil {
+			return server, false, fmt.Errorf("failed to parse reference: %w", err)
+		}
+		img, err := ociService.GetRemoteImage(ctx, ref)
+		if err != nil {
+			return server, false, fmt.Errorf("failed to get remote image: %w", err)
+		}
+		digest, err := ociService.GetImageDigest(img)
+		if err != nil {
+			return server, false, fmt.Errorf("failed to get image digest: %w", err)
+		}
+		image = fmt.Sprintf("%s@%s", tag, digest)
+	}
+
+	snapshot, err := ResolveImageSnapshot(ctx, ociService, image)
+	if err != nil {
+		return server, false, err
+	}
+
+	if image == server.Image && server.Snapshot != nil && snapshotJSON(server.Snapshot) == snapshotJSON(snapshot) {
+		return server, false, nil
+	}
+
+	updated := server
+	updated.Image = image
+	updated.Snapshot = snapshot
+	return updated, true, nil
+}
+
+// updateTrackedLatest resolves the latest version of a registry server.
+func updateTrackedLatest(ctx context.Context, registryClient registryapi.Client, server Server) (Server, bool, error) {
+	url, err := registryapi.ParseServerURL(server.Source)
+	if err != nil {
+		return server, false, fmt.Errorf("failed to parse server URL %s: %w", server.Source, err)
+	}
+
+	source, err := ResolveRegistry(ctx, registryClient, url.WithVersion("latest").String())
+	if err != nil {
+		return server, false, err
+	}
+	if source == server.Source {
+		return server, false, nil
+	}
+
+	snapshot, err := ResolveRegistrySnapshot(ctx, registryClient, source)
+	if err != nil {
+		return server, false, err
+	}
+
+	updated := server
+	updated.Source = source
+	updated.Snapshot = snapshot
+	return updated, true, nil
+}
+
+// diffTools compares the tools of two snapshots of a server, by name.
+func diffTools(before, after *ServerSnapshot) (added, removed, changed []string) {
+	oldTools := snapshotTools(before)
+	newTools := snapshotTools(after)
+
+	for name, tool := range newTools {
+		oldTool, found := oldTools[name]
+		switch {
+		case !found:
+			added = append(added, name)
+		case toolJSON(oldTool) != toolJSON(tool):
+			changed = append(changed, name)
+		}
+	}
+	for name := range oldTools {
+		if _, found := newTools[name]; !found {
+			removed = append(removed, name)
+		}
+	}
+
+	slices.Sort(added)
+	slices.Sort(removed)
+	slices.Sort(changed)
+	return added, removed, changed
+}
+
+func snapshotTools(snapshot *ServerSnapshot) map[string]catalog.Tool {
+	tools := make(map[string]catalog.Tool)
+	if snapshot == nil {
+		return tools
+	}
+	for _, tool := range snapshot.Server.Tools {
+		tools[tool.Name] = tool
+	}
+	return tools
+}
+
+// toolJSON and snapshotJSON compare values by their serialized form, which ignores the
+// differences between nil and empty fields that a round trip through the database introduces.
+func toolJSON(tool catalog.Tool) string {
+	buf, _ := json.Marshal(tool)
+	return string(buf)
+}
+
+func snapshotJSON(snapshot *ServerSnapshot) string {
+	buf, _ := json.Marshal(snapshot)
+	return string(buf)
+}
+
+func serverName(server Server) string {
+	if server.Snapshot != nil {
+		return server.Snapshot.Server.Name
+	}
+	return server.BasicName()
+}
+
+func (u serverUpdate) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s: %s -> %s\n", u.name, u.from, u.to)
+	for _, tool := range u.addedTools {
+		fmt.Fprintf(&sb, "  + %s\n", tool)
+	}
+	for _, tool := range u.removedTools {
+		fmt.Fprintf(&sb, "  - %s\n", tool)
+	}
+	for _, tool := range u.changedTools {
+		fmt.Fprintf(&sb, "  ~ %s\n", tool)
+	}
+
+	return sb.String()
+}
+
+func joinUpdatePolicies() string {
+	policies := make([]string, len(UpdatePolicies))
+	for i, policy := range UpdatePolicies {
+		policies[i] = string(policy)
+	}
+	return strings.Join(policies, ", ")
+}
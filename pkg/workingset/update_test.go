@@ -0,0 +1,228 @@
+package workingset
+
+import (
+	"testing"
+
+	v0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/db"
+	"github.com/docker/mcp-gateway/pkg/oci"
+	"github.com/docker/mcp-gateway/pkg/registryapi"
+	"github.com/docker/mcp-gateway/test/mocks"
+)
+
+const (
+	oldDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	newDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+func getMockUpdatedOciService() oci.Service {
+	return mocks.NewMockOCIService(mocks.WithRemoteImages([]mocks.MockImage{
+		{
+			Ref: "tracked:v1",
+			Labels: map[string]string{
+				"io.docker.server.metadata": `name: tracked
+tools:
+  - name: search
+    description: Search everything
+  - name: create
+    description: Create an item
+`,
+			},
+			DigestString: newDigest,
+		},
+	}))
+}
+
+func getMockUpdatedRegistryClient() registryapi.Client {
+	version := func(v string, latest bool) v0.ServerResponse {
+		return v0.ServerResponse{
+			Server: v0.ServerJSON{
+				Name:     "server1",
+				Version:  v,
+				Packages: []model.Package{{RegistryType: "oci"}},
+			},
+			Meta: v0.ResponseMeta{Official: &v0.RegistryExtensions{IsLatest: latest}},
+		}
+	}
+
+	return mocks.NewMockRegistryAPIClient(mocks.WithServerListResponses(map[string]v0.ServerListResponse{
+		"https://example.com/v0/servers/server1/versions": {
+			Servers: []v0.ServerResponse{version("0.1.0", false), version("0.2.0", true)},
+		},
+	}), mocks.WithServerResponses(map[string]v0.ServerResponse{
+		"https://example.com/v0/servers/server1/versions/0.2.0": version("0.2.0", true),
+	}))
+}
+
+func createTrackedWorkingSet(t *testing.T, dao db.DAO, policy UpdatePolicy) {
+	t.Helper()
+
+	err := dao.CreateWorkingSet(t.Context(), db.WorkingSet{
+		ID:   "test-set",
+		Name: "Test Working Set",
+		Servers: db.ServerList{
+			{
+				Type:         "image",
+				Image:        "tracked:v1@" + oldDigest,
+				UpdatePolicy: string(policy),
+				Snapshot: &db.ServerSnapshot{Server: catalog.Server{
+					Name: "tracked",
+					Tools: []catalog.Tool{
+						{Name: "search", Description: "Search"},
+						{Name: "delete", Description: "Delete an item"},
+					},
+				}},
+			},
+			{
+				Type:         "registry",
+				Source:       "https://example.com/v0/servers/server1/versions/0.1.0",
+				UpdatePolicy: string(UpdatePolicyTrackLatest),
+			},
+		},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+}
+
+func TestUpdateTrackedServers(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	createTrackedWorkingSet(t, dao, UpdatePolicyTrackTag)
+
+	output := captureStdout(func() {
+		err := Update(ctx, dao, getMockUpdatedRegistryClient(), getMockUpdatedOciService(), "test-set", false)
+		require.NoError(t, err)
+	})
+
+	assert.Contains(t, output, "tracked: tracked:v1@"+oldDigest+" -> tracked:v1@"+newDigest)
+	assert.Contains(t, output, "  + create\n")
+	assert.Contains(t, output, "  - delete\n")
+	assert.Contains(t, output, "  ~ search\n")
+	assert.Contains(t, output, "Updated 2 server(s) in profile test-set")
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Equal(t, "tracked:v1@"+newDigest, dbSet.Servers[0].Image)
+	assert.Len(t, dbSet.Servers[0].Snapshot.Server.Tools, 2)
+	assert.Equal(t, string(UpdatePolicyTrackTag), dbSet.Servers[0].UpdatePolicy)
+	assert.Equal(t, "https://example.com/v0/servers/server1/versions/0.2.0", dbSet.Servers[1].Source)
+}
+
+func TestUpdateDryRun(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	createTrackedWorkingSet(t, dao, UpdatePolicyTrackTag)
+
+	output := captureStdout(func() {
+		err := Update(ctx, dao, getMockUpdatedRegistryClient(), getMockUpdatedOciService(), "test-set", true)
+		require.NoError(t, err)
+	})
+	assert.Contains(t, output, "2 update(s) available for profile test-set")
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Equal(t, "tracked:v1@"+oldDigest, dbSet.Servers[0].Image)
+	assert.Equal(t, "https://example.com/v0/servers/server1/versions/0.1.0", dbSet.Servers[1].Source)
+}
+
+func TestUpdatePinnedServer(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	createTrackedWorkingSet(t, dao, UpdatePolicyPinned)
+
+	captureStdout(func() {
+		err := Update(ctx, dao, getMockUpdatedRegistryClient(), getMockUpdatedOciService(), "test-set", false)
+		require.NoError(t, err)
+	})
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Equal(t, "tracked:v1@"+oldDigest, dbSet.Servers[0].Image)
+	assert.Equal(t, "https://example.com/v0/servers/server1/versions/0.2.0", dbSet.Servers[1].Source)
+}
+
+func TestUpdateUpToDate(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+
+	err := dao.CreateWorkingSet(ctx, db.WorkingSet{
+		ID:   "test-set",
+		Name: "Test Working Set",
+		Servers: db.ServerList{
+			{
+				Type:         "registry",
+				Source:       "https://example.com/v0/servers/server1/versions/0.2.0",
+				UpdatePolicy: string(UpdatePolicyTrackLatest),
+			},
+		},
+		Secrets: db.SecretMap{},
+	})
+	require.NoError(t, err)
+
+	output := captureStdout(func() {
+		err := Update(ctx, dao, getMockUpdatedRegistryClient(), getMockUpdatedOciService(), "test-set", false)
+		require.NoError(t, err)
+	})
+	assert.Contains(t, output, "All servers in profile test-set are up to date")
+}
+
+func TestUpdateProfileNotFound(t *testing.T) {
+	dao := setupTestDB(t)
+
+	err := Update(t.Context(), dao, getMockUpdatedRegistryClient(), getMockUpdatedOciService(), "missing", false)
+	require.ErrorContains(t, err, "profile missing not found")
+}
+
+func TestSetUpdatePolicy(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	createTrackedWorkingSet(t, dao, "")
+
+	captureStdout(func() {
+		err := SetUpdatePolicy(ctx, dao, "test-set", []string{"tracked"}, UpdatePolicyTrackTag)
+		require.NoError(t, err)
+	})
+
+	dbSet, err := dao.GetWorkingSet(ctx, "test-set")
+	require.NoError(t, err)
+	assert.Equal(t, string(UpdatePolicyTrackTag), dbSet.Servers[0].UpdatePolicy)
+}
+
+func TestSetUpdatePolicyErrors(t *testing.T) {
+	dao := setupTestDB(t)
+	ctx := t.Context()
+	createTrackedWorkingSet(t, dao, "")
+
+	err := SetUpdatePolicy(ctx, dao, "test-set", []string{"tracked"}, "weekly")
+	require.ErrorContains(t, err, `invalid update policy "weekly"`)
+
+	err = SetUpdatePolicy(ctx, dao, "test-set", []string{"tracked"}, UpdatePolicyTrackLatest)
+	require.ErrorContains(t, err, "only supported by registry servers")
+
+	err = SetUpdatePolicy(ctx, dao, "test-set", []string{"unknown"}, UpdatePolicyPinned)
+	require.ErrorContains(t, err, "server unknown not found in profile")
+
+	err = SetUpdatePolicy(ctx, dao, "test-set", nil, UpdatePolicyPinned)
+	require.ErrorContains(t, err, oneServerError)
+}
+
+func TestDiffTools(t *testing.T) {
+	before := &ServerSnapshot{Server: catalog.Server{Tools: []catalog.Tool{
+		{Name: "a", Description: "A"},
+		{Name: "b", Description: "B"},
+	}}}
+	after := &ServerSnapshot{Server: catalog.Server{Tools: []catalog.Tool{
+		{Name: "b", Description: "B2"},
+		{Name: "c", Description: "C"},
+	}}}
+
+	added, removed, changed := diffTools(before, after)
+	assert.Equal(t, []string{"c"}, added)
+	assert.Equal(t, []string{"a"}, removed)
+	assert.Equal(t, []string{"b"}, changed)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/docker/mcp-gateway/pkg/log"
 	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/registryapi"
+	"github.com/docker/mcp-gateway/pkg/secretprovider"
 	"github.com/docker/mcp-gateway/pkg/sliceutil"
 	"github.com/docker/mcp-gateway/pkg/validate"
 )
@@ -32,6 +33,8 @@ type WorkingSet struct {
 	Name    string            `yaml:"name" json:"name" validate:"required,min=1"`
 	Servers []Server          `yaml:"servers" json:"servers" validate:"dive"`
 	Secrets map[string]Secret `yaml:"secrets,omitempty" json:"secrets,omitempty" validate:"dive"`
+	// DockerContext binds the profile to a Docker context. The gateway refuses to run the profile in another context.
+	DockerContext string `yaml:"docker_context,omitempty" json:"docker_context,omitempty"`
 }
 
 type ServerType string
@@ -40,11 +43,13 @@ const (
 	ServerTypeRegistry ServerType = "registry"
 	ServerTypeImage    ServerType = "image"
 	ServerTypeRemote   ServerType = "remote"
+	// ServerTypeGateway is another MCP gateway, whose tools are aggregated under a prefix
+	ServerTypeGateway ServerType = "gateway"
 )
 
 // Server represents a server configuration in a working set
 type Server struct {
-	Type    ServerType     `yaml:"type" json:"type" validate:"required,oneof=registry image remote"`
+	Type    ServerType     `yaml:"type" json:"type" validate:"required,oneof=registry image remote gateway"`
 	Config  map[string]any `yaml:"config,omitempty" json:"config,omitempty"`
 	Secrets string         `yaml:"secrets,omitempty" json:"secrets,omitempty"`
 	Tools   []string       `yaml:"tools" json:"tools"`
@@ -55,8 +60,23 @@ type Server struct {
 	// ServerTypeImage only
 	Image string `yaml:"image,omitempty" json:"image,omitempty" validate:"required_if=Type image"`
 
-	// ServerTypeRemote only
-	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required_if=Type remote"`
+	// ServerTypeRemote and ServerTypeGateway only
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty" validate:"required_if=Type remote,required_if=Type gateway"`
+	// OAuthScopes overrides the OAuth scopes declared by the catalog
+	OAuthScopes []string `yaml:"oauth_scopes,omitempty" json:"oauth_scopes,omitempty"`
+	// ToolTransforms overrides the transforms applied to tool results by the catalog, by tool name
+	ToolTransforms map[string]string `yaml:"tool_transforms,omitempty" json:"tool_transforms,omitempty"`
+
+	// UpdatePolicy tells `profile update` how to update the server. Defaults to pinned.
+	UpdatePolicy UpdatePolicy `yaml:"update_policy,omitempty" json:"update_policy,omitempty" validate:"omitempty,oneof=pinned track-tag track-latest"`
+
+	// Enabled tells whether the gateway starts the server. Defaults to true. A disabled server
+	// stays configured and can still be added to a session with mcp-add.
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+
+	// Dev overrides the container of the server only when the gateway runs with --dev,
+	// keeping the development setup out of the other runs of the profile.
+	Dev *ServerDev `yaml:"dev,omitempty" json:"dev,omitempty"`
 
 	// Optional snapshot of the server schema
 	Snapshot *ServerSnapshot `yaml:"snapshot,omitempty" json:"snapshot,omitempty"`
@@ -65,12 +85,23 @@ type Server struct {
 type SecretProvider string
 
 const (
-	SecretProviderDockerDesktop SecretProvider = "docker-desktop-store"
+	SecretProviderDockerDesktop        SecretProvider = "docker-desktop-store"
+	SecretProviderAWSSecretsManager    SecretProvider = "aws-secrets-manager"
+	SecretProviderAWSSSMParameterStore SecretProvider = "aws-ssm-parameter-store"
+	SecretProviderOnePassword          SecretProvider = "1password"
 )
 
 // Secret represents a secret configuration in a working set
 type Secret struct {
-	Provider SecretProvider `yaml:"provider" json:"provider" validate:"required,oneof=docker-desktop-store"`
+	Provider SecretProvider `yaml:"provider" json:"provider" validate:"required,oneof=docker-desktop-store aws-secrets-manager aws-ssm-parameter-store 1password"`
+	// Region of the secret store, for the AWS providers. Defaults to the region from the AWS default chain.
+	Region string `yaml:"region,omitempty" json:"region,omitempty"`
+	// Prefix is prepended to secret names when looking them up, for the AWS providers.
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	// Vault used to look up secrets that have no explicit reference, for the 1Password provider.
+	Vault string `yaml:"vault,omitempty" json:"vault,omitempty"`
+	// References maps secret names to 1Password secret references, eg. github.token: op://dev/GitHub/credential
+	References map[string]string `yaml:"references,omitempty" json:"references,omitempty"`
 }
 
 type ServerSnapshot struct {
@@ -81,10 +112,20 @@ func NewFromDb(dbSet *db.WorkingSet) WorkingSet {
 	servers := make([]Server, len(dbSet.Servers))
 	for i, server := range dbSet.Servers {
 		servers[i] = Server{
-			Type:    ServerType(server.Type),
-			Config:  server.Config,
-			Secrets: server.Secrets,
-			Tools:   server.Tools,
+			Type:           ServerType(server.Type),
+			Config:         server.Config,
+			Secrets:        server.Secrets,
+			Tools:          server.Tools,
+			OAuthScopes:    server.OAuthScopes,
+			ToolTransforms: server.ToolTransforms,
+			UpdatePolicy:   UpdatePolicy(server.UpdatePolicy),
+			Enabled:        server.Enabled,
+		}
+		if server.Dev != nil {
+			servers[i].Dev = &ServerDev{
+				Volumes: server.Dev.Volumes,
+				Env:     server.Dev.Env,
+			}
 		}
 		if server.Type == "registry" {
 			servers[i].Source = server.Source
@@ -92,7 +133,7 @@ func NewFromDb(dbSet *db.WorkingSet) WorkingSet {
 		if server.Type == "image" {
 			servers[i].Image = server.Image
 		}
-		if server.Type == "remote" {
+		if server.Type == "remote" || server.Type == "gateway" {
 			servers[i].Endpoint = server.Endpoint
 		}
 
@@ -106,7 +147,11 @@ func NewFromDb(dbSet *db.WorkingSet) WorkingSet {
 	secrets := make(map[string]Secret)
 	for name, secret := range dbSet.Secrets {
 		secrets[name] = Secret{
-			Provider: SecretProvider(secret.Provider),
+			Provider:   SecretProvider(secret.Provider),
+			Region:     secret.Region,
+			Prefix:     secret.Prefix,
+			Vault:      secret.Vault,
+			References: secret.References,
 		}
 	}
 
@@ -116,6 +161,8 @@ func NewFromDb(dbSet *db.WorkingSet) WorkingSet {
 		Name:    dbSet.Name,
 		Servers: servers,
 		Secrets: secrets,
+
+		DockerContext: dbSet.DockerContext,
 	}
 
 	return workingSet
@@ -125,10 +172,20 @@ func (workingSet WorkingSet) ToDb() db.WorkingSet {
 	dbServers := make(db.ServerList, len(workingSet.Servers))
 	for i, server := range workingSet.Servers {
 		dbServers[i] = db.Server{
-			Type:    string(server.Type),
-			Config:  server.Config,
-			Secrets: server.Secrets,
-			Tools:   server.Tools,
+			Type:           string(server.Type),
+			Config:         server.Config,
+			Secrets:        server.Secrets,
+			Tools:          server.Tools,
+			OAuthScopes:    server.OAuthScopes,
+			ToolTransforms: server.ToolTransforms,
+			UpdatePolicy:   string(server.UpdatePolicy),
+			Enabled:        server.Enabled,
+		}
+		if server.Dev != nil {
+			dbServers[i].Dev = &db.ServerDev{
+				Volumes: server.Dev.Volumes,
+				Env:     server.Dev.Env,
+			}
 		}
 		if server.Type == ServerTypeRegistry {
 			dbServers[i].Source = server.Source
@@ -136,7 +193,7 @@ func (workingSet WorkingSet) ToDb() db.WorkingSet {
 		if server.Type == ServerTypeImage {
 			dbServers[i].Image = server.Image
 		}
-		if server.Type == ServerTypeRemote {
+		if server.Type == ServerTypeRemote || server.Type == ServerTypeGateway {
 			dbServers[i].Endpoint = server.Endpoint
 		}
 		if server.Snapshot != nil {
@@ -149,7 +206,11 @@ func (workingSet WorkingSet) ToDb() db.WorkingSet {
 	dbSecrets := make(db.SecretMap, len(workingSet.Secrets))
 	for name, secret := range workingSet.Secrets {
 		dbSecrets[name] = db.Secret{
-			Provider: string(secret.Provider),
+			Provider:   string(secret.Provider),
+			Region:     secret.Region,
+			Prefix:     secret.Prefix,
+			Vault:      secret.Vault,
+			References: secret.References,
 		}
 	}
 
@@ -158,6 +219,8 @@ func (workingSet WorkingSet) ToDb() db.WorkingSet {
 		Name:    workingSet.Name,
 		Servers: dbServers,
 		Secrets: dbSecrets,
+
+		DockerContext: workingSet.DockerContext,
 	}
 
 	return dbSet
@@ -168,9 +231,32 @@ func (workingSet *WorkingSet) Validate() error {
 	if err != nil {
 		return err
 	}
+	if err := workingSet.validateSecretReferences(); err != nil {
+		return err
+	}
+	if err := workingSet.validateUpdatePolicies(); err != nil {
+		return err
+	}
+	if err := workingSet.validateDevOverrides(); err != nil {
+		return err
+	}
 	return workingSet.validateUniqueServerNames()
 }
 
+func (workingSet *WorkingSet) validateSecretReferences() error {
+	for name, secret := range workingSet.Secrets {
+		if secret.Provider != SecretProviderOnePassword {
+			continue
+		}
+		for secretName, reference := range secret.References {
+			if !secretprovider.IsOnePasswordReference(reference) {
+				return fmt.Errorf("invalid 1Password reference for secret %s in %s: %q, expected op://<vault>/<item>/<field>", secretName, name, reference)
+			}
+		}
+	}
+	return nil
+}
+
 func (workingSet *WorkingSet) validateUniqueServerNames() error {
 	seen := make(map[string]bool)
 	for _, server := range workingSet.Servers {
@@ -220,12 +306,19 @@ func (workingSet *WorkingSet) EnsureSnapshotsResolved(ctx context.Context, ociSe
 	return nil
 }
 
+// IsEnabled tells whether the gateway starts the server by default.
+func (s *Server) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
 func (s *Server) BasicName() string {
 	switch s.Type {
 	case ServerTypeImage:
 		return s.Image
 	case ServerTypeRegistry:
 		return s.Source
+	case ServerTypeRemote, ServerTypeGateway:
+		return s.Endpoint
 	}
 	return "unknown"
 }
@@ -285,11 +378,15 @@ func resolveServersFromString(ctx context.Context, registryClient registryapi.Cl
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve registry: %w", err)
 		}
+		serverSnapshot, err := ResolveRegistrySnapshot(ctx, registryClient, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve registry snapshot: %w", err)
+		}
 		return []Server{{
-			Type:    ServerTypeRegistry,
-			Source:  url,
-			Secrets: "default",
-			// TODO(cody): add snapshot
+			Type:     ServerTypeRegistry,
+			Source:   url,
+			Secrets:  "default",
+			Snapshot: serverSnapshot,
 		}}, nil
 	}
 	return nil, fmt.Errorf("invalid server value: %s", value)
@@ -401,16 +498,16 @@ func ResolveRegistry(ctx context.Context, registryClient registryapi.Client, val
 		return "", fmt.Errorf("server version not found")
 	}
 
-	// check oci package exists
-	foundOCIPackage := false
+	// check a package that the gateway can run exists: an image, or a package run in a sandbox image
+	foundPackage := false
 	for _, pkg := range server.Server.Packages {
-		if pkg.RegistryType == "oci" {
-			foundOCIPackage = true
+		if pkg.RegistryType == "oci" || pkg.RegistryType == catalog.PackageTypeNPM || pkg.RegistryType == catalog.PackageTypePyPI {
+			foundPackage = true
 			break
 		}
 	}
-	if !foundOCIPackage {
-		return "", fmt.Errorf("oci package not found for server %s", url.String())
+	if !foundPackage {
+		return "", fmt.Errorf("no oci, npm or pypi package found for server %s", url.String())
 	}
 
 	return url.String(), nil
@@ -426,10 +523,35 @@ func ResolveSnapshot(ctx context.Context, ociService oci.Service, server Server)
 	case ServerTypeRemote:
 		// TODO(bobby): add snapshot when you can add remotes directly from URL
 		return nil, nil //nolint:nilnil
+	case ServerTypeGateway:
+		// The snapshot holds the name of the server, it can't be resolved from the endpoint
+		return nil, fmt.Errorf("gateway server %s has no snapshot, add it to the profile again", server.Endpoint)
 	}
 	return nil, fmt.Errorf("unsupported server type: %s", server.Type)
 }
 
+// ResolveRegistrySnapshot converts the definition of a server published in the MCP Registry to a snapshot.
+func ResolveRegistrySnapshot(ctx context.Context, registryClient registryapi.Client, source string) (*ServerSnapshot, error) {
+	url, err := registryapi.ParseServerURL(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server URL %s: %w", source, err)
+	}
+
+	response, err := registryClient.GetServer(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server from URL %s: %w", url.String(), err)
+	}
+
+	if response.Server.Name == "" {
+		return nil, fmt.Errorf("server not found at URL %s", url.String())
+	}
+
+	detail := oci.ServerDetailFromRegistry(response.Server)
+	return &ServerSnapshot{
+		Server: detail.ToCatalogServer(),
+	}, nil
+}
+
 func ResolveImageSnapshot(ctx context.Context, ociService oci.Service, image string) (*ServerSnapshot, error) {
 	ref, err := name.ParseReference(image)
 	if err != nil {
@@ -442,6 +442,48 @@ func TestWorkingSetValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "valid 1password secrets",
+			ws: WorkingSet{
+				Version: CurrentWorkingSetVersion,
+				ID:      "test-id",
+				Name:    "Test",
+				Secrets: map[string]Secret{
+					"default": {
+						Provider:   SecretProviderOnePassword,
+						References: map[string]string{"github.token": "op://dev/GitHub/credential"},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid 1password reference",
+			ws: WorkingSet{
+				Version: CurrentWorkingSetVersion,
+				ID:      "test-id",
+				Name:    "Test",
+				Secrets: map[string]Secret{
+					"default": {
+						Provider:   SecretProviderOnePassword,
+						References: map[string]string{"github.token": "dev/GitHub"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown secret provider",
+			ws: WorkingSet{
+				Version: CurrentWorkingSetVersion,
+				ID:      "test-id",
+				Name:    "Test",
+				Secrets: map[string]Secret{
+					"default": {Provider: "vault"},
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -565,6 +607,9 @@ func TestResolveServerFromString(t *testing.T) {
 				Type:    ServerTypeRegistry,
 				Source:  "http://example.com/v0/servers/my-server/versions/latest",
 				Secrets: "default",
+				Snapshot: &ServerSnapshot{
+					Server: catalog.Server{Name: "my-server", Image: "example/my-server"},
+				},
 			}},
 			expectedVersion: "latest",
 		},
@@ -575,6 +620,9 @@ func TestResolveServerFromString(t *testing.T) {
 				Type:    ServerTypeRegistry,
 				Source:  "https://example.com/v0/servers/my-server/versions/latest",
 				Secrets: "default",
+				Snapshot: &ServerSnapshot{
+					Server: catalog.Server{Name: "my-server", Image: "example/my-server"},
+				},
 			}},
 			expectedVersion: "latest",
 		},
@@ -585,6 +633,9 @@ func TestResolveServerFromString(t *testing.T) {
 				Type:    ServerTypeRegistry,
 				Source:  "https://example.com/v0/servers/my-server/versions/0.1.0",
 				Secrets: "default",
+				Snapshot: &ServerSnapshot{
+					Server: catalog.Server{Name: "my-server", Image: "example/my-server"},
+				},
 			}},
 			expectedVersion: "0.1.0",
 		},
@@ -601,10 +652,12 @@ func TestResolveServerFromString(t *testing.T) {
 
 			serverResponse := v0.ServerResponse{
 				Server: v0.ServerJSON{
+					Name:    "my-server",
 					Version: tt.expectedVersion,
 					Packages: []model.Package{
 						{
 							RegistryType: "oci",
+							Identifier:   "example/my-server",
 						},
 					},
 				},
@@ -622,7 +675,8 @@ func TestResolveServerFromString(t *testing.T) {
 					Servers: []v0.ServerResponse{serverResponse},
 				},
 			}), mocks.WithServerResponses(map[string]v0.ServerResponse{
-				"http://example.com/v0/servers/my-server/versions/" + tt.expectedVersion: serverResponse,
+				"http://example.com/v0/servers/my-server/versions/" + tt.expectedVersion:  serverResponse,
+				"https://example.com/v0/servers/my-server/versions/" + tt.expectedVersion: serverResponse,
 			}))
 
 			ociService := mocks.NewMockOCIService(
@@ -661,6 +715,7 @@ func TestResolveServerFromStringResolvesLatestVersion(t *testing.T) {
 
 	serverResponse := v0.ServerResponse{
 		Server: v0.ServerJSON{
+			Name:    "my-server",
 			Version: "0.2.0",
 			Packages: []model.Package{
 				{
@@ -676,6 +731,7 @@ func TestResolveServerFromStringResolvesLatestVersion(t *testing.T) {
 	}
 	oldServerResponse := v0.ServerResponse{
 		Server: v0.ServerJSON{
+			Name:    "my-server",
 			Version: "0.1.0",
 			Packages: []model.Package{
 				{